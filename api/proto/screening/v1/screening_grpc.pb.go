@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: screening/v1/screening.proto
+
+package screeningv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ScreeningService_Screen_FullMethodName             = "/aml.screening.v1.ScreeningService/Screen"
+	ScreeningService_GetScreeningResult_FullMethodName = "/aml.screening.v1.ScreeningService/GetScreeningResult"
+)
+
+// ScreeningServiceClient is the client API for ScreeningService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ScreeningService screens transactions synchronously for internal callers
+// on the payment path. Calls need a bearer token in the "authorization"
+// metadata. The screening budget is the service's latency budget or the
+// call's deadline, whichever is shorter.
+type ScreeningServiceClient interface {
+	// Screen screens a transaction and returns the decision
+	Screen(ctx context.Context, in *ScreenRequest, opts ...grpc.CallOption) (*ScreenResponse, error)
+	// GetScreeningResult returns a stored screening result by ID
+	GetScreeningResult(ctx context.Context, in *GetScreeningResultRequest, opts ...grpc.CallOption) (*ScreeningResult, error)
+}
+
+type screeningServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScreeningServiceClient(cc grpc.ClientConnInterface) ScreeningServiceClient {
+	return &screeningServiceClient{cc}
+}
+
+func (c *screeningServiceClient) Screen(ctx context.Context, in *ScreenRequest, opts ...grpc.CallOption) (*ScreenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScreenResponse)
+	err := c.cc.Invoke(ctx, ScreeningService_Screen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *screeningServiceClient) GetScreeningResult(ctx context.Context, in *GetScreeningResultRequest, opts ...grpc.CallOption) (*ScreeningResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScreeningResult)
+	err := c.cc.Invoke(ctx, ScreeningService_GetScreeningResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScreeningServiceServer is the server API for ScreeningService service.
+// All implementations must embed UnimplementedScreeningServiceServer
+// for forward compatibility.
+//
+// ScreeningService screens transactions synchronously for internal callers
+// on the payment path. Calls need a bearer token in the "authorization"
+// metadata. The screening budget is the service's latency budget or the
+// call's deadline, whichever is shorter.
+type ScreeningServiceServer interface {
+	// Screen screens a transaction and returns the decision
+	Screen(context.Context, *ScreenRequest) (*ScreenResponse, error)
+	// GetScreeningResult returns a stored screening result by ID
+	GetScreeningResult(context.Context, *GetScreeningResultRequest) (*ScreeningResult, error)
+	mustEmbedUnimplementedScreeningServiceServer()
+}
+
+// UnimplementedScreeningServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScreeningServiceServer struct{}
+
+func (UnimplementedScreeningServiceServer) Screen(context.Context, *ScreenRequest) (*ScreenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Screen not implemented")
+}
+func (UnimplementedScreeningServiceServer) GetScreeningResult(context.Context, *GetScreeningResultRequest) (*ScreeningResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScreeningResult not implemented")
+}
+func (UnimplementedScreeningServiceServer) mustEmbedUnimplementedScreeningServiceServer() {}
+func (UnimplementedScreeningServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeScreeningServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScreeningServiceServer will
+// result in compilation errors.
+type UnsafeScreeningServiceServer interface {
+	mustEmbedUnimplementedScreeningServiceServer()
+}
+
+func RegisterScreeningServiceServer(s grpc.ServiceRegistrar, srv ScreeningServiceServer) {
+	// If the following call pancis, it indicates UnimplementedScreeningServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ScreeningService_ServiceDesc, srv)
+}
+
+func _ScreeningService_Screen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScreenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScreeningServiceServer).Screen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScreeningService_Screen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScreeningServiceServer).Screen(ctx, req.(*ScreenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ScreeningService_GetScreeningResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScreeningResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScreeningServiceServer).GetScreeningResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScreeningService_GetScreeningResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScreeningServiceServer).GetScreeningResult(ctx, req.(*GetScreeningResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ScreeningService_ServiceDesc is the grpc.ServiceDesc for ScreeningService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScreeningService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aml.screening.v1.ScreeningService",
+	HandlerType: (*ScreeningServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Screen",
+			Handler:    _ScreeningService_Screen_Handler,
+		},
+		{
+			MethodName: "GetScreeningResult",
+			Handler:    _ScreeningService_GetScreeningResult_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "screening/v1/screening.proto",
+}
@@ -0,0 +1,385 @@
+// Command aml is the operator command line for the AML service.
+//
+// Screen one transaction against the live lists, country risk table,
+// velocity counters and history in Postgres and Redis, without persisting,
+// caching, alerting on or notifying about the result:
+//
+//	aml screen -file tx.json
+//
+// Screen it against pinned OFAC and PEP list snapshots with no other
+// dependencies, as replay does:
+//
+//	aml screen -file tx.json -ofac ofac.json -pep pep.json
+//
+// The screening result, including its risk factor breakdown, is printed to
+// stdout as indented JSON.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/patterns"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/currency"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/money"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/repository/redis"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+const usage = `Usage: aml <command> [flags]
+
+Commands:
+  screen    screen a transaction from a JSON file and print the result
+
+Run "aml <command> -h" for a command's flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "screen":
+		screenCommand(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "aml: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+func screenCommand(args []string) {
+	fs := flag.NewFlagSet("screen", flag.ExitOnError)
+	fileFlag := fs.String("file", "", "JSON file holding the transaction to screen")
+	ofacFlag := fs.String("ofac", "", "OFAC list snapshot file; with -pep, screen without Postgres or Redis")
+	pepFlag := fs.String("pep", "", "PEP list snapshot file")
+	clockFlag := fs.String("clock", "", "frozen screening time (RFC3339) for snapshot screening, defaults to the later snapshot updated_at")
+	fs.Parse(args)
+
+	zapLogger, _ := zap.NewProduction()
+	defer zapLogger.Sync()
+	sugar := zapLogger.Sugar()
+
+	if *fileFlag == "" {
+		sugar.Fatalf("-file is required")
+	}
+	if (*ofacFlag == "") != (*pepFlag == "") {
+		sugar.Fatalf("-ofac and -pep must be given together")
+	}
+	if *clockFlag != "" && *ofacFlag == "" {
+		sugar.Fatalf("-clock applies only to snapshot screening")
+	}
+
+	tx, err := readTransaction(*fileFlag)
+	if err != nil {
+		sugar.Fatalf("Failed to read -file: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		sugar.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	appLog, err := logger.New(cfg.Telemetry.ServiceName, cfg.Telemetry.Environment, false)
+	if err != nil {
+		sugar.Fatalf("Failed to create logger: %v", err)
+	}
+	defer appLog.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	reputationProvider, err := screening.NewDenylistProvider(&cfg.Screening.Reputation)
+	if err != nil {
+		sugar.Fatalf("Failed to load reputation denylists: %v", err)
+	}
+	geoIPProvider := screening.OpenMaxMindProvider(cfg.Screening.GeoIP.DatabasePath, appLog)
+	defer geoIPProvider.Close()
+
+	var engine *screening.Engine
+	if *ofacFlag != "" {
+		engine, err = snapshotEngine(cfg, *ofacFlag, *pepFlag, *clockFlag, reputationProvider, geoIPProvider, appLog)
+		if err != nil {
+			sugar.Fatalf("Failed to create snapshot engine: %v", err)
+		}
+	} else {
+		db, err := postgres.NewDB(ctx, &cfg.Database)
+		if err != nil {
+			sugar.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+
+		redisClient, err := redis.NewClient(ctx, &cfg.Redis)
+		if err != nil {
+			sugar.Fatalf("Failed to connect to redis: %v", err)
+		}
+		defer redisClient.Close()
+
+		engine, err = liveEngine(ctx, cfg, db, redisClient, reputationProvider, geoIPProvider, appLog)
+		if err != nil {
+			sugar.Fatalf("Failed to create screening engine: %v", err)
+		}
+	}
+
+	result, err := engine.Screen(ctx, tx)
+	if err != nil {
+		sugar.Fatalf("Screening failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		sugar.Fatalf("Failed to write result: %v", err)
+	}
+}
+
+// readTransaction reads a transaction from a JSON file. A transaction
+// without an ID gets a random one.
+func readTransaction(path string) (*domain.Transaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tx domain.Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	if tx.ID == uuid.Nil {
+		tx.ID = uuid.New()
+	}
+	if tx.UserID == uuid.Nil {
+		return nil, fmt.Errorf("%s: user_id is required", path)
+	}
+	return &tx, nil
+}
+
+// snapshotEngine screens against list snapshots with a frozen clock and no
+// history, the same engine replay uses
+func snapshotEngine(
+	cfg *config.Config,
+	ofacPath, pepPath, clockValue string,
+	reputationProvider screening.ReputationProvider,
+	geoIPProvider screening.GeoIPProvider,
+	log *logger.Logger,
+) (*screening.Engine, error) {
+	snapshot, err := screening.LoadListSnapshot(ofacPath, pepPath)
+	if err != nil {
+		return nil, fmt.Errorf("load list snapshots: %w", err)
+	}
+
+	clock := snapshot.OFACUpdatedAt
+	if snapshot.PEPUpdatedAt.After(clock) {
+		clock = snapshot.PEPUpdatedAt
+	}
+	if clockValue != "" {
+		if clock, err = time.Parse(time.RFC3339, clockValue); err != nil {
+			return nil, fmt.Errorf("invalid -clock: %w", err)
+		}
+	}
+	if clock.IsZero() {
+		return nil, fmt.Errorf("the snapshots have no updated_at; set -clock")
+	}
+
+	// Static rates only, so the result does not depend on when it runs
+	converter := currency.NewConverter(&cfg.Currency, nil, log)
+	return screening.NewSnapshotEngine(snapshot, reputationProvider, geoIPProvider, converter,
+		&cfg.Screening, &cfg.Patterns, &cfg.Compliance, clock, log)
+}
+
+// liveEngine screens against the lists, velocity counters, risk profiles
+// and screening history the service uses. It only reads: results are not
+// persisted, cached, alerted on, audited or notified, and the user's
+// velocity counters, last location and device users are left untouched.
+func liveEngine(
+	ctx context.Context,
+	cfg *config.Config,
+	db *sql.DB,
+	redisClient *goredis.Client,
+	reputationProvider screening.ReputationProvider,
+	geoIPProvider screening.GeoIPProvider,
+	log *logger.Logger,
+) (*screening.Engine, error) {
+	ofacMatcher, err := screening.NewNameMatcher(cfg.Screening.NameMatchers["ofac"])
+	if err != nil {
+		return nil, fmt.Errorf("create ofac name matcher: %w", err)
+	}
+	pepMatcher, err := screening.NewNameMatcher(cfg.Screening.NameMatchers["pep"])
+	if err != nil {
+		return nil, fmt.Errorf("create pep name matcher: %w", err)
+	}
+	normalizer, err := screening.NewNameNormalizer(cfg.Screening.NameFolding, cfg.Screening.EntityStopwords)
+	if err != nil {
+		return nil, fmt.Errorf("create name normalizer: %w", err)
+	}
+
+	ofacCache := screening.WithOFACRetry(readOnlyOFACList{redis.NewOFACCache(redisClient)}, cfg.Screening.OFACCacheRetry)
+	ofacChecker := screening.NewOFACChecker(ofacCache, nil, ofacMatcher, normalizer, log,
+		cfg.Screening.FuzzyMatchThreshold, cfg.Screening.BatchConcurrency, 0)
+	pepChecker := screening.NewPEPChecker(readOnlyPEPList{redis.NewPEPCache(redisClient)}, nil, pepMatcher, normalizer, log,
+		cfg.Screening.FuzzyMatchThreshold, cfg.Screening.PEPPartialMatchThreshold)
+	if err := ofacChecker.LoadIndex(ctx); err != nil {
+		return nil, fmt.Errorf("load ofac index: %w", err)
+	}
+	if err := pepChecker.LoadIndex(ctx); err != nil {
+		return nil, fmt.Errorf("load pep index: %w", err)
+	}
+
+	var ratesSource currency.RatesSource
+	if cfg.Currency.RatesURL != "" {
+		ratesSource = currency.NewHTTPSource(cfg.Currency.RatesURL, cfg.Currency.BaseCurrency)
+	}
+	converter := currency.NewConverter(&cfg.Currency, ratesSource, log)
+
+	screeningResultRepo := postgres.NewScreeningResultRepository(db)
+	transactionRepo := postgres.NewTransactionRepository(db)
+
+	countryRiskTable, err := postgres.NewCountryRiskRepository(db).Latest(ctx)
+	if errors.Is(err, domain.ErrNotFound) {
+		countryRiskTable = screening.ConfiguredCountryRiskTable(&cfg.Patterns)
+	} else if err != nil {
+		return nil, fmt.Errorf("load country risk table: %w", err)
+	}
+
+	countryRisk := screening.NewCountryRisk(countryRiskTable)
+
+	allTenantSettings, err := postgres.NewTenantSettingsRepository(db).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load tenant settings: %w", err)
+	}
+	tenantSettings := screening.NewTenantSettings()
+	tenantSettings.Set(allTenantSettings)
+
+	velocityCache := redis.NewVelocityCache(redisClient)
+
+	return screening.NewEngine(
+		ofacChecker,
+		pepChecker,
+		screening.NewReputationChecker(reputationProvider, screeningResultRepo, &cfg.Screening.Reputation, log),
+		screening.NewGeoIPChecker(geoIPProvider, readOnlyLocations{velocityCache}, countryRisk, &cfg.Screening.GeoIP, log),
+		screening.NewSharedDeviceChecker(readOnlyDeviceUsers{redis.NewDeviceUsers(redisClient, &cfg.Screening.SharedDevice)},
+			&cfg.Screening.SharedDevice, log),
+		screening.NewCounterpartyChecker(readOnlyCounterparties{postgres.NewCounterpartyRepository(db)}, &cfg.Screening.Counterparty, log),
+		redis.NewAccountDenylist(redisClient),
+		screening.NewRiskCalculator(&cfg.Patterns, countryRisk, converter, cfg.Compliance.CTRThreshold, log),
+		tenantSettings,
+		nil, // no shadow scoring
+		converter,
+		patterns.NewEngine(log,
+			patterns.NewStructuringDetector(transactionRepo, converter, clock.Real{}, &cfg.Patterns),
+			patterns.NewSmurfingDetector(transactionRepo, converter, clock.Real{}, &cfg.Patterns),
+			patterns.NewLayeringDetector(transactionRepo, clock.Real{}, &cfg.Patterns),
+			patterns.NewUnusualTimeDetector(transactionRepo, clock.Real{}, &cfg.Patterns),
+			patterns.NewDormantReactivationDetector(transactionRepo, readOnlyVelocity{velocityCache}, converter, clock.Real{}, &cfg.Patterns),
+		),
+		transactionRepo,
+		readOnlyVelocity{velocityCache},
+		postgres.NewRiskProfileRepository(db),
+		nil, // results are not persisted
+		nil, // or cached
+		nil, // list checks always run
+		nil, // or alerted on
+		nil, // or audited
+		nil, // or notified
+		clock.Real{},
+		&cfg.Screening,
+		&cfg.Compliance,
+		log,
+	), nil
+}
+
+// readOnlyOFACList reads the cached OFAC list's version without recording
+// a new one; a list that changed since its last recorded version screens
+// with the version unknown
+type readOnlyOFACList struct {
+	screening.OFACCache
+}
+
+func (c readOnlyOFACList) RecordVersion(ctx context.Context, hash string, entries int, _ time.Time) (screening.ListVersion, bool, error) {
+	return currentListVersion(ctx, c.GetVersion, hash, entries)
+}
+
+// readOnlyPEPList reads the cached PEP list's version without recording a
+// new one, as readOnlyOFACList does
+type readOnlyPEPList struct {
+	screening.PEPCache
+}
+
+func (c readOnlyPEPList) RecordVersion(ctx context.Context, hash string, entries int, _ time.Time) (screening.ListVersion, bool, error) {
+	return currentListVersion(ctx, c.GetVersion, hash, entries)
+}
+
+// currentListVersion returns the recorded list version when its hash is
+// the given one, and otherwise the version unknown
+func currentListVersion(ctx context.Context, get func(context.Context) (screening.ListVersion, error), hash string, entries int) (screening.ListVersion, bool, error) {
+	current, err := get(ctx)
+	if err != nil {
+		return screening.ListVersion{}, false, err
+	}
+	if current.Hash != hash {
+		return screening.ListVersion{Hash: hash, Entries: entries}, false, nil
+	}
+	return current, false, nil
+}
+
+// readOnlyVelocity reads a user's velocity without counting the screened
+// transaction towards it
+type readOnlyVelocity struct {
+	screening.VelocityCache
+}
+
+func (readOnlyVelocity) IncrementVelocity(context.Context, uuid.UUID, money.Amount) error {
+	return nil
+}
+
+// readOnlyLocations reads a user's last location without replacing it
+// with the screened transaction's
+type readOnlyLocations struct {
+	cache *redis.VelocityCache
+}
+
+func (l readOnlyLocations) SwapLastLocation(ctx context.Context, userID uuid.UUID, _ *domain.TransactionLocation, _ time.Duration) (*domain.TransactionLocation, error) {
+	return l.cache.LastLocation(ctx, userID)
+}
+
+// readOnlyDeviceUsers reads the users of a device or IP address as they
+// stood before the screened transaction, without adding its user to them
+type readOnlyDeviceUsers struct {
+	index *redis.DeviceUsers
+}
+
+func (d readOnlyDeviceUsers) Record(ctx context.Context, key string, _ uuid.UUID, at time.Time, limit int) (int, []uuid.UUID, error) {
+	return d.index.Users(ctx, key, at, limit)
+}
+
+// readOnlyCounterparties reads counterparty reputations without counting
+// the screened transaction's decision towards them
+type readOnlyCounterparties struct {
+	screening.CounterpartyStore
+}
+
+func (readOnlyCounterparties) Record(context.Context, domain.CounterpartyEvent, time.Duration) error {
+	return nil
+}
@@ -0,0 +1,124 @@
+// Command archive-restore re-imports records archived by the retention job
+// for an examination request. The restored days are held in Postgres until
+// -hold-until, after which the retention job archives them again.
+//
+//	archive-restore -entity screening_results -from 2025-01-01 -to 2025-03-31 \
+//		-hold-until 2026-12-31 -actor <analyst id> -reason "OCC exam 2026-04"
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/repository/kafka"
+	"github.com/banking/aml-service/internal/repository/objectstore"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/service"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func main() {
+	entityFlag := flag.String("entity", domain.RetentionScreeningResults, "entity type to restore: screening_results or alerts")
+	fromFlag := flag.String("from", "", "first day to restore (YYYY-MM-DD)")
+	toFlag := flag.String("to", "", "last day to restore (YYYY-MM-DD), defaults to -from")
+	holdFlag := flag.String("hold-until", "", "keep the restored records in Postgres until this day (YYYY-MM-DD)")
+	actorFlag := flag.String("actor", "", "ID of the analyst requesting the restore")
+	reasonFlag := flag.String("reason", "", "examination or request the restore is for")
+	flag.Parse()
+
+	zapLogger, _ := zap.NewProduction()
+	defer zapLogger.Sync()
+	sugar := zapLogger.Sugar()
+
+	from, err := time.Parse(time.DateOnly, *fromFlag)
+	if err != nil {
+		sugar.Fatalf("Invalid -from: %v", err)
+	}
+	to := from
+	if *toFlag != "" {
+		if to, err = time.Parse(time.DateOnly, *toFlag); err != nil {
+			sugar.Fatalf("Invalid -to: %v", err)
+		}
+	}
+	holdUntil, err := time.Parse(time.DateOnly, *holdFlag)
+	if err != nil {
+		sugar.Fatalf("Invalid -hold-until: %v", err)
+	}
+	actorID, err := uuid.Parse(*actorFlag)
+	if err != nil {
+		sugar.Fatalf("Invalid -actor: %v", err)
+	}
+
+	req := &domain.RetentionRestoreRequest{
+		EntityType: *entityFlag,
+		From:       from,
+		To:         to.AddDate(0, 0, 1), // -to is inclusive
+		HoldUntil:  holdUntil,
+		Reason:     *reasonFlag,
+		ActorID:    actorID,
+	}
+	if err := req.Validate(time.Now()); err != nil {
+		sugar.Fatalf("Invalid restore: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		sugar.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	appLog, err := logger.New(cfg.Telemetry.ServiceName, cfg.Telemetry.Environment, false)
+	if err != nil {
+		sugar.Fatalf("Failed to create logger: %v", err)
+	}
+	defer appLog.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := postgres.NewDB(ctx, &cfg.Database)
+	if err != nil {
+		sugar.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	var archiveStore service.ObjectStore
+	switch cfg.Storage.Backend {
+	case "s3":
+		archiveStore, err = objectstore.NewS3Store(&cfg.Storage)
+	default:
+		archiveStore, err = objectstore.NewLocalStore(cfg.Storage.LocalDir)
+	}
+	if err != nil {
+		sugar.Fatalf("Failed to create archive store: %v", err)
+	}
+
+	auditProducer := kafka.NewProducer(cfg.Kafka.Brokers, cfg.Kafka.AuditTopic)
+	defer auditProducer.Close()
+	auditWriter, err := audit.NewWriter(postgres.NewAuditRepository(db), auditProducer, cfg.Security.AuditHMACSecret, appLog)
+	if err != nil {
+		sugar.Fatalf("Failed to create audit writer: %v", err)
+	}
+
+	retention := service.NewRetentionJob(postgres.NewRetentionRepository(db), archiveStore, auditWriter,
+		postgres.NewAdvisoryLocker(db), &cfg.Compliance.Retention, appLog)
+
+	n, err := retention.Restore(ctx, req)
+	if err != nil {
+		sugar.Errorf("Restore stopped after %d records: %v", n, err)
+		os.Exit(1)
+	}
+
+	sugar.Infof("Restored %d %s held until %s", n, req.EntityType, *holdFlag)
+}
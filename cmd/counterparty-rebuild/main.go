@@ -0,0 +1,77 @@
+// Command counterparty-rebuild recomputes every counterparty reputation from
+// the stored screening results and submitted SARs, for example after the
+// half-life has changed or a screening outage left the store behind.
+//
+//	counterparty-rebuild
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/crypto"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/screening"
+	"github.com/banking/aml-service/internal/service"
+	"go.uber.org/zap"
+)
+
+func main() {
+	flag.Parse()
+
+	zapLogger, _ := zap.NewProduction()
+	defer zapLogger.Sync()
+	sugar := zapLogger.Sugar()
+
+	cfg, err := config.Load()
+	if err != nil {
+		sugar.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	appLog, err := logger.New(cfg.Telemetry.ServiceName, cfg.Telemetry.Environment, false)
+	if err != nil {
+		sugar.Fatalf("Failed to create logger: %v", err)
+	}
+	defer appLog.Sync()
+
+	keyring, err := crypto.NewKeyring(cfg.Security.EncryptionKeys, cfg.Security.CurrentKeyVersion)
+	if err != nil {
+		sugar.Fatalf("Failed to load encryption keys: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := postgres.NewDB(ctx, &cfg.Database)
+	if err != nil {
+		sugar.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	counterpartyRepo := postgres.NewCounterpartyRepository(db)
+	counterparties := service.NewCounterpartyService(
+		counterpartyRepo,
+		postgres.NewScreeningResultRepository(db),
+		postgres.NewFilingRepository(db, keyring),
+		screening.NewCounterpartyChecker(counterpartyRepo, &cfg.Screening.Counterparty, appLog),
+		clock.Real{},
+		appLog,
+	)
+
+	n, err := counterparties.Rebuild(ctx)
+	if err != nil {
+		sugar.Errorf("Rebuild failed, the store is unchanged: %v", err)
+		os.Exit(1)
+	}
+
+	sugar.Infof("Rebuilt %d counterparty reputations", n)
+}
@@ -0,0 +1,87 @@
+// Command mi-backfill regenerates the stored MI report snapshots for a date
+// range, for example after late data has been loaded for closed periods.
+//
+//	mi-backfill -period daily -from 2026-01-01 -to 2026-01-31
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/service"
+	"go.uber.org/zap"
+)
+
+func main() {
+	periodFlag := flag.String("period", "daily", "report period to regenerate: daily or monthly")
+	fromFlag := flag.String("from", "", "first date to regenerate (YYYY-MM-DD)")
+	toFlag := flag.String("to", "", "last date to regenerate (YYYY-MM-DD), defaults to -from")
+	flag.Parse()
+
+	zapLogger, _ := zap.NewProduction()
+	defer zapLogger.Sync()
+	sugar := zapLogger.Sugar()
+
+	period, err := domain.ParseReportPeriod(*periodFlag)
+	if err != nil {
+		sugar.Fatalf("Invalid -period: %v", err)
+	}
+	from, err := time.Parse(time.DateOnly, *fromFlag)
+	if err != nil {
+		sugar.Fatalf("Invalid -from: %v", err)
+	}
+	to := from
+	if *toFlag != "" {
+		if to, err = time.Parse(time.DateOnly, *toFlag); err != nil {
+			sugar.Fatalf("Invalid -to: %v", err)
+		}
+	}
+	if to.Before(from) {
+		sugar.Fatalf("-to %s is before -from %s", *toFlag, *fromFlag)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		sugar.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	appLog, err := logger.New(cfg.Telemetry.ServiceName, cfg.Telemetry.Environment, false)
+	if err != nil {
+		sugar.Fatalf("Failed to create logger: %v", err)
+	}
+	defer appLog.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := postgres.NewDB(ctx, &cfg.Database)
+	if err != nil {
+		sugar.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	reportRepo := postgres.NewReportRepository(db)
+	reports, err := service.NewReportService(reportRepo, reportRepo, &cfg.Compliance, appLog)
+	if err != nil {
+		sugar.Fatalf("Failed to create report service: %v", err)
+	}
+
+	n, err := reports.Regenerate(ctx, period, from, to)
+	if err != nil {
+		sugar.Errorf("Backfill stopped after %d reports: %v", n, err)
+		os.Exit(1)
+	}
+
+	sugar.Infof("Regenerated %d %s reports", n, period)
+}
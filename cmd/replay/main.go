@@ -0,0 +1,311 @@
+// Command replay re-screens historical transactions against pinned OFAC and
+// PEP list snapshots with a frozen clock and reports how decisions, scores
+// and risk factors differ from the original results, so the effect of a
+// code or configuration change on past traffic can be reviewed before it
+// ships. It exits non-zero when the share of changed decisions exceeds
+// -max-changed-rate.
+//
+// Replay the results stored in Postgres for January:
+//
+//	replay -ofac ofac.json -pep pep.json -from 2026-01-01 -to 2026-01-31 -max-changed-rate 0.001
+//
+// Replay a transactions file, comparing with the output of an earlier run:
+//
+//	replay -ofac ofac.json -pep pep.json -transactions tx.jsonl -baseline before.jsonl -out after.jsonl
+//
+// Snapshot files hold {"updated_at": "...", "entries": [...]}. Instead of
+// files, -ofac-version and -pep-version replay against list versions
+// archived in the object store, as stamped on screening results; the
+// replay refuses to run if either version is not retained.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/currency"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/repository/objectstore"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/screening"
+	"github.com/banking/aml-service/internal/service"
+)
+
+// maxLineSize is the longest JSON line the input files may hold
+const maxLineSize = 4 << 20
+
+func main() {
+	ofacFlag := flag.String("ofac", "", "OFAC list snapshot file")
+	pepFlag := flag.String("pep", "", "PEP list snapshot file")
+	ofacVersionFlag := flag.Int64("ofac-version", 0, "archived OFAC list version to replay against, instead of -ofac")
+	pepVersionFlag := flag.Int64("pep-version", 0, "archived PEP list version to replay against, instead of -pep")
+	txFlag := flag.String("transactions", "", "JSON lines file of transactions to replay")
+	baselineFlag := flag.String("baseline", "", "JSON lines file of screening results to compare -transactions with, such as an earlier -out")
+	fromFlag := flag.String("from", "", "first day of stored screening results to replay (YYYY-MM-DD)")
+	toFlag := flag.String("to", "", "last day of stored screening results to replay (YYYY-MM-DD), defaults to -from")
+	outFlag := flag.String("out", "", "write the replayed screening results to this JSON lines file")
+	reportFlag := flag.String("report", "", "write the diff report to this file instead of stdout")
+	clockFlag := flag.String("clock", "", "frozen screening time (RFC3339), defaults to the later snapshot updated_at")
+	maxRateFlag := flag.Float64("max-changed-rate", 0, "largest share of compared decisions allowed to change")
+	flag.Parse()
+
+	zapLogger, _ := zap.NewProduction()
+	defer zapLogger.Sync()
+	sugar := zapLogger.Sugar()
+
+	pinned := *ofacVersionFlag != 0 || *pepVersionFlag != 0
+	switch {
+	case pinned && (*ofacFlag != "" || *pepFlag != ""):
+		sugar.Fatalf("-ofac-version and -pep-version replace -ofac and -pep")
+	case pinned && (*ofacVersionFlag <= 0 || *pepVersionFlag <= 0):
+		sugar.Fatalf("-ofac-version and -pep-version are required together")
+	case !pinned && (*ofacFlag == "" || *pepFlag == ""):
+		sugar.Fatalf("-ofac and -pep, or -ofac-version and -pep-version, are required")
+	}
+	if (*txFlag == "") == (*fromFlag == "") {
+		sugar.Fatalf("Exactly one of -transactions and -from is required")
+	}
+	if *baselineFlag != "" && *txFlag == "" {
+		sugar.Fatalf("-baseline applies only to -transactions")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		sugar.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	appLog, err := logger.New(cfg.Telemetry.ServiceName, cfg.Telemetry.Environment, false)
+	if err != nil {
+		sugar.Fatalf("Failed to create logger: %v", err)
+	}
+	defer appLog.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var snapshot *screening.ListSnapshot
+	if pinned {
+		var archiveStore screening.ObjectStore
+		switch cfg.Storage.Backend {
+		case "s3":
+			archiveStore, err = objectstore.NewS3Store(&cfg.Storage)
+		default:
+			archiveStore, err = objectstore.NewLocalStore(cfg.Storage.LocalDir)
+		}
+		if err != nil {
+			sugar.Fatalf("Failed to create archive store: %v", err)
+		}
+		snapshot, err = screening.NewListArchive(archiveStore).Snapshot(ctx, *ofacVersionFlag, *pepVersionFlag)
+		if errors.Is(err, screening.ErrListVersionNotRetained) {
+			sugar.Fatalf("Refusing to replay: %v", err)
+		}
+	} else {
+		snapshot, err = screening.LoadListSnapshot(*ofacFlag, *pepFlag)
+	}
+	if err != nil {
+		sugar.Fatalf("Failed to load list snapshots: %v", err)
+	}
+
+	clock := snapshot.OFACUpdatedAt
+	if snapshot.PEPUpdatedAt.After(clock) {
+		clock = snapshot.PEPUpdatedAt
+	}
+	if *clockFlag != "" {
+		if clock, err = time.Parse(time.RFC3339, *clockFlag); err != nil {
+			sugar.Fatalf("Invalid -clock: %v", err)
+		}
+	}
+	if clock.IsZero() {
+		sugar.Fatalf("The snapshots have no updated_at; set -clock")
+	}
+
+	reputationProvider, err := screening.NewDenylistProvider(&cfg.Screening.Reputation)
+	if err != nil {
+		sugar.Fatalf("Failed to load reputation denylists: %v", err)
+	}
+	geoIPProvider := screening.OpenMaxMindProvider(cfg.Screening.GeoIP.DatabasePath, appLog)
+	defer geoIPProvider.Close()
+	// Static rates only, so conversions do not depend on when the replay runs
+	currencyConverter := currency.NewConverter(&cfg.Currency, nil, appLog)
+
+	engine, err := screening.NewSnapshotEngine(snapshot, reputationProvider, geoIPProvider, currencyConverter,
+		&cfg.Screening, &cfg.Patterns, &cfg.Compliance, clock, appLog)
+	if err != nil {
+		sugar.Fatalf("Failed to create snapshot engine: %v", err)
+	}
+
+	var source service.ReplaySource
+	if *txFlag != "" {
+		var baselines map[uuid.UUID]*domain.ScreeningResult
+		if *baselineFlag != "" {
+			if baselines, err = readBaselines(*baselineFlag); err != nil {
+				sugar.Fatalf("Failed to read -baseline: %v", err)
+			}
+		}
+		source = transactionSource(*txFlag, baselines)
+	} else {
+		from, err := time.Parse(time.DateOnly, *fromFlag)
+		if err != nil {
+			sugar.Fatalf("Invalid -from: %v", err)
+		}
+		to := from
+		if *toFlag != "" {
+			if to, err = time.Parse(time.DateOnly, *toFlag); err != nil {
+				sugar.Fatalf("Invalid -to: %v", err)
+			}
+		}
+		if to.Before(from) {
+			sugar.Fatalf("-to %s is before -from %s", *toFlag, *fromFlag)
+		}
+
+		db, err := postgres.NewDB(ctx, &cfg.Database)
+		if err != nil {
+			sugar.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+
+		source = storedSource(ctx, postgres.NewScreeningResultRepository(db), &domain.ScreeningExportRequest{
+			From: from,
+			To:   to.AddDate(0, 0, 1),
+		})
+	}
+
+	var out func(*domain.ScreeningResult) error
+	closeOut := func() error { return nil }
+	if *outFlag != "" {
+		f, err := os.Create(*outFlag)
+		if err != nil {
+			sugar.Fatalf("Failed to create -out: %v", err)
+		}
+		w := bufio.NewWriter(f)
+		enc := json.NewEncoder(w)
+		out = func(result *domain.ScreeningResult) error { return enc.Encode(result) }
+		closeOut = func() error {
+			if err := w.Flush(); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		}
+	}
+
+	report, err := service.NewReplayService(engine, appLog).Replay(ctx, source, out)
+	if closeErr := closeOut(); closeErr != nil && err == nil {
+		err = fmt.Errorf("write -out: %w", closeErr)
+	}
+	if err != nil {
+		sugar.Errorf("Replay stopped after %d transactions: %v", report.Replayed, err)
+		os.Exit(1)
+	}
+
+	if err := writeReport(*reportFlag, report); err != nil {
+		sugar.Errorf("Failed to write report: %v", err)
+		os.Exit(1)
+	}
+
+	sugar.Infof("Replayed %d transactions, %d failed; %d of %d compared decisions changed (%.4f)",
+		report.Replayed, report.Failed, report.DecisionsChanged, report.Compared, report.ChangedDecisionRate)
+	if report.ChangedDecisionRate > *maxRateFlag {
+		sugar.Errorf("Changed-decision rate %.4f exceeds -max-changed-rate %.4f", report.ChangedDecisionRate, *maxRateFlag)
+		os.Exit(1)
+	}
+}
+
+// screeningStream reads stored screening results in a date range
+type screeningStream interface {
+	StreamForExport(ctx context.Context, req *domain.ScreeningExportRequest, fn func(*domain.ScreeningResult) error) error
+}
+
+// storedSource replays stored screening results, each compared with
+// itself. Re-screens and results without a stored transaction are skipped.
+func storedSource(ctx context.Context, results screeningStream, req *domain.ScreeningExportRequest) service.ReplaySource {
+	return func(fn func(*domain.ReplayCase) error) error {
+		return results.StreamForExport(ctx, req, func(result *domain.ScreeningResult) error {
+			if result.RescreenOfID != nil || result.Transaction == nil {
+				return nil
+			}
+			return fn(&domain.ReplayCase{Transaction: result.Transaction, Baseline: result})
+		})
+	}
+}
+
+// transactionSource replays a JSON lines file of transactions, each
+// compared with its baseline when there is one
+func transactionSource(path string, baselines map[uuid.UUID]*domain.ScreeningResult) service.ReplaySource {
+	return func(fn func(*domain.ReplayCase) error) error {
+		return readLines(path, func(line []byte) error {
+			var tx domain.Transaction
+			if err := json.Unmarshal(line, &tx); err != nil {
+				return err
+			}
+			return fn(&domain.ReplayCase{Transaction: &tx, Baseline: baselines[tx.ID]})
+		})
+	}
+}
+
+// readBaselines reads a JSON lines file of screening results by
+// transaction; a later result for the same transaction wins
+func readBaselines(path string) (map[uuid.UUID]*domain.ScreeningResult, error) {
+	baselines := make(map[uuid.UUID]*domain.ScreeningResult)
+	err := readLines(path, func(line []byte) error {
+		var result domain.ScreeningResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			return err
+		}
+		baselines[result.TransactionID] = &result
+		return nil
+	})
+	return baselines, err
+}
+
+// readLines calls fn for each non-empty line of a file
+func readLines(path string, fn func(line []byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return fmt.Errorf("%s line %d: %w", path, n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func writeReport(path string, report *domain.ReplayReport) error {
+	w := os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -3,36 +3,421 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/banking/aml-service/internal/api/grpc/grpcserver"
+	"github.com/banking/aml-service/internal/api/http/handlers"
+	amlmiddleware "github.com/banking/aml-service/internal/api/http/middleware"
+	"github.com/banking/aml-service/internal/api/http/openapi"
+	"github.com/banking/aml-service/internal/api/http/validation"
+	"github.com/banking/aml-service/internal/audit"
 	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/crypto"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/email"
+	"github.com/banking/aml-service/internal/notify"
+	"github.com/banking/aml-service/internal/patterns"
+	"github.com/banking/aml-service/internal/pkg/auth"
+	"github.com/banking/aml-service/internal/pkg/breaker"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/currency"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/pkg/tracing"
+	"github.com/banking/aml-service/internal/repository/kafka"
+	"github.com/banking/aml-service/internal/repository/objectstore"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/repository/redis"
+	"github.com/banking/aml-service/internal/screening"
+	"github.com/banking/aml-service/internal/service"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	// 1. Initialize Logger
-	logger, _ := zap.NewProduction()
-	defer logger.Sync()
-	sugar := logger.Sugar()
+	zapLogger, _ := zap.NewProduction()
+	defer zapLogger.Sync()
+	sugar := zapLogger.Sugar()
 
 	// 2. Load Configuration
 	cfg, err := config.Load()
 	if err != nil {
 		sugar.Fatalf("Failed to load configuration: %v", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	appLog, err := logger.New(cfg.Telemetry.ServiceName, cfg.Telemetry.Environment, false)
+	if err != nil {
+		sugar.Fatalf("Failed to create logger: %v", err)
+	}
+	defer appLog.Sync()
+
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Telemetry)
+	if err != nil {
+		sugar.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	// 3. Connect Database and Cache
+	db, err := postgres.NewDB(context.Background(), &cfg.Database)
+	if err != nil {
+		sugar.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	redisClient, err := redis.NewClient(context.Background(), &cfg.Redis)
+	if err != nil {
+		sugar.Fatalf("Failed to connect to redis: %v", err)
+	}
+	defer redisClient.Close()
+
+	keyring, err := crypto.NewKeyring(cfg.Security.EncryptionKeys, cfg.Security.CurrentKeyVersion)
+	if err != nil {
+		sugar.Fatalf("Failed to load encryption keys: %v", err)
+	}
+
+	screeningResultRepo := postgres.NewScreeningResultRepository(db)
+	transactionRepo := postgres.NewTransactionRepository(db)
+	investigationRepo := postgres.NewInvestigationRepository(db)
+	analystRepo := postgres.NewAnalystRepository(db)
+	alertRepo := postgres.NewAlertRepository(db)
+	riskProfileRepo := postgres.NewRiskProfileRepository(db)
+	filingRepo := postgres.NewFilingRepository(db, keyring)
+	batchJobRepo := postgres.NewBatchJobRepository(db)
+	locker := postgres.NewAdvisoryLocker(db)
+
+	// Tamper-evident audit log, stored in Postgres and streamed to Kafka
+	auditProducer := kafka.NewProducer(cfg.Kafka.Brokers, cfg.Kafka.AuditTopic)
+	defer auditProducer.Close()
+	auditRepo := postgres.NewAuditRepository(db)
+	auditWriter, err := audit.NewWriter(auditRepo, auditProducer, cfg.Security.AuditHMACSecret, appLog)
+	if err != nil {
+		sugar.Fatalf("Failed to create audit writer: %v", err)
+	}
+
+	// Events are pushed to the webhook endpoints subscribed to them. System
+	// alerts about failing endpoints bypass the alert service so they are
+	// not themselves published.
+	webhookEndpointRepo := postgres.NewWebhookEndpointRepository(db, keyring)
+	webhookEventRepo := postgres.NewWebhookEventRepository(db)
+	webhookDispatcher := service.NewWebhookDispatcher(
+		webhookEndpointRepo, webhookEventRepo, postgres.NewWebhookDeadLetterRepository(db), alertRepo, &cfg.Webhooks, appLog,
+	)
+	webhookEndpoints := service.NewWebhookEndpointService(webhookEndpointRepo, webhookEventRepo, webhookDispatcher, auditWriter, appLog)
+	if err := webhookEndpoints.SyncConfigured(context.Background(), cfg.Webhooks.Subscribers); err != nil {
+		sugar.Fatalf("Failed to load webhook endpoints: %v", err)
+	}
+
+	alertService := service.NewAlertService(alertRepo, auditWriter, webhookDispatcher, &cfg.Compliance, appLog)
+
+	// On-call staff are paged in chat about exact OFAC blocks and SLA breaches
+	chatNotifier, err := notify.NewDispatcher(redis.NewNotificationDedup(redisClient), &cfg.Notifications, appLog)
+	if err != nil {
+		sugar.Fatalf("Failed to configure notifications: %v", err)
+	}
+
+	// Circuit breakers for the screening path's Redis and Postgres lookups.
+	// OFAC name lookups are retried inside the breaker.
+	breakers := breaker.NewRegistry(appLog)
+	ofacCache := screening.WithOFACBreaker(screening.WithOFACRetry(redis.NewOFACCache(redisClient), cfg.Screening.OFACCacheRetry),
+		breakers.New(domain.DependencyOFACCache, cfg.Screening.Breakers.OFACCache))
+	pepCache := screening.WithPEPBreaker(redis.NewPEPCache(redisClient),
+		breakers.New(domain.DependencyPEPCache, cfg.Screening.Breakers.PEPCache))
+	velocityStore := redis.NewVelocityCache(redisClient)
+	velocityCache := screening.WithVelocityBreaker(velocityStore,
+		breakers.New(domain.DependencyVelocityCache, cfg.Screening.Breakers.VelocityCache))
+	riskProfiles := screening.WithRiskProfileBreaker(riskProfileRepo,
+		breakers.New(domain.DependencyRiskProfiles, cfg.Screening.Breakers.RiskProfiles, domain.ErrNotFound))
+
+	// Evidence, retention archives and list snapshots share the object store
+	var objectStore service.ObjectStore
+	switch cfg.Storage.Backend {
+	case "s3":
+		objectStore, err = objectstore.NewS3Store(&cfg.Storage)
+	default:
+		objectStore, err = objectstore.NewLocalStore(cfg.Storage.LocalDir)
+	}
+	if err != nil {
+		sugar.Fatalf("Failed to create object store: %v", err)
+	}
+	listArchive := screening.NewListArchive(objectStore)
+
+	// Screening engine
+	ofacMatcher, err := screening.NewNameMatcher(cfg.Screening.NameMatchers["ofac"])
+	if err != nil {
+		sugar.Fatalf("Failed to create ofac name matcher: %v", err)
+	}
+	pepMatcher, err := screening.NewNameMatcher(cfg.Screening.NameMatchers["pep"])
+	if err != nil {
+		sugar.Fatalf("Failed to create pep name matcher: %v", err)
+	}
+	nameNormalizer, err := screening.NewNameNormalizer(cfg.Screening.NameFolding, cfg.Screening.EntityStopwords)
+	if err != nil {
+		sugar.Fatalf("Failed to create name normalizer: %v", err)
+	}
+	ofacChecker := screening.NewOFACChecker(ofacCache, listArchive, ofacMatcher, nameNormalizer, appLog,
+		cfg.Screening.FuzzyMatchThreshold, cfg.Screening.BatchConcurrency, cfg.Screening.OFACDeltaMaxEntries)
+	pepChecker := screening.NewPEPChecker(pepCache, listArchive, pepMatcher, nameNormalizer, appLog,
+		cfg.Screening.FuzzyMatchThreshold, cfg.Screening.PEPPartialMatchThreshold)
+
+	// Indexes are loaded in the background; readiness waits for warm-up
+	warmer := screening.NewWarmer(ofacChecker, pepChecker, &cfg.Screening, appLog)
+
+	reputationProvider, err := screening.NewDenylistProvider(&cfg.Screening.Reputation)
+	if err != nil {
+		sugar.Fatalf("Failed to load reputation denylists: %v", err)
+	}
+
+	// IP geolocation is skipped when the GeoIP database is missing
+	geoIPProvider := screening.OpenMaxMindProvider(cfg.Screening.GeoIP.DatabasePath, appLog)
+	defer geoIPProvider.Close()
+
+	// Amount thresholds are set in the base currency; rates come from the
+	// static table, refreshed from the rates feed when one is configured
+	var ratesSource currency.RatesSource
+	if cfg.Currency.RatesURL != "" {
+		ratesSource = currency.NewHTTPSource(cfg.Currency.RatesURL, cfg.Currency.BaseCurrency)
+	}
+	currencyConverter := currency.NewConverter(&cfg.Currency, ratesSource, appLog)
+
+	accountDenylist := redis.NewAccountDenylist(redisClient)
+
+	// OFAC and PEP outcomes by counterparty, for repeat payees
+	var matchCache screening.MatchCache
+	if cfg.Redis.RiskCacheTTL > 0 {
+		matchCache = redis.NewMatchCache(redisClient, cfg.Redis.RiskCacheTTL)
+	}
+
+	// Counterparty countries are scored against the newest country risk
+	// table saved through the admin API, or the configured one
+	countryRisk := screening.NewCountryRisk(screening.ConfiguredCountryRiskTable(&cfg.Patterns))
+	countryRiskService := service.NewCountryRiskService(postgres.NewCountryRiskRepository(db), countryRisk,
+		countryRisk.Table(), auditWriter, cfg.Patterns.CountryRiskRefreshInterval, appLog)
+	if err := countryRiskService.Refresh(context.Background()); err != nil {
+		sugar.Fatalf("Failed to load country risk table: %v", err)
+	}
+
+	// Tenants' overrides of the configured thresholds and country risk tiers
+	tenantSettings := screening.NewTenantSettings()
+	tenantSettingsService := service.NewTenantSettingsService(postgres.NewTenantSettingsRepository(db), tenantSettings,
+		countryRisk, &cfg.Tenancy, &cfg.Compliance, auditWriter, appLog)
+	if err := tenantSettingsService.Refresh(context.Background()); err != nil {
+		sugar.Fatalf("Failed to load tenant settings: %v", err)
+	}
+
+	// Candidate scoring settings evaluated alongside the enforced ones. The
+	// shadow scorer shares the enforced country risk table unless it has
+	// candidate tiers of its own.
+	var shadowScorer *screening.ShadowScorer
+	if cfg.Screening.Shadow.Enabled {
+		shadowCountryRisk := countryRisk
+		if cfg.Screening.Shadow.CountryRiskTiers != nil {
+			shadowCountryRisk = nil
+		}
+		shadowScorer = screening.NewShadowScorer(
+			screening.NewRiskCalculator(cfg.Screening.Shadow.Patterns(&cfg.Patterns), shadowCountryRisk, currencyConverter, cfg.Compliance.CTRThreshold, appLog),
+			cfg.Screening.Shadow.Thresholds(cfg.Compliance.DecisionThresholds),
+		)
+	}
+
+	// Reputations of external accounts, accumulated across all users from
+	// blocked and suspicious screenings and submitted SARs
+	counterpartyRepo := postgres.NewCounterpartyRepository(db)
+	counterpartyChecker := screening.NewCounterpartyChecker(counterpartyRepo, &cfg.Screening.Counterparty, appLog)
+	counterpartyService := service.NewCounterpartyService(counterpartyRepo, screeningResultRepo, filingRepo, counterpartyChecker, clock.Real{}, appLog)
+
+	screeningEngine := screening.NewEngine(
+		ofacChecker,
+		pepChecker,
+		screening.NewReputationChecker(reputationProvider, screeningResultRepo, &cfg.Screening.Reputation, appLog),
+		screening.NewGeoIPChecker(geoIPProvider, velocityStore, countryRisk, &cfg.Screening.GeoIP, appLog),
+		screening.NewSharedDeviceChecker(redis.NewDeviceUsers(redisClient, &cfg.Screening.SharedDevice), &cfg.Screening.SharedDevice, appLog),
+		counterpartyChecker,
+		accountDenylist,
+		screening.NewRiskCalculator(&cfg.Patterns, countryRisk, currencyConverter, cfg.Compliance.CTRThreshold, appLog),
+		tenantSettings,
+		shadowScorer,
+		currencyConverter,
+		patterns.NewEngine(appLog,
+			patterns.NewStructuringDetector(transactionRepo, currencyConverter, clock.Real{}, &cfg.Patterns),
+			patterns.NewSmurfingDetector(transactionRepo, currencyConverter, clock.Real{}, &cfg.Patterns),
+			patterns.NewLayeringDetector(transactionRepo, clock.Real{}, &cfg.Patterns),
+			patterns.NewUnusualTimeDetector(transactionRepo, clock.Real{}, &cfg.Patterns),
+			patterns.NewDormantReactivationDetector(transactionRepo, velocityCache, currencyConverter, clock.Real{}, &cfg.Patterns),
+		),
+		transactionRepo,
+		velocityCache,
+		riskProfiles,
+		screeningResultRepo,
+		redis.NewResultCache(redisClient),
+		matchCache,
+		alertService,
+		auditWriter,
+		screening.DecisionNotifiers{webhookDispatcher, chatNotifier},
+		clock.Real{},
+		&cfg.Screening,
+		&cfg.Compliance,
+		appLog,
+	)
+
+	amlEventsProducer := kafka.NewProducer(cfg.Kafka.Brokers, cfg.Kafka.AMLEventsTopic)
+	defer amlEventsProducer.Close()
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	go currencyConverter.Run(jobsCtx)
+	go countryRiskService.Run(jobsCtx)
+	go tenantSettingsService.Run(jobsCtx)
+	go warmer.Run(jobsCtx)
+
+	// The webhook and chat dispatchers outlive the servers so events raised
+	// while they drain are still delivered or dead-lettered
+	webhooksCtx, cancelWebhooks := context.WithCancel(context.Background())
+	go chatNotifier.Run(webhooksCtx)
+	webhooksDone := make(chan struct{})
+	go func() {
+		webhookDispatcher.Run(webhooksCtx)
+		close(webhooksDone)
+	}()
+	stopWebhooks := func() {
+		cancelWebhooks()
+		<-webhooksDone
+	}
 
-	// 3. Initialize Echo
+	slaMonitor := service.NewSLAMonitor(investigationRepo, alertService, amlEventsProducer, webhookDispatcher, chatNotifier, locker, auditWriter, clock.Real{}, &cfg.Compliance, appLog)
+	go slaMonitor.Run(jobsCtx)
+
+	filingMonitor := service.NewFilingDeadlineMonitor(filingRepo, alertService, webhookDispatcher, locker, clock.Real{}, &cfg.Compliance, appLog)
+	go filingMonitor.Run(jobsCtx)
+
+	batchScreening := service.NewBatchScreeningService(
+		batchJobRepo, screeningResultRepo, ofacChecker, pepChecker, locker, &cfg.Patterns, appLog,
+	)
+	go batchScreening.Run(jobsCtx)
+
+	deltaRescreener := service.NewOFACDeltaRescreener(
+		ofacChecker, redis.NewOFACCache(redisClient), screeningResultRepo, alertService, locker, &cfg.Screening, appLog,
+	)
+	go deltaRescreener.Run(jobsCtx)
+
+	keyRotator := service.NewKeyRotator(locker, &cfg.Security, appLog, filingRepo, webhookEndpointRepo)
+	go keyRotator.Run(jobsCtx)
+
+	velocityBaselines := service.NewVelocityBaselineJob(screeningResultRepo, transactionRepo, redis.NewVelocityCache(redisClient), locker, &cfg.Patterns, appLog)
+	go velocityBaselines.Run(jobsCtx)
+
+	// Analysts get a morning email of their open work; in dry-run mode the
+	// emails are only logged
+	if cfg.Email.Digest.Enabled {
+		var sender email.Sender = email.NewLogSender(appLog)
+		if !cfg.Email.DryRun {
+			sender, err = email.NewSMTPSender(&cfg.Email.SMTP, cfg.Email.From)
+			if err != nil {
+				sugar.Fatalf("Failed to configure email: %v", err)
+			}
+		}
+		go service.NewAnalystDigestJob(analystRepo, investigationRepo, alertRepo, filingRepo, sender, locker, &cfg.Email.Digest, appLog).Run(jobsCtx)
+	}
+
+	filingService := service.NewFilingService(filingRepo, screeningResultRepo, investigationRepo, alertRepo, auditWriter, counterpartyService, clock.Real{}, &cfg.Compliance, appLog)
+	investigationService := service.NewInvestigationService(investigationRepo, alertService, auditWriter, clock.Real{}, &cfg.Compliance, appLog)
+	retentionJob := service.NewRetentionJob(postgres.NewRetentionRepository(db), objectStore, auditWriter, locker, &cfg.Compliance.Retention, appLog)
+	if cfg.Compliance.Retention.Enabled {
+		go retentionJob.Run(jobsCtx)
+	}
+	if cfg.Compliance.AlertAutoClose.Enabled {
+		go service.NewAlertAutoCloseJob(alertRepo, locker, auditWriter, &cfg.Compliance.AlertAutoClose, appLog).Run(jobsCtx)
+	}
+	entityGraphService := service.NewEntityGraphService(investigationRepo, postgres.NewEntityGraphRepository(db), appLog)
+	userActivityService := service.NewUserActivityService(screeningResultRepo, alertRepo, investigationRepo, filingRepo,
+		riskProfileRepo, redis.NewVelocityCache(redisClient), cfg.Server.UserActivityBudget, appLog)
+	evidenceService := service.NewEvidenceService(investigationRepo, objectStore, auditWriter, appLog)
+	watchlistService, err := service.NewWatchlistService(riskProfileRepo, auditWriter, &cfg.Compliance, appLog)
+	if err != nil {
+		sugar.Fatalf("Failed to create watchlist service: %v", err)
+	}
+	reportRepo := postgres.NewReportRepository(db)
+	reportService, err := service.NewReportService(reportRepo, reportRepo, &cfg.Compliance, appLog)
+	if err != nil {
+		sugar.Fatalf("Failed to create report service: %v", err)
+	}
+	screeningExport := service.NewScreeningExportService(screeningResultRepo, auditWriter, &cfg.Compliance, appLog)
+	// Re-screens pinned to archived list versions run through a snapshot
+	// engine over those versions, screened as of when the run starts
+	pinnedRescreener := func(ctx context.Context, ofacVersion, pepVersion int64) (service.TransactionRescreener, error) {
+		snapshot, err := listArchive.Snapshot(ctx, ofacVersion, pepVersion)
+		if err != nil {
+			return nil, err
+		}
+		engine, err := screening.NewSnapshotEngine(snapshot, reputationProvider, geoIPProvider, currencyConverter,
+			&cfg.Screening, &cfg.Patterns, &cfg.Compliance, time.Now(), appLog)
+		if err != nil {
+			return nil, err
+		}
+		return engine, nil
+	}
+	retroactiveRescreens := service.NewRetroactiveRescreenService(
+		screeningResultRepo, screeningEngine, pinnedRescreener, ofacChecker, alertService, auditWriter, locker, &cfg.Screening, appLog,
+	)
+	go retroactiveRescreens.Run(jobsCtx)
+
+	// Screen transactions published by the transaction service
+	idempotencyStore := redis.NewIdempotencyStore(redisClient)
+	deadLetterProducer := kafka.NewProducer(cfg.Kafka.Brokers, cfg.Kafka.DeadLetterTopic)
+	defer deadLetterProducer.Close()
+	transactionConsumer := newTransactionConsumer(&cfg.Kafka, deadLetterProducer, appLog)
+	defer transactionConsumer.Close()
+	transactionEvents := service.NewTransactionEventHandler(
+		screeningEngine, idempotencyStore, amlEventsProducer, cfg.Tenancy.Allows, cfg.Server.IdempotencyTTL, appLog,
+	)
+	go func() {
+		// Consuming starts once the list indexes are loaded
+		if err := warmer.Wait(jobsCtx); err != nil {
+			return
+		}
+		if err := transactionConsumer.Run(jobsCtx, tracing.ConsumerInterceptor(transactionEvents.Handle)); err != nil {
+			appLog.Error("transaction consumer stopped", logger.ErrorField(err))
+		}
+	}()
+
+	// Reads of SAR subject and investigation data are recorded in the audit
+	// log; screening reads are not, as their volume would swamp it
+	piiAccess := service.NewPIIAccessService(filingRepo, investigationRepo, auditWriter, auditRepo, appLog)
+	piiReads := amlmiddleware.AuditedReads{
+		"GET /api/v1/filings/:id":            {EntityType: audit.EntityFiling, Category: domain.PIIAccessSummary, PurposeRequired: true},
+		"GET /api/v1/filings/:id/fincen.xml": {EntityType: audit.EntityFiling, Category: domain.PIIAccessFull, PurposeRequired: true},
+		"GET /api/v1/filings/:id/history":    {EntityType: audit.EntityFiling, Category: domain.PIIAccessSummary, PurposeRequired: true},
+
+		"GET /api/v1/investigations/:id/timeline":                   {EntityType: audit.EntityInvestigation, Category: domain.PIIAccessSummary},
+		"GET /api/v1/investigations/:id/notes":                      {EntityType: audit.EntityInvestigation, Category: domain.PIIAccessFull},
+		"GET /api/v1/investigations/:id/graph":                      {EntityType: audit.EntityInvestigation, Category: domain.PIIAccessSummary},
+		"GET /api/v1/investigations/:id/evidence":                   {EntityType: audit.EntityInvestigation, Category: domain.PIIAccessSummary},
+		"GET /api/v1/investigations/:id/evidence/:evidence_id/file": {EntityType: audit.EntityInvestigation, Category: domain.PIIAccessFull},
+	}
+
+	// 4. Initialize Echo
 	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(appLog)
+	e.Validator = validation.New()
 
-	// 4. Middleware
+	// 5. Middleware
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.RequestID())
+	e.Use(amlmiddleware.Tracing(piiReads.Audited))
 	e.Use(middleware.Secure()) // Security headers
 
 	// CORS Setup
@@ -41,12 +426,82 @@ func main() {
 		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
 	}))
 
-	// 5. Health Check Route
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	// 6. Health Check Routes
+	handlers.NewHealthHandler(ofacChecker, pepChecker, warmer, &cfg.Screening,
+		handlers.DependencyCheck{Name: "postgres", Critical: true, Check: db.PingContext},
+		handlers.DependencyCheck{Name: "redis", Critical: true, Check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+		handlers.DependencyCheck{Name: "kafka", Check: func(ctx context.Context) error {
+			return kafka.Ping(ctx, cfg.Kafka.Brokers)
+		}},
+	).Register(e.Group("/health"))
+
+	// 7. API Routes
+	api := e.Group("/api/v1")
+	api.Use(amlmiddleware.Tenant(cfg.Security.JWTSecret, cfg.Tenancy.Allows))
+	api.Use(amlmiddleware.Idempotency(idempotencyStore, cfg.Server.IdempotencyTTL, cfg.Server.WriteTimeout, cfg.Server.MaxRequestSize, appLog))
+	api.Use(amlmiddleware.ReadAudit(piiAccess, piiReads, appLog))
+	// Routes that record who acted take the actor from a verified token
+	requireToken := amlmiddleware.RequireToken(cfg.Security.JWTSecret)
+	handlers.NewScreeningHandler(screeningResultRepo, screeningEngine, screeningExport, appLog).Register(api)
+	handlers.NewBatchScreeningHandler(batchScreening, appLog).Register(api)
+	handlers.NewInvestigationHandler(investigationRepo, investigationService, investigationService, appLog).Register(api)
+	handlers.NewEntityGraphHandler(entityGraphService, appLog).Register(api)
+	handlers.NewUserActivityHandler(userActivityService, appLog).Register(api)
+	handlers.NewCounterpartyHandler(counterpartyService, appLog).Register(api)
+	handlers.NewEvidenceHandler(evidenceService, cfg.Server.MaxRequestSize, appLog).Register(api)
+	handlers.NewAnalystHandler(analystRepo, appLog).Register(api)
+	handlers.NewFilingHandler(filingService, requireToken, appLog).Register(api)
+	handlers.NewAlertHandler(alertService, appLog).Register(api)
+	handlers.NewSystemHandler(breakers).Register(api)
+	handlers.NewListStatusHandler(ofacChecker, pepChecker, &cfg.Screening, appLog).Register(api)
+	auditHandler := handlers.NewAuditHandler(auditWriter, piiAccess, appLog)
+	auditHandler.Register(api)
+	handlers.NewWatchlistHandler(watchlistService, requireToken, appLog).Register(api)
+	handlers.NewReportHandler(reportService, appLog).Register(api)
+
+	// Admin routes need an admin token and are rate-limited per caller
+	requireAdmin := amlmiddleware.RequireRole(cfg.Security.JWTSecret, auth.RoleAdmin)
+	adminRateLimit := middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Limit(float64(cfg.Security.AdminRateLimitPerMinute) / 60),
+			Burst:     cfg.Security.AdminRateLimitPerMinute,
+			ExpiresIn: 10 * time.Minute,
+		}),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			if subject, _ := c.Get(amlmiddleware.SubjectContextKey).(string); subject != "" {
+				return subject, nil
+			}
+			return c.RealIP(), nil
+		},
 	})
+	admin := api.Group("/admin", requireAdmin, adminRateLimit)
+	handlers.NewAdminHandler(deltaRescreener, pepChecker, retroactiveRescreens, appLog).Register(admin)
+	handlers.NewAccountDenylistHandler(service.NewAccountDenylistService(accountDenylist, auditWriter, appLog), appLog).Register(admin)
+	handlers.NewWebhookHandler(webhookEndpoints, appLog).Register(admin)
+	handlers.NewCountryRiskHandler(countryRiskService, appLog).Register(admin)
+	handlers.NewTenantSettingsHandler(tenantSettingsService, appLog).Register(admin)
+
+	// Data subject access reports name who read a user's SAR and
+	// investigation data, so they are for admins only
+	api.GET("/audit/access", auditHandler.AccessReport, requireAdmin, adminRateLimit)
 
-	// 6. Start Server (Graceful Shutdown)
+	// The OpenAPI document is public; its Swagger UI needs an admin token
+	apiDocs := openapi.New()
+	docsHandler, err := openapi.NewHandler(apiDocs, "/api/v1/openapi.json")
+	if err != nil {
+		sugar.Fatalf("Failed to render API documentation: %v", err)
+	}
+	api.GET("/openapi.json", docsHandler.Spec)
+	e.GET("/docs", docsHandler.UI, amlmiddleware.RequireRole(cfg.Security.JWTSecret, auth.RoleAdmin))
+	if missing := apiDocs.Undocumented(e.Routes()); len(missing) > 0 {
+		appLog.Warn("routes missing from the OpenAPI document",
+			logger.StringField("routes", strings.Join(missing, ", ")),
+		)
+	}
+
+	// 8. Start Server (Graceful Shutdown)
 	serverAddr := fmt.Sprintf(":%d", cfg.Server.Port)
 
 	go func() {
@@ -57,12 +512,49 @@ func main() {
 
 	sugar.Infof("Server started on %s", serverAddr)
 
+	// Prometheus metrics are served on a separate port so they are not
+	// exposed alongside the public API
+	metricsServer := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Server.MetricsPort),
+		Handler:      metrics.Handler(),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			sugar.Fatalf("shutting down the metrics server: %v", err)
+		}
+	}()
+
+	sugar.Infof("Metrics server started on %s", metricsServer.Addr)
+
+	// The gRPC screening service shares the engine and result store with
+	// the HTTP API
+	grpcServer := grpcserver.NewServer(
+		grpcserver.NewScreeningServer(screeningResultRepo, screeningEngine, appLog),
+		cfg.Security.JWTSecret,
+		cfg.Tenancy.Allows,
+		appLog,
+	)
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPCPort))
+	if err != nil {
+		sugar.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			sugar.Fatalf("shutting down the gRPC server: %v", err)
+		}
+	}()
+
+	sugar.Infof("gRPC server started on %s", grpcListener.Addr())
+
 	// Wait for interrupt signal to gracefully shutdown the server with a timeout
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	sugar.Info("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
@@ -70,6 +562,52 @@ func main() {
 	if err := e.Shutdown(ctx); err != nil {
 		sugar.Fatal(err)
 	}
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		sugar.Fatal(err)
+	}
+	shutdownGRPC(ctx, grpcServer)
+	stopWebhooks()
+	if err := shutdownTracing(ctx); err != nil {
+		sugar.Errorf("Failed to flush traces: %v", err)
+	}
 
 	sugar.Info("Server exited properly")
 }
+
+// shutdownGRPC lets in-flight calls finish, then stops the server outright
+// once ctx is done
+func shutdownGRPC(ctx context.Context, s *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
+
+// newTransactionConsumer reads the transaction topic and any per-priority
+// topics, screening the most urgent events first
+func newTransactionConsumer(cfg *config.KafkaConfig, deadLetters kafka.DeadLetterPublisher, log *logger.Logger) *kafka.PriorityConsumer {
+	levels := make([]kafka.PriorityLevel, len(domain.ScreeningPriorities))
+	for i, p := range domain.ScreeningPriorities {
+		levels[i] = kafka.PriorityLevel{Name: string(p), Weight: cfg.PriorityWeight(string(p))}
+	}
+
+	topics := []kafka.PriorityTopic{{Topic: cfg.TransactionTopic, Level: domain.ScreeningPriorityNormal.Level()}}
+	for name, topic := range cfg.PriorityTopics {
+		priority, _ := domain.ParseScreeningPriority(name)
+		topics = append(topics, kafka.PriorityTopic{Topic: topic, Level: priority.Level()})
+	}
+
+	classify := func(msg tracing.KafkaMessage) (int, bool) {
+		priority, ok := service.TransactionEventPriority(msg)
+		return priority.Level(), ok
+	}
+
+	return kafka.NewPriorityConsumer(cfg.Brokers, cfg.ConsumerGroup, topics, levels, classify,
+		cfg.PriorityQueueSize, cfg.TransactionWorkers, deadLetters, cfg.HandlerMaxAttempts, cfg.HandlerRetryBackoff, log)
+}
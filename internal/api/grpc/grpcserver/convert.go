@@ -0,0 +1,197 @@
+package grpcserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	screeningv1 "github.com/banking/aml-service/api/proto/screening/v1"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// toScreeningRequest converts a proto screen request to the domain request.
+// The transaction must carry an id and user_id; other IDs are optional.
+func toScreeningRequest(req *screeningv1.ScreenRequest) (*domain.ScreeningRequest, error) {
+	tx := req.GetTransaction()
+	if tx == nil {
+		return nil, fmt.Errorf("transaction with id and user_id is required")
+	}
+
+	id, err := uuid.Parse(tx.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction id")
+	}
+	userID, err := uuid.Parse(tx.GetUserId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	accountID, err := optionalUUID(tx.GetAccountId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid account_id")
+	}
+	requesterID, err := optionalUUID(req.GetRequesterId())
+	if err != nil {
+		return nil, fmt.Errorf("invalid requester_id")
+	}
+
+	return &domain.ScreeningRequest{
+		Transaction: &domain.Transaction{
+			ID:              id,
+			UserID:          userID,
+			AccountID:       accountID,
+			Type:            tx.GetType(),
+			Direction:       tx.GetDirection(),
+			Amount:          money.FromFloat(tx.GetAmount()),
+			Currency:        tx.GetCurrency(),
+			SenderName:      tx.GetSenderName(),
+			SenderAccount:   tx.GetSenderAccount(),
+			SenderCountry:   tx.GetSenderCountry(),
+			SenderBank:      tx.GetSenderBank(),
+			ReceiverName:    tx.GetReceiverName(),
+			ReceiverAccount: tx.GetReceiverAccount(),
+			ReceiverCountry: tx.GetReceiverCountry(),
+			ReceiverBank:    tx.GetReceiverBank(),
+			Description:     tx.GetDescription(),
+			Reference:       tx.GetReference(),
+			Channel:         tx.GetChannel(),
+			IPAddress:       tx.GetIpAddress(),
+			DeviceID:        tx.GetDeviceId(),
+			GeoLocation:     tx.GetGeoLocation(),
+			InitiatedAt:     fromTimestamp(tx.GetInitiatedAt()),
+			CreatedAt:       fromTimestamp(tx.GetCreatedAt()),
+		},
+		RequesterID: requesterID,
+		Priority:    domain.ScreeningPriority(req.GetPriority()),
+		BypassCache: req.GetBypassCache(),
+	}, nil
+}
+
+// toScreenResponse converts a domain screening response to proto
+func toScreenResponse(resp *domain.ScreeningResponse) *screeningv1.ScreenResponse {
+	return &screeningv1.ScreenResponse{
+		ScreeningId:          resp.ScreeningID.String(),
+		TransactionId:        resp.TransactionID.String(),
+		Decision:             string(resp.Decision),
+		RiskScore:            int32(resp.RiskScore),
+		RiskLevel:            string(resp.RiskLevel),
+		ProcessingTimeMs:     resp.ProcessingTimeMs,
+		OfacMatch:            resp.OFACMatch,
+		PepMatch:             resp.PEPMatch,
+		PatternDetected:      resp.PatternDetected,
+		RiskFactors:          resp.RiskFactors,
+		ReasonCodes:          resp.ReasonCodes,
+		ChecksFailed:         resp.ChecksFailed,
+		Degraded:             resp.Degraded,
+		InvestigationCreated: resp.InvestigationCreated,
+		InvestigationId:      idString(resp.InvestigationID),
+		IdempotentReplay:     resp.IdempotentReplay,
+		CacheHit:             resp.CacheHit,
+	}
+}
+
+// toScreeningResult converts a stored screening result to proto
+func toScreeningResult(r *domain.ScreeningResult) *screeningv1.ScreeningResult {
+	out := &screeningv1.ScreeningResult{
+		Id:                   r.ID.String(),
+		TransactionId:        r.TransactionID.String(),
+		UserId:               r.UserID.String(),
+		RiskScore:            int32(r.RiskScore),
+		Decision:             string(r.Decision),
+		RiskLevel:            string(r.RiskLevel),
+		ReasonCodes:          r.ReasonCodes,
+		DegradedDependencies: r.DegradedDependencies,
+		RescreenOfId:         idString(r.RescreenOfID),
+		ScreeningDurationMs:  r.ScreeningDurationMs,
+		CreatedAt:            timestamppb.New(r.CreatedAt),
+	}
+
+	if m := r.OFACMatch; m != nil {
+		out.OfacMatch = &screeningv1.OFACMatch{
+			Matched:      m.Matched,
+			MatchScore:   m.MatchScore,
+			MatchType:    string(m.MatchType),
+			SdnName:      m.SDNName,
+			SdnType:      m.SDNType,
+			Program:      m.Program,
+			MatchedField: m.MatchedField,
+			Degraded:     m.Degraded,
+		}
+	}
+	if m := r.PEPMatch; m != nil {
+		out.PepMatch = &screeningv1.PEPMatch{
+			Matched:       m.Matched,
+			MatchScore:    m.MatchScore,
+			MatchType:     string(m.MatchType),
+			PepName:       m.PEPName,
+			PepPosition:   m.PEPPosition,
+			PepCountry:    m.PEPCountry,
+			RiskCategory:  m.RiskCategory,
+			Associate:     m.Associate,
+			AssociateName: m.AssociateName,
+			Degraded:      m.Degraded,
+		}
+		if m.PEPEndDate != nil {
+			out.PepMatch.PepEndDate = timestamppb.New(*m.PEPEndDate)
+		}
+	}
+
+	for _, f := range r.RiskFactors {
+		out.RiskFactors = append(out.RiskFactors, &screeningv1.RiskFactor{
+			Factor:      f.Factor,
+			Weight:      int32(f.Weight),
+			Description: f.Description,
+			Details:     f.Details,
+		})
+	}
+	for _, p := range r.PatternMatches {
+		related := make([]string, len(p.RelatedTxIDs))
+		for i, id := range p.RelatedTxIDs {
+			related[i] = id.String()
+		}
+		out.PatternMatches = append(out.PatternMatches, &screeningv1.PatternMatch{
+			PatternType:  string(p.PatternType),
+			Confidence:   p.Confidence,
+			Description:  p.Description,
+			RelatedTxIds: related,
+			DetectedAt:   timestamppb.New(p.DetectedAt),
+		})
+	}
+	for _, f := range r.ChecksFailed {
+		out.ChecksFailed = append(out.ChecksFailed, &screeningv1.CheckFailure{
+			Check:    f.Check,
+			Error:    f.Error,
+			Blocking: f.Blocking,
+			Skipped:  f.Skipped,
+		})
+	}
+
+	return out
+}
+
+// optionalUUID parses s, treating an empty string as the nil UUID
+func optionalUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
+
+// idString formats an optional ID, returning "" when it is unset
+func idString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// fromTimestamp converts an optional timestamp, returning the zero time
+// when it is unset
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
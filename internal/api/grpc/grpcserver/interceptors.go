@@ -0,0 +1,129 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/banking/aml-service/internal/pkg/auth"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// metricsInterceptor records the duration and status code of every call
+func metricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.ObserveGRPC(path.Base(info.FullMethod), status.Code(err).String(), time.Since(start))
+		return resp, err
+	}
+}
+
+// recoveryInterceptor turns a handler panic into an INTERNAL error so one
+// bad request cannot take the server down
+func recoveryInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("grpc handler panicked",
+					logger.StringField("method", info.FullMethod),
+					logger.StringField("panic", fmt.Sprint(r)),
+					logger.StringField("stack", string(debug.Stack())),
+				)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// authInterceptor admits calls bearing an HS256 JWT, signed with secret,
+// whose roles claim includes role. It mirrors the HTTP RequireRole
+// middleware: missing or invalid tokens get UNAUTHENTICATED and tokens
+// without the role get PERMISSION_DENIED. With no secret configured every
+// call is rejected.
+func authInterceptor(secret, role string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if secret == "" {
+			return nil, status.Error(codes.Unauthenticated, auth.ErrNotConfigured.Error())
+		}
+
+		raw, ok := bearerToken(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		if _, err := auth.VerifyRole(secret, raw, role); err != nil {
+			if errors.Is(err, auth.ErrMissingRole) {
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// tenantInterceptor puts the tenant a call acts for in its context. It
+// mirrors the HTTP Tenant middleware: the token's tenant_id claim takes
+// precedence, x-tenant-id metadata naming another tenant alongside it gets
+// PERMISSION_DENIED, and without either the call acts for tenant.Default.
+// Tenants not allowed get INVALID_ARGUMENT. It must run after
+// authInterceptor, which has verified the token.
+func tenantInterceptor(secret string, allowed func(id string) bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(tenant.GRPCKey); len(v) > 0 {
+				id = strings.TrimSpace(v[0])
+			}
+		}
+
+		if raw, ok := bearerToken(ctx); ok {
+			claimed, err := auth.VerifyTenant(secret, raw)
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			if claimed != "" {
+				if id != "" && id != claimed {
+					return nil, status.Error(codes.PermissionDenied, "token is not valid for tenant "+id)
+				}
+				id = claimed
+			}
+		}
+
+		if id == "" {
+			id = tenant.Default
+		}
+		if !allowed(id) {
+			return nil, status.Error(codes.InvalidArgument, "unknown tenant "+id)
+		}
+
+		return handler(tenant.WithID(ctx, id), req)
+	}
+}
+
+// bearerToken returns the token from the "authorization" metadata
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	for _, v := range md.Get("authorization") {
+		if raw, ok := strings.CutPrefix(v, "Bearer "); ok && raw != "" {
+			return raw, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,119 @@
+// Package grpcserver serves the gRPC screening API defined in
+// api/proto/screening/v1 for internal callers on the payment path.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	screeningv1 "github.com/banking/aml-service/api/proto/screening/v1"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/apperr"
+	"github.com/banking/aml-service/internal/pkg/auth"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// ScreeningResultReader reads stored screening results
+type ScreeningResultReader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScreeningResult, error)
+}
+
+// Screener screens transactions
+type Screener interface {
+	ScreenRequest(ctx context.Context, req *domain.ScreeningRequest) (*domain.ScreeningResult, error)
+}
+
+// ScreeningServer implements the ScreeningService on the same screening
+// engine and result store as the HTTP API
+type ScreeningServer struct {
+	screeningv1.UnimplementedScreeningServiceServer
+
+	results  ScreeningResultReader
+	screener Screener
+	log      *logger.Logger
+}
+
+// NewScreeningServer creates a new gRPC screening server
+func NewScreeningServer(results ScreeningResultReader, screener Screener, log *logger.Logger) *ScreeningServer {
+	return &ScreeningServer{
+		results:  results,
+		screener: screener,
+		log:      log.Named("grpc_screening"),
+	}
+}
+
+// NewServer creates a gRPC server with the screening service registered.
+// Calls pass through metrics, panic recovery, authentication and tenant
+// resolution, in that order, so recovered panics and rejected calls are
+// still measured. tenants reports whether a tenant is configured.
+func NewServer(srv *ScreeningServer, jwtSecret string, tenants func(id string) bool, log *logger.Logger) *grpc.Server {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		metricsInterceptor(),
+		recoveryInterceptor(log.Named("grpc")),
+		authInterceptor(jwtSecret, auth.RoleScreening),
+		tenantInterceptor(jwtSecret, tenants),
+	))
+	screeningv1.RegisterScreeningServiceServer(s, srv)
+	return s
+}
+
+// Screen screens a single transaction synchronously. The engine's latency
+// budget is bounded by the call's deadline, so a caller with a shorter
+// deadline gets DEADLINE_EXCEEDED rather than a late decision.
+func (s *ScreeningServer) Screen(ctx context.Context, req *screeningv1.ScreenRequest) (*screeningv1.ScreenResponse, error) {
+	screenReq, err := toScreeningRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.screener.ScreenRequest(ctx, screenReq)
+	if errors.Is(err, domain.ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		s.log.Error("screening failed",
+			logger.StringField("transaction_id", screenReq.Transaction.ID.String()),
+			logger.ErrorField(err),
+		)
+		return nil, failure(err, "screening failed")
+	}
+
+	return toScreenResponse(result.ToResponse()), nil
+}
+
+// GetScreeningResult returns a stored screening result by ID
+func (s *ScreeningServer) GetScreeningResult(ctx context.Context, req *screeningv1.GetScreeningResultRequest) (*screeningv1.ScreeningResult, error) {
+	id, err := uuid.Parse(req.GetScreeningId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid screening id")
+	}
+
+	result, err := s.results.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "screening result not found")
+		}
+		s.log.Error("failed to get screening result", logger.ErrorField(err))
+		return nil, failure(err, "failed to get screening result")
+	}
+
+	return toScreeningResult(result), nil
+}
+
+// failure returns the status for an unexpected error: UNAVAILABLE when a
+// dependency was unavailable, so callers know a retry may succeed, and
+// INTERNAL otherwise
+func failure(err error, message string) error {
+	if apperr.CodeOf(err) == apperr.CodeDependencyUnavailable {
+		return status.Error(codes.Unavailable, message)
+	}
+	return status.Error(codes.Internal, message)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// AccountDenylistManager adds and removes accounts on the internal denylist
+type AccountDenylistManager interface {
+	List(ctx context.Context) ([]*domain.AccountDenylistEntry, error)
+	Add(ctx context.Context, account string, req *domain.AccountDenylistChangeRequest) (*domain.AccountDenylistEntry, error)
+	Remove(ctx context.Context, account string, req *domain.AccountDenylistChangeRequest) (*domain.AccountDenylistEntry, error)
+}
+
+// AccountDenylistHandler serves the account denylist endpoints. It is
+// mounted on the admin group, which applies authentication and rate
+// limiting.
+type AccountDenylistHandler struct {
+	denylist AccountDenylistManager
+	log      *logger.Logger
+}
+
+// NewAccountDenylistHandler creates a new account denylist handler
+func NewAccountDenylistHandler(denylist AccountDenylistManager, log *logger.Logger) *AccountDenylistHandler {
+	return &AccountDenylistHandler{
+		denylist: denylist,
+		log:      log.Named("account_denylist_handler"),
+	}
+}
+
+// Register mounts the account denylist routes on the given group
+func (h *AccountDenylistHandler) Register(g *echo.Group) {
+	g.GET("/account-denylist", h.List)
+	g.POST("/account-denylist/:account", h.Add)
+	g.DELETE("/account-denylist/:account", h.Remove)
+}
+
+// List returns the denylisted accounts, most recently added first
+func (h *AccountDenylistHandler) List(c echo.Context) error {
+	entries, err := h.denylist.List(c.Request().Context())
+	if err != nil {
+		h.log.Error("failed to list account denylist", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to list account denylist")
+	}
+
+	return c.JSON(http.StatusOK, &domain.AccountDenylistListResponse{
+		Entries: entries,
+		Total:   len(entries),
+	})
+}
+
+// Add denylists an account number or IBAN
+func (h *AccountDenylistHandler) Add(c echo.Context) error {
+	return h.change(c, h.denylist.Add, "add account to denylist")
+}
+
+// Remove takes an account number or IBAN off the denylist
+func (h *AccountDenylistHandler) Remove(c echo.Context) error {
+	return h.change(c, h.denylist.Remove, "remove account from denylist")
+}
+
+func (h *AccountDenylistHandler) change(
+	c echo.Context,
+	apply func(context.Context, string, *domain.AccountDenylistChangeRequest) (*domain.AccountDenylistEntry, error),
+	action string,
+) error {
+	account := c.Param("account")
+
+	var req domain.AccountDenylistChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	entry, err := apply(c.Request().Context(), account, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrValidation):
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrNotFound):
+			return errorResponse(c, http.StatusNotFound, "account is not denylisted")
+		case errors.Is(err, domain.ErrConflict):
+			return errorResponse(c, http.StatusConflict, err.Error())
+		}
+		h.log.Error("failed to "+action, logger.ErrorField(err))
+		return failureResponse(c, err, "failed to "+action)
+	}
+
+	return c.JSON(http.StatusOK, entry)
+}
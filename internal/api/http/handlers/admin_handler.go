@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	amlmiddleware "github.com/banking/aml-service/internal/api/http/middleware"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// IndexLoader reloads a sanctions or PEP list index and reports its state
+type IndexLoader interface {
+	LoadIndex(ctx context.Context) error
+	IndexStatus() screening.IndexStatus
+	ListVersion() screening.ListVersion
+}
+
+// RetroactiveRescreener replays historical transactions after a list change
+type RetroactiveRescreener interface {
+	Start(ctx context.Context, req *domain.RetroactiveRescreenRequest) (*domain.RetroactiveRescreenRun, error)
+	Status() *domain.RetroactiveRescreenRun
+}
+
+// IndexReloadResponse reports the state of an index after an on-demand reload
+type IndexReloadResponse struct {
+	List string `json:"list"`
+	screening.IndexStatus
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// ListVersionResponse describes the version of a list this instance
+// screens against. LoadedAt is when any instance first loaded the version.
+type ListVersionResponse struct {
+	List string `json:"list"`
+	screening.ListVersion
+}
+
+// AdminHandler serves operational endpoints reserved for administrators.
+// Authentication and rate limiting are applied by the group it is mounted on.
+type AdminHandler struct {
+	ofac      IndexLoader
+	pep       IndexLoader
+	rescreens RetroactiveRescreener
+	log       *logger.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(ofac, pep IndexLoader, rescreens RetroactiveRescreener, log *logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		ofac:      ofac,
+		pep:       pep,
+		rescreens: rescreens,
+		log:       log.Named("admin_handler"),
+	}
+}
+
+// Register mounts the admin routes on the given group
+func (h *AdminHandler) Register(g *echo.Group) {
+	g.GET("/lists", h.GetLists)
+	g.POST("/reload/ofac", h.ReloadOFAC)
+	g.POST("/reload/pep", h.ReloadPEP)
+	g.POST("/rescreen/retroactive", h.StartRetroactiveRescreen)
+	g.GET("/rescreen/retroactive", h.GetRetroactiveRescreen)
+}
+
+// GetLists returns the version, content hash, entry count and first load
+// time of the OFAC and PEP lists this instance screens against, as stamped
+// on the results it produces
+func (h *AdminHandler) GetLists(c echo.Context) error {
+	return c.JSON(http.StatusOK, []ListVersionResponse{
+		{List: "ofac", ListVersion: h.ofac.ListVersion()},
+		{List: "pep", ListVersion: h.pep.ListVersion()},
+	})
+}
+
+// ReloadOFAC reloads the OFAC index from the list cache, for emergency
+// designations that cannot wait for the scheduled refresh
+func (h *AdminHandler) ReloadOFAC(c echo.Context) error {
+	return h.reload(c, "ofac", h.ofac)
+}
+
+// ReloadPEP reloads the PEP index from the list cache
+func (h *AdminHandler) ReloadPEP(c echo.Context) error {
+	return h.reload(c, "pep", h.pep)
+}
+
+func (h *AdminHandler) reload(c echo.Context, list string, loader IndexLoader) error {
+	ctx := c.Request().Context()
+	subject, _ := c.Get(amlmiddleware.SubjectContextKey).(string)
+
+	start := time.Now()
+	if err := loader.LoadIndex(ctx); err != nil {
+		h.log.WithContext(ctx).Error("on-demand index reload failed",
+			logger.StringField("list", list),
+			logger.StringField("requested_by", subject),
+			logger.ErrorField(err),
+		)
+		return errorResponse(c, http.StatusServiceUnavailable, "failed to reload "+list+" index")
+	}
+
+	resp := &IndexReloadResponse{
+		List:        list,
+		IndexStatus: loader.IndexStatus(),
+		DurationMs:  time.Since(start).Milliseconds(),
+	}
+
+	h.log.WithContext(ctx).Info("index reloaded on demand",
+		logger.StringField("list", list),
+		logger.StringField("requested_by", subject),
+		logger.IntField("entries", resp.Entries),
+	)
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// StartRetroactiveRescreen queues a replay of stored transactions against
+// the current lists, e.g. after OFAC adds a designation. The run continues
+// in the background; its progress is served by GetRetroactiveRescreen.
+func (h *AdminHandler) StartRetroactiveRescreen(c echo.Context) error {
+	var req domain.RetroactiveRescreenRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	ctx := c.Request().Context()
+	subject, _ := c.Get(amlmiddleware.SubjectContextKey).(string)
+
+	run, err := h.rescreens.Start(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrValidation):
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrConflict):
+			return errorResponse(c, http.StatusConflict, err.Error())
+		}
+		h.log.WithContext(ctx).Error("failed to start retroactive re-screen",
+			logger.StringField("requested_by", subject),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "failed to start retroactive re-screen")
+	}
+
+	h.log.WithContext(ctx).Info("retroactive re-screen requested",
+		logger.StringField("run_id", run.ID.String()),
+		logger.StringField("requested_by", subject),
+	)
+
+	return c.JSON(http.StatusAccepted, run)
+}
+
+// GetRetroactiveRescreen returns the progress of the current or most recent
+// retroactive re-screen on this instance
+func (h *AdminHandler) GetRetroactiveRescreen(c echo.Context) error {
+	run := h.rescreens.Status()
+	if run == nil {
+		return errorResponse(c, http.StatusNotFound, "no retroactive re-screen has run")
+	}
+	return c.JSON(http.StatusOK, run)
+}
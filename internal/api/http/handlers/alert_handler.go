@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// AlertService interface for alert review operations
+type AlertService interface {
+	GetAlert(ctx context.Context, id uuid.UUID) (*domain.AMLAlert, error)
+	ReviewAlert(ctx context.Context, id uuid.UUID, req *domain.ReviewAlertRequest) (*domain.AMLAlert, error)
+	DismissAlert(ctx context.Context, id uuid.UUID, req *domain.DismissAlertRequest) (*domain.AMLAlert, error)
+	EscalateAlert(ctx context.Context, id uuid.UUID, req *domain.EscalateAlertRequest) (*domain.EscalateAlertResponse, error)
+}
+
+// AlertHandler serves alert endpoints
+type AlertHandler struct {
+	alerts AlertService
+	log    *logger.Logger
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alerts AlertService, log *logger.Logger) *AlertHandler {
+	return &AlertHandler{
+		alerts: alerts,
+		log:    log.Named("alert_handler"),
+	}
+}
+
+// Register mounts the alert routes on the given group
+func (h *AlertHandler) Register(g *echo.Group) {
+	g.GET("/alerts/:id", h.GetAlert)
+	g.POST("/alerts/:id/review", h.ReviewAlert)
+	g.POST("/alerts/:id/dismiss", h.DismissAlert)
+	g.POST("/alerts/:id/escalate", h.EscalateAlert)
+}
+
+// GetAlert returns an alert by ID
+func (h *AlertHandler) GetAlert(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid alert id")
+	}
+
+	alert, err := h.alerts.GetAlert(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "alert not found")
+		}
+		h.log.Error("failed to get alert", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to get alert")
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// ReviewAlert marks an alert as under review
+func (h *AlertHandler) ReviewAlert(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid alert id")
+	}
+
+	var req domain.ReviewAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if req.ReviewedBy == uuid.Nil {
+		return errorResponse(c, http.StatusBadRequest, "reviewed_by is required")
+	}
+
+	alert, err := h.alerts.ReviewAlert(c.Request().Context(), id, &req)
+	if err != nil {
+		return h.transitionError(c, err, "review")
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// DismissAlert dismisses an alert and the occurrences correlated into it
+func (h *AlertHandler) DismissAlert(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid alert id")
+	}
+
+	var req domain.DismissAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if req.ReviewedBy == uuid.Nil {
+		return errorResponse(c, http.StatusBadRequest, "reviewed_by is required")
+	}
+
+	alert, err := h.alerts.DismissAlert(c.Request().Context(), id, &req)
+	if err != nil {
+		return h.transitionError(c, err, "dismiss")
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// EscalateAlert escalates an alert, opening a linked investigation when
+// create_investigation is set
+func (h *AlertHandler) EscalateAlert(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid alert id")
+	}
+
+	var req domain.EscalateAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if req.ReviewedBy == uuid.Nil {
+		return errorResponse(c, http.StatusBadRequest, "reviewed_by is required")
+	}
+
+	resp, err := h.alerts.EscalateAlert(c.Request().Context(), id, &req)
+	if err != nil {
+		return h.transitionError(c, err, "escalate")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// transitionError maps an alert lifecycle error to a response
+func (h *AlertHandler) transitionError(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "alert not found")
+	case errors.Is(err, domain.ErrValidation):
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		return errorResponse(c, http.StatusConflict, err.Error())
+	}
+	h.log.Error("failed to "+action+" alert", logger.ErrorField(err))
+	return failureResponse(c, err, "failed to "+action+" alert")
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// AnalystRoster maintains the analysts eligible for auto-assignment
+type AnalystRoster interface {
+	List(ctx context.Context) ([]*domain.Analyst, error)
+	Register(ctx context.Context, a *domain.Analyst) error
+	Deactivate(ctx context.Context, id uuid.UUID) error
+	SetDigestEnabled(ctx context.Context, id uuid.UUID, enabled bool) error
+}
+
+// AnalystHandler serves the analyst roster endpoints
+type AnalystHandler struct {
+	roster AnalystRoster
+	log    *logger.Logger
+}
+
+// NewAnalystHandler creates a new analyst handler
+func NewAnalystHandler(roster AnalystRoster, log *logger.Logger) *AnalystHandler {
+	return &AnalystHandler{
+		roster: roster,
+		log:    log.Named("analyst_handler"),
+	}
+}
+
+// Register mounts the analyst roster routes on the given group
+func (h *AnalystHandler) Register(g *echo.Group) {
+	g.GET("/analysts", h.List)
+	g.PUT("/analysts/:id", h.RegisterAnalyst)
+	g.DELETE("/analysts/:id", h.Deregister)
+	g.PUT("/analysts/:id/preferences", h.UpdatePreferences)
+}
+
+// List returns the active roster with each analyst's open caseload
+func (h *AnalystHandler) List(c echo.Context) error {
+	analysts, err := h.roster.List(c.Request().Context())
+	if err != nil {
+		h.log.Error("failed to list analysts", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to list analysts")
+	}
+	if analysts == nil {
+		analysts = []*domain.Analyst{}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"analysts": analysts})
+}
+
+// RegisterAnalyst adds an analyst to the roster or updates their skills and
+// caseload limit
+func (h *AnalystHandler) RegisterAnalyst(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid analyst id")
+	}
+
+	var req domain.RegisterAnalystRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	analyst := req.NewAnalyst(id, time.Now())
+	if err := h.roster.Register(c.Request().Context(), analyst); err != nil {
+		h.log.Error("failed to register analyst",
+			logger.StringField("analyst_id", id.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "failed to register analyst")
+	}
+
+	return c.JSON(http.StatusOK, analyst)
+}
+
+// Deregister takes an analyst off the roster; their open cases stay with them
+func (h *AnalystHandler) Deregister(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid analyst id")
+	}
+
+	if err := h.roster.Deactivate(c.Request().Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "analyst not found")
+		}
+		h.log.Error("failed to deregister analyst",
+			logger.StringField("analyst_id", id.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "failed to deregister analyst")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdatePreferences subscribes an analyst to, or unsubscribes them from, the
+// morning digest
+func (h *AnalystHandler) UpdatePreferences(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid analyst id")
+	}
+
+	var req domain.AnalystPreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	if err := h.roster.SetDigestEnabled(c.Request().Context(), id, *req.DigestEnabled); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "analyst not found")
+		}
+		h.log.Error("failed to update analyst preferences",
+			logger.StringField("analyst_id", id.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "failed to update analyst preferences")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// AuditVerifier checks the integrity of the audit chain
+type AuditVerifier interface {
+	Verify(ctx context.Context) (*audit.VerifyReport, error)
+}
+
+// PIIAccessReporter reports the recorded reads of a data subject's data
+type PIIAccessReporter interface {
+	Report(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.PIIAccessReport, error)
+}
+
+// AuditHandler serves audit log endpoints
+type AuditHandler struct {
+	verifier AuditVerifier
+	accesses PIIAccessReporter
+	log      *logger.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(verifier AuditVerifier, accesses PIIAccessReporter, log *logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		verifier: verifier,
+		accesses: accesses,
+		log:      log.Named("audit_handler"),
+	}
+}
+
+// Register mounts the audit routes on the given group. The access report
+// is mounted separately, behind the admin role.
+func (h *AuditHandler) Register(g *echo.Group) {
+	g.GET("/audit/verify", h.Verify)
+}
+
+// Verify walks the audit chain and reports the first broken link, if any
+func (h *AuditHandler) Verify(c echo.Context) error {
+	report, err := h.verifier.Verify(c.Request().Context())
+	if err != nil {
+		h.log.Error("failed to verify audit chain", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to verify audit chain")
+	}
+
+	if !report.Valid {
+		h.log.Error("audit chain verification failed",
+			logger.StringField("event_id", report.FirstBroken.EventID.String()),
+			logger.StringField("reason", report.FirstBroken.Reason),
+		)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// AccessReport lists who read a data subject's filings and investigations,
+// when and why, for answering a data subject access request
+//
+// Query parameters: user_id (required), from, to (RFC 3339 or YYYY-MM-DD;
+// to defaults to now)
+func (h *AuditHandler) AccessReport(c echo.Context) error {
+	userID, err := uuid.Parse(c.QueryParam("user_id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "user_id is required")
+	}
+	from, err := parseExportTime(c.QueryParam("from"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, errInvalidParam("from").Error())
+	}
+	to, err := parseExportTime(c.QueryParam("to"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, errInvalidParam("to").Error())
+	}
+
+	report, err := h.accesses.Report(c.Request().Context(), userID, from, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		h.log.Error("failed to build pii access report", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to build access report")
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
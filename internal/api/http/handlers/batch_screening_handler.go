@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// batchHitsRoute names the CSV route so progress responses can link to it
+const batchHitsRoute = "batchHits"
+
+// BatchScreener interface for batch screening jobs
+type BatchScreener interface {
+	Enqueue(ctx context.Context, req *domain.BatchScreeningRequest) (*domain.BatchScreeningJob, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*domain.BatchScreeningJob, error)
+	ListHits(ctx context.Context, id uuid.UUID) ([]domain.BatchResult, error)
+}
+
+// BatchScreeningHandler serves batch screening endpoints
+type BatchScreeningHandler struct {
+	batches BatchScreener
+	log     *logger.Logger
+}
+
+// NewBatchScreeningHandler creates a new batch screening handler
+func NewBatchScreeningHandler(batches BatchScreener, log *logger.Logger) *BatchScreeningHandler {
+	return &BatchScreeningHandler{
+		batches: batches,
+		log:     log.Named("batch_screening_handler"),
+	}
+}
+
+// Register mounts the batch screening routes on the given group
+func (h *BatchScreeningHandler) Register(g *echo.Group) {
+	g.POST("/screening/batch", h.CreateBatch)
+	g.GET("/screening/batch/:job_id", h.GetBatch)
+	g.GET("/screening/batch/:job_id/hits.csv", h.DownloadHits).Name = batchHitsRoute
+}
+
+// CreateBatch enqueues a batch screening job
+func (h *BatchScreeningHandler) CreateBatch(c echo.Context) error {
+	var req domain.BatchScreeningRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	job, err := h.batches.Enqueue(c.Request().Context(), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		h.log.Error("failed to enqueue batch screening", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to enqueue batch screening")
+	}
+
+	return c.JSON(http.StatusAccepted, h.toResponse(c, job))
+}
+
+// GetBatch returns the progress of a batch screening job
+func (h *BatchScreeningHandler) GetBatch(c echo.Context) error {
+	job, err := h.loadJob(c)
+	if job == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, h.toResponse(c, job))
+}
+
+// DownloadHits streams the job's list matches as CSV
+func (h *BatchScreeningHandler) DownloadHits(c echo.Context) error {
+	job, err := h.loadJob(c)
+	if job == nil {
+		return err
+	}
+
+	hits, err := h.batches.ListHits(c.Request().Context(), job.ID)
+	if err != nil {
+		h.log.Error("failed to list batch hits", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to list batch hits")
+	}
+
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/csv")
+	resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="batch-%s-hits.csv"`, job.ID))
+	resp.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(resp)
+	_ = w.Write([]string{
+		"seq", "name", "user_id",
+		"ofac_matched", "ofac_score", "sdn_name", "ofac_program",
+		"pep_matched", "pep_score", "pep_name", "pep_position", "pep_country",
+	})
+	for _, hit := range hits {
+		_ = w.Write(batchHitRecord(hit))
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+// loadJob parses :job_id and loads the job. On failure it writes the error
+// response and returns a nil job.
+func (h *BatchScreeningHandler) loadJob(c echo.Context) (*domain.BatchScreeningJob, error) {
+	id, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		return nil, errorResponse(c, http.StatusBadRequest, "invalid job id")
+	}
+
+	job, err := h.batches.GetJob(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, errorResponse(c, http.StatusNotFound, "batch job not found")
+		}
+		h.log.Error("failed to get batch job", logger.ErrorField(err))
+		return nil, failureResponse(c, err, "failed to get batch job")
+	}
+
+	return job, nil
+}
+
+func (h *BatchScreeningHandler) toResponse(c echo.Context, job *domain.BatchScreeningJob) *domain.BatchJobResponse {
+	return &domain.BatchJobResponse{
+		BatchScreeningJob: job,
+		Progress:          job.Progress(),
+		HitsURL:           c.Echo().Reverse(batchHitsRoute, job.ID),
+	}
+}
+
+func batchHitRecord(hit domain.BatchResult) []string {
+	record := make([]string, 12)
+	record[0] = strconv.Itoa(hit.Seq)
+	record[1] = hit.Name
+	if hit.UserID != nil {
+		record[2] = hit.UserID.String()
+	}
+	if m := hit.OFACMatch; m != nil {
+		record[3] = strconv.FormatBool(m.Matched)
+		record[4] = strconv.FormatFloat(m.MatchScore, 'f', 3, 64)
+		record[5] = m.SDNName
+		record[6] = m.Program
+	}
+	if m := hit.PEPMatch; m != nil {
+		record[7] = strconv.FormatBool(m.Matched)
+		record[8] = strconv.FormatFloat(m.MatchScore, 'f', 3, 64)
+		record[9] = m.PEPName
+		record[10] = m.PEPPosition
+		record[11] = m.PEPCountry
+	}
+	return record
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// CounterpartyReader reads what screening has learnt about external accounts
+type CounterpartyReader interface {
+	Get(ctx context.Context, account string) (*domain.CounterpartyReputationResponse, error)
+}
+
+// CounterpartyHandler serves counterparty reputations to investigators
+type CounterpartyHandler struct {
+	counterparties CounterpartyReader
+	log            *logger.Logger
+}
+
+// NewCounterpartyHandler creates a new counterparty handler
+func NewCounterpartyHandler(counterparties CounterpartyReader, log *logger.Logger) *CounterpartyHandler {
+	return &CounterpartyHandler{
+		counterparties: counterparties,
+		log:            log.Named("counterparty_handler"),
+	}
+}
+
+// Register mounts the counterparty route on the given group
+func (h *CounterpartyHandler) Register(g *echo.Group) {
+	g.GET("/counterparties/:account/reputation", h.GetReputation)
+}
+
+// GetReputation returns an account number or IBAN's reputation at each
+// bank it has been seen with, decayed to now
+func (h *CounterpartyHandler) GetReputation(c echo.Context) error {
+	rep, err := h.counterparties.Get(c.Request().Context(), c.Param("account"))
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		h.log.Error("failed to get counterparty reputation", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to get counterparty reputation")
+	}
+
+	return c.JSON(http.StatusOK, rep)
+}
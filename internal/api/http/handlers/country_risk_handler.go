@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// CountryRiskManager reads and saves versions of the country risk table
+type CountryRiskManager interface {
+	Current() *domain.CountryRiskTable
+	Get(ctx context.Context, version int) (*domain.CountryRiskTable, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.CountryRiskTable, error)
+	Update(ctx context.Context, req *domain.UpdateCountryRiskTableRequest) (*domain.CountryRiskTable, error)
+}
+
+// CountryRiskHandler serves the country risk table endpoints. It is
+// mounted on the admin group, which applies authentication and rate
+// limiting.
+type CountryRiskHandler struct {
+	countryRisk CountryRiskManager
+	log         *logger.Logger
+}
+
+// NewCountryRiskHandler creates a new country risk handler
+func NewCountryRiskHandler(countryRisk CountryRiskManager, log *logger.Logger) *CountryRiskHandler {
+	return &CountryRiskHandler{
+		countryRisk: countryRisk,
+		log:         log.Named("country_risk_handler"),
+	}
+}
+
+// Register mounts the country risk routes on the given group
+func (h *CountryRiskHandler) Register(g *echo.Group) {
+	g.GET("/country-risk", h.Current)
+	g.PUT("/country-risk", h.Update)
+	g.GET("/country-risk/versions", h.List)
+	g.GET("/country-risk/versions/:version", h.Get)
+}
+
+// Current returns the country risk table in force on this instance
+func (h *CountryRiskHandler) Current(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.countryRisk.Current())
+}
+
+// Update saves a new version of the country risk table and puts it in
+// force
+func (h *CountryRiskHandler) Update(c echo.Context) error {
+	var req domain.UpdateCountryRiskTableRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	table, err := h.countryRisk.Update(c.Request().Context(), &req)
+	if err != nil {
+		return h.failure(c, err, "save country risk table")
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+// List returns saved versions of the country risk table, newest first
+func (h *CountryRiskHandler) List(c echo.Context) error {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	tables, err := h.countryRisk.List(c.Request().Context(), limit, offset)
+	if err != nil {
+		return h.failure(c, err, "list country risk tables")
+	}
+
+	return c.JSON(http.StatusOK, &domain.CountryRiskTableListResponse{
+		Tables: tables,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// Get returns a version of the country risk table, such as the one a
+// screening result records; version 0 is the configured table
+func (h *CountryRiskHandler) Get(c echo.Context) error {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 0 {
+		return errorResponse(c, http.StatusBadRequest, "invalid country risk table version")
+	}
+
+	table, err := h.countryRisk.Get(c.Request().Context(), version)
+	if err != nil {
+		return h.failure(c, err, "get country risk table")
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+func (h *CountryRiskHandler) failure(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "country risk table version not found")
+	case errors.Is(err, domain.ErrConflict):
+		return errorResponse(c, http.StatusConflict, err.Error())
+	}
+	h.log.Error("failed to "+action, logger.ErrorField(err))
+	return failureResponse(c, err, "failed to "+action)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// EntityGraphBuilder builds the linked-entity graph for an investigation
+type EntityGraphBuilder interface {
+	Build(ctx context.Context, investigationID uuid.UUID) (*domain.EntityGraph, error)
+}
+
+// EntityGraphHandler serves investigation entity graphs
+type EntityGraphHandler struct {
+	graphs EntityGraphBuilder
+	log    *logger.Logger
+}
+
+// NewEntityGraphHandler creates a new entity graph handler
+func NewEntityGraphHandler(graphs EntityGraphBuilder, log *logger.Logger) *EntityGraphHandler {
+	return &EntityGraphHandler{
+		graphs: graphs,
+		log:    log.Named("entity_graph_handler"),
+	}
+}
+
+// Register mounts the entity graph route on the given group
+func (h *EntityGraphHandler) Register(g *echo.Group) {
+	g.GET("/investigations/:id/graph", h.GetGraph)
+}
+
+// GetGraph returns the subject's counterparties, other users of those
+// counterparties and the cases on them as nodes and edges
+func (h *EntityGraphHandler) GetGraph(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+
+	graph, err := h.graphs.Build(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "investigation not found")
+		}
+		h.log.Error("failed to build entity graph",
+			logger.StringField("investigation_id", id.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "failed to build entity graph")
+	}
+
+	return c.JSON(http.StatusOK, graph)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/apperr"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// EvidenceHeaderSHA256 carries the verified SHA-256 of a downloaded evidence file
+const EvidenceHeaderSHA256 = "X-Content-SHA256"
+
+// EvidenceManager attaches, reads and withdraws investigation evidence
+type EvidenceManager interface {
+	List(ctx context.Context, investigationID uuid.UUID) ([]domain.Evidence, error)
+	Add(ctx context.Context, investigationID uuid.UUID, req *domain.AddEvidenceRequest, data []byte) (*domain.Evidence, error)
+	Open(ctx context.Context, investigationID, evidenceID uuid.UUID) (*domain.Evidence, []byte, error)
+	Delete(ctx context.Context, investigationID, evidenceID uuid.UUID, req *domain.DeleteEvidenceRequest) (*domain.Evidence, error)
+}
+
+// EvidenceHandler serves investigation evidence endpoints
+type EvidenceHandler struct {
+	evidence EvidenceManager
+	maxSize  int64
+	log      *logger.Logger
+}
+
+// NewEvidenceHandler creates a new evidence handler. Uploads larger than
+// maxSize bytes are rejected.
+func NewEvidenceHandler(evidence EvidenceManager, maxSize int64, log *logger.Logger) *EvidenceHandler {
+	return &EvidenceHandler{
+		evidence: evidence,
+		maxSize:  maxSize,
+		log:      log.Named("evidence_handler"),
+	}
+}
+
+// Register mounts the evidence routes on the given group
+func (h *EvidenceHandler) Register(g *echo.Group) {
+	g.GET("/investigations/:id/evidence", h.List)
+	g.POST("/investigations/:id/evidence", h.Upload)
+	g.GET("/investigations/:id/evidence/:evidence_id/file", h.Download)
+	g.DELETE("/investigations/:id/evidence/:evidence_id", h.Delete)
+}
+
+// List returns the investigation's evidence records, including withdrawn ones
+func (h *EvidenceHandler) List(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+
+	evidence, err := h.evidence.List(c.Request().Context(), id)
+	if err != nil {
+		return h.fail(c, err, "list evidence")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"evidence": evidence})
+}
+
+// Upload attaches a file to an investigation
+//
+// Multipart form fields: file, type, description, added_by
+func (h *EvidenceHandler) Upload(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, h.maxSize)
+	if err := req.ParseMultipartForm(h.maxSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return errorResponse(c, http.StatusRequestEntityTooLarge, "evidence file is too large")
+		}
+		return errorResponse(c, http.StatusBadRequest, "invalid multipart form")
+	}
+
+	var body domain.AddEvidenceRequest
+	if err := c.Bind(&body); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&body); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := body.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "file is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid file")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "failed to read file")
+	}
+
+	body.FileName = header.Filename
+	body.ContentType = header.Header.Get(echo.HeaderContentType)
+	if body.ContentType == "" {
+		body.ContentType = http.DetectContentType(data)
+	}
+
+	ev, err := h.evidence.Add(req.Context(), id, &body, data)
+	if err != nil {
+		return h.fail(c, err, "add evidence")
+	}
+
+	return c.JSON(http.StatusCreated, ev)
+}
+
+// Download streams an evidence file back after verifying its hash
+func (h *EvidenceHandler) Download(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+	evidenceID, err := uuid.Parse(c.Param("evidence_id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid evidence id")
+	}
+
+	ev, data, err := h.evidence.Open(c.Request().Context(), id, evidenceID)
+	if err != nil {
+		return h.fail(c, err, "read evidence")
+	}
+
+	c.Response().Header().Set(EvidenceHeaderSHA256, ev.SHA256)
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": ev.FileName}))
+	return c.Blob(http.StatusOK, ev.ContentType, data)
+}
+
+// Delete withdraws evidence; the record, file and hash are retained
+func (h *EvidenceHandler) Delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+	evidenceID, err := uuid.Parse(c.Param("evidence_id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid evidence id")
+	}
+
+	var req domain.DeleteEvidenceRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ev, err := h.evidence.Delete(c.Request().Context(), id, evidenceID, &req)
+	if err != nil {
+		return h.fail(c, err, "delete evidence")
+	}
+
+	return c.JSON(http.StatusOK, ev)
+}
+
+// fail maps an evidence service error to a response
+func (h *EvidenceHandler) fail(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "investigation or evidence not found")
+	case errors.Is(err, domain.ErrConflict):
+		return errorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrIntegrity):
+		return writeError(c, http.StatusInternalServerError, apperr.CodeIntegrity, err.Error(), nil)
+	}
+	h.log.Error("failed to "+action,
+		logger.StringField("investigation_id", c.Param("id")),
+		logger.ErrorField(err),
+	)
+	return failureResponse(c, err, "failed to "+action)
+}
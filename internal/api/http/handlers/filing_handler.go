@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/compliance/fincen"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// FilingService interface for regulatory filing operations
+type FilingService interface {
+	CreateSAR(ctx context.Context, req *domain.CreateSARRequest) (*domain.RegulatoryFiling, error)
+	GetFiling(ctx context.Context, id uuid.UUID) (*domain.RegulatoryFiling, error)
+	ExportFinCEN(ctx context.Context, id uuid.UUID) ([]byte, error)
+	Transition(ctx context.Context, id uuid.UUID, req *domain.FilingTransitionRequest) (*domain.FilingTransitionResponse, error)
+	ListTransitions(ctx context.Context, id uuid.UUID) ([]domain.FilingTransition, error)
+	DraftNarrative(ctx context.Context, id uuid.UUID, req *domain.DraftNarrativeRequest) (*domain.RegulatoryFiling, error)
+	Amend(ctx context.Context, id uuid.UUID, req *domain.AmendFilingRequest) (*domain.RegulatoryFiling, error)
+	History(ctx context.Context, id uuid.UUID) ([]*domain.RegulatoryFiling, error)
+}
+
+// FilingHandler serves regulatory filing endpoints
+type FilingHandler struct {
+	filings      FilingService
+	authenticate echo.MiddlewareFunc
+	log          *logger.Logger
+}
+
+// NewFilingHandler creates a new filing handler. authenticate guards the
+// routes that act on a filing as a named actor, so maker-checker rests on a
+// verified identity, and must put the caller under
+// amlmiddleware.SubjectContextKey.
+func NewFilingHandler(filings FilingService, authenticate echo.MiddlewareFunc, log *logger.Logger) *FilingHandler {
+	return &FilingHandler{
+		filings:      filings,
+		authenticate: authenticate,
+		log:          log.Named("filing_handler"),
+	}
+}
+
+// Register mounts the filing routes on the given group
+func (h *FilingHandler) Register(g *echo.Group) {
+	g.POST("/filings/sar", h.CreateSAR, h.authenticate)
+	g.GET("/filings/:id", h.GetFiling)
+	g.GET("/filings/:id/fincen.xml", h.ExportFinCEN)
+	g.POST("/filings/:id/transition", h.Transition, h.authenticate)
+	g.GET("/filings/:id/transitions", h.ListTransitions)
+	g.POST("/filings/:id/narrative/draft", h.DraftNarrative, h.authenticate)
+	g.POST("/filings/:id/amend", h.Amend, h.authenticate)
+	g.GET("/filings/:id/history", h.History)
+}
+
+// CreateSAR drafts a new SAR prepared by the bearer token's subject
+func (h *FilingHandler) CreateSAR(c echo.Context) error {
+	var req domain.CreateSARRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := tokenActor(c, &req.PreparedBy); err != nil {
+		return errorResponse(c, http.StatusForbidden, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	filing, err := h.filings.CreateSAR(c.Request().Context(), &req)
+	if err != nil {
+		var ferr domain.FieldErrors
+		switch {
+		case errors.As(err, &ferr):
+			return detailedErrorResponse(c, http.StatusBadRequest, "invalid sar", ferr)
+		case errors.Is(err, domain.ErrValidation):
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		h.log.Error("failed to create sar", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to create sar")
+	}
+
+	filing.RedactPII()
+	return c.JSON(http.StatusCreated, filing)
+}
+
+// GetFiling returns a filing by ID
+func (h *FilingHandler) GetFiling(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid filing id")
+	}
+
+	filing, err := h.filings.GetFiling(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "filing not found")
+		}
+		h.log.Error("failed to get filing", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to get filing")
+	}
+
+	filing.RedactPII()
+	return c.JSON(http.StatusOK, filing)
+}
+
+// Transition moves a filing to a new status on behalf of the bearer token's
+// subject, who cannot be the preparer when the filing leaves REVIEW
+func (h *FilingHandler) Transition(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid filing id")
+	}
+
+	var req domain.FilingTransitionRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := tokenActor(c, &req.ActorID); err != nil {
+		return errorResponse(c, http.StatusForbidden, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if req.ToStatus == "" || req.ActorID == uuid.Nil {
+		return errorResponse(c, http.StatusBadRequest, "to_status and actor_id are required")
+	}
+
+	resp, err := h.filings.Transition(c.Request().Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errorResponse(c, http.StatusNotFound, "filing not found")
+		case errors.Is(err, domain.ErrValidation):
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrForbidden):
+			return errorResponse(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, domain.ErrConflict):
+			return errorResponse(c, http.StatusConflict, err.Error())
+		}
+		h.log.Error("failed to transition filing", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to transition filing")
+	}
+
+	resp.Filing.RedactPII()
+	if resp.Superseded != nil {
+		resp.Superseded.RedactPII()
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DraftNarrative generates and stores a first-draft SAR narrative on behalf
+// of the bearer token's subject
+func (h *FilingHandler) DraftNarrative(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid filing id")
+	}
+
+	var req domain.DraftNarrativeRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := tokenActor(c, &req.ActorID); err != nil {
+		return errorResponse(c, http.StatusForbidden, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if req.ActorID == uuid.Nil {
+		return errorResponse(c, http.StatusBadRequest, "actor_id is required")
+	}
+
+	filing, err := h.filings.DraftNarrative(c.Request().Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errorResponse(c, http.StatusNotFound, "filing not found")
+		case errors.Is(err, domain.ErrValidation):
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrConflict):
+			return errorResponse(c, http.StatusConflict, err.Error())
+		}
+		h.log.Error("failed to draft narrative", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to draft narrative")
+	}
+
+	filing.RedactPII()
+	return c.JSON(http.StatusOK, filing)
+}
+
+// Amend opens a draft amendment of a filed report, prepared by the bearer
+// token's subject
+func (h *FilingHandler) Amend(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid filing id")
+	}
+
+	var req domain.AmendFilingRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := tokenActor(c, &req.ActorID); err != nil {
+		return errorResponse(c, http.StatusForbidden, err.Error())
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	amendment, err := h.filings.Amend(c.Request().Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errorResponse(c, http.StatusNotFound, "filing not found")
+		case errors.Is(err, domain.ErrConflict):
+			return errorResponse(c, http.StatusConflict, err.Error())
+		}
+		h.log.Error("failed to amend filing", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to amend filing")
+	}
+
+	amendment.RedactPII()
+	return c.JSON(http.StatusCreated, amendment)
+}
+
+// History returns the amendment chain containing a filing, from the
+// original report to its latest amendment
+func (h *FilingHandler) History(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid filing id")
+	}
+
+	chain, err := h.filings.History(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "filing not found")
+		}
+		h.log.Error("failed to get filing history", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to get filing history")
+	}
+
+	for _, f := range chain {
+		f.RedactPII()
+	}
+	return c.JSON(http.StatusOK, chain)
+}
+
+// ListTransitions returns a filing's status history
+func (h *FilingHandler) ListTransitions(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid filing id")
+	}
+
+	transitions, err := h.filings.ListTransitions(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "filing not found")
+		}
+		h.log.Error("failed to list filing transitions", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to list filing transitions")
+	}
+
+	return c.JSON(http.StatusOK, transitions)
+}
+
+// ExportFinCEN returns an approved SAR as FinCEN BSA E-Filing batch XML
+func (h *FilingHandler) ExportFinCEN(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid filing id")
+	}
+
+	out, err := h.filings.ExportFinCEN(c.Request().Context(), id)
+	if err != nil {
+		var verr *fincen.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			return detailedErrorResponse(c, http.StatusUnprocessableEntity, "filing cannot be exported", verr)
+		case errors.Is(err, domain.ErrNotFound):
+			return errorResponse(c, http.StatusNotFound, "filing not found")
+		}
+		h.log.Error("failed to export filing", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to export filing")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="sar-%s.xml"`, id))
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, out)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	amlmiddleware "github.com/banking/aml-service/internal/api/http/middleware"
+	"github.com/banking/aml-service/internal/api/http/validation"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+const testSecret = "test-secret"
+
+// reviewFilings holds filings and applies transitions with the domain rules,
+// as FilingService does
+type reviewFilings struct {
+	FilingService
+	filings map[uuid.UUID]*domain.RegulatoryFiling
+}
+
+func (s *reviewFilings) Transition(_ context.Context, id uuid.UUID, req *domain.FilingTransitionRequest) (*domain.FilingTransitionResponse, error) {
+	filing, ok := s.filings[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if err := filing.ValidateTransition(req); err != nil {
+		return nil, err
+	}
+	transition := &domain.FilingTransition{FilingID: id, FromStatus: filing.Status, ToStatus: req.ToStatus, ActorID: req.ActorID}
+	filing.Status = req.ToStatus
+	return &domain.FilingTransitionResponse{Filing: filing, Transition: transition}, nil
+}
+
+func token(t *testing.T, subject string) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{Subject: subject}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestTransitionTakesCheckerFromToken(t *testing.T) {
+	preparer, reviewer := uuid.New(), uuid.New()
+	filing := &domain.RegulatoryFiling{ID: uuid.New(), Status: domain.FilingStatusReview, PreparedBy: preparer}
+	filings := &reviewFilings{filings: map[uuid.UUID]*domain.RegulatoryFiling{filing.ID: filing}}
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(logger.NewNop())
+	e.Validator = validation.New()
+	NewFilingHandler(filings, amlmiddleware.RequireToken(testSecret), logger.NewNop()).Register(e.Group("/api/v1"))
+
+	transition := func(bearer, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/filings/"+filing.ID.String()+"/transition", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if bearer != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+bearer)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	approve := `{"to_status":"` + string(domain.FilingStatusApproved) + `"}`
+	approveAs := func(actor uuid.UUID) string {
+		return `{"to_status":"` + string(domain.FilingStatusApproved) + `","actor_id":"` + actor.String() + `"}`
+	}
+
+	tests := []struct {
+		name   string
+		bearer string
+		body   string
+		status int
+	}{
+		{"no token", "", approveAs(reviewer), http.StatusUnauthorized},
+		{"preparer's token", token(t, preparer.String()), approve, http.StatusForbidden},
+		{"preparer's token naming the reviewer", token(t, preparer.String()), approveAs(reviewer), http.StatusForbidden},
+		{"preparer's token naming another actor", token(t, preparer.String()), approveAs(uuid.New()), http.StatusForbidden},
+		{"token subject not an actor id", token(t, "service-account"), approve, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := transition(tt.bearer, tt.body); rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if filing.Status != domain.FilingStatusReview {
+				t.Fatalf("filing moved to %s", filing.Status)
+			}
+		})
+	}
+
+	if rec := transition(token(t, reviewer.String()), approve); rec.Code != http.StatusOK {
+		t.Fatalf("reviewer's approval status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if filing.Status != domain.FilingStatusApproved {
+		t.Fatalf("filing status = %s after the reviewer's approval, want %s", filing.Status, domain.FilingStatusApproved)
+	}
+}
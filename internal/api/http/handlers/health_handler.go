@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// dependencyCheckTimeout bounds each readiness ping so one hung dependency
+// cannot stall the probe
+const dependencyCheckTimeout = time.Second
+
+// Readiness states reported by the readiness probe
+const (
+	ReadinessOK          = "ok"
+	ReadinessDegraded    = "degraded"
+	ReadinessUnavailable = "unavailable"
+)
+
+// DependencyCheck pings one dependency. A failing critical dependency makes
+// the service unready; any other failure only marks it degraded.
+type DependencyCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// IndexReporter reports the state of a sanctions or PEP list index
+type IndexReporter interface {
+	IndexStatus() screening.IndexStatus
+}
+
+// WarmupReporter reports whether startup warm-up has completed
+type WarmupReporter interface {
+	WarmedUp() bool
+}
+
+// DependencyStatus is the outcome of one dependency check
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Critical  bool   `json:"critical"`
+	Healthy   bool   `json:"healthy"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ListIndexHealth is a list index's state and staleness
+type ListIndexHealth struct {
+	screening.IndexStatus
+	AgeSeconds    int64 `json:"age_seconds"`
+	MaxAgeSeconds int64 `json:"max_age_seconds"`
+	Stale         bool  `json:"stale"`
+}
+
+// ScreeningHealth reports the screening list indexes
+type ScreeningHealth struct {
+	OFAC ListIndexHealth `json:"ofac"`
+	PEP  ListIndexHealth `json:"pep"`
+}
+
+// ReadinessResponse is the body of the readiness probe
+type ReadinessResponse struct {
+	Status       string             `json:"status"` // ok, degraded or unavailable
+	Ready        bool               `json:"ready"`
+	WarmedUp     bool               `json:"warmed_up"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	Screening    ScreeningHealth    `json:"screening"`
+}
+
+// HealthHandler serves liveness, readiness and screening health probes
+type HealthHandler struct {
+	checks []DependencyCheck
+	ofac   IndexReporter
+	pep    IndexReporter
+	warmup WarmupReporter
+	cfg    *config.ScreeningConfig
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(ofac, pep IndexReporter, warmup WarmupReporter, cfg *config.ScreeningConfig, checks ...DependencyCheck) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+		ofac:   ofac,
+		pep:    pep,
+		warmup: warmup,
+		cfg:    cfg,
+	}
+}
+
+// Register mounts the health routes on the given group
+func (h *HealthHandler) Register(g *echo.Group) {
+	g.GET("", h.Live)
+	g.GET("/live", h.Live)
+	g.GET("/ready", h.Ready)
+	g.GET("/screening", h.Screening)
+}
+
+// Live reports that the process is up; it checks no dependencies
+func (h *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Ready pings every dependency and reports whether the service can take
+// traffic. It fails with 503 until startup warm-up completes, when a
+// critical dependency is down or when the OFAC index has never loaded,
+// since screening without a sanctions list must not receive traffic.
+// Non-critical failures and a stale or missing PEP index report degraded
+// but stay ready.
+func (h *HealthHandler) Ready(c echo.Context) error {
+	deps := h.checkDependencies(c.Request().Context())
+	screeningHealth := h.screeningHealth()
+	warmedUp := h.warmup.WarmedUp()
+
+	ready := warmedUp && screeningHealth.OFAC.Loaded
+	degraded := screeningHealth.OFAC.Stale || screeningHealth.PEP.Stale
+	for _, d := range deps {
+		if !d.Healthy {
+			ready = ready && !d.Critical
+			degraded = true
+		}
+	}
+
+	status, state := http.StatusOK, ReadinessOK
+	switch {
+	case !ready:
+		status, state = http.StatusServiceUnavailable, ReadinessUnavailable
+	case degraded:
+		state = ReadinessDegraded
+	}
+	return c.JSON(status, &ReadinessResponse{
+		Status:       state,
+		Ready:        ready,
+		WarmedUp:     warmedUp,
+		Dependencies: deps,
+		Screening:    screeningHealth,
+	})
+}
+
+// Screening reports whether the OFAC and PEP indexes are loaded and how
+// stale they are relative to their update intervals
+func (h *HealthHandler) Screening(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.screeningHealth())
+}
+
+// checkDependencies pings every dependency concurrently
+func (h *HealthHandler) checkDependencies(ctx context.Context) []DependencyStatus {
+	statuses := make([]DependencyStatus, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(checkCtx)
+			statuses[i] = DependencyStatus{
+				Name:      check.Name,
+				Critical:  check.Critical,
+				Healthy:   err == nil,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				statuses[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return statuses
+}
+
+func (h *HealthHandler) screeningHealth() ScreeningHealth {
+	now := time.Now()
+	return ScreeningHealth{
+		OFAC: indexHealth(h.ofac.IndexStatus(), h.cfg.OFACUpdateInterval, now),
+		PEP:  indexHealth(h.pep.IndexStatus(), h.cfg.PEPUpdateInterval, now),
+	}
+}
+
+// indexHealth marks an index stale once its list is older than maxAge. An
+// index that never loaded is always stale.
+func indexHealth(status screening.IndexStatus, maxAge time.Duration, now time.Time) ListIndexHealth {
+	age := status.Age(now)
+	return ListIndexHealth{
+		IndexStatus:   status,
+		AgeSeconds:    int64(age.Seconds()),
+		MaxAgeSeconds: int64(maxAge.Seconds()),
+		Stale:         !status.Loaded || age > maxAge,
+	}
+}
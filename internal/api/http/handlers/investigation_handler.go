@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// InvestigationRepository interface for investigation queries
+type InvestigationRepository interface {
+	List(ctx context.Context, filter domain.InvestigationFilter) ([]*domain.Investigation, int, error)
+}
+
+// InvestigationOpener opens new investigations
+type InvestigationOpener interface {
+	Create(ctx context.Context, req *domain.CreateInvestigationRequest) (*domain.Investigation, error)
+}
+
+// InvestigationHistory serves an investigation's timeline and notes
+type InvestigationHistory interface {
+	Timeline(ctx context.Context, id uuid.UUID) ([]*domain.InvestigationTimeline, error)
+	Notes(ctx context.Context, id uuid.UUID, includeInternal bool) ([]*domain.InvestigationNote, error)
+	AddNote(ctx context.Context, id uuid.UUID, req *domain.AddNoteRequest) (*domain.InvestigationNote, error)
+}
+
+// InvestigationHandler serves investigation endpoints
+type InvestigationHandler struct {
+	repo    InvestigationRepository
+	opener  InvestigationOpener
+	history InvestigationHistory
+	log     *logger.Logger
+}
+
+// NewInvestigationHandler creates a new investigation handler
+func NewInvestigationHandler(repo InvestigationRepository, opener InvestigationOpener, history InvestigationHistory, log *logger.Logger) *InvestigationHandler {
+	return &InvestigationHandler{
+		repo:    repo,
+		opener:  opener,
+		history: history,
+		log:     log.Named("investigation_handler"),
+	}
+}
+
+// Register mounts the investigation routes on the given group
+func (h *InvestigationHandler) Register(g *echo.Group) {
+	g.POST("/investigations", h.CreateInvestigation)
+	g.GET("/investigations", h.ListInvestigations)
+	g.GET("/investigations/:id/timeline", h.Timeline)
+	g.GET("/investigations/:id/notes", h.ListNotes)
+	g.POST("/investigations/:id/notes", h.AddNote)
+}
+
+// CreateInvestigation opens an investigation
+func (h *InvestigationHandler) CreateInvestigation(c echo.Context) error {
+	var req domain.CreateInvestigationRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	inv, err := h.opener.Create(c.Request().Context(), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		}
+		h.log.Error("failed to create investigation", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to create investigation")
+	}
+
+	return c.JSON(http.StatusCreated, inv)
+}
+
+// ListInvestigations returns a filtered, paginated list of investigation summaries
+//
+// Query parameters: status, priority, assigned_to, sla_breached, created_from,
+// created_to (RFC 3339), limit (default 50, max 200), offset
+func (h *InvestigationHandler) ListInvestigations(c echo.Context) error {
+	filter, err := parseInvestigationFilter(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	investigations, total, err := h.repo.List(c.Request().Context(), filter)
+	if err != nil {
+		h.log.Error("failed to list investigations", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to list investigations")
+	}
+
+	now := time.Now()
+	summaries := make([]*domain.InvestigationSummary, 0, len(investigations))
+	for _, inv := range investigations {
+		// ToSummary evaluates IsOverdue so a stale sla_breached column is corrected
+		summaries = append(summaries, inv.ToSummary(now))
+	}
+
+	return c.JSON(http.StatusOK, &domain.InvestigationListResponse{
+		Investigations: summaries,
+		Total:          total,
+		Limit:          filter.Limit,
+		Offset:         filter.Offset,
+	})
+}
+
+// Timeline returns the investigation's events in chronological order
+func (h *InvestigationHandler) Timeline(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+
+	events, err := h.history.Timeline(c.Request().Context(), id)
+	if err != nil {
+		return h.fail(c, err, "get investigation timeline")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"events": events})
+}
+
+// ListNotes returns the investigation's notes in chronological order
+//
+// Query parameters: include_internal (default false)
+func (h *InvestigationHandler) ListNotes(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+
+	includeInternal := false
+	if v := c.QueryParam("include_internal"); v != "" {
+		if includeInternal, err = strconv.ParseBool(v); err != nil {
+			return errorResponse(c, http.StatusBadRequest, errInvalidParam("include_internal").Error())
+		}
+	}
+
+	notes, err := h.history.Notes(c.Request().Context(), id, includeInternal)
+	if err != nil {
+		return h.fail(c, err, "list investigation notes")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"notes": notes})
+}
+
+// AddNote adds a note to an open investigation
+func (h *InvestigationHandler) AddNote(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid investigation id")
+	}
+
+	var req domain.AddNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	note, err := h.history.AddNote(c.Request().Context(), id, &req)
+	if err != nil {
+		return h.fail(c, err, "add investigation note")
+	}
+
+	return c.JSON(http.StatusCreated, note)
+}
+
+// fail maps an investigation history error to a response
+func (h *InvestigationHandler) fail(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "investigation not found")
+	case errors.Is(err, domain.ErrConflict):
+		return errorResponse(c, http.StatusConflict, err.Error())
+	}
+	h.log.Error("failed to "+action,
+		logger.StringField("investigation_id", c.Param("id")),
+		logger.ErrorField(err),
+	)
+	return failureResponse(c, err, "failed to "+action)
+}
+
+// parseInvestigationFilter reads list filters from the query string
+func parseInvestigationFilter(c echo.Context) (domain.InvestigationFilter, error) {
+	filter := domain.InvestigationFilter{Limit: defaultPageLimit}
+
+	if v := c.QueryParam("status"); v != "" {
+		status := domain.InvestigationStatus(v)
+		filter.Status = &status
+	}
+	if v := c.QueryParam("priority"); v != "" {
+		priority := domain.InvestigationPriority(v)
+		filter.Priority = &priority
+	}
+	if v := c.QueryParam("assigned_to"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return filter, errInvalidParam("assigned_to")
+		}
+		filter.AssignedTo = &id
+	}
+	if v := c.QueryParam("sla_breached"); v != "" {
+		breached, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errInvalidParam("sla_breached")
+		}
+		filter.SLABreached = &breached
+	}
+	if v := c.QueryParam("created_from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errInvalidParam("created_from")
+		}
+		filter.CreatedFrom = &t
+	}
+	if v := c.QueryParam("created_to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errInvalidParam("created_to")
+		}
+		filter.CreatedTo = &t
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	return filter, nil
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// ListReporter reports a screening list's loaded index and when the cached
+// list was last refreshed
+type ListReporter interface {
+	IndexReporter
+	LastUpdate(ctx context.Context) (time.Time, error)
+}
+
+// ListStatus describes one screening list. The embedded index status is the
+// list results are currently screened against; Version and Hash are the
+// values stamped on those results.
+type ListStatus struct {
+	List   string `json:"list"`
+	Source string `json:"source"`
+	screening.IndexStatus
+
+	// CacheUpdatedAt is the last update of the cached list. ReloadPending
+	// is true when it is newer than the loaded index, until the next reload.
+	CacheUpdatedAt *time.Time `json:"cache_updated_at,omitempty"`
+	ReloadPending  bool       `json:"reload_pending"`
+}
+
+// ListStatusHandler reports the versions and freshness of the screening lists
+type ListStatusHandler struct {
+	ofac ListReporter
+	pep  ListReporter
+	cfg  *config.ScreeningConfig
+	log  *logger.Logger
+}
+
+// NewListStatusHandler creates a new list status handler
+func NewListStatusHandler(ofac, pep ListReporter, cfg *config.ScreeningConfig, log *logger.Logger) *ListStatusHandler {
+	return &ListStatusHandler{
+		ofac: ofac,
+		pep:  pep,
+		cfg:  cfg,
+		log:  log.Named("list_status_handler"),
+	}
+}
+
+// Register mounts the list status route on the given group
+func (h *ListStatusHandler) Register(g *echo.Group) {
+	g.GET("/lists/status", h.GetStatus)
+}
+
+// GetStatus returns the source, entry count, last update and version of the
+// OFAC and PEP lists
+func (h *ListStatusHandler) GetStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	return c.JSON(http.StatusOK, []ListStatus{
+		h.status(ctx, "OFAC", h.cfg.OFACListSource, h.ofac),
+		h.status(ctx, "PEP", h.cfg.PEPListSource, h.pep),
+	})
+}
+
+// status reports a list. The index status is in memory, so a cache outage
+// only leaves CacheUpdatedAt unset.
+func (h *ListStatusHandler) status(ctx context.Context, list, source string, reporter ListReporter) ListStatus {
+	s := ListStatus{
+		List:        list,
+		Source:      source,
+		IndexStatus: reporter.IndexStatus(),
+	}
+
+	updatedAt, err := reporter.LastUpdate(ctx)
+	if err != nil {
+		h.log.WithContext(ctx).Warn("list last update unavailable",
+			logger.StringField("list", list),
+			logger.ErrorField(err),
+		)
+		return s
+	}
+	if !updatedAt.IsZero() {
+		s.CacheUpdatedAt = &updatedAt
+		s.ReloadPending = s.ListUpdatedAt == nil || updatedAt.After(*s.ListUpdatedAt)
+	}
+	return s
+}
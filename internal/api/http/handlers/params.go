@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	amlmiddleware "github.com/banking/aml-service/internal/api/http/middleware"
+)
+
+// errInvalidParam reports a malformed query parameter
+func errInvalidParam(name string) error {
+	return fmt.Errorf("invalid %s parameter", name)
+}
+
+// parsePagination reads limit and offset query parameters, applying the
+// default page size and clamping to the maximum
+func parsePagination(c echo.Context) (limit, offset int, err error) {
+	limit = defaultPageLimit
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return 0, 0, errInvalidParam("limit")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+	}
+	if v := c.QueryParam("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errInvalidParam("offset")
+		}
+	}
+	return limit, offset, nil
+}
+
+// tokenActor sets *actorID to the bearer token subject that
+// amlmiddleware.RequireToken verified. It fails, for a 403, when the subject
+// is not an actor id or the body already named a different actor.
+func tokenActor(c echo.Context, actorID *uuid.UUID) error {
+	subject, _ := c.Get(amlmiddleware.SubjectContextKey).(string)
+	verified, err := uuid.Parse(subject)
+	if err != nil {
+		return errors.New("token subject is not an actor id")
+	}
+	if *actorID != uuid.Nil && *actorID != verified {
+		return errors.New("actor_id does not match the bearer token")
+	}
+	*actorID = verified
+	return nil
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// ReportService interface for management information reports
+type ReportService interface {
+	Report(ctx context.Context, period domain.ReportPeriod, date time.Time) (*domain.MIReport, error)
+}
+
+// ReportHandler serves daily and monthly MI reports
+type ReportHandler struct {
+	reports ReportService
+	log     *logger.Logger
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reports ReportService, log *logger.Logger) *ReportHandler {
+	return &ReportHandler{
+		reports: reports,
+		log:     log.Named("report_handler"),
+	}
+}
+
+// Register mounts the report routes on the given group
+func (h *ReportHandler) Register(g *echo.Group) {
+	for _, period := range []domain.ReportPeriod{domain.ReportPeriodDaily, domain.ReportPeriodMonthly} {
+		path := "/reports/" + strings.ToLower(string(period))
+		g.GET(path, h.get(period, false))
+		g.GET(path+".csv", h.get(period, true))
+	}
+}
+
+// get serves the report for ?date= (YYYY-MM-DD, or YYYY-MM for monthly),
+// defaulting to the most recent closed period, as JSON or CSV
+func (h *ReportHandler) get(period domain.ReportPeriod, asCSV bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		date, err := parseReportDate(period, c.QueryParam("date"))
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, err.Error())
+		}
+
+		report, err := h.reports.Report(c.Request().Context(), period, date)
+		if err != nil {
+			if errors.Is(err, domain.ErrValidation) {
+				return errorResponse(c, http.StatusBadRequest, err.Error())
+			}
+			h.log.Error("failed to get mi report",
+				logger.StringField("period", string(period)),
+				logger.ErrorField(err),
+			)
+			return failureResponse(c, err, "failed to get report")
+		}
+
+		if !asCSV {
+			return c.JSON(http.StatusOK, report)
+		}
+
+		resp := c.Response()
+		resp.Header().Set(echo.HeaderContentType, "text/csv")
+		resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="mi-%s-%s.csv"`,
+			strings.ToLower(string(period)), report.PeriodStart.Format(time.DateOnly)))
+		resp.WriteHeader(http.StatusOK)
+
+		w := csv.NewWriter(resp)
+		_ = w.WriteAll(miReportRecords(report))
+		return w.Error()
+	}
+}
+
+// parseReportDate reads the date query parameter; an empty value returns
+// the zero time
+func parseReportDate(period domain.ReportPeriod, v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	if period == domain.ReportPeriodMonthly {
+		if t, err := time.Parse("2006-01", v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errInvalidParam("date")
+}
+
+// miReportRecords flattens a report into metric,value rows
+func miReportRecords(r *domain.MIReport) [][]string {
+	count := func(n int64) string { return strconv.FormatInt(n, 10) }
+	rate := func(f float64) string { return strconv.FormatFloat(f, 'f', 4, 64) }
+
+	records := [][]string{
+		{"metric", "value"},
+		{"period", string(r.Period)},
+		{"period_start", r.PeriodStart.Format(time.RFC3339)},
+		{"period_end", r.PeriodEnd.Format(time.RFC3339)},
+		{"provisional", strconv.FormatBool(r.Provisional)},
+		{"generated_at", r.GeneratedAt.Format(time.RFC3339)},
+		{"screening.total", count(r.Screening.Total)},
+		{"screening.approved", count(r.Screening.Approved)},
+		{"screening.suspicious", count(r.Screening.Suspicious)},
+		{"screening.blocked", count(r.Screening.Blocked)},
+		{"screening.pending", count(r.Screening.Pending)},
+		{"screening.ofac_hits", count(r.Screening.OFACHits)},
+		{"screening.pep_hits", count(r.Screening.PEPHits)},
+		{"screening.hits", count(r.Screening.Hits)},
+		{"screening.hit_rate", rate(r.Screening.HitRate)},
+		{"investigations.opened", count(r.Investigations.Opened)},
+		{"investigations.closed", count(r.Investigations.Closed)},
+		{"investigations.false_positives", count(r.Investigations.FalsePositives)},
+		{"investigations.false_positive_rate", rate(r.Investigations.FalsePositiveRate)},
+	}
+	for _, f := range []struct {
+		name  string
+		stats domain.MIFilings
+	}{{"sar", r.SAR}, {"ctr", r.CTR}} {
+		records = append(records,
+			[]string{f.name + ".submitted", count(f.stats.Submitted)},
+			[]string{f.name + ".amendments", count(f.stats.Amendments)},
+			[]string{f.name + ".on_time", count(f.stats.OnTime)},
+			[]string{f.name + ".late", count(f.stats.Late)},
+			[]string{f.name + ".past_due", count(f.stats.PastDue)},
+			[]string{f.name + ".on_time_rate", rate(f.stats.OnTimeRate)},
+		)
+	}
+	for _, p := range r.TopPatterns {
+		records = append(records, []string{"top_patterns." + string(p.PatternType), count(p.Count)})
+	}
+
+	return records
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/apperr"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// problemContentType is the media type of every error response
+const problemContentType = "application/problem+json"
+
+// problem is the body of every error response, an RFC 7807 problem details
+// object. Code, RequestID and Errors are extension members: the error
+// class, the X-Request-ID the request was served under, and the invalid
+// fields of a request that failed validation.
+type problem struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail,omitempty"`
+	Instance  string      `json:"instance,omitempty"`
+	Code      apperr.Code `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
+	Errors    interface{} `json:"errors,omitempty"`
+}
+
+// problemTitles are the titles of each class of error. A problem's title
+// is the same for every occurrence of its type; Detail says what happened.
+var problemTitles = map[apperr.Code]string{
+	apperr.CodeValidation:            "Request failed validation",
+	apperr.CodeNotFound:              "Resource not found",
+	apperr.CodeConflict:              "Request conflicts with the resource's state",
+	apperr.CodeUnauthorized:          "Authentication required",
+	apperr.CodeForbidden:             "Request not permitted",
+	apperr.CodeDependencyUnavailable: "Dependency unavailable",
+	apperr.CodeIntegrity:             "Integrity check failed",
+	apperr.CodeInternal:              "Internal error",
+}
+
+// problemType returns the type URI of a class of error, e.g.
+// "urn:aml:problem:validation-failed"
+func problemType(code apperr.Code) string {
+	return "urn:aml:problem:" + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+// errorResponse writes an error body with the given status
+func errorResponse(c echo.Context, status int, message string) error {
+	return writeError(c, status, apperr.CodeForStatus(status), message, nil)
+}
+
+// detailedErrorResponse writes an error body carrying the details of err,
+// such as the fields that failed validation
+func detailedErrorResponse(c echo.Context, status int, message string, err error) error {
+	return writeError(c, status, apperr.CodeForStatus(status), message, apperr.DetailsOf(err))
+}
+
+// failureResponse answers a request that failed unexpectedly: 503 when a
+// dependency was unavailable, otherwise 500. err is not shown to the
+// caller, so the handler should log it.
+func failureResponse(c echo.Context, err error, message string) error {
+	if apperr.CodeOf(err) == apperr.CodeDependencyUnavailable {
+		return writeError(c, http.StatusServiceUnavailable, apperr.CodeDependencyUnavailable, message, nil)
+	}
+	return writeError(c, http.StatusInternalServerError, apperr.CodeInternal, message, nil)
+}
+
+// validationResponse answers a request whose body failed its validate tags
+// with 422 and the invalid fields. Any other error means the validator is
+// misconfigured and is passed to the error handler.
+func validationResponse(c echo.Context, err error) error {
+	var ferr domain.FieldErrors
+	if errors.As(err, &ferr) {
+		return writeError(c, http.StatusUnprocessableEntity, apperr.CodeValidation, "invalid request", ferr.ErrorDetails())
+	}
+	return err
+}
+
+// writeError writes a problem+json error body. details, the invalid fields
+// of the request when it failed validation, become the errors member.
+func writeError(c echo.Context, status int, code apperr.Code, message string, details interface{}) error {
+	title, ok := problemTitles[code]
+	if !ok {
+		title = http.StatusText(status)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, problemContentType)
+	return c.JSON(status, &problem{
+		Type:      problemType(code),
+		Title:     title,
+		Status:    status,
+		Detail:    message,
+		Instance:  c.Request().URL.Path,
+		Code:      code,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+		Errors:    details,
+	})
+}
+
+// ErrorHandler writes errors returned by handlers and middleware as
+// problem+json. apperr codes choose the status; echo.HTTPErrors, such as
+// unknown routes and rejected tokens, keep theirs. Only client errors
+// reveal their message, and server errors are logged.
+func ErrorHandler(log *logger.Logger) echo.HTTPErrorHandler {
+	log = log.Named("http_errors")
+
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		var status int
+		var code apperr.Code
+		var message string
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			status = he.Code
+			code = apperr.CodeForStatus(status)
+			message = http.StatusText(status)
+			if m, ok := he.Message.(string); ok && status < http.StatusInternalServerError {
+				message = m
+			}
+		} else {
+			code = apperr.CodeOf(err)
+			status = apperr.HTTPStatus(code)
+			message = http.StatusText(status)
+			if apperr.IsClientError(code) {
+				message = err.Error()
+			}
+		}
+
+		if status >= http.StatusInternalServerError {
+			log.Error("request failed",
+				logger.StringField("method", c.Request().Method),
+				logger.StringField("path", c.Path()),
+				logger.StringField("request_id", c.Response().Header().Get(echo.HeaderXRequestID)),
+				logger.StringField("code", string(code)),
+				logger.ErrorField(err),
+			)
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(status)
+		} else {
+			err = writeError(c, status, code, message, apperr.DetailsOf(err))
+		}
+		if err != nil {
+			log.Error("failed to write error response",
+				logger.IntField("status", status),
+				logger.ErrorField(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// ScreeningResultReader interface for reading stored screening results
+type ScreeningResultReader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScreeningResult, error)
+}
+
+// Screener screens transactions and names and re-runs screening for stored
+// results
+type Screener interface {
+	ScreenRequest(ctx context.Context, req *domain.ScreeningRequest) (*domain.ScreeningResult, error)
+	ScreenName(ctx context.Context, req *domain.NameScreeningRequest) (*domain.NameScreeningResponse, error)
+	Rescreen(ctx context.Context, original *domain.ScreeningResult) (*domain.ScreeningResult, error)
+}
+
+// ScreeningExporter streams screening results in bulk
+type ScreeningExporter interface {
+	Export(ctx context.Context, req *domain.ScreeningExportRequest, fn func(*domain.ScreeningResult) error) error
+}
+
+// ScreeningHandler serves screening endpoints
+type ScreeningHandler struct {
+	results  ScreeningResultReader
+	screener Screener
+	exporter ScreeningExporter
+	log      *logger.Logger
+}
+
+// NewScreeningHandler creates a new screening handler
+func NewScreeningHandler(results ScreeningResultReader, screener Screener, exporter ScreeningExporter, log *logger.Logger) *ScreeningHandler {
+	return &ScreeningHandler{
+		results:  results,
+		screener: screener,
+		exporter: exporter,
+		log:      log.Named("screening_handler"),
+	}
+}
+
+// Register mounts the screening routes on the given group
+func (h *ScreeningHandler) Register(g *echo.Group) {
+	g.POST("/screening", h.Screen)
+	g.POST("/screen/name", h.ScreenName)
+	g.GET("/screening/reason-codes", h.ListReasonCodes)
+	g.GET("/screening/export", h.Export, middleware.Gzip())
+	g.GET("/screening/:id", h.GetScreening)
+	g.POST("/screening/:id/rescreen", h.Rescreen)
+}
+
+// Screen screens a single transaction synchronously
+func (h *ScreeningHandler) Screen(c echo.Context) error {
+	var req domain.ScreeningRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if req.Transaction == nil || req.Transaction.ID == uuid.Nil || req.Transaction.UserID == uuid.Nil {
+		return errorResponse(c, http.StatusBadRequest, "transaction with id and user_id is required")
+	}
+
+	result, err := h.screener.ScreenRequest(c.Request().Context(), &req)
+	if errors.Is(err, domain.ErrForbidden) {
+		return errorResponse(c, http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		h.log.Error("screening failed",
+			logger.StringField("transaction_id", req.Transaction.ID.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "screening failed")
+	}
+
+	return c.JSON(http.StatusOK, result.ToResponse())
+}
+
+// ScreenName checks a prospective customer's name against the OFAC and PEP
+// lists. It returns 503 when a list could not be checked so onboarding is
+// never approved on an incomplete screen.
+func (h *ScreeningHandler) ScreenName(c echo.Context) error {
+	var req domain.NameScreeningRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errorResponse(c, http.StatusBadRequest, "name is required")
+	}
+	if req.DateOfBirth != "" {
+		if _, err := time.Parse(time.DateOnly, req.DateOfBirth); err != nil {
+			return errorResponse(c, http.StatusBadRequest, "date_of_birth must be YYYY-MM-DD")
+		}
+	}
+
+	resp, err := h.screener.ScreenName(c.Request().Context(), &req)
+	if err != nil {
+		h.log.Error("name screening failed", logger.ErrorField(err))
+		return errorResponse(c, http.StatusServiceUnavailable, "name screening unavailable")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListReasonCodes returns every decision reason code with its description
+func (h *ScreeningHandler) ListReasonCodes(c echo.Context) error {
+	return c.JSON(http.StatusOK, domain.AllReasonCodes())
+}
+
+// GetScreening returns a stored screening result by ID
+func (h *ScreeningHandler) GetScreening(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid screening id")
+	}
+
+	result, err := h.results.GetByID(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "screening result not found")
+		}
+		h.log.Error("failed to get screening result", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to get screening result")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Rescreen re-screens the transaction behind a stored result against the
+// current lists and returns both results with a diff
+func (h *ScreeningHandler) Rescreen(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid screening id")
+	}
+
+	ctx := c.Request().Context()
+	original, err := h.results.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "screening result not found")
+		}
+		h.log.Error("failed to get screening result", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to get screening result")
+	}
+
+	rescreen, err := h.screener.Rescreen(ctx, original)
+	if err != nil {
+		if errors.Is(err, screening.ErrOriginalTransactionMissing) {
+			return errorResponse(c, http.StatusUnprocessableEntity, err.Error())
+		}
+		h.log.Error("rescreen failed",
+			logger.StringField("screening_id", id.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "rescreen failed")
+	}
+
+	return c.JSON(http.StatusOK, domain.RescreenResponse{
+		Original: original,
+		Rescreen: rescreen,
+		Diff:     domain.DiffScreeningResults(original, rescreen),
+	})
+}
+
+// Export streams the screening results created in [from, to) as CSV,
+// optionally filtered by decision. Rows are written as they are read, and
+// the body is gzip-compressed when the client accepts it.
+func (h *ScreeningHandler) Export(c echo.Context) error {
+	req, err := parseScreeningExport(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	resp := c.Response()
+	var w *csv.Writer
+	var rows int
+	err = h.exporter.Export(c.Request().Context(), req, func(result *domain.ScreeningResult) error {
+		if w == nil {
+			w = startScreeningExport(resp, req)
+		}
+		if err := w.Write(screeningExportRecord(result)); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushRows == 0 {
+			w.Flush()
+			resp.Flush()
+		}
+		return w.Error()
+	})
+
+	if w == nil {
+		if err != nil {
+			if errors.Is(err, domain.ErrValidation) {
+				return errorResponse(c, http.StatusBadRequest, err.Error())
+			}
+			h.log.Error("screening export failed", logger.ErrorField(err))
+			return failureResponse(c, err, "screening export failed")
+		}
+		// Nothing matched; still send the header row
+		w = startScreeningExport(resp, req)
+	}
+	w.Flush()
+
+	if err != nil {
+		// The status line has been sent, so the client sees a truncated file
+		h.log.Error("screening export interrupted",
+			logger.IntField("rows_written", rows),
+			logger.ErrorField(err),
+		)
+		return nil
+	}
+	return w.Error()
+}
+
+// exportFlushRows is how many CSV rows are buffered before flushing to the client
+const exportFlushRows = 500
+
+// multiValueSep separates the values of list columns in CSV exports
+const multiValueSep = "|"
+
+// parseScreeningExport reads the export range, decision and actor from the
+// query string. from and to are RFC 3339 timestamps or YYYY-MM-DD dates;
+// to is exclusive.
+func parseScreeningExport(c echo.Context) (*domain.ScreeningExportRequest, error) {
+	req := &domain.ScreeningExportRequest{Decision: domain.ScreeningDecision(c.QueryParam("decision"))}
+
+	var err error
+	if req.From, err = parseExportTime(c.QueryParam("from")); err != nil {
+		return nil, errInvalidParam("from")
+	}
+	if req.To, err = parseExportTime(c.QueryParam("to")); err != nil {
+		return nil, errInvalidParam("to")
+	}
+	if v := c.QueryParam("actor_id"); v != "" {
+		if req.ActorID, err = uuid.Parse(v); err != nil {
+			return nil, errInvalidParam("actor_id")
+		}
+	}
+	return req, nil
+}
+
+// parseExportTime parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC
+// midnight); an empty value returns the zero time
+func parseExportTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
+
+// startScreeningExport sends the CSV headers and column names
+func startScreeningExport(resp *echo.Response, req *domain.ScreeningExportRequest) *csv.Writer {
+	resp.Header().Set(echo.HeaderContentType, "text/csv")
+	resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="screenings-%s-%s.csv"`,
+		req.From.Format(time.DateOnly), req.To.Format(time.DateOnly)))
+	resp.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(resp)
+	_ = w.Write([]string{
+		"screening_id", "transaction_id", "user_id", "created_at",
+		"decision", "risk_score", "risk_level",
+		"ofac_matched", "ofac_score", "sdn_name",
+		"pep_matched", "pep_score", "pep_name",
+		"risk_factors", "pattern_types", "reason_codes", "checks_failed",
+		"rescreen_of_id", "screening_duration_ms",
+		"ofac_list_version", "pep_list_version", "country_risk_version",
+		"ofac_list_hash", "pep_list_hash",
+	})
+	return w
+}
+
+// screeningExportRecord flattens a screening result into a CSV row. Risk
+// factors are written as factor:weight and list columns are joined with
+// multiValueSep.
+func screeningExportRecord(r *domain.ScreeningResult) []string {
+	record := make([]string, 24)
+	record[0] = r.ID.String()
+	record[1] = r.TransactionID.String()
+	record[2] = r.UserID.String()
+	record[3] = r.CreatedAt.UTC().Format(time.RFC3339)
+	record[4] = string(r.Decision)
+	record[5] = strconv.Itoa(r.RiskScore)
+	record[6] = string(r.RiskLevel)
+	if m := r.OFACMatch; m != nil {
+		record[7] = strconv.FormatBool(m.Matched)
+		record[8] = strconv.FormatFloat(m.MatchScore, 'f', 3, 64)
+		record[9] = m.SDNName
+	}
+	if m := r.PEPMatch; m != nil {
+		record[10] = strconv.FormatBool(m.Matched)
+		record[11] = strconv.FormatFloat(m.MatchScore, 'f', 3, 64)
+		record[12] = m.PEPName
+	}
+
+	factors := make([]string, len(r.RiskFactors))
+	for i, f := range r.RiskFactors {
+		factors[i] = f.Factor + ":" + strconv.Itoa(f.Weight)
+	}
+	record[13] = strings.Join(factors, multiValueSep)
+
+	patterns := make([]string, len(r.PatternMatches))
+	for i, p := range r.PatternMatches {
+		patterns[i] = string(p.PatternType)
+	}
+	record[14] = strings.Join(patterns, multiValueSep)
+
+	record[15] = strings.Join(r.ReasonCodes, multiValueSep)
+
+	checks := make([]string, len(r.ChecksFailed))
+	for i, f := range r.ChecksFailed {
+		checks[i] = f.Check
+	}
+	record[16] = strings.Join(checks, multiValueSep)
+
+	if r.RescreenOfID != nil {
+		record[17] = r.RescreenOfID.String()
+	}
+	record[18] = strconv.FormatInt(r.ScreeningDurationMs, 10)
+	if r.OFACListVersion != 0 {
+		record[19] = strconv.FormatInt(r.OFACListVersion, 10)
+	}
+	if r.PEPListVersion != 0 {
+		record[20] = strconv.FormatInt(r.PEPListVersion, 10)
+	}
+	if r.CountryRiskVersion != nil {
+		record[21] = strconv.Itoa(*r.CountryRiskVersion)
+	}
+	record[22] = r.OFACListHash
+	record[23] = r.PEPListHash
+	return record
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/pkg/breaker"
+)
+
+// BreakerReporter reports the state of dependency circuit breakers
+type BreakerReporter interface {
+	Snapshot() []breaker.Status
+}
+
+// SystemHandler serves operational status endpoints
+type SystemHandler struct {
+	breakers BreakerReporter
+}
+
+// NewSystemHandler creates a new system handler
+func NewSystemHandler(breakers BreakerReporter) *SystemHandler {
+	return &SystemHandler{breakers: breakers}
+}
+
+// Register mounts the system routes on the given group
+func (h *SystemHandler) Register(g *echo.Group) {
+	g.GET("/system/breakers", h.ListBreakers)
+}
+
+// ListBreakers returns the state of every dependency circuit breaker
+func (h *SystemHandler) ListBreakers(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.breakers.Snapshot())
+}
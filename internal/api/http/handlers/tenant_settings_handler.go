@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// TenantSettingsManager reads and saves tenants' overrides
+type TenantSettingsManager interface {
+	Get(ctx context.Context, tenantID string) (*domain.TenantSettingsResponse, error)
+	Update(ctx context.Context, tenantID string, req *domain.UpdateTenantSettingsRequest) (*domain.TenantSettingsResponse, error)
+}
+
+// TenantSettingsHandler serves the tenant settings endpoints. It is mounted
+// on the admin group, which applies authentication and rate limiting.
+type TenantSettingsHandler struct {
+	settings TenantSettingsManager
+	log      *logger.Logger
+}
+
+// NewTenantSettingsHandler creates a new tenant settings handler
+func NewTenantSettingsHandler(settings TenantSettingsManager, log *logger.Logger) *TenantSettingsHandler {
+	return &TenantSettingsHandler{
+		settings: settings,
+		log:      log.Named("tenant_settings_handler"),
+	}
+}
+
+// Register mounts the tenant settings routes on the given group
+func (h *TenantSettingsHandler) Register(g *echo.Group) {
+	g.GET("/tenants/:id/settings", h.Get)
+	g.PUT("/tenants/:id/settings", h.Update)
+}
+
+// Get returns a tenant's overrides and the rules in force for it
+func (h *TenantSettingsHandler) Get(c echo.Context) error {
+	resp, err := h.settings.Get(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.failure(c, err, "get tenant settings")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Update replaces a tenant's overrides and puts them in force
+func (h *TenantSettingsHandler) Update(c echo.Context) error {
+	var req domain.UpdateTenantSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	resp, err := h.settings.Update(c.Request().Context(), c.Param("id"), &req)
+	if err != nil {
+		return h.failure(c, err, "save tenant settings")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *TenantSettingsHandler) failure(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		return errorResponse(c, http.StatusForbidden, err.Error())
+	}
+	h.log.Error("failed to "+action, logger.ErrorField(err))
+	return failureResponse(c, err, "failed to "+action)
+}
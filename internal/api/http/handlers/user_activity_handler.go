@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// UserActivityReader assembles a user's activity for case review
+type UserActivityReader interface {
+	Get(ctx context.Context, userID uuid.UUID, since time.Time, limit, offset int) (*domain.UserActivity, error)
+}
+
+// UserActivityHandler serves per-user monitoring profiles
+type UserActivityHandler struct {
+	activity UserActivityReader
+	log      *logger.Logger
+}
+
+// NewUserActivityHandler creates a new user activity handler
+func NewUserActivityHandler(activity UserActivityReader, log *logger.Logger) *UserActivityHandler {
+	return &UserActivityHandler{
+		activity: activity,
+		log:      log.Named("user_activity_handler"),
+	}
+}
+
+// Register mounts the user activity route on the given group
+func (h *UserActivityHandler) Register(g *echo.Group) {
+	g.GET("/users/:user_id/activity", h.GetActivity)
+}
+
+// GetActivity returns the user's risk profile summary, watchlist status,
+// velocity, a page of screenings, pattern counts, alerts, investigations
+// and filings
+//
+// Query parameters: since (RFC 3339 or YYYY-MM-DD), limit (default 50, max
+// 200) and offset of the screenings page
+func (h *UserActivityHandler) GetActivity(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid user id")
+	}
+	since, err := parseExportTime(c.QueryParam("since"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, errInvalidParam("since").Error())
+	}
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	activity, err := h.activity.Get(c.Request().Context(), userID, since, limit, offset)
+	if err != nil {
+		h.log.Error("failed to get user activity",
+			logger.StringField("user_id", userID.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "failed to get user activity")
+	}
+
+	return c.JSON(http.StatusOK, activity)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// WatchlistManager adds and removes users on the internal watchlist
+type WatchlistManager interface {
+	List(ctx context.Context, limit, offset int) ([]*domain.UserRiskProfile, int, error)
+	Add(ctx context.Context, userID uuid.UUID, req *domain.WatchlistChangeRequest) (*domain.UserRiskProfile, error)
+	Remove(ctx context.Context, userID uuid.UUID, req *domain.WatchlistChangeRequest) (*domain.UserRiskProfile, error)
+}
+
+// WatchlistHandler serves watchlist endpoints
+type WatchlistHandler struct {
+	watchlist    WatchlistManager
+	authenticate echo.MiddlewareFunc
+	log          *logger.Logger
+}
+
+// NewWatchlistHandler creates a new watchlist handler. authenticate guards
+// removals and must put the verified caller under
+// amlmiddleware.SubjectContextKey.
+func NewWatchlistHandler(watchlist WatchlistManager, authenticate echo.MiddlewareFunc, log *logger.Logger) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlist:    watchlist,
+		authenticate: authenticate,
+		log:          log.Named("watchlist_handler"),
+	}
+}
+
+// Register mounts the watchlist routes on the given group
+func (h *WatchlistHandler) Register(g *echo.Group) {
+	g.GET("/watchlist", h.List)
+	g.POST("/watchlist/:user_id", h.Add)
+	g.DELETE("/watchlist/:user_id", h.Remove, h.authenticate)
+}
+
+// List returns watchlisted users, most recently added first
+//
+// Query parameters: limit (default 50, max 200), offset
+func (h *WatchlistHandler) List(c echo.Context) error {
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	profiles, total, err := h.watchlist.List(c.Request().Context(), limit, offset)
+	if err != nil {
+		h.log.Error("failed to list watchlist", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to list watchlist")
+	}
+
+	entries := make([]*domain.WatchlistEntry, 0, len(profiles))
+	for _, p := range profiles {
+		entries = append(entries, p.ToWatchlistEntry())
+	}
+
+	return c.JSON(http.StatusOK, &domain.WatchlistListResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// Add puts a user on the watchlist and returns their reassessed profile
+func (h *WatchlistHandler) Add(c echo.Context) error {
+	return h.change(c, h.watchlist.Add, "add user to watchlist", false)
+}
+
+// Remove takes a user off the watchlist. The actor is the bearer token's
+// subject, which must be a supervisor; an actor_id in the body naming
+// anyone else is refused.
+func (h *WatchlistHandler) Remove(c echo.Context) error {
+	return h.change(c, h.watchlist.Remove, "remove user from watchlist", true)
+}
+
+// change applies a watchlist change. With verified set the actor is taken
+// from the bearer token rather than the body.
+func (h *WatchlistHandler) change(
+	c echo.Context,
+	apply func(context.Context, uuid.UUID, *domain.WatchlistChangeRequest) (*domain.UserRiskProfile, error),
+	action string,
+	verified bool,
+) error {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid user id")
+	}
+
+	var req domain.WatchlistChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if verified {
+		if err := tokenActor(c, &req.ActorID); err != nil {
+			return errorResponse(c, http.StatusForbidden, err.Error())
+		}
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+	if err := req.Validate(); err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	profile, err := apply(c.Request().Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errorResponse(c, http.StatusNotFound, "risk profile not found")
+		case errors.Is(err, domain.ErrForbidden):
+			return errorResponse(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, domain.ErrConflict):
+			return errorResponse(c, http.StatusConflict, err.Error())
+		}
+		h.log.Error("failed to "+action,
+			logger.StringField("user_id", userID.String()),
+			logger.ErrorField(err),
+		)
+		return failureResponse(c, err, "failed to "+action)
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
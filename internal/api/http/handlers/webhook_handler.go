@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// WebhookEndpointManager registers webhook endpoints and replays events to
+// them
+type WebhookEndpointManager interface {
+	Create(ctx context.Context, req *domain.CreateWebhookEndpointRequest) (*domain.WebhookEndpoint, error)
+	List(ctx context.Context) ([]*domain.WebhookEndpoint, error)
+	Get(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error)
+	Delete(ctx context.Context, id uuid.UUID, req *domain.WebhookEndpointChangeRequest) error
+	Enable(ctx context.Context, id uuid.UUID, req *domain.WebhookEndpointChangeRequest) (*domain.WebhookEndpoint, error)
+	Deliveries(ctx context.Context, id uuid.UUID, eventID *uuid.UUID, limit, offset int) ([]*domain.WebhookDelivery, error)
+	Replay(ctx context.Context, eventID uuid.UUID, req *domain.ReplayWebhookEventRequest) (*domain.ReplayWebhookEventResponse, error)
+}
+
+// WebhookHandler serves the webhook endpoint administration endpoints. It
+// is mounted on the admin group, which applies authentication and rate
+// limiting.
+type WebhookHandler struct {
+	webhooks WebhookEndpointManager
+	log      *logger.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhooks WebhookEndpointManager, log *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhooks: webhooks,
+		log:      log.Named("webhook_handler"),
+	}
+}
+
+// Register mounts the webhook routes on the given group
+func (h *WebhookHandler) Register(g *echo.Group) {
+	g.POST("/webhooks", h.Create)
+	g.GET("/webhooks", h.List)
+	g.GET("/webhooks/:id", h.Get)
+	g.DELETE("/webhooks/:id", h.Delete)
+	g.POST("/webhooks/:id/enable", h.Enable)
+	g.GET("/webhooks/:id/deliveries", h.Deliveries)
+	g.POST("/webhooks/events/:id/replay", h.Replay)
+}
+
+// Create registers a webhook endpoint
+func (h *WebhookHandler) Create(c echo.Context) error {
+	var req domain.CreateWebhookEndpointRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	endpoint, err := h.webhooks.Create(c.Request().Context(), &req)
+	if err != nil {
+		return h.failure(c, err, "register webhook endpoint")
+	}
+
+	return c.JSON(http.StatusCreated, endpoint)
+}
+
+// List returns every webhook endpoint by name
+func (h *WebhookHandler) List(c echo.Context) error {
+	endpoints, err := h.webhooks.List(c.Request().Context())
+	if err != nil {
+		h.log.Error("failed to list webhook endpoints", logger.ErrorField(err))
+		return failureResponse(c, err, "failed to list webhook endpoints")
+	}
+
+	return c.JSON(http.StatusOK, &domain.WebhookEndpointListResponse{
+		Endpoints: endpoints,
+		Total:     len(endpoints),
+	})
+}
+
+// Get returns a webhook endpoint
+func (h *WebhookHandler) Get(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid webhook endpoint id")
+	}
+
+	endpoint, err := h.webhooks.Get(c.Request().Context(), id)
+	if err != nil {
+		return h.failure(c, err, "get webhook endpoint")
+	}
+
+	return c.JSON(http.StatusOK, endpoint)
+}
+
+// Delete removes a webhook endpoint registered through the API
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	id, req, err := h.bindChange(c)
+	if req == nil {
+		return err
+	}
+
+	if err := h.webhooks.Delete(c.Request().Context(), id, req); err != nil {
+		return h.failure(c, err, "delete webhook endpoint")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Enable re-enables a webhook endpoint and clears its failure count
+func (h *WebhookHandler) Enable(c echo.Context) error {
+	id, req, err := h.bindChange(c)
+	if req == nil {
+		return err
+	}
+
+	endpoint, err := h.webhooks.Enable(c.Request().Context(), id, req)
+	if err != nil {
+		return h.failure(c, err, "enable webhook endpoint")
+	}
+
+	return c.JSON(http.StatusOK, endpoint)
+}
+
+// Deliveries returns a webhook endpoint's delivery attempts, newest first
+func (h *WebhookHandler) Deliveries(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid webhook endpoint id")
+	}
+
+	var eventID *uuid.UUID
+	if v := c.QueryParam("event_id"); v != "" {
+		parsed, err := uuid.Parse(v)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, errInvalidParam("event_id").Error())
+		}
+		eventID = &parsed
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, err := h.webhooks.Deliveries(c.Request().Context(), id, eventID, limit, offset)
+	if err != nil {
+		return h.failure(c, err, "list webhook deliveries")
+	}
+
+	return c.JSON(http.StatusOK, &domain.WebhookDeliveryListResponse{
+		Deliveries: deliveries,
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// Replay re-sends a stored webhook event under its original event ID
+func (h *WebhookHandler) Replay(c echo.Context) error {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid webhook event id")
+	}
+
+	var req domain.ReplayWebhookEventRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return validationResponse(c, err)
+	}
+
+	resp, err := h.webhooks.Replay(c.Request().Context(), eventID, &req)
+	if err != nil {
+		return h.failure(c, err, "replay webhook event")
+	}
+
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// bindChange reads the endpoint ID and change request. On failure it
+// writes the error response and returns a nil request.
+func (h *WebhookHandler) bindChange(c echo.Context) (uuid.UUID, *domain.WebhookEndpointChangeRequest, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, nil, errorResponse(c, http.StatusBadRequest, "invalid webhook endpoint id")
+	}
+
+	var req domain.WebhookEndpointChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return uuid.Nil, nil, errorResponse(c, http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return uuid.Nil, nil, validationResponse(c, err)
+	}
+	return id, &req, nil
+}
+
+func (h *WebhookHandler) failure(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "webhook endpoint or event not found")
+	case errors.Is(err, domain.ErrConflict):
+		return errorResponse(c, http.StatusConflict, err.Error())
+	}
+	h.log.Error("failed to "+action, logger.ErrorField(err))
+	return failureResponse(c, err, "failed to "+action)
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/pkg/auth"
+)
+
+// SubjectContextKey holds the authenticated token subject in the echo context
+const SubjectContextKey = "auth_subject"
+
+// RequireToken admits requests bearing an HS256 JWT signed with secret,
+// whatever its roles, and puts its subject under SubjectContextKey for
+// handlers that act on the caller's identity. Missing or invalid tokens get
+// 401; with no secret configured every request is rejected.
+func RequireToken(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if secret == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, auth.ErrNotConfigured.Error())
+			}
+
+			raw, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || raw == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token")
+			}
+
+			subject, err := auth.VerifySubject(secret, raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+
+			c.Set(SubjectContextKey, subject)
+			return next(c)
+		}
+	}
+}
+
+// RequireRole admits requests bearing an HS256 JWT, signed with secret,
+// whose roles claim includes role. Missing or invalid tokens get 401 and
+// tokens without the role get 403. With no secret configured every request
+// is rejected, so the guarded routes fail closed.
+func RequireRole(secret, role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if secret == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, auth.ErrNotConfigured.Error())
+			}
+
+			raw, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || raw == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token")
+			}
+
+			subject, err := auth.VerifyRole(secret, raw, role)
+			switch {
+			case errors.Is(err, auth.ErrMissingRole):
+				return echo.NewHTTPError(http.StatusForbidden, err.Error())
+			case err != nil:
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+
+			c.Set(SubjectContextKey, subject)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// IdempotencyStore claims idempotency keys and stores their responses
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency makes POST requests carrying domain.IdempotencyKeyHeader safe
+// to retry. The first request with a key runs and its response is kept for
+// ttl; repeats get that response back with domain.IdempotentReplayHeader
+// set. Reusing a key with a different body, or while the first request is
+// still running, is a 409. Keys are scoped to the tenant and request path,
+// so tenants cannot replay each other's responses. The body is read into
+// memory to fingerprint it, so one over maxRequestSize bytes is a 413.
+//
+// The key is held for inFlight while the first request runs so a crashed
+// instance cannot block retries for the full ttl. Server errors release the
+// key so the request can be retried. If the store is unavailable requests
+// run without idempotency rather than failing.
+func Idempotency(store IdempotencyStore, ttl, inFlight time.Duration, maxRequestSize int64, log *logger.Logger) echo.MiddlewareFunc {
+	log = log.Named("idempotency")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			key := req.Header.Get(domain.IdempotencyKeyHeader)
+			if req.Method != http.MethodPost || key == "" {
+				return next(c)
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, maxRequestSize))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "request body is too large")
+				}
+				return echo.NewHTTPError(http.StatusBadRequest, "failed to read request body")
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := req.Context()
+			storeKey := tenant.OrDefault(ctx) + ":" + req.URL.Path + ":" + key
+			fingerprint := requestFingerprint(req.Method, req.URL.Path, body)
+
+			existing, err := store.Reserve(ctx, storeKey, fingerprint, inFlight)
+			if err != nil {
+				log.WithContext(ctx).Warn("idempotency store unavailable, running request without it",
+					logger.StringField("path", req.URL.Path),
+					logger.ErrorField(err),
+				)
+				return next(c)
+			}
+			if existing != nil {
+				return replay(c, existing, fingerprint)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			if err := next(c); err != nil {
+				// Render the error now so the stored response matches what was sent
+				c.Error(err)
+			}
+
+			// Store with a fresh context so a disconnected client cannot
+			// leave the key claimed
+			storeCtx := context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError {
+				if err := store.Release(storeCtx, storeKey); err != nil {
+					log.WithContext(ctx).Error("failed to release idempotency key", logger.ErrorField(err))
+				}
+				return nil
+			}
+
+			err = store.Complete(storeCtx, storeKey, &domain.IdempotencyRecord{
+				Fingerprint: fingerprint,
+				StatusCode:  status,
+				ContentType: c.Response().Header().Get(echo.HeaderContentType),
+				Body:        recorder.body.Bytes(),
+				CreatedAt:   time.Now(),
+			}, ttl)
+			if err != nil {
+				log.WithContext(ctx).Error("failed to store idempotent response", logger.ErrorField(err))
+			}
+			return nil
+		}
+	}
+}
+
+// replay answers a repeated request from the stored record
+func replay(c echo.Context, record *domain.IdempotencyRecord, fingerprint string) error {
+	switch {
+	case record.Fingerprint != fingerprint:
+		return echo.NewHTTPError(http.StatusConflict, "idempotency key was already used with a different request")
+	case !record.Completed:
+		return echo.NewHTTPError(http.StatusConflict, "a request with this idempotency key is still in progress")
+	}
+
+	c.Response().Header().Set(domain.IdempotentReplayHeader, "true")
+	return c.Blob(record.StatusCode, record.ContentType, record.Body)
+}
+
+// requestFingerprint identifies a request by method, path and body
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies the response body as it is written
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/pkg/auth"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// Tenant resolves the tenant each request acts for and puts it in the
+// request context, where repositories scope their queries to it. A bearer
+// token's tenant_id claim takes precedence; a tenant.Header naming another
+// tenant alongside it gets 403, so a token issued for one tenant cannot
+// read another's records. Without a claim the header names the tenant, and
+// without either the request acts for tenant.Default. Tenants not allowed
+// get 400, and a bearer token that fails verification gets 401. With no
+// secret configured tokens are not read and only the header counts.
+func Tenant(secret string, allowed func(id string) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := strings.TrimSpace(req.Header.Get(tenant.Header))
+
+			raw, bearer := strings.CutPrefix(req.Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if bearer && raw != "" && secret != "" {
+				claimed, err := auth.VerifyTenant(secret, raw)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+				}
+				if claimed != "" {
+					if id != "" && id != claimed {
+						return echo.NewHTTPError(http.StatusForbidden, "token is not valid for tenant "+id)
+					}
+					id = claimed
+				}
+			}
+
+			if id == "" {
+				id = tenant.Default
+			}
+			if !allowed(id) {
+				return echo.NewHTTPError(http.StatusBadRequest, "unknown tenant "+id)
+			}
+
+			c.SetRequest(req.WithContext(tenant.WithID(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
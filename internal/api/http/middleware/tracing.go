@@ -0,0 +1,76 @@
+// Package middleware holds the service's Echo middleware
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tracing"
+)
+
+const tracerName = "github.com/banking/aml-service/internal/api/http"
+
+// Tracing starts a server span for every inbound request, continuing any
+// trace propagated in the request headers. Requests carrying
+// tracing.ForceSampleHeader, and requests to routes alwaysSampled reports,
+// are always sampled; alwaysSampled may be nil.
+func Tracing(alwaysSampled func(method, route string) bool) echo.MiddlewareFunc {
+	tracer := otel.Tracer(tracerName)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := c.Path()
+			if route == "" {
+				route = req.URL.Path
+			}
+
+			force, _ := strconv.ParseBool(req.Header.Get(tracing.ForceSampleHeader))
+			if force || alwaysSampled != nil && alwaysSampled(req.Method, route) {
+				ctx = tracing.WithForceSample(ctx)
+			}
+
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("http.route", route),
+					attribute.String("url.path", req.URL.Path),
+				),
+			)
+			defer span.End()
+
+			ctx = tracing.WithLogContext(ctx)
+			if requestID := c.Response().Header().Get(echo.HeaderXRequestID); requestID != "" {
+				ctx = context.WithValue(ctx, logger.RequestIDKey, requestID)
+			}
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				// Let Echo render the error so the recorded status is the one sent
+				c.Error(err)
+				span.RecordError(err)
+			}
+
+			status := c.Response().Status
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= 500 {
+				span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+			}
+
+			return nil
+		}
+	}
+}
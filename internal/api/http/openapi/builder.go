@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/apperr"
+)
+
+// Problem is the RFC 7807 problem details object every error response is
+// written as, with the application/problem+json media type
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      apperr.Code         `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []domain.FieldError `json:"errors,omitempty"`
+}
+
+// builder collects operations as routes.go declares them
+type builder struct {
+	paths   map[string]*PathItem
+	schemas *schemas
+	tag     string
+}
+
+func newBuilder() *builder {
+	return &builder{
+		paths:   make(map[string]*PathItem),
+		schemas: newSchemas(),
+	}
+}
+
+// group sets the tag of the operations declared after it
+func (b *builder) group(tag string) {
+	b.tag = tag
+}
+
+// op declares an operation on an Echo-style path. Path parameters are
+// added from the path; every operation may fail with a Problem.
+func (b *builder) op(method, path, id, summary string) *op {
+	o := &Operation{
+		Tags:        []string{b.tag},
+		Summary:     summary,
+		OperationID: id,
+		Responses: map[string]*Response{
+			"default": b.errorResponse("Error"),
+		},
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(path, -1) {
+		schema := &Schema{Type: "string"}
+		if m[1] == "id" || strings.HasSuffix(m[1], "_id") {
+			schema.Format = "uuid"
+		}
+		o.Parameters = append(o.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: schema})
+	}
+	if method == http.MethodPost {
+		o.Parameters = append(o.Parameters, Parameter{
+			Name:        "Idempotency-Key",
+			In:          "header",
+			Description: "Replays the original response to a retry with the same key and body for 24h",
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	p := specPath(path)
+	if b.paths[p] == nil {
+		b.paths[p] = &PathItem{}
+	}
+	(*b.paths[p])[strings.ToLower(method)] = o
+	return &op{b: b, o: o}
+}
+
+func (b *builder) errorResponse(description string) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]*MediaType{"application/problem+json": {Schema: b.schemas.of(Problem{})}},
+	}
+}
+
+// op adds parameters, a body and responses to an operation
+type op struct {
+	b *builder
+	o *Operation
+}
+
+// describe sets the operation's description
+func (o *op) describe(text string) *op {
+	o.o.Description = text
+	return o
+}
+
+// query adds an optional query parameter
+func (o *op) query(name, description string, schema *Schema) *op {
+	o.o.Parameters = append(o.o.Parameters, Parameter{Name: name, In: "query", Description: description, Schema: schema})
+	return o
+}
+
+// body sets a JSON request body of v's type. Bodies failing their
+// validate tags are answered with 422.
+func (o *op) body(v interface{}) *op {
+	return o.bodyAs("application/json", v)
+}
+
+// multipart sets a multipart form body of v's form fields plus a file part
+func (o *op) multipart(v interface{}, filePart string) *op {
+	// The form fields are inlined so the file part can be added
+	schema := o.b.schemas.object(reflect.TypeOf(v))
+	schema.Properties[filePart] = &Schema{Type: "string", Format: "binary"}
+	schema.Required = append(schema.Required, filePart)
+
+	o.bodyAs("multipart/form-data", nil)
+	o.o.RequestBody.Content["multipart/form-data"].Schema = schema
+	return o
+}
+
+func (o *op) bodyAs(contentType string, v interface{}) *op {
+	o.o.RequestBody = &RequestBody{
+		Required: true,
+		Content:  map[string]*MediaType{contentType: {Schema: o.b.schemas.of(v)}},
+	}
+	o.o.Responses[strconv.Itoa(http.StatusUnprocessableEntity)] = o.b.errorResponse("The body failed validation; errors lists each invalid field")
+	return o
+}
+
+// returns adds a JSON response of v's type, or without a body if v is nil
+func (o *op) returns(status int, description string, v interface{}) *op {
+	r := &Response{Description: description}
+	if v != nil {
+		r.Content = map[string]*MediaType{"application/json": {Schema: o.b.schemas.of(v)}}
+	}
+	o.o.Responses[strconv.Itoa(status)] = r
+	return o
+}
+
+// returnsContent adds a response in another content type
+func (o *op) returnsContent(status int, description, contentType string, schema *Schema) *op {
+	o.o.Responses[strconv.Itoa(status)] = &Response{
+		Description: description,
+		Content:     map[string]*MediaType{contentType: {Schema: schema}},
+	}
+	return o
+}
+
+// authenticated marks the operation as needing a bearer token of any role,
+// whose subject is the acting user
+func (o *op) authenticated() *op {
+	o.o.Security = []map[string][]string{{bearerAuth: {}}}
+	o.o.Responses["401"] = o.b.errorResponse("Missing or invalid bearer token")
+	o.o.Responses["403"] = o.b.errorResponse("The token subject is not an actor id, or the body names another actor")
+	return o
+}
+
+// admin marks the operation as needing an admin bearer token
+func (o *op) admin() *op {
+	o.o.Security = []map[string][]string{{bearerAuth: {}}}
+	o.o.Responses["401"] = o.b.errorResponse("Missing or invalid bearer token")
+	o.o.Responses["403"] = o.b.errorResponse("The token lacks the admin role")
+	o.o.Responses["429"] = o.b.errorResponse("Admin rate limit exceeded")
+	return o
+}
+
+// Schemas for parameters and non-JSON bodies
+var (
+	stringSchema = &Schema{Type: "string"}
+	uuidSchema   = &Schema{Type: "string", Format: "uuid"}
+	timeSchema   = &Schema{Type: "string", Format: "date-time"}
+	boolSchema   = &Schema{Type: "boolean"}
+	intSchema    = &Schema{Type: "integer"}
+	binarySchema = &Schema{Type: "string", Format: "binary"}
+)
@@ -0,0 +1,94 @@
+package openapi
+
+import (
+	"reflect"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/apperr"
+)
+
+// enums lists the values of each enum type, so clients can generate
+// closed types for them
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(domain.ScreeningDecision("")): values(
+		domain.DecisionApproved, domain.DecisionSuspicious, domain.DecisionBlocked, domain.DecisionPending,
+	),
+	reflect.TypeOf(domain.RiskLevel("")): values(
+		domain.RiskLevelLow, domain.RiskLevelMedium, domain.RiskLevelHigh, domain.RiskLevelCritical,
+	),
+	reflect.TypeOf(domain.MatchType("")): values(
+		domain.MatchTypeExact, domain.MatchTypeFuzzy, domain.MatchTypeAlias, domain.MatchTypePartial,
+	),
+	reflect.TypeOf(domain.PatternType("")): values(
+		domain.PatternStructuring, domain.PatternRapidCycling, domain.PatternGeoConcentration,
+		domain.PatternVelocitySpike, domain.PatternMixingLayering, domain.PatternSmurfing,
+		domain.PatternRoundTripping, domain.PatternUnusualTime, domain.PatternDormantReactivation,
+	),
+	reflect.TypeOf(domain.ReasonCode("")): reasonCodes(),
+	reflect.TypeOf(domain.ScreeningPriority("")): values(
+		domain.ScreeningPriorityNormal, domain.ScreeningPriorityHigh, domain.ScreeningPriorityUrgent,
+	),
+	reflect.TypeOf(domain.AlertType("")): values(
+		domain.AlertTypePattern, domain.AlertTypeScreening, domain.AlertTypeVelocity,
+		domain.AlertTypeThreshold, domain.AlertTypeWatchlist, domain.AlertTypeSystemGenerated,
+	),
+	reflect.TypeOf(domain.AlertStatus("")): values(
+		domain.AlertStatusNew, domain.AlertStatusReviewing, domain.AlertStatusEscalated,
+		domain.AlertStatusDismissed, domain.AlertStatusResolved,
+	),
+	reflect.TypeOf(domain.InvestigationStatus("")): values(
+		domain.InvestigationStatusOpen, domain.InvestigationStatusAssigned, domain.InvestigationStatusInProgress,
+		domain.InvestigationStatusEscalated, domain.InvestigationStatusPending, domain.InvestigationStatusClosed,
+	),
+	reflect.TypeOf(domain.InvestigationDecision("")): values(
+		domain.DecisionFalsePositive, domain.DecisionSARFiled, domain.DecisionNoActionRequired,
+		domain.DecisionAccountBlocked, domain.DecisionReferred,
+	),
+	reflect.TypeOf(domain.InvestigationPriority("")): values(
+		domain.PriorityLow, domain.PriorityMedium, domain.PriorityHigh, domain.PriorityCritical,
+	),
+	reflect.TypeOf(domain.FilingType("")): values(
+		domain.FilingTypeSAR, domain.FilingTypeCTR,
+	),
+	reflect.TypeOf(domain.FilingStatus("")): values(
+		domain.FilingStatusDraft, domain.FilingStatusReview, domain.FilingStatusApproved,
+		domain.FilingStatusSubmitted, domain.FilingStatusAccepted, domain.FilingStatusRejected,
+		domain.FilingStatusAmended,
+	),
+	reflect.TypeOf(domain.BatchJobStatus("")): values(
+		domain.BatchJobStatusPending, domain.BatchJobStatusRunning,
+		domain.BatchJobStatusCompleted, domain.BatchJobStatusFailed,
+	),
+	reflect.TypeOf(domain.RetroactiveRescreenStatus("")): values(
+		domain.RetroactiveRescreenQueued, domain.RetroactiveRescreenRunning,
+		domain.RetroactiveRescreenCompleted, domain.RetroactiveRescreenFailed,
+	),
+	reflect.TypeOf(domain.GraphNodeType("")): values(
+		domain.GraphNodeUser, domain.GraphNodeCounterparty, domain.GraphNodeInvestigation,
+		domain.GraphNodeAlert, domain.GraphNodeFiling,
+	),
+	reflect.TypeOf(domain.ReportPeriod("")): values(
+		domain.ReportPeriodDaily, domain.ReportPeriodMonthly,
+	),
+	reflect.TypeOf(domain.WebhookEventType("")): values(domain.AllWebhookEventTypes...),
+	reflect.TypeOf(apperr.Code("")): values(
+		apperr.CodeValidation, apperr.CodeNotFound, apperr.CodeConflict, apperr.CodeUnauthorized,
+		apperr.CodeForbidden, apperr.CodeDependencyUnavailable, apperr.CodeIntegrity, apperr.CodeInternal,
+	),
+}
+
+func values[T ~string](vs ...T) []string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = string(v)
+	}
+	return out
+}
+
+func reasonCodes() []string {
+	var out []string
+	for _, info := range domain.AllReasonCodes() {
+		out = append(out, string(info.Code))
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// swaggerUIVersion pins the Swagger UI release the docs page loads
+const swaggerUIVersion = "5.17.14"
+
+var uiPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>AML Service API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.ui = SwaggerUIBundle({url: "{{.SpecURL}}", dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`))
+
+// Handler serves the document as JSON and a Swagger UI page for it. Both
+// are rendered once, when the handler is created.
+type Handler struct {
+	spec []byte
+	ui   []byte
+}
+
+// NewHandler renders doc and a Swagger UI page that loads it from specURL
+func NewHandler(doc *Document, specURL string) (*Handler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal openapi document: %w", err)
+	}
+
+	var ui strings.Builder
+	if err := uiPage.Execute(&ui, struct{ Version, SpecURL string }{swaggerUIVersion, specURL}); err != nil {
+		return nil, fmt.Errorf("render docs page: %w", err)
+	}
+
+	return &Handler{spec: spec, ui: []byte(ui.String())}, nil
+}
+
+// Spec serves the OpenAPI document
+func (h *Handler) Spec(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, h.spec)
+}
+
+// UI serves the Swagger UI page
+func (h *Handler) UI(c echo.Context) error {
+	return c.HTMLBlob(http.StatusOK, h.ui)
+}
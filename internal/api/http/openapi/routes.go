@@ -0,0 +1,305 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/banking/aml-service/internal/api/http/handlers"
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/breaker"
+)
+
+// tags describes the operation groups, in display order
+var tags = []Tag{
+	{Name: "Screening", Description: "Transaction and name screening"},
+	{Name: "Investigations", Description: "Case management, notes, evidence, entity graphs and user activity"},
+	{Name: "Alerts", Description: "Alert review"},
+	{Name: "Analysts", Description: "The auto-assignment roster"},
+	{Name: "Filings", Description: "SAR and CTR filings"},
+	{Name: "Watchlist", Description: "Users under enhanced monitoring, kept on their risk profiles"},
+	{Name: "Reports", Description: "Management information reports"},
+	{Name: "System", Description: "Circuit breakers, screening list versions and the audit chain"},
+	{Name: "Admin", Description: "List reloads, re-screens, the account denylist, webhook endpoints and the country risk table; needs the admin role"},
+	{Name: "Health", Description: "Liveness and readiness probes"},
+	{Name: "Docs", Description: "This document"},
+}
+
+// registerRoutes declares every route the server registers. Keep it in
+// step with the handlers' Register methods; the server logs any route
+// missing here at startup.
+func registerRoutes(b *builder) {
+	csv := &Schema{Type: "string", Description: "CSV with a header row"}
+	pagination := func(o *op) *op {
+		return o.
+			query("limit", "Page size, default 50, at most 200", intSchema).
+			query("offset", "Number of entries to skip", intSchema)
+	}
+	purposes := &Schema{Type: "string", Enum: values(
+		domain.AccessPurposeInvestigation, domain.AccessPurposeSARPreparation, domain.AccessPurposeQualityAssurance,
+		domain.AccessPurposeRegulatoryRequest, domain.AccessPurposeLawEnforcement, domain.AccessPurposeDataSubjectRequest,
+		domain.AccessPurposeInternalAudit,
+	)}
+	// auditedRead documents the parameters of a read recorded in the audit
+	// log; purpose is required for views of a SAR subject
+	auditedRead := func(o *op, purposeRequired bool) *op {
+		purpose := "Why the record is read, for the audit log"
+		if purposeRequired {
+			purpose += "; required"
+		}
+		return o.
+			query("actor_id", "Who is reading the record, for the audit log; required", uuidSchema).
+			query("purpose", purpose, purposes)
+	}
+
+	b.group("Screening")
+	b.op(http.MethodPost, "/api/v1/screening", "screenTransaction", "Screen a transaction").
+		body(domain.ScreeningRequest{}).
+		returns(http.StatusOK, "The screening decision", domain.ScreeningResponse{})
+	b.op(http.MethodPost, "/api/v1/screen/name", "screenName", "Screen a name against the OFAC and PEP lists").
+		body(domain.NameScreeningRequest{}).
+		returns(http.StatusOK, "The list matches", domain.NameScreeningResponse{})
+	b.op(http.MethodGet, "/api/v1/screening/reason-codes", "listReasonCodes", "List decision reason codes").
+		returns(http.StatusOK, "Every reason code with its description", []domain.ReasonCodeInfo{})
+	b.op(http.MethodGet, "/api/v1/screening/export", "exportScreenings", "Export screening results as CSV").
+		describe("Streams results created in [from, to). Exports over compliance.export_max_rows are refused, and every export is audit-logged.").
+		query("from", "Start of the range, RFC 3339", timeSchema).
+		query("to", "End of the range, exclusive, RFC 3339", timeSchema).
+		query("decision", "Only results with this decision", b.schemas.of(domain.ScreeningDecision(""))).
+		query("actor_id", "Who requested the export, for the audit log", uuidSchema).
+		returnsContent(http.StatusOK, "The results", "text/csv", csv)
+	b.op(http.MethodGet, "/api/v1/screening/:id", "getScreening", "Get a screening result").
+		returns(http.StatusOK, "The stored result", domain.ScreeningResult{})
+	b.op(http.MethodPost, "/api/v1/screening/:id/rescreen", "rescreen", "Re-screen a stored transaction against the current lists").
+		returns(http.StatusOK, "The original and new results and what changed", domain.RescreenResponse{})
+	b.op(http.MethodPost, "/api/v1/screening/batch", "createBatchScreening", "Queue a batch of names or users to screen").
+		body(domain.BatchScreeningRequest{}).
+		returns(http.StatusAccepted, "The queued job", domain.BatchJobResponse{})
+	b.op(http.MethodGet, "/api/v1/screening/batch/:job_id", "getBatchScreening", "Get a batch screening job").
+		returns(http.StatusOK, "The job's progress", domain.BatchJobResponse{})
+	b.op(http.MethodGet, "/api/v1/screening/batch/:job_id/hits.csv", "downloadBatchHits", "Download a batch job's hits as CSV").
+		returnsContent(http.StatusOK, "The names that matched a list", "text/csv", csv)
+
+	b.group("Investigations")
+	b.op(http.MethodPost, "/api/v1/investigations", "createInvestigation", "Open an investigation").
+		body(domain.CreateInvestigationRequest{}).
+		returns(http.StatusCreated, "The new investigation", domain.Investigation{})
+	b.op(http.MethodGet, "/api/v1/investigations", "listInvestigations", "List investigations").
+		query("status", "Only investigations in this status", b.schemas.of(domain.InvestigationStatus(""))).
+		query("priority", "Only investigations of this priority", b.schemas.of(domain.InvestigationPriority(""))).
+		query("assigned_to", "Only investigations assigned to this analyst", uuidSchema).
+		query("sla_breached", "Only investigations past or within their SLA", boolSchema).
+		query("created_from", "Created at or after, RFC 3339", timeSchema).
+		query("created_to", "Created before, RFC 3339", timeSchema).
+		query("limit", "Page size, default 50, at most 200", intSchema).
+		query("offset", "Number of entries to skip", intSchema).
+		returns(http.StatusOK, "A page of investigations", domain.InvestigationListResponse{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/investigations/:id/timeline", "getInvestigationTimeline", "Get an investigation's history"), false).
+		returns(http.StatusOK, "Events, oldest first", struct {
+			Events []*domain.InvestigationTimeline `json:"events"`
+		}{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/investigations/:id/notes", "listInvestigationNotes", "List an investigation's notes"), false).
+		query("include_internal", "Include internal notes", boolSchema).
+		returns(http.StatusOK, "Notes, oldest first", struct {
+			Notes []*domain.InvestigationNote `json:"notes"`
+		}{})
+	b.op(http.MethodPost, "/api/v1/investigations/:id/notes", "addInvestigationNote", "Add a note to an investigation").
+		body(domain.AddNoteRequest{}).
+		returns(http.StatusCreated, "The new note", domain.InvestigationNote{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/investigations/:id/graph", "getEntityGraph", "Get the linked-entity graph of an investigation's subject"), false).
+		returns(http.StatusOK, "The graph", domain.EntityGraph{})
+	pagination(b.op(http.MethodGet, "/api/v1/users/:user_id/activity", "getUserActivity", "Get everything known about a user for case review")).
+		query("since", "Only screenings, patterns, alerts, investigations and filings created at or after this time (RFC 3339 or YYYY-MM-DD)", stringSchema).
+		describe("Backs the case-review UI; the shape is stable. limit and offset page the screenings; alerts and filings hold at most 100 entries each and set truncated when there are more, and investigations hold at most 100 with total counting them all. Every section is always present and lists are never null; risk_profile is null when the user has not been assessed. The sections are read in parallel within server.user_activity_budget (2s); a request that runs out of time or fails to read any section is answered with 503 or 500, never with a partial picture.").
+		returns(http.StatusOK, "The user's activity", domain.UserActivity{})
+	b.op(http.MethodGet, "/api/v1/counterparties/:account/reputation", "getCounterpartyReputation", "Get an external account's reputation across all users").
+		describe("Counts of blocked and suspicious screenings and SAR filings the account has been party to, at each bank it was seen with, decayed to now with screening.counterparty.half_life. weight is the risk factor weight the reputation would add to a screening. An account never flagged has no reputations.").
+		returns(http.StatusOK, "The account's reputations, most recently active first", domain.CounterpartyReputationResponse{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/investigations/:id/evidence", "listEvidence", "List an investigation's evidence, including withdrawn items"), false).
+		returns(http.StatusOK, "The evidence", struct {
+			Evidence []domain.Evidence `json:"evidence"`
+		}{})
+	b.op(http.MethodPost, "/api/v1/investigations/:id/evidence", "uploadEvidence", "Upload an evidence file").
+		multipart(domain.AddEvidenceRequest{}, "file").
+		returns(http.StatusCreated, "The stored evidence", domain.Evidence{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/investigations/:id/evidence/:evidence_id/file", "downloadEvidence", "Download an evidence file"), false).
+		describe("The file is verified against its SHA-256 before it is returned.").
+		returnsContent(http.StatusOK, "The file, in its original content type", "application/octet-stream", binarySchema)
+	b.op(http.MethodDelete, "/api/v1/investigations/:id/evidence/:evidence_id", "withdrawEvidence", "Withdraw evidence").
+		body(domain.DeleteEvidenceRequest{}).
+		returns(http.StatusOK, "The withdrawn evidence; its file and hash are kept", domain.Evidence{})
+
+	b.group("Alerts")
+	b.op(http.MethodGet, "/api/v1/alerts/:id", "getAlert", "Get an alert").
+		returns(http.StatusOK, "The alert", domain.AMLAlert{})
+	b.op(http.MethodPost, "/api/v1/alerts/:id/review", "reviewAlert", "Start reviewing an alert").
+		body(domain.ReviewAlertRequest{}).
+		returns(http.StatusOK, "The alert", domain.AMLAlert{})
+	b.op(http.MethodPost, "/api/v1/alerts/:id/dismiss", "dismissAlert", "Dismiss an alert").
+		body(domain.DismissAlertRequest{}).
+		returns(http.StatusOK, "The alert", domain.AMLAlert{})
+	b.op(http.MethodPost, "/api/v1/alerts/:id/escalate", "escalateAlert", "Escalate an alert to an investigation").
+		body(domain.EscalateAlertRequest{}).
+		returns(http.StatusOK, "The alert and its investigation", domain.EscalateAlertResponse{})
+
+	b.group("Analysts")
+	b.op(http.MethodGet, "/api/v1/analysts", "listAnalysts", "List the roster with open caseloads").
+		returns(http.StatusOK, "The analysts", struct {
+			Analysts []*domain.Analyst `json:"analysts"`
+		}{})
+	b.op(http.MethodPut, "/api/v1/analysts/:id", "registerAnalyst", "Register an analyst or update their skills, caseload limit and digest settings").
+		describe("New analysts are subscribed to the morning digest, which is sent to email and lists new alerts of alert_types (every type when empty). Re-registering keeps the analyst's subscription.").
+		body(domain.RegisterAnalystRequest{}).
+		returns(http.StatusOK, "The analyst", domain.Analyst{})
+	b.op(http.MethodDelete, "/api/v1/analysts/:id", "deregisterAnalyst", "Remove an analyst from the roster").
+		returns(http.StatusNoContent, "Removed", nil)
+	b.op(http.MethodPut, "/api/v1/analysts/:id/preferences", "updateAnalystPreferences", "Subscribe an analyst to or unsubscribe them from the morning digest").
+		body(domain.AnalystPreferencesRequest{}).
+		returns(http.StatusNoContent, "Updated", nil)
+
+	b.group("Filings")
+	b.op(http.MethodPost, "/api/v1/filings/sar", "createSAR", "Draft a SAR").authenticated().
+		describe("The token's subject is the preparer; a prepared_by naming anyone else is refused with 403. A request that passes its validate tags but breaks a filing rule, such as an unknown activity category, is answered with 400 and the invalid fields in details.").
+		body(domain.CreateSARRequest{}).
+		returns(http.StatusCreated, "The draft filing", domain.RegulatoryFiling{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/filings/:id", "getFiling", "Get a filing"), true).
+		returns(http.StatusOK, "The filing, with subject PII redacted", domain.RegulatoryFiling{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/filings/:id/fincen.xml", "exportFinCEN", "Export a filing as FinCEN XML"), true).
+		returnsContent(http.StatusOK, "The filing", "application/xml", stringSchema)
+	b.op(http.MethodPost, "/api/v1/filings/:id/transition", "transitionFiling", "Move a filing to a new status").authenticated().
+		describe("The token's subject is the actor; actor_id may be left out, and naming anyone else is refused with 403. A filing in REVIEW cannot be moved on by its preparer (403).").
+		body(domain.FilingTransitionRequest{}).
+		returns(http.StatusOK, "The filing and the recorded transition", domain.FilingTransitionResponse{})
+	b.op(http.MethodGet, "/api/v1/filings/:id/transitions", "listFilingTransitions", "List a filing's status changes").
+		returns(http.StatusOK, "Transitions, oldest first", []domain.FilingTransition{})
+	b.op(http.MethodPost, "/api/v1/filings/:id/narrative/draft", "draftNarrative", "Draft a SAR narrative from the filing's evidence").authenticated().
+		describe("The token's subject is the actor; actor_id may be left out, and naming anyone else is refused with 403.").
+		body(domain.DraftNarrativeRequest{}).
+		returns(http.StatusOK, "The filing with its drafted narrative", domain.RegulatoryFiling{})
+	b.op(http.MethodPost, "/api/v1/filings/:id/amend", "amendFiling", "Open a draft amendment of a filed SAR or CTR").authenticated().
+		describe("The token's subject prepares the amendment; actor_id may be left out, and naming anyone else is refused with 403.").
+		body(domain.AmendFilingRequest{}).
+		returns(http.StatusCreated, "The draft amendment", domain.RegulatoryFiling{})
+	auditedRead(b.op(http.MethodGet, "/api/v1/filings/:id/history", "getFilingHistory", "Get the amendment chain containing a filing"), true).
+		returns(http.StatusOK, "Filings, original first", []domain.RegulatoryFiling{})
+
+	b.group("Watchlist")
+	pagination(b.op(http.MethodGet, "/api/v1/watchlist", "listWatchlist", "List watchlisted users")).
+		returns(http.StatusOK, "A page of entries", domain.WatchlistListResponse{})
+	b.op(http.MethodPost, "/api/v1/watchlist/:user_id", "addToWatchlist", "Put a user on the watchlist").
+		body(domain.WatchlistChangeRequest{}).
+		returns(http.StatusOK, "The user's risk profile", domain.UserRiskProfile{})
+	b.op(http.MethodDelete, "/api/v1/watchlist/:user_id", "removeFromWatchlist", "Take a user off the watchlist").authenticated().
+		describe("The token's subject is the actor and must be a supervisor; actor_id may be left out, and naming anyone else is refused with 403.").
+		body(domain.WatchlistChangeRequest{}).
+		returns(http.StatusOK, "The user's risk profile", domain.UserRiskProfile{})
+
+	b.group("Reports")
+	for _, period := range []struct{ name, date string }{
+		{"daily", "Day as YYYY-MM-DD, default yesterday"},
+		{"monthly", "Month as YYYY-MM, default last month"},
+	} {
+		id := period.name + "Report"
+		b.op(http.MethodGet, "/api/v1/reports/"+period.name, id, "Get the "+period.name+" MI report").
+			query("date", period.date, stringSchema).
+			returns(http.StatusOK, "The report; the current period is provisional", domain.MIReport{})
+		b.op(http.MethodGet, "/api/v1/reports/"+period.name+".csv", id+"CSV", "Get the "+period.name+" MI report as CSV").
+			query("date", period.date, stringSchema).
+			returnsContent(http.StatusOK, "The report as metric,value rows", "text/csv", csv)
+	}
+
+	b.group("System")
+	b.op(http.MethodGet, "/api/v1/system/breakers", "listBreakers", "List dependency circuit breakers").
+		returns(http.StatusOK, "Each breaker's state", []breaker.Status{})
+	b.op(http.MethodGet, "/api/v1/lists/status", "getListStatus", "Get the source, version and freshness of the OFAC and PEP lists").
+		describe("version is stamped on screening results as ofac_list_version and pep_list_version, and hash, a SHA-256 over the entries, as ofac_list_hash and pep_list_hash").
+		returns(http.StatusOK, "Each list", []handlers.ListStatus{})
+	b.op(http.MethodGet, "/api/v1/audit/access", "getPIIAccessReport", "List who read a data subject's filings and investigations").admin().
+		describe("For data subject access requests. Every read of a filing or investigation detail endpoint is recorded with its actor, purpose and whether subject PII was shown in full or redacted; screening reads are not recorded.").
+		query("user_id", "The data subject; required", uuidSchema).
+		query("from", "Start of the range, RFC 3339 or YYYY-MM-DD", stringSchema).
+		query("to", "End of the range, exclusive, RFC 3339 or YYYY-MM-DD; defaults to now", stringSchema).
+		returns(http.StatusOK, "Reads of the user's data, oldest first", domain.PIIAccessReport{})
+	b.op(http.MethodGet, "/api/v1/audit/verify", "verifyAuditChain", "Verify the audit log's hash chain").
+		returns(http.StatusOK, "The verification result", audit.VerifyReport{})
+
+	b.group("Admin")
+	b.op(http.MethodGet, "/api/v1/admin/lists", "getListVersions", "Get the version, entry count, load time and hash of this instance's OFAC and PEP lists").admin().
+		returns(http.StatusOK, "Each list", []handlers.ListVersionResponse{})
+	b.op(http.MethodPost, "/api/v1/admin/reload/ofac", "reloadOFAC", "Reload this instance's OFAC index").admin().
+		returns(http.StatusOK, "The reloaded index", handlers.IndexReloadResponse{})
+	b.op(http.MethodPost, "/api/v1/admin/reload/pep", "reloadPEP", "Reload this instance's PEP index").admin().
+		returns(http.StatusOK, "The reloaded index", handlers.IndexReloadResponse{})
+	b.op(http.MethodPost, "/api/v1/admin/rescreen/retroactive", "startRetroactiveRescreen", "Re-screen stored transactions against the current lists or archived list versions").admin().
+		describe("ofac_list_version and pep_list_version pin the run to archived list snapshots; the run is refused if either is not retained").
+		body(domain.RetroactiveRescreenRequest{}).
+		returns(http.StatusAccepted, "The queued run", domain.RetroactiveRescreenRun{})
+	b.op(http.MethodGet, "/api/v1/admin/rescreen/retroactive", "getRetroactiveRescreen", "Get the progress of the current or last retroactive re-screen").admin().
+		returns(http.StatusOK, "The run", domain.RetroactiveRescreenRun{})
+	b.op(http.MethodGet, "/api/v1/admin/account-denylist", "listAccountDenylist", "List denylisted accounts").admin().
+		returns(http.StatusOK, "Entries, most recently added first", domain.AccountDenylistListResponse{})
+	b.op(http.MethodPost, "/api/v1/admin/account-denylist/:account", "addToAccountDenylist", "Denylist an account number or IBAN").admin().
+		body(domain.AccountDenylistChangeRequest{}).
+		returns(http.StatusOK, "The entry", domain.AccountDenylistEntry{})
+	b.op(http.MethodDelete, "/api/v1/admin/account-denylist/:account", "removeFromAccountDenylist", "Take an account off the denylist").admin().
+		body(domain.AccountDenylistChangeRequest{}).
+		returns(http.StatusOK, "The entry", domain.AccountDenylistEntry{})
+	b.op(http.MethodPost, "/api/v1/admin/webhooks", "createWebhookEndpoint", "Register a webhook endpoint").admin().
+		describe("Deliveries are signed with HMAC-SHA256 under the secret in the X-AML-Signature header, t=<unix seconds>,v1=<hex digest of \"<t>.<body>\">. An endpoint is disabled after webhooks.disable_after_failures undeliverable events in a row and a system alert raised.").
+		body(domain.CreateWebhookEndpointRequest{}).
+		returns(http.StatusCreated, "The endpoint", domain.WebhookEndpoint{})
+	b.op(http.MethodGet, "/api/v1/admin/webhooks", "listWebhookEndpoints", "List webhook endpoints").admin().
+		returns(http.StatusOK, "Endpoints by name, configured ones included", domain.WebhookEndpointListResponse{})
+	b.op(http.MethodGet, "/api/v1/admin/webhooks/:id", "getWebhookEndpoint", "Get a webhook endpoint").admin().
+		returns(http.StatusOK, "The endpoint", domain.WebhookEndpoint{})
+	b.op(http.MethodDelete, "/api/v1/admin/webhooks/:id", "deleteWebhookEndpoint", "Delete a webhook endpoint registered through the API").admin().
+		body(domain.WebhookEndpointChangeRequest{}).
+		returns(http.StatusNoContent, "Deleted", nil)
+	b.op(http.MethodPost, "/api/v1/admin/webhooks/:id/enable", "enableWebhookEndpoint", "Re-enable a webhook endpoint and clear its failure count").admin().
+		body(domain.WebhookEndpointChangeRequest{}).
+		returns(http.StatusOK, "The endpoint", domain.WebhookEndpoint{})
+	pagination(b.op(http.MethodGet, "/api/v1/admin/webhooks/:id/deliveries", "listWebhookDeliveries", "List a webhook endpoint's delivery attempts").admin().
+		query("event_id", "Only attempts to deliver this event", uuidSchema)).
+		returns(http.StatusOK, "Attempts, newest first", domain.WebhookDeliveryListResponse{})
+	b.op(http.MethodPost, "/api/v1/admin/webhooks/events/:id/replay", "replayWebhookEvent", "Re-send a webhook event").admin().
+		describe("Queues the stored event again under its original X-AML-Event-ID, to the given endpoint or to every enabled endpoint subscribed to its type.").
+		body(domain.ReplayWebhookEventRequest{}).
+		returns(http.StatusAccepted, "The endpoints the event was queued for", domain.ReplayWebhookEventResponse{})
+	b.op(http.MethodGet, "/api/v1/admin/country-risk", "getCountryRiskTable", "Get the country risk table in force").admin().
+		describe("Version 0 is the table in patterns.country_risk_tiers, country_risk_ratings and country_risk_rating_points, in force until a table is saved. Screening results record the version applied as country_risk_version.").
+		returns(http.StatusOK, "The table", domain.CountryRiskTable{})
+	b.op(http.MethodPut, "/api/v1/admin/country-risk", "updateCountryRiskTable", "Save a new version of the country risk table").admin().
+		describe("The new version is in force on this instance at once and on the others within patterns.country_risk_refresh_interval. base_version must be the newest version, or the save is rejected with 409. A tier with edd_required set applies the edd decision thresholds. Ratings grade countries outside the tiers from 0 to 100; a rated counterparty country adds its rating's share of rating_points.").
+		body(domain.UpdateCountryRiskTableRequest{}).
+		returns(http.StatusOK, "The saved version", domain.CountryRiskTable{})
+	pagination(b.op(http.MethodGet, "/api/v1/admin/country-risk/versions", "listCountryRiskTables", "List saved versions of the country risk table").admin()).
+		returns(http.StatusOK, "Versions, newest first", domain.CountryRiskTableListResponse{})
+	b.op(http.MethodGet, "/api/v1/admin/country-risk/versions/:version", "getCountryRiskTableVersion", "Get a version of the country risk table").admin().
+		returns(http.StatusOK, "The table", domain.CountryRiskTable{})
+	b.op(http.MethodGet, "/api/v1/admin/tenants/:id/settings", "getTenantSettings", "Get a tenant's screening and reporting overrides").admin().
+		describe("Returns the tenant's stored overrides, if any, and the rules in force for it with the global configuration filling the gaps. Callers acting for another tenant get 403; tenants not in tenancy.tenants get 404.").
+		returns(http.StatusOK, "The overrides and effective rules", domain.TenantSettingsResponse{})
+	b.op(http.MethodPut, "/api/v1/admin/tenants/:id/settings", "updateTenantSettings", "Replace a tenant's screening and reporting overrides").admin().
+		describe("Overrides the decision thresholds of the risk tiers named, the country risk tiers, and the SAR and CTR thresholds; anything left out falls back to the global configuration. The change is in force on this instance at once and on the others within tenancy.settings_refresh_interval.").
+		body(domain.UpdateTenantSettingsRequest{}).
+		returns(http.StatusOK, "The overrides and effective rules", domain.TenantSettingsResponse{})
+
+	b.group("Health")
+	status := struct {
+		Status string `json:"status"`
+	}{}
+	b.op(http.MethodGet, "/health", "health", "Liveness probe").
+		returns(http.StatusOK, "The process is up", status)
+	b.op(http.MethodGet, "/health/live", "liveness", "Liveness probe").
+		returns(http.StatusOK, "The process is up", status)
+	b.op(http.MethodGet, "/health/ready", "readiness", "Readiness probe").
+		returns(http.StatusOK, "Ready, possibly degraded", handlers.ReadinessResponse{}).
+		returns(http.StatusServiceUnavailable, "Not ready", handlers.ReadinessResponse{})
+	b.op(http.MethodGet, "/health/screening", "screeningHealth", "State and staleness of the list indexes").
+		returns(http.StatusOK, "The indexes", handlers.ScreeningHealth{})
+
+	b.group("Docs")
+	b.op(http.MethodGet, "/api/v1/openapi.json", "getOpenAPI", "Get this document").
+		returnsContent(http.StatusOK, "The OpenAPI document", "application/json", &Schema{Type: "object"})
+	b.op(http.MethodGet, "/docs", "docs", "Browse this document in Swagger UI").admin().
+		returnsContent(http.StatusOK, "The Swagger UI page", "text/html", stringSchema)
+}
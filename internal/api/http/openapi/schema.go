@@ -0,0 +1,240 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/pkg/apperr"
+	"github.com/banking/aml-service/internal/pkg/breaker"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+	amountType   = reflect.TypeOf(money.Amount(0))
+	rawJSONType  = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// schemas generates schemas from Go types. Named structs and enum types
+// become components referenced by name.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// of returns the schema of v's type, or nil for a nil v
+func (s *schemas) of(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	return s.forType(reflect.TypeOf(v))
+}
+
+func (s *schemas) forType(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "duration in nanoseconds"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case amountType:
+		return &Schema{Type: "number", Description: "exact decimal amount, up to 4 decimal places"}
+	case rawJSONType:
+		return &Schema{}
+	}
+	if values, ok := enums[t]; ok {
+		return s.component(t, func() *Schema {
+			return &Schema{Type: "string", Enum: values}
+		})
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem := s.forType(t.Elem())
+		if elem.Ref != "" {
+			// $ref siblings are ignored in OpenAPI 3.0, so the ref is
+			// left bare
+			return elem
+		}
+		elem.Nullable = true
+		return elem
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return s.component(t, func() *Schema { return s.object(t) })
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.forType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.forType(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	}
+	return &Schema{}
+}
+
+// component registers the schema built by build under t's name and returns
+// a reference to it. The name is registered first so recursive types end.
+func (s *schemas) component(t reflect.Type, build func() *Schema) *Schema {
+	name, ok := s.names[t]
+	if !ok {
+		name = s.nameFor(t)
+		s.names[t] = name
+		s.components[name] = build()
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentNames renames types whose own names are too generic to stand
+// alone in the document
+var componentNames = map[reflect.Type]string{
+	reflect.TypeOf(apperr.Code("")):  "ErrorCode",
+	reflect.TypeOf(breaker.Status{}): "BreakerStatus",
+}
+
+// nameFor names a component after its type, prefixing the package name
+// when another package's type already took the name
+func (s *schemas) nameFor(t reflect.Type) string {
+	if name, ok := componentNames[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := s.components[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = string(unicode.ToUpper(rune(pkg[0]))) + pkg[1:] + name
+	}
+	return name
+}
+
+// object builds the schema of a struct from its exported fields. Embedded
+// structs without a json name are inlined, as encoding/json does.
+func (s *schemas) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.addFields(obj, t)
+	return obj
+}
+
+func (s *schemas) addFields(obj *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			// Multipart DTOs are named by their form tags
+			name, _, _ = strings.Cut(f.Tag.Get("form"), ",")
+		}
+		if name == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.addFields(obj, embedded)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := s.forType(f.Type)
+		if applyValidateTag(prop, f.Tag.Get("validate")) {
+			obj.Required = append(obj.Required, name)
+		}
+		obj.Properties[name] = prop
+	}
+}
+
+// applyValidateTag adds the constraints of a validate tag to a property
+// schema and reports whether the tag makes the field required. Constraints
+// are not added to references, whose siblings OpenAPI 3.0 ignores.
+func applyValidateTag(prop *Schema, tag string) (required bool) {
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			// Later rules apply to the elements
+			return required
+		case "oneof":
+			if prop.Ref == "" {
+				prop.Enum = strings.Fields(param)
+			}
+		case "min", "max", "gt":
+			if prop.Ref == "" {
+				applyBound(prop, name, param)
+			}
+		}
+	}
+	return required
+}
+
+func applyBound(prop *Schema, rule, param string) {
+	switch prop.Type {
+	case "string":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if rule == "max" {
+			prop.MaxLength = &n
+		} else {
+			prop.MinLength = &n
+		}
+	case "array":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if rule == "max" {
+			prop.MaxItems = &n
+		} else {
+			prop.MinItems = &n
+		}
+	case "integer", "number":
+		f, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		if rule == "max" {
+			prop.Maximum = &f
+		} else {
+			prop.Minimum = &f
+			prop.ExclusiveMinimum = rule == "gt"
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package openapi describes the HTTP API as an OpenAPI 3 document. The
+// operations are maintained by hand in routes.go; request and response
+// schemas are generated from the Go types the handlers bind and return, so
+// they follow the DTOs' json and validate tags, and enum types list the
+// values defined in the domain package.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Version is the OpenAPI version the document conforms to
+const Version = "3.0.3"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL the API is served from
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations on one path, keyed by lower-case method
+type PathItem map[string]*Operation
+
+// Operation is one method on a path
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body an operation accepts
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is one response an operation may return
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how callers authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Schema is a JSON schema, in the subset OpenAPI 3.0 supports
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// bearerAuth names the JWT security scheme
+const bearerAuth = "bearerAuth"
+
+// New builds the document
+func New() *Document {
+	b := newBuilder()
+	registerRoutes(b)
+
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "AML Service API",
+			Description: "Transaction screening, alerts, investigations and regulatory filings. Errors are returned as RFC 7807 application/problem+json Problem objects.",
+			Version:     "v1",
+		},
+		Servers: []Server{{URL: "/"}},
+		Tags:    tags,
+		Paths:   b.paths,
+		Components: Components{
+			Schemas: b.schemas.components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuth: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "A JWT signed with security.jwt_secret whose roles claim includes the role the operation needs",
+				},
+			},
+		},
+	}
+}
+
+// pathParam matches an Echo path parameter such as :id
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// specPath converts an Echo path to an OpenAPI path, e.g. /alerts/:id to
+// /alerts/{id}
+func specPath(echoPath string) string {
+	return pathParam.ReplaceAllString(echoPath, "{$1}")
+}
+
+// Undocumented returns the registered routes that have no operation in the
+// document, as "METHOD /path". Echo's internal not-found routes are ignored.
+func (d *Document) Undocumented(routes []*echo.Route) []string {
+	var missing []string
+	for _, r := range routes {
+		if !isHTTPMethod(r.Method) {
+			continue
+		}
+		item := d.Paths[specPath(r.Path)]
+		if item == nil || (*item)[strings.ToLower(r.Method)] == nil {
+			missing = append(missing, r.Method+" "+r.Path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func isHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/api/http/handlers"
+	amlmiddleware "github.com/banking/aml-service/internal/api/http/middleware"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// routes registers every handler on the paths cmd/server mounts them on.
+// Handlers only read their dependencies when serving, so none are needed.
+func routes(t *testing.T) []*echo.Route {
+	t.Helper()
+	log := logger.NewNop()
+	screeningCfg := &config.ScreeningConfig{}
+	e := echo.New()
+
+	handlers.NewHealthHandler(nil, nil, nil, screeningCfg).Register(e.Group("/health"))
+
+	api := e.Group("/api/v1")
+	handlers.NewScreeningHandler(nil, nil, nil, log).Register(api)
+	handlers.NewBatchScreeningHandler(nil, log).Register(api)
+	handlers.NewInvestigationHandler(nil, nil, nil, log).Register(api)
+	handlers.NewEntityGraphHandler(nil, log).Register(api)
+	handlers.NewUserActivityHandler(nil, log).Register(api)
+	handlers.NewCounterpartyHandler(nil, log).Register(api)
+	handlers.NewEvidenceHandler(nil, 1<<20, log).Register(api)
+	handlers.NewAnalystHandler(nil, log).Register(api)
+	handlers.NewFilingHandler(nil, amlmiddleware.RequireToken(""), log).Register(api)
+	handlers.NewAlertHandler(nil, log).Register(api)
+	handlers.NewSystemHandler(nil).Register(api)
+	handlers.NewListStatusHandler(nil, nil, screeningCfg, log).Register(api)
+	auditHandler := handlers.NewAuditHandler(nil, nil, log)
+	auditHandler.Register(api)
+	handlers.NewWatchlistHandler(nil, amlmiddleware.RequireToken(""), log).Register(api)
+	handlers.NewReportHandler(nil, log).Register(api)
+
+	admin := api.Group("/admin")
+	handlers.NewAdminHandler(nil, nil, nil, log).Register(admin)
+	handlers.NewAccountDenylistHandler(nil, log).Register(admin)
+	handlers.NewWebhookHandler(nil, log).Register(admin)
+	handlers.NewCountryRiskHandler(nil, log).Register(admin)
+	handlers.NewTenantSettingsHandler(nil, log).Register(admin)
+	api.GET("/audit/access", auditHandler.AccessReport)
+
+	docs, err := NewHandler(New(), "/api/v1/openapi.json")
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	api.GET("/openapi.json", docs.Spec)
+	e.GET("/docs", docs.UI)
+
+	return e.Routes()
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	if missing := New().Undocumented(routes(t)); len(missing) > 0 {
+		t.Errorf("routes missing from the OpenAPI document: %v", missing)
+	}
+}
@@ -0,0 +1,189 @@
+package validation
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	uuidType   = reflect.TypeOf(uuid.UUID{})
+	amountType = reflect.TypeOf(money.Amount(0))
+)
+
+// isRequired rejects zero values. Structs other than time.Time always
+// pass; their own fields carry the rules.
+func isRequired(f Field) bool {
+	switch {
+	case f.Value.Kind() == reflect.Ptr || f.Value.Kind() == reflect.Interface:
+		return !f.Value.IsNil()
+	case f.Value.Kind() == reflect.Struct && f.Value.Type() != timeType:
+		return true
+	case f.Value.Kind() == reflect.Slice || f.Value.Kind() == reflect.Map:
+		return f.Value.Len() > 0
+	}
+	return !f.Value.IsZero()
+}
+
+func isMin(f Field) bool {
+	c, ok := compare(f.Value, f.Param)
+	return ok && c >= 0
+}
+
+func isMax(f Field) bool {
+	c, ok := compare(f.Value, f.Param)
+	return ok && c <= 0
+}
+
+func isGreater(f Field) bool {
+	c, ok := compare(f.Value, f.Param)
+	return ok && c > 0
+}
+
+// compare compares the size of v with param: the length of strings, in
+// characters, and of slices and maps, and the value of numbers. money
+// Amounts are compared with param as a decimal amount. ok is false if v
+// has no size or param does not parse.
+func compare(v reflect.Value, param string) (c int, ok bool) {
+	switch v.Kind() {
+	case reflect.String:
+		n, err := strconv.Atoi(param)
+		return sign(int64(utf8.RuneCountInString(v.String())) - int64(n)), err == nil
+	case reflect.Slice, reflect.Map, reflect.Array:
+		n, err := strconv.Atoi(param)
+		return sign(int64(v.Len()) - int64(n)), err == nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == amountType {
+			a, err := money.Parse(param)
+			return sign(v.Int() - int64(a)), err == nil
+		}
+		n, err := strconv.ParseInt(param, 10, 64)
+		return sign(v.Int() - n), err == nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(param, 10, 64)
+		switch {
+		case v.Uint() < n:
+			return -1, err == nil
+		case v.Uint() > n:
+			return 1, err == nil
+		}
+		return 0, err == nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(param, 64)
+		switch {
+		case v.Float() < n:
+			return -1, err == nil
+		case v.Float() > n:
+			return 1, err == nil
+		}
+		return 0, err == nil
+	}
+	return 0, false
+}
+
+func sign(n int64) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// isOneOf accepts strings and integers listed in the space-separated param
+func isOneOf(f Field) bool {
+	var s string
+	switch f.Value.Kind() {
+	case reflect.String:
+		s = f.Value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(f.Value.Int(), 10)
+	default:
+		return false
+	}
+
+	for _, allowed := range strings.Fields(f.Param) {
+		if s == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// isUUIDNotNil rejects the nil UUID. It is used on optional IDs, where
+// required does not apply but an explicit nil UUID is a client error.
+func isUUIDNotNil(f Field) bool {
+	if f.Value.Type() != uuidType {
+		return f.Value.Kind() == reflect.Ptr && f.Value.IsNil()
+	}
+	return f.Value.Interface().(uuid.UUID) != uuid.Nil
+}
+
+// isCurrencyCode accepts active ISO 4217 codes in either case, as the
+// currency converter upper-cases them
+func isCurrencyCode(f Field) bool {
+	return f.Value.Kind() == reflect.String && currencyCodes[strings.ToUpper(f.Value.String())]
+}
+
+// isCountryCode accepts ISO 3166-1 alpha-2 codes in either case
+func isCountryCode(f Field) bool {
+	return f.Value.Kind() == reflect.String && countryCodes[strings.ToUpper(f.Value.String())]
+}
+
+// isBIC accepts 8- and 11-character SWIFT BICs in either case
+func isBIC(f Field) bool {
+	return f.Value.Kind() == reflect.String && domain.ValidBIC(f.Value.String())
+}
+
+// isDateAfter requires a time to be after the time in the sibling field
+// named by param. It passes when the other time is unset, leaving that to
+// the other field's own rules.
+func isDateAfter(f Field) bool {
+	other := indirect(f.Parent.FieldByName(f.Param))
+	if f.Value.Type() != timeType || !other.IsValid() || other.Type() != timeType {
+		return false
+	}
+
+	after := other.Interface().(time.Time)
+	return after.IsZero() || f.Value.Interface().(time.Time).After(after)
+}
+
+func minMessage(f Field) string {
+	return "must be at least " + f.Param + unit(f.Value)
+}
+
+func maxMessage(f Field) string {
+	return "must be at most " + f.Param + unit(f.Value)
+}
+
+func gtMessage(f Field) string {
+	return "must be greater than " + f.Param + unit(f.Value)
+}
+
+// unit names what a size rule counts for v
+func unit(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return " items"
+	}
+	return ""
+}
+
+func dateAfterMessage(f Field) string {
+	name := f.Param
+	if sf, ok := f.Parent.Type().FieldByName(f.Param); ok {
+		name = fieldName(sf)
+	}
+	return "must be after " + name
+}
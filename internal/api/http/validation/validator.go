@@ -0,0 +1,223 @@
+// Package validation checks request DTOs against their validate struct
+// tags. It is installed as the Echo validator, so handlers run it with
+// c.Validate after binding a request body.
+//
+// Tags follow the go-playground/validator syntax for the rules this service
+// uses: required, omitempty, min, max, gt, oneof and dive, plus the custom
+// rules registered by New. Every invalid field is reported at once as
+// domain.FieldErrors, named by its JSON path.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// Field is the value a rule checks
+type Field struct {
+	// Value is the field's value, with pointers dereferenced
+	Value reflect.Value
+	// Param is the rule's parameter, e.g. "5" in min=5
+	Param string
+	// Parent is the struct holding the field, for rules comparing fields
+	Parent reflect.Value
+}
+
+// Rule checks a field, returning false if it is invalid
+type Rule func(f Field) bool
+
+// Message describes a failed rule to the caller
+type Message func(f Field) string
+
+type rule struct {
+	check   Rule
+	message Message
+}
+
+// Validator checks structs against their validate tags. It implements
+// echo.Validator.
+type Validator struct {
+	rules map[string]rule
+
+	// fields caches the parsed tags of each struct type
+	fields sync.Map // reflect.Type -> []structField
+}
+
+// New creates a validator with the built-in and custom rules registered
+func New() *Validator {
+	v := &Validator{rules: make(map[string]rule)}
+
+	v.Register("required", isRequired, fixed("is required"))
+	v.Register("min", isMin, minMessage)
+	v.Register("max", isMax, maxMessage)
+	v.Register("gt", isGreater, gtMessage)
+	v.Register("oneof", isOneOf, func(f Field) string {
+		return "must be one of " + strings.Join(strings.Fields(f.Param), ", ")
+	})
+
+	v.Register("uuid_not_nil", isUUIDNotNil, fixed("must not be the nil UUID"))
+	v.Register("iso4217", isCurrencyCode, fixed("must be an ISO 4217 currency code"))
+	v.Register("iso3166_alpha2", isCountryCode, fixed("must be an ISO 3166-1 alpha-2 country code"))
+	v.Register("bic", isBIC, fixed("must be an ISO 9362 BIC"))
+	v.Register("date_after", isDateAfter, dateAfterMessage)
+
+	return v
+}
+
+// Register adds a rule under name, replacing any rule of the same name.
+// Rules must be registered before the validator is used.
+func (v *Validator) Register(name string, check Rule, message Message) {
+	v.rules[name] = rule{check: check, message: message}
+}
+
+// Validate checks i, which must be a struct or a pointer to one. It
+// returns domain.FieldErrors listing every invalid field, or nil.
+func (v *Validator) Validate(i interface{}) error {
+	val := reflect.ValueOf(i)
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: expected a struct, got %T", i)
+	}
+
+	var errs domain.FieldErrors
+	if err := v.validateStruct(val, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// structField is a field of a struct type with its parsed validate tag
+type structField struct {
+	index int
+	name  string
+	tags  []tag
+}
+
+type tag struct {
+	name  string
+	param string
+}
+
+func (v *Validator) validateStruct(val reflect.Value, prefix string, errs *domain.FieldErrors) error {
+	for _, sf := range v.structFields(val.Type()) {
+		if err := v.validateField(val.Field(sf.index), val, prefix+sf.name, sf.tags, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateField applies tags to a field, then descends into it if it is a
+// struct. Nested structs are validated whether or not the field is tagged.
+func (v *Validator) validateField(fv, parent reflect.Value, path string, tags []tag, errs *domain.FieldErrors) error {
+	for i, t := range tags {
+		switch t.name {
+		case "omitempty":
+			if fv.IsZero() {
+				return nil
+			}
+			continue
+		case "dive":
+			elem := indirect(fv)
+			if elem.Kind() != reflect.Slice && elem.Kind() != reflect.Array {
+				return fmt.Errorf("validate: dive on non-slice field %s", path)
+			}
+			for j := 0; j < elem.Len(); j++ {
+				if err := v.validateField(elem.Index(j), parent, fmt.Sprintf("%s[%d]", path, j), tags[i+1:], errs); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		r, ok := v.rules[t.name]
+		if !ok {
+			return fmt.Errorf("validate: unknown rule %q on field %s", t.name, path)
+		}
+		f := Field{Value: indirect(fv), Param: t.param, Parent: parent}
+		if !r.check(f) {
+			*errs = append(*errs, domain.FieldError{Field: path, Message: r.message(f)})
+			// Later rules usually restate the same problem, e.g. min
+			// after required on an empty string
+			return nil
+		}
+	}
+
+	if nested := indirect(fv); nested.Kind() == reflect.Struct && nested.Type() != timeType {
+		return v.validateStruct(nested, path+".", errs)
+	}
+	return nil
+}
+
+// structFields returns the exported fields of t that are tagged or may
+// hold tagged structs
+func (v *Validator) structFields(t reflect.Type) []structField {
+	if cached, ok := v.fields.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		raw, tagged := f.Tag.Lookup("validate")
+		if raw == "-" || (!tagged && !mayNest(f.Type)) {
+			continue
+		}
+
+		var tags []tag
+		if raw != "" {
+			for _, part := range strings.Split(raw, ",") {
+				name, param, _ := strings.Cut(part, "=")
+				tags = append(tags, tag{name: name, param: param})
+			}
+		}
+		fields = append(fields, structField{index: i, name: fieldName(f), tags: tags})
+	}
+
+	v.fields.Store(t, fields)
+	return fields
+}
+
+// fieldName returns the name a client knows a field by: its JSON or form
+// key, falling back to the Go name
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form", "query"} {
+		if name, _, _ := strings.Cut(f.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// mayNest reports whether a field of type t may hold a struct to descend into
+func mayNest(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+// indirect dereferences pointers, stopping at nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func fixed(message string) Message {
+	return func(Field) string { return message }
+}
@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+type request struct {
+	ReviewerID *uuid.UUID `json:"reviewer_id" validate:"omitempty,uuid_not_nil"`
+	AnalystID  uuid.UUID  `json:"analyst_id" validate:"uuid_not_nil"`
+	Currency   string     `json:"currency" validate:"iso4217"`
+	Country    string     `json:"country" validate:"omitempty,iso3166_alpha2"`
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to" validate:"date_after=From"`
+}
+
+func validRequest() request {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return request{
+		AnalystID: uuid.New(),
+		Currency:  "EUR",
+		Country:   "DE",
+		From:      from,
+		To:        from.Add(24 * time.Hour),
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	nilID := uuid.Nil
+	tests := []struct {
+		name    string
+		modify  func(r *request)
+		field   string
+		message string
+	}{
+		{name: "valid"},
+		{name: "lower-case codes", modify: func(r *request) { r.Currency, r.Country = "usd", "gb" }},
+		{name: "unset optional ID", modify: func(r *request) { r.ReviewerID = nil }},
+		{name: "unset from date", modify: func(r *request) { r.From = time.Time{} }},
+		{
+			name:    "nil UUID",
+			modify:  func(r *request) { r.AnalystID = uuid.Nil },
+			field:   "analyst_id",
+			message: "must not be the nil UUID",
+		},
+		{
+			name:    "explicit nil optional ID",
+			modify:  func(r *request) { r.ReviewerID = &nilID },
+			field:   "reviewer_id",
+			message: "must not be the nil UUID",
+		},
+		{
+			name:    "unknown currency",
+			modify:  func(r *request) { r.Currency = "EURO" },
+			field:   "currency",
+			message: "must be an ISO 4217 currency code",
+		},
+		{
+			name:    "testing currency",
+			modify:  func(r *request) { r.Currency = "XTS" },
+			field:   "currency",
+			message: "must be an ISO 4217 currency code",
+		},
+		{
+			name:    "alpha-3 country",
+			modify:  func(r *request) { r.Country = "DEU" },
+			field:   "country",
+			message: "must be an ISO 3166-1 alpha-2 country code",
+		},
+		{
+			name:    "unassigned country",
+			modify:  func(r *request) { r.Country = "UK" },
+			field:   "country",
+			message: "must be an ISO 3166-1 alpha-2 country code",
+		},
+		{
+			name:    "same date",
+			modify:  func(r *request) { r.To = r.From },
+			field:   "to",
+			message: "must be after from",
+		},
+		{
+			name:    "earlier date",
+			modify:  func(r *request) { r.To = r.From.Add(-time.Hour) },
+			field:   "to",
+			message: "must be after from",
+		},
+	}
+	v := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validRequest()
+			if tt.modify != nil {
+				tt.modify(&r)
+			}
+			err := v.Validate(&r)
+
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			var fields domain.FieldErrors
+			if !errors.As(err, &fields) {
+				t.Fatalf("Validate = %v, want FieldErrors", err)
+			}
+			want := domain.FieldError{Field: tt.field, Message: tt.message}
+			if len(fields) != 1 || fields[0] != want {
+				t.Errorf("Validate = %+v, want %+v", fields, want)
+			}
+		})
+	}
+}
+
+func TestDateAfterNeedsTimes(t *testing.T) {
+	type misconfigured struct {
+		From string    `json:"from"`
+		To   time.Time `json:"to" validate:"date_after=From"`
+	}
+	err := New().Validate(misconfigured{From: "2026-01-01", To: time.Now()})
+	var fields domain.FieldErrors
+	if !errors.As(err, &fields) || len(fields) != 1 || fields[0].Field != "to" {
+		t.Errorf("Validate = %v, want to rejected when from is not a time", err)
+	}
+}
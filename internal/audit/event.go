@@ -0,0 +1,120 @@
+// Package audit records compliance-relevant actions in a tamper-evident log.
+// Each event carries an HMAC over its contents chained to the previous
+// event's HMAC, so altering, removing or reordering events breaks the chain.
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited actions
+const (
+	ActionScreeningDecision          = "SCREENING_DECISION"
+	ActionInvestigationOpened        = "INVESTIGATION_OPENED"
+	ActionInvestigationStatusChanged = "INVESTIGATION_STATUS_CHANGED"
+	ActionInvestigationAssigned      = "INVESTIGATION_ASSIGNED"
+	ActionInvestigationNoteAdded     = "INVESTIGATION_NOTE_ADDED"
+	ActionEvidenceAdded              = "EVIDENCE_ADDED"
+	ActionEvidenceDeleted            = "EVIDENCE_DELETED"
+	ActionFilingTransitioned         = "FILING_TRANSITIONED"
+	ActionFilingApproved             = "FILING_APPROVED"
+	ActionFilingSubmitted            = "FILING_SUBMITTED"
+	ActionFilingNarrativeDrafted     = "FILING_NARRATIVE_DRAFTED"
+	ActionFilingAmended              = "FILING_AMENDED"
+	ActionWatchlistChanged           = "WATCHLIST_CHANGED"
+	ActionSuppressionCreated         = "SUPPRESSION_CREATED"
+	ActionScreeningsExported         = "SCREENINGS_EXPORTED"
+	ActionRecordsArchived            = "RECORDS_ARCHIVED"
+	ActionRecordsRestored            = "RECORDS_RESTORED"
+	ActionRetroactiveRescreen        = "RETROACTIVE_RESCREEN"
+	ActionAccountDenylistChanged     = "ACCOUNT_DENYLIST_CHANGED"
+	ActionAlertAutoClosed            = "ALERT_AUTO_CLOSED"
+	ActionWebhookEndpointChanged     = "WEBHOOK_ENDPOINT_CHANGED"
+	ActionWebhookEventReplayed       = "WEBHOOK_EVENT_REPLAYED"
+	ActionCountryRiskTableChanged    = "COUNTRY_RISK_TABLE_CHANGED"
+	ActionTenantSettingsChanged      = "TENANT_SETTINGS_CHANGED"
+	ActionPIIAccessed                = "PII_ACCESSED"
+)
+
+// Audited entity types
+const (
+	EntityScreeningResult     = "screening_result"
+	EntityInvestigation       = "investigation"
+	EntityFiling              = "filing"
+	EntityWatchlist           = "watchlist"
+	EntitySuppression         = "suppression"
+	EntityScreeningExport     = "screening_export"
+	EntityRetentionArchive    = "retention_archive"
+	EntityRetentionHold       = "retention_hold"
+	EntityRetroactiveRescreen = "retroactive_rescreen"
+	EntityAccountDenylist     = "account_denylist"
+	EntityAlert               = "alert"
+	EntityWebhookEndpoint     = "webhook_endpoint"
+	EntityWebhookEvent        = "webhook_event"
+	EntityCountryRiskTable    = "country_risk_table"
+	EntityTenantSettings      = "tenant_settings"
+)
+
+// AuditEvent is one entry in the audit chain
+type AuditEvent struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	Sequence   int64           `json:"sequence" db:"sequence"`
+	ActorID    uuid.UUID       `json:"actor_id" db:"actor_id"`
+	Action     string          `json:"action" db:"action"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   string          `json:"entity_id" db:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty" db:"before"`
+	After      json.RawMessage `json:"after,omitempty" db:"after"`
+	RequestID  string          `json:"request_id,omitempty" db:"request_id"`
+	Timestamp  time.Time       `json:"timestamp" db:"timestamp"`
+
+	// PrevHMAC is the HMAC of the event before this one; empty for the first
+	PrevHMAC string `json:"prev_hmac" db:"prev_hmac"`
+	HMAC     string `json:"hmac" db:"hmac"`
+}
+
+// Entry describes an action to audit. Before and After are marshalled to
+// JSON; either may be nil.
+type Entry struct {
+	ActorID    uuid.UUID
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     interface{}
+	After      interface{}
+}
+
+// computeHMAC returns the hex HMAC-SHA256 of the event's contents and the
+// previous HMAC. Fields are length-prefixed so no two events share an input.
+func computeHMAC(secret []byte, e *AuditEvent) string {
+	var b strings.Builder
+	for _, field := range []string{
+		e.ID.String(),
+		strconv.FormatInt(e.Sequence, 10),
+		e.ActorID.String(),
+		e.Action,
+		e.EntityType,
+		e.EntityID,
+		string(e.Before),
+		string(e.After),
+		e.RequestID,
+		e.Timestamp.UTC().Format(time.RFC3339Nano),
+		e.PrevHMAC,
+	} {
+		b.WriteString(strconv.Itoa(len(field)))
+		b.WriteByte(':')
+		b.WriteString(field)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(b.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,558 @@
+// Package fincen serializes regulatory filings into FinCEN BSA E-Filing
+// batch XML.
+package fincen
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const (
+	namespace      = "www.fincen.gov/base"
+	xsiNamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+	sarSchemaURL   = "www.fincen.gov/base https://www.fincen.gov/base/EFL_SARXBatchSchema.xsd"
+	formTypeSAR    = "SARX"
+	dateFormat     = "20060102"
+	maxNarrativeSz = 17000 // characters accepted in a SAR narrative
+)
+
+// Activity party type codes
+const (
+	partyTypeSubject            = "33"
+	partyTypeFilingInstitution  = "30"
+	partyTypeContactOffice      = "8"
+	partyTypeTransmitter        = "35"
+	partyTypeTransmitterContact = "37"
+	partyTypeAccountHolder      = "41"
+)
+
+// Party identification type codes
+const (
+	idTypeSSN            = "1"
+	idTypeEIN            = "2"
+	idTypeTCC            = "4"
+	idTypeDriversLicense = "5"
+	idTypePassport       = "6"
+	idTypeAlienReg       = "7"
+	idTypeRSSD           = "10"
+	idTypeOther          = "999"
+)
+
+// activityClassification is a FinCEN suspicious activity type and the
+// "Other" subtype used when no finer subtype is recorded
+type activityClassification struct {
+	TypeID    int
+	SubtypeID int
+}
+
+// sarCategories maps SARActivity categories to FinCEN classifications
+var sarCategories = map[string]activityClassification{
+	"terrorist financing":          {TypeID: 1, SubtypeID: 199},
+	"structuring":                  {TypeID: 2, SubtypeID: 299},
+	"fraud":                        {TypeID: 3, SubtypeID: 399},
+	"casinos":                      {TypeID: 4, SubtypeID: 499},
+	"money laundering":             {TypeID: 5, SubtypeID: 599},
+	"identification/documentation": {TypeID: 6, SubtypeID: 699},
+	"other suspicious activities":  {TypeID: 7, SubtypeID: 799},
+	"insurance":                    {TypeID: 8, SubtypeID: 899},
+	"securities/futures/options":   {TypeID: 9, SubtypeID: 999},
+	"mortgage fraud":               {TypeID: 10, SubtypeID: 1099},
+	"cyber event":                  {TypeID: 11, SubtypeID: 1199},
+}
+
+// IsSARCategory reports whether a SAR activity category is one of the
+// FinCEN suspicious activity types, ignoring case and surrounding space
+func IsSARCategory(category string) bool {
+	_, ok := sarCategories[strings.ToLower(strings.TrimSpace(category))]
+	return ok
+}
+
+// Problem describes one field that prevents export
+type Problem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every problem found before export. It wraps
+// domain.ErrValidation.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		parts[i] = p.Field + ": " + p.Message
+	}
+	return "fincen export: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return domain.ErrValidation
+}
+
+// ErrorDetails returns the problems for the error response
+func (e *ValidationError) ErrorDetails() interface{} {
+	return e.Problems
+}
+
+// Exporter produces FinCEN SAR batch XML for approved filings
+type Exporter struct {
+	institution *config.FilingInstitutionConfig
+}
+
+// NewExporter creates a new exporter for the given filing institution
+func NewExporter(institution *config.FilingInstitutionConfig) *Exporter {
+	return &Exporter{institution: institution}
+}
+
+// MarshalSAR returns the batch XML for an approved SAR
+func (e *Exporter) MarshalSAR(f *domain.RegulatoryFiling) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := e.ExportSAR(&buf, f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportSAR validates an approved SAR and writes it as batch XML to w.
+// A *ValidationError lists every missing or invalid field.
+func (e *Exporter) ExportSAR(w io.Writer, f *domain.RegulatoryFiling) error {
+	if err := e.validate(f); err != nil {
+		return err
+	}
+
+	batch := e.buildBatch(f, time.Now())
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(batch); err != nil {
+		return fmt.Errorf("encode sar xml: %w", err)
+	}
+	return enc.Flush()
+}
+
+// validate collects every problem that would make FinCEN reject the filing
+func (e *Exporter) validate(f *domain.RegulatoryFiling) error {
+	var problems []Problem
+	require := func(field, value string) {
+		if strings.TrimSpace(value) == "" {
+			problems = append(problems, Problem{Field: field, Message: "is required"})
+		}
+	}
+
+	if f.FilingType != domain.FilingTypeSAR {
+		problems = append(problems, Problem{Field: "filing_type", Message: "only SAR filings can be exported"})
+	}
+	if !f.CanSubmit() {
+		problems = append(problems, Problem{Field: "status", Message: fmt.Sprintf("must be %s, got %s", domain.FilingStatusApproved, f.Status)})
+	}
+
+	inst := e.institution
+	require("filing_institution.name", inst.Name)
+	require("filing_institution.tin", inst.TIN)
+	require("filing_institution.address", inst.Address)
+	require("filing_institution.city", inst.City)
+	require("filing_institution.state", inst.State)
+	require("filing_institution.zip_code", inst.ZipCode)
+	require("filing_institution.country", inst.Country)
+	require("filing_institution.contact_office", inst.ContactOffice)
+	require("filing_institution.contact_phone", inst.ContactPhone)
+	require("filing_institution.transmitter_control_code", inst.TransmitterControlCode)
+
+	if s := f.SubjectInfo; s == nil {
+		problems = append(problems, Problem{Field: "subject_info", Message: "is required"})
+	} else {
+		require("subject_info.last_name", s.LastName)
+		require("subject_info.address", s.Address)
+		require("subject_info.city", s.City)
+		require("subject_info.country", s.Country)
+		require("subject_info.account_number", s.AccountNumber)
+		if s.DOB != "" {
+			if _, err := time.Parse("2006-01-02", s.DOB); err != nil {
+				problems = append(problems, Problem{Field: "subject_info.dob", Message: "must be YYYY-MM-DD"})
+			}
+		}
+	}
+
+	if a := f.SuspiciousActivity; a == nil || len(a.Categories) == 0 {
+		problems = append(problems, Problem{Field: "suspicious_activity.categories", Message: "at least one category is required"})
+	} else {
+		for i, c := range a.Categories {
+			if !IsSARCategory(c) {
+				problems = append(problems, Problem{
+					Field:   fmt.Sprintf("suspicious_activity.categories[%d]", i),
+					Message: fmt.Sprintf("unknown category %q", c),
+				})
+			}
+		}
+	}
+
+	if f.TotalAmount <= 0 {
+		problems = append(problems, Problem{Field: "total_amount", Message: "must be greater than zero"})
+	}
+	if f.ActivityStartDate.IsZero() {
+		problems = append(problems, Problem{Field: "activity_start_date", Message: "is required"})
+	}
+	if f.ActivityEndDate.IsZero() {
+		problems = append(problems, Problem{Field: "activity_end_date", Message: "is required"})
+	}
+	if f.ActivityEndDate.Before(f.ActivityStartDate) {
+		problems = append(problems, Problem{Field: "activity_end_date", Message: "is before activity_start_date"})
+	}
+
+	require("narrative", f.Narrative)
+	if n := utf8.RuneCountInString(f.Narrative); n > maxNarrativeSz {
+		problems = append(problems, Problem{
+			Field:   "narrative",
+			Message: fmt.Sprintf("is %d characters, maximum is %d", n, maxNarrativeSz),
+		})
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// buildBatch maps a validated filing onto the batch XML structure.
+// FinCEN requires SeqNum values to be unique across the whole batch.
+func (e *Exporter) buildBatch(f *domain.RegulatoryFiling, now time.Time) *batchXML {
+	seq := 0
+	next := func() int {
+		seq++
+		return seq
+	}
+
+	amount := f.TotalAmount.Round(0).Int64()
+	inst := e.institution
+	subject := f.SubjectInfo
+
+	activity := activityXML{
+		SeqNum:         next(),
+		FilingDateText: now.UTC().Format(dateFormat),
+		ActivityAssociation: activityAssociationXML{
+			SeqNum: next(),
+		},
+	}
+	// An amendment corrects the prior report named by the BSA ID carried
+	// over from it; one whose original never got a BSA ID, e.g. after a
+	// rejection, is filed as a new report
+	if f.AmendedFromID != nil && f.BSAFilingID != "" {
+		activity.PriorDocumentNumber = f.BSAFilingID
+		activity.ActivityAssociation.CorrectsAmendsPriorReportIndicator = "Y"
+	} else {
+		activity.ActivityAssociation.InitialReportIndicator = "Y"
+	}
+
+	activity.Parties = append(activity.Parties,
+		partyXML{
+			SeqNum:                next(),
+			ActivityPartyTypeCode: partyTypeTransmitter,
+			PartyName:             &partyNameXML{SeqNum: next(), PartyNameTypeCode: "L", RawPartyFullName: inst.Name},
+			Address:               institutionAddress(next(), inst),
+			PhoneNumber:           &phoneXML{SeqNum: next(), PhoneNumberText: digits(inst.ContactPhone)},
+			Identifications: []partyIdentificationXML{
+				{SeqNum: next(), PartyIdentificationNumberText: digits(inst.TIN), PartyIdentificationTypeCode: idTypeEIN},
+				{SeqNum: next(), PartyIdentificationNumberText: inst.TransmitterControlCode, PartyIdentificationTypeCode: idTypeTCC},
+			},
+		},
+		partyXML{
+			SeqNum:                next(),
+			ActivityPartyTypeCode: partyTypeTransmitterContact,
+			PartyName:             &partyNameXML{SeqNum: next(), PartyNameTypeCode: "L", RawPartyFullName: inst.ContactOffice},
+		},
+	)
+
+	filer := partyXML{
+		SeqNum:                next(),
+		ActivityPartyTypeCode: partyTypeFilingInstitution,
+		PartyName:             &partyNameXML{SeqNum: next(), PartyNameTypeCode: "L", RawPartyFullName: inst.Name},
+		Address:               institutionAddress(next(), inst),
+		Identifications: []partyIdentificationXML{
+			{SeqNum: next(), PartyIdentificationNumberText: digits(inst.TIN), PartyIdentificationTypeCode: idTypeEIN},
+		},
+	}
+	if inst.RSSDNumber != "" {
+		filer.Identifications = append(filer.Identifications, partyIdentificationXML{
+			SeqNum: next(), PartyIdentificationNumberText: inst.RSSDNumber, PartyIdentificationTypeCode: idTypeRSSD,
+		})
+	}
+	activity.Parties = append(activity.Parties, filer, partyXML{
+		SeqNum:                next(),
+		ActivityPartyTypeCode: partyTypeContactOffice,
+		PartyName:             &partyNameXML{SeqNum: next(), PartyNameTypeCode: "L", RawPartyFullName: inst.ContactOffice},
+		PhoneNumber:           &phoneXML{SeqNum: next(), PhoneNumberText: digits(inst.ContactPhone)},
+	})
+
+	activity.Parties = append(activity.Parties, subjectParty(next, subject))
+
+	activity.SuspiciousActivity = suspiciousActivityXML{
+		SeqNum:                         next(),
+		SuspiciousActivityFromDateText: f.ActivityStartDate.UTC().Format(dateFormat),
+		SuspiciousActivityToDateText:   f.ActivityEndDate.UTC().Format(dateFormat),
+		TotalSuspiciousAmountText:      amount,
+	}
+	for _, c := range f.SuspiciousActivity.Categories {
+		class := sarCategories[strings.ToLower(strings.TrimSpace(c))]
+		activity.SuspiciousActivity.Classifications = append(activity.SuspiciousActivity.Classifications,
+			classificationXML{SeqNum: next(), SuspiciousActivitySubtypeID: class.SubtypeID, SuspiciousActivityTypeID: class.TypeID})
+	}
+
+	activity.Narratives = []narrativeXML{{
+		SeqNum:                          next(),
+		ActivityNarrativeSequenceNumber: 1,
+		NarrativeText:                   f.Narrative,
+	}}
+
+	return &batchXML{
+		XMLNSFC2:                namespace,
+		XMLNSXSI:                xsiNamespace,
+		SchemaLocation:          sarSchemaURL,
+		ActivityCount:           1,
+		TotalAmount:             amount,
+		PartyCount:              len(activity.Parties),
+		ActivityAttachmentCount: 0,
+		AttachmentCount:         0,
+		FormTypeCode:            formTypeSAR,
+		Activity:                activity,
+	}
+}
+
+// subjectParty maps the SAR subject, including the account involved
+func subjectParty(next func() int, s *domain.SARSubject) partyXML {
+	party := partyXML{
+		SeqNum:                next(),
+		ActivityPartyTypeCode: partyTypeSubject,
+		PartyName: &partyNameXML{
+			SeqNum:                      next(),
+			PartyNameTypeCode:           "L",
+			RawEntityIndividualLastName: s.LastName,
+			RawIndividualFirstName:      s.FirstName,
+			RawIndividualMiddleName:     s.MiddleName,
+			RawIndividualNameSuffixText: s.Suffix,
+		},
+		Address: &addressXML{
+			SeqNum:                next(),
+			RawCityText:           s.City,
+			RawCountryCodeText:    s.Country,
+			RawStateCodeText:      s.State,
+			RawStreetAddress1Text: s.Address,
+			RawZIPCode:            s.ZipCode,
+		},
+	}
+
+	if s.DOB != "" {
+		if dob, err := time.Parse("2006-01-02", s.DOB); err == nil {
+			party.IndividualBirthDateText = dob.Format(dateFormat)
+		}
+	}
+	if s.SSN != "" {
+		party.Identifications = append(party.Identifications, partyIdentificationXML{
+			SeqNum: next(), PartyIdentificationNumberText: digits(s.SSN), PartyIdentificationTypeCode: idTypeSSN,
+		})
+	}
+	if s.IDNumber != "" {
+		id := partyIdentificationXML{
+			SeqNum:                        next(),
+			PartyIdentificationNumberText: s.IDNumber,
+			PartyIdentificationTypeCode:   identificationTypeCode(s.IDType),
+			OtherIssuerStateText:          s.IDState,
+			OtherIssuerCountryText:        s.IDCountry,
+		}
+		if id.PartyIdentificationTypeCode == idTypeOther {
+			id.OtherPartyIdentificationTypeText = s.IDType
+		}
+		party.Identifications = append(party.Identifications, id)
+	}
+	if s.Occupation != "" {
+		party.Occupation = &occupationXML{SeqNum: next(), OccupationBusinessText: s.Occupation}
+	}
+
+	account := &partyAccountAssociationXML{
+		SeqNum:                          next(),
+		PartyAccountAssociationTypeCode: "7",
+		Party: accountPartyXML{
+			SeqNum:                next(),
+			ActivityPartyTypeCode: partyTypeAccountHolder,
+			Account: accountXML{
+				SeqNum:            next(),
+				AccountNumberText: s.AccountNumber,
+			},
+		},
+	}
+	if s.AccountCloseDate != "" {
+		account.Party.Account.AccountClosedIndicator = "Y"
+	}
+	party.AccountAssociation = account
+
+	return party
+}
+
+func institutionAddress(seq int, inst *config.FilingInstitutionConfig) *addressXML {
+	return &addressXML{
+		SeqNum:                seq,
+		RawCityText:           inst.City,
+		RawCountryCodeText:    inst.Country,
+		RawStateCodeText:      inst.State,
+		RawStreetAddress1Text: inst.Address,
+		RawZIPCode:            inst.ZipCode,
+	}
+}
+
+// identificationTypeCode maps a subject's ID type to a FinCEN code
+func identificationTypeCode(idType string) string {
+	switch strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(idType), " ", "_")) {
+	case "DRIVERS_LICENSE", "DRIVER_LICENSE", "STATE_ID":
+		return idTypeDriversLicense
+	case "PASSPORT":
+		return idTypePassport
+	case "ALIEN_REGISTRATION":
+		return idTypeAlienReg
+	default:
+		return idTypeOther
+	}
+}
+
+// digits strips formatting such as dashes from TINs and phone numbers
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// XML structure of the FinCEN SAR batch schema. encoding/xml does not emit
+// namespace prefixes, so the fc2 prefix is part of each element name.
+
+type batchXML struct {
+	XMLName                 xml.Name    `xml:"fc2:EFilingBatchXML"`
+	XMLNSFC2                string      `xml:"xmlns:fc2,attr"`
+	XMLNSXSI                string      `xml:"xmlns:xsi,attr"`
+	SchemaLocation          string      `xml:"xsi:schemaLocation,attr"`
+	ActivityCount           int         `xml:"ActivityCount,attr"`
+	TotalAmount             int64       `xml:"TotalAmount,attr"`
+	PartyCount              int         `xml:"PartyCount,attr"`
+	ActivityAttachmentCount int         `xml:"ActivityAttachmentCount,attr"`
+	AttachmentCount         int         `xml:"AttachmentCount,attr"`
+	FormTypeCode            string      `xml:"fc2:FormTypeCode"`
+	Activity                activityXML `xml:"fc2:Activity"`
+}
+
+type activityXML struct {
+	SeqNum              int                    `xml:"SeqNum,attr"`
+	PriorDocumentNumber string                 `xml:"fc2:EFilingPriorDocumentNumber,omitempty"`
+	FilingDateText      string                 `xml:"fc2:FilingDateText"`
+	ActivityAssociation activityAssociationXML `xml:"fc2:ActivityAssociation"`
+	Parties             []partyXML             `xml:"fc2:Party"`
+	SuspiciousActivity  suspiciousActivityXML  `xml:"fc2:SuspiciousActivity"`
+	Narratives          []narrativeXML         `xml:"fc2:ActivityNarrativeInformation"`
+}
+
+type activityAssociationXML struct {
+	SeqNum                             int    `xml:"SeqNum,attr"`
+	CorrectsAmendsPriorReportIndicator string `xml:"fc2:CorrectsAmendsPriorReportIndicator,omitempty"`
+	InitialReportIndicator             string `xml:"fc2:InitialReportIndicator,omitempty"`
+}
+
+type partyXML struct {
+	SeqNum                  int                         `xml:"SeqNum,attr"`
+	ActivityPartyTypeCode   string                      `xml:"fc2:ActivityPartyTypeCode"`
+	IndividualBirthDateText string                      `xml:"fc2:IndividualBirthDateText,omitempty"`
+	PartyName               *partyNameXML               `xml:"fc2:PartyName,omitempty"`
+	Address                 *addressXML                 `xml:"fc2:Address,omitempty"`
+	PhoneNumber             *phoneXML                   `xml:"fc2:PhoneNumber,omitempty"`
+	Identifications         []partyIdentificationXML    `xml:"fc2:PartyIdentification"`
+	Occupation              *occupationXML              `xml:"fc2:PartyOccupationBusiness,omitempty"`
+	AccountAssociation      *partyAccountAssociationXML `xml:"fc2:PartyAccountAssociation,omitempty"`
+}
+
+type partyNameXML struct {
+	SeqNum                      int    `xml:"SeqNum,attr"`
+	PartyNameTypeCode           string `xml:"fc2:PartyNameTypeCode"`
+	RawEntityIndividualLastName string `xml:"fc2:RawEntityIndividualLastName,omitempty"`
+	RawIndividualFirstName      string `xml:"fc2:RawIndividualFirstName,omitempty"`
+	RawIndividualMiddleName     string `xml:"fc2:RawIndividualMiddleName,omitempty"`
+	RawIndividualNameSuffixText string `xml:"fc2:RawIndividualNameSuffixText,omitempty"`
+	RawPartyFullName            string `xml:"fc2:RawPartyFullName,omitempty"`
+}
+
+type addressXML struct {
+	SeqNum                int    `xml:"SeqNum,attr"`
+	RawCityText           string `xml:"fc2:RawCityText"`
+	RawCountryCodeText    string `xml:"fc2:RawCountryCodeText"`
+	RawStateCodeText      string `xml:"fc2:RawStateCodeText,omitempty"`
+	RawStreetAddress1Text string `xml:"fc2:RawStreetAddress1Text"`
+	RawZIPCode            string `xml:"fc2:RawZIPCode,omitempty"`
+}
+
+type phoneXML struct {
+	SeqNum          int    `xml:"SeqNum,attr"`
+	PhoneNumberText string `xml:"fc2:PhoneNumberText"`
+}
+
+type partyIdentificationXML struct {
+	SeqNum                           int    `xml:"SeqNum,attr"`
+	OtherIssuerCountryText           string `xml:"fc2:OtherIssuerCountryText,omitempty"`
+	OtherIssuerStateText             string `xml:"fc2:OtherIssuerStateText,omitempty"`
+	OtherPartyIdentificationTypeText string `xml:"fc2:OtherPartyIdentificationTypeText,omitempty"`
+	PartyIdentificationNumberText    string `xml:"fc2:PartyIdentificationNumberText"`
+	PartyIdentificationTypeCode      string `xml:"fc2:PartyIdentificationTypeCode"`
+}
+
+type occupationXML struct {
+	SeqNum                 int    `xml:"SeqNum,attr"`
+	OccupationBusinessText string `xml:"fc2:OccupationBusinessText"`
+}
+
+type partyAccountAssociationXML struct {
+	SeqNum                          int             `xml:"SeqNum,attr"`
+	PartyAccountAssociationTypeCode string          `xml:"fc2:PartyAccountAssociationTypeCode"`
+	Party                           accountPartyXML `xml:"fc2:Party"`
+}
+
+type accountPartyXML struct {
+	SeqNum                int        `xml:"SeqNum,attr"`
+	ActivityPartyTypeCode string     `xml:"fc2:ActivityPartyTypeCode"`
+	Account               accountXML `xml:"fc2:Account"`
+}
+
+type accountXML struct {
+	SeqNum                 int    `xml:"SeqNum,attr"`
+	AccountClosedIndicator string `xml:"fc2:AccountClosedIndicator,omitempty"`
+	AccountNumberText      string `xml:"fc2:AccountNumberText"`
+}
+
+type suspiciousActivityXML struct {
+	SeqNum                         int                 `xml:"SeqNum,attr"`
+	SuspiciousActivityFromDateText string              `xml:"fc2:SuspiciousActivityFromDateText"`
+	SuspiciousActivityToDateText   string              `xml:"fc2:SuspiciousActivityToDateText"`
+	TotalSuspiciousAmountText      int64               `xml:"fc2:TotalSuspiciousAmountText"`
+	Classifications                []classificationXML `xml:"fc2:SuspiciousActivityClassification"`
+}
+
+type classificationXML struct {
+	SeqNum                      int `xml:"SeqNum,attr"`
+	SuspiciousActivitySubtypeID int `xml:"fc2:SuspiciousActivitySubtypeID"`
+	SuspiciousActivityTypeID    int `xml:"fc2:SuspiciousActivityTypeID"`
+}
+
+type narrativeXML struct {
+	SeqNum                          int    `xml:"SeqNum,attr"`
+	ActivityNarrativeSequenceNumber int    `xml:"fc2:ActivityNarrativeSequenceNumber"`
+	NarrativeText                   string `xml:"fc2:NarrativeText"`
+}
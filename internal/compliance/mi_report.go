@@ -0,0 +1,86 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// miTopPatterns is the number of pattern types listed in a report
+const miTopPatterns = 5
+
+// MIDataSource aggregates screening, investigation and filing activity over
+// a half-open [start, end) interval
+type MIDataSource interface {
+	ScreeningStats(ctx context.Context, start, end time.Time) (domain.MIScreening, error)
+	InvestigationStats(ctx context.Context, start, end time.Time) (domain.MIInvestigations, error)
+	FilingStats(ctx context.Context, filingType domain.FilingType, start, end time.Time) (domain.MIFilings, error)
+	TopPatterns(ctx context.Context, start, end time.Time, limit int) ([]domain.PatternCount, error)
+}
+
+// MIReportGenerator compiles management information reports
+type MIReportGenerator struct {
+	source MIDataSource
+}
+
+// NewMIReportGenerator creates a new MI report generator
+func NewMIReportGenerator(source MIDataSource) *MIReportGenerator {
+	return &MIReportGenerator{source: source}
+}
+
+// Generate compiles the report for the period starting at start, which must
+// be a period boundary as returned by ReportPeriod.Bounds
+func (g *MIReportGenerator) Generate(ctx context.Context, period domain.ReportPeriod, start time.Time) (*domain.MIReport, error) {
+	start, end := period.Bounds(start)
+
+	screening, err := g.source.ScreeningStats(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("screening stats: %w", err)
+	}
+	screening.HitRate = ratio(screening.Hits, screening.Total)
+
+	investigations, err := g.source.InvestigationStats(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("investigation stats: %w", err)
+	}
+	investigations.FalsePositiveRate = ratio(investigations.FalsePositives, investigations.Closed)
+
+	sar, err := g.source.FilingStats(ctx, domain.FilingTypeSAR, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("sar stats: %w", err)
+	}
+	sar.OnTimeRate = ratio(sar.OnTime, sar.Submitted)
+
+	ctr, err := g.source.FilingStats(ctx, domain.FilingTypeCTR, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("ctr stats: %w", err)
+	}
+	ctr.OnTimeRate = ratio(ctr.OnTime, ctr.Submitted)
+
+	patterns, err := g.source.TopPatterns(ctx, start, end, miTopPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("top patterns: %w", err)
+	}
+
+	return &domain.MIReport{
+		Period:         period,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Screening:      screening,
+		Investigations: investigations,
+		SAR:            sar,
+		CTR:            ctr,
+		TopPatterns:    patterns,
+		GeneratedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// ratio returns n/d, or zero for an empty denominator
+func ratio(n, d int64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
@@ -0,0 +1,340 @@
+// Package compliance drafts regulatory filing content from the evidence
+// gathered during screening and investigation, and compiles the management
+// information reported to the compliance function.
+package compliance
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+const (
+	// maxNarrativeTransactions caps the chronology so the draft stays well
+	// within FinCEN's narrative limit; the remainder is summarised
+	maxNarrativeTransactions = 150
+
+	narrativeDateFormat = "January 2, 2006"
+)
+
+// patternDescriptions explains each detected pattern in plain English
+var patternDescriptions = map[domain.PatternType]string{
+	domain.PatternStructuring:      "Structuring: multiple transactions kept just below reporting thresholds, consistent with an attempt to evade currency transaction reporting.",
+	domain.PatternRapidCycling:     "Rapid cycling: funds received and moved out again within a short period, leaving little or no balance in the account.",
+	domain.PatternGeoConcentration: "Geographic concentration: an unusual share of activity directed to or from a small number of jurisdictions.",
+	domain.PatternVelocitySpike:    "Velocity spike: transaction volume or value far above the customer's established baseline.",
+	domain.PatternMixingLayering:   "Mixing/layering: funds passed through multiple accounts or parties in a way that obscures their origin.",
+	domain.PatternSmurfing:         "Smurfing: several parties or accounts used to move funds for what appears to be a single purpose.",
+	domain.PatternRoundTripping:    "Round-tripping: funds sent out and returned to the customer, directly or through intermediaries.",
+	domain.PatternUnusualTime:      "Unusual timing: activity concentrated at hours the customer does not normally transact.",
+
+	domain.PatternDormantReactivation: "Dormant account reactivation: a long-inactive account suddenly used to move an amount well above its earlier activity.",
+}
+
+// NarrativeInput is the evidence a SAR narrative is drafted from.
+// Investigation and Alerts are optional; Results are the screening results
+// of the filing's transactions.
+type NarrativeInput struct {
+	Filing        *domain.RegulatoryFiling
+	Investigation *domain.Investigation
+	Results       []*domain.ScreeningResult
+	Alerts        []*domain.AMLAlert
+}
+
+// GenerateNarrative assembles a first-draft SAR narrative: a subject
+// summary, the related transactions in chronological order, detected
+// patterns, OFAC and PEP match details, and totals taken from the filing's
+// activity amounts. The draft always opens with a summary paragraph long
+// enough to satisfy the filing's minimum narrative length.
+func GenerateNarrative(in *NarrativeInput) string {
+	f := in.Filing
+	var b strings.Builder
+
+	subjectName := "the subject"
+	if f.SubjectInfo != nil {
+		if name := subjectFullName(f.SubjectInfo); name != "" {
+			subjectName = name
+		}
+	}
+
+	fmt.Fprintf(&b, "This report concerns suspicious activity conducted by %s between %s and %s, involving %d transaction(s) totalling %s.",
+		subjectName,
+		f.ActivityStartDate.Format(narrativeDateFormat),
+		f.ActivityEndDate.Format(narrativeDateFormat),
+		len(f.TransactionIDs),
+		formatAmount(f.TotalAmount, f.Currency),
+	)
+	if inv := in.Investigation; inv != nil {
+		fmt.Fprintf(&b, " The activity was identified through internal investigation %s (%s).", inv.CaseNumber, inv.Title)
+	}
+	b.WriteString("\n")
+
+	writeSubject(&b, f.SubjectInfo)
+	writeTransactions(&b, f, in.Results)
+	writePatterns(&b, in.Results, in.Alerts)
+	writeWatchlistMatches(&b, in.Results)
+	writeTotals(&b, f)
+
+	if inv := in.Investigation; inv != nil && inv.Findings != "" {
+		b.WriteString("\nINVESTIGATION FINDINGS\n")
+		b.WriteString(strings.TrimSpace(inv.Findings))
+		b.WriteString("\n")
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func writeSubject(b *strings.Builder, s *domain.SARSubject) {
+	b.WriteString("\nSUBJECT\n")
+	if s == nil {
+		b.WriteString("Subject details were not recorded on the filing.\n")
+		return
+	}
+
+	if name := subjectFullName(s); name != "" {
+		fmt.Fprintf(b, "Name: %s\n", name)
+	}
+	if s.DOB != "" {
+		fmt.Fprintf(b, "Date of birth: %s\n", s.DOB)
+	}
+	if addr := joinNonEmpty(", ", s.Address, s.City, s.State, s.ZipCode, s.Country); addr != "" {
+		fmt.Fprintf(b, "Address: %s\n", addr)
+	}
+	if s.AccountNumber != "" {
+		account := s.AccountNumber
+		if s.AccountOpenDate != "" {
+			account += ", opened " + s.AccountOpenDate
+		}
+		if s.AccountCloseDate != "" {
+			account += ", closed " + s.AccountCloseDate
+		}
+		fmt.Fprintf(b, "Account: %s\n", account)
+	}
+	if s.IDType != "" {
+		fmt.Fprintf(b, "Identification: %s\n", joinNonEmpty(", ", s.IDType, s.IDState, s.IDCountry))
+	}
+	if occupation := joinNonEmpty(" at ", s.Occupation, s.Employer); occupation != "" {
+		fmt.Fprintf(b, "Occupation: %s\n", occupation)
+	}
+	if s.Relationship != "" {
+		fmt.Fprintf(b, "Relationship to institution: %s\n", s.Relationship)
+	}
+}
+
+func writeTransactions(b *strings.Builder, f *domain.RegulatoryFiling, results []*domain.ScreeningResult) {
+	byTx := make(map[uuid.UUID]*domain.Transaction, len(results))
+	for _, r := range results {
+		if r.Transaction != nil {
+			byTx[r.TransactionID] = r.Transaction
+		}
+	}
+
+	var txs []*domain.Transaction
+	var missing int
+	for _, id := range f.TransactionIDs {
+		if tx, ok := byTx[id]; ok {
+			txs = append(txs, tx)
+		} else {
+			missing++
+		}
+	}
+	slices.SortStableFunc(txs, func(a, b *domain.Transaction) int {
+		return a.InitiatedAt.Compare(b.InitiatedAt)
+	})
+
+	b.WriteString("\nTRANSACTIONS\n")
+	for i, tx := range txs {
+		if i == maxNarrativeTransactions {
+			fmt.Fprintf(b, "... and %d further transaction(s) listed on the filing.\n", len(txs)-i)
+			break
+		}
+		b.WriteString(describeTransaction(tx))
+		b.WriteString("\n")
+	}
+	if missing > 0 {
+		fmt.Fprintf(b, "Details of %d transaction(s) on the filing were not available from screening records.\n", missing)
+	}
+}
+
+// describeTransaction renders one chronology line, e.g.
+// "2026-01-15 14:02 UTC: outbound TRANSFER of 9,500.00 USD to ACME LTD (GB), via WEB"
+func describeTransaction(tx *domain.Transaction) string {
+	preposition := "from"
+	if tx.Direction == domain.DirectionOutbound {
+		preposition = "to"
+	}
+
+	line := fmt.Sprintf("%s: %s %s of %s",
+		tx.InitiatedAt.UTC().Format("2006-01-02 15:04 MST"),
+		strings.ToLower(tx.Direction), tx.Type, formatAmount(tx.Amount, tx.Currency))
+	if name := tx.GetCounterpartyName(); name != "" {
+		line += fmt.Sprintf(" %s %s", preposition, name)
+		if country := tx.GetCounterpartyCountry(); country != "" {
+			line += fmt.Sprintf(" (%s)", country)
+		}
+	}
+	if tx.Channel != "" {
+		line += ", via " + tx.Channel
+	}
+	return line
+}
+
+// writePatterns lists each detected pattern type once, with its highest
+// confidence across screening results and alerts
+func writePatterns(b *strings.Builder, results []*domain.ScreeningResult, alerts []*domain.AMLAlert) {
+	confidence := make(map[domain.PatternType]float64)
+	var order []domain.PatternType
+	note := func(p domain.PatternType, c float64) {
+		if _, ok := confidence[p]; !ok {
+			order = append(order, p)
+		}
+		confidence[p] = max(confidence[p], c)
+	}
+
+	for _, r := range results {
+		for _, m := range r.PatternMatches {
+			note(m.PatternType, m.Confidence)
+		}
+	}
+	for _, a := range alerts {
+		if a.PatternType != nil {
+			note(*a.PatternType, a.Confidence)
+		}
+	}
+	if len(order) == 0 {
+		return
+	}
+
+	b.WriteString("\nDETECTED PATTERNS\n")
+	for _, p := range order {
+		desc, ok := patternDescriptions[p]
+		if !ok {
+			desc = fmt.Sprintf("%s pattern detected.", p)
+		}
+		fmt.Fprintf(b, "- %s (confidence %.0f%%)\n", desc, confidence[p]*100)
+	}
+}
+
+// writeWatchlistMatches reports each distinct OFAC and PEP match
+func writeWatchlistMatches(b *strings.Builder, results []*domain.ScreeningResult) {
+	seen := make(map[string]bool)
+	var lines []string
+	add := func(line string) {
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+
+	for _, r := range results {
+		if m := r.OFACMatch; m != nil && m.Matched {
+			line := fmt.Sprintf("- OFAC: %s matched SDN entry %q", matchedField(m.MatchedField), m.SDNName)
+			if m.Program != "" {
+				line += fmt.Sprintf(" (program %s)", m.Program)
+			}
+			add(line + fmt.Sprintf(", %s match, score %.2f.", strings.ToLower(string(m.MatchType)), m.MatchScore))
+		}
+		if m := r.PEPMatch; m != nil && m.Matched {
+			line := "- PEP: "
+			if m.Associate {
+				line += fmt.Sprintf("%s, a relative or close associate of %s", m.AssociateName, m.PEPName)
+			} else {
+				line += m.PEPName
+			}
+			if role := joinNonEmpty(", ", m.PEPPosition, m.PEPCountry); role != "" {
+				line += fmt.Sprintf(" (%s)", role)
+			}
+			if m.PEPEndDate != nil {
+				line += fmt.Sprintf(", left office %s", m.PEPEndDate.Format(time.DateOnly))
+			}
+			add(line + fmt.Sprintf(", %s match, score %.2f.", strings.ToLower(string(m.MatchType)), m.MatchScore))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	b.WriteString("\nSANCTIONS AND PEP SCREENING\n")
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
+
+// writeTotals restates the filing's amounts so the narrative agrees with
+// the structured activity section
+func writeTotals(b *strings.Builder, f *domain.RegulatoryFiling) {
+	b.WriteString("\nTOTALS\n")
+	fmt.Fprintf(b, "Total suspicious amount: %s\n", formatAmount(f.TotalAmount, f.Currency))
+
+	a := f.SuspiciousActivity
+	if a == nil {
+		return
+	}
+	for _, row := range []struct {
+		label  string
+		amount money.Amount
+	}{
+		{"Cash in", a.CashIn},
+		{"Cash out", a.CashOut},
+		{"Wire transfers in", a.WireTransferIn},
+		{"Wire transfers out", a.WireTransferOut},
+		{"Other in", a.OtherIn},
+		{"Other out", a.OtherOut},
+	} {
+		if row.amount != 0 {
+			fmt.Fprintf(b, "%s: %s\n", row.label, formatAmount(row.amount, f.Currency))
+		}
+	}
+	if len(a.Categories) > 0 {
+		fmt.Fprintf(b, "Activity categories: %s\n", strings.Join(a.Categories, ", "))
+	}
+	if len(a.Instruments) > 0 {
+		fmt.Fprintf(b, "Instruments: %s\n", strings.Join(a.Instruments, ", "))
+	}
+}
+
+func subjectFullName(s *domain.SARSubject) string {
+	return joinNonEmpty(" ", s.FirstName, s.MiddleName, s.LastName, s.Suffix)
+}
+
+func matchedField(field string) string {
+	if field == "" {
+		return "a party name"
+	}
+	return "the " + strings.ReplaceAll(field, "_", " ")
+}
+
+// formatAmount renders an amount with thousands separators, e.g. 12,345.67 USD
+func formatAmount(amount money.Amount, currency string) string {
+	s := amount.StringFixed(2)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	var grouped strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(d)
+	}
+
+	out := sign + grouped.String() + "." + frac
+	if currency != "" {
+		out += " " + currency
+	}
+	return out
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), sep)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -18,17 +19,32 @@ type Config struct {
 	Compliance ComplianceConfig `mapstructure:"compliance"`
 	Telemetry  TelemetryConfig  `mapstructure:"telemetry"`
 	Security   SecurityConfig   `mapstructure:"security"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Currency   CurrencyConfig   `mapstructure:"currency"`
+	Webhooks   WebhooksConfig   `mapstructure:"webhooks"`
+	Tenancy    TenancyConfig    `mapstructure:"tenancy"`
+
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	Email         EmailConfig         `mapstructure:"email"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port            int           `mapstructure:"port"`
+	GRPCPort        int           `mapstructure:"grpc_port"`
 	MetricsPort     int           `mapstructure:"metrics_port"`
 	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 	MaxRequestSize  int64         `mapstructure:"max_request_size"`
+
+	// IdempotencyTTL is how long responses are kept for replay under an
+	// Idempotency-Key, and how long consumed event IDs are remembered
+	IdempotencyTTL time.Duration `mapstructure:"idempotency_ttl"`
+
+	// UserActivityBudget bounds the queries behind a user activity request
+	UserActivityBudget time.Duration `mapstructure:"user_activity_budget"`
 }
 
 // DatabaseConfig holds PostgreSQL configuration
@@ -59,7 +75,7 @@ type RedisConfig struct {
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	OFACCacheTTL time.Duration `mapstructure:"ofac_cache_ttl"`
 	PEPCacheTTL  time.Duration `mapstructure:"pep_cache_ttl"`
-	RiskCacheTTL time.Duration `mapstructure:"risk_cache_ttl"`
+	RiskCacheTTL time.Duration `mapstructure:"risk_cache_ttl"` // OFAC/PEP outcomes by counterparty; zero disables
 }
 
 // KafkaConfig holds Kafka configuration
@@ -70,6 +86,38 @@ type KafkaConfig struct {
 	AMLEventsTopic   string   `mapstructure:"aml_events_topic"`
 	AlertsTopic      string   `mapstructure:"alerts_topic"`
 	AuditTopic       string   `mapstructure:"audit_topic"`
+
+	// A consumed message whose handler fails with a retryable error is
+	// retried up to HandlerMaxAttempts times, with exponential backoff from
+	// HandlerRetryBackoff. Messages that still fail, or fail permanently,
+	// are published to DeadLetterTopic.
+	DeadLetterTopic     string        `mapstructure:"dead_letter_topic"`
+	HandlerMaxAttempts  int           `mapstructure:"handler_max_attempts"`
+	HandlerRetryBackoff time.Duration `mapstructure:"handler_retry_backoff"`
+
+	// Transaction events are screened by TransactionWorkers concurrently,
+	// most urgent first. An event's priority (NORMAL, HIGH, URGENT) comes
+	// from its x-priority header or payload, or from PriorityTopics, which
+	// map a priority to a topic read alongside TransactionTopic so urgent
+	// events published there are not read behind a backlog. Each round of
+	// work takes up to PriorityWeights[p] events of each priority, so
+	// NORMAL events keep a share under sustained urgent load. Up to
+	// PriorityQueueSize events per priority are read ahead.
+	TransactionWorkers int               `mapstructure:"transaction_workers"`
+	PriorityWeights    map[string]int    `mapstructure:"priority_weights"`
+	PriorityTopics     map[string]string `mapstructure:"priority_topics"`
+	PriorityQueueSize  int               `mapstructure:"priority_queue_size"`
+}
+
+// PriorityWeight returns the weight configured for a screening priority,
+// ignoring case, or 0 if it has none
+func (k *KafkaConfig) PriorityWeight(priority string) int {
+	for p, w := range k.PriorityWeights {
+		if strings.EqualFold(p, priority) {
+			return w
+		}
+	}
+	return 0
 }
 
 // ScreeningConfig holds screening configuration
@@ -77,8 +125,248 @@ type ScreeningConfig struct {
 	OFACUpdateInterval  time.Duration `mapstructure:"ofac_update_interval"`
 	PEPUpdateInterval   time.Duration `mapstructure:"pep_update_interval"`
 	MaxScreeningLatency time.Duration `mapstructure:"max_screening_latency"`
-	ParallelChecks      int           `mapstructure:"parallel_checks"`
-	FuzzyMatchThreshold float64       `mapstructure:"fuzzy_match_threshold"`
+
+	// Publishers of the OFAC and PEP lists, reported by the list status
+	// endpoint
+	OFACListSource string `mapstructure:"ofac_list_source"`
+	PEPListSource  string `mapstructure:"pep_list_source"`
+
+	// CheckTimeouts gives each check (ofac, pep, risk_profile, velocity,
+	// patterns, reputation, geoip, shared_device, counterparty,
+	// account_denylist) its own deadline within MaxScreeningLatency so one
+	// slow check cannot use up the budget of the others. A check that times
+	// out is recorded as skipped.
+	CheckTimeouts map[string]time.Duration `mapstructure:"check_timeouts"`
+
+	ParallelChecks      int     `mapstructure:"parallel_checks"`
+	FuzzyMatchThreshold float64 `mapstructure:"fuzzy_match_threshold"`
+
+	// PEPPartialMatchThreshold is the least score a PEP match needs when
+	// it relies on initials or leaves out middle names ("V. Putin" for
+	// "Vladimir Vladimirovich Putin"); 1 turns such matching off
+	PEPPartialMatchThreshold float64 `mapstructure:"pep_partial_match_threshold"`
+
+	// BatchConcurrency bounds the OFAC checks a batch screening runs at
+	// once, leaving Redis capacity for live screening
+	BatchConcurrency int `mapstructure:"batch_concurrency"`
+
+	// NameMatchers selects the fuzzy name matching algorithm per list (ofac,
+	// pep): jaro_winkler, levenshtein or phonetic (Double Metaphone)
+	NameMatchers map[string]string `mapstructure:"name_matchers"`
+
+	// NameFolding folds names before matching: none, diacritics (strip
+	// accents) or transliterate (also romanize Cyrillic, Greek, Arabic,
+	// kana and Hangul). Off by default while its false-positive impact is
+	// measured.
+	NameFolding string `mapstructure:"name_folding"`
+
+	// EntityStopwords are corporate suffixes and filler words stripped from
+	// names matched against SDN entities (not individuals), e.g. "llc",
+	// "ltd", "company", "trading". Empty disables stripping.
+	EntityStopwords []string `mapstructure:"entity_stopwords"`
+
+	// Former PEPs keep the full PEP weight for PEPCoolingOff after leaving
+	// office; beyond that their weight falls in proportion to the time
+	// since, down to FormerPEPMinWeight
+	PEPCoolingOff      time.Duration `mapstructure:"pep_cooling_off"`
+	FormerPEPMinWeight int           `mapstructure:"former_pep_min_weight"`
+
+	// AmountBands scale the OFAC and PEP risk factor weights with the
+	// transaction's USD amount: the multiplier of the highest band whose
+	// min_usd the amount reaches applies. Below every band, or in a
+	// currency that cannot be converted to USD, weights are unchanged.
+	AmountBands []AmountBandConfig `mapstructure:"amount_bands"`
+
+	// Sanctions list delta re-screening
+	ListRefreshInterval time.Duration `mapstructure:"list_refresh_interval"`
+	OFACDeltaMaxEntries int           `mapstructure:"ofac_delta_max_entries"`
+
+	// RetroactiveRescreenRate caps how many stored transactions per second a
+	// retroactive re-screen replays through the engine, so a backfill after
+	// a list change never crowds out live screening
+	RetroactiveRescreenRate float64 `mapstructure:"retroactive_rescreen_rate"`
+
+	// Warm-up loads the OFAC and PEP indexes and primes their Redis caches
+	// at startup; the readiness probe fails until it completes. A list that
+	// fails to warm is retried every WarmupRetryInterval. When disabled the
+	// indexes get one best-effort load and readiness does not wait.
+	WarmupEnabled       bool          `mapstructure:"warmup_enabled"`
+	WarmupRetryInterval time.Duration `mapstructure:"warmup_retry_interval"`
+
+	// EnabledChecks lists the checks (ofac, pep, risk_profile, velocity,
+	// patterns, reputation, geoip, shared_device, counterparty,
+	// account_denylist) screening runs. Disabled checks are never started, so they cannot
+	// fail, and are listed in each result's skipped_checks; e.g. drop pep
+	// without a PEP data license.
+	EnabledChecks []string `mapstructure:"enabled_checks"`
+
+	// FailClosedChecks lists the checks (ofac, pep, risk_profile, velocity,
+	// patterns, reputation, geoip, shared_device, counterparty,
+	// account_denylist) whose failure holds the decision as PENDING. Other checks fail open: the
+	// failure is recorded and screening proceeds.
+	FailClosedChecks []string `mapstructure:"fail_closed_checks"`
+
+	// ResultCacheTTL is how long a result is reused for an identical
+	// transaction payload; zero disables the result cache
+	ResultCacheTTL time.Duration `mapstructure:"result_cache_ttl"`
+
+	// Device and IP reputation risk factors
+	Reputation ReputationConfig `mapstructure:"reputation"`
+
+	// IP geolocation and impossible-travel risk factors
+	GeoIP GeoIPConfig `mapstructure:"geoip"`
+
+	// Devices and IP addresses shared across many users
+	SharedDevice SharedDeviceConfig `mapstructure:"shared_device"`
+
+	// Risk accumulated per external account across all users
+	Counterparty CounterpartyConfig `mapstructure:"counterparty"`
+
+	// AccountDenylistWeight is the risk factor weight for a sender or
+	// receiver account on the internal denylist; a hit also forces a block
+	AccountDenylistWeight int `mapstructure:"account_denylist_weight"`
+
+	// Circuit breakers around the screening path's Redis and Postgres lookups
+	Breakers BreakersConfig `mapstructure:"breakers"`
+
+	// OFACCacheRetry retries failed OFAC cache name lookups before the check
+	// falls back to the in-memory index
+	OFACCacheRetry RetryConfig `mapstructure:"ofac_cache_retry"`
+
+	// Shadow scores every screening a second time with candidate settings,
+	// recorded alongside the enforced decision for comparison
+	Shadow ShadowConfig `mapstructure:"shadow"`
+}
+
+// ShadowConfig holds candidate scoring settings evaluated in shadow mode.
+// Settings left unset inherit the enforced patterns and compliance
+// configuration, so only the values under evaluation need to be given.
+// Shadow results are stored and counted but never affect a decision.
+type ShadowConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	HighValueThreshold float64                 `mapstructure:"high_value_threshold"`
+	CountryRiskTiers   []CountryRiskTierConfig `mapstructure:"country_risk_tiers"`
+	TransactionRules   []TransactionRuleConfig `mapstructure:"transaction_rules"`
+
+	// Decision thresholds keyed by risk tier; tiers not listed inherit
+	// compliance.decision_thresholds
+	DecisionThresholds map[string]DecisionThresholdsConfig `mapstructure:"decision_thresholds"`
+}
+
+// Patterns returns the enforced patterns configuration with the shadow
+// overrides applied
+func (s *ShadowConfig) Patterns(enforced *PatternsConfig) *PatternsConfig {
+	patterns := *enforced
+	if s.HighValueThreshold > 0 {
+		patterns.HighValueThreshold = s.HighValueThreshold
+	}
+	if s.CountryRiskTiers != nil {
+		patterns.CountryRiskTiers = s.CountryRiskTiers
+	}
+	if s.TransactionRules != nil {
+		patterns.TransactionRules = s.TransactionRules
+	}
+	return &patterns
+}
+
+// Thresholds returns the enforced decision thresholds with the shadow
+// overrides applied
+func (s *ShadowConfig) Thresholds(enforced map[string]DecisionThresholdsConfig) map[string]DecisionThresholdsConfig {
+	thresholds := make(map[string]DecisionThresholdsConfig, len(enforced)+len(s.DecisionThresholds))
+	for tier, t := range enforced {
+		thresholds[tier] = t
+	}
+	for tier, t := range s.DecisionThresholds {
+		thresholds[tier] = t
+	}
+	return thresholds
+}
+
+// AmountBandConfig is a transaction size band for OFAC and PEP weighting
+type AmountBandConfig struct {
+	MinUSD     float64 `mapstructure:"min_usd"`
+	Multiplier float64 `mapstructure:"multiplier"` // between 1 and 3
+}
+
+// ReputationConfig holds the local denylists and risk weights for the
+// device and IP reputation check
+type ReputationConfig struct {
+	DeniedIPs           []string `mapstructure:"denied_ips"`            // IPs or CIDR ranges
+	AnonymizerRanges    []string `mapstructure:"anonymizer_ranges"`     // Tor exit nodes and proxies, IPs or CIDR ranges
+	DeniedDevices       []string `mapstructure:"denied_devices"`        // device IDs
+	BlockedLookbackDays int      `mapstructure:"blocked_lookback_days"` // history searched for blocked transactions on the device
+
+	DeniedIPWeight      int `mapstructure:"denied_ip_weight"`
+	AnonymizerWeight    int `mapstructure:"anonymizer_weight"`
+	DeniedDeviceWeight  int `mapstructure:"denied_device_weight"`
+	BlockedDeviceWeight int `mapstructure:"blocked_device_weight"`
+	GeoMismatchWeight   int `mapstructure:"geo_mismatch_weight"`
+}
+
+// GeoIPConfig holds the GeoIP database and risk weights for the IP
+// geolocation check. Without a database the check still detects impossible
+// travel between transactions whose geo location is given as coordinates.
+type GeoIPConfig struct {
+	DatabasePath string `mapstructure:"database_path"` // MaxMind GeoIP2 or GeoLite2 Country or City .mmdb file
+
+	// Travel between consecutive transactions faster than MaxTravelSpeedKmh
+	// is impossible. Moves shorter than MinTravelDistanceKm are ignored, as
+	// IP geolocation is no more precise than that.
+	MaxTravelSpeedKmh   float64       `mapstructure:"max_travel_speed_kmh"`
+	MinTravelDistanceKm float64       `mapstructure:"min_travel_distance_km"`
+	LocationTTL         time.Duration `mapstructure:"location_ttl"` // how long a user's last location is kept
+
+	HighRiskIPWeight       int `mapstructure:"high_risk_ip_weight"`
+	ImpossibleTravelWeight int `mapstructure:"impossible_travel_weight"`
+}
+
+// SharedDeviceConfig flags a device or IP address once more than MaxUsers
+// users have transacted from it within Window. Only the MaxTracked most
+// recently seen users are kept per device or IP, bounding memory.
+type SharedDeviceConfig struct {
+	Window     time.Duration `mapstructure:"window"`
+	MaxUsers   int           `mapstructure:"max_users"`
+	MaxTracked int           `mapstructure:"max_tracked"`
+	SampleSize int           `mapstructure:"sample_size"` // user IDs listed in the risk factor
+	Weight     int           `mapstructure:"weight"`
+}
+
+// CounterpartyConfig scores an external account by the blocked and
+// suspicious screenings and SAR filings it has been party to, across all
+// users. Each counts for the given points, decaying by half every HalfLife;
+// a total below MinWeight raises no factor and one above MaxWeight is
+// capped.
+type CounterpartyConfig struct {
+	HalfLife         time.Duration `mapstructure:"half_life"`
+	BlockedPoints    float64       `mapstructure:"blocked_points"`
+	SuspiciousPoints float64       `mapstructure:"suspicious_points"`
+	SARPoints        float64       `mapstructure:"sar_points"`
+	MinWeight        int           `mapstructure:"min_weight"`
+	MaxWeight        int           `mapstructure:"max_weight"`
+}
+
+// BreakersConfig holds per-dependency circuit breaker settings
+type BreakersConfig struct {
+	OFACCache     CircuitBreakerConfig `mapstructure:"ofac_cache"`
+	PEPCache      CircuitBreakerConfig `mapstructure:"pep_cache"`
+	VelocityCache CircuitBreakerConfig `mapstructure:"velocity_cache"`
+	RiskProfiles  CircuitBreakerConfig `mapstructure:"risk_profiles"`
+}
+
+// CircuitBreakerConfig configures a dependency circuit breaker
+type CircuitBreakerConfig struct {
+	FailureThreshold uint32        `mapstructure:"failure_threshold"`  // consecutive failures before opening
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`       // time open before a half-open probe
+	HalfOpenRequests uint32        `mapstructure:"half_open_requests"` // probes allowed while half-open
+}
+
+// RetryConfig retries a failed call up to Attempts more times, waiting
+// Backoff before the first retry and doubling it for each one after, with
+// jitter of up to half the wait either way
+type RetryConfig struct {
+	Attempts int           `mapstructure:"attempts"`
+	Backoff  time.Duration `mapstructure:"backoff"`
 }
 
 // PatternsConfig holds pattern detection configuration
@@ -88,30 +376,254 @@ type PatternsConfig struct {
 	StructuringThreshold   float64 `mapstructure:"structuring_threshold"`
 	StructuringMinTxCount  int     `mapstructure:"structuring_min_tx_count"`
 
+	// Smurfing: small inbound deposits from many distinct sources into one
+	// account. Deposits below StructuringThreshold count as small.
+	SmurfingWindowHours int `mapstructure:"smurfing_window_hours"`
+	SmurfingMinSources  int `mapstructure:"smurfing_min_sources"`
+
+	// Mixing/layering: funds moved through chains of accounts within minutes
+	MixingWindowMins    int `mapstructure:"mixing_window_mins"`
+	MixingMaxHopGapMins int `mapstructure:"mixing_max_hop_gap_mins"`
+	MixingMinHops       int `mapstructure:"mixing_min_hops"`
+	MixingMinFanOut     int `mapstructure:"mixing_min_fan_out"`
+
+	// Unusual time: hours the user rarely transacts at, learned from
+	// UnusualTimeBaselineDays of history once UnusualTimeMinHistory
+	// transactions exist, plus SuspiciousHours (0-23) flagged for everyone
+	UnusualTimeBaselineDays int     `mapstructure:"unusual_time_baseline_days"`
+	UnusualTimeMinHistory   int     `mapstructure:"unusual_time_min_history"`
+	UnusualTimeRareShare    float64 `mapstructure:"unusual_time_rare_share"`
+	SuspiciousHours         []int   `mapstructure:"suspicious_hours"`
+
+	// Dormant reactivation: a transaction after DormancyDays without any,
+	// above the user's average over DormancyLookbackDays or at least
+	// DormantAmountFloor in the base currency
+	DormancyDays         int     `mapstructure:"dormancy_days"`
+	DormancyLookbackDays int     `mapstructure:"dormancy_lookback_days"`
+	DormantAmountFloor   float64 `mapstructure:"dormant_amount_floor"`
+
 	// Rapid cycling
 	RapidCyclingWindowMins int     `mapstructure:"rapid_cycling_window_mins"`
 	RapidCyclingThreshold  float64 `mapstructure:"rapid_cycling_threshold"`
 
-	// Velocity
-	VelocityBaselineDays    int     `mapstructure:"velocity_baseline_days"`
-	VelocitySpikeMultiplier float64 `mapstructure:"velocity_spike_multiplier"`
+	// Velocity: a day's amount is a spike once it exceeds the user's mean
+	// daily amount by VelocityStdDevMultiplier standard deviations. Users
+	// with fewer than VelocityMinHistoryDays of history are compared against
+	// VelocitySpikeMultiplier times their mean instead.
+	VelocityBaselineDays     int     `mapstructure:"velocity_baseline_days"`
+	VelocitySpikeMultiplier  float64 `mapstructure:"velocity_spike_multiplier"`
+	VelocityStdDevMultiplier float64 `mapstructure:"velocity_std_dev_multiplier"`
+	VelocityMinHistoryDays   int     `mapstructure:"velocity_min_history_days"`
+
+	// Baselines are recomputed every VelocityBaselineInterval from the last
+	// VelocityBaselineDays of screened transactions, using classic (mean
+	// and standard deviation) or robust (trimmed mean and median absolute
+	// deviation) statistics. Robust statistics keep one large legitimate
+	// transfer from skewing the baseline; VelocityBaselineTrim is the share
+	// of days dropped from each end for the trimmed mean.
+	VelocityBaselineMethod   string        `mapstructure:"velocity_baseline_method"`
+	VelocityBaselineTrim     float64       `mapstructure:"velocity_baseline_trim"`
+	VelocityBaselineInterval time.Duration `mapstructure:"velocity_baseline_interval"`
 
-	// Geographic
-	GeoConcentrationThreshold float64  `mapstructure:"geo_concentration_threshold"`
-	HighRiskCountries         []string `mapstructure:"high_risk_countries"`
+	// Risk profile: a transaction is anomalous once its base-currency amount
+	// reaches ProfileAmountMultiplier times the user's average transaction
+	// amount, and moderately so at half that
+	ProfileAmountMultiplier float64 `mapstructure:"profile_amount_multiplier"`
+
+	// Amount heuristics, each a low-weight factor of its own: an amount of
+	// at least RoundAmountFloor in the base currency that is a whole
+	// multiple of RoundAmountUnit in the currency it was sent in; a
+	// base-currency amount within ThresholdAdjacentBand below the CTR
+	// threshold; and an amount sent to the same counterparty, in the same
+	// currency, at least RepeatedAmountMinCount times before within
+	// RepeatedAmountWindow. A weight of 0 disables the heuristic.
+	RoundAmountFloor        float64       `mapstructure:"round_amount_floor"`
+	RoundAmountUnit         float64       `mapstructure:"round_amount_unit"`
+	RoundAmountWeight       int           `mapstructure:"round_amount_weight"`
+	ThresholdAdjacentBand   float64       `mapstructure:"threshold_adjacent_band"`
+	ThresholdAdjacentWeight int           `mapstructure:"threshold_adjacent_weight"`
+	RepeatedAmountWindow    time.Duration `mapstructure:"repeated_amount_window"`
+	RepeatedAmountMinCount  int           `mapstructure:"repeated_amount_min_count"`
+	RepeatedAmountWeight    int           `mapstructure:"repeated_amount_weight"`
+
+	// Description keywords: a transaction whose description or reference
+	// contains any of DescriptionKeywords adds a DESCRIPTION_KEYWORD factor
+	// weighted by the heaviest keyword found
+	DescriptionKeywords []DescriptionKeywordConfig `mapstructure:"description_keywords"`
+
+	// Geographic. CountryRiskTiers, CountryRiskRatings and
+	// CountryRiskRatingPoints are the country risk table in force until one
+	// is saved through the admin API; saved tables are reloaded every
+	// CountryRiskRefreshInterval. Ratings (country -> 0-100) grade the
+	// countries outside the tiers: a rated counterparty country adds its
+	// rating's share of CountryRiskRatingPoints.
+	GeoConcentrationThreshold  float64                 `mapstructure:"geo_concentration_threshold"`
+	CountryRiskTiers           []CountryRiskTierConfig `mapstructure:"country_risk_tiers"`
+	CountryRiskRatings         map[string]int          `mapstructure:"country_risk_ratings"`
+	CountryRiskRatingPoints    int                     `mapstructure:"country_risk_rating_points"`
+	CountryRiskRefreshInterval time.Duration           `mapstructure:"country_risk_refresh_interval"`
+
+	// Transaction rules
+	HighValueThreshold float64                 `mapstructure:"high_value_threshold"`
+	TransactionRules   []TransactionRuleConfig `mapstructure:"transaction_rules"`
 
 	// Batch processing
 	BatchSize     int           `mapstructure:"batch_size"`
 	BatchInterval time.Duration `mapstructure:"batch_interval"`
 }
 
+// CountryRiskTierConfig is a tier of the country risk table: a
+// counterparty in one of Countries (ISO 3166-1 alpha-2) adds Points to the
+// risk score, and EDDRequired marks the tier for enhanced due diligence
+type CountryRiskTierConfig struct {
+	Name        string   `mapstructure:"name"`
+	Points      int      `mapstructure:"points"`
+	EDDRequired bool     `mapstructure:"edd_required"`
+	Countries   []string `mapstructure:"countries"`
+}
+
+// DescriptionKeywordConfig is a watchlist entry looked for in transaction
+// descriptions and references: Term as a whole word or phrase, whose spaces
+// also match punctuation such as "." or "-", or Pattern as a regular
+// expression. Either is matched ignoring case and diacritics. Name labels
+// the entry in risk factor details.
+type DescriptionKeywordConfig struct {
+	Name    string `mapstructure:"name"`
+	Term    string `mapstructure:"term"`
+	Pattern string `mapstructure:"pattern"`
+	Weight  int    `mapstructure:"weight"`
+}
+
+// TransactionRuleConfig adjusts risk scoring for a (type, channel, direction)
+// combination. Empty Type, Channel or Direction match any value.
+type TransactionRuleConfig struct {
+	Name               string  `mapstructure:"name"`
+	Type               string  `mapstructure:"type"`
+	Channel            string  `mapstructure:"channel"`
+	Direction          string  `mapstructure:"direction"`
+	ScoreAdjustment    int     `mapstructure:"score_adjustment"`
+	HighValueThreshold float64 `mapstructure:"high_value_threshold"` // 0 keeps the default
+}
+
 // ComplianceConfig holds compliance reporting configuration
 type ComplianceConfig struct {
 	SARThreshold          float64       `mapstructure:"sar_threshold"`
 	CTRThreshold          float64       `mapstructure:"ctr_threshold"`
 	SARDeadlineDays       int           `mapstructure:"sar_deadline_days"`
+	SARMinNarrativeLength int           `mapstructure:"sar_min_narrative_length"` // characters
 	InvestigationSLA      time.Duration `mapstructure:"investigation_sla"`
 	MaxOpenInvestigations int           `mapstructure:"max_open_investigations"`
+
+	// Supervisors lists the actor IDs with supervisor authority, such as
+	// removing users from the watchlist
+	Supervisors []string `mapstructure:"supervisors"`
+
+	// SLA monitoring
+	SLAScanInterval     time.Duration `mapstructure:"sla_scan_interval"`
+	SLAEscalationPolicy string        `mapstructure:"sla_escalation_policy"` // status, priority, both
+	SLAAtRiskShare      float64       `mapstructure:"sla_at_risk_share"`     // share of SLA elapsed before an unassigned case is escalated
+
+	// SAR deadline monitoring
+	FilingScanInterval     time.Duration `mapstructure:"filing_scan_interval"`
+	SARDeadlineWarningDays []int         `mapstructure:"sar_deadline_warning_days"` // lead times, e.g. 7, 3, 1
+
+	// Alerts for the same user, type, pattern and rule detected within this
+	// window of the previous occurrence are folded into one; zero disables
+	AlertCorrelationWindow time.Duration `mapstructure:"alert_correlation_window"`
+
+	// Reporting institution details used in FinCEN BSA E-Filing exports
+	FilingInstitution FilingInstitutionConfig `mapstructure:"filing_institution"`
+
+	// Decision thresholds keyed by risk tier ("default", "edd")
+	DecisionThresholds map[string]DecisionThresholdsConfig `mapstructure:"decision_thresholds"`
+
+	// IANA time zone whose calendar days and months bound MI reports
+	ReportTimezone string `mapstructure:"report_timezone"`
+
+	// Most screening results a single bulk export may contain
+	ExportMaxRows int `mapstructure:"export_max_rows"`
+
+	// Archival of aged records out of Postgres
+	Retention RetentionConfig `mapstructure:"retention"`
+
+	// Dismissal of stale, low-confidence alerts nobody has picked up
+	AlertAutoClose AlertAutoCloseConfig `mapstructure:"alert_auto_close"`
+
+	// Priority and escalation of alerts by risk score
+	AlertPriority AlertPriorityConfig `mapstructure:"alert_priority"`
+}
+
+// AlertPriorityConfig maps the risk score of every alert raised to a
+// priority, taking the band with the highest min_score the score reaches;
+// an alert keeps a higher priority set by the rule that raised it. Alerts
+// scoring at least EscalationScore, or of EscalationPriority or above when
+// it is set, need escalation; with AutoEscalate they are escalated to a new
+// investigation as they are raised.
+type AlertPriorityConfig struct {
+	Bands              []AlertPriorityBandConfig `mapstructure:"bands"`
+	EscalationScore    int                       `mapstructure:"escalation_score"`
+	EscalationPriority string                    `mapstructure:"escalation_priority"` // empty escalates on score alone
+	AutoEscalate       bool                      `mapstructure:"auto_escalate"`
+}
+
+// AlertPriorityBandConfig gives alerts scoring at least MinScore a priority
+type AlertPriorityBandConfig struct {
+	MinScore int    `mapstructure:"min_score"`
+	Priority string `mapstructure:"priority"` // LOW, MEDIUM, HIGH or CRITICAL
+}
+
+// AlertAutoCloseConfig controls the job that dismisses NEW alerts whose
+// confidence is below MaxConfidence and which have seen no new occurrence
+// or update for MinAge. Watchlist hits, alerts on transactions with an OFAC
+// match and alerts linked to an open investigation are never closed.
+type AlertAutoCloseConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Interval      time.Duration `mapstructure:"interval"`
+	MaxConfidence float64       `mapstructure:"max_confidence"`
+	MinAge        time.Duration `mapstructure:"min_age"`
+	BatchSize     int           `mapstructure:"batch_size"`
+
+	// Alert types and detection rules that are never closed automatically,
+	// in addition to the built-in exclusions
+	ExcludeTypes []string `mapstructure:"exclude_types"`
+	ExcludeRules []string `mapstructure:"exclude_rules"`
+}
+
+// RetentionConfig controls the job that moves aged records out of Postgres
+// into compressed archive files in object storage
+type RetentionConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+
+	// How long records stay in Postgres, keyed by entity type
+	// ("screening_results", "alerts"); entity types not listed are kept
+	HotWindows map[string]time.Duration `mapstructure:"hot_windows"`
+
+	DeleteBatchSize int    `mapstructure:"delete_batch_size"` // rows deleted per statement
+	MaxDaysPerRun   int    `mapstructure:"max_days_per_run"`  // days archived per entity type per run
+	ArchivePrefix   string `mapstructure:"archive_prefix"`    // object key prefix for archive files
+}
+
+// FilingInstitutionConfig identifies the filing institution and transmitter
+// on FinCEN BSA E-Filing submissions
+type FilingInstitutionConfig struct {
+	Name                   string `mapstructure:"name"`
+	TIN                    string `mapstructure:"tin"` // EIN
+	RSSDNumber             string `mapstructure:"rssd_number"`
+	Address                string `mapstructure:"address"`
+	City                   string `mapstructure:"city"`
+	State                  string `mapstructure:"state"`
+	ZipCode                string `mapstructure:"zip_code"`
+	Country                string `mapstructure:"country"`
+	ContactOffice          string `mapstructure:"contact_office"`
+	ContactPhone           string `mapstructure:"contact_phone"`
+	TransmitterControlCode string `mapstructure:"transmitter_control_code"` // TCC issued by FinCEN
+}
+
+// DecisionThresholdsConfig holds the score cutoffs for screening decisions
+type DecisionThresholdsConfig struct {
+	Suspicious int `mapstructure:"suspicious"`
+	Blocked    int `mapstructure:"blocked"`
 }
 
 // TelemetryConfig holds observability configuration
@@ -125,12 +637,182 @@ type TelemetryConfig struct {
 
 // SecurityConfig holds security configuration
 type SecurityConfig struct {
-	EncryptionKeys     []string `mapstructure:"encryption_keys"`
-	CurrentKeyVersion  int      `mapstructure:"current_key_version"`
-	AuditHMACSecret    string   `mapstructure:"audit_hmac_secret"`
-	JWTSecret          string   `mapstructure:"jwt_secret"`
-	AllowedOrigins     []string `mapstructure:"allowed_origins"`
-	RateLimitPerMinute int      `mapstructure:"rate_limit_per_minute"`
+	// EncryptionKeys are base64-encoded 32-byte AES keys; the key at index i
+	// has version i+1. Keep retired keys until rotation has moved every row
+	// to CurrentKeyVersion.
+	EncryptionKeys      []string      `mapstructure:"encryption_keys"`
+	CurrentKeyVersion   int           `mapstructure:"current_key_version"`
+	KeyRotationInterval time.Duration `mapstructure:"key_rotation_interval"`
+	AuditHMACSecret     string        `mapstructure:"audit_hmac_secret"`
+	JWTSecret           string        `mapstructure:"jwt_secret"`
+	AllowedOrigins      []string      `mapstructure:"allowed_origins"`
+	RateLimitPerMinute  int           `mapstructure:"rate_limit_per_minute"`
+
+	// AdminRateLimitPerMinute caps calls to admin endpoints per caller
+	AdminRateLimitPerMinute int `mapstructure:"admin_rate_limit_per_minute"`
+}
+
+// TenancyConfig holds the tenants the deployment serves. Each tenant's
+// records are kept apart, and its decision thresholds, country risk tiers
+// and SAR/CTR thresholds may be overridden in its stored settings, falling
+// back to this configuration.
+type TenancyConfig struct {
+	// Tenants lists the tenant IDs requests may act for; requests naming
+	// no tenant act for "default", which must be listed
+	Tenants []string `mapstructure:"tenants"`
+
+	// SettingsRefreshInterval is how often stored tenant settings are
+	// reloaded, so changes saved on another instance take effect
+	SettingsRefreshInterval time.Duration `mapstructure:"settings_refresh_interval"`
+}
+
+// Allows reports whether id is a configured tenant
+func (c *TenancyConfig) Allows(id string) bool {
+	return slices.Contains(c.Tenants, id)
+}
+
+// StorageConfig holds object storage configuration for evidence files
+type StorageConfig struct {
+	Backend  string `mapstructure:"backend"`   // local or s3
+	LocalDir string `mapstructure:"local_dir"` // root directory for the local backend
+
+	S3Endpoint        string `mapstructure:"s3_endpoint"` // defaults to the AWS regional endpoint
+	S3Region          string `mapstructure:"s3_region"`
+	S3Bucket          string `mapstructure:"s3_bucket"`
+	S3AccessKeyID     string `mapstructure:"s3_access_key_id"`
+	S3SecretAccessKey string `mapstructure:"s3_secret_access_key"`
+}
+
+// CurrencyConfig holds the exchange rates used to compare amounts in any
+// currency against thresholds set in the base currency
+type CurrencyConfig struct {
+	// BaseCurrency is the ISO 4217 currency that high-value, structuring
+	// and CTR thresholds are expressed in
+	BaseCurrency string `mapstructure:"base_currency"`
+
+	// Rates is the static rate table, as base currency per unit of each
+	// ISO 4217 currency. The base currency itself is always 1.
+	Rates map[string]float64 `mapstructure:"rates"`
+
+	// RatesURL optionally points at a JSON rates feed of the form
+	// {"base": "USD", "rates": {"EUR": 0.92}}, quoting units of each
+	// currency per unit of the base. It is fetched every RefreshInterval
+	// and its rates take precedence over the static table.
+	RatesURL        string        `mapstructure:"rates_url"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// WebhooksConfig configures outbound event notifications to downstream
+// systems, such as a card platform that freezes funds on a block.
+// Endpoints are listed in Subscribers or registered through the admin API.
+// Deliveries are retried MaxAttempts times with exponential backoff from
+// RetryBackoff; ones that still fail are written to the dead-letter table,
+// and an endpoint is disabled after DisableAfterFailures such events in a
+// row. Each instance reloads endpoints every RefreshInterval.
+type WebhooksConfig struct {
+	Subscribers          []WebhookSubscriberConfig `mapstructure:"subscribers"`
+	Timeout              time.Duration             `mapstructure:"timeout"` // per attempt
+	MaxAttempts          int                       `mapstructure:"max_attempts"`
+	RetryBackoff         time.Duration             `mapstructure:"retry_backoff"`
+	QueueSize            int                       `mapstructure:"queue_size"`
+	Workers              int                       `mapstructure:"workers"`
+	DisableAfterFailures int                       `mapstructure:"disable_after_failures"`
+	RefreshInterval      time.Duration             `mapstructure:"refresh_interval"`
+}
+
+// WebhookSubscriberConfig registers one webhook endpoint, subscribed to the
+// screening events of Decisions of TenantID. Each request is signed with
+// HMAC-SHA256 under Secret.
+type WebhookSubscriberConfig struct {
+	Name      string   `mapstructure:"name"`
+	TenantID  string   `mapstructure:"tenant_id"` // defaults to "default"
+	URL       string   `mapstructure:"url"`
+	Secret    string   `mapstructure:"secret"`
+	Decisions []string `mapstructure:"decisions"` // defaults to BLOCKED and SUSPICIOUS
+}
+
+// NotificationsConfig configures chat notifications to on-call compliance
+// staff. Each route sends an event type to one or more channels; events
+// without a route are not sent. Non-critical events are not sent during
+// QuietHours. Sends are retried MaxAttempts times with exponential backoff
+// from RetryBackoff, and an event is sent at most once per DedupWindow.
+type NotificationsConfig struct {
+	Channels   []NotificationChannelConfig `mapstructure:"channels"`
+	Routes     []NotificationRouteConfig   `mapstructure:"routes"`
+	QuietHours QuietHoursConfig            `mapstructure:"quiet_hours"`
+
+	// LinkTemplate builds the deep link in each message; {kind} is replaced
+	// by "screening" or "investigation" and {id} by the record's ID
+	LinkTemplate string `mapstructure:"link_template"`
+
+	Timeout      time.Duration `mapstructure:"timeout"` // per attempt
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	QueueSize    int           `mapstructure:"queue_size"`
+	Workers      int           `mapstructure:"workers"`
+	DedupWindow  time.Duration `mapstructure:"dedup_window"`
+}
+
+// NotificationChannelConfig is a chat incoming webhook. Type is "slack" or
+// "teams".
+type NotificationChannelConfig struct {
+	Name       string `mapstructure:"name"`
+	Type       string `mapstructure:"type"`
+	WebhookURL string `mapstructure:"webhook_url"`
+}
+
+// NotificationRouteConfig sends an event type to the named channels
+type NotificationRouteConfig struct {
+	Event    string   `mapstructure:"event"`
+	Channels []string `mapstructure:"channels"`
+}
+
+// QuietHoursConfig is a daily window, as HH:MM in Timezone, in which
+// non-critical notifications are dropped. The window may span midnight;
+// it is off when Start is empty.
+type QuietHoursConfig struct {
+	Start    string `mapstructure:"start"`
+	End      string `mapstructure:"end"`
+	Timezone string `mapstructure:"timezone"`
+}
+
+// EmailConfig configures outgoing email. In DryRun mode emails are
+// rendered and logged instead of sent, and no SMTP server is needed.
+type EmailConfig struct {
+	From   string       `mapstructure:"from"`
+	DryRun bool         `mapstructure:"dry_run"`
+	SMTP   SMTPConfig   `mapstructure:"smtp"`
+	Digest DigestConfig `mapstructure:"digest"`
+}
+
+// SMTPConfig is the mail server emails are sent through. TLS is starttls
+// (upgrade a plain connection, usually port 587), tls (implicit TLS,
+// usually port 465) or none.
+type SMTPConfig struct {
+	Host     string        `mapstructure:"host"`
+	Port     int           `mapstructure:"port"`
+	Username string        `mapstructure:"username"`
+	Password string        `mapstructure:"password"`
+	TLS      string        `mapstructure:"tls"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// DigestConfig schedules the morning email listing each analyst's open
+// investigations, new alerts matching their alert filter and filings
+// awaiting their review. It is sent once a day from SendAt (HH:MM in
+// Timezone); instances check whether it is due every CheckInterval.
+type DigestConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	SendAt        string        `mapstructure:"send_at"`
+	Timezone      string        `mapstructure:"timezone"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+
+	// MaxItems caps each section of a digest
+	MaxItems int `mapstructure:"max_items"`
+
+	// LinkTemplate builds the link for each item; {kind} is replaced by
+	// "investigation", "alert" or "filing" and {id} by the record's ID
+	LinkTemplate string `mapstructure:"link_template"`
 }
 
 // Load loads configuration from environment and config files
@@ -169,12 +851,15 @@ func Load() (*Config, error) {
 func setDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.port", 8084)
+	v.SetDefault("server.grpc_port", 9084)
 	v.SetDefault("server.metrics_port", 9094)
 	v.SetDefault("server.read_timeout", "30s")
 	v.SetDefault("server.write_timeout", "30s")
 	v.SetDefault("server.idle_timeout", "60s")
 	v.SetDefault("server.shutdown_timeout", "30s")
 	v.SetDefault("server.max_request_size", 1048576) // 1MB
+	v.SetDefault("server.idempotency_ttl", "24h")
+	v.SetDefault("server.user_activity_budget", "2s")
 
 	// Database defaults
 	v.SetDefault("database.host", "localhost")
@@ -210,25 +895,185 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("kafka.aml_events_topic", "banking.aml.events")
 	v.SetDefault("kafka.alerts_topic", "banking.aml.alerts")
 	v.SetDefault("kafka.audit_topic", "banking.audit.logs")
+	v.SetDefault("kafka.dead_letter_topic", "banking.aml.dead-letter")
+	v.SetDefault("kafka.handler_max_attempts", 3)
+	v.SetDefault("kafka.handler_retry_backoff", "1s")
+	v.SetDefault("kafka.transaction_workers", 4)
+	v.SetDefault("kafka.priority_weights", map[string]int{"URGENT": 8, "HIGH": 3, "NORMAL": 1})
+	v.SetDefault("kafka.priority_queue_size", 500)
 
 	// Screening defaults
 	v.SetDefault("screening.ofac_update_interval", "24h")
 	v.SetDefault("screening.pep_update_interval", "168h") // 7 days
+	v.SetDefault("screening.ofac_list_source", "US Treasury OFAC SDN List")
+	v.SetDefault("screening.pep_list_source", "PEP data provider")
 	v.SetDefault("screening.max_screening_latency", "200ms")
+	v.SetDefault("screening.check_timeouts", map[string]interface{}{
+		"ofac":             "5ms",
+		"pep":              "10ms",
+		"risk_profile":     "50ms",
+		"velocity":         "10ms",
+		"patterns":         "100ms",
+		"reputation":       "50ms",
+		"geoip":            "20ms",
+		"shared_device":    "10ms",
+		"counterparty":     "20ms",
+		"account_denylist": "10ms",
+	})
 	v.SetDefault("screening.parallel_checks", 6)
+	v.SetDefault("screening.batch_concurrency", 8)
 	v.SetDefault("screening.fuzzy_match_threshold", 0.85)
+	v.SetDefault("screening.pep_partial_match_threshold", 0.88)
+	v.SetDefault("screening.name_folding", "none")
+	v.SetDefault("screening.entity_stopwords", []string{
+		"llc", "ltd", "limited", "inc", "incorporated", "corp", "corporation",
+		"co", "company", "plc", "llp", "lp", "gmbh", "ag", "sa", "srl", "bv", "nv",
+		"trading", "holding", "holdings", "group", "the", "and", "of",
+	})
+	v.SetDefault("screening.name_matchers", map[string]interface{}{
+		"ofac": "jaro_winkler",
+		"pep":  "jaro_winkler",
+	})
+	v.SetDefault("screening.pep_cooling_off", "8760h") // 1 year
+	v.SetDefault("screening.former_pep_min_weight", 5)
+	v.SetDefault("screening.amount_bands", []map[string]interface{}{
+		{"min_usd": 10000, "multiplier": 1.25},
+		{"min_usd": 100000, "multiplier": 1.5},
+		{"min_usd": 1000000, "multiplier": 2.0},
+	})
+	v.SetDefault("screening.list_refresh_interval", "5m")
+	v.SetDefault("screening.ofac_delta_max_entries", 1000)
+	v.SetDefault("screening.retroactive_rescreen_rate", 20)
+	v.SetDefault("screening.warmup_enabled", true)
+	v.SetDefault("screening.warmup_retry_interval", "5s")
+	v.SetDefault("screening.enabled_checks", []string{
+		"ofac", "pep", "risk_profile", "velocity", "patterns", "reputation", "geoip", "shared_device",
+		"counterparty", "account_denylist",
+	})
+	v.SetDefault("screening.fail_closed_checks", []string{"ofac", "pep"})
+	v.SetDefault("screening.result_cache_ttl", "30s")
+	v.SetDefault("screening.reputation.blocked_lookback_days", 90)
+	v.SetDefault("screening.reputation.denied_ip_weight", 25)
+	v.SetDefault("screening.reputation.anonymizer_weight", 15)
+	v.SetDefault("screening.reputation.denied_device_weight", 25)
+	v.SetDefault("screening.reputation.blocked_device_weight", 20)
+	v.SetDefault("screening.reputation.geo_mismatch_weight", 10)
+	v.SetDefault("screening.geoip.max_travel_speed_kmh", 900)
+	v.SetDefault("screening.geoip.min_travel_distance_km", 300)
+	v.SetDefault("screening.geoip.location_ttl", "720h") // 30 days
+	v.SetDefault("screening.geoip.high_risk_ip_weight", 15)
+	v.SetDefault("screening.geoip.impossible_travel_weight", 20)
+	v.SetDefault("screening.shared_device.window", "720h") // 30 days
+	v.SetDefault("screening.shared_device.max_users", 3)
+	v.SetDefault("screening.shared_device.max_tracked", 100)
+	v.SetDefault("screening.shared_device.sample_size", 5)
+	v.SetDefault("screening.shared_device.weight", 20)
+	v.SetDefault("screening.counterparty.half_life", "2160h") // 90 days
+	v.SetDefault("screening.counterparty.blocked_points", 10)
+	v.SetDefault("screening.counterparty.suspicious_points", 4)
+	v.SetDefault("screening.counterparty.sar_points", 15)
+	v.SetDefault("screening.counterparty.min_weight", 5)
+	v.SetDefault("screening.counterparty.max_weight", 40)
+	v.SetDefault("screening.account_denylist_weight", 50)
+	v.SetDefault("screening.shadow.enabled", false)
+	for _, dep := range []string{"ofac_cache", "pep_cache", "velocity_cache", "risk_profiles"} {
+		v.SetDefault("screening.breakers."+dep+".failure_threshold", 5)
+		v.SetDefault("screening.breakers."+dep+".open_timeout", "10s")
+		v.SetDefault("screening.breakers."+dep+".half_open_requests", 1)
+	}
+	v.SetDefault("screening.ofac_cache_retry.attempts", 2)
+	v.SetDefault("screening.ofac_cache_retry.backoff", "20ms")
 
 	// Pattern detection defaults
 	v.SetDefault("patterns.structuring_window_hours", 24)
 	v.SetDefault("patterns.structuring_threshold", 10000.0)
 	v.SetDefault("patterns.structuring_min_tx_count", 3)
+	v.SetDefault("patterns.smurfing_window_hours", 48)
+	v.SetDefault("patterns.smurfing_min_sources", 5)
+	v.SetDefault("patterns.mixing_window_mins", 120)
+	v.SetDefault("patterns.mixing_max_hop_gap_mins", 15)
+	v.SetDefault("patterns.mixing_min_hops", 3)
+	v.SetDefault("patterns.mixing_min_fan_out", 4)
+	v.SetDefault("patterns.unusual_time_baseline_days", 30)
+	v.SetDefault("patterns.unusual_time_min_history", 20)
+	v.SetDefault("patterns.unusual_time_rare_share", 0.02)
+	v.SetDefault("patterns.suspicious_hours", []int{2, 3, 4})
+	v.SetDefault("patterns.dormancy_days", 90)
+	v.SetDefault("patterns.dormancy_lookback_days", 730)
+	v.SetDefault("patterns.dormant_amount_floor", 10000.0)
 	v.SetDefault("patterns.rapid_cycling_window_mins", 60)
 	v.SetDefault("patterns.rapid_cycling_threshold", 0.9)
 	v.SetDefault("patterns.velocity_baseline_days", 30)
 	v.SetDefault("patterns.velocity_spike_multiplier", 10.0)
+	v.SetDefault("patterns.velocity_std_dev_multiplier", 3.0)
+	v.SetDefault("patterns.velocity_min_history_days", 14)
+	v.SetDefault("patterns.velocity_baseline_method", "robust")
+	v.SetDefault("patterns.velocity_baseline_trim", 0.1)
+	v.SetDefault("patterns.velocity_baseline_interval", "24h")
+	v.SetDefault("patterns.profile_amount_multiplier", 10.0)
+	v.SetDefault("patterns.round_amount_floor", 5000.0)
+	v.SetDefault("patterns.round_amount_unit", 1000.0)
+	v.SetDefault("patterns.round_amount_weight", 5)
+	v.SetDefault("patterns.threshold_adjacent_band", 1000.0)
+	v.SetDefault("patterns.threshold_adjacent_weight", 8)
+	v.SetDefault("patterns.repeated_amount_window", "168h")
+	v.SetDefault("patterns.repeated_amount_min_count", 2)
+	v.SetDefault("patterns.repeated_amount_weight", 6)
+	v.SetDefault("patterns.description_keywords", []map[string]interface{}{
+		{"name": "crypto_mixer", "term": "tornado cash", "weight": 15},
+		{"name": "crypto_mixer", "term": "chipmixer", "weight": 15},
+		{"name": "crypto_mixer", "term": "sinbad", "weight": 15},
+		{"name": "crypto_mixer", "term": "blender io", "weight": 15},
+		{"name": "crypto_mixer", "pattern": `\b(samourai|wasabi)\s*(wallet|whirlpool|coinjoin)\b`, "weight": 15},
+		{"name": "shell_company", "term": "shelf company", "weight": 8},
+		{"name": "shell_company", "term": "nominee director", "weight": 8},
+		{"name": "shell_company", "term": "bearer shares", "weight": 8},
+	})
 	v.SetDefault("patterns.geo_concentration_threshold", 0.8)
-	v.SetDefault("patterns.high_risk_countries", []string{
-		"IR", "KP", "SY", "CU", "VE", "MM", "BY", "RU",
+	v.SetDefault("patterns.country_risk_tiers", []map[string]interface{}{
+		{
+			"name":         "FATF_BLACKLIST",
+			"points":       30,
+			"edd_required": true,
+			"countries":    []string{"IR", "KP", "MM"},
+		},
+		{
+			"name":         "FATF_GREYLIST",
+			"points":       20,
+			"edd_required": true,
+			"countries":    []string{"SY", "VE"},
+		},
+		{
+			"name":      "ELEVATED",
+			"points":    15,
+			"countries": []string{"CU", "BY", "RU"},
+		},
+	})
+	v.SetDefault("patterns.country_risk_rating_points", 20)
+	v.SetDefault("patterns.country_risk_refresh_interval", "1m")
+	v.SetDefault("patterns.high_value_threshold", 10000.0)
+	v.SetDefault("patterns.transaction_rules", []map[string]interface{}{
+		{
+			"name":                 "mobile_outbound_transfer",
+			"type":                 "TRANSFER",
+			"channel":              "MOBILE",
+			"direction":            "OUTBOUND",
+			"score_adjustment":     5,
+			"high_value_threshold": 3000.0,
+		},
+		{
+			"name":             "api_outbound_payment",
+			"type":             "PAYMENT",
+			"channel":          "API",
+			"direction":        "OUTBOUND",
+			"score_adjustment": 5,
+		},
+		{
+			"name":             "branch_cash_deposit",
+			"type":             "DEPOSIT",
+			"channel":          "BRANCH",
+			"score_adjustment": 5,
+		},
 	})
 	v.SetDefault("patterns.batch_size", 1000)
 	v.SetDefault("patterns.batch_interval", "5m")
@@ -237,8 +1082,45 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("compliance.sar_threshold", 70.0)
 	v.SetDefault("compliance.ctr_threshold", 10000.0)
 	v.SetDefault("compliance.sar_deadline_days", 30)
+	v.SetDefault("compliance.sar_min_narrative_length", 100)
 	v.SetDefault("compliance.investigation_sla", "72h")
 	v.SetDefault("compliance.max_open_investigations", 100)
+	v.SetDefault("compliance.sla_scan_interval", "5m")
+	v.SetDefault("compliance.sla_escalation_policy", "status")
+	v.SetDefault("compliance.sla_at_risk_share", 0.75)
+	v.SetDefault("compliance.filing_scan_interval", "1h")
+	v.SetDefault("compliance.sar_deadline_warning_days", []int{7, 3, 1})
+	v.SetDefault("compliance.alert_correlation_window", "1h")
+	v.SetDefault("compliance.filing_institution.country", "US")
+	v.SetDefault("compliance.decision_thresholds", map[string]interface{}{
+		"default": map[string]interface{}{"suspicious": 50, "blocked": 80},
+		"edd":     map[string]interface{}{"suspicious": 35, "blocked": 65},
+	})
+	v.SetDefault("compliance.report_timezone", "UTC")
+	v.SetDefault("compliance.export_max_rows", 100000)
+	v.SetDefault("compliance.retention.enabled", false)
+	v.SetDefault("compliance.retention.interval", "1h")
+	v.SetDefault("compliance.retention.hot_windows", map[string]interface{}{
+		"screening_results": "2160h", // 90 days
+		"alerts":            "8760h", // 365 days
+	})
+	v.SetDefault("compliance.retention.delete_batch_size", 1000)
+	v.SetDefault("compliance.retention.max_days_per_run", 7)
+	v.SetDefault("compliance.retention.archive_prefix", "archive")
+	v.SetDefault("compliance.alert_auto_close.enabled", false)
+	v.SetDefault("compliance.alert_auto_close.interval", "1h")
+	v.SetDefault("compliance.alert_auto_close.max_confidence", 0.3)
+	v.SetDefault("compliance.alert_auto_close.min_age", "720h") // 30 days
+	v.SetDefault("compliance.alert_auto_close.batch_size", 500)
+	v.SetDefault("compliance.alert_priority.bands", []map[string]interface{}{
+		{"min_score": 0, "priority": "LOW"},
+		{"min_score": 30, "priority": "MEDIUM"},
+		{"min_score": 60, "priority": "HIGH"},
+		{"min_score": 80, "priority": "CRITICAL"},
+	})
+	v.SetDefault("compliance.alert_priority.escalation_score", 80)
+	v.SetDefault("compliance.alert_priority.escalation_priority", "CRITICAL")
+	v.SetDefault("compliance.alert_priority.auto_escalate", false)
 
 	// Telemetry defaults
 	v.SetDefault("telemetry.service_name", "aml-service")
@@ -249,6 +1131,46 @@ func setDefaults(v *viper.Viper) {
 
 	// Security defaults
 	v.SetDefault("security.current_key_version", 1)
+	v.SetDefault("security.key_rotation_interval", "1h")
 	v.SetDefault("security.rate_limit_per_minute", 1000)
+	v.SetDefault("security.admin_rate_limit_per_minute", 6)
 	v.SetDefault("security.allowed_origins", []string{"*"})
+
+	v.SetDefault("tenancy.tenants", []string{"default"})
+	v.SetDefault("tenancy.settings_refresh_interval", "1m")
+
+	// Storage defaults
+	v.SetDefault("currency.base_currency", "USD")
+	v.SetDefault("currency.rates", map[string]interface{}{})
+	v.SetDefault("currency.refresh_interval", "24h")
+
+	v.SetDefault("storage.backend", "local")
+	v.SetDefault("storage.local_dir", "./data/evidence")
+	v.SetDefault("storage.s3_region", "us-east-1")
+
+	v.SetDefault("webhooks.timeout", "2s")
+	v.SetDefault("webhooks.max_attempts", 5)
+	v.SetDefault("webhooks.retry_backoff", "500ms")
+	v.SetDefault("webhooks.queue_size", 10000)
+	v.SetDefault("webhooks.workers", 4)
+	v.SetDefault("webhooks.disable_after_failures", 10)
+	v.SetDefault("webhooks.refresh_interval", "30s")
+
+	// Notifications defaults
+	v.SetDefault("notifications.quiet_hours.timezone", "UTC")
+	v.SetDefault("notifications.timeout", "5s")
+	v.SetDefault("notifications.max_attempts", 3)
+	v.SetDefault("notifications.retry_backoff", "1s")
+	v.SetDefault("notifications.queue_size", 1000)
+	v.SetDefault("notifications.workers", 2)
+	v.SetDefault("notifications.dedup_window", "24h")
+
+	// Email defaults
+	v.SetDefault("email.smtp.port", 587)
+	v.SetDefault("email.smtp.tls", "starttls")
+	v.SetDefault("email.smtp.timeout", "10s")
+	v.SetDefault("email.digest.send_at", "07:00")
+	v.SetDefault("email.digest.timezone", "UTC")
+	v.SetDefault("email.digest.check_interval", "15m")
+	v.SetDefault("email.digest.max_items", 25)
 }
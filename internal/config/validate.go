@@ -0,0 +1,491 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Validate checks the configuration for values that would otherwise fail
+// later or silently misbehave. It reports every problem found, not just the
+// first.
+func (c *Config) Validate() error {
+	var v validator
+
+	v.port("server.port", c.Server.Port)
+	v.port("server.grpc_port", c.Server.GRPCPort)
+	v.port("server.metrics_port", c.Server.MetricsPort)
+	v.positiveDuration("server.read_timeout", c.Server.ReadTimeout)
+	v.positiveDuration("server.write_timeout", c.Server.WriteTimeout)
+	v.positiveDuration("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	v.check(c.Server.MaxRequestSize > 0, "server.max_request_size must be positive")
+	v.positiveDuration("server.user_activity_budget", c.Server.UserActivityBudget)
+
+	v.required("database.host", c.Database.Host)
+	v.port("database.port", c.Database.Port)
+	v.required("database.database", c.Database.Database)
+	v.check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive")
+
+	v.required("redis.host", c.Redis.Host)
+	v.port("redis.port", c.Redis.Port)
+
+	v.check(len(c.Kafka.Brokers) > 0, "kafka.brokers must list at least one broker")
+	for i, b := range c.Kafka.Brokers {
+		v.check(b != "", "kafka.brokers[%d] is empty", i)
+	}
+	v.required("kafka.consumer_group", c.Kafka.ConsumerGroup)
+	v.required("kafka.transaction_topic", c.Kafka.TransactionTopic)
+	v.required("kafka.aml_events_topic", c.Kafka.AMLEventsTopic)
+	v.required("kafka.dead_letter_topic", c.Kafka.DeadLetterTopic)
+	v.check(c.Kafka.HandlerMaxAttempts > 0, "kafka.handler_max_attempts must be positive")
+	v.positiveDuration("kafka.handler_retry_backoff", c.Kafka.HandlerRetryBackoff)
+	v.check(c.Kafka.TransactionWorkers > 0, "kafka.transaction_workers must be positive")
+	v.check(c.Kafka.PriorityQueueSize > 0, "kafka.priority_queue_size must be positive")
+	for _, priority := range []string{"URGENT", "HIGH", "NORMAL"} {
+		v.check(c.Kafka.PriorityWeight(priority) > 0, "kafka.priority_weights.%s must be positive", priority)
+	}
+	for priority := range c.Kafka.PriorityWeights {
+		v.check(isScreeningPriority(strings.ToUpper(priority)), "kafka.priority_weights: %q is not a screening priority", priority)
+	}
+	for priority, topic := range c.Kafka.PriorityTopics {
+		v.check(isScreeningPriority(strings.ToUpper(priority)), "kafka.priority_topics: %q is not a screening priority", priority)
+		v.check(topic != "" && topic != c.Kafka.TransactionTopic,
+			"kafka.priority_topics.%s must name a topic other than kafka.transaction_topic", priority)
+	}
+
+	v.ratio("screening.fuzzy_match_threshold", c.Screening.FuzzyMatchThreshold)
+	v.ratio("screening.pep_partial_match_threshold", c.Screening.PEPPartialMatchThreshold)
+	v.check(c.Screening.PEPPartialMatchThreshold >= c.Screening.FuzzyMatchThreshold,
+		"screening.pep_partial_match_threshold must be at least screening.fuzzy_match_threshold")
+	v.positiveDuration("screening.max_screening_latency", c.Screening.MaxScreeningLatency)
+	v.check(c.Screening.ParallelChecks > 0, "screening.parallel_checks must be positive")
+	v.check(c.Screening.BatchConcurrency > 0, "screening.batch_concurrency must be positive")
+	for _, check := range c.Screening.EnabledChecks {
+		v.check(isScreeningCheck(check), "screening.enabled_checks: unknown check %q", check)
+	}
+	for _, check := range c.Screening.FailClosedChecks {
+		v.check(isScreeningCheck(check), "screening.fail_closed_checks: unknown check %q", check)
+	}
+	for list, algorithm := range c.Screening.NameMatchers {
+		v.check(list == "ofac" || list == "pep", "screening.name_matchers: unknown list %q", list)
+		switch algorithm {
+		case "jaro_winkler", "levenshtein", "phonetic":
+		default:
+			v.add("screening.name_matchers.%s must be jaro_winkler, levenshtein or phonetic, got %q", list, algorithm)
+		}
+	}
+	for i, w := range c.Screening.EntityStopwords {
+		v.check(w != "", "screening.entity_stopwords[%d] is empty", i)
+	}
+	for i, band := range c.Screening.AmountBands {
+		v.check(band.MinUSD > 0, "screening.amount_bands[%d].min_usd must be positive", i)
+		v.check(band.Multiplier >= 1 && band.Multiplier <= 3,
+			"screening.amount_bands[%d].multiplier must be between 1 and 3, got %g", i, band.Multiplier)
+	}
+	v.check(c.Screening.GeoIP.MaxTravelSpeedKmh > 0, "screening.geoip.max_travel_speed_kmh must be positive")
+	v.check(c.Screening.GeoIP.MinTravelDistanceKm >= 0, "screening.geoip.min_travel_distance_km must not be negative")
+	v.positiveDuration("screening.geoip.location_ttl", c.Screening.GeoIP.LocationTTL)
+	v.positiveDuration("screening.shared_device.window", c.Screening.SharedDevice.Window)
+	v.check(c.Screening.SharedDevice.MaxUsers > 0, "screening.shared_device.max_users must be positive")
+	v.check(c.Screening.SharedDevice.MaxTracked > c.Screening.SharedDevice.MaxUsers,
+		"screening.shared_device.max_tracked must be greater than max_users")
+	v.check(c.Screening.SharedDevice.SampleSize >= 0, "screening.shared_device.sample_size must not be negative")
+	v.positiveDuration("screening.counterparty.half_life", c.Screening.Counterparty.HalfLife)
+	v.check(c.Screening.Counterparty.BlockedPoints >= 0 && c.Screening.Counterparty.SuspiciousPoints >= 0 &&
+		c.Screening.Counterparty.SARPoints >= 0, "screening.counterparty points must not be negative")
+	v.check(c.Screening.Counterparty.MinWeight > 0, "screening.counterparty.min_weight must be positive")
+	v.check(c.Screening.Counterparty.MaxWeight >= c.Screening.Counterparty.MinWeight,
+		"screening.counterparty.max_weight must not be less than min_weight")
+	v.check(c.Screening.AccountDenylistWeight > 0, "screening.account_denylist_weight must be positive")
+	v.check(c.Screening.RetroactiveRescreenRate > 0, "screening.retroactive_rescreen_rate must be positive")
+	v.positiveDuration("screening.list_refresh_interval", c.Screening.ListRefreshInterval)
+	if c.Screening.WarmupEnabled {
+		v.positiveDuration("screening.warmup_retry_interval", c.Screening.WarmupRetryInterval)
+	}
+	if len(c.Screening.AmountBands) > 0 && !strings.EqualFold(c.Currency.BaseCurrency, "USD") {
+		_, ok := c.Currency.Rates["usd"]
+		v.check(ok, "screening.amount_bands are in USD, so currency.rates needs a usd rate when currency.base_currency is %s", c.Currency.BaseCurrency)
+	}
+	switch c.Screening.NameFolding {
+	case "none", "diacritics", "transliterate":
+	default:
+		v.add("screening.name_folding must be none, diacritics or transliterate, got %q", c.Screening.NameFolding)
+	}
+	for check, timeout := range c.Screening.CheckTimeouts {
+		v.check(isScreeningCheck(check), "screening.check_timeouts: unknown check %q", check)
+		v.check(timeout > 0 && timeout <= c.Screening.MaxScreeningLatency,
+			"screening.check_timeouts.%s must be positive and within screening.max_screening_latency, got %s", check, timeout)
+	}
+	v.check(c.Screening.OFACCacheRetry.Attempts >= 0, "screening.ofac_cache_retry.attempts must not be negative")
+	if c.Screening.OFACCacheRetry.Attempts > 0 {
+		v.positiveDuration("screening.ofac_cache_retry.backoff", c.Screening.OFACCacheRetry.Backoff)
+	}
+
+	v.ratio("patterns.rapid_cycling_threshold", c.Patterns.RapidCyclingThreshold)
+	v.ratio("patterns.geo_concentration_threshold", c.Patterns.GeoConcentrationThreshold)
+	v.ratio("patterns.unusual_time_rare_share", c.Patterns.UnusualTimeRareShare)
+	for _, h := range c.Patterns.SuspiciousHours {
+		v.check(h >= 0 && h <= 23, "patterns.suspicious_hours: %d is not an hour of the day (0-23)", h)
+	}
+	v.check(c.Patterns.DormancyDays > 0, "patterns.dormancy_days must be positive")
+	v.check(c.Patterns.DormancyLookbackDays > c.Patterns.DormancyDays, "patterns.dormancy_lookback_days must be greater than dormancy_days")
+	v.check(c.Patterns.DormantAmountFloor > 0, "patterns.dormant_amount_floor must be positive")
+	v.check(c.Patterns.HighValueThreshold > 0, "patterns.high_value_threshold must be positive")
+	v.check(c.Patterns.StructuringThreshold > 0, "patterns.structuring_threshold must be positive")
+	v.check(c.Patterns.StructuringWindowHours > 0, "patterns.structuring_window_hours must be positive")
+	v.check(c.Patterns.StructuringMinTxCount >= 2, "patterns.structuring_min_tx_count must be at least 2")
+	v.check(c.Patterns.BatchSize > 0, "patterns.batch_size must be positive")
+	v.positiveDuration("patterns.batch_interval", c.Patterns.BatchInterval)
+	v.check(c.Patterns.VelocityBaselineDays > 0, "patterns.velocity_baseline_days must be positive")
+	switch c.Patterns.VelocityBaselineMethod {
+	case "classic", "robust":
+	default:
+		v.add("patterns.velocity_baseline_method must be classic or robust, got %q", c.Patterns.VelocityBaselineMethod)
+	}
+	v.check(c.Patterns.VelocityBaselineTrim >= 0 && c.Patterns.VelocityBaselineTrim < 0.5,
+		"patterns.velocity_baseline_trim must be at least 0 and below 0.5, got %g", c.Patterns.VelocityBaselineTrim)
+	v.positiveDuration("patterns.velocity_baseline_interval", c.Patterns.VelocityBaselineInterval)
+	v.check(c.Patterns.ProfileAmountMultiplier > 1, "patterns.profile_amount_multiplier must be greater than 1")
+	v.check(c.Patterns.RoundAmountFloor >= 0, "patterns.round_amount_floor must not be negative")
+	v.check(c.Patterns.RoundAmountUnit > 0, "patterns.round_amount_unit must be positive")
+	v.check(c.Patterns.RoundAmountWeight >= 0, "patterns.round_amount_weight must not be negative")
+	v.check(c.Patterns.ThresholdAdjacentBand > 0 && c.Patterns.ThresholdAdjacentBand < c.Compliance.CTRThreshold,
+		"patterns.threshold_adjacent_band must be positive and below compliance.ctr_threshold")
+	v.check(c.Patterns.ThresholdAdjacentWeight >= 0, "patterns.threshold_adjacent_weight must not be negative")
+	v.positiveDuration("patterns.repeated_amount_window", c.Patterns.RepeatedAmountWindow)
+	v.check(c.Patterns.RepeatedAmountMinCount >= 1, "patterns.repeated_amount_min_count must be at least 1")
+	v.check(c.Patterns.RepeatedAmountWeight >= 0, "patterns.repeated_amount_weight must not be negative")
+	v.descriptionKeywords("patterns.description_keywords", c.Patterns.DescriptionKeywords)
+	v.countryRiskTiers("patterns.country_risk_tiers", c.Patterns.CountryRiskTiers)
+	for country, rating := range c.Patterns.CountryRiskRatings {
+		v.check(isCountryCode(strings.ToUpper(country)), "patterns.country_risk_ratings: %q is not an ISO 3166-1 alpha-2 code", country)
+		v.check(rating >= 0 && rating <= 100, "patterns.country_risk_ratings.%s must be between 0 and 100, got %d", country, rating)
+	}
+	v.check(c.Patterns.CountryRiskRatingPoints >= 0 && c.Patterns.CountryRiskRatingPoints <= 100,
+		"patterns.country_risk_rating_points must be between 0 and 100, got %d", c.Patterns.CountryRiskRatingPoints)
+	v.positiveDuration("patterns.country_risk_refresh_interval", c.Patterns.CountryRiskRefreshInterval)
+
+	v.check(c.Compliance.SARMinNarrativeLength > 0, "compliance.sar_min_narrative_length must be positive")
+	v.positiveDuration("compliance.investigation_sla", c.Compliance.InvestigationSLA)
+	v.positiveDuration("compliance.sla_scan_interval", c.Compliance.SLAScanInterval)
+	v.positiveDuration("compliance.filing_scan_interval", c.Compliance.FilingScanInterval)
+	v.ratio("compliance.sla_at_risk_share", c.Compliance.SLAAtRiskShare)
+	switch c.Compliance.SLAEscalationPolicy {
+	case "status", "priority", "both":
+	default:
+		v.add("compliance.sla_escalation_policy must be status, priority or both, got %q", c.Compliance.SLAEscalationPolicy)
+	}
+	for name, t := range c.Compliance.DecisionThresholds {
+		v.check(t.Suspicious > 0 && t.Suspicious < t.Blocked && t.Blocked <= 100,
+			"compliance.decision_thresholds.%s: want 0 < suspicious < blocked <= 100, got %d and %d", name, t.Suspicious, t.Blocked)
+	}
+	for name, t := range c.Screening.Shadow.DecisionThresholds {
+		v.check(t.Suspicious > 0 && t.Suspicious < t.Blocked && t.Blocked <= 100,
+			"screening.shadow.decision_thresholds.%s: want 0 < suspicious < blocked <= 100, got %d and %d", name, t.Suspicious, t.Blocked)
+	}
+	v.check(c.Screening.Shadow.HighValueThreshold >= 0, "screening.shadow.high_value_threshold must not be negative")
+	v.countryRiskTiers("screening.shadow.country_risk_tiers", c.Screening.Shadow.CountryRiskTiers)
+	if _, err := time.LoadLocation(c.Compliance.ReportTimezone); err != nil {
+		v.add("compliance.report_timezone: %v", err)
+	}
+	v.check(c.Compliance.ExportMaxRows > 0, "compliance.export_max_rows must be positive")
+	v.positiveDuration("compliance.retention.interval", c.Compliance.Retention.Interval)
+	for entity, window := range c.Compliance.Retention.HotWindows {
+		v.check(entity == "screening_results" || entity == "alerts",
+			"compliance.retention.hot_windows: unknown entity type %q, want screening_results or alerts", entity)
+		v.check(window >= 24*time.Hour, "compliance.retention.hot_windows.%s must be at least 24h, got %s", entity, window)
+	}
+	v.check(c.Compliance.Retention.DeleteBatchSize > 0, "compliance.retention.delete_batch_size must be positive")
+	v.check(c.Compliance.Retention.MaxDaysPerRun > 0, "compliance.retention.max_days_per_run must be positive")
+	v.required("compliance.retention.archive_prefix", c.Compliance.Retention.ArchivePrefix)
+	v.positiveDuration("compliance.alert_auto_close.interval", c.Compliance.AlertAutoClose.Interval)
+	v.ratio("compliance.alert_auto_close.max_confidence", c.Compliance.AlertAutoClose.MaxConfidence)
+	v.check(c.Compliance.AlertAutoClose.MinAge >= 24*time.Hour,
+		"compliance.alert_auto_close.min_age must be at least 24h, got %s", c.Compliance.AlertAutoClose.MinAge)
+	v.check(c.Compliance.AlertAutoClose.BatchSize > 0, "compliance.alert_auto_close.batch_size must be positive")
+	v.check(len(c.Compliance.AlertPriority.Bands) > 0, "compliance.alert_priority.bands must list at least one band")
+	minScores := make(map[int]bool, len(c.Compliance.AlertPriority.Bands))
+	for i, band := range c.Compliance.AlertPriority.Bands {
+		v.check(band.MinScore >= 0 && band.MinScore <= 100,
+			"compliance.alert_priority.bands[%d].min_score must be between 0 and 100, got %d", i, band.MinScore)
+		v.check(!minScores[band.MinScore], "compliance.alert_priority.bands[%d]: duplicate min_score %d", i, band.MinScore)
+		minScores[band.MinScore] = true
+		v.check(isRiskLevel(band.Priority),
+			"compliance.alert_priority.bands[%d].priority must be LOW, MEDIUM, HIGH or CRITICAL, got %q", i, band.Priority)
+	}
+	v.check(c.Compliance.AlertPriority.EscalationScore > 0 && c.Compliance.AlertPriority.EscalationScore <= 100,
+		"compliance.alert_priority.escalation_score must be between 1 and 100, got %d", c.Compliance.AlertPriority.EscalationScore)
+	v.check(c.Compliance.AlertPriority.EscalationPriority == "" || isRiskLevel(c.Compliance.AlertPriority.EscalationPriority),
+		"compliance.alert_priority.escalation_priority must be empty, LOW, MEDIUM, HIGH or CRITICAL, got %q", c.Compliance.AlertPriority.EscalationPriority)
+
+	v.ratio("telemetry.sampling_ratio", c.Telemetry.SamplingRatio)
+
+	v.check(len(c.Security.EncryptionKeys) > 0, "security.encryption_keys must contain at least one key")
+	v.check(c.Security.CurrentKeyVersion >= 1 && c.Security.CurrentKeyVersion <= len(c.Security.EncryptionKeys),
+		"security.current_key_version %d has no matching key in security.encryption_keys", c.Security.CurrentKeyVersion)
+	v.positiveDuration("security.key_rotation_interval", c.Security.KeyRotationInterval)
+
+	v.check(c.Security.AdminRateLimitPerMinute > 0, "security.admin_rate_limit_per_minute must be positive")
+
+	v.check(c.Tenancy.Allows("default"), "tenancy.tenants must list the default tenant")
+	tenants := make(map[string]bool, len(c.Tenancy.Tenants))
+	for i, id := range c.Tenancy.Tenants {
+		v.check(isTenantID(id), "tenancy.tenants[%d] must be 1-64 lower-case letters, digits, '-' or '_', got %q", i, id)
+		v.check(!tenants[id], "tenancy.tenants[%d]: duplicate tenant %q", i, id)
+		tenants[id] = true
+	}
+	v.positiveDuration("tenancy.settings_refresh_interval", c.Tenancy.SettingsRefreshInterval)
+
+	switch c.Storage.Backend {
+	case "local":
+		v.required("storage.local_dir", c.Storage.LocalDir)
+	case "s3":
+		v.required("storage.s3_bucket", c.Storage.S3Bucket)
+		v.required("storage.s3_region", c.Storage.S3Region)
+		v.required("storage.s3_access_key_id", c.Storage.S3AccessKeyID)
+		v.required("storage.s3_secret_access_key", c.Storage.S3SecretAccessKey)
+	default:
+		v.add("storage.backend must be local or s3, got %q", c.Storage.Backend)
+	}
+
+	v.check(len(c.Currency.BaseCurrency) == 3, "currency.base_currency must be an ISO 4217 code, got %q", c.Currency.BaseCurrency)
+	for currency, rate := range c.Currency.Rates {
+		v.check(rate > 0, "currency.rates.%s must be positive", currency)
+	}
+	if c.Currency.RatesURL != "" {
+		v.positiveDuration("currency.refresh_interval", c.Currency.RefreshInterval)
+	}
+
+	v.positiveDuration("webhooks.timeout", c.Webhooks.Timeout)
+	v.positiveDuration("webhooks.retry_backoff", c.Webhooks.RetryBackoff)
+	v.check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+	v.check(c.Webhooks.QueueSize > 0, "webhooks.queue_size must be positive")
+	v.check(c.Webhooks.Workers > 0, "webhooks.workers must be positive")
+	v.check(c.Webhooks.DisableAfterFailures > 0, "webhooks.disable_after_failures must be positive")
+	v.positiveDuration("webhooks.refresh_interval", c.Webhooks.RefreshInterval)
+	names := make(map[string]bool, len(c.Webhooks.Subscribers))
+	for i, s := range c.Webhooks.Subscribers {
+		v.check(s.Name != "", "webhooks.subscribers[%d].name is required", i)
+		v.check(!names[s.Name], "webhooks.subscribers[%d].name %q is not unique", i, s.Name)
+		names[s.Name] = true
+		u, err := url.Parse(s.URL)
+		v.check(err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "",
+			"webhooks.subscribers[%d].url must be an http or https URL, got %q", i, s.URL)
+		v.check(s.Secret != "", "webhooks.subscribers[%d].secret is required", i)
+		v.check(s.TenantID == "" || c.Tenancy.Allows(s.TenantID),
+			"webhooks.subscribers[%d].tenant_id %q is not in tenancy.tenants", i, s.TenantID)
+		for _, d := range s.Decisions {
+			switch d {
+			case "APPROVED", "SUSPICIOUS", "BLOCKED", "PENDING":
+			default:
+				v.add("webhooks.subscribers[%d].decisions: unknown decision %q", i, d)
+			}
+		}
+	}
+
+	v.notifications(&c.Notifications)
+	v.email(&c.Email)
+
+	return errors.Join(v.problems...)
+}
+
+// notifications checks the chat channels and that every route names a
+// known event and configured channels
+func (v *validator) notifications(n *NotificationsConfig) {
+	v.positiveDuration("notifications.timeout", n.Timeout)
+	v.positiveDuration("notifications.retry_backoff", n.RetryBackoff)
+	v.positiveDuration("notifications.dedup_window", n.DedupWindow)
+	v.check(n.MaxAttempts > 0, "notifications.max_attempts must be positive")
+	v.check(n.QueueSize > 0, "notifications.queue_size must be positive")
+	v.check(n.Workers > 0, "notifications.workers must be positive")
+
+	channels := make(map[string]bool, len(n.Channels))
+	for i, ch := range n.Channels {
+		v.check(ch.Name != "", "notifications.channels[%d].name is required", i)
+		v.check(!channels[ch.Name], "notifications.channels[%d].name %q is not unique", i, ch.Name)
+		channels[ch.Name] = true
+		v.check(ch.Type == "slack" || ch.Type == "teams",
+			"notifications.channels[%d].type must be slack or teams, got %q", i, ch.Type)
+		u, err := url.Parse(ch.WebhookURL)
+		v.check(err == nil && u.Scheme == "https" && u.Host != "",
+			"notifications.channels[%d].webhook_url must be an https URL", i)
+	}
+
+	for i, r := range n.Routes {
+		switch r.Event {
+		case "screening.ofac_blocked", "investigation.sla_breached", "investigation.sla_at_risk":
+		default:
+			v.add("notifications.routes[%d].event: unknown event %q", i, r.Event)
+		}
+		v.check(len(r.Channels) > 0, "notifications.routes[%d].channels is required", i)
+		for _, name := range r.Channels {
+			v.check(channels[name], "notifications.routes[%d]: unknown channel %q", i, name)
+		}
+	}
+
+	if q := n.QuietHours; q.Start != "" || q.End != "" {
+		_, startErr := time.Parse("15:04", q.Start)
+		_, endErr := time.Parse("15:04", q.End)
+		v.check(startErr == nil && endErr == nil,
+			"notifications.quiet_hours start and end must both be HH:MM, got %q and %q", q.Start, q.End)
+		if _, err := time.LoadLocation(q.Timezone); err != nil {
+			v.add("notifications.quiet_hours.timezone: %v", err)
+		}
+	}
+}
+
+// email checks the digest schedule and, unless emails are only logged,
+// the SMTP server. Nothing is checked while the digest is disabled, as it
+// is the only sender.
+func (v *validator) email(e *EmailConfig) {
+	if !e.Digest.Enabled {
+		return
+	}
+
+	_, err := mail.ParseAddress(e.From)
+	v.check(err == nil, "email.from must be an email address, got %q", e.From)
+
+	d := e.Digest
+	_, err = time.Parse("15:04", d.SendAt)
+	v.check(err == nil, "email.digest.send_at must be HH:MM, got %q", d.SendAt)
+	if _, err := time.LoadLocation(d.Timezone); err != nil {
+		v.add("email.digest.timezone: %v", err)
+	}
+	v.positiveDuration("email.digest.check_interval", d.CheckInterval)
+	v.check(d.MaxItems > 0, "email.digest.max_items must be positive")
+
+	if e.DryRun {
+		return
+	}
+	v.required("email.smtp.host", e.SMTP.Host)
+	v.check(e.SMTP.Port > 0, "email.smtp.port must be positive")
+	switch e.SMTP.TLS {
+	case "starttls", "tls", "none":
+	default:
+		v.add("email.smtp.tls must be starttls, tls or none, got %q", e.SMTP.TLS)
+	}
+	v.positiveDuration("email.smtp.timeout", e.SMTP.Timeout)
+}
+
+// isScreeningCheck reports whether name is one of the engine's checks
+func isScreeningCheck(name string) bool {
+	switch name {
+	case "ofac", "pep", "risk_profile", "velocity", "patterns", "reputation", "geoip", "shared_device", "counterparty",
+		"account_denylist":
+		return true
+	}
+	return false
+}
+
+// isRiskLevel reports whether level names a risk level
+func isRiskLevel(level string) bool {
+	switch level {
+	case "LOW", "MEDIUM", "HIGH", "CRITICAL":
+		return true
+	}
+	return false
+}
+
+// isScreeningPriority reports whether priority names a screening priority
+func isScreeningPriority(priority string) bool {
+	switch priority {
+	case "NORMAL", "HIGH", "URGENT":
+		return true
+	}
+	return false
+}
+
+// isTenantID reports whether id is usable as a tenant ID: it is sent in
+// headers and tokens and stored on every record, so it is kept short and
+// plain
+func isTenantID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// validator collects configuration problems
+type validator struct {
+	problems []error
+}
+
+// countryRiskTiers checks a country risk table: named tiers worth 1 to 100
+// points, each listing alpha-2 country codes found in no other tier
+func (v *validator) countryRiskTiers(key string, tiers []CountryRiskTierConfig) {
+	names := make(map[string]bool, len(tiers))
+	tierOf := make(map[string]string)
+	for i, t := range tiers {
+		v.check(t.Name != "", "%s[%d].name is required", key, i)
+		v.check(!names[t.Name], "%s[%d].name %q is not unique", key, i, t.Name)
+		names[t.Name] = true
+		v.check(t.Points > 0 && t.Points <= 100, "%s[%d].points must be between 1 and 100, got %d", key, i, t.Points)
+		v.check(len(t.Countries) > 0, "%s[%d].countries must not be empty", key, i)
+		for _, country := range t.Countries {
+			if !isCountryCode(country) {
+				v.add("%s[%d].countries: %q is not an ISO 3166-1 alpha-2 code", key, i, country)
+				continue
+			}
+			if other, ok := tierOf[country]; ok {
+				v.add("%s[%d].countries: %s is already in tier %q", key, i, country, other)
+				continue
+			}
+			tierOf[country] = t.Name
+		}
+	}
+}
+
+// descriptionKeywords checks description keywords: each a named term or
+// regular expression, not both, worth 1 to 100 points
+func (v *validator) descriptionKeywords(key string, keywords []DescriptionKeywordConfig) {
+	for i, k := range keywords {
+		v.check(k.Name != "", "%s[%d].name is required", key, i)
+		v.check((strings.TrimSpace(k.Term) == "") != (k.Pattern == ""), "%s[%d] needs exactly one of term and pattern", key, i)
+		if k.Pattern != "" {
+			if _, err := regexp.Compile(k.Pattern); err != nil {
+				v.add("%s[%d].pattern: %v", key, i, err)
+			}
+		}
+		v.check(k.Weight > 0 && k.Weight <= 100, "%s[%d].weight must be between 1 and 100, got %d", key, i, k.Weight)
+	}
+}
+
+// isCountryCode reports whether s is two upper-case letters
+func isCountryCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
+
+func (v *validator) add(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Errorf(format, args...))
+}
+
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.add(format, args...)
+	}
+}
+
+func (v *validator) required(key, value string) {
+	v.check(value != "", "%s is required", key)
+}
+
+func (v *validator) port(key string, port int) {
+	v.check(port > 0 && port <= 65535, "%s must be between 1 and 65535, got %d", key, port)
+}
+
+func (v *validator) ratio(key string, value float64) {
+	v.check(value >= 0 && value <= 1, "%s must be between 0 and 1, got %g", key, value)
+}
+
+func (v *validator) positiveDuration(key string, d time.Duration) {
+	v.check(d > 0, "%s must be positive, got %s", key, d)
+}
@@ -0,0 +1,236 @@
+// Package crypto provides field-level envelope encryption for PII.
+//
+// Each value is sealed with a fresh 256-bit data key using AES-256-GCM; the
+// data key is in turn sealed with a versioned key-encryption key from the
+// configured key set. Ciphertexts have the form
+//
+//	v<version>:<base64 sealed data key>:<base64 sealed value>
+//
+// so a value can be decrypted with any key still in the set, and rotated to
+// a new key by re-sealing only the data key.
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const keySize = 32 // AES-256
+
+// ErrUnknownKeyVersion is returned for ciphertext sealed with a key that is
+// not in the key set
+var ErrUnknownKeyVersion = errors.New("unknown encryption key version")
+
+// ErrMalformedCiphertext is returned for values that are not in the
+// versioned ciphertext format
+var ErrMalformedCiphertext = errors.New("malformed ciphertext")
+
+// Keyring holds the key-encryption keys by version
+type Keyring struct {
+	keys    map[int][]byte
+	current int
+}
+
+// NewKeyring builds a keyring from base64-encoded 32-byte keys. The key at
+// index i has version i+1; new values are sealed with currentVersion.
+func NewKeyring(encodedKeys []string, currentVersion int) (*Keyring, error) {
+	keys := make(map[int][]byte, len(encodedKeys))
+	for i, encoded := range encodedKeys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode encryption key %d: %w", i+1, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("encryption key %d is %d bytes, want %d", i+1, len(key), keySize)
+		}
+		keys[i+1] = key
+	}
+
+	if _, ok := keys[currentVersion]; !ok {
+		return nil, fmt.Errorf("current key version %d: %w", currentVersion, ErrUnknownKeyVersion)
+	}
+
+	return &Keyring{keys: keys, current: currentVersion}, nil
+}
+
+// CurrentVersion returns the version new values are sealed with
+func (k *Keyring) CurrentVersion() int {
+	return k.current
+}
+
+// Encrypt seals plaintext with the current key. Empty values stay empty.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("generate data key: %w", err)
+	}
+
+	sealedValue, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", fmt.Errorf("seal value: %w", err)
+	}
+	sealedKey, err := seal(k.keys[k.current], dataKey)
+	if err != nil {
+		return "", fmt.Errorf("seal data key: %w", err)
+	}
+
+	return format(k.current, sealedKey, sealedValue), nil
+}
+
+// Decrypt opens a value sealed by Encrypt. Empty values stay empty.
+func (k *Keyring) Decrypt(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	version, sealedKey, sealedValue, err := parse(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := k.openDataKey(version, sealedKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, sealedValue)
+	if err != nil {
+		return "", fmt.Errorf("open value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Reencrypt brings a stored value up to the current key: ciphertext under
+// an older key has its data key re-sealed, plaintext is encrypted, and
+// values already under the current key are returned unchanged
+func (k *Keyring) Reencrypt(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if !IsCiphertext(value) {
+		return k.Encrypt(value)
+	}
+
+	version, sealedKey, sealedValue, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	if version == k.current {
+		return value, nil
+	}
+
+	dataKey, err := k.openDataKey(version, sealedKey)
+	if err != nil {
+		return "", err
+	}
+	resealedKey, err := seal(k.keys[k.current], dataKey)
+	if err != nil {
+		return "", fmt.Errorf("seal data key: %w", err)
+	}
+
+	return format(k.current, resealedKey, sealedValue), nil
+}
+
+func (k *Keyring) openDataKey(version int, sealedKey []byte) ([]byte, error) {
+	kek, ok := k.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("key version %d: %w", version, ErrUnknownKeyVersion)
+	}
+	dataKey, err := open(kek, sealedKey)
+	if err != nil {
+		return nil, fmt.Errorf("open data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// KeyVersion returns the key version a ciphertext was sealed with
+func KeyVersion(ciphertext string) (int, error) {
+	version, _, _, err := parse(ciphertext)
+	return version, err
+}
+
+// IsCiphertext reports whether a value is in the versioned ciphertext format
+func IsCiphertext(value string) bool {
+	_, _, _, err := parse(value)
+	return err == nil
+}
+
+type piiAccessKey struct{}
+
+// WithPIIAccess marks ctx as allowed to read decrypted PII
+func WithPIIAccess(ctx context.Context) context.Context {
+	return context.WithValue(ctx, piiAccessKey{}, true)
+}
+
+// HasPIIAccess reports whether ctx is allowed to read decrypted PII
+func HasPIIAccess(ctx context.Context) bool {
+	allowed, _ := ctx.Value(piiAccessKey{}).(bool)
+	return allowed
+}
+
+func format(version int, sealedKey, sealedValue []byte) string {
+	return "v" + strconv.Itoa(version) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedValue)
+}
+
+func parse(ciphertext string) (version int, sealedKey, sealedValue []byte, err error) {
+	parts := strings.Split(ciphertext, ":")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "v") {
+		return 0, nil, nil, ErrMalformedCiphertext
+	}
+	if version, err = strconv.Atoi(parts[0][1:]); err != nil || version < 1 {
+		return 0, nil, nil, ErrMalformedCiphertext
+	}
+	if sealedKey, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		return 0, nil, nil, ErrMalformedCiphertext
+	}
+	if sealedValue, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return 0, nil, nil, ErrMalformedCiphertext
+	}
+	return version, sealedKey, sealedValue, nil
+}
+
+// seal encrypts with AES-256-GCM, prefixing the random nonce
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a nonce-prefixed AES-256-GCM ciphertext
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformedCiphertext
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountDenylistEntry is a blocked account number or IBAN on the internal
+// denylist, such as a known mule account
+type AccountDenylistEntry struct {
+	Account string    `json:"account"` // normalized, see NormalizeAccount
+	Reason  string    `json:"reason"`
+	AddedBy uuid.UUID `json:"added_by"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// AccountDenylistChangeRequest adds an account to or removes an account
+// from the internal denylist
+type AccountDenylistChangeRequest struct {
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+	Reason  string    `json:"reason" validate:"required"`
+}
+
+// Validate checks that the change names an actor and a reason
+func (r *AccountDenylistChangeRequest) Validate() error {
+	if r.ActorID == uuid.Nil || r.Reason == "" {
+		return fmt.Errorf("%w: actor_id and reason are required", ErrValidation)
+	}
+	return nil
+}
+
+// AccountDenylistListResponse lists the denylisted accounts
+type AccountDenylistListResponse struct {
+	Entries []*AccountDenylistEntry `json:"entries"`
+	Total   int                     `json:"total"`
+}
+
+// NormalizeAccount puts an account number or IBAN in the form the denylist
+// is keyed on: upper case, without spaces or the separators IBANs are
+// commonly printed with, so "GB29 NWBK 6016-1331 9268 19" and
+// "gb29nwbk60161331926819" match
+func NormalizeAccount(account string) string {
+	var b strings.Builder
+	b.Grow(len(account))
+	for _, r := range strings.ToUpper(account) {
+		switch r {
+		case ' ', '\t', '-', '.', '/':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,6 +36,7 @@ const (
 type AMLAlert struct {
 	ID          uuid.UUID `json:"id" db:"id"`
 	AlertNumber string    `json:"alert_number" db:"alert_number"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
 
 	// Subject
 	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
@@ -60,26 +64,137 @@ type AMLAlert struct {
 	ReviewedAt      *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
 	Resolution      string     `json:"resolution,omitempty" db:"resolution"`
 
+	// Correlation: repeat occurrences within the correlation window are
+	// folded into this alert instead of creating new ones
+	OccurrenceCount int       `json:"occurrence_count" db:"occurrence_count"`
+	LastDetectedAt  time.Time `json:"last_detected_at" db:"last_detected_at"`
+
 	// Timestamps
 	DetectedAt time.Time `json:"detected_at" db:"detected_at"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// GenerateAlertNumber returns a human-readable alert number, e.g. ALT-20260115-1A2B3C4D
+func GenerateAlertNumber(t time.Time) string {
+	return fmt.Sprintf("ALT-%s-%s", t.UTC().Format("20060102"),
+		strings.ToUpper(uuid.New().String()[:8]))
+}
+
 // IsResolved returns true if the alert has been resolved
 func (a *AMLAlert) IsResolved() bool {
 	return a.Status == AlertStatusDismissed || a.Status == AlertStatusResolved
 }
 
-// RequiresEscalation returns true if alert should be escalated
-func (a *AMLAlert) RequiresEscalation() bool {
-	return a.RiskScore >= 80 || a.Priority == RiskLevelCritical
+// riskLevelRank orders risk levels by severity
+var riskLevelRank = map[RiskLevel]int{
+	RiskLevelLow:      1,
+	RiskLevelMedium:   2,
+	RiskLevelHigh:     3,
+	RiskLevelCritical: 4,
+}
+
+// CorrelationKey identifies alerts describing the same ongoing behaviour:
+// same user, alert type, pattern and detection rule
+func (a *AMLAlert) CorrelationKey() string {
+	pattern := ""
+	if a.PatternType != nil {
+		pattern = string(*a.PatternType)
+	}
+	return strings.Join([]string{a.UserID.String(), string(a.AlertType), pattern, a.DetectionRule}, "|")
+}
+
+// Absorb folds a correlated occurrence into this alert, keeping the highest
+// priority, score and confidence seen across the group
+func (a *AMLAlert) Absorb(occurrence *AMLAlert) {
+	a.OccurrenceCount += max(occurrence.OccurrenceCount, 1)
+
+	txIDs := occurrence.RelatedTxIDs
+	if occurrence.TransactionID != nil {
+		txIDs = append([]uuid.UUID{*occurrence.TransactionID}, txIDs...)
+	}
+	for _, id := range txIDs {
+		if !slices.Contains(a.RelatedTxIDs, id) {
+			a.RelatedTxIDs = append(a.RelatedTxIDs, id)
+		}
+	}
+
+	if riskLevelRank[occurrence.Priority] > riskLevelRank[a.Priority] {
+		a.Priority = occurrence.Priority
+	}
+	a.RiskScore = max(a.RiskScore, occurrence.RiskScore)
+	a.Confidence = max(a.Confidence, occurrence.Confidence)
+
+	if occurrence.DetectedAt.After(a.LastDetectedAt) {
+		a.LastDetectedAt = occurrence.DetectedAt
+	}
+	a.UpdatedAt = occurrence.CreatedAt
+}
+
+// AlertPriorityBand gives alerts scoring at least MinScore its Priority
+type AlertPriorityBand struct {
+	MinScore int
+	Priority RiskLevel
+}
+
+// AlertPriorityPolicy maps an alert's risk score to its priority and
+// decides which alerts need escalation: those scoring at least
+// EscalationScore, or of EscalationPriority or above when it is set
+type AlertPriorityPolicy struct {
+	Bands              []AlertPriorityBand // ascending by MinScore
+	EscalationScore    int
+	EscalationPriority RiskLevel
+}
+
+// DefaultAlertPriorityPolicy returns bands matching CalculateRiskLevel and
+// escalates alerts scoring 80 or rated CRITICAL
+func DefaultAlertPriorityPolicy() AlertPriorityPolicy {
+	return AlertPriorityPolicy{
+		Bands: []AlertPriorityBand{
+			{MinScore: 0, Priority: RiskLevelLow},
+			{MinScore: 30, Priority: RiskLevelMedium},
+			{MinScore: 60, Priority: RiskLevelHigh},
+			{MinScore: 80, Priority: RiskLevelCritical},
+		},
+		EscalationScore:    80,
+		EscalationPriority: RiskLevelCritical,
+	}
+}
+
+// PriorityFor returns the priority of the highest band the score reaches,
+// or "" if it is below every band
+func (p AlertPriorityPolicy) PriorityFor(score int) RiskLevel {
+	var priority RiskLevel
+	for _, band := range p.Bands {
+		if score >= band.MinScore {
+			priority = band.Priority
+		}
+	}
+	return priority
+}
+
+// Prioritize raises the alert's priority to its score's band. A higher
+// priority set by the rule that raised the alert, such as CRITICAL for an
+// exact OFAC match, is kept.
+func (p AlertPriorityPolicy) Prioritize(a *AMLAlert) {
+	if priority := p.PriorityFor(a.RiskScore); riskLevelRank[priority] > riskLevelRank[a.Priority] {
+		a.Priority = priority
+	}
+}
+
+// RequiresEscalation returns true if the alert should be escalated under
+// the given policy
+func (a *AMLAlert) RequiresEscalation(policy AlertPriorityPolicy) bool {
+	if a.RiskScore >= policy.EscalationScore {
+		return true
+	}
+	return policy.EscalationPriority != "" && riskLevelRank[a.Priority] >= riskLevelRank[policy.EscalationPriority]
 }
 
 // CreateAlertRequest represents a request to create an alert
 type CreateAlertRequest struct {
 	UserID        uuid.UUID    `json:"user_id" validate:"required"`
-	TransactionID *uuid.UUID   `json:"transaction_id,omitempty"`
+	TransactionID *uuid.UUID   `json:"transaction_id,omitempty" validate:"omitempty,uuid_not_nil"`
 	AlertType     AlertType    `json:"alert_type" validate:"required"`
 	Title         string       `json:"title" validate:"required,min=5,max=200"`
 	Description   string       `json:"description" validate:"required,min=10"`
@@ -87,7 +202,13 @@ type CreateAlertRequest struct {
 	Confidence    float64      `json:"confidence" validate:"min=0,max=1"`
 	RiskScore     int          `json:"risk_score" validate:"min=0,max=100"`
 	DetectionRule string       `json:"detection_rule" validate:"required"`
-	RelatedTxIDs  []uuid.UUID  `json:"related_tx_ids,omitempty"`
+	RelatedTxIDs  []uuid.UUID  `json:"related_tx_ids,omitempty" validate:"omitempty,dive,uuid_not_nil"`
+}
+
+// DismissAlertRequest represents a request to dismiss an alert
+type DismissAlertRequest struct {
+	ReviewedBy uuid.UUID `json:"reviewed_by" validate:"required"`
+	Resolution string    `json:"resolution" validate:"required"`
 }
 
 // AlertSummary is a lean DTO for list views
@@ -100,6 +221,7 @@ type AlertSummary struct {
 	Priority    RiskLevel   `json:"priority"`
 	Title       string      `json:"title"`
 	Confidence  float64     `json:"confidence"`
+	Occurrences int         `json:"occurrence_count"`
 	DetectedAt  time.Time   `json:"detected_at"`
 }
 
@@ -114,6 +236,27 @@ func (a *AMLAlert) ToSummary() *AlertSummary {
 		Priority:    a.Priority,
 		Title:       a.Title,
 		Confidence:  a.Confidence,
+		Occurrences: a.OccurrenceCount,
 		DetectedAt:  a.DetectedAt,
 	}
 }
+
+// AlertResolutionAutoClosed is the resolution of alerts dismissed by the
+// auto-close job
+const AlertResolutionAutoClosed = "auto-closed: stale low-confidence"
+
+// NeverAutoClosedAlertTypes are never dismissed automatically, whatever the
+// policy. Watchlist hits include every alert raised on an OFAC listing.
+var NeverAutoClosedAlertTypes = []AlertType{AlertTypeWatchlist}
+
+// AlertAutoClosePolicy selects the NEW alerts that may be dismissed without
+// review: confidence below MaxConfidence, no occurrence or update since
+// StaleBefore, and neither type nor detection rule excluded. Alerts on a
+// transaction with an OFAC match or linked to an open investigation are
+// never selected.
+type AlertAutoClosePolicy struct {
+	MaxConfidence float64
+	StaleBefore   time.Time
+	ExcludeTypes  []AlertType
+	ExcludeRules  []string
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// alertTransitions lists the legal status changes of an alert. Escalated
+// alerts are worked through their investigation and can only be resolved.
+var alertTransitions = map[AlertStatus][]AlertStatus{
+	AlertStatusNew:       {AlertStatusReviewing, AlertStatusEscalated, AlertStatusDismissed},
+	AlertStatusReviewing: {AlertStatusEscalated, AlertStatusDismissed, AlertStatusResolved},
+	AlertStatusEscalated: {AlertStatusResolved},
+}
+
+// CanTransitionTo returns true if moving to the given status is legal
+func (a *AMLAlert) CanTransitionTo(to AlertStatus) bool {
+	return slices.Contains(alertTransitions[a.Status], to)
+}
+
+// ValidateTransition returns ErrConflict if the alert cannot move to the given status
+func (a *AMLAlert) ValidateTransition(to AlertStatus) error {
+	if !a.CanTransitionTo(to) {
+		return fmt.Errorf("%w: cannot move alert from %s to %s", ErrConflict, a.Status, to)
+	}
+	return nil
+}
+
+// ReviewAlertRequest represents a request to start reviewing an alert
+type ReviewAlertRequest struct {
+	ReviewedBy uuid.UUID `json:"reviewed_by" validate:"required"`
+}
+
+// EscalateAlertRequest represents a request to escalate an alert
+type EscalateAlertRequest struct {
+	ReviewedBy uuid.UUID `json:"reviewed_by" validate:"required"`
+	Reason     string    `json:"reason,omitempty"`
+
+	// CreateInvestigation opens an investigation linked to the alert
+	CreateInvestigation bool `json:"create_investigation,omitempty"`
+}
+
+// EscalateAlertResponse is returned after an escalation. Investigation is
+// set when one was opened for the alert.
+type EscalateAlertResponse struct {
+	Alert         *AMLAlert      `json:"alert"`
+	Investigation *Investigation `json:"investigation,omitempty"`
+}
+
+// GenerateCaseNumber returns a human-readable case number, e.g. INV-20260115-1A2B3C4D
+func GenerateCaseNumber(t time.Time) string {
+	return fmt.Sprintf("INV-%s-%s", t.UTC().Format("20060102"),
+		strings.ToUpper(uuid.New().String()[:8]))
+}
+
+// NewInvestigation opens an investigation for the alert, due after sla
+func (a *AMLAlert) NewInvestigation(sla time.Duration, now time.Time) *Investigation {
+	alertID := a.ID
+	return &Investigation{
+		ID:                uuid.New(),
+		CaseNumber:        GenerateCaseNumber(now),
+		TenantID:          a.TenantID,
+		UserID:            a.UserID,
+		TransactionID:     a.TransactionID,
+		AlertID:           &alertID,
+		Status:            InvestigationStatusOpen,
+		Priority:          InvestigationPriority(a.Priority),
+		RiskScore:         a.RiskScore,
+		InvestigationType: string(a.AlertType),
+		Title:             a.Title,
+		Description:       fmt.Sprintf("Escalated from alert %s: %s", a.AlertNumber, a.Description),
+		DueDate:           now.Add(sla),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
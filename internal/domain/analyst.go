@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Analyst is an investigator on the auto-assignment roster
+type Analyst struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name"`
+	Skills       []string  `json:"skills" db:"skills"` // investigation types the analyst specialises in
+	MaxOpenCases int       `json:"max_open_cases" db:"max_open_cases"`
+	Active       bool      `json:"active" db:"active"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+
+	// OpenCases is the number of open investigations assigned to the
+	// analyst, computed when the roster is read
+	OpenCases int `json:"open_cases"`
+
+	// Morning digest of open work, sent to Email unless the analyst has
+	// unsubscribed. AlertTypes is their team's alert filter: the digest
+	// lists new alerts of these types, or of every type when empty.
+	Email         string      `json:"email,omitempty" db:"email"`
+	AlertTypes    []AlertType `json:"alert_types" db:"alert_types"`
+	DigestEnabled bool        `json:"digest_enabled" db:"digest_enabled"`
+	LastDigestAt  *time.Time  `json:"last_digest_at,omitempty" db:"last_digest_at"`
+}
+
+// HasSkill returns true if the analyst specialises in the investigation type
+func (a *Analyst) HasSkill(investigationType string) bool {
+	return slices.ContainsFunc(a.Skills, func(s string) bool {
+		return strings.EqualFold(s, investigationType)
+	})
+}
+
+// Capacity returns how many more cases the analyst can take, honouring both
+// their own limit and the service-wide limit (0 for none)
+func (a *Analyst) Capacity(maxOpen int) int {
+	limit := a.MaxOpenCases
+	if maxOpen > 0 && (limit <= 0 || maxOpen < limit) {
+		limit = maxOpen
+	}
+	return limit - a.OpenCases
+}
+
+// SelectAnalyst picks the active analyst with capacity and the fewest open
+// cases, preferring analysts skilled in the investigation type. It returns
+// nil when nobody has capacity.
+func SelectAnalyst(analysts []*Analyst, investigationType string, maxOpen int) *Analyst {
+	var best *Analyst
+	bestSkilled := false
+	for _, a := range analysts {
+		if !a.Active || a.Capacity(maxOpen) <= 0 {
+			continue
+		}
+		skilled := a.HasSkill(investigationType)
+		switch {
+		case best == nil,
+			skilled && !bestSkilled,
+			skilled == bestSkilled && a.OpenCases < best.OpenCases:
+			best, bestSkilled = a, skilled
+		}
+	}
+	return best
+}
+
+// RegisterAnalystRequest adds an analyst to the roster or updates their
+// skills, caseload limit and digest address and alert filter
+type RegisterAnalystRequest struct {
+	Name         string      `json:"name" validate:"required"`
+	Skills       []string    `json:"skills,omitempty"`
+	MaxOpenCases int         `json:"max_open_cases" validate:"min=1"`
+	Email        string      `json:"email,omitempty" validate:"omitempty,email"`
+	AlertTypes   []AlertType `json:"alert_types,omitempty"`
+}
+
+// Validate checks that the registration names the analyst and a caseload
+// limit, and that its alert filter names known alert types
+func (r *RegisterAnalystRequest) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	case r.MaxOpenCases < 1:
+		return fmt.Errorf("%w: max_open_cases must be at least 1", ErrValidation)
+	}
+	for _, t := range r.AlertTypes {
+		switch t {
+		case AlertTypePattern, AlertTypeScreening, AlertTypeVelocity,
+			AlertTypeThreshold, AlertTypeWatchlist, AlertTypeSystemGenerated:
+		default:
+			return fmt.Errorf("%w: unknown alert type %q", ErrValidation, t)
+		}
+	}
+	return nil
+}
+
+// NewAnalyst returns the active roster entry for the registration. New
+// analysts are subscribed to the digest; re-registering keeps an existing
+// analyst's preference.
+func (r *RegisterAnalystRequest) NewAnalyst(id uuid.UUID, now time.Time) *Analyst {
+	return &Analyst{
+		ID:            id,
+		Name:          strings.TrimSpace(r.Name),
+		Skills:        r.Skills,
+		MaxOpenCases:  r.MaxOpenCases,
+		Email:         strings.TrimSpace(r.Email),
+		AlertTypes:    r.AlertTypes,
+		DigestEnabled: true,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// AnalystPreferencesRequest subscribes an analyst to, or unsubscribes them
+// from, the morning digest
+type AnalystPreferencesRequest struct {
+	DigestEnabled *bool `json:"digest_enabled" validate:"required"`
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxBatchScreeningItems caps the number of names in one batch job
+const MaxBatchScreeningItems = 10000
+
+// BatchJobStatus represents the status of a batch screening job
+type BatchJobStatus string
+
+const (
+	BatchJobStatusPending   BatchJobStatus = "PENDING"
+	BatchJobStatusRunning   BatchJobStatus = "RUNNING"
+	BatchJobStatusCompleted BatchJobStatus = "COMPLETED"
+	BatchJobStatusFailed    BatchJobStatus = "FAILED"
+)
+
+// BatchScreeningRequest requests sanctions and PEP screening of a list of
+// names and/or customers, outside the transaction flow
+type BatchScreeningRequest struct {
+	Names   []string    `json:"names,omitempty"`
+	UserIDs []uuid.UUID `json:"user_ids,omitempty" validate:"omitempty,dive,uuid_not_nil"`
+}
+
+// BatchItem is a single entry to screen; UserID is set when the name was
+// resolved from a customer
+type BatchItem struct {
+	Name   string     `json:"name"`
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+}
+
+// BatchScreeningJob tracks a batch screening run. Cursor is the number of
+// items already screened, so a restarted job resumes where it stopped.
+type BatchScreeningJob struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	TenantID    string         `json:"tenant_id" db:"tenant_id"`
+	Status      BatchJobStatus `json:"status" db:"status"`
+	Items       []BatchItem    `json:"-" db:"items"`
+	TotalItems  int            `json:"total_items" db:"total_items"`
+	Cursor      int            `json:"processed_items" db:"cursor"`
+	HitCount    int            `json:"hit_count" db:"hit_count"`
+	Error       string         `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	StartedAt   *time.Time     `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty" db:"completed_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Progress returns the fraction of items screened, from 0 to 1
+func (j *BatchScreeningJob) Progress() float64 {
+	if j.TotalItems == 0 {
+		return 1
+	}
+	return float64(j.Cursor) / float64(j.TotalItems)
+}
+
+// IsFinished returns true once the job can make no further progress
+func (j *BatchScreeningJob) IsFinished() bool {
+	return j.Status == BatchJobStatusCompleted || j.Status == BatchJobStatusFailed
+}
+
+// BatchResult is the screening outcome for one batch item
+type BatchResult struct {
+	JobID      uuid.UUID  `json:"job_id" db:"job_id"`
+	Seq        int        `json:"seq" db:"seq"`
+	Name       string     `json:"name" db:"name"`
+	UserID     *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	OFACMatch  *OFACMatch `json:"ofac_match,omitempty" db:"ofac_match"`
+	PEPMatch   *PEPMatch  `json:"pep_match,omitempty" db:"pep_match"`
+	Hit        bool       `json:"hit" db:"hit"`
+	Error      string     `json:"error,omitempty" db:"error"`
+	ScreenedAt time.Time  `json:"screened_at" db:"screened_at"`
+}
+
+// BatchJobResponse reports batch job progress
+type BatchJobResponse struct {
+	*BatchScreeningJob
+	Progress float64 `json:"progress"`
+	HitsURL  string  `json:"hits_url"`
+}
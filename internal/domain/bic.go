@@ -0,0 +1,38 @@
+package domain
+
+import "strings"
+
+// NormalizeBIC puts a SWIFT BIC in the form it is matched on: upper case,
+// without the spaces it is sometimes printed with
+func NormalizeBIC(bic string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(bic), ""))
+}
+
+// ValidBIC reports whether bic is an ISO 9362 BIC: a four-letter bank code,
+// an ISO 3166 country code, a two-character location code and an optional
+// three-character branch code
+func ValidBIC(bic string) bool {
+	bic = NormalizeBIC(bic)
+	if len(bic) != 8 && len(bic) != 11 {
+		return false
+	}
+	for i := 0; i < len(bic); i++ {
+		c := bic[i]
+		letter := c >= 'A' && c <= 'Z'
+		digit := c >= '0' && c <= '9'
+		if i < 6 && !letter || i >= 6 && !letter && !digit {
+			return false
+		}
+	}
+	return true
+}
+
+// BICInstitution returns the eight-character institution part of a BIC,
+// dropping the branch code, so that every branch of a designated bank
+// matches. It returns "" for a malformed BIC.
+func BICInstitution(bic string) string {
+	if !ValidBIC(bic) {
+		return ""
+	}
+	return NormalizeBIC(bic)[:8]
+}
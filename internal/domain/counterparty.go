@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CounterpartyKey identifies an external account across all of a tenant's
+// users: the normalized account number and its bank, named by the
+// institution part of its BIC when known and otherwise by its normalized
+// name. Each tenant learns about a counterparty separately.
+type CounterpartyKey struct {
+	TenantID string `json:"tenant_id"`
+	Account  string `json:"account"`
+	Bank     string `json:"bank"`
+}
+
+// NewCounterpartyKey normalizes an account and its bank into a key. ok is
+// false when there is no account to key on.
+func NewCounterpartyKey(account, bank, bic string) (CounterpartyKey, bool) {
+	account = NormalizeAccount(account)
+	if account == "" {
+		return CounterpartyKey{}, false
+	}
+	name := BICInstitution(bic)
+	if name == "" {
+		name = strings.ToUpper(strings.Join(strings.Fields(bank), " "))
+	}
+	return CounterpartyKey{Account: account, Bank: name}, true
+}
+
+// Counterparty returns the key of the transaction's external account: the
+// receiver's for outbound transactions and the sender's for inbound ones.
+// The key names no tenant; callers keying reputations set it.
+func (t *Transaction) Counterparty() (CounterpartyKey, bool) {
+	if t.Direction == DirectionInbound {
+		return NewCounterpartyKey(t.SenderAccount, t.SenderBank, t.SenderBankBIC)
+	}
+	return NewCounterpartyKey(t.ReceiverAccount, t.ReceiverBank, t.ReceiverBankBIC)
+}
+
+// CounterpartyEvent is one screening or SAR filing that reflects on a
+// counterparty
+type CounterpartyEvent struct {
+	Key        CounterpartyKey
+	Blocked    float64 // blocked screenings
+	Suspicious float64 // suspicious screenings
+	SARs       float64 // SAR filings covering the counterparty's transactions
+	At         time.Time
+}
+
+// CounterpartyReputation is what screening has learnt about a counterparty
+// from all of a tenant's users' transactions. The counts decay exponentially, halving
+// every half-life, and are held as of DecayedAt.
+type CounterpartyReputation struct {
+	CounterpartyKey
+	Blocked     float64   `json:"blocked"`
+	Suspicious  float64   `json:"suspicious"`
+	SARs        float64   `json:"sars"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastEventAt time.Time `json:"last_event_at"`
+	DecayedAt   time.Time `json:"decayed_at"`
+
+	// Weight is the risk factor weight the reputation carries now; it is
+	// set when reputations are read for investigators
+	Weight int `json:"weight"`
+}
+
+// DecayedTo returns the reputation with its counts decayed to now
+func (r *CounterpartyReputation) DecayedTo(now time.Time, halfLife time.Duration) *CounterpartyReputation {
+	decayed := *r
+	if now.After(r.DecayedAt) && halfLife > 0 {
+		factor := math.Pow(0.5, float64(now.Sub(r.DecayedAt))/float64(halfLife))
+		decayed.Blocked *= factor
+		decayed.Suspicious *= factor
+		decayed.SARs *= factor
+		decayed.DecayedAt = now
+	}
+	return &decayed
+}
+
+// Apply adds an event to the reputation, decaying whichever of the two is
+// older to the time of the newer
+func (r *CounterpartyReputation) Apply(event CounterpartyEvent, halfLife time.Duration) {
+	if r.FirstSeenAt.IsZero() || event.At.Before(r.FirstSeenAt) {
+		r.FirstSeenAt = event.At
+	}
+	if event.At.After(r.LastEventAt) {
+		r.LastEventAt = event.At
+	}
+
+	added := (&CounterpartyReputation{
+		Blocked:    event.Blocked,
+		Suspicious: event.Suspicious,
+		SARs:       event.SARs,
+		DecayedAt:  event.At,
+	}).DecayedTo(r.DecayedAt, halfLife)
+	current := r.DecayedTo(added.DecayedAt, halfLife)
+
+	r.Blocked = current.Blocked + added.Blocked
+	r.Suspicious = current.Suspicious + added.Suspicious
+	r.SARs = current.SARs + added.SARs
+	r.DecayedAt = current.DecayedAt
+}
+
+// CounterpartyReputationResponse lists what is known about an account at
+// each bank it has been seen with, decayed to the time of the request
+type CounterpartyReputationResponse struct {
+	Account      string                    `json:"account"`
+	Reputations  []*CounterpartyReputation `json:"reputations"`
+	HalfLifeDays float64                   `json:"half_life_days"`
+}
+
+// FlaggedScreening is a transaction whose latest screening was blocked or
+// suspicious, read when rebuilding counterparty reputations
+type FlaggedScreening struct {
+	TenantID      string
+	TransactionID uuid.UUID
+	Transaction   *Transaction
+	Decision      ScreeningDecision
+	ScreenedAt    time.Time
+}
+
+// SubmittedSAR is a submitted SAR and the transactions it covers, read when
+// rebuilding counterparty reputations
+type SubmittedSAR struct {
+	ID             uuid.UUID
+	TransactionIDs []uuid.UUID
+	SubmittedAt    time.Time
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CountryRiskTier is a tier of the country risk table, such as the FATF
+// black or grey list. A counterparty in one of Countries adds Points to
+// the risk score, and EDDRequired marks the jurisdiction for enhanced due
+// diligence.
+type CountryRiskTier struct {
+	Name        string   `json:"name" validate:"required,max=50"`
+	Points      int      `json:"points" validate:"min=1,max=100"`
+	EDDRequired bool     `json:"edd_required"`
+	Countries   []string `json:"countries" validate:"required,min=1,dive,iso3166_alpha2"`
+}
+
+// CountryRiskTable is a version of the country risk table. Version 0 is
+// the table in configuration, in force until a table is saved through the
+// admin API; saved tables are numbered from 1 and never changed.
+//
+// Ratings grade countries outside the tiers from 0 to 100, e.g. by their
+// Basel AML Index. A counterparty in a rated country adds its rating's
+// share of RatingPoints, so a country rated 50 adds half of them; the
+// tiers are the top bands and take precedence over a rating.
+type CountryRiskTable struct {
+	Version      int               `json:"version"`
+	Tiers        []CountryRiskTier `json:"tiers"`
+	Ratings      map[string]int    `json:"ratings,omitempty"`
+	RatingPoints int               `json:"rating_points,omitempty"`
+	Comment      string            `json:"comment,omitempty"`
+	CreatedBy    uuid.UUID         `json:"created_by"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// RatingPointsFor returns the points a counterparty in a rated country adds,
+// in proportion to its rating, rounded to the nearest point
+func (t *CountryRiskTable) RatingPointsFor(country string) (rating, points int) {
+	rating = t.Ratings[country]
+	return rating, (rating*t.RatingPoints + 50) / 100
+}
+
+// UpdateCountryRiskTableRequest saves a new version of the country risk
+// table. BaseVersion is the version the change was made against; the save
+// is rejected if another version was saved since.
+type UpdateCountryRiskTableRequest struct {
+	BaseVersion  int               `json:"base_version" validate:"min=0"`
+	Tiers        []CountryRiskTier `json:"tiers" validate:"dive"`
+	Ratings      map[string]int    `json:"ratings,omitempty"`
+	RatingPoints int               `json:"rating_points" validate:"min=0,max=100"`
+	Comment      string            `json:"comment" validate:"required"`
+	ActorID      uuid.UUID         `json:"actor_id" validate:"required"`
+}
+
+// Validate checks that tier names are unique, that every country is given
+// in upper case, as counterparty countries are, and in one tier only, and
+// that ratings are between 0 and 100
+func (r *UpdateCountryRiskTableRequest) Validate() error {
+	for country, rating := range r.Ratings {
+		if !isCountryCode(country) {
+			return fmt.Errorf("%w: rated country %q is not an upper-case ISO 3166-1 alpha-2 code", ErrValidation, country)
+		}
+		if rating < 0 || rating > 100 {
+			return fmt.Errorf("%w: rating of %s must be between 0 and 100, got %d", ErrValidation, country, rating)
+		}
+	}
+
+	return validateCountryRiskTiers(r.Tiers)
+}
+
+// validateCountryRiskTiers checks that tier names are unique and that every
+// country is given in upper case and in one tier only
+func validateCountryRiskTiers(tiers []CountryRiskTier) error {
+	names := make(map[string]bool, len(tiers))
+	tierOf := make(map[string]string)
+	for _, t := range tiers {
+		if names[t.Name] {
+			return fmt.Errorf("%w: tier name %q is not unique", ErrValidation, t.Name)
+		}
+		names[t.Name] = true
+		for _, country := range t.Countries {
+			if country != strings.ToUpper(country) {
+				return fmt.Errorf("%w: country code %q must be upper case", ErrValidation, country)
+			}
+			if other, ok := tierOf[country]; ok {
+				return fmt.Errorf("%w: %s is in tiers %q and %q", ErrValidation, country, other, t.Name)
+			}
+			tierOf[country] = t.Name
+		}
+	}
+	return nil
+}
+
+// isCountryCode reports whether s is two upper-case letters
+func isCountryCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
+
+// CountryRiskTableListResponse is a page of country risk table versions,
+// newest first
+type CountryRiskTableListResponse struct {
+	Tables []*CountryRiskTable `json:"tables"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
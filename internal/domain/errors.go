@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/banking/aml-service/internal/pkg/apperr"
+)
+
+// The domain errors are apperr sentinels, so errors.Is matches them against
+// any apperr.Error of the same code, including those from other packages
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound error = apperr.New(apperr.CodeNotFound, "not found")
+
+// ErrValidation is wrapped by errors describing invalid caller input
+var ErrValidation error = apperr.New(apperr.CodeValidation, "validation failed")
+
+// ErrConflict is returned when an operation conflicts with the current state of a record
+var ErrConflict error = apperr.New(apperr.CodeConflict, "conflict")
+
+// ErrForbidden is returned when the actor may not perform an operation
+var ErrForbidden error = apperr.New(apperr.CodeForbidden, "forbidden")
+
+// ErrIntegrity is returned when stored content no longer matches its recorded hash
+var ErrIntegrity error = apperr.New(apperr.CodeIntegrity, "integrity check failed")
+
+// FieldError describes one invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors lists every invalid field of a request, so a client can
+// highlight each of them at once. It wraps ErrValidation.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, f := range e {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return ErrValidation.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e FieldErrors) Unwrap() error {
+	return ErrValidation
+}
+
+// ErrorDetails returns the invalid fields for the error response
+func (e FieldErrors) ErrorDetails() interface{} {
+	return []FieldError(e)
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/banking/aml-service/internal/pkg/apperr"
+)
+
+func TestErrorTaxonomy(t *testing.T) {
+	tests := []struct {
+		sentinel error
+		code     apperr.Code
+		status   int
+	}{
+		{ErrNotFound, apperr.CodeNotFound, http.StatusNotFound},
+		{ErrValidation, apperr.CodeValidation, http.StatusBadRequest},
+		{ErrConflict, apperr.CodeConflict, http.StatusConflict},
+		{ErrForbidden, apperr.CodeForbidden, http.StatusForbidden},
+		{ErrIntegrity, apperr.CodeIntegrity, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			err := fmt.Errorf("get investigation: %w", fmt.Errorf("%w: case closed", tt.sentinel))
+
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("wrapped error does not match %v", tt.sentinel)
+			}
+			for _, other := range tests {
+				if other.sentinel != tt.sentinel && errors.Is(err, other.sentinel) {
+					t.Errorf("wrapped error also matches %v", other.sentinel)
+				}
+			}
+			var e *apperr.Error
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("errors.As = %+v, want a %s error", e, tt.code)
+			}
+			if got := apperr.CodeOf(err); got != tt.code {
+				t.Errorf("CodeOf = %q, want %q", got, tt.code)
+			}
+			if got := apperr.HTTPStatus(apperr.CodeOf(err)); got != tt.status {
+				t.Errorf("HTTPStatus = %d, want %d", got, tt.status)
+			}
+		})
+	}
+
+	// A service error of a code matches that code's sentinel
+	if err := apperr.Wrap(apperr.CodeNotFound, "alert not found", errors.New("no rows")); !errors.Is(err, ErrNotFound) {
+		t.Error("NOT_FOUND error does not match ErrNotFound")
+	}
+}
+
+func TestFieldErrorsAreValidationErrors(t *testing.T) {
+	fields := FieldErrors{{Field: "amount", Message: "must be positive"}}
+	err := fmt.Errorf("screen transaction: %w", fields)
+
+	if !errors.Is(err, ErrValidation) {
+		t.Error("field errors do not match ErrValidation")
+	}
+	if got := apperr.CodeOf(err); got != apperr.CodeValidation {
+		t.Errorf("CodeOf = %q, want %q", got, apperr.CodeValidation)
+	}
+	if got, ok := apperr.DetailsOf(err).([]FieldError); !ok || len(got) != 1 || got[0] != fields[0] {
+		t.Errorf("DetailsOf = %v, want the invalid fields", apperr.DetailsOf(err))
+	}
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Evidence timeline event types
+const (
+	TimelineEventEvidenceAdded   = "EVIDENCE_ADDED"
+	TimelineEventEvidenceDeleted = "EVIDENCE_DELETED"
+)
+
+// AddEvidenceRequest describes a file attached to an investigation. The file
+// itself arrives as the multipart "file" part.
+type AddEvidenceRequest struct {
+	Type        string    `form:"type" validate:"required"` // document, screenshot, statement
+	Description string    `form:"description"`
+	AddedBy     uuid.UUID `form:"added_by" validate:"required"`
+
+	FileName    string `form:"-"`
+	ContentType string `form:"-"`
+}
+
+// Validate checks that the upload names its type and uploader
+func (r *AddEvidenceRequest) Validate() error {
+	if r.Type == "" || r.AddedBy == uuid.Nil {
+		return fmt.Errorf("%w: type and added_by are required", ErrValidation)
+	}
+	return nil
+}
+
+// DeleteEvidenceRequest withdraws evidence. The record, file and hash are
+// kept for audit.
+type DeleteEvidenceRequest struct {
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+	Reason  string    `json:"reason" validate:"required"`
+}
+
+// Validate checks that the deletion names an actor and a reason
+func (r *DeleteEvidenceRequest) Validate() error {
+	if r.ActorID == uuid.Nil || r.Reason == "" {
+		return fmt.Errorf("%w: actor_id and reason are required", ErrValidation)
+	}
+	return nil
+}
+
+// EvidenceObjectKey is where an evidence file is stored
+func EvidenceObjectKey(investigationID, evidenceID uuid.UUID) string {
+	return "investigations/" + investigationID.String() + "/evidence/" + evidenceID.String()
+}
+
+// IsDeleted returns true if the evidence has been withdrawn
+func (e *Evidence) IsDeleted() bool {
+	return e.DeletedAt != nil
+}
+
+// HasFile returns true if the evidence has an attached file
+func (e *Evidence) HasFile() bool {
+	return e.ObjectKey != ""
+}
+
+// FindEvidence returns the investigation's evidence with the given ID
+func (i *Investigation) FindEvidence(id uuid.UUID) (*Evidence, error) {
+	for idx := range i.Evidence {
+		if i.Evidence[idx].ID == id {
+			return &i.Evidence[idx], nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -1,9 +1,15 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/pkg/money"
 )
 
 // FilingType represents the type of regulatory filing
@@ -32,6 +38,7 @@ type RegulatoryFiling struct {
 	ID           uuid.UUID `json:"id" db:"id"`
 	FilingNumber string    `json:"filing_number" db:"filing_number"`
 	BSAFilingID  string    `json:"bsa_filing_id,omitempty" db:"bsa_filing_id"` // FinCEN BSA ID
+	TenantID     string    `json:"tenant_id" db:"tenant_id"`
 
 	// Type
 	FilingType FilingType   `json:"filing_type" db:"filing_type"`
@@ -48,13 +55,18 @@ type RegulatoryFiling struct {
 	CTRDetails         *CTRDetails  `json:"ctr_details,omitempty" db:"ctr_details"`
 
 	// Amounts
-	TotalAmount float64 `json:"total_amount" db:"total_amount"`
-	Currency    string  `json:"currency" db:"currency"`
+	TotalAmount money.Amount `json:"total_amount" db:"total_amount"`
+	Currency    string       `json:"currency" db:"currency"`
 
 	// Narrative (for SAR)
 	Narrative          string `json:"narrative,omitempty" db:"narrative"`
 	NarrativeEncrypted string `json:"-" db:"narrative_encrypted"`
 
+	// Generated narrative draft: when it was drafted and the SHA-256 of the
+	// drafted text, used to tell whether the narrative was edited since
+	NarrativeDraftedAt   *time.Time `json:"narrative_drafted_at,omitempty" db:"narrative_drafted_at"`
+	NarrativeDraftSHA256 string     `json:"-" db:"narrative_draft_sha256"`
+
 	// Workflow
 	PreparedBy uuid.UUID  `json:"prepared_by" db:"prepared_by"`
 	ReviewedBy *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
@@ -70,6 +82,11 @@ type RegulatoryFiling struct {
 	ConfirmationNumber string     `json:"confirmation_number,omitempty" db:"confirmation_number"`
 	RejectionReason    string     `json:"rejection_reason,omitempty" db:"rejection_reason"`
 
+	// Deadline monitoring: smallest lead time (in days) already warned
+	// about, and when the overdue alert was raised
+	DeadlineWarningDays *int       `json:"-" db:"deadline_warning_days"`
+	OverdueAlertedAt    *time.Time `json:"-" db:"overdue_alerted_at"`
+
 	// Amendments
 	AmendedFromID   *uuid.UUID `json:"amended_from_id,omitempty" db:"amended_from_id"`
 	AmendmentReason string     `json:"amendment_reason,omitempty" db:"amendment_reason"`
@@ -87,20 +104,20 @@ type SARSubject struct {
 	LastName   string `json:"last_name"`
 	Suffix     string `json:"suffix,omitempty"`
 	DOB        string `json:"dob,omitempty"` // YYYY-MM-DD
-	SSN        string `json:"ssn,omitempty"` // Encrypted
+	SSN        string `json:"ssn,omitempty"` // Encrypted at rest
 
 	// Address
 	Address string `json:"address"`
 	City    string `json:"city"`
 	State   string `json:"state"`
 	ZipCode string `json:"zip_code"`
-	Country string `json:"country"`
+	Country string `json:"country" validate:"omitempty,iso3166_alpha2"`
 
 	// Identification
 	IDType    string `json:"id_type,omitempty"`
 	IDNumber  string `json:"id_number,omitempty"`
 	IDState   string `json:"id_state,omitempty"`
-	IDCountry string `json:"id_country,omitempty"`
+	IDCountry string `json:"id_country,omitempty" validate:"omitempty,iso3166_alpha2"`
 
 	// Account
 	AccountNumber    string `json:"account_number"`
@@ -125,12 +142,12 @@ type SARActivity struct {
 	Products []string `json:"products"` // Checking, Savings, etc.
 
 	// Amount breakdown
-	CashIn          float64 `json:"cash_in,omitempty"`
-	CashOut         float64 `json:"cash_out,omitempty"`
-	WireTransferIn  float64 `json:"wire_transfer_in,omitempty"`
-	WireTransferOut float64 `json:"wire_transfer_out,omitempty"`
-	OtherIn         float64 `json:"other_in,omitempty"`
-	OtherOut        float64 `json:"other_out,omitempty"`
+	CashIn          money.Amount `json:"cash_in,omitempty"`
+	CashOut         money.Amount `json:"cash_out,omitempty"`
+	WireTransferIn  money.Amount `json:"wire_transfer_in,omitempty"`
+	WireTransferOut money.Amount `json:"wire_transfer_out,omitempty"`
+	OtherIn         money.Amount `json:"other_in,omitempty"`
+	OtherOut        money.Amount `json:"other_out,omitempty"`
 
 	// Law enforcement
 	LEContactName  string `json:"le_contact_name,omitempty"`
@@ -144,8 +161,8 @@ type CTRDetails struct {
 	TransactionType string `json:"transaction_type"` // Deposit, Withdrawal, etc.
 
 	// Amounts
-	CashIn  float64 `json:"cash_in"`
-	CashOut float64 `json:"cash_out"`
+	CashIn  money.Amount `json:"cash_in"`
+	CashOut money.Amount `json:"cash_out"`
 
 	// Conductor (if different from account holder)
 	ConductedByOther  bool   `json:"conducted_by_other"`
@@ -157,8 +174,54 @@ type CTRDetails struct {
 	ConductorIDNumber string `json:"conductor_id_number,omitempty"`
 
 	// Multiple transactions
-	MultipleTransactions bool    `json:"multiple_transactions"`
-	AggregatedAmount     float64 `json:"aggregated_amount,omitempty"`
+	MultipleTransactions bool         `json:"multiple_transactions"`
+	AggregatedAmount     money.Amount `json:"aggregated_amount,omitempty"`
+}
+
+// RedactedValue replaces PII in filings returned to callers without PII access
+const RedactedValue = "[REDACTED]"
+
+// RedactPII masks the encrypted identifiers and narrative
+func (f *RegulatoryFiling) RedactPII() {
+	redact := func(v *string) {
+		if *v != "" {
+			*v = RedactedValue
+		}
+	}
+
+	redact(&f.Narrative)
+	if f.SubjectInfo != nil {
+		s := *f.SubjectInfo
+		redact(&s.SSN)
+		redact(&s.IDNumber)
+		f.SubjectInfo = &s
+	}
+	if f.CTRDetails != nil {
+		c := *f.CTRDetails
+		redact(&c.ConductorSSN)
+		redact(&c.ConductorIDNumber)
+		f.CTRDetails = &c
+	}
+}
+
+// NarrativeEdited returns true if the narrative holds text that was not
+// produced by the draft generator, or was changed after it was drafted.
+// The narrative must be decrypted.
+func (f *RegulatoryFiling) NarrativeEdited() bool {
+	return f.Narrative != "" && narrativeHash(f.Narrative) != f.NarrativeDraftSHA256
+}
+
+// SetNarrativeDraft replaces the narrative with a generated draft
+func (f *RegulatoryFiling) SetNarrativeDraft(narrative string, now time.Time) {
+	f.Narrative = narrative
+	f.NarrativeDraftSHA256 = narrativeHash(narrative)
+	f.NarrativeDraftedAt = &now
+	f.UpdatedAt = now
+}
+
+func narrativeHash(narrative string) string {
+	sum := sha256.Sum256([]byte(narrative))
+	return hex.EncodeToString(sum[:])
 }
 
 // IsDraft returns true if filing is still in draft
@@ -171,33 +234,63 @@ func (f *RegulatoryFiling) CanSubmit() bool {
 	return f.Status == FilingStatusApproved
 }
 
-// IsOverdue returns true if filing is past due date
-func (f *RegulatoryFiling) IsOverdue() bool {
+// IsOverdue returns true if filing is past due date at now
+func (f *RegulatoryFiling) IsOverdue(now time.Time) bool {
 	return f.Status != FilingStatusSubmitted &&
 		f.Status != FilingStatusAccepted &&
-		time.Now().After(f.FilingDueDate)
+		now.After(f.FilingDueDate)
+}
+
+// DaysUntilDue returns the whole days remaining before the filing is due,
+// rounded up; zero or negative once the deadline has passed
+func (f *RegulatoryFiling) DaysUntilDue(now time.Time) int {
+	remaining := f.FilingDueDate.Sub(now)
+	days := int(remaining / (24 * time.Hour))
+	if remaining > 0 && remaining%(24*time.Hour) != 0 {
+		days++
+	}
+	return days
+}
+
+// SARDueDate returns the statutory filing deadline for activity ending at activityEnd
+func SARDueDate(activityEnd time.Time, deadlineDays int) time.Time {
+	return activityEnd.AddDate(0, 0, deadlineDays)
+}
+
+// GenerateFilingNumber returns a human-readable filing number, e.g. SAR-20260115-1A2B3C4D
+func GenerateFilingNumber(filingType FilingType, t time.Time) string {
+	return fmt.Sprintf("%s-%s-%s", filingType, t.UTC().Format("20060102"),
+		strings.ToUpper(uuid.New().String()[:8]))
 }
 
 // CreateSARRequest represents a request to create a SAR
 type CreateSARRequest struct {
-	UserID             uuid.UUID   `json:"user_id" validate:"required"`
-	InvestigationID    *uuid.UUID  `json:"investigation_id,omitempty"`
-	TransactionIDs     []uuid.UUID `json:"transaction_ids" validate:"required,min=1"`
-	SubjectInfo        SARSubject  `json:"subject_info" validate:"required"`
-	SuspiciousActivity SARActivity `json:"suspicious_activity" validate:"required"`
-	Narrative          string      `json:"narrative" validate:"required,min=100"`
-	TotalAmount        float64     `json:"total_amount" validate:"required,gt=0"`
-	ActivityStartDate  time.Time   `json:"activity_start_date" validate:"required"`
-	ActivityEndDate    time.Time   `json:"activity_end_date" validate:"required"`
+	UserID             uuid.UUID    `json:"user_id" validate:"required"`
+	InvestigationID    *uuid.UUID   `json:"investigation_id,omitempty" validate:"omitempty,uuid_not_nil"`
+	TransactionIDs     []uuid.UUID  `json:"transaction_ids" validate:"required,min=1,dive,uuid_not_nil"`
+	SubjectInfo        SARSubject   `json:"subject_info" validate:"required"`
+	SuspiciousActivity SARActivity  `json:"suspicious_activity" validate:"required"`
+	Narrative          string       `json:"narrative" validate:"required"`
+	TotalAmount        money.Amount `json:"total_amount" validate:"required,gt=0"`
+	ActivityStartDate  time.Time    `json:"activity_start_date" validate:"required"`
+	ActivityEndDate    time.Time    `json:"activity_end_date" validate:"required,date_after=ActivityStartDate"`
+	PreparedBy         uuid.UUID    `json:"prepared_by" validate:"required"`
+}
+
+// DraftNarrativeRequest asks for a generated SAR narrative. Force allows a
+// manually written or edited narrative to be replaced.
+type DraftNarrativeRequest struct {
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+	Force   bool      `json:"force,omitempty"`
 }
 
 // CreateCTRRequest represents a request to create a CTR
 type CreateCTRRequest struct {
-	UserID         uuid.UUID   `json:"user_id" validate:"required"`
-	TransactionIDs []uuid.UUID `json:"transaction_ids" validate:"required,min=1"`
-	SubjectInfo    SARSubject  `json:"subject_info" validate:"required"`
-	CTRDetails     CTRDetails  `json:"ctr_details" validate:"required"`
-	TotalAmount    float64     `json:"total_amount" validate:"required,gt=10000"`
+	UserID         uuid.UUID    `json:"user_id" validate:"required"`
+	TransactionIDs []uuid.UUID  `json:"transaction_ids" validate:"required,min=1,dive,uuid_not_nil"`
+	SubjectInfo    SARSubject   `json:"subject_info" validate:"required"`
+	CTRDetails     CTRDetails   `json:"ctr_details" validate:"required"`
+	TotalAmount    money.Amount `json:"total_amount" validate:"required,gt=10000"`
 }
 
 // FilingSummary is a lean DTO for list views
@@ -207,14 +300,15 @@ type FilingSummary struct {
 	FilingType    FilingType   `json:"filing_type"`
 	Status        FilingStatus `json:"status"`
 	UserID        uuid.UUID    `json:"user_id"`
-	TotalAmount   float64      `json:"total_amount"`
+	TotalAmount   money.Amount `json:"total_amount"`
 	FilingDueDate time.Time    `json:"filing_due_date"`
 	IsOverdue     bool         `json:"is_overdue"`
 	CreatedAt     time.Time    `json:"created_at"`
 }
 
-// ToSummary converts RegulatoryFiling to FilingSummary
-func (f *RegulatoryFiling) ToSummary() *FilingSummary {
+// ToSummary converts RegulatoryFiling to FilingSummary, judging whether
+// it is overdue at now
+func (f *RegulatoryFiling) ToSummary(now time.Time) *FilingSummary {
 	return &FilingSummary{
 		ID:            f.ID,
 		FilingNumber:  f.FilingNumber,
@@ -223,7 +317,7 @@ func (f *RegulatoryFiling) ToSummary() *FilingSummary {
 		UserID:        f.UserID,
 		TotalAmount:   f.TotalAmount,
 		FilingDueDate: f.FilingDueDate,
-		IsOverdue:     f.IsOverdue(),
+		IsOverdue:     f.IsOverdue(now),
 		CreatedAt:     f.CreatedAt,
 	}
 }
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// filingTransitions lists the legal status changes of a filing. Filings
+// past APPROVED are never edited in place; corrections go through an
+// amendment, a new draft linked to the original. The original moves to
+// AMENDED when its amendment is submitted, never by request.
+var filingTransitions = map[FilingStatus][]FilingStatus{
+	FilingStatusDraft:     {FilingStatusReview},
+	FilingStatusReview:    {FilingStatusApproved, FilingStatusDraft},
+	FilingStatusApproved:  {FilingStatusSubmitted, FilingStatusDraft},
+	FilingStatusSubmitted: {FilingStatusAccepted, FilingStatusRejected, FilingStatusAmended},
+	FilingStatusRejected:  {FilingStatusDraft, FilingStatusAmended},
+	FilingStatusAccepted:  {FilingStatusAmended},
+}
+
+// amendableStatuses are the statuses from which a filing may be amended
+var amendableStatuses = []FilingStatus{
+	FilingStatusSubmitted,
+	FilingStatusAccepted,
+	FilingStatusRejected,
+}
+
+// FilingTransition records a status change and who made it
+type FilingTransition struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	FilingID   uuid.UUID    `json:"filing_id" db:"filing_id"`
+	FromStatus FilingStatus `json:"from_status" db:"from_status"`
+	ToStatus   FilingStatus `json:"to_status" db:"to_status"`
+	ActorID    uuid.UUID    `json:"actor_id" db:"actor_id"`
+	Reason     string       `json:"reason,omitempty" db:"reason"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+}
+
+// FilingTransitionRequest represents a request to move a filing to a new status
+type FilingTransitionRequest struct {
+	ToStatus FilingStatus `json:"to_status" validate:"required"`
+
+	// ActorID is set from the verified bearer token; a value in the body
+	// must match it
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+
+	// Reason is required when rejecting or returning a filing to draft
+	Reason string `json:"reason,omitempty"`
+
+	// Set from the FinCEN acknowledgement when accepting a filing
+	BSAFilingID        string `json:"bsa_filing_id,omitempty"`
+	ConfirmationNumber string `json:"confirmation_number,omitempty"`
+}
+
+// FilingTransitionResponse is returned after a transition. Superseded is
+// the original filing, moved to AMENDED, when an amendment is submitted.
+type FilingTransitionResponse struct {
+	Filing     *RegulatoryFiling `json:"filing"`
+	Transition *FilingTransition `json:"transition"`
+	Superseded *RegulatoryFiling `json:"superseded,omitempty"`
+}
+
+// FilingChange is a status change to apply to a filing that must still be
+// in status From
+type FilingChange struct {
+	Filing     *RegulatoryFiling
+	From       FilingStatus
+	Transition *FilingTransition
+}
+
+// AmendFilingRequest represents a request to open an amendment of a filing
+type AmendFilingRequest struct {
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+	Reason  string    `json:"reason" validate:"required"`
+}
+
+// Validate checks the amendment request
+func (r *AmendFilingRequest) Validate() error {
+	if r.ActorID == uuid.Nil {
+		return fmt.Errorf("%w: actor_id is required", ErrValidation)
+	}
+	if strings.TrimSpace(r.Reason) == "" {
+		return fmt.Errorf("%w: reason is required to amend a filing", ErrValidation)
+	}
+	return nil
+}
+
+// CanTransitionTo returns true if moving to the given status is legal
+func (f *RegulatoryFiling) CanTransitionTo(to FilingStatus) bool {
+	return slices.Contains(filingTransitions[f.Status], to)
+}
+
+// CanAmend returns true if the filing's status allows an amendment. Whether
+// an amendment is already open is checked when it is created.
+func (f *RegulatoryFiling) CanAmend() bool {
+	return slices.Contains(amendableStatuses, f.Status)
+}
+
+// IsAmendment returns true if the filing amends an earlier one
+func (f *RegulatoryFiling) IsAmendment() bool {
+	return f.AmendedFromID != nil
+}
+
+// IsEditable returns true if the filing content may still be changed
+func (f *RegulatoryFiling) IsEditable() bool {
+	return f.Status == FilingStatusDraft
+}
+
+// requiresChecker returns true if leaving the given status must be done by
+// someone other than the preparer (maker-checker)
+func requiresChecker(from FilingStatus) bool {
+	return from == FilingStatusReview
+}
+
+// ValidateTransition checks that a transition is legal for the filing and
+// carries the data it needs
+func (f *RegulatoryFiling) ValidateTransition(req *FilingTransitionRequest) error {
+	if req.ToStatus == FilingStatusAmended {
+		return fmt.Errorf("%w: open an amendment instead; the filing moves to %s when the amendment is submitted", ErrValidation, FilingStatusAmended)
+	}
+	if !f.CanTransitionTo(req.ToStatus) {
+		return fmt.Errorf("%w: cannot move filing from %s to %s", ErrConflict, f.Status, req.ToStatus)
+	}
+	if requiresChecker(f.Status) && req.ActorID == f.PreparedBy {
+		return fmt.Errorf("%w: the preparer cannot review their own filing", ErrForbidden)
+	}
+
+	switch req.ToStatus {
+	case FilingStatusRejected:
+		if req.Reason == "" {
+			return fmt.Errorf("%w: reason is required to move a filing to %s", ErrValidation, req.ToStatus)
+		}
+	case FilingStatusDraft:
+		if f.Status != FilingStatusRejected && req.Reason == "" {
+			return fmt.Errorf("%w: reason is required to return a filing to draft", ErrValidation)
+		}
+	}
+
+	return nil
+}
+
+// NewAmendment opens a draft that amends a filed report, deep-copying its
+// content so the preparer only edits what changed. The BSA ID is carried
+// over so the FinCEN export can reference the report being corrected.
+func (f *RegulatoryFiling) NewAmendment(preparedBy uuid.UUID, reason string, now time.Time) *RegulatoryFiling {
+	amendment := *f
+
+	amendment.ID = uuid.New()
+	amendment.FilingNumber = GenerateFilingNumber(f.FilingType, now)
+	amendment.Status = FilingStatusDraft
+	amendment.TransactionIDs = slices.Clone(f.TransactionIDs)
+	amendment.PreparedBy = preparedBy
+	amendment.ReviewedBy = nil
+	amendment.ApprovedBy = nil
+	amendment.SubmittedAt = nil
+	amendment.ConfirmationNumber = ""
+	amendment.RejectionReason = ""
+	amendment.DeadlineWarningDays = nil
+	amendment.OverdueAlertedAt = nil
+	originalID := f.ID
+	amendment.AmendedFromID = &originalID
+	amendment.AmendmentReason = reason
+	amendment.CreatedAt = now
+	amendment.UpdatedAt = now
+
+	if f.SubjectInfo != nil {
+		subject := *f.SubjectInfo
+		amendment.SubjectInfo = &subject
+	}
+	if f.SuspiciousActivity != nil {
+		activity := *f.SuspiciousActivity
+		activity.Categories = slices.Clone(activity.Categories)
+		activity.Instruments = slices.Clone(activity.Instruments)
+		activity.Products = slices.Clone(activity.Products)
+		amendment.SuspiciousActivity = &activity
+	}
+	if f.CTRDetails != nil {
+		ctr := *f.CTRDetails
+		amendment.CTRDetails = &ctr
+	}
+
+	return &amendment
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GraphNodeType identifies what a node in an entity graph represents
+type GraphNodeType string
+
+const (
+	GraphNodeUser          GraphNodeType = "USER"
+	GraphNodeCounterparty  GraphNodeType = "COUNTERPARTY"
+	GraphNodeInvestigation GraphNodeType = "INVESTIGATION"
+	GraphNodeAlert         GraphNodeType = "ALERT"
+	GraphNodeFiling        GraphNodeType = "FILING"
+)
+
+// Entity graph edge types
+const (
+	GraphEdgeTransactedWith = "TRANSACTED_WITH" // user -> counterparty
+	GraphEdgeCase           = "CASE"            // user -> investigation, alert or filing
+)
+
+// GraphNode is an entity in a linked-entity graph
+type GraphNode struct {
+	ID        string        `json:"id"`
+	Type      GraphNodeType `json:"type"`
+	Label     string        `json:"label"`
+	RiskScore int           `json:"risk_score"`
+	Status    string        `json:"status,omitempty"`
+	Hops      int           `json:"hops"` // distance from the subject
+}
+
+// GraphEdge links two nodes of a linked-entity graph
+type GraphEdge struct {
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Type    string `json:"type"`
+	TxCount int    `json:"tx_count,omitempty"`
+}
+
+// EntityGraph links an investigation's subject to their counterparties,
+// other users of those counterparties, and the cases on those users.
+// Truncated is set when the node cap cut the graph short.
+type EntityGraph struct {
+	InvestigationID uuid.UUID    `json:"investigation_id"`
+	SubjectID       string       `json:"subject_id"`
+	Since           time.Time    `json:"since"`
+	Nodes           []*GraphNode `json:"nodes"`
+	Edges           []*GraphEdge `json:"edges"`
+	Truncated       bool         `json:"truncated"`
+
+	maxNodes int
+	index    map[string]*GraphNode
+}
+
+// NewEntityGraph starts an empty graph holding at most maxNodes nodes
+func NewEntityGraph(investigationID uuid.UUID, since time.Time, maxNodes int) *EntityGraph {
+	return &EntityGraph{
+		InvestigationID: investigationID,
+		Since:           since,
+		Nodes:           []*GraphNode{},
+		Edges:           []*GraphEdge{},
+		maxNodes:        maxNodes,
+		index:           make(map[string]*GraphNode),
+	}
+}
+
+// AddNode adds a node, or raises the risk score of an existing one. It
+// returns false when the node is new and the graph is full.
+func (g *EntityGraph) AddNode(n *GraphNode) bool {
+	if existing, ok := g.index[n.ID]; ok {
+		existing.RiskScore = max(existing.RiskScore, n.RiskScore)
+		return true
+	}
+	if len(g.Nodes) >= g.maxNodes {
+		g.Truncated = true
+		return false
+	}
+	g.index[n.ID] = n
+	g.Nodes = append(g.Nodes, n)
+	return true
+}
+
+// AddEdge links two nodes already in the graph
+func (g *EntityGraph) AddEdge(e *GraphEdge) {
+	if g.index[e.Source] == nil || g.index[e.Target] == nil {
+		return
+	}
+	g.Edges = append(g.Edges, e)
+}
+
+// HopsTo returns the distance of a node from the subject, or 0 if the node
+// is not in the graph
+func (g *EntityGraph) HopsTo(id string) int {
+	if n, ok := g.index[id]; ok {
+		return n.Hops
+	}
+	return 0
+}
+
+// UserNodeID is the graph node ID of a user
+func UserNodeID(id uuid.UUID) string {
+	return "user:" + id.String()
+}
+
+// CounterpartyLink aggregates a user's screened transactions with one
+// counterparty. Key is the counterparty's account, or its lower-cased name
+// prefixed with "name:" when no account was recorded.
+type CounterpartyLink struct {
+	UserID    uuid.UUID
+	Key       string
+	Name      string
+	Account   string
+	TxCount   int
+	RiskScore int // highest screening risk score among the transactions
+}
+
+// CaseLink is an investigation, alert or filing on a user
+type CaseLink struct {
+	Type      GraphNodeType
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Label     string
+	Status    string
+	RiskScore int
+}
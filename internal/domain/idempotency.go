@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Idempotency headers for retried POST requests
+const (
+	IdempotencyKeyHeader   = "Idempotency-Key"
+	IdempotentReplayHeader = "Idempotent-Replayed"
+)
+
+// IdempotencyRecord is what is stored under an idempotency key: the
+// fingerprint of the request that claimed it and, once that request has
+// finished, the response to replay
+type IdempotencyRecord struct {
+	Fingerprint string    `json:"fingerprint"`
+	Completed   bool      `json:"completed"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
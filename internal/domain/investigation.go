@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -39,10 +42,39 @@ const (
 	PriorityCritical InvestigationPriority = "CRITICAL"
 )
 
+// Timeline event types
+const (
+	TimelineEventSLABreached = "SLA_BREACHED"
+	TimelineEventSLAAtRisk   = "SLA_AT_RISK"
+	TimelineEventAssigned    = "ASSIGNED"
+	TimelineEventEscalated   = "ESCALATED"
+	TimelineEventOpened      = "OPENED"
+	TimelineEventNoteAdded   = "NOTE_ADDED"
+)
+
+// MaxNoteLength caps an investigation note, in characters
+const MaxNoteLength = 10000
+
+// SystemActorID identifies automated actions in audit and timeline records
+var SystemActorID = uuid.Nil
+
+// Escalate returns the next priority level, capped at CRITICAL
+func (p InvestigationPriority) Escalate() InvestigationPriority {
+	switch p {
+	case PriorityLow:
+		return PriorityMedium
+	case PriorityMedium:
+		return PriorityHigh
+	default:
+		return PriorityCritical
+	}
+}
+
 // Investigation represents an AML investigation
 type Investigation struct {
 	ID         uuid.UUID `json:"id" db:"id"`
 	CaseNumber string    `json:"case_number" db:"case_number"`
+	TenantID   string    `json:"tenant_id" db:"tenant_id"`
 
 	// Subject
 	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
@@ -80,6 +112,7 @@ type Investigation struct {
 	// SLA
 	DueDate     time.Time `json:"due_date" db:"due_date"`
 	SLABreached bool      `json:"sla_breached" db:"sla_breached"`
+	SLAAtRisk   bool      `json:"sla_at_risk" db:"sla_at_risk"`
 
 	// Timestamps
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
@@ -95,6 +128,18 @@ type Evidence struct {
 	Reference   string    `json:"reference"` // URL or ID reference
 	AddedBy     uuid.UUID `json:"added_by"`
 	AddedAt     time.Time `json:"added_at"`
+
+	// Attached file, held in object storage under ObjectKey
+	ObjectKey   string `json:"object_key,omitempty"`
+	FileName    string `json:"file_name,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+
+	// Evidence is soft-deleted only, so the record and hash remain for audit
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy    *uuid.UUID `json:"deleted_by,omitempty"`
+	DeleteReason string     `json:"delete_reason,omitempty"`
 }
 
 // InvestigationNote represents a note/comment on an investigation
@@ -108,6 +153,56 @@ type InvestigationNote struct {
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// AddNoteRequest adds a note to an investigation. Internal notes are kept
+// out of note listings unless they are asked for explicitly.
+type AddNoteRequest struct {
+	AuthorID   uuid.UUID `json:"author_id" validate:"required"`
+	Content    string    `json:"content" validate:"required"`
+	IsInternal bool      `json:"is_internal"`
+}
+
+// Validate checks that the note names an author and has content
+func (r *AddNoteRequest) Validate() error {
+	switch {
+	case r.AuthorID == uuid.Nil || strings.TrimSpace(r.Content) == "":
+		return fmt.Errorf("%w: author_id and content are required", ErrValidation)
+	case utf8.RuneCountInString(r.Content) > MaxNoteLength:
+		return fmt.Errorf("%w: content must be at most %d characters", ErrValidation, MaxNoteLength)
+	}
+	return nil
+}
+
+// NewNote builds the requested note on an investigation
+func (r *AddNoteRequest) NewNote(investigationID uuid.UUID, now time.Time) *InvestigationNote {
+	return &InvestigationNote{
+		ID:              uuid.New(),
+		InvestigationID: investigationID,
+		AuthorID:        r.AuthorID,
+		Content:         r.Content,
+		IsInternal:      r.IsInternal,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// AddedEvent returns the timeline event recording the note. The content
+// stays on the note so an internal note does not leak through the timeline.
+func (n *InvestigationNote) AddedEvent() *InvestigationTimeline {
+	description := "Note added"
+	if n.IsInternal {
+		description = "Internal note added"
+	}
+	return &InvestigationTimeline{
+		ID:              uuid.New(),
+		InvestigationID: n.InvestigationID,
+		EventType:       TimelineEventNoteAdded,
+		Description:     description,
+		NewValue:        n.ID.String(),
+		ActorID:         n.AuthorID,
+		CreatedAt:       n.CreatedAt,
+	}
+}
+
 // InvestigationTimeline represents an event in investigation history
 type InvestigationTimeline struct {
 	ID              uuid.UUID `json:"id" db:"id"`
@@ -125,9 +220,46 @@ func (i *Investigation) IsClosed() bool {
 	return i.Status == InvestigationStatusClosed
 }
 
-// IsOverdue returns true if the investigation has breached SLA
-func (i *Investigation) IsOverdue() bool {
-	return !i.IsClosed() && time.Now().After(i.DueDate)
+// IsOverdue returns true if the investigation has breached SLA at now
+func (i *Investigation) IsOverdue(now time.Time) bool {
+	return !i.IsClosed() && now.After(i.DueDate)
+}
+
+// SLAElapsed returns the share of the investigation's SLA window used by
+// now; 1 or more means the due date has passed
+func (i *Investigation) SLAElapsed(now time.Time) float64 {
+	window := i.DueDate.Sub(i.CreatedAt)
+	if window <= 0 {
+		return 1
+	}
+	return float64(now.Sub(i.CreatedAt)) / float64(window)
+}
+
+// Assign gives the investigation to an analyst
+func (i *Investigation) Assign(assignee, assignedBy uuid.UUID, now time.Time) {
+	i.AssignedTo = &assignee
+	i.AssignedBy = &assignedBy
+	i.AssignedAt = &now
+	i.Status = InvestigationStatusAssigned
+	i.UpdatedAt = now
+}
+
+// OpenedEvent returns the timeline event recording that actorID opened the
+// investigation
+func (i *Investigation) OpenedEvent(actorID uuid.UUID) *InvestigationTimeline {
+	description := fmt.Sprintf("Opened with %s priority", i.Priority)
+	if i.AlertID != nil {
+		description = fmt.Sprintf("Opened from alert %s with %s priority", i.AlertID, i.Priority)
+	}
+	return &InvestigationTimeline{
+		ID:              uuid.New(),
+		InvestigationID: i.ID,
+		EventType:       TimelineEventOpened,
+		Description:     description,
+		NewValue:        string(InvestigationStatusOpen),
+		ActorID:         actorID,
+		CreatedAt:       i.CreatedAt,
+	}
 }
 
 // CanAssign returns true if the investigation can be assigned
@@ -143,14 +275,57 @@ func (i *Investigation) CanClose() bool {
 // CreateInvestigationRequest represents a request to create an investigation
 type CreateInvestigationRequest struct {
 	UserID            uuid.UUID             `json:"user_id" validate:"required"`
-	TransactionID     *uuid.UUID            `json:"transaction_id,omitempty"`
-	ScreeningResultID *uuid.UUID            `json:"screening_result_id,omitempty"`
-	AlertID           *uuid.UUID            `json:"alert_id,omitempty"`
+	TransactionID     *uuid.UUID            `json:"transaction_id,omitempty" validate:"omitempty,uuid_not_nil"`
+	ScreeningResultID *uuid.UUID            `json:"screening_result_id,omitempty" validate:"omitempty,uuid_not_nil"`
+	AlertID           *uuid.UUID            `json:"alert_id,omitempty" validate:"omitempty,uuid_not_nil"`
 	InvestigationType string                `json:"investigation_type" validate:"required"`
 	Title             string                `json:"title" validate:"required,min=5,max=200"`
 	Description       string                `json:"description" validate:"required,min=10"`
 	Priority          InvestigationPriority `json:"priority" validate:"required,oneof=LOW MEDIUM HIGH CRITICAL"`
 	RiskScore         int                   `json:"risk_score" validate:"min=0,max=100"`
+	CreatedBy         uuid.UUID             `json:"created_by" validate:"required"`
+
+	// AutoAssign gives the case to the least loaded analyst with capacity
+	AutoAssign bool `json:"auto_assign,omitempty"`
+}
+
+// Validate checks the fields required to open an investigation
+func (r *CreateInvestigationRequest) Validate() error {
+	switch {
+	case r.UserID == uuid.Nil || r.CreatedBy == uuid.Nil:
+		return fmt.Errorf("%w: user_id and created_by are required", ErrValidation)
+	case r.InvestigationType == "" || r.Title == "" || r.Description == "":
+		return fmt.Errorf("%w: investigation_type, title and description are required", ErrValidation)
+	case r.RiskScore < 0 || r.RiskScore > 100:
+		return fmt.Errorf("%w: risk_score must be between 0 and 100", ErrValidation)
+	}
+
+	switch r.Priority {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical:
+		return nil
+	}
+	return fmt.Errorf("%w: priority must be one of LOW, MEDIUM, HIGH, CRITICAL", ErrValidation)
+}
+
+// NewInvestigation opens the requested investigation, due after sla
+func (r *CreateInvestigationRequest) NewInvestigation(sla time.Duration, now time.Time) *Investigation {
+	return &Investigation{
+		ID:                uuid.New(),
+		CaseNumber:        GenerateCaseNumber(now),
+		UserID:            r.UserID,
+		TransactionID:     r.TransactionID,
+		ScreeningResultID: r.ScreeningResultID,
+		AlertID:           r.AlertID,
+		Status:            InvestigationStatusOpen,
+		Priority:          r.Priority,
+		RiskScore:         r.RiskScore,
+		InvestigationType: r.InvestigationType,
+		Title:             r.Title,
+		Description:       r.Description,
+		DueDate:           now.Add(sla),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
 }
 
 // AssignInvestigationRequest represents a request to assign an investigation
@@ -187,11 +362,13 @@ type InvestigationSummary struct {
 	AssignedTo  *uuid.UUID            `json:"assigned_to,omitempty"`
 	DueDate     time.Time             `json:"due_date"`
 	SLABreached bool                  `json:"sla_breached"`
+	SLAAtRisk   bool                  `json:"sla_at_risk"`
 	CreatedAt   time.Time             `json:"created_at"`
 }
 
-// ToSummary converts Investigation to InvestigationSummary
-func (i *Investigation) ToSummary() *InvestigationSummary {
+// ToSummary converts Investigation to InvestigationSummary, judging
+// whether it is overdue at now
+func (i *Investigation) ToSummary(now time.Time) *InvestigationSummary {
 	return &InvestigationSummary{
 		ID:          i.ID,
 		CaseNumber:  i.CaseNumber,
@@ -202,7 +379,44 @@ func (i *Investigation) ToSummary() *InvestigationSummary {
 		Title:       i.Title,
 		AssignedTo:  i.AssignedTo,
 		DueDate:     i.DueDate,
-		SLABreached: i.SLABreached || i.IsOverdue(),
+		SLABreached: i.SLABreached || i.IsOverdue(now),
+		SLAAtRisk:   i.SLAAtRisk,
 		CreatedAt:   i.CreatedAt,
 	}
 }
+
+// InvestigationFilter holds filters and pagination for listing investigations
+type InvestigationFilter struct {
+	Status      *InvestigationStatus
+	Priority    *InvestigationPriority
+	AssignedTo  *uuid.UUID
+	UserID      *uuid.UUID
+	SLABreached *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Limit       int
+	Offset      int
+}
+
+// InvestigationListResponse is a page of investigation summaries
+type InvestigationListResponse struct {
+	Investigations []*InvestigationSummary `json:"investigations"`
+	Total          int                     `json:"total"`
+	Limit          int                     `json:"limit"`
+	Offset         int                     `json:"offset"`
+}
+
+// InvestigationSLABreachedEvent is the Kafka event published when an
+// investigation passes its due date while still open
+type InvestigationSLABreachedEvent struct {
+	EventID         uuid.UUID             `json:"event_id"`
+	EventType       string                `json:"event_type"`
+	Timestamp       time.Time             `json:"timestamp"`
+	InvestigationID uuid.UUID             `json:"investigation_id"`
+	CaseNumber      string                `json:"case_number"`
+	UserID          uuid.UUID             `json:"user_id"`
+	Status          InvestigationStatus   `json:"status"`
+	Priority        InvestigationPriority `json:"priority"`
+	AssignedTo      *uuid.UUID            `json:"assigned_to,omitempty"`
+	DueDate         time.Time             `json:"due_date"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Categories of personal data a read exposes
+const (
+	// PIIAccessFull is a read of the record with its personal data in the
+	// clear, such as a FinCEN export or investigation notes
+	PIIAccessFull = "FULL"
+
+	// PIIAccessSummary is a read of the record with identifiers such as the
+	// subject's SSN redacted
+	PIIAccessSummary = "SUMMARY"
+)
+
+// Purpose codes a reader gives for viewing a data subject's records
+const (
+	AccessPurposeInvestigation      = "INVESTIGATION"
+	AccessPurposeSARPreparation     = "SAR_PREPARATION"
+	AccessPurposeQualityAssurance   = "QUALITY_ASSURANCE"
+	AccessPurposeRegulatoryRequest  = "REGULATORY_REQUEST"
+	AccessPurposeLawEnforcement     = "LAW_ENFORCEMENT_REQUEST"
+	AccessPurposeDataSubjectRequest = "DATA_SUBJECT_REQUEST"
+	AccessPurposeInternalAudit      = "INTERNAL_AUDIT"
+)
+
+var accessPurposes = map[string]bool{
+	AccessPurposeInvestigation:      true,
+	AccessPurposeSARPreparation:     true,
+	AccessPurposeQualityAssurance:   true,
+	AccessPurposeRegulatoryRequest:  true,
+	AccessPurposeLawEnforcement:     true,
+	AccessPurposeDataSubjectRequest: true,
+	AccessPurposeInternalAudit:      true,
+}
+
+// IsAccessPurpose reports whether p is a known purpose code
+func IsAccessPurpose(p string) bool {
+	return accessPurposes[p]
+}
+
+// PIIAccess is one read of the personal data held on a filing or
+// investigation, as recorded in the audit log
+type PIIAccess struct {
+	ActorID    uuid.UUID `json:"actor_id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Category   string    `json:"category"`
+	Purpose    string    `json:"purpose,omitempty"`
+
+	// Route is the endpoint read, e.g. "GET /api/v1/filings/:id"
+	Route string `json:"route"`
+
+	// SubjectUserIDs are the users whose data the record holds
+	SubjectUserIDs []uuid.UUID `json:"subject_user_ids"`
+
+	RequestID  string    `json:"request_id,omitempty"`
+	AccessedAt time.Time `json:"accessed_at"`
+}
+
+// PIIAccessReport lists the recorded reads of one data subject's personal
+// data, oldest first, for answering a data subject access request
+type PIIAccessReport struct {
+	UserID      uuid.UUID    `json:"user_id"`
+	From        *time.Time   `json:"from,omitempty"`
+	To          time.Time    `json:"to"`
+	Accesses    []*PIIAccess `json:"accesses"`
+	GeneratedAt time.Time    `json:"generated_at"`
+}
@@ -0,0 +1,189 @@
+package domain
+
+import "sort"
+
+// ReasonCode is a machine-readable explanation for a screening decision
+type ReasonCode string
+
+const (
+	ReasonOFACExact            ReasonCode = "RC001_OFAC_EXACT"
+	ReasonOFACMatch            ReasonCode = "RC002_OFAC_MATCH"
+	ReasonPEPMatch             ReasonCode = "RC003_PEP_MATCH"
+	ReasonUserWatchlist        ReasonCode = "RC004_USER_WATCHLIST"
+	ReasonUserPEP              ReasonCode = "RC005_USER_PEP"
+	ReasonPriorSARs            ReasonCode = "RC006_PRIOR_SARS"
+	ReasonPEPAssociate         ReasonCode = "RC007_PEP_ASSOCIATE"
+	ReasonDenylistedAccount    ReasonCode = "RC008_DENYLISTED_ACCOUNT"
+	ReasonSanctionedBank       ReasonCode = "RC009_SANCTIONED_BANK"
+	ReasonStructuring          ReasonCode = "RC010_STRUCTURING"
+	ReasonRapidCycling         ReasonCode = "RC011_RAPID_CYCLING"
+	ReasonGeoConcentration     ReasonCode = "RC012_GEO_CONCENTRATION"
+	ReasonMixingLayering       ReasonCode = "RC013_MIXING_LAYERING"
+	ReasonSmurfing             ReasonCode = "RC014_SMURFING"
+	ReasonRoundTripping        ReasonCode = "RC015_ROUND_TRIPPING"
+	ReasonUnusualTime          ReasonCode = "RC016_UNUSUAL_TIME"
+	ReasonDormantReactivation  ReasonCode = "RC017_DORMANT_REACTIVATION"
+	ReasonVelocity             ReasonCode = "RC020_VELOCITY"
+	ReasonProfileAmount        ReasonCode = "RC021_PROFILE_AMOUNT"
+	ReasonRoundAmount          ReasonCode = "RC022_ROUND_AMOUNT"
+	ReasonThresholdAdjacent    ReasonCode = "RC023_THRESHOLD_ADJACENT"
+	ReasonRepeatedAmount       ReasonCode = "RC024_REPEATED_AMOUNT"
+	ReasonDescriptionKeyword   ReasonCode = "RC025_DESCRIPTION_KEYWORD"
+	ReasonHighRiskCountry      ReasonCode = "RC030_HIGH_RISK_COUNTRY"
+	ReasonCrossBorder          ReasonCode = "RC031_CROSS_BORDER"
+	ReasonHighAmount           ReasonCode = "RC032_HIGH_AMOUNT"
+	ReasonDeniedIP             ReasonCode = "RC033_DENIED_IP"
+	ReasonAnonymizer           ReasonCode = "RC034_ANONYMIZER"
+	ReasonDeviceReputation     ReasonCode = "RC035_DEVICE_REPUTATION"
+	ReasonGeoMismatch          ReasonCode = "RC036_GEO_MISMATCH"
+	ReasonUnknownCurrency      ReasonCode = "RC037_UNKNOWN_CURRENCY"
+	ReasonNewCountry           ReasonCode = "RC038_NEW_COUNTRY"
+	ReasonImpossibleTravel     ReasonCode = "RC039_IMPOSSIBLE_TRAVEL"
+	ReasonTransactionRule      ReasonCode = "RC040_TRANSACTION_RULE"
+	ReasonIPHighRiskCountry    ReasonCode = "RC041_IP_HIGH_RISK_COUNTRY"
+	ReasonIPCountryMismatch    ReasonCode = "RC042_IP_COUNTRY_MISMATCH"
+	ReasonSharedDevice         ReasonCode = "RC043_SHARED_DEVICE"
+	ReasonCounterparty         ReasonCode = "RC044_COUNTERPARTY_REPUTATION"
+	ReasonEnhancedDueDiligence ReasonCode = "RC050_ENHANCED_DUE_DILIGENCE"
+	ReasonCheckUnavailable     ReasonCode = "RC090_CHECK_UNAVAILABLE"
+	ReasonOther                ReasonCode = "RC099_OTHER"
+)
+
+// ReasonCodeInfo describes a reason code for integrators
+type ReasonCodeInfo struct {
+	Code        ReasonCode `json:"code"`
+	Description string     `json:"description"`
+}
+
+// reasonCodeDescriptions documents every reason code
+var reasonCodeDescriptions = map[ReasonCode]string{
+	ReasonOFACExact:            "Exact OFAC sanctions match; transaction blocked",
+	ReasonOFACMatch:            "Counterparty matches the OFAC sanctions list",
+	ReasonPEPMatch:             "Counterparty is a Politically Exposed Person",
+	ReasonUserWatchlist:        "User is on the internal watchlist",
+	ReasonUserPEP:              "User is a Politically Exposed Person",
+	ReasonPriorSARs:            "User has prior SAR filings",
+	ReasonPEPAssociate:         "Counterparty is a relative or close associate of a Politically Exposed Person",
+	ReasonDenylistedAccount:    "Sender or receiver account is on the internal account denylist; transaction blocked",
+	ReasonSanctionedBank:       "Sender or receiver bank is on the OFAC sanctions list; transaction blocked",
+	ReasonStructuring:          "Structuring pattern detected",
+	ReasonRapidCycling:         "Rapid cycling of funds detected",
+	ReasonGeoConcentration:     "Unusual geographic concentration of counterparties",
+	ReasonMixingLayering:       "Mixing or layering pattern detected",
+	ReasonSmurfing:             "Smurfing across related accounts detected",
+	ReasonRoundTripping:        "Round-tripping of funds detected",
+	ReasonUnusualTime:          "Transaction at an unusual time for the user",
+	ReasonDormantReactivation:  "Long-dormant account suddenly moving a large amount",
+	ReasonVelocity:             "Transaction velocity exceeds the user's baseline",
+	ReasonProfileAmount:        "Amount is far above the user's historical average",
+	ReasonRoundAmount:          "Amount is a large round number in the currency sent",
+	ReasonThresholdAdjacent:    "Amount is just below the currency transaction reporting threshold",
+	ReasonRepeatedAmount:       "Same amount sent to the same counterparty repeatedly within a short window",
+	ReasonDescriptionKeyword:   "Description or reference contains a watchlisted term",
+	ReasonHighRiskCountry:      "Counterparty is in a high-risk country",
+	ReasonCrossBorder:          "Cross-border transaction",
+	ReasonHighAmount:           "Amount exceeds the high-value threshold",
+	ReasonDeniedIP:             "Transaction originated from a known-bad IP address",
+	ReasonAnonymizer:           "Transaction originated through Tor or an anonymizing proxy",
+	ReasonDeviceReputation:     "Device is denylisted or linked to previously blocked transactions",
+	ReasonGeoMismatch:          "Device location does not match the sender country",
+	ReasonUnknownCurrency:      "Currency has no exchange rate; amount thresholds could not be applied",
+	ReasonNewCountry:           "Cross-border transaction with a country outside the user's usual countries",
+	ReasonImpossibleTravel:     "Location is too far from the user's previous transaction to have travelled in the time between",
+	ReasonTransactionRule:      "Transaction type/channel rule adjusted the score",
+	ReasonIPHighRiskCountry:    "IP address is located in a high-risk country",
+	ReasonIPCountryMismatch:    "IP address is located outside the user's usual countries",
+	ReasonSharedDevice:         "Device or IP address is shared by many users",
+	ReasonCounterparty:         "Counterparty account has a history of blocked or suspicious transfers or SAR filings",
+	ReasonEnhancedDueDiligence: "Stricter enhanced due diligence thresholds applied",
+	ReasonCheckUnavailable:     "A critical check could not be completed; decision held as PENDING",
+	ReasonOther:                "Other risk factor",
+}
+
+// riskFactorReasonCodes maps each RiskFactor.Factor to its reason code
+var riskFactorReasonCodes = map[string]ReasonCode{
+	"OFAC_MATCH":                    ReasonOFACMatch,
+	"PEP_MATCH":                     ReasonPEPMatch,
+	"PEP_ASSOCIATE":                 ReasonPEPAssociate,
+	"DENYLISTED_ACCOUNT":            ReasonDenylistedAccount,
+	"SANCTIONED_BANK":               ReasonSanctionedBank,
+	"USER_WATCHLIST":                ReasonUserWatchlist,
+	"USER_PEP":                      ReasonUserPEP,
+	"PRIOR_SARS":                    ReasonPriorSARs,
+	"HIGH_RISK_COUNTRY":             ReasonHighRiskCountry,
+	"COUNTRY_RISK_RATING":           ReasonHighRiskCountry,
+	"CROSS_BORDER":                  ReasonCrossBorder,
+	"HIGH_AMOUNT":                   ReasonHighAmount,
+	"TRANSACTION_RULE":              ReasonTransactionRule,
+	"DENIED_IP":                     ReasonDeniedIP,
+	"ANONYMIZER":                    ReasonAnonymizer,
+	"DENIED_DEVICE":                 ReasonDeviceReputation,
+	"BLOCKED_DEVICE":                ReasonDeviceReputation,
+	"GEO_MISMATCH":                  ReasonGeoMismatch,
+	"UNKNOWN_CURRENCY":              ReasonUnknownCurrency,
+	"PROFILE_AMOUNT":                ReasonProfileAmount,
+	"ROUND_AMOUNT":                  ReasonRoundAmount,
+	"THRESHOLD_ADJACENT":            ReasonThresholdAdjacent,
+	"REPEATED_AMOUNT":               ReasonRepeatedAmount,
+	"DESCRIPTION_KEYWORD":           ReasonDescriptionKeyword,
+	"NEW_COUNTRY":                   ReasonNewCountry,
+	"IP_HIGH_RISK_COUNTRY":          ReasonIPHighRiskCountry,
+	"IP_COUNTRY_MISMATCH":           ReasonIPCountryMismatch,
+	"IMPOSSIBLE_TRAVEL":             ReasonImpossibleTravel,
+	"SHARED_DEVICE":                 ReasonSharedDevice,
+	"COUNTERPARTY_REPUTATION":       ReasonCounterparty,
+	string(PatternStructuring):      ReasonStructuring,
+	string(PatternRapidCycling):     ReasonRapidCycling,
+	string(PatternGeoConcentration): ReasonGeoConcentration,
+	string(PatternVelocitySpike):    ReasonVelocity,
+	string(PatternMixingLayering):   ReasonMixingLayering,
+	string(PatternSmurfing):         ReasonSmurfing,
+	string(PatternRoundTripping):    ReasonRoundTripping,
+	string(PatternUnusualTime):      ReasonUnusualTime,
+
+	string(PatternDormantReactivation): ReasonDormantReactivation,
+}
+
+// ReasonCodeForFactor returns the reason code for a risk factor name
+func ReasonCodeForFactor(factor string) ReasonCode {
+	if code, ok := riskFactorReasonCodes[factor]; ok {
+		return code
+	}
+	return ReasonOther
+}
+
+// AllReasonCodes returns every reason code with its description, sorted by code
+func AllReasonCodes() []ReasonCodeInfo {
+	codes := make([]ReasonCodeInfo, 0, len(reasonCodeDescriptions))
+	for code, description := range reasonCodeDescriptions {
+		codes = append(codes, ReasonCodeInfo{Code: code, Description: description})
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
+
+// BuildReasonCodes derives the sorted, de-duplicated reason codes for a result
+// from its risk factors, OFAC override and applied thresholds
+func BuildReasonCodes(result *ScreeningResult) []string {
+	seen := make(map[ReasonCode]bool)
+
+	if result.OFACMatch != nil && result.OFACMatch.Matched && result.OFACMatch.MatchType == MatchTypeExact {
+		seen[ReasonOFACExact] = true
+	}
+	if result.AppliedThresholds != nil && result.AppliedThresholds.Tier == ThresholdTierEDD {
+		seen[ReasonEnhancedDueDiligence] = true
+	}
+	if result.HasBlockingFailure() {
+		seen[ReasonCheckUnavailable] = true
+	}
+	for _, factor := range result.RiskFactors {
+		seen[ReasonCodeForFactor(factor.Factor)] = true
+	}
+
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+	return codes
+}
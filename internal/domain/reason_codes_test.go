@@ -0,0 +1,71 @@
+package domain
+
+import "testing"
+
+func TestReasonCodeForFactor(t *testing.T) {
+	tests := []struct {
+		factor string
+		want   ReasonCode
+	}{
+		{"OFAC_MATCH", ReasonOFACMatch},
+		{"PEP_MATCH", ReasonPEPMatch},
+		{"PEP_ASSOCIATE", ReasonPEPAssociate},
+		{"DENYLISTED_ACCOUNT", ReasonDenylistedAccount},
+		{"SANCTIONED_BANK", ReasonSanctionedBank},
+		{"USER_WATCHLIST", ReasonUserWatchlist},
+		{"USER_PEP", ReasonUserPEP},
+		{"PRIOR_SARS", ReasonPriorSARs},
+		{"HIGH_RISK_COUNTRY", ReasonHighRiskCountry},
+		{"COUNTRY_RISK_RATING", ReasonHighRiskCountry},
+		{"CROSS_BORDER", ReasonCrossBorder},
+		{"HIGH_AMOUNT", ReasonHighAmount},
+		{"TRANSACTION_RULE", ReasonTransactionRule},
+		{"DENIED_IP", ReasonDeniedIP},
+		{"ANONYMIZER", ReasonAnonymizer},
+		{"DENIED_DEVICE", ReasonDeviceReputation},
+		{"BLOCKED_DEVICE", ReasonDeviceReputation},
+		{"GEO_MISMATCH", ReasonGeoMismatch},
+		{"UNKNOWN_CURRENCY", ReasonUnknownCurrency},
+		{"PROFILE_AMOUNT", ReasonProfileAmount},
+		{"ROUND_AMOUNT", ReasonRoundAmount},
+		{"THRESHOLD_ADJACENT", ReasonThresholdAdjacent},
+		{"REPEATED_AMOUNT", ReasonRepeatedAmount},
+		{"DESCRIPTION_KEYWORD", ReasonDescriptionKeyword},
+		{"NEW_COUNTRY", ReasonNewCountry},
+		{"IP_HIGH_RISK_COUNTRY", ReasonIPHighRiskCountry},
+		{"IP_COUNTRY_MISMATCH", ReasonIPCountryMismatch},
+		{"IMPOSSIBLE_TRAVEL", ReasonImpossibleTravel},
+		{"SHARED_DEVICE", ReasonSharedDevice},
+		{"COUNTERPARTY_REPUTATION", ReasonCounterparty},
+		{string(PatternStructuring), ReasonStructuring},
+		{string(PatternRapidCycling), ReasonRapidCycling},
+		{string(PatternGeoConcentration), ReasonGeoConcentration},
+		{string(PatternVelocitySpike), ReasonVelocity},
+		{string(PatternMixingLayering), ReasonMixingLayering},
+		{string(PatternSmurfing), ReasonSmurfing},
+		{string(PatternRoundTripping), ReasonRoundTripping},
+		{string(PatternUnusualTime), ReasonUnusualTime},
+		{string(PatternDormantReactivation), ReasonDormantReactivation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.factor, func(t *testing.T) {
+			got := ReasonCodeForFactor(tt.factor)
+			if got == ReasonOther {
+				t.Fatalf("ReasonCodeForFactor(%q) fell back to %s", tt.factor, ReasonOther)
+			}
+			if got != tt.want {
+				t.Errorf("ReasonCodeForFactor(%q) = %s, want %s", tt.factor, got, tt.want)
+			}
+			if reasonCodeDescriptions[got] == "" {
+				t.Errorf("reason code %s has no description", got)
+			}
+		})
+	}
+
+	if len(tests) != len(riskFactorReasonCodes) {
+		t.Errorf("table lists %d factors but %d are mapped; add the new ones", len(tests), len(riskFactorReasonCodes))
+	}
+	if got := ReasonCodeForFactor("NOT_A_FACTOR"); got != ReasonOther {
+		t.Errorf("ReasonCodeForFactor of an unknown factor = %s, want %s", got, ReasonOther)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+// ReplayCase is one historical transaction to replay, with the result it
+// was given at the time when that is known
+type ReplayCase struct {
+	Transaction *Transaction
+	Baseline    *ScreeningResult // nil when there is nothing to compare against
+}
+
+// ReplayChange describes a replayed transaction whose decision changed
+type ReplayChange struct {
+	TransactionID    uuid.UUID         `json:"transaction_id"`
+	BaselineDecision ScreeningDecision `json:"baseline_decision"`
+	ReplayDecision   ScreeningDecision `json:"replay_decision"`
+	BaselineScore    int               `json:"baseline_score"`
+	ReplayScore      int               `json:"replay_score"`
+	FactorsAdded     []string          `json:"factors_added,omitempty"`
+	FactorsRemoved   []string          `json:"factors_removed,omitempty"`
+}
+
+// ReplayReport summarises a replay of historical traffic against its
+// baseline results
+type ReplayReport struct {
+	Replayed int `json:"replayed"` // transactions re-screened
+	Compared int `json:"compared"` // replays that had a baseline
+	Failed   int `json:"failed"`   // replays that returned an error
+
+	DecisionsChanged    int     `json:"decisions_changed"`
+	ChangedDecisionRate float64 `json:"changed_decision_rate"` // DecisionsChanged / Compared
+
+	// Counts keyed by "BASELINE->REPLAY" decision, by score delta bucket
+	// and by risk factor name
+	DecisionTransitions map[string]int `json:"decision_transitions"`
+	ScoreDeltas         map[string]int `json:"score_deltas"`
+	FactorsAdded        map[string]int `json:"factors_added"`
+	FactorsRemoved      map[string]int `json:"factors_removed"`
+
+	Changes []ReplayChange `json:"changes"`
+}
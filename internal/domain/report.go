@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReportPeriod is the calendar period covered by a management information report
+type ReportPeriod string
+
+const (
+	ReportPeriodDaily   ReportPeriod = "DAILY"
+	ReportPeriodMonthly ReportPeriod = "MONTHLY"
+)
+
+// ParseReportPeriod accepts "daily" or "monthly" in any case
+func ParseReportPeriod(s string) (ReportPeriod, error) {
+	switch p := ReportPeriod(strings.ToUpper(s)); p {
+	case ReportPeriodDaily, ReportPeriodMonthly:
+		return p, nil
+	}
+	return "", fmt.Errorf("%w: unknown report period %q", ErrValidation, s)
+}
+
+// Bounds returns the half-open [start, end) calendar period containing t,
+// in t's location
+func (p ReportPeriod) Bounds(t time.Time) (start, end time.Time) {
+	y, m, d := t.Date()
+	if p == ReportPeriodMonthly {
+		start = time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// MIReport holds the regulatory management information for one calendar
+// period. Reports for closed periods are stored as snapshots so the figures
+// already reported do not move when late data arrives; a provisional report
+// covers a period that has not ended and is never stored.
+type MIReport struct {
+	Period         ReportPeriod     `json:"period" db:"period"`
+	PeriodStart    time.Time        `json:"period_start" db:"period_start"`
+	PeriodEnd      time.Time        `json:"period_end" db:"period_end"`
+	Screening      MIScreening      `json:"screening"`
+	Investigations MIInvestigations `json:"investigations"`
+	SAR            MIFilings        `json:"sar"`
+	CTR            MIFilings        `json:"ctr"`
+	TopPatterns    []PatternCount   `json:"top_patterns"`
+	Provisional    bool             `json:"provisional"`
+	GeneratedAt    time.Time        `json:"generated_at" db:"generated_at"`
+}
+
+// MIScreening counts first-time screenings in the period; re-screens of
+// earlier results are excluded so volumes match transaction counts
+type MIScreening struct {
+	Total      int64   `json:"total"`
+	Approved   int64   `json:"approved"`
+	Suspicious int64   `json:"suspicious"`
+	Blocked    int64   `json:"blocked"`
+	Pending    int64   `json:"pending"`
+	OFACHits   int64   `json:"ofac_hits"`
+	PEPHits    int64   `json:"pep_hits"`
+	Hits       int64   `json:"hits"`     // suspicious or blocked
+	HitRate    float64 `json:"hit_rate"` // hits / total
+}
+
+// MIInvestigations counts investigations opened and closed in the period
+type MIInvestigations struct {
+	Opened            int64   `json:"opened"`
+	Closed            int64   `json:"closed"`
+	FalsePositives    int64   `json:"false_positives"`
+	FalsePositiveRate float64 `json:"false_positive_rate"` // false positives / closed
+}
+
+// MIFilings counts filings of one type submitted in the period. Submitted,
+// OnTime and Late cover original filings; amendments are counted apart since
+// they have no due date of their own.
+type MIFilings struct {
+	Submitted  int64   `json:"submitted"`
+	Amendments int64   `json:"amendments"`
+	OnTime     int64   `json:"on_time"`
+	Late       int64   `json:"late"`
+	PastDue    int64   `json:"past_due"`     // due in the period and not submitted by the due date
+	OnTimeRate float64 `json:"on_time_rate"` // on time / submitted
+}
+
+// PatternCount is the number of screenings in which a pattern was detected
+type PatternCount struct {
+	PatternType PatternType `json:"pattern_type"`
+	Count       int64       `json:"count"`
+}
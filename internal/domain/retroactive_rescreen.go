@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetroactiveRescreenStatus is the state of a retroactive re-screen run
+type RetroactiveRescreenStatus string
+
+const (
+	RetroactiveRescreenQueued    RetroactiveRescreenStatus = "QUEUED"
+	RetroactiveRescreenRunning   RetroactiveRescreenStatus = "RUNNING"
+	RetroactiveRescreenCompleted RetroactiveRescreenStatus = "COMPLETED"
+	RetroactiveRescreenFailed    RetroactiveRescreenStatus = "FAILED"
+)
+
+// RetroactiveRescreenRequest selects stored screenings to replay against
+// the current sanctions and PEP lists after a list change, or against
+// archived versions of them
+type RetroactiveRescreenRequest struct {
+	From time.Time `json:"from" validate:"required"`
+	To   time.Time `json:"to" validate:"required,date_after=From"` // exclusive
+
+	// EntityID limits the replay to transactions with a sender or receiver
+	// matching this SDN entity's names; every transaction when empty
+	EntityID string `json:"entity_id,omitempty"`
+
+	// OFACListVersion and PEPListVersion, given together, replay against
+	// those archived list versions instead of the current lists
+	OFACListVersion int64 `json:"ofac_list_version,omitempty"`
+	PEPListVersion  int64 `json:"pep_list_version,omitempty"`
+
+	Reason  string    `json:"reason" validate:"required"`
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+}
+
+// Validate checks that the request names an actor, a reason, a non-empty
+// date range and either both list versions or neither
+func (r *RetroactiveRescreenRequest) Validate() error {
+	if r.ActorID == uuid.Nil {
+		return fmt.Errorf("%w: actor_id is required", ErrValidation)
+	}
+	if r.Reason == "" {
+		return fmt.Errorf("%w: reason is required", ErrValidation)
+	}
+	if r.From.IsZero() || r.To.IsZero() || !r.From.Before(r.To) {
+		return fmt.Errorf("%w: from and to are required and from must be before to", ErrValidation)
+	}
+	if r.OFACListVersion < 0 || r.PEPListVersion < 0 || (r.OFACListVersion == 0) != (r.PEPListVersion == 0) {
+		return fmt.Errorf("%w: ofac_list_version and pep_list_version must be given together", ErrValidation)
+	}
+	return nil
+}
+
+// Pinned reports whether the request replays against archived list
+// versions
+func (r *RetroactiveRescreenRequest) Pinned() bool {
+	return r.OFACListVersion > 0
+}
+
+// RetroactiveRescreenRun reports the progress of a retroactive re-screen
+type RetroactiveRescreenRun struct {
+	ID      uuid.UUID                  `json:"id"`
+	Request RetroactiveRescreenRequest `json:"request"`
+	Status  RetroactiveRescreenStatus  `json:"status"`
+
+	Scanned      int `json:"scanned"`       // stored results read
+	Skipped      int `json:"skipped"`       // re-screens, results without a transaction, or no party match
+	Rescreened   int `json:"rescreened"`    // transactions replayed through the engine
+	Failed       int `json:"failed"`        // replays that returned an error
+	NewHits      int `json:"new_hits"`      // list matches the original screening did not have
+	AlertsRaised int `json:"alerts_raised"` // alerts tagged retroactive
+
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
@@ -4,12 +4,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/pkg/money"
 )
 
 // UserRiskProfile represents a user's AML risk assessment
 type UserRiskProfile struct {
-	ID     uuid.UUID `json:"id" db:"id"`
-	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	ID       uuid.UUID `json:"id" db:"id"`
+	TenantID string    `json:"tenant_id" db:"tenant_id"`
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
 
 	// Overall risk assessment
 	RiskScore      int       `json:"risk_score" db:"risk_score"` // 0-100
@@ -73,30 +76,57 @@ type VelocityData struct {
 	UserID uuid.UUID `json:"user_id" db:"user_id"`
 
 	// Hourly
-	TxCountHour int     `json:"tx_count_hour"`
-	AmountHour  float64 `json:"amount_hour"`
+	TxCountHour int          `json:"tx_count_hour"`
+	AmountHour  money.Amount `json:"amount_hour"`
 
 	// Daily
-	TxCountDay int     `json:"tx_count_day"`
-	AmountDay  float64 `json:"amount_day"`
+	TxCountDay int          `json:"tx_count_day"`
+	AmountDay  money.Amount `json:"amount_day"`
 
 	// Weekly
-	TxCountWeek int     `json:"tx_count_week"`
-	AmountWeek  float64 `json:"amount_week"`
+	TxCountWeek int          `json:"tx_count_week"`
+	AmountWeek  money.Amount `json:"amount_week"`
 
 	// Monthly
-	TxCountMonth int     `json:"tx_count_month"`
-	AmountMonth  float64 `json:"amount_month"`
+	TxCountMonth int          `json:"tx_count_month"`
+	AmountMonth  money.Amount `json:"amount_month"`
 
-	// Baselines
+	// Baselines over the days before today
 	AvgDailyTxCount   float64 `json:"avg_daily_tx_count"`
 	AvgDailyAmount    float64 `json:"avg_daily_amount"`
 	StdDevDailyAmount float64 `json:"std_dev_daily_amount"`
+	BaselineDays      int     `json:"baseline_days"` // days of history behind the baseline
 
 	// Last updated
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// VelocityBaseline is a user's typical daily activity, recomputed nightly
+// from screened transactions
+type VelocityBaseline struct {
+	AvgDailyTxCount   float64 `json:"avg_daily_tx_count"`
+	AvgDailyAmount    float64 `json:"avg_daily_amount"`
+	StdDevDailyAmount float64 `json:"std_dev_daily_amount"`
+	BaselineDays      int     `json:"baseline_days"`
+}
+
+// TransactionLocation is where and when a user transacted, kept with the
+// velocity data to detect impossible travel between transactions
+type TransactionLocation struct {
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Country   string    `json:"country,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// DailyActivity is one user's transaction count and amount on a UTC day
+type DailyActivity struct {
+	UserID uuid.UUID `json:"user_id"`
+	Day    time.Time `json:"day"`
+	Count  int       `json:"count"`
+	Amount float64   `json:"amount"`
+}
+
 // CalculateOverallRisk computes the weighted average risk score
 func (r *UserRiskProfile) CalculateOverallRisk() int {
 	// Weighted average of risk factors
@@ -137,9 +167,9 @@ func (r *UserRiskProfile) RequiresEnhancedDueDiligence() bool {
 	return r.IsPEP || r.HasOFACMatch || r.IsHighRisk() || r.OnWatchlist
 }
 
-// NeedsReview returns true if the risk profile needs manual review
-func (r *UserRiskProfile) NeedsReview() bool {
-	return time.Now().After(r.NextReviewDate) || r.HasOFACMatch
+// NeedsReview returns true if the risk profile needs manual review at now
+func (r *UserRiskProfile) NeedsReview(now time.Time) bool {
+	return now.After(r.NextReviewDate) || r.HasOFACMatch
 }
 
 // UpdateRiskProfileRequest represents a request to update a risk profile
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/pkg/money"
 )
 
 // ScreeningDecision represents the outcome of transaction screening
@@ -33,6 +35,10 @@ const (
 	MatchTypeExact MatchType = "EXACT"
 	MatchTypeFuzzy MatchType = "FUZZY"
 	MatchTypeAlias MatchType = "ALIAS"
+
+	// MatchTypePartial matches a listed name with initials expanded or
+	// middle names left out, e.g. "V. Putin" for "Vladimir Vladimirovich Putin"
+	MatchTypePartial MatchType = "PARTIAL"
 )
 
 // ScreeningResult represents the result of a transaction screening
@@ -40,6 +46,7 @@ type ScreeningResult struct {
 	ID            uuid.UUID `json:"id" db:"id"`
 	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
 	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	TenantID      string    `json:"tenant_id" db:"tenant_id"`
 
 	// Screening details
 	RiskScore int               `json:"risk_score" db:"risk_score"` // 0-100
@@ -52,14 +59,212 @@ type ScreeningResult struct {
 	RiskFactors    []RiskFactor   `json:"risk_factors" db:"risk_factors"`
 	PatternMatches []PatternMatch `json:"pattern_matches,omitempty" db:"pattern_matches"`
 
+	// Machine-readable decision reasons (see ReasonCode)
+	ReasonCodes []string `json:"reason_codes" db:"reason_codes"`
+
+	// Decision thresholds applied to this result
+	AppliedThresholds *DecisionThresholds `json:"applied_thresholds,omitempty" db:"applied_thresholds"`
+
+	// Screened transaction, kept so the result can be re-screened later
+	Transaction *Transaction `json:"transaction,omitempty" db:"transaction"`
+
+	// Last-update timestamps of the sanctions lists used for this screening
+	OFACListUpdatedAt *time.Time `json:"ofac_list_updated_at,omitempty" db:"ofac_list_updated_at"`
+	PEPListUpdatedAt  *time.Time `json:"pep_list_updated_at,omitempty" db:"pep_list_updated_at"`
+
+	// Versions of those lists, under which their snapshots are archived,
+	// and their content hashes, identifying the exact entries screened
+	// against. A version is 0 when it could not be recorded.
+	OFACListVersion int64  `json:"ofac_list_version,omitempty" db:"ofac_list_version"`
+	PEPListVersion  int64  `json:"pep_list_version,omitempty" db:"pep_list_version"`
+	OFACListHash    string `json:"ofac_list_hash,omitempty" db:"ofac_list_hash"`
+	PEPListHash     string `json:"pep_list_hash,omitempty" db:"pep_list_hash"`
+
+	// Version of the country risk table applied (see CountryRiskTable)
+	CountryRiskVersion *int `json:"country_risk_version,omitempty" db:"country_risk_version"`
+
+	// RescreenOfID links a re-screen to the result it re-evaluated
+	RescreenOfID *uuid.UUID `json:"rescreen_of_id,omitempty" db:"rescreen_of_id"`
+
+	// Checks that errored instead of completing
+	ChecksFailed []CheckFailure `json:"checks_failed,omitempty" db:"checks_failed"`
+
+	// Dependencies bypassed because their circuit breaker was open
+	DegradedDependencies []string `json:"degraded_dependencies,omitempty" db:"degraded_dependencies"`
+
+	// Checks disabled in configuration and not run
+	SkippedChecks []string `json:"skipped_checks,omitempty" db:"skipped_checks"`
+
+	// Typed errors for every check or step that did not complete
+	Errors []ScreeningError `json:"errors,omitempty" db:"errors"`
+
+	// Score and decision under the shadow configuration, when shadow mode
+	// is enabled; recorded for comparison and never enforced
+	ShadowScore    *int              `json:"shadow_score,omitempty" db:"shadow_score"`
+	ShadowDecision ScreeningDecision `json:"shadow_decision,omitempty" db:"shadow_decision"`
+
 	// Performance metrics
 	ScreeningDurationMs int64 `json:"screening_duration_ms" db:"screening_duration_ms"`
 
+	// IdempotentReplay is set when a stored result is returned for a redelivered transaction
+	IdempotentReplay bool `json:"idempotent_replay,omitempty" db:"-"`
+
+	// CacheHit is set when the result was served from the result cache for
+	// an identical transaction payload
+	CacheHit bool `json:"cache_hit,omitempty" db:"-"`
+
 	// Timestamps
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Screening check names
+const (
+	CheckOFAC        = "ofac"
+	CheckPEP         = "pep"
+	CheckRiskProfile = "risk_profile"
+	CheckVelocity    = "velocity"
+	CheckPatterns    = "patterns"
+	CheckReputation  = "reputation"
+	CheckGeoIP       = "geoip"
+
+	CheckSharedDevice    = "shared_device"
+	CheckCounterparty    = "counterparty"
+	CheckAccountDenylist = "account_denylist"
+)
+
+// Screening dependencies guarded by circuit breakers
+const (
+	DependencyOFACCache     = "ofac_cache"
+	DependencyPEPCache      = "pep_cache"
+	DependencyVelocityCache = "velocity_cache"
+	DependencyRiskProfiles  = "risk_profiles"
+)
+
+// CheckFailure records a screening check that could not be completed, as
+// opposed to one that completed without a match
+type CheckFailure struct {
+	Check string `json:"check"`
+	Error string `json:"error"`
+
+	// Blocking is set for fail-closed checks; the decision is held as PENDING
+	Blocking bool `json:"blocking"`
+
+	// Skipped is set when the check ran out of its time budget
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// ScreeningError reports a check or step of a screening that did not
+// complete, so consumers can tell a partial screening from a clean one
+type ScreeningError struct {
+	Code    string `json:"code"`            // e.g. OFAC_UNAVAILABLE, PATTERN_TIMEOUT
+	Check   string `json:"check,omitempty"` // check or dependency affected
+	Message string `json:"message"`
+}
+
+// RescreenResponse pairs an original screening result with its re-screen
+type RescreenResponse struct {
+	Original *ScreeningResult `json:"original"`
+	Rescreen *ScreeningResult `json:"rescreen"`
+	Diff     ScreeningDiff    `json:"diff"`
+}
+
+// ScreeningDiff summarises what changed between two screening results
+type ScreeningDiff struct {
+	DecisionChanged    bool              `json:"decision_changed"`
+	OldDecision        ScreeningDecision `json:"old_decision"`
+	NewDecision        ScreeningDecision `json:"new_decision"`
+	OldRiskScore       int               `json:"old_risk_score"`
+	NewRiskScore       int               `json:"new_risk_score"`
+	ScoreDelta         int               `json:"score_delta"`
+	AddedRiskFactors   []RiskFactor      `json:"added_risk_factors"`
+	RemovedRiskFactors []RiskFactor      `json:"removed_risk_factors"`
+	OFACListChanged    bool              `json:"ofac_list_changed"`
+	PEPListChanged     bool              `json:"pep_list_changed"`
+}
+
+// DiffScreeningResults compares a re-screen against the original result.
+// Risk factors are matched by factor name.
+func DiffScreeningResults(original, rescreen *ScreeningResult) ScreeningDiff {
+	diff := ScreeningDiff{
+		DecisionChanged:    original.Decision != rescreen.Decision,
+		OldDecision:        original.Decision,
+		NewDecision:        rescreen.Decision,
+		OldRiskScore:       original.RiskScore,
+		NewRiskScore:       rescreen.RiskScore,
+		ScoreDelta:         rescreen.RiskScore - original.RiskScore,
+		AddedRiskFactors:   []RiskFactor{},
+		RemovedRiskFactors: []RiskFactor{},
+		OFACListChanged:    listChanged(original.OFACListHash, rescreen.OFACListHash, original.OFACListUpdatedAt, rescreen.OFACListUpdatedAt),
+		PEPListChanged:     listChanged(original.PEPListHash, rescreen.PEPListHash, original.PEPListUpdatedAt, rescreen.PEPListUpdatedAt),
+	}
+
+	before := make(map[string]bool, len(original.RiskFactors))
+	for _, f := range original.RiskFactors {
+		before[f.Factor] = true
+	}
+	after := make(map[string]bool, len(rescreen.RiskFactors))
+	for _, f := range rescreen.RiskFactors {
+		after[f.Factor] = true
+		if !before[f.Factor] {
+			diff.AddedRiskFactors = append(diff.AddedRiskFactors, f)
+		}
+	}
+	for _, f := range original.RiskFactors {
+		if !after[f.Factor] {
+			diff.RemovedRiskFactors = append(diff.RemovedRiskFactors, f)
+		}
+	}
+
+	return diff
+}
+
+// listChanged compares list content hashes, falling back to the
+// last-update timestamps for results screened before hashes were recorded
+func listChanged(oldHash, newHash string, oldUpdatedAt, newUpdatedAt *time.Time) bool {
+	if oldHash != "" && newHash != "" {
+		return oldHash != newHash
+	}
+	return !sameTime(oldUpdatedAt, newUpdatedAt)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// Decision threshold tiers
+const (
+	ThresholdTierDefault = "default"
+	ThresholdTierEDD     = "edd"
+)
+
+// DecisionThresholds holds the score cutoffs used to reach a decision
+type DecisionThresholds struct {
+	Tier       string `json:"tier"`
+	Suspicious int    `json:"suspicious"`
+	Blocked    int    `json:"blocked"`
+}
+
+// DefaultDecisionThresholds returns the standard 50/80 cutoffs
+func DefaultDecisionThresholds() DecisionThresholds {
+	return DecisionThresholds{Tier: ThresholdTierDefault, Suspicious: 50, Blocked: 80}
+}
+
+// Decide returns the screening decision for a score under these thresholds
+func (t DecisionThresholds) Decide(score int) ScreeningDecision {
+	switch {
+	case score >= t.Blocked:
+		return DecisionBlocked
+	case score >= t.Suspicious:
+		return DecisionSuspicious
+	default:
+		return DecisionApproved
+	}
+}
+
 // OFACMatch represents a match against the OFAC sanctions list
 type OFACMatch struct {
 	Matched         bool      `json:"matched"`
@@ -70,18 +275,41 @@ type OFACMatch struct {
 	Program         string    `json:"program,omitempty"`
 	MatchedField    string    `json:"matched_field,omitempty"`
 	CheckDurationMs int64     `json:"check_duration_ms"`
+
+	// Degraded is set when the cache was bypassed and only the in-memory
+	// index was searched
+	Degraded bool `json:"degraded,omitempty"`
+
+	// CacheHit is set when the outcome came from the match cache rather
+	// than a list lookup
+	CacheHit bool `json:"cache_hit,omitempty"`
 }
 
 // PEPMatch represents a match against the PEP database
 type PEPMatch struct {
-	Matched         bool      `json:"matched"`
-	MatchScore      float64   `json:"match_score,omitempty"`
-	MatchType       MatchType `json:"match_type,omitempty"`
-	PEPName         string    `json:"pep_name,omitempty"`
-	PEPPosition     string    `json:"pep_position,omitempty"`
-	PEPCountry      string    `json:"pep_country,omitempty"`
-	RiskCategory    string    `json:"risk_category,omitempty"`
-	CheckDurationMs int64     `json:"check_duration_ms"`
+	Matched         bool       `json:"matched"`
+	MatchScore      float64    `json:"match_score,omitempty"`
+	MatchType       MatchType  `json:"match_type,omitempty"`
+	PEPName         string     `json:"pep_name,omitempty"`
+	PEPPosition     string     `json:"pep_position,omitempty"`
+	PEPCountry      string     `json:"pep_country,omitempty"`
+	PEPEndDate      *time.Time `json:"pep_end_date,omitempty"` // when the PEP left office
+	RiskCategory    string     `json:"risk_category,omitempty"`
+	CheckDurationMs int64      `json:"check_duration_ms"`
+
+	// Associate is set when the name matched a relative or close associate
+	// (RCA) of a PEP rather than the PEP. PEPName and PEPPosition then
+	// describe the principal and AssociateName the listed associate.
+	Associate     bool   `json:"associate,omitempty"`
+	AssociateName string `json:"associate_name,omitempty"`
+
+	// Degraded is set when the cache was bypassed and only the in-memory
+	// index was searched
+	Degraded bool `json:"degraded,omitempty"`
+
+	// CacheHit is set when the outcome came from the match cache rather
+	// than a list lookup
+	CacheHit bool `json:"cache_hit,omitempty"`
 }
 
 // RiskFactor represents a factor contributing to the risk score
@@ -90,6 +318,19 @@ type RiskFactor struct {
 	Weight      int    `json:"weight"` // Points added to risk score
 	Description string `json:"description"`
 	Details     string `json:"details,omitempty"`
+
+	// Country risk factors name the country risk tier that applied and
+	// whether it requires enhanced due diligence
+	Tier        string `json:"tier,omitempty"`
+	EDDRequired bool   `json:"edd_required,omitempty"`
+
+	// Amount-based factors record the transaction amount as screened and
+	// converted to the base currency the threshold is set in. Converted
+	// fields are empty when the currency has no rate.
+	OriginalAmount    money.Amount `json:"original_amount,omitempty"`
+	OriginalCurrency  string       `json:"original_currency,omitempty"`
+	ConvertedAmount   money.Amount `json:"converted_amount,omitempty"`
+	ConvertedCurrency string       `json:"converted_currency,omitempty"`
 }
 
 // PatternMatch represents a detected money laundering pattern
@@ -113,6 +354,8 @@ const (
 	PatternSmurfing         PatternType = "SMURFING"
 	PatternRoundTripping    PatternType = "ROUND_TRIPPING"
 	PatternUnusualTime      PatternType = "UNUSUAL_TIME"
+
+	PatternDormantReactivation PatternType = "DORMANT_REACTIVATION"
 )
 
 // CalculateRiskLevel returns the risk level based on score
@@ -131,14 +374,7 @@ func CalculateRiskLevel(score int) RiskLevel {
 
 // CalculateDecision returns the screening decision based on score
 func CalculateDecision(score int) ScreeningDecision {
-	switch {
-	case score >= 80:
-		return DecisionBlocked
-	case score >= 50:
-		return DecisionSuspicious
-	default:
-		return DecisionApproved
-	}
+	return DefaultDecisionThresholds().Decide(score)
 }
 
 // IsHighRisk returns true if the result warrants investigation
@@ -156,7 +392,70 @@ func (s *ScreeningResult) HasOFACMatch() bool {
 	return s.OFACMatch != nil && s.OFACMatch.Matched
 }
 
+// HasBlockingFailure returns true if a fail-closed check could not be completed
+func (s *ScreeningResult) HasBlockingFailure() bool {
+	for _, f := range s.ChecksFailed {
+		if f.Blocking {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDegraded returns true if screening ran with dependencies bypassed
+func (s *ScreeningResult) IsDegraded() bool {
+	return len(s.DegradedDependencies) > 0
+}
+
 // HasPEPMatch returns true if there was a PEP match
 func (s *ScreeningResult) HasPEPMatch() bool {
 	return s.PEPMatch != nil && s.PEPMatch.Matched
 }
+
+// ToResponse converts ScreeningResult to ScreeningResponse
+func (s *ScreeningResult) ToResponse() *ScreeningResponse {
+	riskFactors := make([]string, 0, len(s.RiskFactors))
+	for _, f := range s.RiskFactors {
+		riskFactors = append(riskFactors, f.Factor)
+	}
+
+	var checksFailed []string
+	for _, f := range s.ChecksFailed {
+		checksFailed = append(checksFailed, f.Check)
+	}
+
+	var errs []string
+	for _, e := range s.Errors {
+		errs = append(errs, e.Code)
+	}
+
+	return &ScreeningResponse{
+		ScreeningID:      s.ID,
+		TransactionID:    s.TransactionID,
+		Decision:         s.Decision,
+		RiskScore:        s.RiskScore,
+		RiskLevel:        s.RiskLevel,
+		ProcessingTimeMs: s.ScreeningDurationMs,
+		OFACMatch:        s.HasOFACMatch(),
+		PEPMatch:         s.HasPEPMatch(),
+		PatternDetected:  len(s.PatternMatches) > 0,
+		RiskFactors:      riskFactors,
+		ReasonCodes:      s.ReasonCodes,
+		ChecksFailed:     checksFailed,
+		SkippedChecks:    s.SkippedChecks,
+		Degraded:         s.IsDegraded(),
+		IdempotentReplay: s.IdempotentReplay,
+		CacheHit:         s.CacheHit,
+		Errors:           errs,
+	}
+}
+
+// ScreeningCompletedEvent is the Kafka event published after screening
+type ScreeningCompletedEvent struct {
+	EventID   uuid.UUID          `json:"event_id"`
+	EventType string             `json:"event_type"`
+	Timestamp time.Time          `json:"timestamp"`
+	TenantID  string             `json:"tenant_id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Result    *ScreeningResponse `json:"payload"`
+}
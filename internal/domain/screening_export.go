@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScreeningExportRequest selects the screening results for a bulk export
+type ScreeningExportRequest struct {
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`                 // exclusive
+	Decision ScreeningDecision `json:"decision,omitempty"` // all decisions when empty
+	ActorID  uuid.UUID         `json:"actor_id"`
+}
+
+// Validate checks that the export names an actor, a non-empty date range
+// and a known decision
+func (r *ScreeningExportRequest) Validate() error {
+	if r.ActorID == uuid.Nil {
+		return fmt.Errorf("%w: actor_id is required", ErrValidation)
+	}
+	if r.From.IsZero() || r.To.IsZero() || !r.From.Before(r.To) {
+		return fmt.Errorf("%w: from and to are required and from must be before to", ErrValidation)
+	}
+	switch r.Decision {
+	case "", DecisionApproved, DecisionSuspicious, DecisionBlocked, DecisionPending:
+	default:
+		return fmt.Errorf("%w: unknown decision %q", ErrValidation, r.Decision)
+	}
+	return nil
+}
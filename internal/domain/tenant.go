@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantSettings overrides the configured screening and reporting rules for
+// one tenant. Anything left unset falls back to the global configuration:
+// DecisionThresholds replace the configured thresholds of the risk tiers
+// they name, CountryRiskTiers, when set, replace the tiers of the country
+// risk table in force (its ratings still apply), and the SAR and CTR
+// thresholds replace the configured amounts.
+type TenantSettings struct {
+	TenantID           string                        `json:"tenant_id"`
+	DecisionThresholds map[string]DecisionThresholds `json:"decision_thresholds,omitempty"`
+	CountryRiskTiers   []CountryRiskTier             `json:"country_risk_tiers,omitempty"`
+	SARThreshold       *float64                      `json:"sar_threshold,omitempty"`
+	CTRThreshold       *float64                      `json:"ctr_threshold,omitempty"`
+	UpdatedBy          uuid.UUID                     `json:"updated_by"`
+	UpdatedAt          time.Time                     `json:"updated_at"`
+}
+
+// CountryTier returns the overriding tier a country is in, or nil if it is
+// in none
+func (s *TenantSettings) CountryTier(country string) *CountryRiskTier {
+	for i := range s.CountryRiskTiers {
+		for _, c := range s.CountryRiskTiers[i].Countries {
+			if c == country {
+				return &s.CountryRiskTiers[i]
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateTenantSettingsRequest replaces a tenant's settings. Fields left
+// out fall back to the global configuration.
+type UpdateTenantSettingsRequest struct {
+	DecisionThresholds map[string]DecisionThresholds `json:"decision_thresholds,omitempty"`
+	CountryRiskTiers   []CountryRiskTier             `json:"country_risk_tiers,omitempty" validate:"dive"`
+	SARThreshold       *float64                      `json:"sar_threshold,omitempty" validate:"omitempty,gt=0"`
+	CTRThreshold       *float64                      `json:"ctr_threshold,omitempty" validate:"omitempty,gt=0"`
+	ActorID            uuid.UUID                     `json:"actor_id" validate:"required"`
+}
+
+// Validate checks that thresholds name known risk tiers with scores between
+// 1 and 100, suspicious below blocked, and that the country risk tiers are
+// well formed
+func (r *UpdateTenantSettingsRequest) Validate() error {
+	for tier, t := range r.DecisionThresholds {
+		if tier != ThresholdTierDefault && tier != ThresholdTierEDD {
+			return fmt.Errorf("%w: unknown threshold tier %q, must be %s or %s", ErrValidation, tier, ThresholdTierDefault, ThresholdTierEDD)
+		}
+		if t.Suspicious < 1 || t.Blocked > 100 || t.Suspicious >= t.Blocked {
+			return fmt.Errorf("%w: %s thresholds must satisfy 1 <= suspicious < blocked <= 100", ErrValidation, tier)
+		}
+	}
+	if r.SARThreshold != nil && *r.SARThreshold <= 0 {
+		return fmt.Errorf("%w: sar_threshold must be positive", ErrValidation)
+	}
+	if r.CTRThreshold != nil && *r.CTRThreshold <= 0 {
+		return fmt.Errorf("%w: ctr_threshold must be positive", ErrValidation)
+	}
+	return validateCountryRiskTiers(r.CountryRiskTiers)
+}
+
+// NewSettings returns the settings the request saves for a tenant
+func (r *UpdateTenantSettingsRequest) NewSettings(tenantID string, now time.Time) *TenantSettings {
+	var thresholds map[string]DecisionThresholds
+	if len(r.DecisionThresholds) > 0 {
+		thresholds = make(map[string]DecisionThresholds, len(r.DecisionThresholds))
+		for tier, t := range r.DecisionThresholds {
+			t.Tier = tier
+			thresholds[tier] = t
+		}
+	}
+
+	return &TenantSettings{
+		TenantID:           tenantID,
+		DecisionThresholds: thresholds,
+		CountryRiskTiers:   r.CountryRiskTiers,
+		SARThreshold:       r.SARThreshold,
+		CTRThreshold:       r.CTRThreshold,
+		UpdatedBy:          r.ActorID,
+		UpdatedAt:          now,
+	}
+}
+
+// TenantSettingsResponse shows a tenant's stored overrides alongside the
+// rules in force for it once the global configuration fills the gaps
+type TenantSettingsResponse struct {
+	TenantID  string          `json:"tenant_id"`
+	Overrides *TenantSettings `json:"overrides,omitempty"`
+	Effective struct {
+		DecisionThresholds map[string]DecisionThresholds `json:"decision_thresholds"`
+		CountryRiskTiers   []CountryRiskTier             `json:"country_risk_tiers"`
+		SARThreshold       float64                       `json:"sar_threshold"`
+		CTRThreshold       float64                       `json:"ctr_threshold"`
+	} `json:"effective"`
+}
@@ -1,9 +1,37 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// Transaction types
+const (
+	TransactionTypeTransfer   = "TRANSFER"
+	TransactionTypeDeposit    = "DEPOSIT"
+	TransactionTypeWithdrawal = "WITHDRAWAL"
+	TransactionTypePayment    = "PAYMENT"
+)
+
+// Transaction channels
+const (
+	ChannelMobile = "MOBILE"
+	ChannelWeb    = "WEB"
+	ChannelBranch = "BRANCH"
+	ChannelAPI    = "API"
+)
+
+// Transaction directions
+const (
+	DirectionInbound  = "INBOUND"
+	DirectionOutbound = "OUTBOUND"
 )
 
 // Transaction represents a transaction to be screened
@@ -14,20 +42,22 @@ type Transaction struct {
 	AccountID uuid.UUID `json:"account_id"`
 
 	// Transaction details
-	Type      string  `json:"type"`      // TRANSFER, DEPOSIT, WITHDRAWAL, PAYMENT
-	Direction string  `json:"direction"` // INBOUND, OUTBOUND
-	Amount    float64 `json:"amount"`
-	Currency  string  `json:"currency"`
+	Type      string       `json:"type"`      // TRANSFER, DEPOSIT, WITHDRAWAL, PAYMENT
+	Direction string       `json:"direction"` // INBOUND, OUTBOUND
+	Amount    money.Amount `json:"amount"`
+	Currency  string       `json:"currency" validate:"omitempty,iso4217"`
 
 	// Parties
 	SenderName      string `json:"sender_name,omitempty"`
 	SenderAccount   string `json:"sender_account,omitempty"`
-	SenderCountry   string `json:"sender_country,omitempty"`
+	SenderCountry   string `json:"sender_country,omitempty" validate:"omitempty,iso3166_alpha2"`
 	SenderBank      string `json:"sender_bank,omitempty"`
+	SenderBankBIC   string `json:"sender_bank_bic,omitempty" validate:"omitempty,bic"`
 	ReceiverName    string `json:"receiver_name,omitempty"`
 	ReceiverAccount string `json:"receiver_account,omitempty"`
-	ReceiverCountry string `json:"receiver_country,omitempty"`
+	ReceiverCountry string `json:"receiver_country,omitempty" validate:"omitempty,iso3166_alpha2"`
 	ReceiverBank    string `json:"receiver_bank,omitempty"`
+	ReceiverBankBIC string `json:"receiver_bank_bic,omitempty" validate:"omitempty,bic"`
 
 	// Context
 	Description string `json:"description,omitempty"`
@@ -44,20 +74,115 @@ type Transaction struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// Party roles
+const (
+	PartyRoleCustomer     = "CUSTOMER"
+	PartyRoleCounterparty = "COUNTERPARTY"
+)
+
+// TransactionWindow selects the transactions of some users initiated in
+// [From, To)
+type TransactionWindow struct {
+	UserIDs []uuid.UUID
+	From    time.Time
+	To      time.Time
+
+	// ExcludeBlocked leaves out transactions their first screening blocked,
+	// which moved no money
+	ExcludeBlocked bool
+}
+
+// PartyName is a customer or counterparty name seen on a user's transactions
+type PartyName struct {
+	Name     string    `json:"name"`
+	UserID   uuid.UUID `json:"user_id"`
+	Role     string    `json:"role"` // CUSTOMER, COUNTERPARTY
+	TenantID string    `json:"tenant_id"`
+}
+
+// ScreeningPriority is how urgently a queued transaction is screened
+type ScreeningPriority string
+
+const (
+	ScreeningPriorityNormal ScreeningPriority = "NORMAL"
+	ScreeningPriorityHigh   ScreeningPriority = "HIGH"
+	ScreeningPriorityUrgent ScreeningPriority = "URGENT"
+)
+
+// ScreeningPriorities lists the screening priorities, most urgent first
+var ScreeningPriorities = []ScreeningPriority{
+	ScreeningPriorityUrgent,
+	ScreeningPriorityHigh,
+	ScreeningPriorityNormal,
+}
+
+// ParseScreeningPriority returns the priority named by s, ignoring case.
+// An empty name is NORMAL; ok is false for a name that is not a priority.
+func ParseScreeningPriority(s string) (ScreeningPriority, bool) {
+	if s == "" {
+		return ScreeningPriorityNormal, true
+	}
+	for _, p := range ScreeningPriorities {
+		if strings.EqualFold(s, string(p)) {
+			return p, true
+		}
+	}
+	return ScreeningPriorityNormal, false
+}
+
+// Level returns the priority's position in ScreeningPriorities, 0 being
+// the most urgent. Unknown priorities rank as NORMAL.
+func (p ScreeningPriority) Level() int {
+	for i, known := range ScreeningPriorities {
+		if p == known {
+			return i
+		}
+	}
+	return len(ScreeningPriorities) - 1
+}
+
 // TransactionCreatedEvent is the Kafka event received from transaction service
 type TransactionCreatedEvent struct {
-	EventID     uuid.UUID    `json:"event_id"`
-	EventType   string       `json:"event_type"`
-	Timestamp   time.Time    `json:"timestamp"`
-	Transaction *Transaction `json:"payload"`
+	EventID     uuid.UUID         `json:"event_id"`
+	EventType   string            `json:"event_type"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Priority    ScreeningPriority `json:"priority,omitempty"` // NORMAL when empty
+	Transaction *Transaction      `json:"payload"`
 }
 
-// ScreeningRequest represents a request to screen a transaction
+// ScreeningRequest represents a request to screen a transaction. Priority
+// orders queued screenings; a request screened synchronously runs at once.
 type ScreeningRequest struct {
-	Transaction *Transaction `json:"transaction" validate:"required"`
-	RequesterID uuid.UUID    `json:"requester_id"`
-	Priority    string       `json:"priority,omitempty"` // NORMAL, HIGH, URGENT
-	BypassCache bool         `json:"bypass_cache,omitempty"`
+	Transaction *Transaction      `json:"transaction" validate:"required"`
+	RequesterID uuid.UUID         `json:"requester_id"`
+	Priority    ScreeningPriority `json:"priority,omitempty" validate:"omitempty,oneof=NORMAL HIGH URGENT"`
+	BypassCache bool              `json:"bypass_cache,omitempty"`
+
+	// TenantID is the tenant the transaction belongs to. It is taken from
+	// the caller's context; a request naming another tenant is refused.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// NameScreeningRequest screens a prospective customer's name before any
+// transaction exists, e.g. during onboarding
+type NameScreeningRequest struct {
+	Name        string `json:"name" validate:"required"`
+	DateOfBirth string `json:"date_of_birth,omitempty"` // YYYY-MM-DD
+	Country     string `json:"country,omitempty" validate:"omitempty,iso3166_alpha2"`
+}
+
+// NameScreeningResponse combines the OFAC and PEP results for a name.
+// PEPCountryMatch is set when both the request and the PEP match carry a
+// country, to help reviewers discount namesakes.
+type NameScreeningResponse struct {
+	Name            string     `json:"name"`
+	DateOfBirth     string     `json:"date_of_birth,omitempty"`
+	Country         string     `json:"country,omitempty"`
+	Matched         bool       `json:"matched"`
+	OFACMatch       *OFACMatch `json:"ofac_match"`
+	PEPMatch        *PEPMatch  `json:"pep_match"`
+	PEPCountryMatch *bool      `json:"pep_country_match,omitempty"`
+	ScreenedAt      time.Time  `json:"screened_at"`
 }
 
 // ScreeningResponse represents the response from transaction screening
@@ -74,12 +199,22 @@ type ScreeningResponse struct {
 	PEPMatch        bool     `json:"pep_match"`
 	PatternDetected bool     `json:"pattern_detected"`
 	RiskFactors     []string `json:"risk_factors,omitempty"`
+	ReasonCodes     []string `json:"reason_codes,omitempty"`
+	ChecksFailed    []string `json:"checks_failed,omitempty"`
+	SkippedChecks   []string `json:"skipped_checks,omitempty"` // disabled in configuration
+	Degraded        bool     `json:"degraded,omitempty"`
 
 	// Actions
 	InvestigationCreated bool       `json:"investigation_created"`
 	InvestigationID      *uuid.UUID `json:"investigation_id,omitempty"`
 
-	// Errors
+	// Set when the result was replayed from a previous screening
+	IdempotentReplay bool `json:"idempotent_replay,omitempty"`
+
+	// Set when the result was served from the result cache
+	CacheHit bool `json:"cache_hit,omitempty"`
+
+	// Codes of the checks and steps that did not complete (see ScreeningError)
 	Errors []string `json:"errors,omitempty"`
 }
 
@@ -98,9 +233,15 @@ func (r *ScreeningResponse) NeedsReview() bool {
 	return r.Decision == DecisionSuspicious
 }
 
+// IsPending returns true if the decision is held because a critical check
+// could not be completed; the transaction should be held and re-screened
+func (r *ScreeningResponse) IsPending() bool {
+	return r.Decision == DecisionPending
+}
+
 // GetCounterpartyName returns the name of the counterparty
 func (t *Transaction) GetCounterpartyName() string {
-	if t.Direction == "OUTBOUND" {
+	if t.Direction == DirectionOutbound {
 		return t.ReceiverName
 	}
 	return t.SenderName
@@ -108,7 +249,7 @@ func (t *Transaction) GetCounterpartyName() string {
 
 // GetCounterpartyCountry returns the country of the counterparty
 func (t *Transaction) GetCounterpartyCountry() string {
-	if t.Direction == "OUTBOUND" {
+	if t.Direction == DirectionOutbound {
 		return t.ReceiverCountry
 	}
 	return t.SenderCountry
@@ -120,7 +261,64 @@ func (t *Transaction) IsCrossBorder() bool {
 		t.SenderCountry != t.ReceiverCountry
 }
 
-// IsHighValue returns true if transaction amount exceeds threshold
-func (t *Transaction) IsHighValue(threshold float64) bool {
-	return t.Amount >= threshold
+// materialFields is the subset of a transaction that affects screening.
+// Timestamps are left out so a redelivery stamped with a new CreatedAt
+// hashes the same as the original, and fields added later are omitted when
+// empty so they leave the hashes of earlier transactions unchanged.
+type materialFields struct {
+	ID              uuid.UUID    `json:"id"`
+	UserID          uuid.UUID    `json:"user_id"`
+	AccountID       uuid.UUID    `json:"account_id"`
+	Type            string       `json:"type"`
+	Direction       string       `json:"direction"`
+	Amount          money.Amount `json:"amount"`
+	Currency        string       `json:"currency"`
+	SenderName      string       `json:"sender_name"`
+	SenderAccount   string       `json:"sender_account"`
+	SenderCountry   string       `json:"sender_country"`
+	SenderBank      string       `json:"sender_bank"`
+	SenderBankBIC   string       `json:"sender_bank_bic,omitempty"`
+	ReceiverName    string       `json:"receiver_name"`
+	ReceiverAccount string       `json:"receiver_account"`
+	ReceiverCountry string       `json:"receiver_country"`
+	ReceiverBank    string       `json:"receiver_bank"`
+	ReceiverBankBIC string       `json:"receiver_bank_bic,omitempty"`
+	Description     string       `json:"description"`
+	Reference       string       `json:"reference"`
+	Channel         string       `json:"channel"`
+	IPAddress       string       `json:"ip_address"`
+	DeviceID        string       `json:"device_id"`
+	GeoLocation     string       `json:"geo_location"`
+}
+
+// ContentHash returns a hex SHA-256 over the fields that affect screening,
+// so logically identical payloads share a hash
+func (t *Transaction) ContentHash() string {
+	data, _ := json.Marshal(materialFields{
+		ID:              t.ID,
+		UserID:          t.UserID,
+		AccountID:       t.AccountID,
+		Type:            t.Type,
+		Direction:       t.Direction,
+		Amount:          t.Amount,
+		Currency:        t.Currency,
+		SenderName:      t.SenderName,
+		SenderAccount:   t.SenderAccount,
+		SenderCountry:   t.SenderCountry,
+		SenderBank:      t.SenderBank,
+		SenderBankBIC:   t.SenderBankBIC,
+		ReceiverName:    t.ReceiverName,
+		ReceiverAccount: t.ReceiverAccount,
+		ReceiverCountry: t.ReceiverCountry,
+		ReceiverBank:    t.ReceiverBank,
+		ReceiverBankBIC: t.ReceiverBankBIC,
+		Description:     t.Description,
+		Reference:       t.Reference,
+		Channel:         t.Channel,
+		IPAddress:       t.IPAddress,
+		DeviceID:        t.DeviceID,
+		GeoLocation:     t.GeoLocation,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// UserActivity is everything the AML system knows about a user, the
+// backing document of the case-review UI. Every section is always present:
+// lists are empty rather than null, and RiskProfile is null only when the
+// user has not been assessed yet. Since, when set, limits the screenings,
+// patterns, alerts, investigations and filings to those created at or
+// after it.
+type UserActivity struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	Since       *time.Time `json:"since,omitempty"`
+	GeneratedAt time.Time  `json:"generated_at"`
+
+	RiskProfile    *UserRiskSummary     `json:"risk_profile"`
+	Watchlist      UserWatchlistStatus  `json:"watchlist"`
+	Velocity       *VelocityData        `json:"velocity"`
+	Screenings     UserScreeningPage    `json:"screenings"`
+	Patterns       []UserPatternSummary `json:"patterns"`
+	Alerts         UserAlerts           `json:"alerts"`
+	Investigations UserInvestigations   `json:"investigations"`
+	Filings        UserFilings          `json:"filings"`
+}
+
+// UserRiskSummary is the headline of a user's risk profile
+type UserRiskSummary struct {
+	RiskScore      int       `json:"risk_score"`
+	RiskLevel      RiskLevel `json:"risk_level"`
+	LastAssessment time.Time `json:"last_assessment"`
+	NextReviewDate time.Time `json:"next_review_date"`
+
+	CountryRisk      int `json:"country_risk"`
+	OccupationRisk   int `json:"occupation_risk"`
+	TransactionRisk  int `json:"transaction_risk"`
+	BehavioralRisk   int `json:"behavioral_risk"`
+	RelationshipRisk int `json:"relationship_risk"`
+
+	IsPEP             bool     `json:"is_pep"`
+	HasOFACMatch      bool     `json:"has_ofac_match"`
+	HighRiskCountries []string `json:"high_risk_countries"`
+
+	TxCountLast30Days  int `json:"tx_count_last_30_days"`
+	SARCount           int `json:"sar_count"`
+	InvestigationCount int `json:"investigation_count"`
+	BlockedTxCount     int `json:"blocked_tx_count"`
+}
+
+// ToRiskSummary converts UserRiskProfile to UserRiskSummary
+func (r *UserRiskProfile) ToRiskSummary() *UserRiskSummary {
+	highRisk := r.HighRiskCountries
+	if highRisk == nil {
+		highRisk = []string{}
+	}
+	return &UserRiskSummary{
+		RiskScore:          r.RiskScore,
+		RiskLevel:          r.RiskLevel,
+		LastAssessment:     r.LastAssessment,
+		NextReviewDate:     r.NextReviewDate,
+		CountryRisk:        r.CountryRisk,
+		OccupationRisk:     r.OccupationRisk,
+		TransactionRisk:    r.TransactionRisk,
+		BehavioralRisk:     r.BehavioralRisk,
+		RelationshipRisk:   r.RelationshipRisk,
+		IsPEP:              r.IsPEP,
+		HasOFACMatch:       r.HasOFACMatch,
+		HighRiskCountries:  highRisk,
+		TxCountLast30Days:  r.TxCountLast30Days,
+		SARCount:           r.SARCount,
+		InvestigationCount: r.InvestigationCount,
+		BlockedTxCount:     r.BlockedTxCount,
+	}
+}
+
+// UserWatchlistStatus is whether a user is on the internal watchlist
+type UserWatchlistStatus struct {
+	OnWatchlist bool       `json:"on_watchlist"`
+	Reason      string     `json:"reason,omitempty"`
+	AddedAt     *time.Time `json:"added_at,omitempty"`
+}
+
+// UserScreeningPage is a page of a user's screening results, newest first
+type UserScreeningPage struct {
+	Items  []*UserScreening `json:"items"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+// UserScreening is one screening result in a user's activity
+type UserScreening struct {
+	ScreeningID     uuid.UUID         `json:"screening_id"`
+	TransactionID   uuid.UUID         `json:"transaction_id"`
+	Decision        ScreeningDecision `json:"decision"`
+	RiskScore       int               `json:"risk_score"`
+	RiskLevel       RiskLevel         `json:"risk_level"`
+	ReasonCodes     []string          `json:"reason_codes"`
+	Amount          *money.Amount     `json:"amount,omitempty"`
+	Counterparty    string            `json:"counterparty,omitempty"`
+	PatternDetected bool              `json:"pattern_detected"`
+	IsRescreen      bool              `json:"is_rescreen"`
+	ScreenedAt      time.Time         `json:"screened_at"`
+}
+
+// ToUserScreening converts ScreeningResult to UserScreening
+func (s *ScreeningResult) ToUserScreening() *UserScreening {
+	reasonCodes := s.ReasonCodes
+	if reasonCodes == nil {
+		reasonCodes = []string{}
+	}
+	us := &UserScreening{
+		ScreeningID:     s.ID,
+		TransactionID:   s.TransactionID,
+		Decision:        s.Decision,
+		RiskScore:       s.RiskScore,
+		RiskLevel:       s.RiskLevel,
+		ReasonCodes:     reasonCodes,
+		PatternDetected: len(s.PatternMatches) > 0,
+		IsRescreen:      s.RescreenOfID != nil,
+		ScreenedAt:      s.CreatedAt,
+	}
+	if s.Transaction != nil {
+		amount := s.Transaction.Amount
+		us.Amount = &amount
+		us.Counterparty = s.Transaction.GetCounterpartyName()
+	}
+	return us
+}
+
+// UserPatternSummary is how often a pattern type was detected on a user's
+// screenings
+type UserPatternSummary struct {
+	PatternType    PatternType `json:"pattern_type"`
+	Count          int         `json:"count"`
+	MaxConfidence  float64     `json:"max_confidence"`
+	LastDetectedAt time.Time   `json:"last_detected_at"`
+}
+
+// UserAlerts are a user's alerts, newest first, split into those still
+// being worked and those dismissed or resolved. Truncated is set when the
+// user has more alerts than the section holds.
+type UserAlerts struct {
+	Open       []*AlertSummary `json:"open"`
+	Historical []*AlertSummary `json:"historical"`
+	Truncated  bool            `json:"truncated"`
+}
+
+// UserInvestigations are a user's investigations, newest first. Total
+// counts them all when there are more than the section holds.
+type UserInvestigations struct {
+	Items []*InvestigationSummary `json:"items"`
+	Total int                     `json:"total"`
+}
+
+// UserFilings are the regulatory filings on a user, newest first.
+// Truncated is set when there are more than the section holds.
+type UserFilings struct {
+	Items     []*FilingSummary `json:"items"`
+	Truncated bool             `json:"truncated"`
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatchlistRiskUplift is added to a watchlisted user's assessed risk score
+const WatchlistRiskUplift = 20
+
+// WatchlistChangeRequest adds a user to or removes a user from the internal
+// watchlist
+type WatchlistChangeRequest struct {
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+	Reason  string    `json:"reason" validate:"required"`
+}
+
+// Validate checks that the change names an actor and a reason
+func (r *WatchlistChangeRequest) Validate() error {
+	if r.ActorID == uuid.Nil || r.Reason == "" {
+		return fmt.Errorf("%w: actor_id and reason are required", ErrValidation)
+	}
+	return nil
+}
+
+// NewRiskProfile returns an empty profile for a user assessed for the first time
+func NewRiskProfile(userID uuid.UUID, now time.Time) *UserRiskProfile {
+	return &UserRiskProfile{
+		ID:             uuid.New(),
+		UserID:         userID,
+		RiskLevel:      RiskLevelLow,
+		LastAssessment: now,
+		NextReviewDate: now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Reassess recomputes the overall risk score and level from the profile's
+// risk factors and watchlist status
+func (r *UserRiskProfile) Reassess(now time.Time) {
+	score := r.CalculateOverallRisk()
+	if r.OnWatchlist {
+		score = min(score+WatchlistRiskUplift, 100)
+	}
+
+	r.RiskScore = score
+	r.RiskLevel = CalculateRiskLevel(score)
+	r.LastAssessment = now
+	r.UpdatedAt = now
+}
+
+// WatchlistEntry is a watchlisted user in list views
+type WatchlistEntry struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Reason    string     `json:"reason"`
+	AddedAt   *time.Time `json:"added_at,omitempty"`
+	RiskScore int        `json:"risk_score"`
+	RiskLevel RiskLevel  `json:"risk_level"`
+}
+
+// WatchlistListResponse is a paginated list of watchlisted users
+type WatchlistListResponse struct {
+	Entries []*WatchlistEntry `json:"entries"`
+	Total   int               `json:"total"`
+	Limit   int               `json:"limit"`
+	Offset  int               `json:"offset"`
+}
+
+// ToWatchlistEntry converts a watchlisted profile to a list entry
+func (r *UserRiskProfile) ToWatchlistEntry() *WatchlistEntry {
+	return &WatchlistEntry{
+		UserID:    r.UserID,
+		Reason:    r.WatchlistReason,
+		AddedAt:   r.WatchlistAddedAt,
+		RiskScore: r.RiskScore,
+		RiskLevel: r.RiskLevel,
+	}
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventType names an event webhook endpoints subscribe to
+type WebhookEventType string
+
+// Webhook event types. Every screening decision has an event type,
+// "screening." followed by the lower-case decision.
+const (
+	WebhookEventScreeningApproved        WebhookEventType = "screening.approved"
+	WebhookEventScreeningSuspicious      WebhookEventType = "screening.suspicious"
+	WebhookEventScreeningBlocked         WebhookEventType = "screening.blocked"
+	WebhookEventScreeningPending         WebhookEventType = "screening.pending"
+	WebhookEventAlertCreated             WebhookEventType = "alert.created"
+	WebhookEventInvestigationSLABreached WebhookEventType = "investigation.sla_breached"
+	WebhookEventFilingOverdue            WebhookEventType = "filing.overdue"
+)
+
+// AllWebhookEventTypes lists every event type an endpoint may subscribe to
+var AllWebhookEventTypes = []WebhookEventType{
+	WebhookEventScreeningApproved,
+	WebhookEventScreeningSuspicious,
+	WebhookEventScreeningBlocked,
+	WebhookEventScreeningPending,
+	WebhookEventAlertCreated,
+	WebhookEventInvestigationSLABreached,
+	WebhookEventFilingOverdue,
+}
+
+// ScreeningWebhookEvent returns the event type of a screening decision
+func ScreeningWebhookEvent(decision ScreeningDecision) WebhookEventType {
+	return WebhookEventType("screening." + strings.ToLower(string(decision)))
+}
+
+// WebhookEndpoint is a registered receiver of webhook events. Endpoints in
+// the webhooks.subscribers config are synced into the same table at
+// startup and are marked ManagedByConfig; they can only be changed there.
+// An endpoint whose deliveries keep failing is disabled until an operator
+// enables it again. An endpoint receives only its own tenant's events.
+type WebhookEndpoint struct {
+	ID                  uuid.UUID          `json:"id" db:"id"`
+	TenantID            string             `json:"tenant_id" db:"tenant_id"`
+	Name                string             `json:"name" db:"name"`
+	URL                 string             `json:"url" db:"url"`
+	Secret              string             `json:"-" db:"secret"` // encrypted at rest
+	EventTypes          []WebhookEventType `json:"event_types" db:"event_types"`
+	Enabled             bool               `json:"enabled" db:"enabled"`
+	ManagedByConfig     bool               `json:"managed_by_config" db:"managed_by_config"`
+	ConsecutiveFailures int                `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          *time.Time         `json:"disabled_at,omitempty" db:"disabled_at"`
+	DisabledReason      string             `json:"disabled_reason,omitempty" db:"disabled_reason"`
+	CreatedBy           uuid.UUID          `json:"created_by" db:"created_by"`
+	CreatedAt           time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint receives events of a type
+func (e *WebhookEndpoint) Subscribes(t WebhookEventType) bool {
+	return slices.Contains(e.EventTypes, t)
+}
+
+// WebhookEvent is an event sent to webhook endpoints. It is stored so a
+// missed callback can be replayed; ID is the X-AML-Event-ID every delivery
+// of it carries, replays included.
+type WebhookEvent struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	TenantID  string           `json:"tenant_id" db:"tenant_id"`
+	Type      WebhookEventType `json:"event_type" db:"event_type"`
+	Payload   json.RawMessage  `json:"payload" db:"payload"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// Webhook delivery attempt statuses
+const (
+	WebhookDeliveryDelivered = "DELIVERED"
+	WebhookDeliveryFailed    = "FAILED"
+)
+
+// WebhookDelivery records one attempt to deliver an event to an endpoint
+type WebhookDelivery struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	EventID    uuid.UUID        `json:"event_id" db:"event_id"`
+	EventType  WebhookEventType `json:"event_type" db:"event_type"`
+	EndpointID uuid.UUID        `json:"endpoint_id" db:"endpoint_id"`
+	Attempt    int              `json:"attempt" db:"attempt"`
+	Replay     bool             `json:"replay" db:"replay"`
+	Status     string           `json:"status" db:"status"`
+	StatusCode int              `json:"status_code,omitempty" db:"status_code"`
+	LatencyMs  int64            `json:"latency_ms" db:"latency_ms"`
+	Error      string           `json:"error,omitempty" db:"error"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+}
+
+// WebhookDeadLetter records an event that could not be delivered to a
+// webhook endpoint after every retry, so it can be investigated and
+// replayed. The screening fields are set for screening events only.
+type WebhookDeadLetter struct {
+	ID            uuid.UUID         `json:"id" db:"id"`
+	EventID       uuid.UUID         `json:"event_id" db:"event_id"`
+	EventType     WebhookEventType  `json:"event_type" db:"event_type"`
+	EndpointID    uuid.UUID         `json:"endpoint_id" db:"endpoint_id"`
+	Subscriber    string            `json:"subscriber" db:"subscriber"`
+	URL           string            `json:"url" db:"url"`
+	ScreeningID   *uuid.UUID        `json:"screening_id,omitempty" db:"screening_id"`
+	TransactionID *uuid.UUID        `json:"transaction_id,omitempty" db:"transaction_id"`
+	Decision      ScreeningDecision `json:"decision,omitempty" db:"decision"`
+	Payload       json.RawMessage   `json:"payload" db:"payload"`
+	Attempts      int               `json:"attempts" db:"attempts"`
+	LastError     string            `json:"last_error" db:"last_error"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+}
+
+// CreateWebhookEndpointRequest registers a webhook endpoint
+type CreateWebhookEndpointRequest struct {
+	Name       string             `json:"name" validate:"required,max=100"`
+	URL        string             `json:"url" validate:"required"`
+	Secret     string             `json:"secret" validate:"required,min=16"`
+	EventTypes []WebhookEventType `json:"event_types" validate:"required,min=1,dive,oneof=screening.approved screening.suspicious screening.blocked screening.pending alert.created investigation.sla_breached filing.overdue"`
+	ActorID    uuid.UUID          `json:"actor_id" validate:"required"`
+}
+
+// WebhookEndpointChangeRequest deletes or re-enables a webhook endpoint
+type WebhookEndpointChangeRequest struct {
+	ActorID uuid.UUID `json:"actor_id" validate:"required"`
+	Reason  string    `json:"reason" validate:"required"`
+}
+
+// ReplayWebhookEventRequest re-sends a stored event, to one endpoint or to
+// every enabled endpoint subscribed to its type
+type ReplayWebhookEventRequest struct {
+	EndpointID *uuid.UUID `json:"endpoint_id,omitempty" validate:"omitempty,uuid_not_nil"`
+	ActorID    uuid.UUID  `json:"actor_id" validate:"required"`
+	Reason     string     `json:"reason" validate:"required"`
+}
+
+// ReplayWebhookEventResponse lists the endpoints a replay was queued for
+type ReplayWebhookEventResponse struct {
+	EventID     uuid.UUID   `json:"event_id"`
+	EndpointIDs []uuid.UUID `json:"endpoint_ids"`
+}
+
+// WebhookEndpointListResponse lists the registered webhook endpoints
+type WebhookEndpointListResponse struct {
+	Endpoints []*WebhookEndpoint `json:"endpoints"`
+	Total     int                `json:"total"`
+}
+
+// WebhookDeliveryListResponse is a page of delivery attempts, newest first
+type WebhookDeliveryListResponse struct {
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+}
@@ -0,0 +1,146 @@
+package email
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Digest is an analyst's morning summary of open work. Each section holds
+// at most the configured number of items; the More fields are set when
+// there were others.
+type Digest struct {
+	AnalystName string
+	Date        time.Time // the day the digest is for, in the digest time zone
+
+	Investigations     []DigestInvestigation
+	MoreInvestigations bool
+	Alerts             []DigestAlert
+	MoreAlerts         bool
+	Filings            []DigestFiling
+	MoreFilings        bool
+}
+
+// DigestInvestigation is an open investigation assigned to the analyst
+type DigestInvestigation struct {
+	CaseNumber string
+	Title      string
+	Priority   string
+	Status     string
+	DueDate    time.Time
+	DueToday   bool
+	Breached   bool
+	Link       string
+}
+
+// DigestAlert is a new alert matching the analyst's alert filter
+type DigestAlert struct {
+	AlertNumber string
+	Title       string
+	AlertType   string
+	Priority    string
+	RiskScore   int
+	Link        string
+}
+
+// DigestFiling is a filing awaiting the analyst's review
+type DigestFiling struct {
+	FilingNumber string
+	FilingType   string
+	DueDate      time.Time
+	Link         string
+}
+
+// Empty reports whether there is nothing to tell the analyst
+func (d *Digest) Empty() bool {
+	return len(d.Investigations) == 0 && len(d.Alerts) == 0 && len(d.Filings) == 0
+}
+
+// Attention counts the investigations due today or already breached
+func (d *Digest) Attention() int {
+	n := 0
+	for _, inv := range d.Investigations {
+		if inv.DueToday || inv.Breached {
+			n++
+		}
+	}
+	return n
+}
+
+var digestPage = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("Mon 2 Jan 2006") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>AML work digest</title></head>
+<body style="font-family: Arial, sans-serif; font-size: 14px; color: #222;">
+<p>Good morning {{.AnalystName}}, here is your open work for {{date .Date}}.</p>
+
+<h2 style="font-size: 16px;">Your open investigations</h2>
+{{- if .Investigations}}
+<table cellpadding="6" cellspacing="0" style="border-collapse: collapse;">
+<tr style="text-align: left; background: #eee;"><th>Case</th><th>Title</th><th>Priority</th><th>Status</th><th>Due</th><th></th></tr>
+{{- range .Investigations}}
+<tr{{if .Breached}} style="background: #fde2e2;"{{else if .DueToday}} style="background: #fff4d6;"{{end}}>
+<td>{{if .Link}}<a href="{{.Link}}">{{.CaseNumber}}</a>{{else}}{{.CaseNumber}}{{end}}</td>
+<td>{{.Title}}</td><td>{{.Priority}}</td><td>{{.Status}}</td><td>{{date .DueDate}}</td>
+<td>{{if .Breached}}<strong style="color: #b00020;">SLA breached</strong>{{else if .DueToday}}<strong style="color: #8a5a00;">Due today</strong>{{end}}</td>
+</tr>
+{{- end}}
+</table>
+{{- if .MoreInvestigations}}<p>More investigations are assigned to you; see the case manager for the full list.</p>{{end}}
+{{- else}}
+<p>No open investigations are assigned to you.</p>
+{{- end}}
+
+<h2 style="font-size: 16px;">New alerts</h2>
+{{- if .Alerts}}
+<table cellpadding="6" cellspacing="0" style="border-collapse: collapse;">
+<tr style="text-align: left; background: #eee;"><th>Alert</th><th>Title</th><th>Type</th><th>Priority</th><th>Risk score</th></tr>
+{{- range .Alerts}}
+<tr>
+<td>{{if .Link}}<a href="{{.Link}}">{{.AlertNumber}}</a>{{else}}{{.AlertNumber}}{{end}}</td>
+<td>{{.Title}}</td><td>{{.AlertType}}</td><td>{{.Priority}}</td><td>{{.RiskScore}}</td>
+</tr>
+{{- end}}
+</table>
+{{- if .MoreAlerts}}<p>More new alerts match your filter; see the case manager for the full list.</p>{{end}}
+{{- else}}
+<p>No new alerts match your filter.</p>
+{{- end}}
+
+<h2 style="font-size: 16px;">Filings awaiting your review</h2>
+{{- if .Filings}}
+<table cellpadding="6" cellspacing="0" style="border-collapse: collapse;">
+<tr style="text-align: left; background: #eee;"><th>Filing</th><th>Type</th><th>Due</th></tr>
+{{- range .Filings}}
+<tr>
+<td>{{if .Link}}<a href="{{.Link}}">{{.FilingNumber}}</a>{{else}}{{.FilingNumber}}{{end}}</td>
+<td>{{.FilingType}}</td><td>{{date .DueDate}}</td>
+</tr>
+{{- end}}
+</table>
+{{- if .MoreFilings}}<p>More filings await review; see the case manager for the full list.</p>{{end}}
+{{- else}}
+<p>No filings await your review.</p>
+{{- end}}
+
+<p style="color: #777; font-size: 12px;">You receive this digest as a registered analyst. To unsubscribe, turn off the digest in your analyst preferences.</p>
+</body>
+</html>
+`))
+
+// RenderDigest renders a digest as an email to the given address
+func RenderDigest(to string, d *Digest) (*Message, error) {
+	var html strings.Builder
+	if err := digestPage.Execute(&html, d); err != nil {
+		return nil, fmt.Errorf("render digest: %w", err)
+	}
+
+	subject := "AML work digest for " + d.Date.Format("Mon 2 Jan")
+	if n := d.Attention(); n > 0 {
+		subject += fmt.Sprintf(": %d investigation(s) due today or breached", n)
+	}
+
+	return &Message{To: []string{to}, Subject: subject, HTML: html.String()}, nil
+}
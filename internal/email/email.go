@@ -0,0 +1,42 @@
+// Package email sends HTML emails, such as the analysts' morning digest,
+// through an SMTP server, or logs them instead in dry-run mode.
+package email
+
+import (
+	"context"
+	"strings"
+
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// Message is an HTML email
+type Message struct {
+	To      []string
+	Subject string
+	HTML    string
+}
+
+// Sender delivers emails
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// LogSender logs emails instead of sending them, for dry runs
+type LogSender struct {
+	log *logger.Logger
+}
+
+// NewLogSender creates a sender that only logs
+func NewLogSender(log *logger.Logger) *LogSender {
+	return &LogSender{log: log.Named("email_dry_run")}
+}
+
+// Send logs the rendered email
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	s.log.Info("email not sent, dry run",
+		logger.StringField("to", strings.Join(msg.To, ", ")),
+		logger.StringField("subject", msg.Subject),
+		logger.StringField("html", msg.HTML),
+	)
+	return nil
+}
@@ -0,0 +1,138 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+)
+
+// SMTPSender sends emails through an SMTP server
+type SMTPSender struct {
+	cfg  *config.SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTPSender creates a sender for the configured server, sending from
+// the given address
+func NewSMTPSender(cfg *config.SMTPConfig, from string) (*SMTPSender, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("parse from address: %w", err)
+	}
+	return &SMTPSender{cfg: cfg, from: addr}, nil
+}
+
+// Send delivers msg over a new connection, bounded by the configured
+// timeout
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	recipients := make([]*mail.Address, len(msg.To))
+	for i, to := range msg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("parse recipient %q: %w", to, err)
+		}
+		recipients[i] = addr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, r := range recipients {
+		if err := client.Rcpt(r.Address); err != nil {
+			return fmt.Errorf("smtp rcpt to %s: %w", r.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(s.compose(msg, recipients)); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial connects to the server and, unless TLS is off, secures the
+// connection either from the start or with STARTTLS
+func (s *SMTPSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	var err error
+	if s.cfg.TLS == "tls" {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("smtp handshake: %w", err)
+	}
+	if s.cfg.TLS == "starttls" {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// compose builds the MIME message with a quoted-printable HTML body
+func (s *SMTPSender) compose(msg *Message, recipients []*mail.Address) []byte {
+	to := make([]string, len(recipients))
+	for i, r := range recipients {
+		to[i] = r.String()
+	}
+
+	var b bytes.Buffer
+	b.WriteString("From: " + s.from.String() + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	qp.Write([]byte(msg.HTML))
+	qp.Close()
+	return b.Bytes()
+}
@@ -0,0 +1,312 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+)
+
+// Deduper claims dedup keys so a message is sent once even when several
+// instances see the same event
+type Deduper interface {
+	// Claim reports whether key was free and is now held for ttl
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// delivery is a message queued for one channel
+type delivery struct {
+	msg     *Message
+	channel Channel
+}
+
+// Dispatcher routes messages to chat channels by event. Sending happens on
+// background workers with bounded retries, so a chat outage never slows
+// screening or the SLA monitor; a message that cannot be queued or sent is
+// logged and dropped.
+type Dispatcher struct {
+	routes map[Event][]Channel
+	quiet  *quietHours
+	dedup  Deduper
+	queue  chan *delivery
+	cfg    *config.NotificationsConfig
+	log    *logger.Logger
+}
+
+// NewDispatcher creates a dispatcher for the configured channels and routes
+func NewDispatcher(dedup Deduper, cfg *config.NotificationsConfig, log *logger.Logger) (*Dispatcher, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	channels := make(map[string]Channel, len(cfg.Channels))
+	for _, c := range cfg.Channels {
+		switch c.Type {
+		case "slack":
+			channels[c.Name] = NewSlackChannel(c.Name, c.WebhookURL, client)
+		case "teams":
+			channels[c.Name] = NewTeamsChannel(c.Name, c.WebhookURL, client)
+		default:
+			return nil, fmt.Errorf("notification channel %s: unknown type %q", c.Name, c.Type)
+		}
+	}
+
+	routes := make(map[Event][]Channel, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		for _, name := range r.Channels {
+			ch, ok := channels[name]
+			if !ok {
+				return nil, fmt.Errorf("notification route %s: unknown channel %q", r.Event, name)
+			}
+			routes[Event(r.Event)] = append(routes[Event(r.Event)], ch)
+		}
+	}
+
+	quiet, err := parseQuietHours(&cfg.QuietHours)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dispatcher{
+		routes: routes,
+		quiet:  quiet,
+		dedup:  dedup,
+		queue:  make(chan *delivery, cfg.QueueSize),
+		cfg:    cfg,
+		log:    log.Named("notify"),
+	}, nil
+}
+
+// Notify pages on-call staff when a screening blocked a transaction on an
+// exact OFAC match
+func (d *Dispatcher) Notify(ctx context.Context, result *domain.ScreeningResult) {
+	m := result.OFACMatch
+	if result.Decision != domain.DecisionBlocked || m == nil || !m.Matched || m.MatchType != domain.MatchTypeExact {
+		return
+	}
+
+	d.send(&Message{
+		Event: EventOFACBlocked,
+		Title: "Transaction blocked on exact OFAC match",
+		Facts: []Fact{
+			{Name: "Transaction", Value: result.TransactionID.String()},
+			{Name: "Risk score", Value: strconv.Itoa(result.RiskScore)},
+			{Name: "Reason codes", Value: reasonCodes(result.ReasonCodes)},
+			{Name: "SDN", Value: m.SDNName},
+			{Name: "Program", Value: m.Program},
+		},
+		Link:     d.link("screening", result.ID.String()),
+		DedupKey: string(EventOFACBlocked) + ":" + result.TransactionID.String(),
+	})
+}
+
+// NotifySLABreach pages on-call staff when an investigation breaches its
+// SLA. alert is the system alert raised for the breach.
+func (d *Dispatcher) NotifySLABreach(ctx context.Context, inv *domain.Investigation, alert *domain.AMLAlert) {
+	d.send(&Message{
+		Event: EventSLABreached,
+		Title: fmt.Sprintf("Investigation %s breached SLA", inv.CaseNumber),
+		Facts: []Fact{
+			{Name: "Case", Value: inv.CaseNumber},
+			{Name: "Alert", Value: alert.AlertNumber},
+			{Name: "Risk score", Value: strconv.Itoa(inv.RiskScore)},
+			{Name: "Reason codes", Value: alert.DetectionRule},
+			{Name: "Priority", Value: string(inv.Priority)},
+			{Name: "Due", Value: inv.DueDate.UTC().Format(time.RFC3339)},
+		},
+		Link:     d.link("investigation", inv.ID.String()),
+		DedupKey: string(EventSLABreached) + ":" + inv.ID.String(),
+	})
+}
+
+// NotifySLAAtRisk tells on-call staff an unassigned investigation is close
+// to breaching its SLA
+func (d *Dispatcher) NotifySLAAtRisk(ctx context.Context, inv *domain.Investigation) {
+	d.send(&Message{
+		Event: EventSLAAtRisk,
+		Title: fmt.Sprintf("Investigation %s is at risk of breaching SLA", inv.CaseNumber),
+		Facts: []Fact{
+			{Name: "Case", Value: inv.CaseNumber},
+			{Name: "Risk score", Value: strconv.Itoa(inv.RiskScore)},
+			{Name: "Reason codes", Value: "INVESTIGATION_SLA_AT_RISK"},
+			{Name: "Priority", Value: string(inv.Priority)},
+			{Name: "Due", Value: inv.DueDate.UTC().Format(time.RFC3339)},
+		},
+		Link:     d.link("investigation", inv.ID.String()),
+		DedupKey: string(EventSLAAtRisk) + ":" + inv.ID.String(),
+	})
+}
+
+// send queues msg for each channel routed for its event without blocking
+func (d *Dispatcher) send(msg *Message) {
+	channels := d.routes[msg.Event]
+	if len(channels) == 0 {
+		return
+	}
+
+	if !msg.Event.Critical() && d.quiet.contains(time.Now()) {
+		metrics.RecordNotification(string(msg.Event), "quiet_hours")
+		d.log.Debug("notification suppressed during quiet hours", logger.StringField("event", string(msg.Event)))
+		return
+	}
+
+	for _, ch := range channels {
+		select {
+		case d.queue <- &delivery{msg: msg, channel: ch}:
+		default:
+			metrics.RecordNotification(string(msg.Event), "dropped")
+			d.log.Warn("notification queue full, dropping message",
+				logger.StringField("event", string(msg.Event)),
+				logger.StringField("channel", ch.Name()),
+			)
+		}
+	}
+}
+
+// Run sends queued messages until ctx is cancelled. Messages still queued
+// at shutdown are dropped; the events they describe are recorded elsewhere.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.log.Info("notification dispatcher started",
+		logger.IntField("workers", d.cfg.Workers),
+		logger.IntField("routes", len(d.routes)),
+	)
+
+	var wg sync.WaitGroup
+	for range d.cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case dl := <-d.queue:
+					d.deliver(ctx, dl)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(d.queue); n > 0 {
+		d.log.Warn("notification dispatcher stopped with messages queued", logger.IntField("dropped", n))
+		return
+	}
+	d.log.Info("notification dispatcher stopped")
+}
+
+// deliver sends a message to one channel, retrying transient failures with
+// exponential backoff. A message already sent to the channel within the
+// dedup window is skipped; when the dedup store is unreachable the message
+// is sent anyway, since a duplicate page beats a missed one.
+func (d *Dispatcher) deliver(ctx context.Context, dl *delivery) {
+	event := string(dl.msg.Event)
+
+	claimed, err := d.dedup.Claim(ctx, dl.msg.DedupKey+":"+dl.channel.Name(), d.cfg.DedupWindow)
+	if err != nil {
+		d.log.Warn("notification dedup unavailable, sending anyway",
+			logger.StringField("event", event),
+			logger.StringField("channel", dl.channel.Name()),
+			logger.ErrorField(err),
+		)
+	} else if !claimed {
+		metrics.RecordNotification(event, "duplicate")
+		return
+	}
+
+	backoff := d.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := dl.channel.Send(ctx, dl.msg)
+		if err == nil {
+			metrics.RecordNotification(event, "sent")
+			return
+		}
+		if !retryable(err) || attempt == d.cfg.MaxAttempts {
+			metrics.RecordNotification(event, "failed")
+			d.log.Error("failed to send notification",
+				logger.StringField("event", event),
+				logger.StringField("channel", dl.channel.Name()),
+				logger.IntField("attempts", attempt),
+				logger.ErrorField(err),
+			)
+			return
+		}
+
+		metrics.RecordNotification(event, "retried")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// link fills the configured link template
+func (d *Dispatcher) link(kind, id string) string {
+	if d.cfg.LinkTemplate == "" {
+		return ""
+	}
+	return strings.NewReplacer("{kind}", kind, "{id}", id).Replace(d.cfg.LinkTemplate)
+}
+
+func reasonCodes(codes []string) string {
+	if len(codes) == 0 {
+		return "-"
+	}
+	return strings.Join(codes, ", ")
+}
+
+// quietHours is a daily window in a time zone. A nil window contains no
+// time.
+type quietHours struct {
+	start, end int // minutes after midnight
+	loc        *time.Location
+}
+
+func parseQuietHours(cfg *config.QuietHoursConfig) (*quietHours, error) {
+	if cfg.Start == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("quiet hours timezone: %w", err)
+	}
+	start, err := parseClock(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("quiet hours start: %w", err)
+	}
+	end, err := parseClock(cfg.End)
+	if err != nil {
+		return nil, fmt.Errorf("quiet hours end: %w", err)
+	}
+	return &quietHours{start: start, end: end, loc: loc}, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether t falls in the window, which may span midnight
+func (q *quietHours) contains(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	local := t.In(q.loc)
+	m := local.Hour()*60 + local.Minute()
+	if q.start <= q.end {
+		return m >= q.start && m < q.end
+	}
+	return m >= q.start || m < q.end
+}
@@ -0,0 +1,107 @@
+// Package notify pages on-call compliance staff in chat, through Slack or
+// Microsoft Teams incoming webhooks, when a transaction is blocked on an
+// exact sanctions hit or an investigation is about to breach or has
+// breached its SLA.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Event names what a notification is about. Routes in the notifications
+// config map events to channels.
+type Event string
+
+const (
+	// EventOFACBlocked is a transaction blocked on an exact OFAC match
+	EventOFACBlocked Event = "screening.ofac_blocked"
+	// EventSLABreached is an investigation past its SLA due date
+	EventSLABreached Event = "investigation.sla_breached"
+	// EventSLAAtRisk is an unassigned investigation most of the way to its
+	// SLA due date
+	EventSLAAtRisk Event = "investigation.sla_at_risk"
+)
+
+// Critical reports whether an event is sent even during quiet hours
+func (e Event) Critical() bool {
+	return e != EventSLAAtRisk
+}
+
+// Fact is one labelled value shown in a message
+type Fact struct {
+	Name  string
+	Value string
+}
+
+// Message is a chat notification. Channels render the title, the facts in
+// order and a button opening Link.
+type Message struct {
+	Event Event
+	Title string
+	Facts []Fact
+	Link  string // empty when no link template is configured
+
+	// DedupKey identifies what the message is about; a message is sent at
+	// most once per key within the dedup window
+	DedupKey string
+}
+
+// Channel delivers messages to a chat tool
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) error
+}
+
+// maxResponseBody caps how much of a chat webhook's response is read
+const maxResponseBody = 4 << 10
+
+// statusError is a non-2xx response from a chat webhook
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return "chat webhook returned " + e.status
+}
+
+// retryable reports whether a send that failed with err is worth retrying:
+// network errors, timeouts, 429 and 5xx responses
+func retryable(err error) bool {
+	se, ok := err.(*statusError)
+	if !ok {
+		return true
+	}
+	return se.code == http.StatusTooManyRequests || se.code >= 500
+}
+
+// postJSON POSTs payload to a chat webhook
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &statusError{code: resp.StatusCode, status: resp.Status}
+	}
+	return nil
+}
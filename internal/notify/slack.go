@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// SlackChannel posts messages to a Slack incoming webhook as Block Kit
+// messages
+type SlackChannel struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewSlackChannel creates a Slack channel posting to webhookURL
+func NewSlackChannel(name, webhookURL string, client *http.Client) *SlackChannel {
+	return &SlackChannel{name: name, url: webhookURL, client: client}
+}
+
+// Name returns the channel's configured name
+func (c *SlackChannel) Name() string {
+	return c.name
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"` // shown in notifications and clients without blocks
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// Send posts a message
+func (c *SlackChannel) Send(ctx context.Context, msg *Message) error {
+	blocks := []slackBlock{{Type: "header", Text: &slackText{Type: "plain_text", Text: msg.Title}}}
+
+	// Slack allows at most 10 fields per section
+	for i := 0; i < len(msg.Facts); i += 10 {
+		section := slackBlock{Type: "section"}
+		for _, f := range msg.Facts[i:min(i+10, len(msg.Facts))] {
+			section.Fields = append(section.Fields, slackText{Type: "mrkdwn", Text: "*" + slackEscape(f.Name) + "*\n" + slackEscape(f.Value)})
+		}
+		blocks = append(blocks, section)
+	}
+
+	if msg.Link != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "<" + msg.Link + "|Open in case manager>"}})
+	}
+
+	return postJSON(ctx, c.client, c.url, &slackMessage{Text: msg.Title, Blocks: blocks})
+}
+
+// slackEscape escapes the characters Slack treats as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// TeamsChannel posts messages to a Microsoft Teams incoming webhook as
+// Adaptive Cards
+type TeamsChannel struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewTeamsChannel creates a Teams channel posting to webhookURL
+func NewTeamsChannel(name, webhookURL string, client *http.Client) *TeamsChannel {
+	return &TeamsChannel{name: name, url: webhookURL, client: client}
+}
+
+// Name returns the channel's configured name
+func (c *TeamsChannel) Name() string {
+	return c.name
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type teamsElement struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	Weight string      `json:"weight,omitempty"`
+	Size   string      `json:"size,omitempty"`
+	Color  string      `json:"color,omitempty"`
+	Wrap   bool        `json:"wrap,omitempty"`
+	Facts  []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type teamsCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+	Actions []teamsAction  `json:"actions,omitempty"`
+}
+
+type teamsAttachment struct {
+	ContentType string     `json:"contentType"`
+	Content     *teamsCard `json:"content"`
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// Send posts a message
+func (c *TeamsChannel) Send(ctx context.Context, msg *Message) error {
+	title := teamsElement{Type: "TextBlock", Text: msg.Title, Weight: "Bolder", Size: "Medium", Wrap: true}
+	if msg.Event.Critical() {
+		title.Color = "Attention"
+	}
+
+	facts := make([]teamsFact, len(msg.Facts))
+	for i, f := range msg.Facts {
+		facts[i] = teamsFact{Title: f.Name, Value: f.Value}
+	}
+
+	card := &teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    []teamsElement{title, {Type: "FactSet", Facts: facts}},
+	}
+	if msg.Link != "" {
+		card.Actions = []teamsAction{{Type: "Action.OpenUrl", Title: "Open in case manager", URL: msg.Link}}
+	}
+
+	return postJSON(ctx, c.client, c.url, &teamsMessage{
+		Type:        "message",
+		Attachments: []teamsAttachment{{ContentType: "application/vnd.microsoft.card.adaptive", Content: card}},
+	})
+}
@@ -0,0 +1,188 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// velocityBucketDays is how many days of daily buckets the velocity cache
+// keeps; its derived baseline covers at most these days before today
+const velocityBucketDays = 30
+
+// VelocityReader returns a user's current velocity and baseline
+type VelocityReader interface {
+	GetVelocity(ctx context.Context, userID uuid.UUID) (*domain.VelocityData, error)
+}
+
+// DormantReactivationDetector flags an account that suddenly moves money
+// after a long silence: a transaction after at least the dormancy period
+// without any, above the user's average before they went quiet or above an
+// absolute floor. Amounts are compared in the base currency.
+//
+// A velocity baseline showing activity inside the dormancy period rules
+// the pattern out without reading history. Users with no transactions in
+// the lookback window are treated as new rather than dormant.
+type DormantReactivationDetector struct {
+	history        TransactionRepository
+	velocity       VelocityReader
+	converter      CurrencyConverter
+	clock          clock.Clock
+	dormancy       time.Duration
+	lookback       time.Duration
+	floor          money.Amount  // in the base currency
+	baselineWindow time.Duration // longest period a velocity baseline covers
+}
+
+// NewDormantReactivationDetector creates a dormant-account reactivation detector
+func NewDormantReactivationDetector(history TransactionRepository, velocity VelocityReader, converter CurrencyConverter, clk clock.Clock, cfg *config.PatternsConfig) *DormantReactivationDetector {
+	return &DormantReactivationDetector{
+		history:        history,
+		velocity:       velocity,
+		converter:      converter,
+		clock:          clk,
+		dormancy:       time.Duration(cfg.DormancyDays) * 24 * time.Hour,
+		lookback:       time.Duration(cfg.DormancyLookbackDays) * 24 * time.Hour,
+		floor:          money.FromFloat(cfg.DormantAmountFloor),
+		baselineWindow: time.Duration(max(cfg.VelocityBaselineDays, velocityBucketDays)) * 24 * time.Hour,
+	}
+}
+
+// Name returns the detector name
+func (d *DormantReactivationDetector) Name() string {
+	return "dormant_reactivation"
+}
+
+// Detect checks whether the transaction ends a dormancy period with an
+// amount above the user's earlier average or the floor
+func (d *DormantReactivationDetector) Detect(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error) {
+	if tx.Amount <= 0 {
+		return nil, nil
+	}
+	amount, ok := d.converter.Convert(tx.Amount, tx.Currency)
+	if !ok {
+		return nil, nil
+	}
+
+	now := d.clock.Now()
+	at := initiatedAt(tx, d.clock)
+
+	if d.recentlyActive(ctx, userID, at, now) {
+		return nil, nil
+	}
+
+	// Activity inside the dormancy period is the common case and rules the
+	// pattern out; only then is the longer lookback read
+	recent, err := d.history.GetByUser(ctx, userID, at.Add(-d.dormancy))
+	if err != nil {
+		return nil, fmt.Errorf("list user transactions: %w", err)
+	}
+	for _, h := range upTo(recent, at) {
+		if h.ID != tx.ID {
+			return nil, nil
+		}
+	}
+
+	history, err := d.history.GetInWindow(ctx, domain.TransactionWindow{
+		UserIDs: []uuid.UUID{userID},
+		From:    at.Add(-d.lookback),
+		To:      at.Add(-d.dormancy),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list user transactions: %w", err)
+	}
+
+	var last time.Time
+	var total money.Amount
+	counted := 0
+	for _, h := range history {
+		if h.ID == tx.ID {
+			continue
+		}
+		if h.InitiatedAt.After(last) {
+			last = h.InitiatedAt
+		}
+		if converted, ok := d.converter.Convert(h.Amount, h.Currency); ok && h.Amount > 0 {
+			total += converted
+			counted++
+		}
+	}
+	if last.IsZero() {
+		return nil, nil
+	}
+	idle := at.Sub(last)
+	if idle < d.dormancy {
+		return nil, nil
+	}
+
+	var average money.Amount
+	if counted > 0 {
+		average = total / money.Amount(counted)
+	}
+	aboveAverage := counted > 0 && amount > average
+	if !aboveAverage && amount < d.floor {
+		return nil, nil
+	}
+
+	// The reference the amount is measured against: the average it beats,
+	// or the floor when it only reaches that
+	reference := d.floor
+	if aboveAverage {
+		reference = average
+	}
+
+	base := d.converter.Base()
+	description := fmt.Sprintf("First transaction in %d days: %s %s", int(idle.Hours()/24), amount.StringFixed(2), base)
+	if aboveAverage {
+		description += fmt.Sprintf(", above the average of %s %s over %d earlier transactions", average.StringFixed(2), base, counted)
+	}
+	if amount >= d.floor {
+		description += fmt.Sprintf(", at or above the %s %s floor", d.floor.StringFixed(2), base)
+	}
+
+	return []domain.PatternMatch{{
+		PatternType:  domain.PatternDormantReactivation,
+		Confidence:   d.confidence(idle, amount, reference),
+		Description:  description,
+		RelatedTxIDs: []uuid.UUID{tx.ID},
+		DetectedAt:   now,
+	}}, nil
+}
+
+// recentlyActive reports whether the user's velocity baseline shows
+// transactions inside the dormancy period. The baseline covers the days
+// before today, so the transaction being screened is never counted, and
+// says nothing about a transaction from an earlier day being rescreened. A
+// velocity cache failure is not an error; history decides instead.
+func (d *DormantReactivationDetector) recentlyActive(ctx context.Context, userID uuid.UUID, at, now time.Time) bool {
+	if d.baselineWindow > d.dormancy || now.Sub(at) >= 24*time.Hour {
+		return false
+	}
+	velocity, err := d.velocity.GetVelocity(ctx, userID)
+	if err != nil || velocity == nil {
+		return false
+	}
+	return velocity.BaselineDays > 0 && velocity.AvgDailyTxCount > 0
+}
+
+// confidence grows with how long the account was idle beyond the dormancy
+// period, reaching its share at four times the period, and with how far
+// the amount exceeds its reference, reaching its share at 16 times
+func (d *DormantReactivationDetector) confidence(idle time.Duration, amount, reference money.Amount) float64 {
+	idleScore := math.Min(1, float64(idle-d.dormancy)/float64(3*d.dormancy))
+
+	amountScore := 1.0
+	if reference > 0 {
+		amountScore = math.Min(1, math.Max(0, math.Log2(amount.Float64()/reference.Float64())/4))
+	}
+
+	return math.Round((0.4+0.3*idleScore+0.25*amountScore)*100) / 100
+}
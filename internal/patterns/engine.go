@@ -0,0 +1,60 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// Detector detects one money laundering pattern for a transaction
+type Detector interface {
+	Name() string
+	Detect(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error)
+}
+
+// Engine runs registered pattern detectors against a transaction
+type Engine struct {
+	detectors []Detector
+	log       *logger.Logger
+}
+
+// NewEngine creates a new pattern engine with the given detectors
+func NewEngine(log *logger.Logger, detectors ...Detector) *Engine {
+	return &Engine{
+		detectors: detectors,
+		log:       log.Named("pattern_engine"),
+	}
+}
+
+// Register adds a detector to the engine
+func (e *Engine) Register(d Detector) {
+	e.detectors = append(e.detectors, d)
+}
+
+// DetectPatterns runs every detector and returns all matches. A failing
+// detector is skipped so the others still run; the matches found are
+// returned together with the joined errors of the detectors that failed.
+func (e *Engine) DetectPatterns(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error) {
+	var matches []domain.PatternMatch
+	var errs []error
+
+	for _, d := range e.detectors {
+		found, err := d.Detect(ctx, userID, tx)
+		if err != nil {
+			e.log.Warn("pattern detector failed",
+				logger.StringField("detector", d.Name()),
+				logger.ErrorField(err),
+			)
+			errs = append(errs, fmt.Errorf("%s detector: %w", d.Name(), err))
+			continue
+		}
+		matches = append(matches, found...)
+	}
+
+	return matches, errors.Join(errs...)
+}
@@ -0,0 +1,51 @@
+package patterns
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+)
+
+// TransactionRepository reads the history of screened transactions by when
+// they were initiated. A transaction screened more than once is returned
+// once, and transactions are returned oldest first.
+type TransactionRepository interface {
+	// GetByUser returns a user's transactions initiated since the given time
+	GetByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.Transaction, error)
+
+	// GetInWindow returns the transactions of the window's users initiated
+	// within it
+	GetInWindow(ctx context.Context, window domain.TransactionWindow) ([]*domain.Transaction, error)
+
+	// GetRelated returns the transactions in the given direction on an
+	// account initiated since the given time, whichever user made them
+	GetRelated(ctx context.Context, accountID uuid.UUID, direction string, since time.Time) ([]*domain.Transaction, error)
+}
+
+// initiatedAt returns when the transaction was initiated, or the clock's
+// time when it carries none. Detection windows end here rather than at the
+// current time, so rescreening a historical transaction reads the history
+// around it.
+func initiatedAt(tx *domain.Transaction, clk clock.Clock) time.Time {
+	if tx.InitiatedAt.IsZero() {
+		return clk.Now()
+	}
+	return tx.InitiatedAt
+}
+
+// upTo drops the transactions initiated after at. History is read from the
+// start of a window onwards, so for a historical transaction it also
+// returns what followed it.
+func upTo(history []*domain.Transaction, at time.Time) []*domain.Transaction {
+	kept := history[:0:0]
+	for _, h := range history {
+		if !h.InitiatedAt.After(at) {
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
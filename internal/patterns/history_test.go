@@ -0,0 +1,137 @@
+package patterns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/currency"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// storedHistory serves transactions by initiation time, oldest first, as
+// the postgres repository does
+type storedHistory []*domain.Transaction
+
+func (s storedHistory) GetByUser(_ context.Context, userID uuid.UUID, since time.Time) ([]*domain.Transaction, error) {
+	var found []*domain.Transaction
+	for _, tx := range s {
+		if tx.UserID == userID && !tx.InitiatedAt.Before(since) {
+			found = append(found, tx)
+		}
+	}
+	return found, nil
+}
+
+func (s storedHistory) GetInWindow(_ context.Context, window domain.TransactionWindow) ([]*domain.Transaction, error) {
+	var found []*domain.Transaction
+	for _, tx := range s {
+		for _, id := range window.UserIDs {
+			if tx.UserID == id && !tx.InitiatedAt.Before(window.From) && tx.InitiatedAt.Before(window.To) {
+				found = append(found, tx)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (s storedHistory) GetRelated(_ context.Context, accountID uuid.UUID, direction string, since time.Time) ([]*domain.Transaction, error) {
+	var found []*domain.Transaction
+	for _, tx := range s {
+		if tx.AccountID == accountID && tx.Direction == direction && !tx.InitiatedAt.Before(since) {
+			found = append(found, tx)
+		}
+	}
+	return found, nil
+}
+
+// activeVelocity reports a baseline with daily activity
+type activeVelocity struct{}
+
+func (activeVelocity) GetVelocity(_ context.Context, userID uuid.UUID) (*domain.VelocityData, error) {
+	return &domain.VelocityData{UserID: userID, BaselineDays: 30, AvgDailyTxCount: 3}, nil
+}
+
+// TestRescreenHistoricalTransaction screens transactions from months
+// before the clock's time. Their windows must end at their initiation time:
+// the history before them counts and the history after them does not.
+func TestRescreenHistoricalTransaction(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	userID, accountID := uuid.New(), uuid.New()
+
+	transaction := func(initiated time.Time, amount float64) *domain.Transaction {
+		return &domain.Transaction{
+			ID:          uuid.New(),
+			UserID:      userID,
+			AccountID:   accountID,
+			Amount:      money.FromFloat(amount),
+			Currency:    "USD",
+			Direction:   domain.DirectionOutbound,
+			InitiatedAt: initiated,
+		}
+	}
+
+	converter := currency.NewConverter(&config.CurrencyConfig{BaseCurrency: "USD"}, nil, logger.NewNop())
+	cfg := &config.PatternsConfig{
+		StructuringWindowHours: 24,
+		StructuringThreshold:   10000,
+		StructuringMinTxCount:  3,
+		DormancyDays:           90,
+		DormancyLookbackDays:   365,
+		DormantAmountFloor:     5000,
+	}
+	clk := clock.NewFake(now)
+
+	t.Run("structuring", func(t *testing.T) {
+		tx := transaction(at, 4000)
+		earlier := []*domain.Transaction{transaction(at.Add(-3*time.Hour), 4000), transaction(at.Add(-2*time.Hour), 4000)}
+		later := transaction(at.Add(2*time.Hour), 4000)
+		history := storedHistory{earlier[0], earlier[1], later}
+
+		matches, err := NewStructuringDetector(history, converter, clk, cfg).Detect(context.Background(), userID, tx)
+		if err != nil {
+			t.Fatalf("Detect: %v", err)
+		}
+		if len(matches) != 1 {
+			t.Fatalf("got %d matches, want the run ending at the rescreened transaction", len(matches))
+		}
+		related := matches[0].RelatedTxIDs
+		if len(related) != 3 {
+			t.Fatalf("related %d transactions, want the rescreened one and the 2 before it", len(related))
+		}
+		for _, id := range related {
+			if id == later.ID {
+				t.Error("a transaction initiated after the rescreened one was counted")
+			}
+		}
+		if !matches[0].DetectedAt.Equal(now) {
+			t.Errorf("DetectedAt = %s, want the clock's %s", matches[0].DetectedAt, now)
+		}
+	})
+
+	t.Run("dormant reactivation", func(t *testing.T) {
+		tx := transaction(at, 8000)
+		history := storedHistory{
+			transaction(at.AddDate(0, -6, 0), 1000),
+			// Activity after the rescreened transaction says nothing about
+			// whether the account was dormant before it
+			transaction(at.AddDate(0, 0, 10), 1000),
+		}
+
+		detector := NewDormantReactivationDetector(history, activeVelocity{}, converter, clk, cfg)
+		matches, err := detector.Detect(context.Background(), userID, tx)
+		if err != nil {
+			t.Fatalf("Detect: %v", err)
+		}
+		if len(matches) != 1 || matches[0].PatternType != domain.PatternDormantReactivation {
+			t.Fatalf("got %+v, want a dormant reactivation after 6 months idle", matches)
+		}
+	})
+}
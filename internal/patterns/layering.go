@@ -0,0 +1,218 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+)
+
+// LayeringDetector flags funds moved rapidly through intermediary accounts.
+// It builds a graph of the user's recent transactions with accounts as nodes
+// and transfers as edges, then looks for two shapes:
+//
+//   - chains: the transaction completes a run of hops, each leaving the
+//     account the previous one arrived at within a few minutes
+//   - hubs: the transaction's account both collects from and pays out to
+//     many distinct accounts within the window
+type LayeringDetector struct {
+	history   TransactionRepository
+	clock     clock.Clock
+	window    time.Duration
+	maxHopGap time.Duration
+	minHops   int
+	minFanOut int
+}
+
+// NewLayeringDetector creates a mixing/layering detector
+func NewLayeringDetector(history TransactionRepository, clk clock.Clock, cfg *config.PatternsConfig) *LayeringDetector {
+	return &LayeringDetector{
+		history:   history,
+		clock:     clk,
+		window:    time.Duration(cfg.MixingWindowMins) * time.Minute,
+		maxHopGap: time.Duration(cfg.MixingMaxHopGapMins) * time.Minute,
+		minHops:   cfg.MixingMinHops,
+		minFanOut: cfg.MixingMinFanOut,
+	}
+}
+
+// Name returns the detector name
+func (d *LayeringDetector) Name() string {
+	return "mixing_layering"
+}
+
+// flowEdge is one transfer in the transaction graph
+type flowEdge struct {
+	tx   *domain.Transaction
+	from string
+	to   string
+	at   time.Time
+}
+
+// Detect checks whether the transaction is part of a layering chain or hub
+func (d *LayeringDetector) Detect(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error) {
+	at := initiatedAt(tx, d.clock)
+	history, err := d.history.GetByUser(ctx, userID, at.Add(-d.window))
+	if err != nil {
+		return nil, fmt.Errorf("list user transactions: %w", err)
+	}
+
+	current, ok := newFlowEdge(tx)
+	if !ok {
+		return nil, nil
+	}
+	if current.at.IsZero() {
+		current.at = at
+	}
+	edges := []flowEdge{current}
+	for _, h := range upTo(history, at) {
+		if h.ID == tx.ID {
+			continue
+		}
+		if e, ok := newFlowEdge(h); ok {
+			edges = append(edges, e)
+		}
+	}
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].at.Before(edges[j].at) })
+
+	var confidence float64
+	var related []uuid.UUID
+	var findings []string
+
+	chain := d.longestChainTo(edges, tx.ID)
+	if hops := len(chain); hops >= d.minHops {
+		confidence = d.chainConfidence(chain)
+		for _, e := range chain {
+			related = append(related, e.tx.ID)
+		}
+		findings = append(findings, fmt.Sprintf("%d hops through %d intermediary accounts in %s",
+			hops, hops-1, chain[hops-1].at.Sub(chain[0].at).Round(time.Second)))
+	}
+
+	fanIn, fanOut, hubTxs := fanAround(edges, ownAccount(tx))
+	if fanIn >= d.minFanOut && fanOut >= d.minFanOut {
+		hub := math.Min(0.5+0.05*float64(min(fanIn, fanOut)-d.minFanOut), 0.75)
+		if len(findings) > 0 {
+			// A chain running through a hub is the stronger signal
+			confidence = math.Min(confidence+0.1, 1.0)
+		} else {
+			confidence = hub
+			related = hubTxs
+		}
+		findings = append(findings, fmt.Sprintf("account received from %d and paid out to %d distinct accounts within %s",
+			fanIn, fanOut, d.window))
+	}
+
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	return []domain.PatternMatch{{
+		PatternType:  domain.PatternMixingLayering,
+		Confidence:   confidence,
+		Description:  "Rapid movement of funds: " + strings.Join(findings, "; "),
+		RelatedTxIDs: related,
+		DetectedAt:   d.clock.Now(),
+	}}, nil
+}
+
+// longestChainTo returns the longest run of hops ending with the given
+// transaction. Edges must be sorted by time.
+func (d *LayeringDetector) longestChainTo(edges []flowEdge, txID uuid.UUID) []flowEdge {
+	length := make([]int, len(edges))
+	prev := make([]int, len(edges))
+	target := -1
+
+	for i, e := range edges {
+		length[i], prev[i] = 1, -1
+		for j := 0; j < i; j++ {
+			gap := e.at.Sub(edges[j].at)
+			if edges[j].to == e.from && gap >= 0 && gap <= d.maxHopGap && length[j]+1 > length[i] {
+				length[i], prev[i] = length[j]+1, j
+			}
+		}
+		if e.tx.ID == txID {
+			target = i
+		}
+	}
+	if target < 0 {
+		return nil
+	}
+
+	chain := make([]flowEdge, length[target])
+	for i, k := len(chain)-1, target; k >= 0; i, k = i-1, prev[k] {
+		chain[i] = edges[k]
+	}
+	return chain
+}
+
+// chainConfidence starts at 0.5 at the minimum hop count, rises with each
+// extra hop and adds up to 0.2 the faster the funds moved on between hops
+func (d *LayeringDetector) chainConfidence(chain []flowEdge) float64 {
+	c := math.Min(0.5+0.1*float64(len(chain)-d.minHops), 0.8)
+
+	avgGap := chain[len(chain)-1].at.Sub(chain[0].at) / time.Duration(len(chain)-1)
+	c += 0.2 * (1 - float64(avgGap)/float64(d.maxHopGap))
+
+	return math.Min(c, 1.0)
+}
+
+// fanAround counts the distinct accounts paying into and out of an account,
+// returning the transactions involved
+func fanAround(edges []flowEdge, account string) (fanIn, fanOut int, txIDs []uuid.UUID) {
+	sources := make(map[string]bool)
+	destinations := make(map[string]bool)
+	for _, e := range edges {
+		switch account {
+		case e.to:
+			sources[e.from] = true
+		case e.from:
+			destinations[e.to] = true
+		default:
+			continue
+		}
+		txIDs = append(txIDs, e.tx.ID)
+	}
+	return len(sources), len(destinations), txIDs
+}
+
+// newFlowEdge maps a transaction to a transfer between accounts. The
+// customer's side falls back to their internal account ID when the account
+// number is missing; transactions without a counterparty account are skipped.
+func newFlowEdge(tx *domain.Transaction) (flowEdge, bool) {
+	from, to := tx.SenderAccount, tx.ReceiverAccount
+	if tx.Direction == domain.DirectionOutbound && from == "" {
+		from = ownAccount(tx)
+	}
+	if tx.Direction == domain.DirectionInbound && to == "" {
+		to = ownAccount(tx)
+	}
+	if from == "" || to == "" || from == to {
+		return flowEdge{}, false
+	}
+
+	at := tx.InitiatedAt
+	if at.IsZero() {
+		at = tx.CreatedAt
+	}
+	return flowEdge{tx: tx, from: from, to: to, at: at}, true
+}
+
+// ownAccount returns the customer's account node for a transaction
+func ownAccount(tx *domain.Transaction) string {
+	if tx.Direction == domain.DirectionOutbound && tx.SenderAccount != "" {
+		return tx.SenderAccount
+	}
+	if tx.Direction == domain.DirectionInbound && tx.ReceiverAccount != "" {
+		return tx.ReceiverAccount
+	}
+	return tx.AccountID.String()
+}
@@ -0,0 +1,182 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// CurrencyConverter converts amounts to the base currency thresholds are set in
+type CurrencyConverter interface {
+	Base() string
+	Convert(amount money.Amount, currency string) (money.Amount, bool)
+}
+
+// SmurfingDetector flags many small deposits from distinct sources funnelling
+// into one account. Unlike structuring, which looks at one customer splitting
+// their own funds, the deposits here come from different senders, and senders
+// sharing a device or IP address suggest they are coordinated.
+type SmurfingDetector struct {
+	history    TransactionRepository
+	converter  CurrencyConverter
+	clock      clock.Clock
+	window     time.Duration
+	minSources int
+	maxAmount  money.Amount // in the base currency
+}
+
+// NewSmurfingDetector creates a smurfing detector
+func NewSmurfingDetector(history TransactionRepository, converter CurrencyConverter, clk clock.Clock, cfg *config.PatternsConfig) *SmurfingDetector {
+	return &SmurfingDetector{
+		history:    history,
+		converter:  converter,
+		clock:      clk,
+		window:     time.Duration(cfg.SmurfingWindowHours) * time.Hour,
+		minSources: cfg.SmurfingMinSources,
+		maxAmount:  money.FromFloat(cfg.StructuringThreshold),
+	}
+}
+
+// Name returns the detector name
+func (d *SmurfingDetector) Name() string {
+	return "smurfing"
+}
+
+// Detect checks whether the transaction completes a smurfing pattern into
+// its account
+func (d *SmurfingDetector) Detect(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error) {
+	if tx.Direction != domain.DirectionInbound || !d.isSmall(tx) {
+		return nil, nil
+	}
+
+	at := initiatedAt(tx, d.clock)
+	history, err := d.history.GetRelated(ctx, tx.AccountID, domain.DirectionInbound, at.Add(-d.window))
+	if err != nil {
+		return nil, fmt.Errorf("list inbound transactions: %w", err)
+	}
+
+	// The transaction being screened is not stored yet
+	deposits := []*domain.Transaction{tx}
+	for _, h := range upTo(history, at) {
+		if h.ID != tx.ID && d.isSmall(h) {
+			deposits = append(deposits, h)
+		}
+	}
+
+	var related []uuid.UUID
+	var total money.Amount // in the base currency, of the deposits that convert
+	unconverted := 0
+	sources := make(map[string]bool)
+	devices := make(map[string]map[string]bool) // device or IP -> sources seen on it
+	for _, dep := range deposits {
+		source := sourceKey(dep)
+		if source == "" {
+			continue
+		}
+		sources[source] = true
+		related = append(related, dep.ID)
+		if amount, ok := d.converter.Convert(dep.Amount, dep.Currency); ok {
+			total += amount
+		} else {
+			unconverted++
+		}
+
+		for _, key := range []string{deviceKey(dep), ipKey(dep)} {
+			if key == "" {
+				continue
+			}
+			if devices[key] == nil {
+				devices[key] = make(map[string]bool)
+			}
+			devices[key][source] = true
+		}
+	}
+
+	if len(sources) < d.minSources {
+		return nil, nil
+	}
+
+	// Distinct sources that share a device or IP with another source
+	linked := make(map[string]bool)
+	for _, seen := range devices {
+		if len(seen) < 2 {
+			continue
+		}
+		for source := range seen {
+			linked[source] = true
+		}
+	}
+
+	description := fmt.Sprintf("%d small deposits totalling %s %s from %d distinct sources into account %s within %s",
+		len(related), total.StringFixed(2), d.converter.Base(), len(sources), tx.AccountID, d.window)
+	if unconverted > 0 {
+		description += fmt.Sprintf("; %d deposits in currencies without an exchange rate not totalled", unconverted)
+	}
+	if len(linked) > 0 {
+		description += fmt.Sprintf("; %d sources share a device or IP address", len(linked))
+	}
+
+	return []domain.PatternMatch{{
+		PatternType:  domain.PatternSmurfing,
+		Confidence:   d.confidence(len(sources), len(linked)),
+		Description:  description,
+		RelatedTxIDs: related,
+		DetectedAt:   d.clock.Now(),
+	}}, nil
+}
+
+// confidence starts at 0.6 at the minimum source count, rises with each
+// extra source and is boosted by the share of sources linked by device or IP
+func (d *SmurfingDetector) confidence(sources, linked int) float64 {
+	c := 0.6 + 0.05*float64(sources-d.minSources)
+	c = math.Min(c, 0.85)
+	c += 0.15 * float64(linked) / float64(sources)
+	return math.Min(c, 1.0)
+}
+
+// isSmall reports whether a deposit is below the reporting threshold once
+// converted to the base currency. A deposit in a currency without a rate
+// counts as small, so an unknown currency cannot hide a pattern.
+func (d *SmurfingDetector) isSmall(tx *domain.Transaction) bool {
+	if tx.Amount <= 0 {
+		return false
+	}
+	amount, ok := d.converter.Convert(tx.Amount, tx.Currency)
+	return !ok || amount < d.maxAmount
+}
+
+// sourceKey identifies who sent a deposit: the sending account, or failing
+// that the device or IP address it was made from
+func sourceKey(tx *domain.Transaction) string {
+	switch {
+	case tx.SenderAccount != "":
+		return "account:" + tx.SenderAccount
+	case tx.DeviceID != "":
+		return deviceKey(tx)
+	case tx.IPAddress != "":
+		return ipKey(tx)
+	}
+	return ""
+}
+
+func deviceKey(tx *domain.Transaction) string {
+	if tx.DeviceID == "" {
+		return ""
+	}
+	return "device:" + tx.DeviceID
+}
+
+func ipKey(tx *domain.Transaction) string {
+	if tx.IPAddress == "" {
+		return ""
+	}
+	return "ip:" + tx.IPAddress
+}
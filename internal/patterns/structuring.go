@@ -0,0 +1,138 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// StructuringDetector flags a customer splitting funds into transactions
+// that each stay below the reporting threshold but together exceed it.
+// Amounts are converted to the base currency before they are compared or
+// summed, so a deposit split across USD, EUR and GBP is caught the same as
+// one split in a single currency.
+type StructuringDetector struct {
+	history   TransactionRepository
+	converter CurrencyConverter
+	clock     clock.Clock
+	window    time.Duration
+	threshold money.Amount // in the base currency
+	minCount  int
+}
+
+// NewStructuringDetector creates a structuring detector
+func NewStructuringDetector(history TransactionRepository, converter CurrencyConverter, clk clock.Clock, cfg *config.PatternsConfig) *StructuringDetector {
+	return &StructuringDetector{
+		history:   history,
+		converter: converter,
+		clock:     clk,
+		window:    time.Duration(cfg.StructuringWindowHours) * time.Hour,
+		threshold: money.FromFloat(cfg.StructuringThreshold),
+		minCount:  cfg.StructuringMinTxCount,
+	}
+}
+
+// Name returns the detector name
+func (d *StructuringDetector) Name() string {
+	return "structuring"
+}
+
+// Detect checks whether the transaction completes a run of sub-threshold
+// transactions in the same direction whose total reaches the threshold
+func (d *StructuringDetector) Detect(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error) {
+	// Only a sub-threshold transaction can complete the pattern; a
+	// currency without a rate cannot be compared against the threshold
+	if tx.Amount <= 0 {
+		return nil, nil
+	}
+	if amount, ok := d.converter.Convert(tx.Amount, tx.Currency); !ok || amount >= d.threshold {
+		return nil, nil
+	}
+
+	at := initiatedAt(tx, d.clock)
+	history, err := d.history.GetByUser(ctx, userID, at.Add(-d.window))
+	if err != nil {
+		return nil, fmt.Errorf("list user transactions: %w", err)
+	}
+
+	// The transaction being screened is not stored yet
+	transactions := append([]*domain.Transaction{tx}, upTo(history, at)...)
+
+	var related []uuid.UUID
+	var total money.Amount // in the base currency
+	original := make(map[string]money.Amount)
+	unconverted := 0
+	for i, t := range transactions {
+		if i > 0 && (t.ID == tx.ID || t.Direction != tx.Direction || t.Amount <= 0) {
+			continue
+		}
+		amount, ok := d.converter.Convert(t.Amount, t.Currency)
+		if !ok {
+			unconverted++
+			continue
+		}
+		if amount >= d.threshold {
+			continue
+		}
+		related = append(related, t.ID)
+		total += amount
+		original[strings.ToUpper(t.Currency)] += t.Amount
+	}
+
+	if len(related) < d.minCount || total < d.threshold {
+		return nil, nil
+	}
+
+	base := d.converter.Base()
+	description := fmt.Sprintf("%d %s transactions each below %s %s totalling %s %s within %s; original amounts %s",
+		len(related), strings.ToLower(tx.Direction), d.threshold.StringFixed(2), base,
+		total.StringFixed(2), base, d.window, formatByCurrency(original))
+	if unconverted > 0 {
+		description += fmt.Sprintf("; %d transactions in currencies without an exchange rate not totalled", unconverted)
+	}
+
+	return []domain.PatternMatch{{
+		PatternType:  domain.PatternStructuring,
+		Confidence:   d.confidence(len(related), len(original)),
+		Description:  description,
+		RelatedTxIDs: related,
+		DetectedAt:   d.clock.Now(),
+	}}, nil
+}
+
+// confidence starts at 0.6 at the minimum transaction count and rises with
+// each extra transaction. Splitting across currencies adds 0.1, as it takes
+// deliberate effort to stay under the threshold that way.
+func (d *StructuringDetector) confidence(count, currencies int) float64 {
+	c := math.Min(0.6+0.05*float64(count-d.minCount), 0.85)
+	if currencies > 1 {
+		c += 0.1
+	}
+	return math.Min(c, 1.0)
+}
+
+// formatByCurrency lists amounts in their original currencies, e.g.
+// "4000.00 EUR, 7500.00 USD"
+func formatByCurrency(amounts map[string]money.Amount) string {
+	currencies := make([]string, 0, len(amounts))
+	for cur := range amounts {
+		currencies = append(currencies, cur)
+	}
+	sort.Strings(currencies)
+
+	parts := make([]string, len(currencies))
+	for i, cur := range currencies {
+		parts[i] = amounts[cur].StringFixed(2) + " " + cur
+	}
+	return strings.Join(parts, ", ")
+}
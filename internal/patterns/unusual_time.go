@@ -0,0 +1,112 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+)
+
+// UnusualTimeDetector flags transactions made at hours the user rarely
+// transacts, learned from their history, or inside configured suspicious
+// hours. Hours are read in the offset the transaction was initiated with,
+// which is the customer's local time for client-stamped transactions.
+//
+// Matches are deliberately low confidence: odd hours nudge the score but
+// should rarely decide the outcome on their own.
+type UnusualTimeDetector struct {
+	history         TransactionRepository
+	clock           clock.Clock
+	baseline        time.Duration
+	minHistory      int
+	rareShare       float64
+	suspiciousHours map[int]bool
+}
+
+// NewUnusualTimeDetector creates an unusual-time detector
+func NewUnusualTimeDetector(history TransactionRepository, clk clock.Clock, cfg *config.PatternsConfig) *UnusualTimeDetector {
+	suspicious := make(map[int]bool, len(cfg.SuspiciousHours))
+	for _, h := range cfg.SuspiciousHours {
+		suspicious[h] = true
+	}
+
+	return &UnusualTimeDetector{
+		history:         history,
+		clock:           clk,
+		baseline:        time.Duration(cfg.UnusualTimeBaselineDays) * 24 * time.Hour,
+		minHistory:      cfg.UnusualTimeMinHistory,
+		rareShare:       cfg.UnusualTimeRareShare,
+		suspiciousHours: suspicious,
+	}
+}
+
+// Name returns the detector name
+func (d *UnusualTimeDetector) Name() string {
+	return "unusual_time"
+}
+
+// Detect checks the transaction's hour against the user's usual hours and
+// the suspicious hours
+func (d *UnusualTimeDetector) Detect(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error) {
+	at := tx.InitiatedAt
+	if at.IsZero() {
+		return nil, nil
+	}
+	hour := at.Hour()
+
+	history, err := d.history.GetByUser(ctx, userID, at.Add(-d.baseline))
+	if err != nil {
+		return nil, fmt.Errorf("list user transactions: %w", err)
+	}
+
+	var hours [24]int
+	total := 0
+	for _, h := range upTo(history, at) {
+		if h.ID == tx.ID || h.InitiatedAt.IsZero() {
+			continue
+		}
+		hours[h.InitiatedAt.Hour()]++
+		total++
+	}
+
+	var confidence float64
+	var findings []string
+
+	if total >= d.minHistory {
+		// Count the neighbouring hours too so activity just either side of
+		// the usual window is not treated as unusual
+		near := hours[(hour+23)%24] + hours[hour] + hours[(hour+1)%24]
+		if share := float64(near) / float64(total); share < d.rareShare {
+			confidence = 0.3
+			findings = append(findings, fmt.Sprintf("%.1f%% of the user's %d transactions in the last %s were made around %02d:00",
+				share*100, total, d.baseline, hour))
+		}
+	}
+
+	if d.suspiciousHours[hour] {
+		if confidence > 0 {
+			confidence = 0.4
+		} else {
+			confidence = 0.25
+		}
+		findings = append(findings, fmt.Sprintf("%02d:00 is within the configured suspicious hours", hour))
+	}
+
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	return []domain.PatternMatch{{
+		PatternType:  domain.PatternUnusualTime,
+		Confidence:   confidence,
+		Description:  fmt.Sprintf("Transaction at %s: %s", at.Format("15:04 MST"), strings.Join(findings, "; ")),
+		RelatedTxIDs: []uuid.UUID{tx.ID},
+		DetectedAt:   d.clock.Now(),
+	}}, nil
+}
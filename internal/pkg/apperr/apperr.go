@@ -0,0 +1,171 @@
+// Package apperr classifies errors by what a caller can do about them. Each
+// class has a machine-readable code that HTTP handlers map to a status and
+// the Kafka consumer uses to decide between retrying and dead-lettering.
+package apperr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Code identifies a class of error
+type Code string
+
+const (
+	CodeValidation            Code = "VALIDATION_FAILED"
+	CodeNotFound              Code = "NOT_FOUND"
+	CodeConflict              Code = "CONFLICT"
+	CodeUnauthorized          Code = "UNAUTHORIZED"
+	CodeForbidden             Code = "FORBIDDEN"
+	CodeDependencyUnavailable Code = "DEPENDENCY_UNAVAILABLE"
+	CodeIntegrity             Code = "INTEGRITY_FAILED"
+	CodeInternal              Code = "INTERNAL"
+)
+
+// Error is an error of a known class. Errors without a cause are used as
+// sentinels: errors.Is matches any Error of the same code against them.
+type Error struct {
+	Code    Code
+	Message string
+	Details interface{}
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a sentinel of the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Err == nil && t.Code == e.Code
+}
+
+// New returns an error of the given code
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap returns an error of the given code caused by err
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Unavailable reports that a call to dependency failed or was refused
+func Unavailable(dependency string, err error) *Error {
+	return Wrap(CodeDependencyUnavailable, dependency+" unavailable", err)
+}
+
+// Detailer is implemented by errors carrying structured details for the
+// caller, such as the fields that failed validation
+type Detailer interface {
+	ErrorDetails() interface{}
+}
+
+// CodeOf returns the class of err. Errors that do not carry a code are
+// classified from the standard library's signals: timeouts, network errors
+// and broken database connections mean a dependency is unavailable, and
+// anything else is internal.
+func CodeOf(err error) Code {
+	var e *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &e):
+		return e.Code
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone):
+		return CodeDependencyUnavailable
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return CodeDependencyUnavailable
+	}
+	return CodeInternal
+}
+
+// DetailsOf returns the structured details carried by err, if any
+func DetailsOf(err error) interface{} {
+	var d Detailer
+	if errors.As(err, &d) {
+		return d.ErrorDetails()
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Details
+	}
+	return nil
+}
+
+// Retryable reports whether the operation that failed with err may succeed
+// if tried again. Unavailable dependencies and unclassified failures are
+// retryable; errors in the request itself are not.
+func Retryable(err error) bool {
+	switch CodeOf(err) {
+	case CodeDependencyUnavailable, CodeInternal:
+		return true
+	}
+	return false
+}
+
+// HTTPStatus returns the HTTP status for a code
+func HTTPStatus(code Code) int {
+	switch code {
+	case CodeValidation:
+		return http.StatusBadRequest
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeConflict:
+		return http.StatusConflict
+	case CodeUnauthorized:
+		return http.StatusUnauthorized
+	case CodeForbidden:
+		return http.StatusForbidden
+	case CodeDependencyUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// CodeForStatus returns the code for an HTTP status, for responses that
+// were not produced from an Error
+func CodeForStatus(status int) Code {
+	switch {
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusConflict:
+		return CodeConflict
+	case status == http.StatusUnauthorized:
+		return CodeUnauthorized
+	case status == http.StatusForbidden:
+		return CodeForbidden
+	case status == http.StatusServiceUnavailable, status == http.StatusGatewayTimeout:
+		return CodeDependencyUnavailable
+	case status >= 400 && status < 500:
+		return CodeValidation
+	}
+	return CodeInternal
+}
+
+// IsClientError reports whether code describes a problem with the request,
+// whose message is safe to show the caller
+func IsClientError(code Code) bool {
+	switch code {
+	case CodeValidation, CodeNotFound, CodeConflict, CodeUnauthorized, CodeForbidden:
+		return true
+	}
+	return false
+}
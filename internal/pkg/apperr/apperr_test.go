@@ -0,0 +1,142 @@
+package apperr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+)
+
+var errNotFound = New(CodeNotFound, "not found")
+
+type fieldErrors []string
+
+func (f fieldErrors) Error() string             { return "invalid fields" }
+func (f fieldErrors) ErrorDetails() interface{} { return []string(f) }
+
+func TestErrorIs(t *testing.T) {
+	wrapped := fmt.Errorf("get alert: %w", Wrap(CodeNotFound, "alert not found", sql.ErrNoRows))
+
+	if !errors.Is(wrapped, errNotFound) {
+		t.Error("wrapped error of the same code does not match the sentinel")
+	}
+	if !errors.Is(wrapped, sql.ErrNoRows) {
+		t.Error("error does not match its cause")
+	}
+	if errors.Is(wrapped, New(CodeConflict, "conflict")) {
+		t.Error("error matches a sentinel of another code")
+	}
+	if errors.Is(wrapped, Wrap(CodeNotFound, "other", errors.New("cause"))) {
+		t.Error("error matches an error with a cause, which is not a sentinel")
+	}
+
+	var e *Error
+	if !errors.As(wrapped, &e) || e.Code != CodeNotFound || e.Message != "alert not found" {
+		t.Errorf("errors.As = %+v, want the NOT_FOUND error", e)
+	}
+	if got, want := wrapped.Error(), "get alert: alert not found: sql: no rows in result set"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"coded", fmt.Errorf("wrapped: %w", New(CodeForbidden, "forbidden")), CodeForbidden},
+		{"unavailable", Unavailable("redis", errors.New("refused")), CodeDependencyUnavailable},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), CodeDependencyUnavailable},
+		{"bad connection", driver.ErrBadConn, CodeDependencyUnavailable},
+		{"connection done", sql.ErrConnDone, CodeDependencyUnavailable},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("refused")}, CodeDependencyUnavailable},
+		{"unclassified", errors.New("boom"), CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	if !Retryable(Unavailable("postgres", errors.New("refused"))) {
+		t.Error("unavailable dependency is not retryable")
+	}
+	if !Retryable(errors.New("boom")) {
+		t.Error("unclassified failure is not retryable")
+	}
+	if Retryable(New(CodeValidation, "bad amount")) {
+		t.Error("validation failure is retryable")
+	}
+}
+
+func TestDetailsOf(t *testing.T) {
+	fields := fieldErrors{"amount"}
+	if got, ok := DetailsOf(fmt.Errorf("validate: %w", fields)).([]string); !ok || len(got) != 1 || got[0] != "amount" {
+		t.Errorf("DetailsOf a Detailer = %v, want its fields", got)
+	}
+	e := &Error{Code: CodeValidation, Message: "invalid", Details: "details"}
+	if got := DetailsOf(e); got != "details" {
+		t.Errorf("DetailsOf an Error = %v, want its details", got)
+	}
+	if got := DetailsOf(errors.New("boom")); got != nil {
+		t.Errorf("DetailsOf a plain error = %v, want nil", got)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code   Code
+		status int
+	}{
+		{CodeValidation, http.StatusBadRequest},
+		{CodeNotFound, http.StatusNotFound},
+		{CodeConflict, http.StatusConflict},
+		{CodeUnauthorized, http.StatusUnauthorized},
+		{CodeForbidden, http.StatusForbidden},
+		{CodeDependencyUnavailable, http.StatusServiceUnavailable},
+		{CodeIntegrity, http.StatusInternalServerError},
+		{CodeInternal, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			if got := HTTPStatus(tt.code); got != tt.status {
+				t.Errorf("HTTPStatus = %d, want %d", got, tt.status)
+			}
+		})
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		code   Code
+	}{
+		{http.StatusBadRequest, CodeValidation},
+		{http.StatusUnprocessableEntity, CodeValidation},
+		{http.StatusRequestEntityTooLarge, CodeValidation},
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusConflict, CodeConflict},
+		{http.StatusUnauthorized, CodeUnauthorized},
+		{http.StatusForbidden, CodeForbidden},
+		{http.StatusServiceUnavailable, CodeDependencyUnavailable},
+		{http.StatusGatewayTimeout, CodeDependencyUnavailable},
+		{http.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			if got := CodeForStatus(tt.status); got != tt.code {
+				t.Errorf("CodeForStatus(%d) = %q, want %q", tt.status, got, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Package breaker wraps sony/gobreaker with logging and state reporting for
+// the service's external dependencies
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/apperr"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// IsOpen returns true if err means the breaker rejected the call without
+// attempting it
+func IsOpen(err error) bool {
+	return errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests)
+}
+
+// Breaker guards calls to a single dependency
+type Breaker struct {
+	name   string
+	cb     *gobreaker.CircuitBreaker[any]
+	ignore []error
+	log    *logger.Logger
+
+	transitions    atomic.Int64
+	lastTransition atomic.Int64 // unix nanos
+}
+
+// Status is a point-in-time view of a breaker
+type Status struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	ConsecutiveFailures uint32     `json:"consecutive_failures"`
+	Transitions         int64      `json:"transitions"`
+	LastTransitionAt    *time.Time `json:"last_transition_at,omitempty"`
+}
+
+// New creates a breaker that opens after cfg.FailureThreshold consecutive
+// failures and probes again after cfg.OpenTimeout. Errors matching any of
+// ignore (e.g. not-found) count as successes; context cancellation by the
+// caller is not counted at all.
+func New(name string, cfg config.CircuitBreakerConfig, log *logger.Logger, ignore ...error) *Breaker {
+	b := &Breaker{
+		name:   name,
+		ignore: ignore,
+		log:    log.Named("breaker"),
+	}
+
+	threshold := max(cfg.FailureThreshold, 1)
+	b.cb = gobreaker.NewCircuitBreaker[any](gobreaker.Settings{
+		Name:        name,
+		MaxRequests: cfg.HalfOpenRequests,
+		Timeout:     cfg.OpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= threshold
+		},
+		IsSuccessful: func(err error) bool {
+			return err == nil || b.ignored(err)
+		},
+		IsExcluded: func(err error) bool {
+			return errors.Is(err, context.Canceled)
+		},
+		OnStateChange: b.onStateChange,
+	})
+
+	return b
+}
+
+// Name returns the dependency name
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Run executes fn through the breaker. Failures, and calls the open breaker
+// refused, are returned as apperr.CodeDependencyUnavailable errors naming
+// the dependency; ignored errors and cancellation are returned as they are.
+func (b *Breaker) Run(fn func() error) error {
+	_, err := b.cb.Execute(func() (any, error) {
+		return nil, fn()
+	})
+	if err == nil || b.ignored(err) || errors.Is(err, context.Canceled) {
+		return err
+	}
+	return apperr.Unavailable(b.name, err)
+}
+
+// ignored returns true if err matches one of the errors counted as success
+func (b *Breaker) ignored(err error) bool {
+	for _, target := range b.ignore {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Do executes fn through the breaker and returns its result
+func Do[T any](b *Breaker, fn func() (T, error)) (T, error) {
+	var result T
+	err := b.Run(func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
+
+// Status returns the breaker's current state
+func (b *Breaker) Status() Status {
+	s := Status{
+		Name:                b.name,
+		State:               b.cb.State().String(),
+		ConsecutiveFailures: b.cb.Counts().ConsecutiveFailures,
+		Transitions:         b.transitions.Load(),
+	}
+	if nanos := b.lastTransition.Load(); nanos != 0 {
+		t := time.Unix(0, nanos)
+		s.LastTransitionAt = &t
+	}
+	return s
+}
+
+func (b *Breaker) onStateChange(name string, from, to gobreaker.State) {
+	b.transitions.Add(1)
+	b.lastTransition.Store(time.Now().UnixNano())
+
+	log := b.log.Info
+	if to == gobreaker.StateOpen {
+		log = b.log.Warn
+	}
+	log("circuit breaker state changed",
+		logger.StringField("breaker", name),
+		logger.StringField("from", from.String()),
+		logger.StringField("to", to.String()),
+	)
+}
+
+// Registry tracks breakers so their state can be reported together
+type Registry struct {
+	log *logger.Logger
+
+	mu       sync.RWMutex
+	breakers map[string]*Breaker
+}
+
+// NewRegistry creates an empty breaker registry
+func NewRegistry(log *logger.Logger) *Registry {
+	return &Registry{
+		log:      log,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// New creates and registers a breaker
+func (r *Registry) New(name string, cfg config.CircuitBreakerConfig, ignore ...error) *Breaker {
+	b := New(name, cfg, r.log, ignore...)
+
+	r.mu.Lock()
+	r.breakers[name] = b
+	r.mu.Unlock()
+
+	return b
+}
+
+// Snapshot returns the status of every registered breaker, sorted by name
+func (r *Registry) Snapshot() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,148 @@
+// Package currency converts transaction amounts into the base currency that
+// monetary thresholds are configured in
+package currency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// RatesSource supplies exchange rates as base currency per unit of each
+// upper-case ISO 4217 currency
+type RatesSource interface {
+	Rates(ctx context.Context) (map[string]float64, error)
+}
+
+// Converter converts amounts to the base currency. Rates from the optional
+// remote source take precedence over the static table; when a refresh
+// fails the previously loaded rates stay in use.
+type Converter struct {
+	base     string
+	static   map[string]float64
+	remote   RatesSource
+	interval time.Duration
+	log      *logger.Logger
+
+	mu    sync.RWMutex
+	rates map[string]float64
+}
+
+// NewConverter creates a converter from the configured static table. remote
+// may be nil, in which case only the static table is used.
+func NewConverter(cfg *config.CurrencyConfig, remote RatesSource, log *logger.Logger) *Converter {
+	base := strings.ToUpper(cfg.BaseCurrency)
+	static := make(map[string]float64, len(cfg.Rates)+1)
+	for code, rate := range cfg.Rates {
+		// Config map keys arrive lower-cased
+		static[strings.ToUpper(code)] = rate
+	}
+	static[base] = 1
+
+	return &Converter{
+		base:     base,
+		static:   static,
+		remote:   remote,
+		interval: cfg.RefreshInterval,
+		log:      log.Named("currency_converter"),
+		rates:    static,
+	}
+}
+
+// Base returns the base currency code
+func (c *Converter) Base() string {
+	return c.base
+}
+
+// Convert converts an amount to the base currency, rounded to the amount's
+// precision. ok is false when the currency has no rate.
+func (c *Converter) Convert(amount money.Amount, currency string) (money.Amount, bool) {
+	rate, ok := c.rate(currency)
+	if !ok {
+		return 0, false
+	}
+	return amount.Mul(rate), true
+}
+
+// ConvertTo converts an amount between two currencies through the base
+// currency. ok is false when either currency has no rate.
+func (c *Converter) ConvertTo(amount money.Amount, from, to string) (money.Amount, bool) {
+	fromRate, ok := c.rate(from)
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := c.rate(to)
+	if !ok {
+		return 0, false
+	}
+	return amount.Mul(fromRate / toRate), true
+}
+
+func (c *Converter) rate(currency string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rate, ok := c.rates[strings.ToUpper(strings.TrimSpace(currency))]
+	return rate, ok
+}
+
+// Run refreshes the rates from the remote source at startup and then on the
+// configured interval until ctx is cancelled. It returns at once when there
+// is no remote source.
+func (c *Converter) Run(ctx context.Context) {
+	if c.remote == nil {
+		return
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.log.Info("currency rate refresh started",
+		logger.StringField("base_currency", c.base),
+		logger.DurationField("interval", c.interval),
+	)
+
+	for {
+		if err := c.Refresh(ctx); err != nil {
+			c.log.Error("currency rate refresh failed, keeping previous rates", logger.ErrorField(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			c.log.Info("currency rate refresh stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh loads the remote rates and overlays them on the static table
+func (c *Converter) Refresh(ctx context.Context) error {
+	fetched, err := c.remote.Rates(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch currency rates: %w", err)
+	}
+
+	rates := make(map[string]float64, len(c.static)+len(fetched))
+	for code, rate := range c.static {
+		rates[code] = rate
+	}
+	for code, rate := range fetched {
+		if rate > 0 {
+			rates[strings.ToUpper(code)] = rate
+		}
+	}
+	rates[c.base] = 1
+
+	c.mu.Lock()
+	c.rates = rates
+	c.mu.Unlock()
+
+	c.log.Info("currency rates refreshed", logger.IntField("currencies", len(rates)))
+	return nil
+}
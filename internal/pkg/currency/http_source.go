@@ -0,0 +1,74 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// httpTimeout bounds a single rates request
+const httpTimeout = 30 * time.Second
+
+// maxRatesBody caps how much of a rates response is read
+const maxRatesBody = 1 << 20
+
+// HTTPSource fetches rates from a JSON feed quoting units of each currency
+// per unit of the base, such as {"base": "USD", "rates": {"EUR": 0.92}}
+type HTTPSource struct {
+	url    string
+	base   string
+	client *http.Client
+}
+
+// NewHTTPSource creates a rates source for the feed at url, which must quote
+// against base
+func NewHTTPSource(url, base string) *HTTPSource {
+	return &HTTPSource{
+		url:    url,
+		base:   strings.ToUpper(base),
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Rates fetches the feed and inverts its quotes to base currency per unit
+func (s *HTTPSource) Rates(ctx context.Context) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build rates request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request rates: unexpected status %s", resp.Status)
+	}
+
+	var feed struct {
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRatesBody)).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decode rates: %w", err)
+	}
+	if !strings.EqualFold(feed.Base, s.base) {
+		return nil, fmt.Errorf("rates feed quotes against %q, expected %q", feed.Base, s.base)
+	}
+	if len(feed.Rates) == 0 {
+		return nil, fmt.Errorf("rates feed returned no rates")
+	}
+
+	rates := make(map[string]float64, len(feed.Rates))
+	for code, perBase := range feed.Rates {
+		if perBase > 0 {
+			rates[strings.ToUpper(code)] = 1 / perBase
+		}
+	}
+	return rates, nil
+}
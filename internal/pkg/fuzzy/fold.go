@@ -0,0 +1,47 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stripMarks decomposes text, drops combining marks and recomposes what is
+// left, so "é" becomes "e" and compatibility forms such as "ﬁ" are expanded
+var stripMarks = transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// foldedLetters are Latin letters with no canonical decomposition
+var foldedLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d",
+	'ð': "d", 'þ': "th", 'ı': "i", 'ħ': "h", 'ŀ': "l", 'ŧ': "t",
+	'Æ': "AE", 'Œ': "OE", 'Ø': "O", 'Ł': "L", 'Đ': "D", 'Ð': "D",
+	'Þ': "TH", 'Ħ': "H", 'Ŀ': "L", 'Ŧ': "T",
+}
+
+// FoldDiacritics strips diacritics, folding accented letters to their base
+// form: "José Müller" becomes "Jose Muller". Letters without a
+// decomposition, such as "ø" and "ß", are mapped to their usual ASCII
+// spelling.
+func FoldDiacritics(s string) string {
+	folded, _, err := transform.String(stripMarks, s)
+	if err != nil {
+		folded = s
+	}
+
+	if !strings.ContainsFunc(folded, func(r rune) bool { _, ok := foldedLetters[r]; return ok }) {
+		return folded
+	}
+
+	var b strings.Builder
+	for _, r := range folded {
+		if repl, ok := foldedLetters[r]; ok {
+			b.WriteString(repl)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Penalties taken off a perfect score for each allowance Initials makes
+const (
+	firstInitialPenalty  = 0.08 // given name written as its initial
+	middleInitialPenalty = 0.02 // middle name written as its initial
+	middleMissingPenalty = 0.03 // middle name present on one side only
+)
+
+// Initials scores how well two normalized personal names agree when
+// initials stand in for given names and middle names may be left out, so
+// "v putin" and "vladimir putin" both match "vladimir vladimirovich putin".
+// The surnames, taken as the last words, must be equal and at least two
+// letters long, and the given names must agree in order, each equal to the
+// other or its initial. Every initial and missing middle name lowers the
+// score a little, so a name written in full scores above one reduced to
+// initials. Names of a single word, or that disagree on any word, score 0.
+// Returns value between 0 (no match) and 1 (exact match)
+func Initials(s1, s2 string) float64 {
+	w1, w2 := strings.Fields(s1), strings.Fields(s2)
+	if len(w1) < 2 || len(w2) < 2 {
+		return 0.0
+	}
+
+	surname := w1[len(w1)-1]
+	if surname != w2[len(w2)-1] || utf8.RuneCountInString(surname) < 2 {
+		return 0.0
+	}
+
+	score := 1.0
+	switch {
+	case w1[0] == w2[0]:
+	case initialOf(w1[0], w2[0]) || initialOf(w2[0], w1[0]):
+		score -= firstInitialPenalty
+	default:
+		return 0.0
+	}
+
+	// Middle names are aligned in order; one the other side lacks is
+	// skipped, and a name that agrees with none of the other side's fails
+	m1, m2 := w1[1:len(w1)-1], w2[1:len(w2)-1]
+	i, j := 0, 0
+	for i < len(m1) && j < len(m2) {
+		switch {
+		case m1[i] == m2[j]:
+			i, j = i+1, j+1
+		case initialOf(m1[i], m2[j]) || initialOf(m2[j], m1[i]):
+			score -= middleInitialPenalty
+			i, j = i+1, j+1
+		case agreesWithAny(m1[i], m2[j+1:]):
+			score -= middleMissingPenalty
+			j++
+		case agreesWithAny(m2[j], m1[i+1:]):
+			score -= middleMissingPenalty
+			i++
+		default:
+			return 0.0
+		}
+	}
+	score -= float64(len(m1)-i+len(m2)-j) * middleMissingPenalty
+
+	return max(score, 0.0)
+}
+
+// initialOf reports whether initial is a single letter starting name
+func initialOf(initial, name string) bool {
+	return utf8.RuneCountInString(initial) == 1 && strings.HasPrefix(name, initial)
+}
+
+// agreesWithAny reports whether word equals or is an initial of, or is
+// initialled by, any of names
+func agreesWithAny(word string, names []string) bool {
+	for _, name := range names {
+		if word == name || initialOf(word, name) || initialOf(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+// Package fuzzy provides string similarity and folding functions for name
+// matching
+package fuzzy
+
+// JaroWinkler calculates Jaro-Winkler similarity between two strings
+// Returns value between 0 (no match) and 1 (exact match)
+func JaroWinkler(s1, s2 string) float64 {
+	if s1 == s2 {
+		return 1.0
+	}
+
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0.0
+	}
+
+	// Calculate Jaro distance
+	matchDistance := max(len(s1), len(s2))/2 - 1
+	if matchDistance < 0 {
+		matchDistance = 0
+	}
+
+	s1Matches := make([]bool, len(s1))
+	s2Matches := make([]bool, len(s2))
+
+	matches := 0
+	transpositions := 0
+
+	for i := 0; i < len(s1); i++ {
+		start := max(0, i-matchDistance)
+		end := min(i+matchDistance+1, len(s2))
+
+		for j := start; j < end; j++ {
+			if s2Matches[j] || s1[i] != s2[j] {
+				continue
+			}
+			s1Matches[i] = true
+			s2Matches[j] = true
+			matches++
+			break
+		}
+	}
+
+	if matches == 0 {
+		return 0.0
+	}
+
+	k := 0
+	for i := 0; i < len(s1); i++ {
+		if !s1Matches[i] {
+			continue
+		}
+		for !s2Matches[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	jaro := (float64(matches)/float64(len(s1)) +
+		float64(matches)/float64(len(s2)) +
+		float64(matches-transpositions/2)/float64(matches)) / 3.0
+
+	// Calculate Winkler adjustment (prefix bonus)
+	prefix := 0
+	for i := 0; i < min(4, min(len(s1), len(s2))); i++ {
+		if s1[i] == s2[i] {
+			prefix++
+		} else {
+			break
+		}
+	}
+
+	return jaro + float64(prefix)*0.1*(1.0-jaro)
+}
@@ -0,0 +1,36 @@
+package fuzzy
+
+// Levenshtein calculates the Levenshtein ratio between two strings: one
+// minus the edit distance divided by the length of the longer string.
+// Returns value between 0 (no match) and 1 (exact match)
+func Levenshtein(s1, s2 string) float64 {
+	if s1 == s2 {
+		return 1.0
+	}
+
+	r1, r2 := []rune(s1), []rune(s2)
+	if len(r1) == 0 || len(r2) == 0 {
+		return 0.0
+	}
+
+	// Two-row dynamic programming over the edit distance matrix
+	prev := make([]int, len(r2)+1)
+	curr := make([]int, len(r2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(r1); i++ {
+		curr[0] = i
+		for j := 1; j <= len(r2); j++ {
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1.0 - float64(prev[len(r2)])/float64(max(len(r1), len(r2)))
+}
@@ -0,0 +1,659 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// metaphoneCodeLen is the maximum length of a Double Metaphone code
+const metaphoneCodeLen = 4
+
+// Phonetic calculates how alike two names sound. Each word is encoded with
+// Double Metaphone and paired with the most similar-sounding word of the
+// other name, comparing codes with Jaro-Winkler; the score is the average
+// over the words of both names, so a missing or extra word lowers it.
+// Names with no encodable letters, such as those in non-Latin scripts, fall
+// back to Jaro-Winkler on the names themselves.
+// Returns value between 0 (no match) and 1 (exact match)
+func Phonetic(s1, s2 string) float64 {
+	if s1 == s2 {
+		return 1.0
+	}
+
+	w1, w2 := encodeWords(s1), encodeWords(s2)
+	if len(w1) == 0 || len(w2) == 0 {
+		return JaroWinkler(s1, s2)
+	}
+
+	total := 0.0
+	for _, w := range w1 {
+		total += w.bestMatch(w2)
+	}
+	for _, w := range w2 {
+		total += w.bestMatch(w1)
+	}
+	return total / float64(len(w1)+len(w2))
+}
+
+// wordCode is the Double Metaphone encoding of one word
+type wordCode struct {
+	primary, alternate string
+}
+
+// encodeWords encodes each word of name, skipping words with no code
+func encodeWords(name string) []wordCode {
+	var codes []wordCode
+	for _, word := range strings.Fields(name) {
+		if p, a := DoubleMetaphone(word); p != "" || a != "" {
+			codes = append(codes, wordCode{primary: p, alternate: a})
+		}
+	}
+	return codes
+}
+
+// bestMatch returns the highest similarity between w and any of words,
+// over both encodings of each
+func (w wordCode) bestMatch(words []wordCode) float64 {
+	best := 0.0
+	for _, o := range words {
+		best = max(best,
+			JaroWinkler(w.primary, o.primary),
+			JaroWinkler(w.primary, o.alternate),
+			JaroWinkler(w.alternate, o.primary),
+			JaroWinkler(w.alternate, o.alternate),
+		)
+	}
+	return best
+}
+
+// DoubleMetaphone returns the primary and alternate Double Metaphone codes
+// of a word, after Lawrence Philips' algorithm. The alternate code equals
+// the primary when the word has a single likely pronunciation.
+func DoubleMetaphone(word string) (primary, alternate string) {
+	m := &metaphone{s: []rune(strings.ToUpper(strings.TrimSpace(word)))}
+	if len(m.s) == 0 {
+		return "", ""
+	}
+	m.slavoGermanic = m.isSlavoGermanic()
+
+	i := 0
+	if m.has(0, "GN", "KN", "PN", "WR", "PS") {
+		i = 1
+	}
+
+	for i < len(m.s) && (m.primary.Len() < metaphoneCodeLen || m.alternate.Len() < metaphoneCodeLen) {
+		switch c := m.s[i]; c {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if i == 0 {
+				m.add("A")
+			}
+			i++
+		case 'B':
+			m.add("P")
+			i = m.skipDouble(i, 'B')
+		case 'Ç':
+			m.add("S")
+			i++
+		case 'C':
+			i = m.handleC(i)
+		case 'D':
+			i = m.handleD(i)
+		case 'F':
+			m.add("F")
+			i = m.skipDouble(i, 'F')
+		case 'G':
+			i = m.handleG(i)
+		case 'H':
+			i = m.handleH(i)
+		case 'J':
+			i = m.handleJ(i)
+		case 'K':
+			m.add("K")
+			i = m.skipDouble(i, 'K')
+		case 'L':
+			i = m.handleL(i)
+		case 'M':
+			m.add("M")
+			if m.conditionM0(i) {
+				i += 2
+			} else {
+				i++
+			}
+		case 'N':
+			m.add("N")
+			i = m.skipDouble(i, 'N')
+		case 'Ñ':
+			m.add("N")
+			i++
+		case 'P':
+			i = m.handleP(i)
+		case 'Q':
+			m.add("K")
+			i = m.skipDouble(i, 'Q')
+		case 'R':
+			i = m.handleR(i)
+		case 'S':
+			i = m.handleS(i)
+		case 'T':
+			i = m.handleT(i)
+		case 'V':
+			m.add("F")
+			i = m.skipDouble(i, 'V')
+		case 'W':
+			i = m.handleW(i)
+		case 'X':
+			i = m.handleX(i)
+		case 'Z':
+			i = m.handleZ(i)
+		default:
+			i++
+		}
+	}
+
+	return truncate(m.primary.String()), truncate(m.alternate.String())
+}
+
+// metaphone holds the state of one Double Metaphone encoding
+type metaphone struct {
+	s             []rune
+	primary       strings.Builder
+	alternate     strings.Builder
+	slavoGermanic bool
+}
+
+// add appends code to both encodings
+func (m *metaphone) add(code string) {
+	m.primary.WriteString(code)
+	m.alternate.WriteString(code)
+}
+
+// add2 appends different codes to the primary and alternate encodings
+func (m *metaphone) add2(primary, alternate string) {
+	m.primary.WriteString(primary)
+	m.alternate.WriteString(alternate)
+}
+
+// at returns the letter at i, or zero outside the word
+func (m *metaphone) at(i int) rune {
+	if i < 0 || i >= len(m.s) {
+		return 0
+	}
+	return m.s[i]
+}
+
+// has reports whether the word contains any of options starting at i. All
+// options must be the same length.
+func (m *metaphone) has(i int, options ...string) bool {
+	if i < 0 || len(options) == 0 {
+		return false
+	}
+	n := len([]rune(options[0]))
+	if i+n > len(m.s) {
+		return false
+	}
+	sub := string(m.s[i : i+n])
+	for _, o := range options {
+		if sub == o {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *metaphone) last() int {
+	return len(m.s) - 1
+}
+
+func (m *metaphone) skipDouble(i int, c rune) int {
+	if m.at(i+1) == c {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) isSlavoGermanic() bool {
+	s := string(m.s)
+	return strings.ContainsRune(s, 'W') || strings.ContainsRune(s, 'K') ||
+		strings.Contains(s, "CZ") || strings.Contains(s, "WITZ")
+}
+
+func (m *metaphone) germanic() bool {
+	return m.has(0, "VAN ", "VON ") || m.has(0, "SCH")
+}
+
+func (m *metaphone) handleC(i int) int {
+	switch {
+	case m.conditionC0(i):
+		m.add("K")
+		return i + 2
+	case i == 0 && m.has(i, "CAESAR"):
+		m.add("S")
+		return i + 2
+	case m.has(i, "CH"):
+		return m.handleCH(i)
+	case m.has(i, "CZ") && !m.has(i-2, "WICZ"):
+		// Czerny
+		m.add2("S", "X")
+		return i + 2
+	case m.has(i+1, "CIA"):
+		// focaccia
+		m.add("X")
+		return i + 3
+	case m.has(i, "CC") && !(i == 1 && m.at(0) == 'M'):
+		// double "cc" but not McClelland
+		return m.handleCC(i)
+	case m.has(i, "CK", "CG", "CQ"):
+		m.add("K")
+		return i + 2
+	case m.has(i, "CI", "CE", "CY"):
+		// Italian vs. English
+		if m.has(i, "CIO", "CIE", "CIA") {
+			m.add2("S", "X")
+		} else {
+			m.add("S")
+		}
+		return i + 2
+	}
+
+	m.add("K")
+	switch {
+	case m.has(i+1, " C", " Q", " G"):
+		// Mac Caffrey, Mac Gregor
+		return i + 3
+	case m.has(i+1, "C", "K", "Q") && !m.has(i+1, "CE", "CI"):
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) conditionC0(i int) bool {
+	if m.has(i, "CHIA") {
+		return true
+	}
+	if i <= 1 || isMetaphoneVowel(m.at(i-2)) || !m.has(i-1, "ACH") {
+		return false
+	}
+	c := m.at(i + 2)
+	return (c != 'I' && c != 'E') || m.has(i-2, "BACHER", "MACHER")
+}
+
+func (m *metaphone) handleCC(i int) int {
+	if m.has(i+2, "I", "E", "H") && !m.has(i+2, "HU") {
+		// bellocchio but not bacchus
+		if (i == 1 && m.at(i-1) == 'A') || m.has(i-1, "UCCEE", "UCCES") {
+			// accident, accede, succeed
+			m.add("KS")
+		} else {
+			// bacci, bertucci and other Italian
+			m.add("X")
+		}
+		return i + 3
+	}
+	m.add("K")
+	return i + 2
+}
+
+func (m *metaphone) handleCH(i int) int {
+	switch {
+	case i > 0 && m.has(i, "CHAE"):
+		// Michael
+		m.add2("K", "X")
+	case m.conditionCH0(i), m.conditionCH1(i):
+		// Greek roots and Germanic "kh"
+		m.add("K")
+	case i > 0 && m.has(0, "MC"):
+		m.add("K")
+	case i > 0:
+		m.add2("X", "K")
+	default:
+		m.add("X")
+	}
+	return i + 2
+}
+
+func (m *metaphone) conditionCH0(i int) bool {
+	if i != 0 {
+		return false
+	}
+	if !m.has(i+1, "HARAC", "HARIS") && !m.has(i+1, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !m.has(0, "CHORE")
+}
+
+func (m *metaphone) conditionCH1(i int) bool {
+	return m.germanic() ||
+		m.has(i-2, "ORCHES", "ARCHIT", "ORCHID") ||
+		m.has(i+2, "T", "S") ||
+		((m.has(i-1, "A", "O", "U", "E") || i == 0) &&
+			(m.has(i+2, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || i+1 == m.last()))
+}
+
+func (m *metaphone) handleD(i int) int {
+	switch {
+	case m.has(i, "DG"):
+		if m.has(i+2, "I", "E", "Y") {
+			// edge
+			m.add("J")
+			return i + 3
+		}
+		// edgar
+		m.add("TK")
+		return i + 2
+	case m.has(i, "DT", "DD"):
+		m.add("T")
+		return i + 2
+	}
+	m.add("T")
+	return i + 1
+}
+
+func (m *metaphone) handleG(i int) int {
+	switch {
+	case m.at(i+1) == 'H':
+		return m.handleGH(i)
+	case m.at(i+1) == 'N':
+		switch {
+		case i == 1 && isMetaphoneVowel(m.at(0)) && !m.slavoGermanic:
+			m.add2("KN", "N")
+		case !m.has(i+2, "EY") && m.at(i+1) != 'Y' && !m.slavoGermanic:
+			m.add2("N", "KN")
+		default:
+			m.add("KN")
+		}
+		return i + 2
+	case m.has(i+1, "LI") && !m.slavoGermanic:
+		m.add2("KL", "L")
+		return i + 2
+	case i == 0 && (m.at(i+1) == 'Y' || m.has(i+1, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		// -ges-, -gep-, -gel-, -gie- at the start
+		m.add2("K", "J")
+		return i + 2
+	case (m.has(i+1, "ER") || m.at(i+1) == 'Y') &&
+		!m.has(0, "DANGER", "RANGER", "MANGER") &&
+		!m.has(i-1, "E", "I") && !m.has(i-1, "RGY", "OGY"):
+		// -ger-, -gy-
+		m.add2("K", "J")
+		return i + 2
+	case m.has(i+1, "E", "I", "Y") || m.has(i-1, "AGGI", "OGGI"):
+		// Italian biaggi
+		switch {
+		case m.germanic() || m.has(i+1, "ET"):
+			m.add("K")
+		case m.has(i+1, "IER"):
+			m.add("J")
+		default:
+			m.add2("J", "K")
+		}
+		return i + 2
+	case m.at(i+1) == 'G':
+		m.add("K")
+		return i + 2
+	}
+	m.add("K")
+	return i + 1
+}
+
+func (m *metaphone) handleGH(i int) int {
+	switch {
+	case i > 0 && !isMetaphoneVowel(m.at(i-1)):
+		m.add("K")
+	case i == 0:
+		if m.at(i+2) == 'I' {
+			m.add("J")
+		} else {
+			m.add("K")
+		}
+	case (i > 1 && m.has(i-2, "B", "H", "D")) ||
+		(i > 2 && m.has(i-3, "B", "H", "D")) ||
+		(i > 3 && m.has(i-4, "B", "H")):
+		// Parker's rule: hugh
+	case i > 2 && m.at(i-1) == 'U' && m.has(i-3, "C", "G", "L", "R", "T"):
+		// laugh, McLaughlin, cough, rough, tough
+		m.primary.WriteString("F")
+	case m.at(i-1) != 'I':
+		m.add("K")
+	}
+	return i + 2
+}
+
+func (m *metaphone) handleH(i int) int {
+	// Only kept when first or between vowels
+	if (i == 0 || isMetaphoneVowel(m.at(i-1))) && isMetaphoneVowel(m.at(i+1)) {
+		m.add("H")
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) handleJ(i int) int {
+	if m.has(i, "JOSE") || m.has(0, "SAN ") {
+		// Spanish: Jose, San Jacinto
+		if (i == 0 && m.at(i+4) == ' ') || len(m.s) == 4 || m.has(0, "SAN ") {
+			m.add("H")
+		} else {
+			m.add2("J", "H")
+		}
+		return i + 1
+	}
+
+	switch {
+	case i == 0:
+		m.add2("J", "A")
+	case isMetaphoneVowel(m.at(i-1)) && !m.slavoGermanic && (m.at(i+1) == 'A' || m.at(i+1) == 'O'):
+		m.add2("J", "H")
+	case i == m.last():
+		m.add2("J", "")
+	case !m.has(i+1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.has(i-1, "S", "K", "L"):
+		m.add("J")
+	}
+	return m.skipDouble(i, 'J')
+}
+
+func (m *metaphone) handleL(i int) int {
+	if m.at(i+1) != 'L' {
+		m.add("L")
+		return i + 1
+	}
+	// Spanish -illo, -illa, -alle are silent in the alternate
+	if (i == len(m.s)-3 && m.has(i-1, "ILLO", "ILLA", "ALLE")) ||
+		((m.has(len(m.s)-2, "AS", "OS") || m.has(m.last(), "A", "O")) && m.has(i-1, "ALLE")) {
+		m.primary.WriteString("L")
+	} else {
+		m.add("L")
+	}
+	return i + 2
+}
+
+func (m *metaphone) conditionM0(i int) bool {
+	if m.at(i+1) == 'M' {
+		return true
+	}
+	// dumb, thumb
+	return m.has(i-1, "UMB") && (i+1 == m.last() || m.has(i+2, "ER"))
+}
+
+func (m *metaphone) handleP(i int) int {
+	if m.at(i+1) == 'H' {
+		m.add("F")
+		return i + 2
+	}
+	m.add("P")
+	if m.has(i+1, "P", "B") {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) handleR(i int) int {
+	// French: Rogier is silent in the primary
+	if i == m.last() && !m.slavoGermanic && m.has(i-2, "IE") && !m.has(i-4, "ME", "MA") {
+		m.alternate.WriteString("R")
+	} else {
+		m.add("R")
+	}
+	return m.skipDouble(i, 'R')
+}
+
+func (m *metaphone) handleS(i int) int {
+	switch {
+	case m.has(i-1, "ISL", "YSL"):
+		// island, isle, carlisle
+		return i + 1
+	case i == 0 && m.has(i, "SUGAR"):
+		m.add2("X", "S")
+		return i + 1
+	case m.has(i, "SH"):
+		if m.has(i+1, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			// Germanic
+			m.add("S")
+		} else {
+			m.add("X")
+		}
+		return i + 2
+	case m.has(i, "SIO", "SIA") || m.has(i, "SIAN"):
+		// Italian and Armenian
+		if m.slavoGermanic {
+			m.add("S")
+		} else {
+			m.add2("S", "X")
+		}
+		return i + 3
+	case (i == 0 && m.has(i+1, "M", "N", "L", "W")) || m.has(i+1, "Z"):
+		// smith matches schmidt, snider matches schneider; Slavic -sz-
+		m.add2("S", "X")
+		if m.has(i+1, "Z") {
+			return i + 2
+		}
+		return i + 1
+	case m.has(i, "SC"):
+		return m.handleSC(i)
+	}
+
+	if i == m.last() && m.has(i-2, "AI", "OI") {
+		// French: resnais, artois
+		m.alternate.WriteString("S")
+	} else {
+		m.add("S")
+	}
+	if m.has(i+1, "S", "Z") {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) handleSC(i int) int {
+	switch {
+	case m.at(i+2) == 'H':
+		// Schlesinger's rule
+		switch {
+		case m.has(i+3, "ER", "EN"):
+			// schermerhorn, schenker
+			m.add2("X", "SK")
+		case m.has(i+3, "OO", "UY", "ED", "EM"):
+			// Dutch: school, schooner
+			m.add("SK")
+		case i == 0 && !isMetaphoneVowel(m.at(3)) && m.at(3) != 'W':
+			m.add2("X", "S")
+		default:
+			m.add("X")
+		}
+	case m.has(i+2, "I", "E", "Y"):
+		m.add("S")
+	default:
+		m.add("SK")
+	}
+	return i + 3
+}
+
+func (m *metaphone) handleT(i int) int {
+	switch {
+	case m.has(i, "TION"), m.has(i, "TIA", "TCH"):
+		m.add("X")
+		return i + 3
+	case m.has(i, "TH") || m.has(i, "TTH"):
+		if m.has(i+2, "OM", "AM") || m.germanic() {
+			// thomas, thames
+			m.add("T")
+		} else {
+			m.add2("0", "T")
+		}
+		return i + 2
+	}
+	m.add("T")
+	if m.has(i+1, "T", "D") {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) handleW(i int) int {
+	switch {
+	case m.has(i, "WR"):
+		m.add("R")
+		return i + 2
+	case i == 0 && (isMetaphoneVowel(m.at(i+1)) || m.has(i, "WH")):
+		if isMetaphoneVowel(m.at(i + 1)) {
+			// Wasserman matches Vasserman
+			m.add2("A", "F")
+		} else {
+			// Uomo matches Womo
+			m.add("A")
+		}
+		return i + 1
+	case (i == m.last() && isMetaphoneVowel(m.at(i-1))) ||
+		m.has(i-1, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.has(0, "SCH"):
+		// Arnow matches Arnoff
+		m.alternate.WriteString("F")
+		return i + 1
+	case m.has(i, "WICZ", "WITZ"):
+		// Polish: filipowicz
+		m.add2("TS", "FX")
+		return i + 4
+	}
+	return i + 1
+}
+
+func (m *metaphone) handleX(i int) int {
+	if i == 0 {
+		m.add("S")
+		return i + 1
+	}
+	// French: breaux
+	if !(i == m.last() && (m.has(i-3, "IAU", "EAU") || m.has(i-2, "AU", "OU"))) {
+		m.add("KS")
+	}
+	if m.has(i+1, "C", "X") {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) handleZ(i int) int {
+	if m.at(i+1) == 'H' {
+		// Chinese pinyin: zhao
+		m.add("J")
+		return i + 2
+	}
+	if m.has(i+1, "ZO", "ZI", "ZA") || (m.slavoGermanic && i > 0 && m.at(i-1) != 'T') {
+		m.add2("S", "TS")
+	} else {
+		m.add("S")
+	}
+	return m.skipDouble(i, 'Z')
+}
+
+func isMetaphoneVowel(r rune) bool {
+	switch unicode.ToUpper(r) {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		return true
+	}
+	return false
+}
+
+func truncate(code string) string {
+	if len(code) > metaphoneCodeLen {
+		return code[:metaphoneCodeLen]
+	}
+	return code
+}
@@ -0,0 +1,211 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Transliterate romanizes Cyrillic, Greek, Arabic, Japanese kana and Korean
+// Hangul letters so names can be compared with their Latin spellings:
+// "Владимир" becomes "vladimir" and "김정은" becomes "gimjeongeun".
+// Transliterated letters are lower case; other characters, including Latin
+// text and Chinese ideographs, which need a reading dictionary, are kept
+// unchanged. Arabic is written without short vowels, so its romanization
+// is consonantal and relies on fuzzy matching to bridge the gaps.
+func Transliterate(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	var kana kanaWriter
+	for _, r := range s {
+		if isKana(r) {
+			kana.write(&b, r)
+			continue
+		}
+		kana.flush(&b)
+
+		if r >= hangulBase && r <= hangulLast {
+			writeHangul(&b, r)
+		} else if latin, ok := scriptLetters[unicode.ToLower(r)]; ok {
+			b.WriteString(latin)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	kana.flush(&b)
+	return b.String()
+}
+
+// scriptLetters maps lower-case Cyrillic, Greek and Arabic letters to Latin
+var scriptLetters = map[rune]string{
+	// Cyrillic (Russian, Ukrainian, Belarusian, Serbian, Macedonian)
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+	'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+	'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o",
+	'ϊ': "i", 'ϋ': "y", 'ΐ': "i", 'ΰ': "y",
+
+	// Arabic and Persian
+	'ا': "a", 'أ': "a", 'إ': "i", 'آ': "a", 'ٱ': "a", 'ء': "", 'ؤ': "", 'ئ': "",
+	'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h", 'خ': "kh", 'د': "d",
+	'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "d",
+	'ط': "t", 'ظ': "z", 'ع': "", 'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k",
+	'ل': "l", 'م': "m", 'ن': "n", 'ه': "h", 'ة': "a", 'و': "w", 'ي': "y",
+	'ى': "a", 'پ': "p", 'چ': "ch", 'ژ': "zh", 'گ': "g", 'ک': "k", 'ی': "y",
+}
+
+// Hangul syllables are composed arithmetically from an initial consonant,
+// a vowel and an optional final consonant
+const (
+	hangulBase   = 0xAC00
+	hangulLast   = 0xD7A3
+	hangulVowels = 21
+	hangulFinals = 28
+)
+
+// Revised Romanization of Korean, indexed by jamo position
+var (
+	hangulInitials = [...]string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulMedials  = [...]string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulCodas    = [...]string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+// writeHangul romanizes a precomposed Hangul syllable
+func writeHangul(b *strings.Builder, r rune) {
+	s := int(r - hangulBase)
+	b.WriteString(hangulInitials[s/(hangulVowels*hangulFinals)])
+	b.WriteString(hangulMedials[(s%(hangulVowels*hangulFinals))/hangulFinals])
+	b.WriteString(hangulCodas[s%hangulFinals])
+}
+
+// Kana code points. Katakana is romanized through the hiragana table by
+// shifting it onto the hiragana block.
+const (
+	hiraganaFirst = 0x3041
+	hiraganaLast  = 0x3096
+	katakanaFirst = 0x30A1
+	katakanaLast  = 0x30F6
+	katakanaShift = katakanaFirst - hiraganaFirst
+	kanaMiddleDot = '・'
+	kanaLongVowel = 'ー'
+	kanaSmallTsu  = 'っ'
+)
+
+// isKana reports whether r is romanized by kanaWriter
+func isKana(r rune) bool {
+	return (r >= hiraganaFirst && r <= hiraganaLast) || (r >= katakanaFirst && r <= katakanaLast) ||
+		r == kanaMiddleDot || r == kanaLongVowel
+}
+
+// kanaSyllables maps hiragana to Hepburn romanization
+var kanaSyllables = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n", 'ゔ': "vu",
+	'ゕ': "ka", 'ゖ': "ke",
+}
+
+// kanaSmallY are the small y-kana that combine with an i-row syllable,
+// as in きゃ "kya" and しゃ "sha"
+var kanaSmallY = map[rune]string{'ゃ': "a", 'ゅ': "u", 'ょ': "o"}
+
+// kanaSmallVowels are the small vowels katakana uses for foreign sounds,
+// as in ファ "fa" and ティ "ti"
+var kanaSmallVowels = map[rune]string{'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o"}
+
+// kanaWriter romanizes a run of kana. The latest syllable is held back
+// until the next character so small kana can modify it.
+type kanaWriter struct {
+	pending  string // romanization of the latest syllable, not yet written
+	geminate bool   // a small tsu is waiting for the next syllable
+}
+
+// flush writes the held syllable and ends the run
+func (k *kanaWriter) flush(b *strings.Builder) {
+	b.WriteString(k.pending)
+	k.pending, k.geminate = "", false
+}
+
+func (k *kanaWriter) write(b *strings.Builder, r rune) {
+	if r >= katakanaFirst && r <= katakanaLast {
+		r -= katakanaShift
+	}
+
+	switch r {
+	case kanaMiddleDot:
+		k.flush(b)
+		b.WriteByte(' ')
+		return
+	case kanaLongVowel:
+		// The lengthened vowel is dropped, as most Latin spellings do
+		return
+	case kanaSmallTsu:
+		k.geminate = true
+		return
+	}
+
+	if vowel, ok := kanaSmallY[r]; ok {
+		k.combine(vowel, true)
+		return
+	}
+	if vowel, ok := kanaSmallVowels[r]; ok {
+		k.combine(vowel, false)
+		return
+	}
+
+	syllable, ok := kanaSyllables[r]
+	if !ok {
+		k.flush(b)
+		b.WriteRune(r)
+		return
+	}
+	b.WriteString(k.pending)
+	if k.geminate && !strings.ContainsRune("aiueon", rune(syllable[0])) {
+		syllable = syllable[:1] + syllable
+	}
+	k.pending, k.geminate = syllable, false
+}
+
+// combine replaces the vowel of the held syllable with a small kana's
+// vowel. With yGlide the syllable must end in "i", which becomes a "y"
+// glide unless the consonant already carries one (shi, chi, ji). A small
+// kana with nothing to combine with is read as a full syllable.
+func (k *kanaWriter) combine(vowel string, yGlide bool) {
+	p := k.pending
+	if len(p) < 2 || (yGlide && !strings.HasSuffix(p, "i")) {
+		if yGlide {
+			vowel = "y" + vowel
+		}
+		k.pending += vowel
+		return
+	}
+
+	stem := p[:len(p)-1]
+	if yGlide && !strings.HasSuffix(stem, "sh") && !strings.HasSuffix(stem, "ch") && !strings.HasSuffix(stem, "j") {
+		stem += "y"
+	}
+	k.pending = stem + vowel
+}
@@ -64,6 +64,12 @@ func New(serviceName, environment string, debug bool) (*Logger, error) {
 	}, nil
 }
 
+// NewNop returns a logger that discards everything, for tests and tools
+// that have nowhere to log
+func NewNop() *Logger {
+	return &Logger{Logger: zap.NewNop()}
+}
+
 // Named returns a named sub-logger
 func (l *Logger) Named(name string) *Logger {
 	return &Logger{
@@ -185,6 +191,15 @@ func (l *Logger) InvestigationCreated(investigationID, caseNumber, userID string
 	)
 }
 
+// InvestigationEscalated logs an investigation escalation
+func (l *Logger) InvestigationEscalated(investigationID, caseNumber, reason string) {
+	l.Warn("investigation escalated",
+		zap.String("investigation_id", investigationID),
+		zap.String("case_number", caseNumber),
+		zap.String("reason", reason),
+	)
+}
+
 // SARFiled logs SAR filing
 func (l *Logger) SARFiled(filingID, filingNumber, userID string) {
 	l.Info("sar filed",
@@ -245,6 +260,11 @@ func IntField(key string, value int) zap.Field {
 	return zap.Int(key, value)
 }
 
+// Int64Field creates an int64 field
+func Int64Field(key string, value int64) zap.Field {
+	return zap.Int64(key, value)
+}
+
 // Float64Field creates a float64 field
 func Float64Field(key string, value float64) zap.Field {
 	return zap.Float64(key, value)
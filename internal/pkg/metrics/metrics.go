@@ -0,0 +1,296 @@
+// Package metrics defines the service's Prometheus collectors and the
+// handler that exposes them
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "aml"
+
+// Registry holds every collector served on /metrics. A dedicated registry
+// keeps metrics from imported libraries out unless added here.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+// Screening latency buckets in seconds, centred on the 200ms budget
+var screeningBuckets = []float64{.005, .01, .025, .05, .075, .1, .15, .2, .3, .5, 1, 2.5}
+
+// Per-check buckets in seconds; cached checks should land well under 5ms
+var checkBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .2, .5}
+
+// Queue wait buckets in seconds, from an idle queue to a backfill backlog
+var queueWaitBuckets = []float64{.001, .01, .05, .1, .5, 1, 5, 30, 120, 600}
+
+var (
+	screeningDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "screening",
+		Name:      "duration_seconds",
+		Help:      "End-to-end transaction screening latency by tenant and decision.",
+		Buckets:   screeningBuckets,
+	}, []string{"tenant", "decision"})
+
+	screeningDecisions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "screening",
+		Name:      "decisions_total",
+		Help:      "Screening decisions by tenant and decision type.",
+	}, []string{"tenant", "decision"})
+
+	checkDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "screening",
+		Name:      "check_duration_seconds",
+		Help:      "Latency of individual screening checks.",
+		Buckets:   checkBuckets,
+	}, []string{"check"})
+
+	ofacHits = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ofac",
+		Name:      "hits_total",
+		Help:      "OFAC sanctions list hits by match type.",
+	}, []string{"match_type"})
+
+	ofacDesignationsRemoved = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ofac",
+		Name:      "designations_removed_total",
+		Help:      "OFAC designations dropped from the list, counted on each index reload.",
+	})
+
+	ofacListsRejected = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ofac",
+		Name:      "lists_rejected_total",
+		Help:      "OFAC list reloads refused because more entities changed than the sanity threshold allows; the previous index stays in use.",
+	})
+
+	pepHits = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pep",
+		Name:      "hits_total",
+		Help:      "PEP list hits by match type.",
+	}, []string{"match_type"})
+
+	patternDetections = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "patterns",
+		Name:      "detections_total",
+		Help:      "Suspicious pattern detections by pattern type.",
+	}, []string{"pattern_type"})
+
+	consumerLag = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "consumer_lag",
+		Help:      "Messages behind the partition high watermark.",
+	}, []string{"topic", "partition"})
+
+	investigationSLA = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "investigations",
+		Name:      "sla_escalations_total",
+		Help:      "Investigations escalated by the SLA monitor by state (at_risk or breached).",
+	}, []string{"state"})
+
+	checkTimeouts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "screening",
+		Name:      "check_timeouts_total",
+		Help:      "Screening checks skipped because they exceeded their timeout, by check.",
+	}, []string{"check"})
+
+	cacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Cache lookups by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	shadowDisagreements = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "screening",
+		Name:      "shadow_disagreements_total",
+		Help:      "Screenings whose shadow decision differs from the enforced one, by enforced and shadow decision.",
+	}, []string{"decision", "shadow_decision"})
+
+	webhookDeliveries = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Webhook notifications by subscriber and result (delivered, retried, dead_lettered, dropped).",
+	}, []string{"subscriber", "result"})
+
+	notifications = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notification",
+		Name:      "messages_total",
+		Help:      "Chat notifications by event and result (sent, retried, failed, duplicate, quiet_hours, dropped).",
+	}, []string{"event", "result"})
+
+	digests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "email",
+		Name:      "digests_total",
+		Help:      "Analyst digests by result (sent, empty, failed).",
+	}, []string{"result"})
+
+	kafkaMessages = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_total",
+		Help:      "Consumed messages by topic and outcome (processed, retried, dead_lettered) and error code.",
+	}, []string{"topic", "outcome", "code"})
+
+	kafkaQueueWait = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "queue_wait_seconds",
+		Help:      "Time consumed messages waited for a worker, by priority.",
+		Buckets:   queueWaitBuckets,
+	}, []string{"priority"})
+
+	ofacCacheFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ofac",
+		Name:      "cache_failures_total",
+		Help:      "Failed OFAC cache name lookups by outcome (retried, index, failed).",
+	}, []string{"outcome"})
+
+	grpcDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC request latency by method and status code.",
+		Buckets:   screeningBuckets,
+	}, []string{"method", "code"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveScreening records a completed screening for a tenant and its
+// decision
+func ObserveScreening(tenant, decision string, d time.Duration) {
+	screeningDuration.WithLabelValues(tenant, decision).Observe(d.Seconds())
+	screeningDecisions.WithLabelValues(tenant, decision).Inc()
+}
+
+// ObserveCheck records the latency of one screening check
+func ObserveCheck(check string, d time.Duration) {
+	checkDuration.WithLabelValues(check).Observe(d.Seconds())
+}
+
+// RecordCheckTimeout counts a screening check skipped on timeout
+func RecordCheckTimeout(check string) {
+	checkTimeouts.WithLabelValues(check).Inc()
+}
+
+// RecordOFACHit counts an OFAC match
+func RecordOFACHit(matchType string) {
+	ofacHits.WithLabelValues(matchType).Inc()
+}
+
+// RecordOFACDesignationsRemoved counts designations dropped from the OFAC
+// list
+func RecordOFACDesignationsRemoved(n int) {
+	ofacDesignationsRemoved.Add(float64(n))
+}
+
+// RecordOFACListRejected counts an OFAC list reload refused by the sanity
+// threshold
+func RecordOFACListRejected() {
+	ofacListsRejected.Inc()
+}
+
+// RecordOFACCacheFailure counts a failed OFAC cache lookup: retried, served
+// from the in-memory index, or failed with the index unavailable too
+func RecordOFACCacheFailure(outcome string) {
+	ofacCacheFailures.WithLabelValues(outcome).Inc()
+}
+
+// RecordPEPHit counts a PEP match
+func RecordPEPHit(matchType string) {
+	pepHits.WithLabelValues(matchType).Inc()
+}
+
+// RecordPatternDetection counts a detected pattern
+func RecordPatternDetection(patternType string) {
+	patternDetections.WithLabelValues(patternType).Inc()
+}
+
+// SetConsumerLag reports how far a consumer is behind on a partition
+func SetConsumerLag(topic string, partition int, lag int64) {
+	consumerLag.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(lag))
+}
+
+// RecordSLAEscalation counts an investigation flagged as at risk or breached
+func RecordSLAEscalation(state string) {
+	investigationSLA.WithLabelValues(state).Inc()
+}
+
+// ObserveGRPC records a completed gRPC request
+func ObserveGRPC(method, code string, d time.Duration) {
+	grpcDuration.WithLabelValues(method, code).Observe(d.Seconds())
+}
+
+// RecordShadowDisagreement counts a screening whose shadow decision differs
+// from the enforced decision
+func RecordShadowDisagreement(decision, shadowDecision string) {
+	shadowDisagreements.WithLabelValues(decision, shadowDecision).Inc()
+}
+
+// RecordWebhookDelivery counts a webhook delivery attempt outcome
+func RecordWebhookDelivery(subscriber, result string) {
+	webhookDeliveries.WithLabelValues(subscriber, result).Inc()
+}
+
+// RecordNotification counts a chat notification outcome
+func RecordNotification(event, result string) {
+	notifications.WithLabelValues(event, result).Inc()
+}
+
+// RecordDigest counts an analyst digest outcome
+func RecordDigest(result string) {
+	digests.WithLabelValues(result).Inc()
+}
+
+// RecordKafkaMessage counts a consumed message outcome. code is the
+// apperr code of the failure, empty for processed messages.
+func RecordKafkaMessage(topic, outcome, code string) {
+	kafkaMessages.WithLabelValues(topic, outcome, code).Inc()
+}
+
+// ObserveKafkaQueueWait records how long a consumed message waited for a
+// worker
+func ObserveKafkaQueueWait(priority string, d time.Duration) {
+	kafkaQueueWait.WithLabelValues(priority).Observe(d.Seconds())
+}
+
+// RecordCacheLookup counts a cache hit or miss
+func RecordCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,217 @@
+// Package money provides an exact fixed-point type for monetary amounts, so
+// sums and threshold comparisons are free of binary floating-point error
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places an Amount holds, the largest minor
+// unit exponent in ISO 4217
+const Scale = 4
+
+// scaleFactor is 10^Scale
+const scaleFactor = 10000
+
+// maxWhole is the largest whole-unit part an Amount can hold
+const maxWhole = math.MaxInt64 / scaleFactor
+
+// Amount is a monetary amount in units of 10^-Scale. It is an integer, so
+// amounts add, subtract and compare exactly with the usual operators. It
+// marshals to a JSON number in its exact decimal form and scans from
+// NUMERIC columns.
+type Amount int64
+
+// FromFloat converts a float to the nearest Amount, rounding half away
+// from zero
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * scaleFactor))
+}
+
+// Parse parses a decimal string such as "-1234.56". Digits beyond Scale
+// decimal places are rounded half away from zero. Exponent notation is
+// accepted for compatibility with JSON producers that emit it.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) > maxWhole {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+		return FromFloat(f), nil
+	}
+
+	digits := s
+	neg := false
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		neg = digits[0] == '-'
+		digits = digits[1:]
+	}
+
+	wholePart, fracPart, _ := strings.Cut(digits, ".")
+	if (wholePart == "" && fracPart == "") || !isDigits(wholePart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	var whole int64
+	if wholePart != "" {
+		var err error
+		if whole, err = strconv.ParseInt(wholePart, 10, 64); err != nil || whole > maxWhole {
+			return 0, fmt.Errorf("amount %q out of range", s)
+		}
+	}
+
+	roundUp := len(fracPart) > Scale && fracPart[Scale] >= '5'
+	if len(fracPart) > Scale {
+		fracPart = fracPart[:Scale]
+	}
+	frac, _ := strconv.ParseInt(fracPart+strings.Repeat("0", Scale-len(fracPart)), 10, 64)
+
+	v := whole*scaleFactor + frac
+	if roundUp {
+		v++
+	}
+	if neg {
+		v = -v
+	}
+	return Amount(v), nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Float64 returns the amount as a float, for statistics and metrics that do
+// not need exact values
+func (a Amount) Float64() float64 {
+	return float64(a) / scaleFactor
+}
+
+// Int64 returns the whole units of the amount, truncated toward zero
+func (a Amount) Int64() int64 {
+	return int64(a) / scaleFactor
+}
+
+// Mul multiplies the amount by a factor such as an exchange rate, rounding
+// the result half away from zero
+func (a Amount) Mul(f float64) Amount {
+	return Amount(math.Round(float64(a) * f))
+}
+
+// Round rounds the amount to the given number of decimal places, half away
+// from zero
+func (a Amount) Round(places int) Amount {
+	if places >= Scale {
+		return a
+	}
+	if places < 0 {
+		places = 0
+	}
+	p := Amount(pow10(Scale - places))
+	q, r := a/p, a%p
+	if r >= p-r {
+		q++
+	} else if -r >= p+r {
+		q--
+	}
+	return q * p
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// String returns the exact decimal form without trailing zeros, such as
+// "1234.5"
+func (a Amount) String() string {
+	s := a.StringFixed(Scale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// StringFixed returns the amount rounded to the given number of decimal
+// places, such as "1234.50" for two
+func (a Amount) StringFixed(places int) string {
+	places = max(0, min(places, Scale))
+	r := int64(a.Round(places))
+
+	sign := ""
+	u := uint64(r)
+	if r < 0 {
+		sign, u = "-", uint64(-r)
+	}
+
+	whole := strconv.FormatUint(u/scaleFactor, 10)
+	if places == 0 {
+		return sign + whole
+	}
+	frac := fmt.Sprintf("%04d", u%scaleFactor)
+	return sign + whole + "." + frac[:places]
+}
+
+// MarshalJSON encodes the amount as a JSON number in its exact decimal form
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a string holding one; null leaves
+// the amount unchanged
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	v, err := Parse(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value stores the amount as its exact decimal string, which NUMERIC
+// columns accept without loss
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads a NUMERIC, integer or float column. NULL scans as zero.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		*a = Amount(v * scaleFactor)
+	case float64:
+		*a = FromFloat(v)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+	return nil
+}
+
+func (a *Amount) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package stats provides the classic and outlier-resistant summary
+// statistics used for behavioural baselines
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+// madScale makes the median absolute deviation a consistent estimator of
+// the standard deviation for normally distributed data
+const madScale = 1.4826
+
+// meanADScale does the same for the mean absolute deviation around the median
+const meanADScale = 1.2533
+
+// Mean returns the arithmetic mean of xs, or 0 when xs is empty
+func Mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// StdDev returns the population standard deviation of xs
+func StdDev(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	mean := Mean(xs)
+	var variance float64
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+	return math.Sqrt(variance / float64(len(xs)))
+}
+
+// Median returns the median of xs, or 0 when xs is empty
+func Median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// TrimmedMean returns the mean of xs after dropping the trim share of
+// values from each end, rounded up so a single extreme value is dropped
+// from even a short series. At least one value is always kept.
+func TrimmedMean(xs []float64, trim float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+
+	k := int(math.Ceil(trim * float64(len(sorted))))
+	k = min(k, (len(sorted)-1)/2)
+	return Mean(sorted[k : len(sorted)-k])
+}
+
+// RobustStdDev estimates the standard deviation of xs from the median
+// absolute deviation, which a few extreme values barely move. When more
+// than half the values are equal the MAD is zero, so the trimmed mean of
+// the absolute deviations around the median is used instead, dropping the
+// trim share of deviations from each end.
+func RobustStdDev(xs []float64, trim float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	median := Median(xs)
+	deviations := make([]float64, len(xs))
+	for i, x := range xs {
+		deviations[i] = math.Abs(x - median)
+	}
+
+	if mad := Median(deviations); mad > 0 {
+		return madScale * mad
+	}
+	return meanADScale * TrimmedMean(deviations, trim)
+}
@@ -0,0 +1,40 @@
+// Package tenant carries the tenant a request acts for through its context.
+// Every tenant's screenings, alerts, investigations and filings are kept
+// apart; repositories scope their queries to the tenant in the context.
+package tenant
+
+import "context"
+
+// Default is the tenant of requests that name none, and of every record
+// written before tenants were introduced
+const Default = "default"
+
+// Where callers name the tenant they act for
+const (
+	Header      = "X-Tenant-ID" // HTTP request header
+	KafkaHeader = "x-tenant-id" // Kafka message header
+	GRPCKey     = "x-tenant-id" // gRPC metadata key
+	Claim       = "tenant_id"   // JWT claim
+)
+
+type tenantKey struct{}
+
+// WithID returns a context acting for the tenant
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant ctx acts for; ok is false for a context
+// that names none, such as a background job's, which acts for every tenant
+func FromContext(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// OrDefault returns the tenant ctx acts for, or Default if it names none
+func OrDefault(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return Default
+}
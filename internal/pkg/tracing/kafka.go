@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const kafkaTracerName = "github.com/banking/aml-service/internal/pkg/tracing/kafka"
+
+// KafkaMessage is the part of a consumed record the interceptor needs
+type KafkaMessage struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       string
+	Value     []byte
+	Headers   map[string]string
+}
+
+// MessageHandler processes a consumed Kafka message
+type MessageHandler func(ctx context.Context, msg KafkaMessage) error
+
+// ConsumerInterceptor wraps a message handler in a consumer span that
+// continues the producer's trace from the message headers. A message
+// carrying ForceSampleHeader is always sampled.
+func ConsumerInterceptor(next MessageHandler) MessageHandler {
+	tracer := otel.Tracer(kafkaTracerName)
+
+	return func(ctx context.Context, msg KafkaMessage) error {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Headers))
+		if force, _ := strconv.ParseBool(msg.Headers[ForceSampleHeader]); force {
+			ctx = WithForceSample(ctx)
+		}
+
+		ctx, span := tracer.Start(ctx, msg.Topic+" process",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.destination.name", msg.Topic),
+				attribute.Int("messaging.kafka.destination.partition", msg.Partition),
+				attribute.Int64("messaging.kafka.message.offset", msg.Offset),
+				attribute.String("messaging.kafka.message.key", msg.Key),
+			),
+		)
+		defer span.End()
+
+		if err := next(WithLogContext(ctx), msg); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+// Package tracing sets up the OpenTelemetry tracer provider and the helpers
+// that carry trace context across HTTP, Kafka and log lines
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// ForceSampleHeader forces a request to be traced regardless of the
+// sampling ratio; set it to "true" when debugging a specific transaction
+const ForceSampleHeader = "X-Force-Sample"
+
+// Init installs a global tracer provider exporting over OTLP/gRPC and
+// returns a function that flushes and stops it
+func Init(ctx context.Context, cfg *config.TelemetryConfig) (func(context.Context) error, error) {
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.DeploymentEnvironment(cfg.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(newForceSampler(cfg.SamplingRatio)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+type forceSampleKey struct{}
+
+// WithForceSample marks ctx so the next root span is always sampled
+func WithForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleKey{}, true)
+}
+
+func isForceSampled(ctx context.Context) bool {
+	force, _ := ctx.Value(forceSampleKey{}).(bool)
+	return force
+}
+
+// forceSampler follows the parent's sampling decision, sampling new traces
+// at a fixed ratio, unless the context asks for the span to be force-sampled
+type forceSampler struct {
+	ratio sdktrace.Sampler
+}
+
+func newForceSampler(ratio float64) sdktrace.Sampler {
+	return forceSampler{ratio: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))}
+}
+
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if isForceSampled(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.ratio.ShouldSample(p)
+}
+
+func (s forceSampler) Description() string {
+	return "ForceSampler{" + s.ratio.Description() + "}"
+}
+
+// WithLogContext stores the IDs of the span in ctx under the logger's
+// trace keys so Logger.WithContext correlates log lines with the trace
+func WithLogContext(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, logger.TraceIDKey, sc.TraceID().String())
+	return context.WithValue(ctx, logger.SpanIDKey, sc.SpanID().String())
+}
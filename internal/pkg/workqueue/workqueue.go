@@ -0,0 +1,115 @@
+// Package workqueue provides a bounded priority queue that shares its
+// consumers fairly between priority levels
+package workqueue
+
+import (
+	"context"
+	"sync"
+)
+
+// Queue holds items at a fixed number of priority levels, level 0 the most
+// urgent, and hands them out by weighted round robin: each round serves up
+// to its weight in items from every level, most urgent first, so a busy
+// urgent level delays the others without starving them. A level with
+// nothing waiting gives up its turn, so no consumer idles while any item
+// waits.
+//
+// Each level holds at most size items. Push blocks while its level is full,
+// so a flood at one level holds back only its own producers.
+type Queue[T any] struct {
+	weights []int
+	space   []chan struct{} // a token for each item held, per level
+	ready   chan struct{}   // a token for each item held, across levels
+
+	mu      sync.Mutex
+	items   [][]T
+	credits []int // items each level may still take this round
+}
+
+// New creates a queue with one level per weight. Weights below 1 are
+// treated as 1, and a size below 1 as 1.
+func New[T any](weights []int, size int) *Queue[T] {
+	size = max(size, 1)
+	q := &Queue[T]{
+		weights: make([]int, len(weights)),
+		space:   make([]chan struct{}, len(weights)),
+		ready:   make(chan struct{}, len(weights)*size),
+		items:   make([][]T, len(weights)),
+		credits: make([]int, len(weights)),
+	}
+	for i, w := range weights {
+		q.weights[i] = max(w, 1)
+		q.space[i] = make(chan struct{}, size)
+	}
+	copy(q.credits, q.weights)
+	return q
+}
+
+// Levels returns the number of priority levels
+func (q *Queue[T]) Levels() int {
+	return len(q.weights)
+}
+
+// Push adds an item at a level, waiting while the level is full. Levels
+// outside the queue's range are clamped to it. It returns the context's
+// error if ctx is done before there is room.
+func (q *Queue[T]) Push(ctx context.Context, level int, item T) error {
+	level = min(max(level, 0), len(q.weights)-1)
+
+	select {
+	case q.space[level] <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	q.mu.Lock()
+	q.items[level] = append(q.items[level], item)
+	q.mu.Unlock()
+
+	// Never blocks: ready has room for every item the levels can hold
+	q.ready <- struct{}{}
+	return nil
+}
+
+// Pop removes and returns the next item and its level, waiting until one
+// is queued. It returns the context's error if ctx is done first.
+func (q *Queue[T]) Pop(ctx context.Context) (T, int, error) {
+	var zero T
+	select {
+	case <-q.ready:
+	case <-ctx.Done():
+		return zero, 0, ctx.Err()
+	}
+
+	q.mu.Lock()
+	level := q.next()
+	item := q.items[level][0]
+	q.items[level][0] = zero
+	q.items[level] = q.items[level][1:]
+	q.mu.Unlock()
+
+	<-q.space[level]
+	return item, level, nil
+}
+
+// Len returns the number of items waiting at a level
+func (q *Queue[T]) Len(level int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items[level])
+}
+
+// next picks the level to serve and takes one of its credits, starting a
+// new round once every level with items has used its credits. At least
+// one level must hold an item.
+func (q *Queue[T]) next() int {
+	for {
+		for level := range q.items {
+			if q.credits[level] > 0 && len(q.items[level]) > 0 {
+				q.credits[level]--
+				return level
+			}
+		}
+		copy(q.credits, q.weights)
+	}
+}
@@ -0,0 +1,182 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/banking/aml-service/internal/pkg/apperr"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/pkg/tracing"
+)
+
+// Headers added to dead-lettered messages, alongside the original headers
+const (
+	deadLetterTopicHeader     = "x-dead-letter-topic"
+	deadLetterPartitionHeader = "x-dead-letter-partition"
+	deadLetterOffsetHeader    = "x-dead-letter-offset"
+	deadLetterAttemptsHeader  = "x-dead-letter-attempts"
+	deadLetterCodeHeader      = "x-dead-letter-error-code"
+	deadLetterErrorHeader     = "x-dead-letter-error"
+)
+
+// DeadLetterPublisher receives messages the handler could not process
+type DeadLetterPublisher interface {
+	PublishWithHeaders(ctx context.Context, key string, value []byte, headers map[string]string) error
+}
+
+// Consumer reads a topic as part of a consumer group
+type Consumer struct {
+	reader      *kafkago.Reader
+	deadLetters DeadLetterPublisher
+	maxAttempts int
+	backoff     time.Duration
+	log         *logger.Logger
+}
+
+// NewConsumer creates a consumer for topic in the given group. Failed
+// messages are retried up to maxAttempts times, backing off exponentially
+// from backoff, before they are dead-lettered.
+func NewConsumer(brokers []string, groupID, topic string, deadLetters DeadLetterPublisher, maxAttempts int, backoff time.Duration, log *logger.Logger) *Consumer {
+	return &Consumer{
+		reader: kafkago.NewReader(kafkago.ReaderConfig{
+			Brokers: brokers,
+			GroupID: groupID,
+			Topic:   topic,
+		}),
+		deadLetters: deadLetters,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		log:         log.Named("kafka_consumer"),
+	}
+}
+
+// Run hands each message to handler until ctx is cancelled. Offsets are
+// committed after the handler returns, so a message is redelivered if the
+// process dies mid-handling. A handler error is classified by its apperr
+// code: dependency outages and unclassified failures are retried, and a
+// message that still fails, or is rejected outright, is dead-lettered and
+// committed so one bad record cannot stall the partition.
+func (c *Consumer) Run(ctx context.Context, handler tracing.MessageHandler) error {
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return fmt.Errorf("fetch message: %w", err)
+		}
+
+		msg := kafkaMessage(m)
+		attempts, err := c.handle(ctx, handler, msg)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Left uncommitted, so the message is redelivered after restart
+				return nil
+			}
+			c.deadLetter(ctx, msg, attempts, err)
+		} else {
+			metrics.RecordKafkaMessage(m.Topic, "processed", "")
+		}
+
+		if err := c.reader.CommitMessages(ctx, m); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return fmt.Errorf("commit message: %w", err)
+		}
+	}
+}
+
+// kafkaMessage converts a fetched record to the form handlers take
+func kafkaMessage(m kafkago.Message) tracing.KafkaMessage {
+	headers := make(map[string]string, len(m.Headers))
+	for _, h := range m.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	return tracing.KafkaMessage{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Key:       string(m.Key),
+		Value:     m.Value,
+		Headers:   headers,
+	}
+}
+
+// handle runs handler on msg, retrying retryable failures with exponential
+// backoff, and returns the number of attempts made and the last error
+func (c *Consumer) handle(ctx context.Context, handler tracing.MessageHandler, msg tracing.KafkaMessage) (int, error) {
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, msg)
+		if err == nil || !apperr.Retryable(err) || attempt >= c.maxAttempts {
+			return attempt, err
+		}
+
+		metrics.RecordKafkaMessage(msg.Topic, "retried", string(apperr.CodeOf(err)))
+		c.log.Warn("retrying message",
+			logger.StringField("topic", msg.Topic),
+			logger.IntField("partition", msg.Partition),
+			logger.Int64Field("offset", msg.Offset),
+			logger.IntField("attempt", attempt),
+			logger.DurationField("backoff", backoff),
+			logger.ErrorField(err),
+		)
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// deadLetter publishes a message the handler could not process, with its
+// origin and the failure in headers. If publishing fails the message is
+// only logged; it can still be read from its original offset until the
+// topic's retention expires.
+func (c *Consumer) deadLetter(ctx context.Context, msg tracing.KafkaMessage, attempts int, cause error) {
+	code := apperr.CodeOf(cause)
+	metrics.RecordKafkaMessage(msg.Topic, "dead_lettered", string(code))
+
+	headers := make(map[string]string, len(msg.Headers)+6)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[deadLetterTopicHeader] = msg.Topic
+	headers[deadLetterPartitionHeader] = strconv.Itoa(msg.Partition)
+	headers[deadLetterOffsetHeader] = strconv.FormatInt(msg.Offset, 10)
+	headers[deadLetterAttemptsHeader] = strconv.Itoa(attempts)
+	headers[deadLetterCodeHeader] = string(code)
+	headers[deadLetterErrorHeader] = cause.Error()
+
+	c.log.Error("dead-lettering message",
+		logger.StringField("topic", msg.Topic),
+		logger.IntField("partition", msg.Partition),
+		logger.Int64Field("offset", msg.Offset),
+		logger.IntField("attempts", attempts),
+		logger.StringField("code", string(code)),
+		logger.ErrorField(cause),
+	)
+
+	if err := c.deadLetters.PublishWithHeaders(ctx, msg.Key, msg.Value, headers); err != nil {
+		c.log.Error("failed to dead-letter message; it remains at its original offset",
+			logger.StringField("topic", msg.Topic),
+			logger.IntField("partition", msg.Partition),
+			logger.Int64Field("offset", msg.Offset),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// Close leaves the consumer group and closes the reader
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
@@ -0,0 +1,27 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// Ping checks that at least one broker accepts connections
+func Ping(ctx context.Context, brokers []string) error {
+	var errs []error
+	for _, broker := range brokers {
+		conn, err := kafkago.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dial %s: %w", broker, err))
+			continue
+		}
+		conn.Close()
+		return nil
+	}
+	if len(errs) == 0 {
+		return errors.New("no brokers configured")
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,263 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/pkg/tracing"
+	"github.com/banking/aml-service/internal/pkg/workqueue"
+)
+
+// PriorityLevel is a level of a PriorityConsumer's queue. Weight is how
+// many of its messages each round of work takes.
+type PriorityLevel struct {
+	Name   string
+	Weight int
+}
+
+// PriorityTopic is a topic a PriorityConsumer reads. Its messages are
+// queued at Level, the index of a PriorityLevel, unless the classifier
+// places them at a more urgent one.
+type PriorityTopic struct {
+	Topic string
+	Level int
+}
+
+// Classifier returns the level a message asks for; ok is false for a
+// message that does not say
+type Classifier func(msg tracing.KafkaMessage) (level int, ok bool)
+
+// PriorityConsumer reads several topics as one consumer group and hands
+// their messages to a pool of workers, most urgent first. Each topic has
+// its own reader, so a backlog on one does not hold back messages on
+// another, and read-ahead messages wait in a queue whose levels share the
+// workers by weight, so urgent messages jump ahead of a backlog without
+// starving it.
+//
+// Messages finish out of order, so a partition's offset is committed only
+// up to the oldest message still being processed; a message is redelivered
+// if the process dies before it, or any message read before it, finishes.
+type PriorityConsumer struct {
+	consumers []*Consumer
+	topics    []PriorityTopic
+	levels    []PriorityLevel
+	classify  Classifier
+	queue     *workqueue.Queue[*queuedMessage]
+	workers   int
+	log       *logger.Logger
+}
+
+// queuedMessage is a fetched message waiting for a worker
+type queuedMessage struct {
+	msg      kafkago.Message
+	consumer *Consumer
+	offsets  *offsetTracker
+	queuedAt time.Time
+}
+
+// NewPriorityConsumer creates a consumer for topics in the given group.
+// queueSize bounds the messages read ahead at each level; workers handle
+// them concurrently. Failed messages are retried and dead-lettered as by
+// Consumer.
+func NewPriorityConsumer(
+	brokers []string,
+	groupID string,
+	topics []PriorityTopic,
+	levels []PriorityLevel,
+	classify Classifier,
+	queueSize, workers int,
+	deadLetters DeadLetterPublisher,
+	maxAttempts int,
+	backoff time.Duration,
+	log *logger.Logger,
+) *PriorityConsumer {
+	weights := make([]int, len(levels))
+	for i, level := range levels {
+		weights[i] = level.Weight
+	}
+
+	consumers := make([]*Consumer, len(topics))
+	for i, t := range topics {
+		consumers[i] = NewConsumer(brokers, groupID, t.Topic, deadLetters, maxAttempts, backoff, log)
+	}
+
+	return &PriorityConsumer{
+		consumers: consumers,
+		topics:    topics,
+		levels:    levels,
+		classify:  classify,
+		queue:     workqueue.New[*queuedMessage](weights, queueSize),
+		workers:   max(workers, 1),
+		log:       log.Named("kafka_priority_consumer"),
+	}
+}
+
+// Run reads every topic and hands each message to handler until ctx is
+// cancelled or a read fails
+func (p *PriorityConsumer) Run(ctx context.Context, handler tracing.MessageHandler) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for i, c := range p.consumers {
+		offsets := newOffsetTracker(c.reader)
+		level := p.topics[i].Level
+		g.Go(func() error {
+			return p.fetch(gctx, c, offsets, level)
+		})
+	}
+	for range p.workers {
+		g.Go(func() error {
+			return p.work(gctx, handler)
+		})
+	}
+
+	p.log.Info("priority consumer started",
+		logger.IntField("topics", len(p.topics)),
+		logger.IntField("workers", p.workers),
+	)
+	return g.Wait()
+}
+
+// fetch reads a topic into the queue, waiting while the level a message
+// belongs to is full
+func (p *PriorityConsumer) fetch(ctx context.Context, c *Consumer, offsets *offsetTracker, topicLevel int) error {
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return fmt.Errorf("fetch message: %w", err)
+		}
+
+		level := topicLevel
+		if classified, ok := p.classify(kafkaMessage(m)); ok && classified < level {
+			level = max(classified, 0)
+		}
+
+		offsets.fetched(m)
+		queued := &queuedMessage{msg: m, consumer: c, offsets: offsets, queuedAt: time.Now()}
+		if err := p.queue.Push(ctx, level, queued); err != nil {
+			// Left uncommitted, so the message is redelivered after restart
+			return nil
+		}
+	}
+}
+
+// work handles queued messages until ctx is cancelled, committing each
+// partition as far as its messages have finished
+func (p *PriorityConsumer) work(ctx context.Context, handler tracing.MessageHandler) error {
+	for {
+		queued, level, err := p.queue.Pop(ctx)
+		if err != nil {
+			return nil
+		}
+		metrics.ObserveKafkaQueueWait(p.levels[level].Name, time.Since(queued.queuedAt))
+
+		msg := kafkaMessage(queued.msg)
+		attempts, err := queued.consumer.handle(ctx, handler, msg)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Left uncommitted, so the message is redelivered after restart
+				return nil
+			}
+			queued.consumer.deadLetter(ctx, msg, attempts, err)
+		} else {
+			metrics.RecordKafkaMessage(msg.Topic, "processed", "")
+		}
+
+		if err := queued.offsets.done(ctx, queued.msg); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return fmt.Errorf("commit message: %w", err)
+		}
+	}
+}
+
+// Close leaves the consumer group and closes every reader
+func (p *PriorityConsumer) Close() error {
+	var errs []error
+	for _, c := range p.consumers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// offsetTracker commits a reader's partitions only as far as every message
+// fetched before has finished, so messages that finish out of order are
+// not skipped by a restart or rebalance
+type offsetTracker struct {
+	reader *kafkago.Reader
+
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+// partitionOffsets holds the offsets fetched from a partition and not yet
+// committed, in order, and which of them have finished
+type partitionOffsets struct {
+	pending  []int64
+	finished map[int64]bool
+}
+
+func newOffsetTracker(reader *kafkago.Reader) *offsetTracker {
+	return &offsetTracker{
+		reader:     reader,
+		partitions: make(map[int]*partitionOffsets),
+	}
+}
+
+// fetched records a message as in progress. A message fetched again after
+// a rebalance is tracked once.
+func (t *offsetTracker) fetched(m kafkago.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[m.Partition]
+	if !ok {
+		p = &partitionOffsets{finished: make(map[int64]bool)}
+		t.partitions[m.Partition] = p
+	}
+	i, found := slices.BinarySearch(p.pending, m.Offset)
+	if !found {
+		p.pending = slices.Insert(p.pending, i, m.Offset)
+	}
+}
+
+// done records a message as finished and commits its partition up to the
+// newest message with nothing older still in progress. Commits are made
+// under the lock so a partition's committed offset never moves backwards.
+func (t *offsetTracker) done(ctx context.Context, m kafkago.Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[m.Partition]
+	if !ok {
+		return nil
+	}
+	p.finished[m.Offset] = true
+
+	committable := int64(-1)
+	for len(p.pending) > 0 && p.finished[p.pending[0]] {
+		committable = p.pending[0]
+		delete(p.finished, committable)
+		p.pending = p.pending[1:]
+	}
+	if committable < 0 {
+		return nil
+	}
+
+	return t.reader.CommitMessages(ctx, kafkago.Message{
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    committable,
+	})
+}
@@ -0,0 +1,64 @@
+// Package kafka publishes service events to Kafka topics
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// Producer publishes messages to a single topic
+type Producer struct {
+	writer *kafkago.Writer
+}
+
+// NewProducer creates a producer for topic. Messages with the same key are
+// routed to the same partition so their order is kept.
+func NewProducer(brokers []string, topic string) *Producer {
+	return &Producer{
+		writer: &kafkago.Writer{
+			Addr:         kafkago.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafkago.Hash{},
+			RequiredAcks: kafkago.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+// Publish writes one message and waits for it to be acknowledged
+func (p *Producer) Publish(ctx context.Context, key string, value []byte) error {
+	err := p.writer.WriteMessages(ctx, kafkago.Message{
+		Key:   []byte(key),
+		Value: value,
+	})
+	if err != nil {
+		return fmt.Errorf("publish to %s: %w", p.writer.Topic, err)
+	}
+	return nil
+}
+
+// PublishWithHeaders writes one message with the given headers and waits
+// for it to be acknowledged
+func (p *Producer) PublishWithHeaders(ctx context.Context, key string, value []byte, headers map[string]string) error {
+	msg := kafkago.Message{
+		Key:     []byte(key),
+		Value:   value,
+		Headers: make([]kafkago.Header, 0, len(headers)),
+	}
+	for k, v := range headers {
+		msg.Headers = append(msg.Headers, kafkago.Header{Key: k, Value: []byte(v)})
+	}
+
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("publish to %s: %w", p.writer.Topic, err)
+	}
+	return nil
+}
+
+// Close flushes pending messages and closes the producer
+func (p *Producer) Close() error {
+	return p.writer.Close()
+}
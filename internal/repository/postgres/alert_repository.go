@@ -0,0 +1,364 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const alertColumns = `id, alert_number, tenant_id, user_id, transaction_id, alert_type, status, priority, risk_score,
+	title, description, pattern_type, related_tx_ids, confidence, detection_rule,
+	investigation_id, reviewed_by, reviewed_at, resolution,
+	occurrence_count, last_detected_at,
+	detected_at, created_at, updated_at`
+
+// AlertRepository persists AML alerts in PostgreSQL
+type AlertRepository struct {
+	db *sql.DB
+}
+
+// NewAlertRepository creates a new alert repository
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// Create inserts an alert
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.AMLAlert) error {
+	return insertAlert(ctx, r.db, alert)
+}
+
+// CreateCorrelated folds the alert into an unresolved alert with the same
+// correlation key last detected at or after since, or inserts it if there is
+// none. It returns the alert that now represents the group.
+func (r *AlertRepository) CreateCorrelated(ctx context.Context, alert *domain.AMLAlert, since time.Time) (*domain.AMLAlert, error) {
+	tenantID, err := tenantFor(ctx, alert.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	alert.TenantID = tenantID
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialise correlation per key so concurrent occurrences cannot both
+	// open a new group
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, alert.TenantID+":"+alert.CorrelationKey()); err != nil {
+		return nil, fmt.Errorf("lock alert correlation key: %w", err)
+	}
+
+	query := `SELECT ` + alertColumns + ` FROM aml_alerts
+		WHERE user_id = $1 AND alert_type = $2 AND detection_rule = $3
+			AND pattern_type IS NOT DISTINCT FROM $4
+			AND status NOT IN ('DISMISSED', 'RESOLVED')
+			AND last_detected_at >= $5
+			AND tenant_id = $6
+		ORDER BY last_detected_at DESC
+		LIMIT 1
+		FOR UPDATE`
+
+	group, err := scanAlert(tx.QueryRowContext(ctx, query,
+		alert.UserID, alert.AlertType, alert.DetectionRule, alert.PatternType, since, alert.TenantID,
+	))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		group = alert
+		if err := insertAlert(ctx, tx, group); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		group.Absorb(alert)
+		if _, err := updateAlert(ctx, tx, group, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit alert correlation: %w", err)
+	}
+	return group, nil
+}
+
+// Update writes all mutable fields of an alert
+func (r *AlertRepository) Update(ctx context.Context, alert *domain.AMLAlert) error {
+	res, err := updateAlert(ctx, r.db, alert, "")
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+// ApplyTransition writes the alert if it is still in the from status and,
+// in the same transaction, inserts the investigation it was escalated to,
+// opened by the alert's reviewer
+func (r *AlertRepository) ApplyTransition(ctx context.Context, alert *domain.AMLAlert, from domain.AlertStatus, inv *domain.Investigation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if inv != nil {
+		if err := insertInvestigation(ctx, tx, inv, *alert.ReviewedBy); err != nil {
+			return err
+		}
+	}
+
+	res, err := updateAlert(ctx, tx, alert, from)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: alert is no longer %s", domain.ErrConflict, from)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit alert transition: %w", err)
+	}
+	return nil
+}
+
+func insertAlert(ctx context.Context, db execer, alert *domain.AMLAlert) error {
+	tenantID, err := tenantFor(ctx, alert.TenantID)
+	if err != nil {
+		return err
+	}
+	alert.TenantID = tenantID
+	if alert.OccurrenceCount == 0 {
+		alert.OccurrenceCount = 1
+	}
+	if alert.LastDetectedAt.IsZero() {
+		alert.LastDetectedAt = alert.DetectedAt
+	}
+
+	query := `INSERT INTO aml_alerts (` + alertColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			$15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`
+
+	_, err = db.ExecContext(ctx, query,
+		alert.ID, alert.AlertNumber, alert.TenantID, alert.UserID, alert.TransactionID,
+		alert.AlertType, alert.Status, alert.Priority, alert.RiskScore,
+		alert.Title, alert.Description, alert.PatternType, pq.Array(nonNilSlice(alert.RelatedTxIDs)),
+		alert.Confidence, alert.DetectionRule,
+		alert.InvestigationID, alert.ReviewedBy, alert.ReviewedAt, alert.Resolution,
+		alert.OccurrenceCount, alert.LastDetectedAt,
+		alert.DetectedAt, alert.CreatedAt, alert.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert alert: %w", err)
+	}
+
+	return nil
+}
+
+// updateAlert writes the alert's mutable fields. A non-empty expectedStatus
+// makes the update conditional on the stored status.
+func updateAlert(ctx context.Context, db execer, alert *domain.AMLAlert, expectedStatus domain.AlertStatus) (sql.Result, error) {
+	query := `UPDATE aml_alerts SET
+		status = $2, priority = $3, risk_score = $4, description = $5,
+		related_tx_ids = $6, confidence = $7,
+		investigation_id = $8, reviewed_by = $9, reviewed_at = $10, resolution = $11,
+		occurrence_count = $12, last_detected_at = $13,
+		updated_at = $14
+		WHERE id = $1 AND ($15 = '' OR status = $15) AND ($16 = '' OR tenant_id = $16)`
+
+	res, err := db.ExecContext(ctx, query,
+		alert.ID, alert.Status, alert.Priority, alert.RiskScore, alert.Description,
+		pq.Array(nonNilSlice(alert.RelatedTxIDs)), alert.Confidence,
+		alert.InvestigationID, alert.ReviewedBy, alert.ReviewedAt, alert.Resolution,
+		alert.OccurrenceCount, alert.LastDetectedAt,
+		alert.UpdatedAt, expectedStatus, tenantScope(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update alert: %w", err)
+	}
+
+	return res, nil
+}
+
+// AutoClose dismisses up to limit of the oldest alerts selected by policy,
+// recording the system actor as reviewer, and returns them. Eligibility is
+// evaluated in the same statement as the update, and rows locked by a
+// concurrent review are skipped, so an alert picked up meanwhile is left
+// alone.
+func (r *AlertRepository) AutoClose(ctx context.Context, policy domain.AlertAutoClosePolicy, closedAt time.Time, limit int) ([]*domain.AMLAlert, error) {
+	excludeTypes := make([]string, len(policy.ExcludeTypes))
+	for i, t := range policy.ExcludeTypes {
+		excludeTypes[i] = string(t)
+	}
+
+	query := `UPDATE aml_alerts SET
+			status = $1, reviewed_by = $2, reviewed_at = $3, resolution = $4, updated_at = $3
+		WHERE id IN (
+			SELECT a.id FROM aml_alerts a
+			WHERE a.status = $5
+				AND ($11 = '' OR a.tenant_id = $11)
+				AND a.confidence < $6
+				AND a.last_detected_at < $7 AND a.updated_at < $7
+				AND a.alert_type <> ALL($8::text[])
+				AND a.detection_rule <> ALL($9::text[])
+				AND NOT EXISTS (
+					SELECT 1 FROM investigations i
+					WHERE i.id = a.investigation_id AND i.status <> 'CLOSED')
+				AND NOT EXISTS (
+					SELECT 1 FROM screening_results sr
+					WHERE (sr.transaction_id = a.transaction_id OR sr.transaction_id = ANY(a.related_tx_ids))
+						AND sr.ofac_match @> '{"matched": true}')
+			ORDER BY a.last_detected_at
+			LIMIT $10
+			FOR UPDATE SKIP LOCKED)
+		RETURNING ` + alertColumns
+
+	rows, err := r.db.QueryContext(ctx, query,
+		domain.AlertStatusDismissed, domain.SystemActorID, closedAt, domain.AlertResolutionAutoClosed,
+		domain.AlertStatusNew, policy.MaxConfidence, policy.StaleBefore,
+		pq.Array(excludeTypes), pq.Array(nonNilSlice(policy.ExcludeRules)), limit, tenantScope(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("auto-close alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*domain.AMLAlert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// GetByID returns an alert by ID
+func (r *AlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AMLAlert, error) {
+	query := `SELECT ` + alertColumns + ` FROM aml_alerts
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
+
+	alert, err := scanAlert(r.db.QueryRowContext(ctx, query, id, tenantScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return alert, err
+}
+
+// ListByInvestigation returns the alerts linked to an investigation, oldest first
+func (r *AlertRepository) ListByInvestigation(ctx context.Context, investigationID uuid.UUID) ([]*domain.AMLAlert, error) {
+	query := `SELECT ` + alertColumns + ` FROM aml_alerts
+		WHERE investigation_id = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY detected_at`
+
+	rows, err := r.db.QueryContext(ctx, query, investigationID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list alerts by investigation: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*domain.AMLAlert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// ListByUser returns a user's alerts detected at or after since, newest
+// first
+func (r *AlertRepository) ListByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.AMLAlert, error) {
+	query := `SELECT ` + alertColumns + ` FROM aml_alerts
+		WHERE user_id = $1 AND detected_at >= $2 AND ($4 = '' OR tenant_id = $4)
+		ORDER BY detected_at DESC, id
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list alerts by user: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*domain.AMLAlert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// ListNewSince returns alerts still NEW that were created since the given
+// time, highest priority first. An empty types list matches every type.
+func (r *AlertRepository) ListNewSince(ctx context.Context, since time.Time, types []domain.AlertType, limit int) ([]*domain.AMLAlert, error) {
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = string(t)
+	}
+
+	query := `SELECT ` + alertColumns + ` FROM aml_alerts
+		WHERE status = 'NEW' AND created_at >= $1
+			AND (cardinality($2::text[]) = 0 OR alert_type = ANY($2))
+			AND ($4 = '' OR tenant_id = $4)
+		ORDER BY risk_score DESC, created_at
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, since, pq.Array(typeNames), limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list new alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*domain.AMLAlert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+func scanAlert(row rowScanner) (*domain.AMLAlert, error) {
+	var alert domain.AMLAlert
+
+	err := row.Scan(
+		&alert.ID, &alert.AlertNumber, &alert.TenantID, &alert.UserID, &alert.TransactionID,
+		&alert.AlertType, &alert.Status, &alert.Priority, &alert.RiskScore,
+		&alert.Title, &alert.Description, &alert.PatternType, pq.Array(&alert.RelatedTxIDs),
+		&alert.Confidence, &alert.DetectionRule,
+		&alert.InvestigationID, &alert.ReviewedBy, &alert.ReviewedAt, &alert.Resolution,
+		&alert.OccurrenceCount, &alert.LastDetectedAt,
+		&alert.DetectedAt, &alert.CreatedAt, &alert.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan alert: %w", err)
+	}
+
+	return &alert, nil
+}
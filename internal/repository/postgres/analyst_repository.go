@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// analystSelect reads roster entries with their open caseload
+const analystSelect = `SELECT a.id, a.name, a.skills, a.max_open_cases, a.active, a.created_at, a.updated_at,
+		(SELECT COUNT(*) FROM investigations i WHERE i.assigned_to = a.id AND i.status <> 'CLOSED'),
+		a.email, a.alert_types, a.digest_enabled, a.last_digest_at
+	FROM analysts a`
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// AnalystRepository persists the investigation analyst roster in PostgreSQL
+type AnalystRepository struct {
+	db *sql.DB
+}
+
+// NewAnalystRepository creates a new analyst repository
+func NewAnalystRepository(db *sql.DB) *AnalystRepository {
+	return &AnalystRepository{db: db}
+}
+
+// Register adds an analyst to the roster, or reactivates and updates an
+// existing entry. An existing analyst's digest subscription is kept.
+func (r *AnalystRepository) Register(ctx context.Context, a *domain.Analyst) error {
+	query := `INSERT INTO analysts (id, name, skills, max_open_cases, email, alert_types, digest_enabled,
+			active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name, skills = EXCLUDED.skills,
+			max_open_cases = EXCLUDED.max_open_cases, email = EXCLUDED.email,
+			alert_types = EXCLUDED.alert_types, active = EXCLUDED.active,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at, digest_enabled, last_digest_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		a.ID, a.Name, pq.Array(nonNilSlice(a.Skills)), a.MaxOpenCases, a.Email,
+		pq.Array(alertTypeStrings(a.AlertTypes)), a.DigestEnabled, a.Active, a.CreatedAt, a.UpdatedAt,
+	).Scan(&a.CreatedAt, &a.DigestEnabled, &a.LastDigestAt)
+	if err != nil {
+		return fmt.Errorf("register analyst: %w", err)
+	}
+	return nil
+}
+
+// Deactivate takes an analyst off the roster. Their open cases stay
+// assigned to them.
+func (r *AnalystRepository) Deactivate(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE analysts SET active = FALSE, updated_at = NOW() WHERE id = $1 AND active`, id)
+	if err != nil {
+		return fmt.Errorf("deactivate analyst: %w", err)
+	}
+	return requireAffected(res)
+}
+
+// SetDigestEnabled subscribes an active analyst to, or unsubscribes them
+// from, the morning digest
+func (r *AnalystRepository) SetDigestEnabled(ctx context.Context, id uuid.UUID, enabled bool) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE analysts SET digest_enabled = $2, updated_at = NOW() WHERE id = $1 AND active`, id, enabled)
+	if err != nil {
+		return fmt.Errorf("set analyst digest preference: %w", err)
+	}
+	return requireAffected(res)
+}
+
+// ListDigestDue returns the active, subscribed analysts with an email
+// address whose last digest predates scheduledAt
+func (r *AnalystRepository) ListDigestDue(ctx context.Context, scheduledAt time.Time) ([]*domain.Analyst, error) {
+	rows, err := r.db.QueryContext(ctx, analystSelect+`
+		WHERE a.active AND a.digest_enabled AND a.email <> ''
+			AND (a.last_digest_at IS NULL OR a.last_digest_at < $1)
+		ORDER BY a.name`, scheduledAt)
+	if err != nil {
+		return nil, fmt.Errorf("list analysts due a digest: %w", err)
+	}
+	return scanAnalysts(rows)
+}
+
+// MarkDigestSent records when an analyst's digest was sent
+func (r *AnalystRepository) MarkDigestSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE analysts SET last_digest_at = $2 WHERE id = $1`, id, sentAt)
+	if err != nil {
+		return fmt.Errorf("mark analyst digest sent: %w", err)
+	}
+	return nil
+}
+
+// List returns the active roster, least loaded first
+func (r *AnalystRepository) List(ctx context.Context) ([]*domain.Analyst, error) {
+	return listActiveAnalysts(ctx, r.db)
+}
+
+func listActiveAnalysts(ctx context.Context, db queryer) ([]*domain.Analyst, error) {
+	rows, err := db.QueryContext(ctx, analystSelect+` WHERE a.active ORDER BY 8, a.name`)
+	if err != nil {
+		return nil, fmt.Errorf("list analysts: %w", err)
+	}
+	return scanAnalysts(rows)
+}
+
+func scanAnalysts(rows *sql.Rows) ([]*domain.Analyst, error) {
+	defer rows.Close()
+
+	var analysts []*domain.Analyst
+	for rows.Next() {
+		var a domain.Analyst
+		var alertTypes []string
+		err := rows.Scan(&a.ID, &a.Name, pq.Array(&a.Skills), &a.MaxOpenCases, &a.Active,
+			&a.CreatedAt, &a.UpdatedAt, &a.OpenCases,
+			&a.Email, pq.Array(&alertTypes), &a.DigestEnabled, &a.LastDigestAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan analyst: %w", err)
+		}
+		for _, t := range alertTypes {
+			a.AlertTypes = append(a.AlertTypes, domain.AlertType(t))
+		}
+		analysts = append(analysts, &a)
+	}
+
+	return analysts, rows.Err()
+}
+
+// alertTypeStrings converts alert types for a TEXT[] column
+func alertTypeStrings(types []domain.AlertType) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+)
+
+// auditChainLockKey serialises appends to the audit chain across instances
+const auditChainLockKey int64 = 0x414d4c05 // "AML" + 5
+
+const auditEventColumns = `sequence, id, actor_id, action, entity_type, entity_id,
+	before, after, request_id, timestamp, prev_hmac, hmac`
+
+// AuditRepository persists the audit chain in PostgreSQL
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Append stores an event as the new chain head. The chain is locked for the
+// duration of the transaction so concurrent writers cannot fork it.
+func (r *AuditRepository) Append(ctx context.Context, e *audit.AuditEvent, seal func(head *audit.AuditEvent)) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockKey); err != nil {
+		return fmt.Errorf("lock audit chain: %w", err)
+	}
+
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events ORDER BY sequence DESC LIMIT 1`
+	head, err := scanAuditEvent(tx.QueryRowContext(ctx, query))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	seal(head)
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO audit_events (`+auditEventColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		e.Sequence,
+		e.ID,
+		e.ActorID,
+		e.Action,
+		e.EntityType,
+		e.EntityID,
+		nullJSON(e.Before),
+		nullJSON(e.After),
+		e.RequestID,
+		e.Timestamp,
+		e.PrevHMAC,
+		e.HMAC,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit event: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListAfter returns events with a sequence greater than after, in order
+func (r *AuditRepository) ListAfter(ctx context.Context, after int64, limit int) ([]*audit.AuditEvent, error) {
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events
+		WHERE sequence > $1
+		ORDER BY sequence
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*audit.AuditEvent, 0, limit)
+	for rows.Next() {
+		e, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// ListSubjectAccess returns the PII_ACCESSED events naming userID among
+// their subjects recorded in [from, to), in chain order. The lookup is
+// served by idx_audit_events_pii_subjects.
+func (r *AuditRepository) ListSubjectAccess(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*audit.AuditEvent, error) {
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events
+		WHERE action = $1
+			AND (after::jsonb -> 'subject_user_ids') @> jsonb_build_array($2::text)
+			AND timestamp >= $3 AND timestamp < $4
+		ORDER BY sequence`
+
+	rows, err := r.db.QueryContext(ctx, query, audit.ActionPIIAccessed, userID.String(), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list pii access events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*audit.AuditEvent
+	for rows.Next() {
+		e, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+func scanAuditEvent(row rowScanner) (*audit.AuditEvent, error) {
+	var e audit.AuditEvent
+	var before, after []byte
+
+	err := row.Scan(
+		&e.Sequence,
+		&e.ID,
+		&e.ActorID,
+		&e.Action,
+		&e.EntityType,
+		&e.EntityID,
+		&before,
+		&after,
+		&e.RequestID,
+		&e.Timestamp,
+		&e.PrevHMAC,
+		&e.HMAC,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan audit event: %w", err)
+	}
+
+	if len(before) > 0 {
+		e.Before = before
+	}
+	if len(after) > 0 {
+		e.After = after
+	}
+	return &e, nil
+}
+
+// nullJSON stores an empty document as NULL
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const batchJobColumns = `id, tenant_id, status, items, total_items, cursor, hit_count, error,
+	created_at, started_at, completed_at, updated_at`
+
+// BatchJobRepository persists batch screening jobs and their results in PostgreSQL
+type BatchJobRepository struct {
+	db *sql.DB
+}
+
+// NewBatchJobRepository creates a new batch job repository
+func NewBatchJobRepository(db *sql.DB) *BatchJobRepository {
+	return &BatchJobRepository{db: db}
+}
+
+// Create inserts a batch job along with its items
+func (r *BatchJobRepository) Create(ctx context.Context, job *domain.BatchScreeningJob) error {
+	tenantID, err := tenantFor(ctx, job.TenantID)
+	if err != nil {
+		return err
+	}
+	job.TenantID = tenantID
+
+	items, err := json.Marshal(nonNilSlice(job.Items))
+	if err != nil {
+		return fmt.Errorf("marshal batch items: %w", err)
+	}
+
+	query := `INSERT INTO batch_jobs (` + batchJobColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		job.ID, job.TenantID, job.Status, items, job.TotalItems, job.Cursor, job.HitCount, job.Error,
+		job.CreatedAt, job.StartedAt, job.CompletedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert batch job: %w", err)
+	}
+
+	return nil
+}
+
+// Update writes the status and progress of a batch job
+func (r *BatchJobRepository) Update(ctx context.Context, job *domain.BatchScreeningJob) error {
+	query := `UPDATE batch_jobs SET
+		status = $2, cursor = $3, hit_count = $4, error = $5,
+		started_at = $6, completed_at = $7, updated_at = $8
+		WHERE id = $1`
+
+	res, err := r.db.ExecContext(ctx, query,
+		job.ID, job.Status, job.Cursor, job.HitCount, job.Error,
+		job.StartedAt, job.CompletedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update batch job: %w", err)
+	}
+
+	return requireAffected(res)
+}
+
+// GetByID returns a batch job by ID, including its items
+func (r *BatchJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BatchScreeningJob, error) {
+	query := `SELECT ` + batchJobColumns + ` FROM batch_jobs
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
+
+	job, err := scanBatchJob(r.db.QueryRowContext(ctx, query, id, tenantScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return job, err
+}
+
+// NextRunnable returns the oldest pending or interrupted job, or
+// domain.ErrNotFound when there is nothing to do
+func (r *BatchJobRepository) NextRunnable(ctx context.Context) (*domain.BatchScreeningJob, error) {
+	query := `SELECT ` + batchJobColumns + ` FROM batch_jobs
+		WHERE status IN ('PENDING', 'RUNNING')
+		ORDER BY created_at
+		LIMIT 1`
+
+	job, err := scanBatchJob(r.db.QueryRowContext(ctx, query))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return job, err
+}
+
+// SaveProgress stores a chunk of results and advances the job cursor in a
+// single transaction, so a restart never skips or double-counts items
+func (r *BatchJobRepository) SaveProgress(ctx context.Context, job *domain.BatchScreeningJob, results []domain.BatchResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO batch_results
+		(job_id, seq, name, user_id, ofac_match, pep_match, hit, error, screened_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (job_id, seq) DO NOTHING`)
+	if err != nil {
+		return fmt.Errorf("prepare batch result insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, res := range results {
+		var ofacMatch, pepMatch []byte
+		if res.OFACMatch != nil {
+			if ofacMatch, err = json.Marshal(res.OFACMatch); err != nil {
+				return fmt.Errorf("marshal ofac match: %w", err)
+			}
+		}
+		if res.PEPMatch != nil {
+			if pepMatch, err = json.Marshal(res.PEPMatch); err != nil {
+				return fmt.Errorf("marshal pep match: %w", err)
+			}
+		}
+
+		if _, err := stmt.ExecContext(ctx,
+			res.JobID, res.Seq, res.Name, res.UserID, ofacMatch, pepMatch, res.Hit, res.Error, res.ScreenedAt,
+		); err != nil {
+			return fmt.Errorf("insert batch result: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE batch_jobs SET
+		status = $2, cursor = $3, hit_count = $4, updated_at = $5
+		WHERE id = $1`,
+		job.ID, job.Status, job.Cursor, job.HitCount, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("advance batch cursor: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListHits returns the results of a job that matched a sanctions or PEP list, in input order
+func (r *BatchJobRepository) ListHits(ctx context.Context, jobID uuid.UUID) ([]domain.BatchResult, error) {
+	query := `SELECT job_id, seq, name, user_id, ofac_match, pep_match, hit, error, screened_at
+		FROM batch_results
+		WHERE job_id = $1 AND hit
+			AND ($2 = '' OR EXISTS (SELECT 1 FROM batch_jobs j WHERE j.id = job_id AND j.tenant_id = $2))
+		ORDER BY seq`
+
+	rows, err := r.db.QueryContext(ctx, query, jobID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list batch hits: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.BatchResult
+	for rows.Next() {
+		var res domain.BatchResult
+		var ofacMatch, pepMatch []byte
+
+		if err := rows.Scan(
+			&res.JobID, &res.Seq, &res.Name, &res.UserID, &ofacMatch, &pepMatch, &res.Hit, &res.Error, &res.ScreenedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan batch result: %w", err)
+		}
+
+		if len(ofacMatch) > 0 {
+			if err := json.Unmarshal(ofacMatch, &res.OFACMatch); err != nil {
+				return nil, fmt.Errorf("unmarshal ofac match: %w", err)
+			}
+		}
+		if len(pepMatch) > 0 {
+			if err := json.Unmarshal(pepMatch, &res.PEPMatch); err != nil {
+				return nil, fmt.Errorf("unmarshal pep match: %w", err)
+			}
+		}
+
+		results = append(results, res)
+	}
+
+	return results, rows.Err()
+}
+
+func scanBatchJob(row rowScanner) (*domain.BatchScreeningJob, error) {
+	var job domain.BatchScreeningJob
+	var items []byte
+
+	err := row.Scan(
+		&job.ID, &job.TenantID, &job.Status, &items, &job.TotalItems, &job.Cursor, &job.HitCount, &job.Error,
+		&job.CreatedAt, &job.StartedAt, &job.CompletedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan batch job: %w", err)
+	}
+
+	if err := json.Unmarshal(items, &job.Items); err != nil {
+		return nil, fmt.Errorf("unmarshal batch items: %w", err)
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const counterpartyColumns = `tenant_id, account, bank, blocked, suspicious, sars, first_seen_at, last_event_at, decayed_at`
+
+// CounterpartyRepository persists counterparty reputations, per tenant.
+// Counts are stored decayed as of decayed_at and decayed further by
+// readers.
+type CounterpartyRepository struct {
+	db *sql.DB
+}
+
+// NewCounterpartyRepository creates a new counterparty repository
+func NewCounterpartyRepository(db *sql.DB) *CounterpartyRepository {
+	return &CounterpartyRepository{db: db}
+}
+
+// Get returns a counterparty's reputation with the key's tenant, or nil if
+// none was recorded
+func (r *CounterpartyRepository) Get(ctx context.Context, key domain.CounterpartyKey) (*domain.CounterpartyReputation, error) {
+	tenantID, err := tenantFor(ctx, key.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	rep, err := scanCounterparty(r.db.QueryRowContext(ctx, `SELECT `+counterpartyColumns+` FROM counterparty_reputation
+		WHERE tenant_id = $1 AND account = $2 AND bank = $3`, tenantID, key.Account, key.Bank))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return rep, err
+}
+
+// ListByAccount returns an account's reputation at each bank it was seen
+// with, most recently active first
+func (r *CounterpartyRepository) ListByAccount(ctx context.Context, account string) ([]*domain.CounterpartyReputation, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+counterpartyColumns+` FROM counterparty_reputation
+		WHERE account = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY last_event_at DESC`, account, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list counterparty reputations: %w", err)
+	}
+	defer rows.Close()
+
+	reps := make([]*domain.CounterpartyReputation, 0)
+	for rows.Next() {
+		rep, err := scanCounterparty(rows)
+		if err != nil {
+			return nil, err
+		}
+		reps = append(reps, rep)
+	}
+	return reps, rows.Err()
+}
+
+// Record adds an event to a counterparty's reputation in one statement, so
+// concurrent screenings never lose a count. The stored counts and the
+// event are each decayed to the later of their times before being added,
+// as domain.CounterpartyReputation.Apply does.
+func (r *CounterpartyRepository) Record(ctx context.Context, event domain.CounterpartyEvent, halfLife time.Duration) error {
+	tenantID, err := tenantFor(ctx, event.Key.TenantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO counterparty_reputation AS c (`+counterpartyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $7)
+		ON CONFLICT (tenant_id, account, bank) DO UPDATE SET
+			blocked    = c.blocked * counterparty_decay(c.decayed_at, EXCLUDED.decayed_at, $8) + EXCLUDED.blocked * counterparty_decay(EXCLUDED.decayed_at, c.decayed_at, $8),
+			suspicious = c.suspicious * counterparty_decay(c.decayed_at, EXCLUDED.decayed_at, $8) + EXCLUDED.suspicious * counterparty_decay(EXCLUDED.decayed_at, c.decayed_at, $8),
+			sars       = c.sars * counterparty_decay(c.decayed_at, EXCLUDED.decayed_at, $8) + EXCLUDED.sars * counterparty_decay(EXCLUDED.decayed_at, c.decayed_at, $8),
+			first_seen_at = LEAST(c.first_seen_at, EXCLUDED.first_seen_at),
+			last_event_at = GREATEST(c.last_event_at, EXCLUDED.last_event_at),
+			decayed_at    = GREATEST(c.decayed_at, EXCLUDED.decayed_at)`,
+		tenantID, event.Key.Account, event.Key.Bank, event.Blocked, event.Suspicious, event.SARs, event.At, halfLife.Seconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("record counterparty event: %w", err)
+	}
+	return nil
+}
+
+// Replace swaps every stored reputation of the tenant ctx acts for, or of
+// every tenant when it names none, for the given ones in a single
+// transaction, so readers see either the old store or the rebuilt one
+func (r *CounterpartyRepository) Replace(ctx context.Context, reps []*domain.CounterpartyReputation) error {
+	scope := tenantScope(ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM counterparty_reputation WHERE ($1 = '' OR tenant_id = $1)`, scope); err != nil {
+		return fmt.Errorf("clear counterparty reputations: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO counterparty_reputation (`+counterpartyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`)
+	if err != nil {
+		return fmt.Errorf("prepare counterparty insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, rep := range reps {
+		tenantID, err := tenantFor(ctx, rep.TenantID)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx,
+			tenantID, rep.Account, rep.Bank, rep.Blocked, rep.Suspicious, rep.SARs, rep.FirstSeenAt, rep.LastEventAt, rep.DecayedAt,
+		); err != nil {
+			return fmt.Errorf("insert counterparty reputation: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func scanCounterparty(row rowScanner) (*domain.CounterpartyReputation, error) {
+	var rep domain.CounterpartyReputation
+	err := row.Scan(&rep.TenantID, &rep.Account, &rep.Bank, &rep.Blocked, &rep.Suspicious, &rep.SARs,
+		&rep.FirstSeenAt, &rep.LastEventAt, &rep.DecayedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan counterparty reputation: %w", err)
+	}
+	return &rep, nil
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const countryRiskTableColumns = `version, tiers, ratings, rating_points, comment, created_by, created_at`
+
+// CountryRiskRepository persists versions of the country risk table
+type CountryRiskRepository struct {
+	db *sql.DB
+}
+
+// NewCountryRiskRepository creates a new country risk repository
+func NewCountryRiskRepository(db *sql.DB) *CountryRiskRepository {
+	return &CountryRiskRepository{db: db}
+}
+
+// Create stores a table under its version, returning ErrConflict if the
+// version is taken
+func (r *CountryRiskRepository) Create(ctx context.Context, table *domain.CountryRiskTable) error {
+	tiers, err := json.Marshal(nonNilSlice(table.Tiers))
+	if err != nil {
+		return fmt.Errorf("marshal country risk tiers: %w", err)
+	}
+	ratings, err := json.Marshal(nonNilMap(table.Ratings))
+	if err != nil {
+		return fmt.Errorf("marshal country risk ratings: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, `INSERT INTO country_risk_tables (`+countryRiskTableColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (version) DO NOTHING`,
+		table.Version, tiers, ratings, table.RatingPoints, table.Comment, table.CreatedBy, table.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create country risk table: %w", err)
+	}
+	if err := requireAffected(res); errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: country risk table version %d already exists", domain.ErrConflict, table.Version)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Latest returns the newest table, or ErrNotFound if none was saved
+func (r *CountryRiskRepository) Latest(ctx context.Context) (*domain.CountryRiskTable, error) {
+	return r.scanOne(r.db.QueryRowContext(ctx, `SELECT `+countryRiskTableColumns+` FROM country_risk_tables
+		ORDER BY version DESC
+		LIMIT 1`))
+}
+
+// Get returns a table by version
+func (r *CountryRiskRepository) Get(ctx context.Context, version int) (*domain.CountryRiskTable, error) {
+	return r.scanOne(r.db.QueryRowContext(ctx, `SELECT `+countryRiskTableColumns+` FROM country_risk_tables
+		WHERE version = $1`, version))
+}
+
+// List returns a page of tables, newest first
+func (r *CountryRiskRepository) List(ctx context.Context, limit, offset int) ([]*domain.CountryRiskTable, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+countryRiskTableColumns+` FROM country_risk_tables
+		ORDER BY version DESC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list country risk tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make([]*domain.CountryRiskTable, 0)
+	for rows.Next() {
+		table, err := scanCountryRiskTable(rows)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (r *CountryRiskRepository) scanOne(row *sql.Row) (*domain.CountryRiskTable, error) {
+	table, err := scanCountryRiskTable(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return table, err
+}
+
+func scanCountryRiskTable(row rowScanner) (*domain.CountryRiskTable, error) {
+	var table domain.CountryRiskTable
+	var tiers, ratings []byte
+	if err := row.Scan(&table.Version, &tiers, &ratings, &table.RatingPoints, &table.Comment, &table.CreatedBy, &table.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan country risk table: %w", err)
+	}
+	if err := json.Unmarshal(tiers, &table.Tiers); err != nil {
+		return nil, fmt.Errorf("unmarshal country risk tiers: %w", err)
+	}
+	if err := json.Unmarshal(ratings, &table.Ratings); err != nil {
+		return nil, fmt.Errorf("unmarshal country risk ratings: %w", err)
+	}
+	return &table, nil
+}
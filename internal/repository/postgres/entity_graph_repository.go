@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// counterpartyKeyExpr identifies a transaction's counterparty by account,
+// falling back to the lower-cased name. It must match the expression index
+// idx_screening_results_counterparty_key.
+const counterpartyKeyExpr = `COALESCE(
+		NULLIF(CASE WHEN transaction->>'direction' = 'OUTBOUND'
+			THEN transaction->>'receiver_account' ELSE transaction->>'sender_account' END, ''),
+		'name:' || LOWER(NULLIF(` + counterpartyNameExpr + `, '')))`
+
+// counterpartyLinkSelect aggregates screened transactions per user and
+// counterparty of the tenant in $2; callers append the WHERE conditions on
+// screening_results
+const counterpartyLinkSelect = `SELECT user_id, key, MAX(name), COUNT(DISTINCT transaction_id), MAX(risk_score)
+	FROM (
+		SELECT user_id, transaction_id, risk_score,
+			` + counterpartyKeyExpr + ` AS key,
+			` + counterpartyNameExpr + ` AS name
+		FROM screening_results
+		WHERE transaction IS NOT NULL AND created_at >= $1 AND ($2 = '' OR tenant_id = $2) AND `
+
+// EntityGraphRepository reads the links between users, counterparties and
+// cases from persisted screening results
+type EntityGraphRepository struct {
+	db *sql.DB
+}
+
+// NewEntityGraphRepository creates a new entity graph repository
+func NewEntityGraphRepository(db *sql.DB) *EntityGraphRepository {
+	return &EntityGraphRepository{db: db}
+}
+
+// ListCounterparties returns the counterparties a user transacted with since
+// the given time, most frequent first
+func (r *EntityGraphRepository) ListCounterparties(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]domain.CounterpartyLink, error) {
+	query := counterpartyLinkSelect + `user_id = $3
+		) t
+		WHERE key IS NOT NULL
+		GROUP BY user_id, key
+		ORDER BY COUNT(*) DESC, key
+		LIMIT $4`
+
+	return r.queryLinks(ctx, query, since, tenantScope(ctx), userID, limit)
+}
+
+// ListCounterpartyUsers returns other users who transacted with any of the
+// given counterparties since the given time, most frequent first
+func (r *EntityGraphRepository) ListCounterpartyUsers(ctx context.Context, keys []string, exclude uuid.UUID, since time.Time, limit int) ([]domain.CounterpartyLink, error) {
+	query := counterpartyLinkSelect + counterpartyKeyExpr + ` = ANY($3) AND user_id <> $4
+		) t
+		GROUP BY user_id, key
+		ORDER BY COUNT(*) DESC, user_id, key
+		LIMIT $5`
+
+	return r.queryLinks(ctx, query, since, tenantScope(ctx), pq.Array(keys), exclude, limit)
+}
+
+func (r *EntityGraphRepository) queryLinks(ctx context.Context, query string, args ...interface{}) ([]domain.CounterpartyLink, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list counterparty links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []domain.CounterpartyLink
+	for rows.Next() {
+		var l domain.CounterpartyLink
+		var name sql.NullString
+		if err := rows.Scan(&l.UserID, &l.Key, &name, &l.TxCount, &l.RiskScore); err != nil {
+			return nil, fmt.Errorf("scan counterparty link: %w", err)
+		}
+		l.Name = name.String
+		if !strings.HasPrefix(l.Key, "name:") {
+			l.Account = l.Key
+		}
+		links = append(links, l)
+	}
+
+	return links, rows.Err()
+}
+
+// ListCases returns the investigations, alerts and filings on the given
+// users, newest first
+func (r *EntityGraphRepository) ListCases(ctx context.Context, userIDs []uuid.UUID, limit int) ([]domain.CaseLink, error) {
+	query := `SELECT type, id, user_id, label, status, risk_score FROM (
+			SELECT 'INVESTIGATION' AS type, id, user_id, case_number AS label, status, risk_score, created_at
+			FROM investigations WHERE user_id = ANY($1) AND ($3 = '' OR tenant_id = $3)
+			UNION ALL
+			SELECT 'ALERT', id, user_id, alert_number, status, risk_score, created_at
+			FROM aml_alerts WHERE user_id = ANY($1) AND ($3 = '' OR tenant_id = $3)
+			UNION ALL
+			SELECT 'FILING', id, user_id, filing_number, status, 0, created_at
+			FROM regulatory_filings WHERE user_id = ANY($1) AND ($3 = '' OR tenant_id = $3)
+		) cases
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs), limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list cases: %w", err)
+	}
+	defer rows.Close()
+
+	var cases []domain.CaseLink
+	for rows.Next() {
+		var c domain.CaseLink
+		if err := rows.Scan(&c.Type, &c.ID, &c.UserID, &c.Label, &c.Status, &c.RiskScore); err != nil {
+			return nil, fmt.Errorf("scan case: %w", err)
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, rows.Err()
+}
@@ -0,0 +1,663 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/crypto"
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const filingColumns = `id, filing_number, bsa_filing_id, tenant_id, filing_type, status,
+	user_id, investigation_id, transaction_ids,
+	subject_info, suspicious_activity, ctr_details,
+	total_amount, currency, narrative, narrative_encrypted, encryption_key_version,
+	narrative_drafted_at, narrative_draft_sha256,
+	prepared_by, reviewed_by, approved_by,
+	activity_start_date, activity_end_date, filing_due_date,
+	submitted_at, confirmation_number, rejection_reason,
+	deadline_warning_days, overdue_alerted_at,
+	amended_from_id, amendment_reason,
+	created_at, updated_at`
+
+// FilingRepository persists regulatory filings in PostgreSQL.
+//
+// SSNs, ID numbers and the narrative are encrypted before they are written.
+// Reads decrypt them only for contexts granted crypto.WithPIIAccess; other
+// callers get the ciphertext, which is written back unchanged on update.
+type FilingRepository struct {
+	db   *sql.DB
+	keys *crypto.Keyring
+}
+
+// NewFilingRepository creates a new filing repository
+func NewFilingRepository(db *sql.DB, keys *crypto.Keyring) *FilingRepository {
+	return &FilingRepository{db: db, keys: keys}
+}
+
+// Create inserts a filing
+func (r *FilingRepository) Create(ctx context.Context, f *domain.RegulatoryFiling) error {
+	return r.insertFiling(ctx, r.db, f)
+}
+
+// Update writes all mutable fields of a filing
+func (r *FilingRepository) Update(ctx context.Context, f *domain.RegulatoryFiling) error {
+	res, err := r.updateFiling(ctx, r.db, f, "")
+	if err != nil {
+		return err
+	}
+
+	return requireAffected(res)
+}
+
+// UpdateDraft writes a filing only while it is still a draft. It returns
+// domain.ErrConflict if the filing has left DRAFT.
+func (r *FilingRepository) UpdateDraft(ctx context.Context, f *domain.RegulatoryFiling) error {
+	res, err := r.updateFiling(ctx, r.db, f, domain.FilingStatusDraft)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: filing is no longer %s", domain.ErrConflict, domain.FilingStatusDraft)
+		}
+		return err
+	}
+	return nil
+}
+
+// ApplyTransition updates a filing that is still in status from and records
+// the transition. When an amendment is submitted, superseded moves the
+// original filing to AMENDED in the same transaction. It returns
+// domain.ErrConflict if either filing changed status concurrently.
+func (r *FilingRepository) ApplyTransition(ctx context.Context, f *domain.RegulatoryFiling, from domain.FilingStatus, t *domain.FilingTransition, superseded *domain.FilingChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.applyChange(ctx, tx, f, from, t); err != nil {
+		return err
+	}
+	if superseded != nil {
+		if err := r.applyChange(ctx, tx, superseded.Filing, superseded.From, superseded.Transition); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit filing transition: %w", err)
+	}
+	return nil
+}
+
+// applyChange writes a filing still in status from and records the transition
+func (r *FilingRepository) applyChange(ctx context.Context, db execer, f *domain.RegulatoryFiling, from domain.FilingStatus, t *domain.FilingTransition) error {
+	res, err := r.updateFiling(ctx, db, f, from)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: filing %s is no longer %s", domain.ErrConflict, f.FilingNumber, from)
+		}
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `INSERT INTO filing_transitions
+		(id, filing_id, from_status, to_status, actor_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		t.ID, t.FilingID, t.FromStatus, t.ToStatus, t.ActorID, t.Reason, t.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert filing transition: %w", err)
+	}
+	return nil
+}
+
+// CreateAmendment inserts an amendment of a filing that is still in status
+// from. It returns domain.ErrConflict if the original changed status or
+// already has an open amendment, one that has not yet been submitted.
+func (r *FilingRepository) CreateAmendment(ctx context.Context, amendment *domain.RegulatoryFiling, from domain.FilingStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the original so concurrent amendments serialize
+	var status domain.FilingStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM regulatory_filings
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+		FOR UPDATE`, amendment.AmendedFromID, tenantScope(ctx)).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("lock amended filing: %w", err)
+	}
+	if status != from {
+		return fmt.Errorf("%w: filing is no longer %s", domain.ErrConflict, from)
+	}
+
+	var open string
+	err = tx.QueryRowContext(ctx, `SELECT filing_number FROM regulatory_filings
+		WHERE amended_from_id = $1 AND status NOT IN ('SUBMITTED', 'ACCEPTED', 'AMENDED')
+		LIMIT 1`, amendment.AmendedFromID).Scan(&open)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: filing already has an open amendment %s", domain.ErrConflict, open)
+	case !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("check open amendments: %w", err)
+	}
+
+	if err := r.insertFiling(ctx, tx, amendment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit amendment: %w", err)
+	}
+	return nil
+}
+
+// ListAmendmentChain returns every filing in the amendment chain containing
+// the given filing, from the original report to its latest amendment
+func (r *FilingRepository) ListAmendmentChain(ctx context.Context, id uuid.UUID) ([]*domain.RegulatoryFiling, error) {
+	query := `WITH RECURSIVE ancestors AS (
+			SELECT id, amended_from_id FROM regulatory_filings
+			WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+			UNION ALL
+			SELECT f.id, f.amended_from_id
+			FROM regulatory_filings f
+			JOIN ancestors a ON f.id = a.amended_from_id
+		), chain AS (
+			SELECT id FROM ancestors WHERE amended_from_id IS NULL
+			UNION ALL
+			SELECT f.id
+			FROM regulatory_filings f
+			JOIN chain c ON f.amended_from_id = c.id
+		)
+		SELECT ` + filingColumns + ` FROM regulatory_filings
+		WHERE id IN (SELECT id FROM chain)
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, id, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list amendment chain: %w", err)
+	}
+	defer rows.Close()
+
+	var filings []*domain.RegulatoryFiling
+	for rows.Next() {
+		f, err := r.scanFiling(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		filings = append(filings, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list amendment chain: %w", err)
+	}
+	if len(filings) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return filings, nil
+}
+
+// ListTransitions returns a filing's status history, oldest first
+func (r *FilingRepository) ListTransitions(ctx context.Context, filingID uuid.UUID) ([]domain.FilingTransition, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, filing_id, from_status, to_status, actor_id, reason, created_at
+		FROM filing_transitions
+		WHERE filing_id = $1 AND ($2 = '' OR EXISTS (SELECT 1 FROM regulatory_filings f
+			WHERE f.id = filing_id AND f.tenant_id = $2))
+		ORDER BY created_at`, filingID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list filing transitions: %w", err)
+	}
+	defer rows.Close()
+
+	transitions := []domain.FilingTransition{}
+	for rows.Next() {
+		var t domain.FilingTransition
+		if err := rows.Scan(&t.ID, &t.FilingID, &t.FromStatus, &t.ToStatus, &t.ActorID, &t.Reason, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan filing transition: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+
+	return transitions, rows.Err()
+}
+
+// GetByID returns a filing by ID
+func (r *FilingRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RegulatoryFiling, error) {
+	query := `SELECT ` + filingColumns + ` FROM regulatory_filings
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
+
+	f, err := r.scanFiling(ctx, r.db.QueryRowContext(ctx, query, id, tenantScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return f, err
+}
+
+// ListByUser returns the filings on a user created at or after since,
+// newest first
+func (r *FilingRepository) ListByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.RegulatoryFiling, error) {
+	query := `SELECT ` + filingColumns + ` FROM regulatory_filings
+		WHERE user_id = $1 AND created_at >= $2 AND ($4 = '' OR tenant_id = $4)
+		ORDER BY created_at DESC, id
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list filings by user: %w", err)
+	}
+	defer rows.Close()
+
+	var filings []*domain.RegulatoryFiling
+	for rows.Next() {
+		f, err := r.scanFiling(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		filings = append(filings, f)
+	}
+
+	return filings, rows.Err()
+}
+
+// ListOpenSARsDueBefore returns unsubmitted SARs due before the given time,
+// soonest first. Amendments are excluded since they carry the original
+// filing's deadline.
+func (r *FilingRepository) ListOpenSARsDueBefore(ctx context.Context, before time.Time, limit int) ([]*domain.RegulatoryFiling, error) {
+	query := `SELECT ` + filingColumns + ` FROM regulatory_filings
+		WHERE filing_type = 'SAR'
+			AND status NOT IN ('SUBMITTED', 'ACCEPTED', 'AMENDED')
+			AND filing_due_date < $1
+			AND overdue_alerted_at IS NULL
+			AND amended_from_id IS NULL
+			AND ($3 = '' OR tenant_id = $3)
+		ORDER BY filing_due_date
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list open sars: %w", err)
+	}
+	defer rows.Close()
+
+	var filings []*domain.RegulatoryFiling
+	for rows.Next() {
+		f, err := r.scanFiling(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		filings = append(filings, f)
+	}
+
+	return filings, rows.Err()
+}
+
+// ListSubmittedSARs returns up to limit SARs that were submitted, other
+// than amendments, with IDs after the given one in ID order. Only the
+// fields naming the transactions covered are read, so no content is
+// decrypted.
+func (r *FilingRepository) ListSubmittedSARs(ctx context.Context, after uuid.UUID, limit int) ([]domain.SubmittedSAR, error) {
+	query := `SELECT id, transaction_ids, submitted_at FROM regulatory_filings
+		WHERE filing_type = 'SAR'
+			AND submitted_at IS NOT NULL
+			AND amended_from_id IS NULL
+			AND id > $1
+			AND ($3 = '' OR tenant_id = $3)
+		ORDER BY id
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, after, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list submitted sars: %w", err)
+	}
+	defer rows.Close()
+
+	var sars []domain.SubmittedSAR
+	for rows.Next() {
+		var sar domain.SubmittedSAR
+		if err := rows.Scan(&sar.ID, pq.Array(&sar.TransactionIDs), &sar.SubmittedAt); err != nil {
+			return nil, fmt.Errorf("scan submitted sar: %w", err)
+		}
+		sars = append(sars, sar)
+	}
+	return sars, rows.Err()
+}
+
+// ListAwaitingReview returns the filings pending review that an analyst
+// may review: prepared by someone else and not sent back by another
+// reviewer. Soonest due first.
+func (r *FilingRepository) ListAwaitingReview(ctx context.Context, reviewerID uuid.UUID, limit int) ([]*domain.RegulatoryFiling, error) {
+	query := `SELECT ` + filingColumns + ` FROM regulatory_filings
+		WHERE status = 'PENDING_REVIEW'
+			AND prepared_by <> $1
+			AND (reviewed_by IS NULL OR reviewed_by = $1)
+			AND ($3 = '' OR tenant_id = $3)
+		ORDER BY filing_due_date
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, reviewerID, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list filings awaiting review: %w", err)
+	}
+	defer rows.Close()
+
+	var filings []*domain.RegulatoryFiling
+	for rows.Next() {
+		f, err := r.scanFiling(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		filings = append(filings, f)
+	}
+
+	return filings, rows.Err()
+}
+
+func (r *FilingRepository) insertFiling(ctx context.Context, db execer, f *domain.RegulatoryFiling) error {
+	tenantID, err := tenantFor(ctx, f.TenantID)
+	if err != nil {
+		return err
+	}
+	f.TenantID = tenantID
+
+	subject, activity, ctr, narrative, err := r.sealFilingContent(f)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO regulatory_filings (` + filingColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31,
+			$32, $33, $34)`
+
+	_, err = db.ExecContext(ctx, query,
+		f.ID, f.FilingNumber, f.BSAFilingID, f.TenantID, f.FilingType, f.Status,
+		f.UserID, f.InvestigationID, pq.Array(nonNilSlice(f.TransactionIDs)),
+		subject, activity, ctr,
+		f.TotalAmount, f.Currency, "", narrative, r.keys.CurrentVersion(),
+		f.NarrativeDraftedAt, f.NarrativeDraftSHA256,
+		f.PreparedBy, f.ReviewedBy, f.ApprovedBy,
+		f.ActivityStartDate, f.ActivityEndDate, f.FilingDueDate,
+		f.SubmittedAt, f.ConfirmationNumber, f.RejectionReason,
+		f.DeadlineWarningDays, f.OverdueAlertedAt,
+		f.AmendedFromID, f.AmendmentReason,
+		f.CreatedAt, f.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert filing: %w", err)
+	}
+
+	return nil
+}
+
+// updateFiling writes all mutable fields of a filing. A non-empty
+// expectedStatus makes the update conditional on the stored status.
+func (r *FilingRepository) updateFiling(ctx context.Context, db execer, f *domain.RegulatoryFiling, expectedStatus domain.FilingStatus) (sql.Result, error) {
+	subject, activity, ctr, narrative, err := r.sealFilingContent(f)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE regulatory_filings SET
+		bsa_filing_id = $2, status = $3, transaction_ids = $4,
+		subject_info = $5, suspicious_activity = $6, ctr_details = $7,
+		total_amount = $8, currency = $9, narrative = '', narrative_encrypted = $10,
+		encryption_key_version = $11,
+		reviewed_by = $12, approved_by = $13,
+		activity_start_date = $14, activity_end_date = $15, filing_due_date = $16,
+		submitted_at = $17, confirmation_number = $18, rejection_reason = $19,
+		deadline_warning_days = $20, overdue_alerted_at = $21,
+		narrative_drafted_at = $22, narrative_draft_sha256 = $23,
+		updated_at = $24
+		WHERE id = $1 AND ($25 = '' OR status = $25) AND ($26 = '' OR tenant_id = $26)`
+
+	res, err := db.ExecContext(ctx, query,
+		f.ID, f.BSAFilingID, f.Status, pq.Array(nonNilSlice(f.TransactionIDs)),
+		subject, activity, ctr,
+		f.TotalAmount, f.Currency, narrative,
+		r.keys.CurrentVersion(),
+		f.ReviewedBy, f.ApprovedBy,
+		f.ActivityStartDate, f.ActivityEndDate, f.FilingDueDate,
+		f.SubmittedAt, f.ConfirmationNumber, f.RejectionReason,
+		f.DeadlineWarningDays, f.OverdueAlertedAt,
+		f.NarrativeDraftedAt, f.NarrativeDraftSHA256,
+		f.UpdatedAt, expectedStatus, tenantScope(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update filing: %w", err)
+	}
+
+	return res, nil
+}
+
+// sealFilingContent encodes the JSONB content columns of a filing and
+// encrypts its PII. Values that are already ciphertext are kept, so a
+// filing read without PII access can be written back unchanged.
+func (r *FilingRepository) sealFilingContent(f *domain.RegulatoryFiling) (subject, activity, ctr []byte, narrative string, err error) {
+	if f.SubjectInfo != nil {
+		s := *f.SubjectInfo
+		if err = r.reencryptFields(&s.SSN, &s.IDNumber); err != nil {
+			return nil, nil, nil, "", fmt.Errorf("encrypt subject info: %w", err)
+		}
+		if subject, err = json.Marshal(s); err != nil {
+			return nil, nil, nil, "", fmt.Errorf("marshal subject info: %w", err)
+		}
+	}
+	if f.SuspiciousActivity != nil {
+		if activity, err = json.Marshal(f.SuspiciousActivity); err != nil {
+			return nil, nil, nil, "", fmt.Errorf("marshal suspicious activity: %w", err)
+		}
+	}
+	if f.CTRDetails != nil {
+		c := *f.CTRDetails
+		if err = r.reencryptFields(&c.ConductorSSN, &c.ConductorIDNumber); err != nil {
+			return nil, nil, nil, "", fmt.Errorf("encrypt ctr details: %w", err)
+		}
+		if ctr, err = json.Marshal(c); err != nil {
+			return nil, nil, nil, "", fmt.Errorf("marshal ctr details: %w", err)
+		}
+	}
+	if narrative, err = r.keys.Reencrypt(f.Narrative); err != nil {
+		return nil, nil, nil, "", fmt.Errorf("encrypt narrative: %w", err)
+	}
+	return subject, activity, ctr, narrative, nil
+}
+
+// reencryptFields brings each field up to the current key in place
+func (r *FilingRepository) reencryptFields(fields ...*string) error {
+	for _, field := range fields {
+		sealed, err := r.keys.Reencrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = sealed
+	}
+	return nil
+}
+
+// decryptFields decrypts each ciphertext field in place; plaintext left by
+// rows written before encryption is kept as is
+func (r *FilingRepository) decryptFields(fields ...*string) error {
+	for _, field := range fields {
+		if !crypto.IsCiphertext(*field) {
+			continue
+		}
+		plain, err := r.keys.Decrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = plain
+	}
+	return nil
+}
+
+// scanFiling reads a filing row, decrypting PII for contexts with access
+func (r *FilingRepository) scanFiling(ctx context.Context, row rowScanner) (*domain.RegulatoryFiling, error) {
+	f, err := scanFilingRow(row)
+	if err != nil || !crypto.HasPIIAccess(ctx) {
+		return f, err
+	}
+
+	fields := []*string{&f.Narrative}
+	if f.SubjectInfo != nil {
+		fields = append(fields, &f.SubjectInfo.SSN, &f.SubjectInfo.IDNumber)
+	}
+	if f.CTRDetails != nil {
+		fields = append(fields, &f.CTRDetails.ConductorSSN, &f.CTRDetails.ConductorIDNumber)
+	}
+	if err := r.decryptFields(fields...); err != nil {
+		return nil, fmt.Errorf("decrypt filing %s: %w", f.ID, err)
+	}
+	return f, nil
+}
+
+func scanFilingRow(row rowScanner) (*domain.RegulatoryFiling, error) {
+	var f domain.RegulatoryFiling
+	var subject, activity, ctr []byte
+	var narrative, narrativeEncrypted string
+	var keyVersion int
+	var warningDays sql.NullInt64
+
+	err := row.Scan(
+		&f.ID, &f.FilingNumber, &f.BSAFilingID, &f.TenantID, &f.FilingType, &f.Status,
+		&f.UserID, &f.InvestigationID, pq.Array(&f.TransactionIDs),
+		&subject, &activity, &ctr,
+		&f.TotalAmount, &f.Currency, &narrative, &narrativeEncrypted, &keyVersion,
+		&f.NarrativeDraftedAt, &f.NarrativeDraftSHA256,
+		&f.PreparedBy, &f.ReviewedBy, &f.ApprovedBy,
+		&f.ActivityStartDate, &f.ActivityEndDate, &f.FilingDueDate,
+		&f.SubmittedAt, &f.ConfirmationNumber, &f.RejectionReason,
+		&warningDays, &f.OverdueAlertedAt,
+		&f.AmendedFromID, &f.AmendmentReason,
+		&f.CreatedAt, &f.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan filing: %w", err)
+	}
+
+	// Rows written before encryption keep the narrative in plaintext
+	f.Narrative = narrativeEncrypted
+	if f.Narrative == "" {
+		f.Narrative = narrative
+	}
+
+	if warningDays.Valid {
+		days := int(warningDays.Int64)
+		f.DeadlineWarningDays = &days
+	}
+
+	if len(subject) > 0 {
+		if err := json.Unmarshal(subject, &f.SubjectInfo); err != nil {
+			return nil, fmt.Errorf("unmarshal subject info: %w", err)
+		}
+	}
+	if len(activity) > 0 {
+		if err := json.Unmarshal(activity, &f.SuspiciousActivity); err != nil {
+			return nil, fmt.Errorf("unmarshal suspicious activity: %w", err)
+		}
+	}
+	if len(ctr) > 0 {
+		if err := json.Unmarshal(ctr, &f.CTRDetails); err != nil {
+			return nil, fmt.Errorf("unmarshal ctr details: %w", err)
+		}
+	}
+
+	return &f, nil
+}
+
+// ReencryptBatch moves up to limit filings sealed under an older key, or
+// written before encryption, onto the current key and returns how many were
+// rewritten. Rows are locked with SKIP LOCKED so rotation runs alongside
+// normal traffic and concurrent rotators take disjoint batches.
+func (r *FilingRepository) ReencryptBatch(ctx context.Context, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, subject_info, ctr_details, narrative, narrative_encrypted
+		FROM regulatory_filings
+		WHERE encryption_key_version <> $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, r.keys.CurrentVersion(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("list filings for key rotation: %w", err)
+	}
+
+	type sealedRow struct {
+		id                    uuid.UUID
+		subject, ctr          []byte
+		narrative, narrativeE string
+	}
+	var batch []sealedRow
+	for rows.Next() {
+		var s sealedRow
+		if err := rows.Scan(&s.id, &s.subject, &s.ctr, &s.narrative, &s.narrativeE); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan filing for key rotation: %w", err)
+		}
+		batch = append(batch, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list filings for key rotation: %w", err)
+	}
+
+	for _, s := range batch {
+		f := &domain.RegulatoryFiling{Narrative: s.narrativeE}
+		if f.Narrative == "" {
+			f.Narrative = s.narrative
+		}
+		if len(s.subject) > 0 {
+			if err := json.Unmarshal(s.subject, &f.SubjectInfo); err != nil {
+				return 0, fmt.Errorf("unmarshal subject info: %w", err)
+			}
+		}
+		if len(s.ctr) > 0 {
+			if err := json.Unmarshal(s.ctr, &f.CTRDetails); err != nil {
+				return 0, fmt.Errorf("unmarshal ctr details: %w", err)
+			}
+		}
+
+		subject, _, ctr, narrative, err := r.sealFilingContent(f)
+		if err != nil {
+			return 0, fmt.Errorf("reencrypt filing %s: %w", s.id, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE regulatory_filings SET
+			subject_info = $2, ctr_details = $3, narrative = '', narrative_encrypted = $4,
+			encryption_key_version = $5
+			WHERE id = $1`,
+			s.id, subject, ctr, narrative, r.keys.CurrentVersion(),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("update filing %s: %w", s.id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit key rotation: %w", err)
+	}
+	return len(batch), nil
+}
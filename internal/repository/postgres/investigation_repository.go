@@ -0,0 +1,618 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const investigationColumns = `id, case_number, tenant_id, user_id, transaction_id, screening_result_id, alert_id,
+	status, priority, risk_score, investigation_type,
+	assigned_to, assigned_at, assigned_by,
+	title, description, findings, evidence,
+	decision, decision_reason, decision_by, decision_at,
+	sar_filing_id, ctr_filing_id, due_date, sla_breached, sla_at_risk,
+	created_at, updated_at, closed_at`
+
+// overdueCondition matches investigations whose SLA is breached, either
+// flagged already or past due while still open
+const overdueCondition = `(sla_breached OR (status <> 'CLOSED' AND due_date < NOW()))`
+
+// inTenantInvestigation is a condition that the investigation identified
+// by investigationID belongs to the tenant in the tenantParam parameter,
+// any tenant if that is empty
+func inTenantInvestigation(tenantParam, investigationID string) string {
+	return `(` + tenantParam + ` = '' OR EXISTS (SELECT 1 FROM investigations i
+		WHERE i.id = ` + investigationID + ` AND i.tenant_id = ` + tenantParam + `))`
+}
+
+// InvestigationRepository persists investigations in PostgreSQL
+type InvestigationRepository struct {
+	db *sql.DB
+}
+
+// NewInvestigationRepository creates a new investigation repository
+func NewInvestigationRepository(db *sql.DB) *InvestigationRepository {
+	return &InvestigationRepository{db: db}
+}
+
+// Create inserts an investigation and records who opened it on its timeline
+func (r *InvestigationRepository) Create(ctx context.Context, inv *domain.Investigation, openedBy uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertInvestigation(ctx, tx, inv, openedBy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit investigation: %w", err)
+	}
+	return nil
+}
+
+// insertInvestigation inserts an investigation along with the timeline
+// event recording that openedBy opened it. db should be a transaction.
+func insertInvestigation(ctx context.Context, db execer, inv *domain.Investigation, openedBy uuid.UUID) error {
+	tenantID, err := tenantFor(ctx, inv.TenantID)
+	if err != nil {
+		return err
+	}
+	inv.TenantID = tenantID
+
+	evidence, err := json.Marshal(nonNilSlice(inv.Evidence))
+	if err != nil {
+		return fmt.Errorf("marshal evidence: %w", err)
+	}
+
+	query := `INSERT INTO investigations (` + investigationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			$15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30)`
+
+	_, err = db.ExecContext(ctx, query,
+		inv.ID, inv.CaseNumber, inv.TenantID, inv.UserID, inv.TransactionID, inv.ScreeningResultID, inv.AlertID,
+		inv.Status, inv.Priority, inv.RiskScore, inv.InvestigationType,
+		inv.AssignedTo, inv.AssignedAt, inv.AssignedBy,
+		inv.Title, inv.Description, inv.Findings, evidence,
+		inv.Decision, inv.DecisionReason, inv.DecisionBy, inv.DecisionAt,
+		inv.SARFilingID, inv.CTRFilingID, inv.DueDate, inv.SLABreached, inv.SLAAtRisk,
+		inv.CreatedAt, inv.UpdatedAt, inv.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert investigation: %w", err)
+	}
+
+	return insertTimelineEvent(ctx, db, inv.OpenedEvent(openedBy))
+}
+
+// Update writes all mutable fields of an investigation
+func (r *InvestigationRepository) Update(ctx context.Context, inv *domain.Investigation) error {
+	return updateInvestigation(ctx, r.db, inv)
+}
+
+// Escalate records an automatic SLA escalation in one transaction: the
+// investigation's updated fields, the timeline event and, unless nil, the
+// alert raised for it. A failure leaves none of them written, so the SLA
+// scan finds the case again.
+func (r *InvestigationRepository) Escalate(ctx context.Context, inv *domain.Investigation, event *domain.InvestigationTimeline, alert *domain.AMLAlert) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateInvestigation(ctx, tx, inv); err != nil {
+		return err
+	}
+	if err := insertTimelineEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	if alert != nil {
+		if err := insertAlert(ctx, tx, alert); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit escalation: %w", err)
+	}
+	return nil
+}
+
+// updateInvestigation writes all mutable fields of an investigation of the
+// tenant ctx acts for, returning ErrNotFound if there is none
+func updateInvestigation(ctx context.Context, db execer, inv *domain.Investigation) error {
+	evidence, err := json.Marshal(nonNilSlice(inv.Evidence))
+	if err != nil {
+		return fmt.Errorf("marshal evidence: %w", err)
+	}
+
+	query := `UPDATE investigations SET
+		status = $2, priority = $3, risk_score = $4,
+		assigned_to = $5, assigned_at = $6, assigned_by = $7,
+		title = $8, description = $9, findings = $10, evidence = $11,
+		decision = $12, decision_reason = $13, decision_by = $14, decision_at = $15,
+		sar_filing_id = $16, ctr_filing_id = $17, due_date = $18, sla_breached = $19,
+		sla_at_risk = $20, updated_at = $21, closed_at = $22
+		WHERE id = $1 AND ($23 = '' OR tenant_id = $23)`
+
+	res, err := db.ExecContext(ctx, query,
+		inv.ID, inv.Status, inv.Priority, inv.RiskScore,
+		inv.AssignedTo, inv.AssignedAt, inv.AssignedBy,
+		inv.Title, inv.Description, inv.Findings, evidence,
+		inv.Decision, inv.DecisionReason, inv.DecisionBy, inv.DecisionAt,
+		inv.SARFilingID, inv.CTRFilingID, inv.DueDate, inv.SLABreached,
+		inv.SLAAtRisk, inv.UpdatedAt, inv.ClosedAt, tenantScope(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("update investigation: %w", err)
+	}
+
+	return requireAffected(res)
+}
+
+// GetByID returns an investigation by ID
+func (r *InvestigationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Investigation, error) {
+	query := `SELECT ` + investigationColumns + ` FROM investigations
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
+
+	inv, err := scanInvestigation(r.db.QueryRowContext(ctx, query, id, tenantScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return inv, err
+}
+
+// List returns investigations matching the filter, newest first, along
+// with the total number of matching rows
+func (r *InvestigationRepository) List(ctx context.Context, filter domain.InvestigationFilter) ([]*domain.Investigation, int, error) {
+	where, args := buildInvestigationWhere(tenantScope(ctx), filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM investigations` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count investigations: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM investigations%s ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`,
+		investigationColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list investigations: %w", err)
+	}
+	defer rows.Close()
+
+	investigations := make([]*domain.Investigation, 0, filter.Limit)
+	for rows.Next() {
+		inv, err := scanInvestigation(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		investigations = append(investigations, inv)
+	}
+
+	return investigations, total, rows.Err()
+}
+
+// buildInvestigationWhere builds the WHERE clause and positional args for a
+// filter within a tenant; an empty tenant matches every tenant
+func buildInvestigationWhere(tenantID string, filter domain.InvestigationFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if tenantID != "" {
+		add("tenant_id = $%d", tenantID)
+	}
+	if filter.Status != nil {
+		add("status = $%d", *filter.Status)
+	}
+	if filter.Priority != nil {
+		add("priority = $%d", *filter.Priority)
+	}
+	if filter.AssignedTo != nil {
+		add("assigned_to = $%d", *filter.AssignedTo)
+	}
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.SLABreached != nil {
+		if *filter.SLABreached {
+			conditions = append(conditions, overdueCondition)
+		} else {
+			conditions = append(conditions, "NOT "+overdueCondition)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanInvestigation(row rowScanner) (*domain.Investigation, error) {
+	var inv domain.Investigation
+	var evidence []byte
+
+	err := row.Scan(
+		&inv.ID, &inv.CaseNumber, &inv.TenantID, &inv.UserID, &inv.TransactionID, &inv.ScreeningResultID, &inv.AlertID,
+		&inv.Status, &inv.Priority, &inv.RiskScore, &inv.InvestigationType,
+		&inv.AssignedTo, &inv.AssignedAt, &inv.AssignedBy,
+		&inv.Title, &inv.Description, &inv.Findings, &evidence,
+		&inv.Decision, &inv.DecisionReason, &inv.DecisionBy, &inv.DecisionAt,
+		&inv.SARFilingID, &inv.CTRFilingID, &inv.DueDate, &inv.SLABreached, &inv.SLAAtRisk,
+		&inv.CreatedAt, &inv.UpdatedAt, &inv.ClosedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan investigation: %w", err)
+	}
+
+	if err := json.Unmarshal(evidence, &inv.Evidence); err != nil {
+		return nil, fmt.Errorf("unmarshal evidence: %w", err)
+	}
+
+	return &inv, nil
+}
+
+// ListOverdue returns open investigations past their due date at now that
+// have not yet been flagged as SLA breached
+func (r *InvestigationRepository) ListOverdue(ctx context.Context, now time.Time, limit int) ([]*domain.Investigation, error) {
+	query := `SELECT ` + investigationColumns + ` FROM investigations
+		WHERE status <> 'CLOSED' AND due_date < $3 AND NOT sla_breached
+			AND ($2 = '' OR tenant_id = $2)
+		ORDER BY due_date
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, tenantScope(ctx), now)
+	if err != nil {
+		return nil, fmt.Errorf("list overdue investigations: %w", err)
+	}
+	defer rows.Close()
+
+	var investigations []*domain.Investigation
+	for rows.Next() {
+		inv, err := scanInvestigation(rows)
+		if err != nil {
+			return nil, err
+		}
+		investigations = append(investigations, inv)
+	}
+
+	return investigations, rows.Err()
+}
+
+// ListOpenByAssignee returns the open investigations assigned to an
+// analyst, soonest due first
+func (r *InvestigationRepository) ListOpenByAssignee(ctx context.Context, analystID uuid.UUID, limit int) ([]*domain.Investigation, error) {
+	query := `SELECT ` + investigationColumns + ` FROM investigations
+		WHERE assigned_to = $1 AND status <> 'CLOSED' AND ($3 = '' OR tenant_id = $3)
+		ORDER BY due_date
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, analystID, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list investigations by assignee: %w", err)
+	}
+	defer rows.Close()
+
+	var investigations []*domain.Investigation
+	for rows.Next() {
+		inv, err := scanInvestigation(rows)
+		if err != nil {
+			return nil, err
+		}
+		investigations = append(investigations, inv)
+	}
+
+	return investigations, rows.Err()
+}
+
+// ListAtRisk returns open, unassigned investigations that have used at
+// least the given share of their SLA by now and have not yet been flagged
+// as at risk or breached
+func (r *InvestigationRepository) ListAtRisk(ctx context.Context, now time.Time, share float64, limit int) ([]*domain.Investigation, error) {
+	query := `SELECT ` + investigationColumns + ` FROM investigations
+		WHERE status <> 'CLOSED' AND NOT sla_breached AND NOT sla_at_risk
+			AND assigned_to IS NULL
+			AND $4 >= created_at + (due_date - created_at) * $1
+			AND ($3 = '' OR tenant_id = $3)
+		ORDER BY due_date
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, share, limit, tenantScope(ctx), now)
+	if err != nil {
+		return nil, fmt.Errorf("list at-risk investigations: %w", err)
+	}
+	defer rows.Close()
+
+	var investigations []*domain.Investigation
+	for rows.Next() {
+		inv, err := scanInvestigation(rows)
+		if err != nil {
+			return nil, err
+		}
+		investigations = append(investigations, inv)
+	}
+
+	return investigations, rows.Err()
+}
+
+// CreateAutoAssigned inserts an investigation, opened by openedBy, assigned to the least loaded
+// analyst with capacity, chosen by domain.SelectAnalyst, and records the
+// assignment on its timeline. Assignments are serialised so concurrent cases
+// cannot overfill an analyst. When nobody has capacity the investigation is
+// inserted unassigned and nil is returned.
+func (r *InvestigationRepository) CreateAutoAssigned(ctx context.Context, inv *domain.Investigation, openedBy uuid.UUID, maxOpen int) (*domain.Analyst, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('investigation_assignment'))`); err != nil {
+		return nil, fmt.Errorf("lock investigation assignment: %w", err)
+	}
+
+	analysts, err := listActiveAnalysts(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	analyst := domain.SelectAnalyst(analysts, inv.InvestigationType, maxOpen)
+	if analyst != nil {
+		inv.Assign(analyst.ID, domain.SystemActorID, inv.CreatedAt)
+	}
+
+	if err := insertInvestigation(ctx, tx, inv, openedBy); err != nil {
+		return nil, err
+	}
+
+	if analyst != nil {
+		analyst.OpenCases++
+		err := insertTimelineEvent(ctx, tx, &domain.InvestigationTimeline{
+			ID:              uuid.New(),
+			InvestigationID: inv.ID,
+			EventType:       domain.TimelineEventAssigned,
+			Description: fmt.Sprintf("Auto-assigned to %s (%d of %d open cases)",
+				analyst.Name, analyst.OpenCases, analyst.OpenCases+analyst.Capacity(maxOpen)),
+			NewValue:  analyst.ID.String(),
+			ActorID:   domain.SystemActorID,
+			CreatedAt: inv.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit investigation assignment: %w", err)
+	}
+	return analyst, nil
+}
+
+// AddEvidence appends evidence to an investigation and records it on the
+// timeline in the same transaction
+func (r *InvestigationRepository) AddEvidence(ctx context.Context, id uuid.UUID, ev *domain.Evidence, event *domain.InvestigationTimeline) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal evidence: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE investigations
+		SET evidence = evidence || jsonb_build_array($2::jsonb), updated_at = $3
+		WHERE id = $1 AND ($4 = '' OR tenant_id = $4)`, id, data, ev.AddedAt, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("add evidence: %w", err)
+	}
+	if err := requireAffected(res); err != nil {
+		return err
+	}
+	if err := insertTimelineEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit evidence: %w", err)
+	}
+	return nil
+}
+
+// UpdateEvidence applies fn to one evidence item under a row lock and, if it
+// succeeds, writes the evidence back and records event on the timeline
+func (r *InvestigationRepository) UpdateEvidence(
+	ctx context.Context,
+	id, evidenceID uuid.UUID,
+	fn func(*domain.Evidence) (*domain.InvestigationTimeline, error),
+) (*domain.Evidence, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var data []byte
+	err = tx.QueryRowContext(ctx, `SELECT evidence FROM investigations
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+		FOR UPDATE`, id, tenantScope(ctx)).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get evidence: %w", err)
+	}
+
+	inv := &domain.Investigation{ID: id}
+	if err := json.Unmarshal(data, &inv.Evidence); err != nil {
+		return nil, fmt.Errorf("unmarshal evidence: %w", err)
+	}
+	ev, err := inv.FindEvidence(evidenceID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := fn(ev)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(nonNilSlice(inv.Evidence))
+	if err != nil {
+		return nil, fmt.Errorf("marshal evidence: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE investigations SET evidence = $2, updated_at = $3 WHERE id = $1`,
+		id, data, event.CreatedAt); err != nil {
+		return nil, fmt.Errorf("update evidence: %w", err)
+	}
+	if err := insertTimelineEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit evidence: %w", err)
+	}
+	return ev, nil
+}
+
+// AddTimelineEvent appends an event to an investigation's timeline
+func (r *InvestigationRepository) AddTimelineEvent(ctx context.Context, event *domain.InvestigationTimeline) error {
+	return insertTimelineEvent(ctx, r.db, event)
+}
+
+// insertTimelineEvent inserts an event on the timeline of an investigation
+// of the tenant ctx acts for, returning ErrNotFound if there is none
+func insertTimelineEvent(ctx context.Context, db execer, event *domain.InvestigationTimeline) error {
+	query := `INSERT INTO investigation_timeline
+		(id, investigation_id, event_type, description, old_value, new_value, actor_id, created_at)
+		SELECT $1::uuid, $2::uuid, $3, $4, $5, $6, $7::uuid, $8::timestamptz
+		WHERE ` + inTenantInvestigation(`$9`, `$2`)
+
+	res, err := db.ExecContext(ctx, query,
+		event.ID, event.InvestigationID, event.EventType, event.Description,
+		event.OldValue, event.NewValue, event.ActorID, event.CreatedAt, tenantScope(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("insert timeline event: %w", err)
+	}
+
+	return requireAffected(res)
+}
+
+// ListTimeline returns an investigation's timeline in the order the events
+// happened. An opening event sorts ahead of events recorded at the same
+// instant, such as an automatic assignment.
+func (r *InvestigationRepository) ListTimeline(ctx context.Context, investigationID uuid.UUID) ([]*domain.InvestigationTimeline, error) {
+	query := `SELECT id, investigation_id, event_type, description, old_value, new_value, actor_id, created_at
+		FROM investigation_timeline
+		WHERE investigation_id = $1 AND ` + inTenantInvestigation(`$3`, `$1`) + `
+		ORDER BY created_at, event_type <> $2, id`
+
+	rows, err := r.db.QueryContext(ctx, query, investigationID, domain.TimelineEventOpened, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list timeline events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.InvestigationTimeline{}
+	for rows.Next() {
+		var e domain.InvestigationTimeline
+		err := rows.Scan(&e.ID, &e.InvestigationID, &e.EventType, &e.Description,
+			&e.OldValue, &e.NewValue, &e.ActorID, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan timeline event: %w", err)
+		}
+		events = append(events, &e)
+	}
+
+	return events, rows.Err()
+}
+
+// AddNote inserts a note and records it on the timeline in the same
+// transaction
+func (r *InvestigationRepository) AddNote(ctx context.Context, note *domain.InvestigationNote, event *domain.InvestigationTimeline) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO investigation_notes
+		(id, investigation_id, author_id, content, is_internal, created_at, updated_at)
+		SELECT $1::uuid, $2::uuid, $3::uuid, $4, $5::boolean, $6::timestamptz, $7::timestamptz
+		WHERE `+inTenantInvestigation(`$8`, `$2`),
+		note.ID, note.InvestigationID, note.AuthorID, note.Content, note.IsInternal,
+		note.CreatedAt, note.UpdatedAt, tenantScope(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("insert note: %w", err)
+	}
+	if err := requireAffected(res); err != nil {
+		return err
+	}
+	if err := insertTimelineEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit note: %w", err)
+	}
+	return nil
+}
+
+// ListNotes returns an investigation's notes, oldest first. Internal notes
+// are included only when includeInternal is set.
+func (r *InvestigationRepository) ListNotes(ctx context.Context, investigationID uuid.UUID, includeInternal bool) ([]*domain.InvestigationNote, error) {
+	query := `SELECT id, investigation_id, author_id, content, is_internal, created_at, updated_at
+		FROM investigation_notes
+		WHERE investigation_id = $1 AND ($2 OR NOT is_internal) AND ` + inTenantInvestigation(`$3`, `$1`) + `
+		ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, investigationID, includeInternal, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*domain.InvestigationNote{}
+	for rows.Next() {
+		var n domain.InvestigationNote
+		err := rows.Scan(&n.ID, &n.InvestigationID, &n.AuthorID, &n.Content, &n.IsInternal,
+			&n.CreatedAt, &n.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		notes = append(notes, &n)
+	}
+
+	return notes, rows.Err()
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// AdvisoryLocker coordinates background jobs across instances using
+// PostgreSQL session-level advisory locks
+type AdvisoryLocker struct {
+	db *sql.DB
+}
+
+// NewAdvisoryLocker creates a new advisory locker
+func NewAdvisoryLocker(db *sql.DB) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db}
+}
+
+// TryLock attempts to take the lock identified by key without blocking.
+// When acquired, the returned release function must be called to unlock.
+func (l *AdvisoryLocker) TryLock(ctx context.Context, key int64) (release func(), acquired bool, err error) {
+	// Advisory locks belong to a session, so hold a dedicated connection
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("try advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release = func() {
+		// Use a fresh context so the unlock runs even if ctx was cancelled
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key)
+		conn.Close()
+	}
+	return release, true, nil
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// ReportRepository aggregates activity for management information reports
+// and stores the report snapshots in PostgreSQL
+type ReportRepository struct {
+	db *sql.DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// The aggregates cover the tenant the context acts for, or every tenant for
+// a context naming none, and snapshots are stored per tenant the same way.
+
+// ScreeningStats counts first-time screenings created in [start, end) by
+// decision and list match
+func (r *ReportRepository) ScreeningStats(ctx context.Context, start, end time.Time) (domain.MIScreening, error) {
+	query := `SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE decision = $3),
+			COUNT(*) FILTER (WHERE decision = $4),
+			COUNT(*) FILTER (WHERE decision = $5),
+			COUNT(*) FILTER (WHERE decision = $6),
+			COUNT(*) FILTER (WHERE (ofac_match->>'matched')::boolean),
+			COUNT(*) FILTER (WHERE (pep_match->>'matched')::boolean)
+		FROM screening_results
+		WHERE created_at >= $1 AND created_at < $2 AND rescreen_of_id IS NULL
+			AND ($7 = '' OR tenant_id = $7)`
+
+	var s domain.MIScreening
+	err := r.db.QueryRowContext(ctx, query, start, end,
+		domain.DecisionApproved, domain.DecisionSuspicious, domain.DecisionBlocked, domain.DecisionPending,
+		tenantScope(ctx),
+	).Scan(&s.Total, &s.Approved, &s.Suspicious, &s.Blocked, &s.Pending, &s.OFACHits, &s.PEPHits)
+	if err != nil {
+		return s, fmt.Errorf("query screening stats: %w", err)
+	}
+
+	s.Hits = s.Suspicious + s.Blocked
+	return s, nil
+}
+
+// InvestigationStats counts investigations opened and closed in [start, end)
+// and how many of the closed ones were false positives
+func (r *ReportRepository) InvestigationStats(ctx context.Context, start, end time.Time) (domain.MIInvestigations, error) {
+	query := `SELECT
+			COUNT(*) FILTER (WHERE created_at >= $1 AND created_at < $2),
+			COUNT(*) FILTER (WHERE closed_at >= $1 AND closed_at < $2),
+			COUNT(*) FILTER (WHERE closed_at >= $1 AND closed_at < $2 AND decision = $3)
+		FROM investigations
+		WHERE ((created_at >= $1 AND created_at < $2) OR (closed_at >= $1 AND closed_at < $2))
+			AND ($4 = '' OR tenant_id = $4)`
+
+	var s domain.MIInvestigations
+	err := r.db.QueryRowContext(ctx, query, start, end, domain.DecisionFalsePositive, tenantScope(ctx)).
+		Scan(&s.Opened, &s.Closed, &s.FalsePositives)
+	if err != nil {
+		return s, fmt.Errorf("query investigation stats: %w", err)
+	}
+
+	return s, nil
+}
+
+// FilingStats counts filings of the given type submitted in [start, end),
+// split into originals and amendments, whether the originals met their due
+// date, and originals due in the period that missed it
+func (r *ReportRepository) FilingStats(ctx context.Context, filingType domain.FilingType, start, end time.Time) (domain.MIFilings, error) {
+	query := `SELECT
+			COUNT(*) FILTER (WHERE submitted_at >= $2 AND submitted_at < $3 AND amended_from_id IS NULL),
+			COUNT(*) FILTER (WHERE submitted_at >= $2 AND submitted_at < $3 AND amended_from_id IS NOT NULL),
+			COUNT(*) FILTER (WHERE submitted_at >= $2 AND submitted_at < $3 AND amended_from_id IS NULL
+				AND submitted_at <= filing_due_date),
+			COUNT(*) FILTER (WHERE submitted_at >= $2 AND submitted_at < $3 AND amended_from_id IS NULL
+				AND submitted_at > filing_due_date),
+			COUNT(*) FILTER (WHERE filing_due_date >= $2 AND filing_due_date < $3 AND amended_from_id IS NULL
+				AND (submitted_at IS NULL OR submitted_at > filing_due_date))
+		FROM regulatory_filings
+		WHERE filing_type = $1
+			AND ((submitted_at >= $2 AND submitted_at < $3) OR (filing_due_date >= $2 AND filing_due_date < $3))
+			AND ($4 = '' OR tenant_id = $4)`
+
+	var s domain.MIFilings
+	err := r.db.QueryRowContext(ctx, query, filingType, start, end, tenantScope(ctx)).
+		Scan(&s.Submitted, &s.Amendments, &s.OnTime, &s.Late, &s.PastDue)
+	if err != nil {
+		return s, fmt.Errorf("query %s filing stats: %w", filingType, err)
+	}
+
+	return s, nil
+}
+
+// TopPatterns returns the pattern types detected most often in first-time
+// screenings created in [start, end), most frequent first
+func (r *ReportRepository) TopPatterns(ctx context.Context, start, end time.Time, limit int) ([]domain.PatternCount, error) {
+	query := `SELECT pm->>'pattern_type' AS pattern_type, COUNT(*) AS n
+		FROM screening_results, jsonb_array_elements(pattern_matches) AS pm
+		WHERE created_at >= $1 AND created_at < $2 AND rescreen_of_id IS NULL
+			AND ($4 = '' OR tenant_id = $4)
+		GROUP BY pattern_type
+		ORDER BY n DESC, pattern_type
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("query top patterns: %w", err)
+	}
+	defer rows.Close()
+
+	patterns := []domain.PatternCount{}
+	for rows.Next() {
+		var p domain.PatternCount
+		if err := rows.Scan(&p.PatternType, &p.Count); err != nil {
+			return nil, fmt.Errorf("scan pattern count: %w", err)
+		}
+		patterns = append(patterns, p)
+	}
+
+	return patterns, rows.Err()
+}
+
+// GetSnapshot returns the stored report for the period starting at start,
+// or domain.ErrNotFound
+func (r *ReportRepository) GetSnapshot(ctx context.Context, period domain.ReportPeriod, start time.Time) (*domain.MIReport, error) {
+	query := `SELECT report FROM mi_reports WHERE tenant_id = $3 AND period = $1 AND period_start = $2`
+
+	var raw []byte
+	err := r.db.QueryRowContext(ctx, query, period, start, tenantScope(ctx)).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get mi report: %w", err)
+	}
+
+	var report domain.MIReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return nil, fmt.Errorf("unmarshal mi report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// SaveSnapshot stores a report, replacing any earlier snapshot of the same period
+func (r *ReportRepository) SaveSnapshot(ctx context.Context, report *domain.MIReport) error {
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("marshal mi report: %w", err)
+	}
+
+	query := `INSERT INTO mi_reports (period, period_start, period_end, report, generated_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, period, period_start) DO UPDATE SET
+			period_end = EXCLUDED.period_end,
+			report = EXCLUDED.report,
+			generated_at = EXCLUDED.generated_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		report.Period, report.PeriodStart, report.PeriodEnd, raw, report.GeneratedAt, tenantScope(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("save mi report: %w", err)
+	}
+
+	return nil
+}
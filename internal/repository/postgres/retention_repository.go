@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// retentionTable describes how an entity type is stored for archival
+type retentionTable struct {
+	table      string
+	timeColumn string // the record time the hot window is measured from
+	eligible   string // condition a record must meet to be archived
+}
+
+var retentionTables = map[string]retentionTable{
+	domain.RetentionScreeningResults: {
+		table:      "screening_results",
+		timeColumn: "created_at",
+		eligible:   "TRUE",
+	},
+	// Open alerts and alerts attached to a case stay in Postgres
+	domain.RetentionAlerts: {
+		table:      "aml_alerts",
+		timeColumn: "detected_at",
+		eligible:   "status IN ('DISMISSED', 'RESOLVED') AND investigation_id IS NULL",
+	},
+}
+
+// archivePageSize is how many records StreamArchivable reads per query
+const archivePageSize = 500
+
+// RetentionRepository selects aged records for archival, deletes them once
+// archived, re-imports restored archives and keeps the archive manifest and
+// retention holds. Records are archived as their row_to_json form, so an
+// archive restores every column without the repository knowing them.
+type RetentionRepository struct {
+	db *sql.DB
+}
+
+// NewRetentionRepository creates a new retention repository
+func NewRetentionRepository(db *sql.DB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+func retentionTableFor(entityType string) (retentionTable, error) {
+	t, ok := retentionTables[entityType]
+	if !ok {
+		return t, fmt.Errorf("%w: unknown retention entity type %q", domain.ErrValidation, entityType)
+	}
+	return t, nil
+}
+
+// scopeCondition is the WHERE condition selecting the records of an
+// ArchiveScope from table alias r. The scope's entity type, from, to and
+// as-of time are bound to $1 to $4. Records under an active retention hold
+// are excluded.
+func (t retentionTable) scopeCondition() string {
+	return fmt.Sprintf(`r.%[1]s >= $2 AND r.%[1]s < $3 AND r.updated_at <= $4 AND (%[2]s)
+			AND NOT EXISTS (
+				SELECT 1 FROM retention_holds h
+				WHERE h.entity_type = $1 AND h.hold_until > NOW()
+					AND r.%[1]s >= h.period_start AND r.%[1]s < h.period_end
+			)`, t.timeColumn, t.eligible)
+}
+
+func scopeArgs(scope *domain.ArchiveScope) []interface{} {
+	return []interface{}{scope.EntityType, scope.From, scope.To, scope.AsOf}
+}
+
+// OldestArchivable returns the record time of the oldest record of the
+// entity type that is eligible for archival, older than before and last
+// updated no later than asOf, and false when there is none.
+func (r *RetentionRepository) OldestArchivable(ctx context.Context, entityType string, before, asOf time.Time) (time.Time, bool, error) {
+	t, err := retentionTableFor(entityType)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	scope := &domain.ArchiveScope{EntityType: entityType, To: before, AsOf: asOf}
+	query := fmt.Sprintf(`SELECT MIN(r.%s) FROM %s r WHERE %s`, t.timeColumn, t.table, t.scopeCondition())
+
+	var oldest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, scopeArgs(scope)...).Scan(&oldest); err != nil {
+		return time.Time{}, false, fmt.Errorf("find oldest archivable %s: %w", entityType, err)
+	}
+	return oldest.Time, oldest.Valid, nil
+}
+
+// CountArchivable counts the records in scope
+func (r *RetentionRepository) CountArchivable(ctx context.Context, scope *domain.ArchiveScope) (int, error) {
+	t, err := retentionTableFor(scope.EntityType)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s r WHERE %s`, t.table, t.scopeCondition())
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, scopeArgs(scope)...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count archivable %s: %w", scope.EntityType, err)
+	}
+	return count, nil
+}
+
+// StreamArchivable calls fn with the JSON form of each record in scope,
+// oldest first. Records are read in pages keyed on the record time and id,
+// so the scope is never held in memory. An error from fn stops the stream
+// and is returned.
+func (r *RetentionRepository) StreamArchivable(ctx context.Context, scope *domain.ArchiveScope, fn func(record []byte) error) error {
+	t, err := retentionTableFor(scope.EntityType)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`SELECT r.id, r.%[1]s, row_to_json(r)::text FROM %[2]s r
+		WHERE %[3]s AND (r.%[1]s, r.id) > ($5, $6)
+		ORDER BY r.%[1]s, r.id
+		LIMIT $7`, t.timeColumn, t.table, t.scopeCondition())
+
+	afterTime, afterID := scope.From, uuid.Nil
+	for {
+		args := append(scopeArgs(scope), afterTime, afterID, archivePageSize)
+		n, err := r.archivePage(ctx, query, args, func(id uuid.UUID, at time.Time, record []byte) error {
+			afterTime, afterID = at, id
+			return fn(record)
+		})
+		if err != nil {
+			return err
+		}
+		if n < archivePageSize {
+			return nil
+		}
+	}
+}
+
+func (r *RetentionRepository) archivePage(ctx context.Context, query string, args []interface{}, fn func(uuid.UUID, time.Time, []byte) error) (int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("list archivable records: %w", err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		var (
+			id     uuid.UUID
+			at     time.Time
+			record []byte
+		)
+		if err := rows.Scan(&id, &at, &record); err != nil {
+			return n, fmt.Errorf("scan archivable record: %w", err)
+		}
+		n++
+		if err := fn(id, at, record); err != nil {
+			return n, err
+		}
+	}
+	return n, rows.Err()
+}
+
+// DeleteArchivable deletes up to limit records in scope and returns how
+// many were deleted. Deleting in bounded batches keeps each statement's
+// locks short.
+func (r *RetentionRepository) DeleteArchivable(ctx context.Context, scope *domain.ArchiveScope, limit int) (int, error) {
+	t, err := retentionTableFor(scope.EntityType)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %[1]s WHERE id IN (
+			SELECT r.id FROM %[1]s r WHERE %[2]s LIMIT $5
+		)`, t.table, t.scopeCondition())
+
+	res, err := r.db.ExecContext(ctx, query, append(scopeArgs(scope), limit)...)
+	if err != nil {
+		return 0, fmt.Errorf("delete archived %s: %w", scope.EntityType, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("delete archived %s: %w", scope.EntityType, err)
+	}
+	return int(n), nil
+}
+
+// Import inserts archived records of the entity type in one transaction
+// and returns how many were inserted. Records still in Postgres are left
+// as they are.
+func (r *RetentionRepository) Import(ctx context.Context, entityType string, records [][]byte) (int, error) {
+	t, err := retentionTableFor(entityType)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(`INSERT INTO %[1]s
+		SELECT * FROM json_populate_record(NULL::%[1]s, $1::json)
+		ON CONFLICT (id) DO NOTHING`, t.table))
+	if err != nil {
+		return 0, fmt.Errorf("prepare %s import: %w", entityType, err)
+	}
+	defer stmt.Close()
+
+	inserted := 0
+	for _, record := range records {
+		res, err := stmt.ExecContext(ctx, string(record))
+		if err != nil {
+			return 0, fmt.Errorf("import %s: %w", entityType, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("import %s: %w", entityType, err)
+		}
+		inserted += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit %s import: %w", entityType, err)
+	}
+	return inserted, nil
+}
+
+// SaveArchive records an archive file in the manifest
+func (r *RetentionRepository) SaveArchive(ctx context.Context, a *domain.RetentionArchive) error {
+	query := `INSERT INTO retention_archives
+		(id, entity_type, period_start, period_end, object_key, row_count, sha256, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		a.ID, a.EntityType, a.PeriodStart, a.PeriodEnd, a.ObjectKey, a.RowCount, a.SHA256, a.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save retention archive: %w", err)
+	}
+	return nil
+}
+
+// ListArchives returns the archives of the entity type whose period
+// overlaps [from, to), oldest first
+func (r *RetentionRepository) ListArchives(ctx context.Context, entityType string, from, to time.Time) ([]*domain.RetentionArchive, error) {
+	query := `SELECT id, entity_type, period_start, period_end, object_key, row_count, sha256, created_at
+		FROM retention_archives
+		WHERE entity_type = $1 AND period_start < $3 AND period_end > $2
+		ORDER BY period_start, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, entityType, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list retention archives: %w", err)
+	}
+	defer rows.Close()
+
+	var archives []*domain.RetentionArchive
+	for rows.Next() {
+		var a domain.RetentionArchive
+		if err := rows.Scan(&a.ID, &a.EntityType, &a.PeriodStart, &a.PeriodEnd, &a.ObjectKey, &a.RowCount, &a.SHA256, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan retention archive: %w", err)
+		}
+		archives = append(archives, &a)
+	}
+	return archives, rows.Err()
+}
+
+// CreateHold stores a retention hold
+func (r *RetentionRepository) CreateHold(ctx context.Context, h *domain.RetentionHold) error {
+	query := `INSERT INTO retention_holds
+		(id, entity_type, period_start, period_end, hold_until, reason, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		h.ID, h.EntityType, h.From, h.To, h.Until, h.Reason, h.ActorID, h.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create retention hold: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+const riskProfileColumns = `id, tenant_id, user_id, risk_score, risk_level, last_assessment, next_review_date,
+	country_risk, occupation_risk, transaction_risk, behavioral_risk, relationship_risk,
+	is_pep, pep_details, is_high_net_worth, has_ofac_match, ofac_match_details,
+	avg_monthly_volume, avg_transaction_amt, tx_count_last_30_days,
+	primary_countries, high_risk_countries,
+	sar_count, investigation_count, blocked_tx_count,
+	on_watchlist, watchlist_reason, watchlist_added_at,
+	created_at, updated_at`
+
+// RiskProfileRepository persists user risk profiles in PostgreSQL. Each
+// tenant has its own profile of a user.
+type RiskProfileRepository struct {
+	db *sql.DB
+}
+
+// NewRiskProfileRepository creates a new risk profile repository
+func NewRiskProfileRepository(db *sql.DB) *RiskProfileRepository {
+	return &RiskProfileRepository{db: db}
+}
+
+// GetByUserID returns a user's risk profile with the tenant ctx acts for, or
+// the default tenant if it names none
+func (r *RiskProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserRiskProfile, error) {
+	query := `SELECT ` + riskProfileColumns + ` FROM user_risk_profiles WHERE tenant_id = $1 AND user_id = $2`
+
+	profile, err := scanRiskProfile(r.db.QueryRowContext(ctx, query, tenant.OrDefault(ctx), userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return profile, err
+}
+
+// Save inserts or updates a risk profile keyed by tenant and user ID. A
+// profile naming a tenant other than the one ctx acts for is refused.
+func (r *RiskProfileRepository) Save(ctx context.Context, p *domain.UserRiskProfile) error {
+	tenantID, err := tenantFor(ctx, p.TenantID)
+	if err != nil {
+		return err
+	}
+	p.TenantID = tenantID
+
+	var pepDetails []byte
+	if p.PEPDetails != nil {
+		var err error
+		if pepDetails, err = json.Marshal(p.PEPDetails); err != nil {
+			return fmt.Errorf("marshal pep details: %w", err)
+		}
+	}
+
+	query := `INSERT INTO user_risk_profiles (` + riskProfileColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30)
+		ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+			risk_score = EXCLUDED.risk_score,
+			risk_level = EXCLUDED.risk_level,
+			last_assessment = EXCLUDED.last_assessment,
+			next_review_date = EXCLUDED.next_review_date,
+			country_risk = EXCLUDED.country_risk,
+			occupation_risk = EXCLUDED.occupation_risk,
+			transaction_risk = EXCLUDED.transaction_risk,
+			behavioral_risk = EXCLUDED.behavioral_risk,
+			relationship_risk = EXCLUDED.relationship_risk,
+			is_pep = EXCLUDED.is_pep,
+			pep_details = EXCLUDED.pep_details,
+			is_high_net_worth = EXCLUDED.is_high_net_worth,
+			has_ofac_match = EXCLUDED.has_ofac_match,
+			ofac_match_details = EXCLUDED.ofac_match_details,
+			avg_monthly_volume = EXCLUDED.avg_monthly_volume,
+			avg_transaction_amt = EXCLUDED.avg_transaction_amt,
+			tx_count_last_30_days = EXCLUDED.tx_count_last_30_days,
+			primary_countries = EXCLUDED.primary_countries,
+			high_risk_countries = EXCLUDED.high_risk_countries,
+			sar_count = EXCLUDED.sar_count,
+			investigation_count = EXCLUDED.investigation_count,
+			blocked_tx_count = EXCLUDED.blocked_tx_count,
+			on_watchlist = EXCLUDED.on_watchlist,
+			watchlist_reason = EXCLUDED.watchlist_reason,
+			watchlist_added_at = EXCLUDED.watchlist_added_at,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		p.ID, p.TenantID, p.UserID, p.RiskScore, p.RiskLevel, p.LastAssessment, p.NextReviewDate,
+		p.CountryRisk, p.OccupationRisk, p.TransactionRisk, p.BehavioralRisk, p.RelationshipRisk,
+		p.IsPEP, pepDetails, p.IsHighNetWorth, p.HasOFACMatch, p.OFACMatchDetails,
+		p.AvgMonthlyVolume, p.AvgTransactionAmt, p.TxCountLast30Days,
+		pq.Array(nonNilSlice(p.PrimaryCountries)), pq.Array(nonNilSlice(p.HighRiskCountries)),
+		p.SARCount, p.InvestigationCount, p.BlockedTxCount,
+		p.OnWatchlist, p.WatchlistReason, p.WatchlistAddedAt,
+		p.CreatedAt, p.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save risk profile: %w", err)
+	}
+
+	return nil
+}
+
+// ListWatchlisted returns the profiles of users the tenant ctx acts for has
+// watchlisted, most recently added first, and the total number watchlisted
+func (r *RiskProfileRepository) ListWatchlisted(ctx context.Context, limit, offset int) ([]*domain.UserRiskProfile, int, error) {
+	scope := tenantScope(ctx)
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_risk_profiles
+		WHERE on_watchlist AND ($1 = '' OR tenant_id = $1)`, scope).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count watchlisted profiles: %w", err)
+	}
+
+	query := `SELECT ` + riskProfileColumns + ` FROM user_risk_profiles
+		WHERE on_watchlist AND ($3 = '' OR tenant_id = $3)
+		ORDER BY watchlist_added_at DESC NULLS LAST, user_id
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset, scope)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list watchlisted profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := make([]*domain.UserRiskProfile, 0, limit)
+	for rows.Next() {
+		p, err := scanRiskProfile(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		profiles = append(profiles, p)
+	}
+
+	return profiles, total, rows.Err()
+}
+
+func scanRiskProfile(row rowScanner) (*domain.UserRiskProfile, error) {
+	var p domain.UserRiskProfile
+	var pepDetails []byte
+
+	err := row.Scan(
+		&p.ID, &p.TenantID, &p.UserID, &p.RiskScore, &p.RiskLevel, &p.LastAssessment, &p.NextReviewDate,
+		&p.CountryRisk, &p.OccupationRisk, &p.TransactionRisk, &p.BehavioralRisk, &p.RelationshipRisk,
+		&p.IsPEP, &pepDetails, &p.IsHighNetWorth, &p.HasOFACMatch, &p.OFACMatchDetails,
+		&p.AvgMonthlyVolume, &p.AvgTransactionAmt, &p.TxCountLast30Days,
+		pq.Array(&p.PrimaryCountries), pq.Array(&p.HighRiskCountries),
+		&p.SARCount, &p.InvestigationCount, &p.BlockedTxCount,
+		&p.OnWatchlist, &p.WatchlistReason, &p.WatchlistAddedAt,
+		&p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan risk profile: %w", err)
+	}
+
+	if len(pepDetails) > 0 {
+		if err := json.Unmarshal(pepDetails, &p.PEPDetails); err != nil {
+			return nil, fmt.Errorf("unmarshal pep details: %w", err)
+		}
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,634 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const screeningResultColumns = `id, transaction_id, user_id, tenant_id, risk_score, decision, risk_level,
+	ofac_match, pep_match, risk_factors, pattern_matches, applied_thresholds,
+	reason_codes, transaction, ofac_list_updated_at, pep_list_updated_at, ofac_list_version,
+	pep_list_version, ofac_list_hash, pep_list_hash, country_risk_version, rescreen_of_id, checks_failed, degraded_dependencies, skipped_checks, errors, shadow_score, shadow_decision,
+	screening_duration_ms, created_at, updated_at`
+
+// ScreeningResultRepository persists screening results in PostgreSQL
+type ScreeningResultRepository struct {
+	db *sql.DB
+}
+
+// NewScreeningResultRepository creates a new screening result repository
+func NewScreeningResultRepository(db *sql.DB) *ScreeningResultRepository {
+	return &ScreeningResultRepository{db: db}
+}
+
+// Save inserts a screening result
+func (r *ScreeningResultRepository) Save(ctx context.Context, result *domain.ScreeningResult) error {
+	var ofacMatch, pepMatch, appliedThresholds, transaction []byte
+	var err error
+
+	if result.OFACMatch != nil {
+		if ofacMatch, err = json.Marshal(result.OFACMatch); err != nil {
+			return fmt.Errorf("marshal ofac match: %w", err)
+		}
+	}
+	if result.PEPMatch != nil {
+		if pepMatch, err = json.Marshal(result.PEPMatch); err != nil {
+			return fmt.Errorf("marshal pep match: %w", err)
+		}
+	}
+
+	if result.AppliedThresholds != nil {
+		if appliedThresholds, err = json.Marshal(result.AppliedThresholds); err != nil {
+			return fmt.Errorf("marshal applied thresholds: %w", err)
+		}
+	}
+
+	if result.Transaction != nil {
+		if transaction, err = json.Marshal(result.Transaction); err != nil {
+			return fmt.Errorf("marshal transaction: %w", err)
+		}
+	}
+
+	riskFactors, err := json.Marshal(nonNilSlice(result.RiskFactors))
+	if err != nil {
+		return fmt.Errorf("marshal risk factors: %w", err)
+	}
+	patternMatches, err := json.Marshal(nonNilSlice(result.PatternMatches))
+	if err != nil {
+		return fmt.Errorf("marshal pattern matches: %w", err)
+	}
+
+	reasonCodes, err := json.Marshal(nonNilSlice(result.ReasonCodes))
+	if err != nil {
+		return fmt.Errorf("marshal reason codes: %w", err)
+	}
+	checksFailed, err := json.Marshal(nonNilSlice(result.ChecksFailed))
+	if err != nil {
+		return fmt.Errorf("marshal checks failed: %w", err)
+	}
+	degradedDependencies, err := json.Marshal(nonNilSlice(result.DegradedDependencies))
+	if err != nil {
+		return fmt.Errorf("marshal degraded dependencies: %w", err)
+	}
+	skippedChecks, err := json.Marshal(nonNilSlice(result.SkippedChecks))
+	if err != nil {
+		return fmt.Errorf("marshal skipped checks: %w", err)
+	}
+	screeningErrors, err := json.Marshal(nonNilSlice(result.Errors))
+	if err != nil {
+		return fmt.Errorf("marshal screening errors: %w", err)
+	}
+	if result.TenantID, err = tenantFor(ctx, result.TenantID); err != nil {
+		return err
+	}
+
+	// Transaction history is windowed on when the transaction was
+	// initiated, falling back to when it was screened
+	var initiatedAt *time.Time
+	if result.Transaction != nil {
+		at := result.Transaction.InitiatedAt
+		if at.IsZero() {
+			at = result.CreatedAt
+		}
+		initiatedAt = &at
+	}
+
+	query := `INSERT INTO screening_results (` + screeningResultColumns + `, initiated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30,
+			$31, $32)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		result.ID,
+		result.TransactionID,
+		result.UserID,
+		result.TenantID,
+		result.RiskScore,
+		result.Decision,
+		result.RiskLevel,
+		ofacMatch,
+		pepMatch,
+		riskFactors,
+		patternMatches,
+		appliedThresholds,
+		reasonCodes,
+		transaction,
+		result.OFACListUpdatedAt,
+		result.PEPListUpdatedAt,
+		sql.NullInt64{Int64: result.OFACListVersion, Valid: result.OFACListVersion != 0},
+		sql.NullInt64{Int64: result.PEPListVersion, Valid: result.PEPListVersion != 0},
+		sql.NullString{String: result.OFACListHash, Valid: result.OFACListHash != ""},
+		sql.NullString{String: result.PEPListHash, Valid: result.PEPListHash != ""},
+		result.CountryRiskVersion,
+		result.RescreenOfID,
+		checksFailed,
+		degradedDependencies,
+		skippedChecks,
+		screeningErrors,
+		result.ShadowScore,
+		sql.NullString{String: string(result.ShadowDecision), Valid: result.ShadowDecision != ""},
+		result.ScreeningDurationMs,
+		result.CreatedAt,
+		result.UpdatedAt,
+		initiatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert screening result: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a screening result by ID
+func (r *ScreeningResultRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScreeningResult, error) {
+	query := `SELECT ` + screeningResultColumns + ` FROM screening_results
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
+	return r.scanOne(r.db.QueryRowContext(ctx, query, id, tenantScope(ctx)))
+}
+
+// GetByTransactionID returns the most recent screening result for a transaction
+func (r *ScreeningResultRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.ScreeningResult, error) {
+	query := `SELECT ` + screeningResultColumns + ` FROM screening_results
+		WHERE transaction_id = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at DESC
+		LIMIT 1`
+	return r.scanOne(r.db.QueryRowContext(ctx, query, transactionID, tenantScope(ctx)))
+}
+
+// ListLatestByTransactionIDs returns the most recent screening result of
+// each of the given transactions. Transactions never screened are omitted.
+func (r *ScreeningResultRepository) ListLatestByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*domain.ScreeningResult, error) {
+	query := `SELECT DISTINCT ON (transaction_id) ` + screeningResultColumns + ` FROM screening_results
+		WHERE transaction_id = ANY($1) AND ($2 = '' OR tenant_id = $2)
+		ORDER BY transaction_id, created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(transactionIDs), tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list screening results by transaction: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*domain.ScreeningResult, 0, len(transactionIDs))
+	for rows.Next() {
+		result, err := scanScreeningResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// ListByUser returns a user's screening results, newest first
+func (r *ScreeningResultRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.ScreeningResult, error) {
+	query := `SELECT ` + screeningResultColumns + ` FROM screening_results
+		WHERE user_id = $1 AND ($4 = '' OR tenant_id = $4)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list screening results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*domain.ScreeningResult, 0, limit)
+	for rows.Next() {
+		result, err := scanScreeningResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// PageByUser returns a page of a user's screening results created at or
+// after since, newest first, along with the total number of them
+func (r *ScreeningResultRepository) PageByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit, offset int) ([]*domain.ScreeningResult, int, error) {
+	scope := tenantScope(ctx)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM screening_results
+		WHERE user_id = $1 AND created_at >= $2 AND ($3 = '' OR tenant_id = $3)`
+	if err := r.db.QueryRowContext(ctx, countQuery, userID, since, scope).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count screening results: %w", err)
+	}
+
+	query := `SELECT ` + screeningResultColumns + ` FROM screening_results
+		WHERE user_id = $1 AND created_at >= $2 AND ($5 = '' OR tenant_id = $5)
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since, limit, offset, scope)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list screening results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*domain.ScreeningResult, 0, limit)
+	for rows.Next() {
+		result, err := scanScreeningResult(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, result)
+	}
+
+	return results, total, rows.Err()
+}
+
+// CountPatternsByUser counts the patterns detected on a user's screenings
+// created at or after since by pattern type, most frequent first.
+// Re-screens are left out so a re-evaluated transaction counts once.
+func (r *ScreeningResultRepository) CountPatternsByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]domain.UserPatternSummary, error) {
+	query := `SELECT pm->>'pattern_type', COUNT(*),
+			COALESCE(MAX((pm->>'confidence')::float8), 0), MAX(sr.created_at)
+		FROM screening_results sr, jsonb_array_elements(sr.pattern_matches) pm
+		WHERE sr.user_id = $1 AND sr.created_at >= $2 AND sr.rescreen_of_id IS NULL
+			AND ($3 = '' OR sr.tenant_id = $3)
+		GROUP BY 1
+		ORDER BY 2 DESC, 1`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("count patterns: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.UserPatternSummary{}
+	for rows.Next() {
+		var c domain.UserPatternSummary
+		if err := rows.Scan(&c.PatternType, &c.Count, &c.MaxConfidence, &c.LastDetectedAt); err != nil {
+			return nil, fmt.Errorf("scan pattern count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// exportPageSize is how many results StreamForExport reads per query
+const exportPageSize = 500
+
+// CountForExport counts the screening results an export would include
+func (r *ScreeningResultRepository) CountForExport(ctx context.Context, req *domain.ScreeningExportRequest) (int, error) {
+	query := `SELECT COUNT(*) FROM screening_results
+		WHERE created_at >= $1 AND created_at < $2 AND ($3 = '' OR decision = $3)
+			AND ($4 = '' OR tenant_id = $4)`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, req.From, req.To, req.Decision, tenantScope(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count screening results for export: %w", err)
+	}
+	return count, nil
+}
+
+// StreamForExport calls fn for each screening result an export includes,
+// oldest first. Results are read in pages keyed on (created_at, id), so the
+// range is never held in memory and no connection is held between pages.
+// An error from fn stops the stream and is returned.
+func (r *ScreeningResultRepository) StreamForExport(ctx context.Context, req *domain.ScreeningExportRequest, fn func(*domain.ScreeningResult) error) error {
+	query := `SELECT ` + screeningResultColumns + ` FROM screening_results
+		WHERE created_at >= $1 AND created_at < $2 AND ($3 = '' OR decision = $3)
+			AND (created_at, id) > ($4, $5) AND ($7 = '' OR tenant_id = $7)
+		ORDER BY created_at, id
+		LIMIT $6`
+
+	afterTime, afterID := req.From, uuid.Nil
+	for {
+		page, err := r.exportPage(ctx, query, req, afterTime, afterID)
+		if err != nil {
+			return err
+		}
+		for _, result := range page {
+			if err := fn(result); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+		last := page[len(page)-1]
+		afterTime, afterID = last.CreatedAt, last.ID
+	}
+}
+
+func (r *ScreeningResultRepository) exportPage(ctx context.Context, query string, req *domain.ScreeningExportRequest, afterTime time.Time, afterID uuid.UUID) ([]*domain.ScreeningResult, error) {
+	rows, err := r.db.QueryContext(ctx, query, req.From, req.To, req.Decision, afterTime, afterID, exportPageSize, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list screening results for export: %w", err)
+	}
+	defer rows.Close()
+
+	page := make([]*domain.ScreeningResult, 0, exportPageSize)
+	for rows.Next() {
+		result, err := scanScreeningResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, result)
+	}
+
+	return page, rows.Err()
+}
+
+func (r *ScreeningResultRepository) scanOne(row *sql.Row) (*domain.ScreeningResult, error) {
+	result, err := scanScreeningResult(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return result, err
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanScreeningResult(row rowScanner) (*domain.ScreeningResult, error) {
+	var result domain.ScreeningResult
+	var ofacMatch, pepMatch, riskFactors, patternMatches, appliedThresholds, reasonCodes, transaction, checksFailed, degradedDependencies, skippedChecks, screeningErrors []byte
+	var ofacListVersion, pepListVersion sql.NullInt64
+	var ofacListHash, pepListHash, shadowDecision sql.NullString
+
+	err := row.Scan(
+		&result.ID,
+		&result.TransactionID,
+		&result.UserID,
+		&result.TenantID,
+		&result.RiskScore,
+		&result.Decision,
+		&result.RiskLevel,
+		&ofacMatch,
+		&pepMatch,
+		&riskFactors,
+		&patternMatches,
+		&appliedThresholds,
+		&reasonCodes,
+		&transaction,
+		&result.OFACListUpdatedAt,
+		&result.PEPListUpdatedAt,
+		&ofacListVersion,
+		&pepListVersion,
+		&ofacListHash,
+		&pepListHash,
+		&result.CountryRiskVersion,
+		&result.RescreenOfID,
+		&checksFailed,
+		&degradedDependencies,
+		&skippedChecks,
+		&screeningErrors,
+		&result.ShadowScore,
+		&shadowDecision,
+		&result.ScreeningDurationMs,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan screening result: %w", err)
+	}
+
+	result.OFACListVersion = ofacListVersion.Int64
+	result.PEPListVersion = pepListVersion.Int64
+	result.OFACListHash = ofacListHash.String
+	result.PEPListHash = pepListHash.String
+	result.ShadowDecision = domain.ScreeningDecision(shadowDecision.String)
+
+	if len(ofacMatch) > 0 {
+		if err := json.Unmarshal(ofacMatch, &result.OFACMatch); err != nil {
+			return nil, fmt.Errorf("unmarshal ofac match: %w", err)
+		}
+	}
+	if len(pepMatch) > 0 {
+		if err := json.Unmarshal(pepMatch, &result.PEPMatch); err != nil {
+			return nil, fmt.Errorf("unmarshal pep match: %w", err)
+		}
+	}
+	if len(appliedThresholds) > 0 {
+		if err := json.Unmarshal(appliedThresholds, &result.AppliedThresholds); err != nil {
+			return nil, fmt.Errorf("unmarshal applied thresholds: %w", err)
+		}
+	}
+	if len(transaction) > 0 {
+		if err := json.Unmarshal(transaction, &result.Transaction); err != nil {
+			return nil, fmt.Errorf("unmarshal transaction: %w", err)
+		}
+	}
+	if err := json.Unmarshal(riskFactors, &result.RiskFactors); err != nil {
+		return nil, fmt.Errorf("unmarshal risk factors: %w", err)
+	}
+	if err := json.Unmarshal(patternMatches, &result.PatternMatches); err != nil {
+		return nil, fmt.Errorf("unmarshal pattern matches: %w", err)
+	}
+	if err := json.Unmarshal(reasonCodes, &result.ReasonCodes); err != nil {
+		return nil, fmt.Errorf("unmarshal reason codes: %w", err)
+	}
+	if err := json.Unmarshal(checksFailed, &result.ChecksFailed); err != nil {
+		return nil, fmt.Errorf("unmarshal checks failed: %w", err)
+	}
+	if err := json.Unmarshal(degradedDependencies, &result.DegradedDependencies); err != nil {
+		return nil, fmt.Errorf("unmarshal degraded dependencies: %w", err)
+	}
+	if err := json.Unmarshal(skippedChecks, &result.SkippedChecks); err != nil {
+		return nil, fmt.Errorf("unmarshal skipped checks: %w", err)
+	}
+	if err := json.Unmarshal(screeningErrors, &result.Errors); err != nil {
+		return nil, fmt.Errorf("unmarshal screening errors: %w", err)
+	}
+
+	return &result, nil
+}
+
+// nonNilSlice ensures empty slices are stored as [] rather than null
+func nonNilSlice[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// nonNilMap ensures empty maps are stored as {} rather than null
+func nonNilMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return map[K]V{}
+	}
+	return m
+}
+
+// customerNameExpr and counterpartyNameExpr extract party names from the
+// stored transaction, mirroring Transaction.GetCounterpartyName
+const (
+	customerNameExpr = `CASE WHEN transaction->>'direction' = 'OUTBOUND'
+		THEN transaction->>'sender_name' ELSE transaction->>'receiver_name' END`
+	counterpartyNameExpr = `CASE WHEN transaction->>'direction' = 'OUTBOUND'
+		THEN transaction->>'receiver_name' ELSE transaction->>'sender_name' END`
+)
+
+// ResolveUserName returns the customer's own name as recorded on their most
+// recent screened transaction
+func (r *ScreeningResultRepository) ResolveUserName(ctx context.Context, userID uuid.UUID) (string, error) {
+	query := `SELECT ` + customerNameExpr + `
+		FROM screening_results
+		WHERE user_id = $1 AND transaction IS NOT NULL AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var name sql.NullString
+	if err := r.db.QueryRowContext(ctx, query, userID, tenantScope(ctx)).Scan(&name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("resolve user name: %w", err)
+	}
+	if !name.Valid || name.String == "" {
+		return "", domain.ErrNotFound
+	}
+
+	return name.String, nil
+}
+
+// ListPartyNames returns distinct customer and counterparty names from
+// screened transactions, ordered for keyset pagination after the given party
+func (r *ScreeningResultRepository) ListPartyNames(ctx context.Context, after domain.PartyName, limit int) ([]domain.PartyName, error) {
+	query := `SELECT name, user_id, role, tenant_id FROM (
+			SELECT DISTINCT ` + customerNameExpr + ` AS name, user_id, 'CUSTOMER' AS role, tenant_id
+			FROM screening_results WHERE transaction IS NOT NULL AND ($5 = '' OR tenant_id = $5)
+			UNION
+			SELECT DISTINCT ` + counterpartyNameExpr + ` AS name, user_id, 'COUNTERPARTY' AS role, tenant_id
+			FROM screening_results WHERE transaction IS NOT NULL AND ($5 = '' OR tenant_id = $5)
+		) parties
+		WHERE name <> '' AND (name, user_id, role, tenant_id) > ($1, $2, $3, $6)
+		ORDER BY name, user_id, role, tenant_id
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, after.Name, after.UserID, after.Role, limit, tenantScope(ctx), after.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list party names: %w", err)
+	}
+	defer rows.Close()
+
+	parties := make([]domain.PartyName, 0, limit)
+	for rows.Next() {
+		var p domain.PartyName
+		if err := rows.Scan(&p.Name, &p.UserID, &p.Role, &p.TenantID); err != nil {
+			return nil, fmt.Errorf("scan party name: %w", err)
+		}
+		parties = append(parties, p)
+	}
+
+	return parties, rows.Err()
+}
+
+// ListFlaggedScreenings returns up to limit transactions whose most recent
+// screening was blocked or suspicious, with that decision, for transaction
+// IDs after the given one in ID order
+func (r *ScreeningResultRepository) ListFlaggedScreenings(ctx context.Context, after uuid.UUID, limit int) ([]domain.FlaggedScreening, error) {
+	query := `SELECT tenant_id, transaction_id, transaction, decision, created_at FROM (
+			SELECT DISTINCT ON (transaction_id) tenant_id, transaction_id, transaction, decision, created_at
+			FROM screening_results
+			WHERE transaction IS NOT NULL AND transaction_id > $1
+				AND ($5 = '' OR tenant_id = $5)
+			ORDER BY transaction_id, created_at DESC
+		) latest
+		WHERE decision IN ($2, $3)
+		ORDER BY transaction_id
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, after, domain.DecisionBlocked, domain.DecisionSuspicious, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list flagged screenings: %w", err)
+	}
+	defer rows.Close()
+
+	var flagged []domain.FlaggedScreening
+	for rows.Next() {
+		var f domain.FlaggedScreening
+		var data []byte
+		if err := rows.Scan(&f.TenantID, &f.TransactionID, &data, &f.Decision, &f.ScreenedAt); err != nil {
+			return nil, fmt.Errorf("scan flagged screening: %w", err)
+		}
+		if err := json.Unmarshal(data, &f.Transaction); err != nil {
+			return nil, fmt.Errorf("unmarshal transaction: %w", err)
+		}
+		flagged = append(flagged, f)
+	}
+	return flagged, rows.Err()
+}
+
+// CountBlockedByDevice counts blocked screening decisions since the given
+// time for transactions made from a device
+func (r *ScreeningResultRepository) CountBlockedByDevice(ctx context.Context, deviceID string, since time.Time) (int, error) {
+	query := `SELECT COUNT(DISTINCT transaction_id)
+		FROM screening_results
+		WHERE transaction IS NOT NULL
+			AND transaction->>'device_id' = $1
+			AND decision = $2
+			AND created_at >= $3
+			AND ($4 = '' OR tenant_id = $4)`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, deviceID, domain.DecisionBlocked, since, tenantScope(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count blocked transactions by device: %w", err)
+	}
+	return count, nil
+}
+
+// ListActiveUsers returns up to limit users with screened transactions
+// initiated in [since, until) whose IDs sort after the given one, in ID
+// order
+func (r *ScreeningResultRepository) ListActiveUsers(ctx context.Context, since, until time.Time, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	query := `SELECT DISTINCT user_id FROM screening_results
+		WHERE initiated_at >= $1 AND initiated_at < $2 AND user_id > $3
+			AND ($5 = '' OR tenant_id = $5)
+		ORDER BY user_id
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, since, until, after, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list active users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan active user: %w", err)
+		}
+		users = append(users, id)
+	}
+	return users, rows.Err()
+}
+
+// scanTransactions decodes rows holding a single stored transaction column
+func scanTransactions(rows *sql.Rows) ([]*domain.Transaction, error) {
+	var txs []*domain.Transaction
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan transaction: %w", err)
+		}
+		var tx domain.Transaction
+		if err := json.Unmarshal(data, &tx); err != nil {
+			return nil, fmt.Errorf("unmarshal transaction: %w", err)
+		}
+		txs = append(txs, &tx)
+	}
+
+	return txs, rows.Err()
+}
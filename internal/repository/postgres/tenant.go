@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// Screening results, alerts, investigations, filings, batch jobs,
+// counterparty reputations, risk profiles and webhook endpoints belong to a
+// tenant. Their queries take the
+// tenant the context acts for as a parameter and match it with a condition
+// of the form
+//
+//	($n = '' OR tenant_id = $n)
+//
+// so a request only ever reads and writes its own tenant's rows, while a
+// context naming no tenant, such as a background job's, acts for all.
+
+// tenantScope returns the tenant ctx acts for, or "" if it acts for every
+// tenant
+func tenantScope(ctx context.Context) string {
+	id, _ := tenant.FromContext(ctx)
+	return id
+}
+
+// tenantFor returns the tenant a new row belongs to: the one the record
+// names, else the one ctx acts for, else tenant.Default. A record naming a
+// tenant other than the one ctx acts for is refused.
+func tenantFor(ctx context.Context, id string) (string, error) {
+	scope := tenantScope(ctx)
+	switch {
+	case id == "":
+		return tenant.OrDefault(ctx), nil
+	case scope != "" && id != scope:
+		return "", fmt.Errorf("%w: record belongs to tenant %s, not %s", domain.ErrForbidden, id, scope)
+	}
+	return id, nil
+}
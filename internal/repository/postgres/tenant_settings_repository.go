@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const tenantSettingsColumns = `tenant_id, decision_thresholds, country_risk_tiers, sar_threshold, ctr_threshold, updated_by, updated_at`
+
+// TenantSettingsRepository persists each tenant's overrides of the
+// configured screening and reporting rules
+type TenantSettingsRepository struct {
+	db *sql.DB
+}
+
+// NewTenantSettingsRepository creates a new tenant settings repository
+func NewTenantSettingsRepository(db *sql.DB) *TenantSettingsRepository {
+	return &TenantSettingsRepository{db: db}
+}
+
+// Upsert stores a tenant's settings, replacing any it had
+func (r *TenantSettingsRepository) Upsert(ctx context.Context, settings *domain.TenantSettings) error {
+	thresholds, err := json.Marshal(nonNilMap(settings.DecisionThresholds))
+	if err != nil {
+		return fmt.Errorf("marshal decision thresholds: %w", err)
+	}
+	tiers, err := json.Marshal(nonNilSlice(settings.CountryRiskTiers))
+	if err != nil {
+		return fmt.Errorf("marshal country risk tiers: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO tenant_settings (`+tenantSettingsColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			decision_thresholds = EXCLUDED.decision_thresholds,
+			country_risk_tiers = EXCLUDED.country_risk_tiers,
+			sar_threshold = EXCLUDED.sar_threshold,
+			ctr_threshold = EXCLUDED.ctr_threshold,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		settings.TenantID, thresholds, tiers, settings.SARThreshold, settings.CTRThreshold,
+		settings.UpdatedBy, settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert tenant settings: %w", err)
+	}
+	return nil
+}
+
+// Get returns a tenant's settings, or ErrNotFound if it has none
+func (r *TenantSettingsRepository) Get(ctx context.Context, tenantID string) (*domain.TenantSettings, error) {
+	settings, err := scanTenantSettings(r.db.QueryRowContext(ctx, `SELECT `+tenantSettingsColumns+` FROM tenant_settings
+		WHERE tenant_id = $1`, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return settings, err
+}
+
+// List returns every tenant's settings
+func (r *TenantSettingsRepository) List(ctx context.Context) ([]*domain.TenantSettings, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+tenantSettingsColumns+` FROM tenant_settings
+		ORDER BY tenant_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list tenant settings: %w", err)
+	}
+	defer rows.Close()
+
+	all := make([]*domain.TenantSettings, 0)
+	for rows.Next() {
+		settings, err := scanTenantSettings(rows)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, settings)
+	}
+	return all, rows.Err()
+}
+
+func scanTenantSettings(row rowScanner) (*domain.TenantSettings, error) {
+	var s domain.TenantSettings
+	var thresholds, tiers []byte
+	var sar, ctr sql.NullFloat64
+	if err := row.Scan(&s.TenantID, &thresholds, &tiers, &sar, &ctr, &s.UpdatedBy, &s.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan tenant settings: %w", err)
+	}
+	if err := json.Unmarshal(thresholds, &s.DecisionThresholds); err != nil {
+		return nil, fmt.Errorf("unmarshal decision thresholds: %w", err)
+	}
+	if err := json.Unmarshal(tiers, &s.CountryRiskTiers); err != nil {
+		return nil, fmt.Errorf("unmarshal country risk tiers: %w", err)
+	}
+	if len(s.DecisionThresholds) == 0 {
+		s.DecisionThresholds = nil
+	}
+	if len(s.CountryRiskTiers) == 0 {
+		s.CountryRiskTiers = nil
+	}
+	if sar.Valid {
+		s.SARThreshold = &sar.Float64
+	}
+	if ctr.Valid {
+		s.CTRThreshold = &ctr.Float64
+	}
+	return &s, nil
+}
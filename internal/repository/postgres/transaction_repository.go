@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// TransactionRepository reads the history of screened transactions from the
+// screening results that recorded them, windowed by initiated_at. The
+// windowed queries are served by idx_screening_results_user_initiated and
+// idx_screening_results_account_initiated.
+type TransactionRepository struct {
+	db *sql.DB
+}
+
+// NewTransactionRepository creates a new transaction repository
+func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+// GetByUser returns a user's transactions initiated since the given time,
+// oldest first. A transaction screened more than once is returned once.
+func (r *TransactionRepository) GetByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.Transaction, error) {
+	query := `SELECT transaction FROM (
+			SELECT DISTINCT ON (transaction_id) transaction, initiated_at
+			FROM screening_results
+			WHERE user_id = $1 AND transaction IS NOT NULL AND initiated_at >= $2
+				AND ($3 = '' OR tenant_id = $3)
+			ORDER BY transaction_id, created_at
+		) recent
+		ORDER BY initiated_at`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list user transactions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTransactions(rows)
+}
+
+// GetInWindow returns the transactions of the window's users initiated in
+// [From, To), oldest first. A transaction screened more than once is
+// returned once, and is left out if ExcludeBlocked is set and its first
+// screening blocked it.
+func (r *TransactionRepository) GetInWindow(ctx context.Context, window domain.TransactionWindow) ([]*domain.Transaction, error) {
+	query := `SELECT transaction FROM (
+			SELECT DISTINCT ON (transaction_id) transaction, decision, initiated_at
+			FROM screening_results
+			WHERE user_id = ANY($1) AND transaction IS NOT NULL AND initiated_at >= $2 AND initiated_at < $3
+				AND ($5 = '' OR tenant_id = $5)
+			ORDER BY transaction_id, created_at
+		) first_screenings
+		WHERE NOT $4 OR decision <> $6
+		ORDER BY initiated_at`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		pq.Array(window.UserIDs), window.From, window.To, window.ExcludeBlocked, tenantScope(ctx), domain.DecisionBlocked,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list transactions in window: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTransactions(rows)
+}
+
+// GetRelated returns the transactions in the given direction on an account
+// initiated since the given time, oldest first, whichever user made them. A
+// transaction screened more than once is returned once.
+func (r *TransactionRepository) GetRelated(ctx context.Context, accountID uuid.UUID, direction string, since time.Time) ([]*domain.Transaction, error) {
+	query := `SELECT transaction FROM (
+			SELECT DISTINCT ON (transaction_id) transaction, initiated_at
+			FROM screening_results
+			WHERE transaction IS NOT NULL
+				AND transaction->>'account_id' = $1
+				AND transaction->>'direction' = $2
+				AND initiated_at >= $3
+				AND ($4 = '' OR tenant_id = $4)
+			ORDER BY transaction_id, created_at
+		) related
+		ORDER BY initiated_at`
+
+	rows, err := r.db.QueryContext(ctx, query, accountID.String(), direction, since, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list related transactions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTransactions(rows)
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// WebhookDeadLetterRepository persists undeliverable webhook events
+type WebhookDeadLetterRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookDeadLetterRepository creates a new webhook dead-letter repository
+func NewWebhookDeadLetterRepository(db *sql.DB) *WebhookDeadLetterRepository {
+	return &WebhookDeadLetterRepository{db: db}
+}
+
+// Create stores a dead letter
+func (r *WebhookDeadLetterRepository) Create(ctx context.Context, d *domain.WebhookDeadLetter) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO webhook_dead_letters (
+			id, event_id, event_type, endpoint_id, subscriber, url, screening_id,
+			transaction_id, decision, payload, attempts, last_error, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		d.ID, d.EventID, d.EventType, d.EndpointID, d.Subscriber, d.URL, d.ScreeningID,
+		d.TransactionID, sql.NullString{String: string(d.Decision), Valid: d.Decision != ""},
+		[]byte(d.Payload), d.Attempts, d.LastError, d.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create webhook dead letter: %w", err)
+	}
+	return nil
+}
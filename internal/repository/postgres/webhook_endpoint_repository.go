@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/banking/aml-service/internal/crypto"
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const webhookEndpointColumns = `id, tenant_id, name, url, secret, event_types, enabled, managed_by_config,
+	consecutive_failures, disabled_at, disabled_reason, created_by, created_at, updated_at`
+
+// WebhookEndpointRepository persists webhook endpoints. Signing secrets are
+// encrypted with the keyring and decrypted on read.
+type WebhookEndpointRepository struct {
+	db   *sql.DB
+	keys *crypto.Keyring
+}
+
+// NewWebhookEndpointRepository creates a new webhook endpoint repository
+func NewWebhookEndpointRepository(db *sql.DB, keys *crypto.Keyring) *WebhookEndpointRepository {
+	return &WebhookEndpointRepository{db: db, keys: keys}
+}
+
+// Create stores an endpoint for the tenant ctx acts for, returning
+// ErrConflict if the name is taken
+func (r *WebhookEndpointRepository) Create(ctx context.Context, e *domain.WebhookEndpoint) error {
+	tenantID, err := tenantFor(ctx, e.TenantID)
+	if err != nil {
+		return err
+	}
+	e.TenantID = tenantID
+
+	secret, err := r.keys.Encrypt(e.Secret)
+	if err != nil {
+		return fmt.Errorf("encrypt webhook secret: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, `INSERT INTO webhook_endpoints (
+			id, tenant_id, name, url, secret, encryption_key_version, event_types, enabled,
+			managed_by_config, consecutive_failures, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (name) DO NOTHING`,
+		e.ID, e.TenantID, e.Name, e.URL, secret, r.keys.CurrentVersion(), pq.Array(e.EventTypes), e.Enabled,
+		e.ManagedByConfig, e.ConsecutiveFailures, e.CreatedBy, e.CreatedAt, e.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create webhook endpoint: %w", err)
+	}
+	if err := requireAffected(res); errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: a webhook endpoint named %q already exists", domain.ErrConflict, e.Name)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SyncConfigured makes the config-managed endpoints match endpoints: each
+// is created, or updated by name keeping its enabled state and failure
+// count, and config-managed endpoints no longer configured are deleted. An
+// endpoint registered through the API under a configured name is left alone
+// and reported as a conflict.
+func (r *WebhookEndpointRepository) SyncConfigured(ctx context.Context, endpoints []*domain.WebhookEndpoint) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	names := make([]string, len(endpoints))
+	var conflicts []string
+	for i, e := range endpoints {
+		names[i] = e.Name
+		secret, err := r.keys.Encrypt(e.Secret)
+		if err != nil {
+			return fmt.Errorf("encrypt webhook secret: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx, `INSERT INTO webhook_endpoints (
+				id, tenant_id, name, url, secret, encryption_key_version, event_types, enabled,
+				managed_by_config, consecutive_failures, created_by, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, TRUE, TRUE, 0, $8, $9, $9)
+			ON CONFLICT (name) DO UPDATE SET
+				tenant_id = EXCLUDED.tenant_id, url = EXCLUDED.url, secret = EXCLUDED.secret,
+				encryption_key_version = EXCLUDED.encryption_key_version,
+				event_types = EXCLUDED.event_types, updated_at = EXCLUDED.updated_at
+			WHERE webhook_endpoints.managed_by_config`,
+			e.ID, e.TenantID, e.Name, e.URL, secret, r.keys.CurrentVersion(), pq.Array(e.EventTypes), e.CreatedBy, e.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("sync webhook endpoint %s: %w", e.Name, err)
+		}
+		if requireAffected(res) != nil {
+			conflicts = append(conflicts, e.Name)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM webhook_endpoints
+		WHERE managed_by_config AND NOT (name = ANY($1))`, pq.Array(names))
+	if err != nil {
+		return fmt.Errorf("delete unconfigured webhook endpoints: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit webhook endpoint sync: %w", err)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: webhook endpoints %q are registered through the API", domain.ErrConflict, conflicts)
+	}
+	return nil
+}
+
+// GetByID returns an endpoint by ID, if it belongs to the tenant ctx acts
+// for
+func (r *WebhookEndpointRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`, id, tenantScope(ctx))
+	e, err := r.scanEndpoint(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return e, err
+}
+
+// List returns the endpoints of the tenant ctx acts for by name
+func (r *WebhookEndpointRepository) List(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints
+		WHERE ($1 = '' OR tenant_id = $1)
+		ORDER BY name`, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list webhook endpoints: %w", err)
+	}
+	return r.scanEndpoints(rows)
+}
+
+// ListEnabled returns the enabled endpoints of every tenant, whichever one
+// ctx acts for, for the dispatcher to route each event to its own tenant's
+func (r *WebhookEndpointRepository) ListEnabled(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints
+		WHERE enabled
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list enabled webhook endpoints: %w", err)
+	}
+	return r.scanEndpoints(rows)
+}
+
+func (r *WebhookEndpointRepository) scanEndpoints(rows *sql.Rows) ([]*domain.WebhookEndpoint, error) {
+	defer rows.Close()
+
+	var endpoints []*domain.WebhookEndpoint
+	for rows.Next() {
+		e, err := r.scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, rows.Err()
+}
+
+// Delete removes an endpoint registered through the API. Config-managed
+// endpoints are refused with ErrConflict.
+func (r *WebhookEndpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhook_endpoints
+		WHERE id = $1 AND NOT managed_by_config AND ($2 = '' OR tenant_id = $2)`, id, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("delete webhook endpoint: %w", err)
+	}
+	if err := requireAffected(res); err != nil {
+		if _, getErr := r.GetByID(ctx, id); getErr == nil {
+			return fmt.Errorf("%w: webhook endpoint is managed by config", domain.ErrConflict)
+		}
+		return err
+	}
+	return nil
+}
+
+// Enable re-enables an endpoint and clears its failure count
+func (r *WebhookEndpointRepository) Enable(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE webhook_endpoints SET
+		enabled = TRUE, consecutive_failures = 0, disabled_at = NULL, disabled_reason = NULL,
+		updated_at = NOW()
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`, id, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("enable webhook endpoint: %w", err)
+	}
+	return requireAffected(res)
+}
+
+// RecordSuccess clears an endpoint's consecutive failure count
+func (r *WebhookEndpointRepository) RecordSuccess(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE webhook_endpoints SET consecutive_failures = 0
+		WHERE id = $1 AND consecutive_failures > 0`, id)
+	if err != nil {
+		return fmt.Errorf("reset webhook endpoint failures: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure counts a failed delivery against an endpoint and disables
+// it once disableAfter deliveries in a row have failed. disabled is true
+// only for the call that disabled it, so one alert is raised however many
+// instances see the failures.
+func (r *WebhookEndpointRepository) RecordFailure(ctx context.Context, id uuid.UUID, disableAfter int, reason string, now time.Time) (disabled bool, err error) {
+	err = r.db.QueryRowContext(ctx, `WITH prev AS (
+			SELECT enabled FROM webhook_endpoints WHERE id = $1 FOR UPDATE
+		)
+		UPDATE webhook_endpoints e SET
+			consecutive_failures = e.consecutive_failures + 1,
+			enabled = e.enabled AND e.consecutive_failures + 1 < $2,
+			disabled_at = CASE WHEN e.enabled AND e.consecutive_failures + 1 >= $2 THEN $3 ELSE e.disabled_at END,
+			disabled_reason = CASE WHEN e.enabled AND e.consecutive_failures + 1 >= $2 THEN $4 ELSE e.disabled_reason END,
+			updated_at = $3
+		FROM prev
+		WHERE e.id = $1
+		RETURNING prev.enabled AND NOT e.enabled`,
+		id, disableAfter, now, reason,
+	).Scan(&disabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted while the delivery was in flight
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("record webhook endpoint failure: %w", err)
+	}
+	return disabled, nil
+}
+
+// ReencryptBatch moves up to limit endpoint secrets sealed under an older
+// key onto the current key and returns how many were rewritten
+func (r *WebhookEndpointRepository) ReencryptBatch(ctx context.Context, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, secret FROM webhook_endpoints
+		WHERE encryption_key_version <> $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, r.keys.CurrentVersion(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("list webhook endpoints for key rotation: %w", err)
+	}
+
+	type sealedRow struct {
+		id     uuid.UUID
+		secret string
+	}
+	var batch []sealedRow
+	for rows.Next() {
+		var s sealedRow
+		if err := rows.Scan(&s.id, &s.secret); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan webhook endpoint for key rotation: %w", err)
+		}
+		batch = append(batch, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list webhook endpoints for key rotation: %w", err)
+	}
+
+	for _, s := range batch {
+		secret, err := r.keys.Reencrypt(s.secret)
+		if err != nil {
+			return 0, fmt.Errorf("reencrypt webhook endpoint %s: %w", s.id, err)
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE webhook_endpoints SET secret = $2, encryption_key_version = $3
+			WHERE id = $1`, s.id, secret, r.keys.CurrentVersion())
+		if err != nil {
+			return 0, fmt.Errorf("update webhook endpoint %s: %w", s.id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit key rotation: %w", err)
+	}
+	return len(batch), nil
+}
+
+func (r *WebhookEndpointRepository) scanEndpoint(row rowScanner) (*domain.WebhookEndpoint, error) {
+	var e domain.WebhookEndpoint
+	var eventTypes []string
+	var disabledReason sql.NullString
+
+	err := row.Scan(
+		&e.ID, &e.TenantID, &e.Name, &e.URL, &e.Secret, pq.Array(&eventTypes), &e.Enabled, &e.ManagedByConfig,
+		&e.ConsecutiveFailures, &e.DisabledAt, &disabledReason, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan webhook endpoint: %w", err)
+	}
+
+	if e.Secret, err = r.keys.Decrypt(e.Secret); err != nil {
+		return nil, fmt.Errorf("decrypt webhook secret: %w", err)
+	}
+	e.DisabledReason = disabledReason.String
+	e.EventTypes = make([]domain.WebhookEventType, len(eventTypes))
+	for i, t := range eventTypes {
+		e.EventTypes[i] = domain.WebhookEventType(t)
+	}
+	return &e, nil
+}
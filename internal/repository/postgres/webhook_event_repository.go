@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// WebhookEventRepository persists webhook events and their delivery attempts
+type WebhookEventRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookEventRepository creates a new webhook event repository
+func NewWebhookEventRepository(db *sql.DB) *WebhookEventRepository {
+	return &WebhookEventRepository{db: db}
+}
+
+// CreateEvent stores an event
+func (r *WebhookEventRepository) CreateEvent(ctx context.Context, e *domain.WebhookEvent) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO webhook_events (id, tenant_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		e.ID, e.TenantID, e.Type, []byte(e.Payload), e.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create webhook event: %w", err)
+	}
+	return nil
+}
+
+// GetEvent returns an event by ID, if it belongs to the tenant ctx acts for
+func (r *WebhookEventRepository) GetEvent(ctx context.Context, id uuid.UUID) (*domain.WebhookEvent, error) {
+	var e domain.WebhookEvent
+	var payload []byte
+	err := r.db.QueryRowContext(ctx, `SELECT id, tenant_id, event_type, payload, created_at
+		FROM webhook_events WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`, id, tenantScope(ctx),
+	).Scan(&e.ID, &e.TenantID, &e.Type, &payload, &e.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webhook event: %w", err)
+	}
+	e.Payload = payload
+	return &e, nil
+}
+
+// CreateDelivery records a delivery attempt
+func (r *WebhookEventRepository) CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO webhook_deliveries (
+			id, event_id, event_type, endpoint_id, attempt, replay, status,
+			status_code, latency_ms, error, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		d.ID, d.EventID, d.EventType, d.EndpointID, d.Attempt, d.Replay, d.Status,
+		sql.NullInt64{Int64: int64(d.StatusCode), Valid: d.StatusCode != 0},
+		d.LatencyMs,
+		sql.NullString{String: d.Error, Valid: d.Error != ""},
+		d.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns an endpoint's delivery attempts, newest first,
+// optionally only those of one event
+func (r *WebhookEventRepository) ListDeliveries(ctx context.Context, endpointID uuid.UUID, eventID *uuid.UUID, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, event_id, event_type, endpoint_id, attempt, replay,
+			status, status_code, latency_ms, error, created_at
+		FROM webhook_deliveries
+		WHERE endpoint_id = $1 AND ($2::uuid IS NULL OR event_id = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`,
+		endpointID, eventID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var statusCode sql.NullInt64
+		var deliveryErr sql.NullString
+		err := rows.Scan(&d.ID, &d.EventID, &d.EventType, &d.EndpointID, &d.Attempt, &d.Replay,
+			&d.Status, &statusCode, &d.LatencyMs, &deliveryErr, &d.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		d.StatusCode = int(statusCode.Int64)
+		d.Error = deliveryErr.String
+		deliveries = append(deliveries, &d)
+	}
+	return deliveries, rows.Err()
+}
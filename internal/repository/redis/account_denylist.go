@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const accountDenylistKey = keyPrefix + "account_denylist" // hash: normalized account -> entry JSON
+
+// AccountDenylist stores the internal denylist of account numbers and IBANs
+// in Redis. Entries do not expire; they are removed through the admin API.
+type AccountDenylist struct {
+	client *goredis.Client
+}
+
+// NewAccountDenylist creates a new account denylist store
+func NewAccountDenylist(client *goredis.Client) *AccountDenylist {
+	return &AccountDenylist{client: client}
+}
+
+// Lookup returns the entries for whichever of the normalized accounts are
+// denylisted
+func (d *AccountDenylist) Lookup(ctx context.Context, accounts ...string) ([]*domain.AccountDenylistEntry, error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+
+	values, err := d.client.HMGet(ctx, accountDenylistKey, accounts...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get account denylist entries: %w", err)
+	}
+
+	var entries []*domain.AccountDenylistEntry
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue // not denylisted
+		}
+		var entry domain.AccountDenylistEntry
+		if err := json.Unmarshal([]byte(s), &entry); err != nil {
+			return nil, fmt.Errorf("unmarshal account denylist entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// List returns every entry, most recently added first
+func (d *AccountDenylist) List(ctx context.Context) ([]*domain.AccountDenylistEntry, error) {
+	values, err := d.client.HVals(ctx, accountDenylistKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get account denylist entries: %w", err)
+	}
+
+	entries := make([]*domain.AccountDenylistEntry, 0, len(values))
+	for _, v := range values {
+		var entry domain.AccountDenylistEntry
+		if err := json.Unmarshal([]byte(v), &entry); err != nil {
+			return nil, fmt.Errorf("unmarshal account denylist entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].AddedAt.After(entries[j].AddedAt) })
+	return entries, nil
+}
+
+// Add stores an entry, returning ErrConflict if the account is already
+// denylisted
+func (d *AccountDenylist) Add(ctx context.Context, entry *domain.AccountDenylistEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal account denylist entry: %w", err)
+	}
+
+	added, err := d.client.HSetNX(ctx, accountDenylistKey, entry.Account, data).Result()
+	if err != nil {
+		return fmt.Errorf("add account denylist entry: %w", err)
+	}
+	if !added {
+		return fmt.Errorf("%w: account is already denylisted", domain.ErrConflict)
+	}
+	return nil
+}
+
+// Remove deletes and returns an account's entry, returning ErrNotFound if
+// the account is not denylisted
+func (d *AccountDenylist) Remove(ctx context.Context, account string) (*domain.AccountDenylistEntry, error) {
+	entries, err := d.Lookup(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	removed, err := d.client.HDel(ctx, accountDenylistKey, account).Result()
+	if err != nil {
+		return nil, fmt.Errorf("remove account denylist entry: %w", err)
+	}
+	if removed == 0 {
+		return nil, domain.ErrNotFound // removed concurrently
+	}
+	return entries[0], nil
+}
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/config"
+)
+
+// DeviceUsers tracks which users transact from each device and IP address,
+// to spot devices shared across accounts. Each tenant's users are tracked
+// in their own sets.
+//
+// Keys:
+//
+//	aml:device_users:{tenant}:{device|ip}:{value}  sorted set {user -> last seen, Unix seconds}
+//
+// Users not seen within the window are dropped, and each set keeps only
+// the most recently seen max_tracked users, so a key's size is bounded
+// however many accounts use the device.
+type DeviceUsers struct {
+	client *goredis.Client
+	cfg    *config.SharedDeviceConfig
+}
+
+// NewDeviceUsers creates a new device and IP user index
+func NewDeviceUsers(client *goredis.Client, cfg *config.SharedDeviceConfig) *DeviceUsers {
+	return &DeviceUsers{client: client, cfg: cfg}
+}
+
+// Record links userID to key as seen at, then returns the number of users
+// seen on key within the window and the most recently seen of them, up to
+// limit
+func (d *DeviceUsers) Record(ctx context.Context, key string, userID uuid.UUID, at time.Time, limit int) (int, []uuid.UUID, error) {
+	k := deviceUsersKey(key)
+
+	pipe := d.client.TxPipeline()
+	pipe.ZAdd(ctx, k, goredis.Z{Score: float64(at.Unix()), Member: userID.String()})
+	pipe.ZRemRangeByScore(ctx, k, "-inf", strconv.FormatInt(at.Add(-d.cfg.Window).Unix(), 10))
+	pipe.ZRemRangeByRank(ctx, k, 0, int64(-d.cfg.MaxTracked-1))
+	pipe.Expire(ctx, k, d.cfg.Window)
+	countCmd := pipe.ZCard(ctx, k)
+	usersCmd := pipe.ZRevRange(ctx, k, 0, int64(limit-1))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, nil, fmt.Errorf("record device user: %w", err)
+	}
+	return parseDeviceUsers(countCmd.Val(), usersCmd.Val())
+}
+
+// Users returns the number of users seen on key within the window before
+// at and the most recently seen of them, up to limit, without recording
+// anyone
+func (d *DeviceUsers) Users(ctx context.Context, key string, at time.Time, limit int) (int, []uuid.UUID, error) {
+	k := deviceUsersKey(key)
+	since := strconv.FormatInt(at.Add(-d.cfg.Window).Unix(), 10)
+
+	pipe := d.client.Pipeline()
+	countCmd := pipe.ZCount(ctx, k, "("+since, "+inf")
+	usersCmd := pipe.ZRevRangeByScore(ctx, k, &goredis.ZRangeBy{Min: "(" + since, Max: "+inf", Count: int64(limit)})
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, nil, fmt.Errorf("get device users: %w", err)
+	}
+	return parseDeviceUsers(countCmd.Val(), usersCmd.Val())
+}
+
+func deviceUsersKey(key string) string {
+	return keyPrefix + "device_users:" + key
+}
+
+func parseDeviceUsers(count int64, members []string) (int, []uuid.UUID, error) {
+	users := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		id, err := uuid.Parse(m)
+		if err != nil {
+			return 0, nil, fmt.Errorf("parse device user %q: %w", m, err)
+		}
+		users = append(users, id)
+	}
+	return int(count), users, nil
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+const idempotencyKeyPrefix = keyPrefix + "idempotency:" // + scope:key -> record JSON
+
+// IdempotencyStore records which idempotency keys have been claimed and the
+// responses they produced
+type IdempotencyStore struct {
+	client *goredis.Client
+}
+
+// NewIdempotencyStore creates a new idempotency store
+func NewIdempotencyStore(client *goredis.Client) *IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+// Reserve claims key for a request with the given fingerprint. It returns
+// nil when the key was free and is now held for ttl, or the existing record
+// when another request already claimed it.
+func (s *IdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	data, err := json.Marshal(&domain.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal idempotency record: %w", err)
+	}
+
+	claimed, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	existing, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			// Expired between the two calls; claim it again
+			return s.Reserve(ctx, key, fingerprint, ttl)
+		}
+		return nil, fmt.Errorf("get idempotency record: %w", err)
+	}
+
+	var record domain.IdempotencyRecord
+	if err := json.Unmarshal(existing, &record); err != nil {
+		return nil, fmt.Errorf("unmarshal idempotency record: %w", err)
+	}
+	return &record, nil
+}
+
+// Complete stores the finished response under key for ttl
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error {
+	record.Completed = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal idempotency record: %w", err)
+	}
+	if err := s.client.Set(ctx, idempotencyKeyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release frees a key whose request failed so a retry can run
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, idempotencyKeyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
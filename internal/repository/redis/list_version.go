@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// getListVersion reads the list version hash at key, returning the zero
+// version if none was recorded
+func getListVersion(ctx context.Context, client *goredis.Client, key string) (screening.ListVersion, error) {
+	fields, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return screening.ListVersion{}, fmt.Errorf("get %s: %w", key, err)
+	}
+	return parseListVersion(key, fields)
+}
+
+// recordListVersion makes hash the current version of the list at key. A
+// hash equal to the current version's keeps that version; any other is
+// recorded as the next version number. It runs under WATCH and is retried
+// if another instance records a version in between, so every instance
+// loading the same entries gets the same version.
+func recordListVersion(ctx context.Context, client *goredis.Client, key, hash string, entries int, at time.Time) (screening.ListVersion, bool, error) {
+	var version screening.ListVersion
+	var created bool
+
+	record := func(tx *goredis.Tx) error {
+		fields, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		current, err := parseListVersion(key, fields)
+		if err != nil {
+			return err
+		}
+		if current.Version > 0 && current.Hash == hash {
+			version, created = current, false
+			return nil
+		}
+
+		next := screening.ListVersion{
+			Version:  current.Version + 1,
+			Hash:     hash,
+			Entries:  entries,
+			LoadedAt: at.UTC(),
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.HSet(ctx, key, map[string]interface{}{
+				"version":   next.Version,
+				"hash":      next.Hash,
+				"entries":   next.Entries,
+				"loaded_at": next.LoadedAt.Format(time.RFC3339Nano),
+			})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		version, created = next, true
+		return nil
+	}
+
+	for range maxWatchRetries {
+		err := client.Watch(ctx, record, key)
+		if errors.Is(err, goredis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return screening.ListVersion{}, false, fmt.Errorf("record %s: %w", key, err)
+		}
+		return version, created, nil
+	}
+	return screening.ListVersion{}, false, fmt.Errorf("record %s: version changed concurrently %d times", key, maxWatchRetries)
+}
+
+func parseListVersion(key string, fields map[string]string) (screening.ListVersion, error) {
+	var v screening.ListVersion
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	var err error
+	if v.Version, err = strconv.ParseInt(fields["version"], 10, 64); err != nil {
+		return v, fmt.Errorf("parse %s version: %w", key, err)
+	}
+	if v.Entries, err = strconv.Atoi(fields["entries"]); err != nil {
+		return v, fmt.Errorf("parse %s entries: %w", key, err)
+	}
+	if v.LoadedAt, err = time.Parse(time.RFC3339Nano, fields["loaded_at"]); err != nil {
+		return v, fmt.Errorf("parse %s loaded_at: %w", key, err)
+	}
+	v.Hash = fields["hash"]
+	return v, nil
+}
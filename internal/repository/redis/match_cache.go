@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+)
+
+const (
+	ofacMatchKeyPrefix = keyPrefix + "match:ofac:" // + list version:name hash -> OFACMatch JSON
+	pepMatchKeyPrefix  = keyPrefix + "match:pep:"  // + list version:name hash -> PEPMatch JSON
+)
+
+// MatchCache stores OFAC and PEP check outcomes by counterparty so
+// repeated payments to the same counterparty skip fuzzy matching. Keys
+// carry the list version, so outcomes for a replaced list simply expire.
+type MatchCache struct {
+	client *goredis.Client
+	ttl    time.Duration
+}
+
+// NewMatchCache creates a new match cache whose entries live for ttl
+func NewMatchCache(client *goredis.Client, ttl time.Duration) *MatchCache {
+	return &MatchCache{client: client, ttl: ttl}
+}
+
+// GetOFAC returns the OFAC outcome cached under key, or nil on a miss
+func (c *MatchCache) GetOFAC(ctx context.Context, key string) (*domain.OFACMatch, error) {
+	var match domain.OFACMatch
+	found, err := c.get(ctx, "ofac_match", ofacMatchKeyPrefix+key, &match)
+	if !found {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// SetOFAC caches an OFAC outcome under key
+func (c *MatchCache) SetOFAC(ctx context.Context, key string, match *domain.OFACMatch) error {
+	return c.set(ctx, ofacMatchKeyPrefix+key, match)
+}
+
+// GetPEP returns the PEP outcome cached under key, or nil on a miss
+func (c *MatchCache) GetPEP(ctx context.Context, key string) (*domain.PEPMatch, error) {
+	var match domain.PEPMatch
+	found, err := c.get(ctx, "pep_match", pepMatchKeyPrefix+key, &match)
+	if !found {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// SetPEP caches a PEP outcome under key
+func (c *MatchCache) SetPEP(ctx context.Context, key string, match *domain.PEPMatch) error {
+	return c.set(ctx, pepMatchKeyPrefix+key, match)
+}
+
+// get decodes the value at key into dst and records the lookup under the
+// given cache name
+func (c *MatchCache) get(ctx context.Context, cache, key string, dst interface{}) (bool, error) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			metrics.RecordCacheLookup(cache, false)
+			return false, nil
+		}
+		return false, fmt.Errorf("get %s: %w", cache, err)
+	}
+
+	metrics.RecordCacheLookup(cache, true)
+
+	if err := json.Unmarshal(data, dst); err != nil {
+		return false, fmt.Errorf("unmarshal %s: %w", cache, err)
+	}
+	return true, nil
+}
+
+func (c *MatchCache) set(ctx context.Context, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal match: %w", err)
+	}
+	if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
+		return fmt.Errorf("set match: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const notificationDedupKeyPrefix = keyPrefix + "notify:" // + dedup key -> 1
+
+// NotificationDedup records which chat notifications have been sent so
+// instances don't page twice for the same event
+type NotificationDedup struct {
+	client *goredis.Client
+}
+
+// NewNotificationDedup creates a new notification dedup store
+func NewNotificationDedup(client *goredis.Client) *NotificationDedup {
+	return &NotificationDedup{client: client}
+}
+
+// Claim reports whether key was free and is now held for ttl
+func (d *NotificationDedup) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	claimed, err := d.client.SetNX(ctx, notificationDedupKeyPrefix+key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("claim notification key: %w", err)
+	}
+	return claimed, nil
+}
@@ -0,0 +1,259 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+const (
+	ofacEntriesKey    = keyPrefix + "ofac:entries"     // hash: normalized name -> entry JSON
+	ofacTombstonesKey = keyPrefix + "ofac:tombstones"  // hash: normalized name -> removal time, RFC 3339
+	ofacLastUpdateKey = keyPrefix + "ofac:last_update" // RFC 3339 timestamp
+	ofacVersionKey    = keyPrefix + "ofac:version"     // hash: version, hash, entries, loaded_at
+
+	// Latest list version whose delta stored names were re-screened against
+	ofacRescreenedKey = keyPrefix + "ofac:rescreened_version"
+)
+
+// maxWatchRetries bounds how often a list write is retried when another
+// writer changes the list under it
+const maxWatchRetries = 5
+
+// OFACCache stores the OFAC SDN list in Redis
+type OFACCache struct {
+	client *goredis.Client
+}
+
+// NewOFACCache creates a new OFAC cache
+func NewOFACCache(client *goredis.Client) *OFACCache {
+	return &OFACCache{client: client}
+}
+
+// GetByExactName returns the entry indexed under a normalized name
+func (c *OFACCache) GetByExactName(ctx context.Context, name string) (*screening.OFACEntry, error) {
+	data, err := c.client.HGet(ctx, ofacEntriesKey, name).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			metrics.RecordCacheLookup("ofac", false)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get ofac entry: %w", err)
+	}
+
+	metrics.RecordCacheLookup("ofac", true)
+
+	var entry screening.OFACEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshal ofac entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// GetByFuzzyName returns entries whose normalized name is at least threshold
+// similar to name by matcher, best match first
+func (c *OFACCache) GetByFuzzyName(ctx context.Context, name string, matcher screening.NameMatcher, threshold float64) ([]screening.OFACEntry, error) {
+	entries, err := c.GetAllEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		entry screening.OFACEntry
+		score float64
+	}
+	var matches []scored
+	for _, entry := range entries {
+		if score := matcher.Similarity(name, entry.NormalizedName); score >= threshold {
+			matches = append(matches, scored{entry: entry, score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	result := make([]screening.OFACEntry, len(matches))
+	for i, m := range matches {
+		result[i] = m.entry
+	}
+	return result, nil
+}
+
+// GetAllEntries returns every cached entry
+func (c *OFACCache) GetAllEntries(ctx context.Context) ([]screening.OFACEntry, error) {
+	values, err := c.client.HVals(ctx, ofacEntriesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get ofac entries: %w", err)
+	}
+
+	entries := make([]screening.OFACEntry, 0, len(values))
+	for _, v := range values {
+		var entry screening.OFACEntry
+		if err := json.Unmarshal([]byte(v), &entry); err != nil {
+			return nil, fmt.Errorf("unmarshal ofac entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// SetEntries writes entries to the cached list. In ListFullReplace mode
+// every cached entry absent from entries is deleted and recorded in the
+// tombstone hash with the time it was removed; writing an entry again
+// clears its tombstone.
+func (c *OFACCache) SetEntries(ctx context.Context, entries []screening.OFACEntry, ttl time.Duration, mode screening.ListWriteMode) error {
+	fields := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("marshal ofac entry: %w", err)
+		}
+		fields[entry.NormalizedName] = data
+	}
+
+	return writeHash(ctx, c.client, ofacEntriesKey, ofacTombstonesKey, fields, ttl, mode == screening.ListFullReplace, time.Now())
+}
+
+// GetLastUpdate returns when the list was last refreshed
+func (c *OFACCache) GetLastUpdate(ctx context.Context) (time.Time, error) {
+	return getTime(ctx, c.client, ofacLastUpdateKey)
+}
+
+// SetLastUpdate records when the list was last refreshed
+func (c *OFACCache) SetLastUpdate(ctx context.Context, t time.Time) error {
+	return c.client.Set(ctx, ofacLastUpdateKey, t.UTC().Format(time.RFC3339Nano), 0).Err()
+}
+
+// GetVersion returns the list's current version, or the zero version if
+// none was recorded
+func (c *OFACCache) GetVersion(ctx context.Context) (screening.ListVersion, error) {
+	return getListVersion(ctx, c.client, ofacVersionKey)
+}
+
+// RecordVersion makes the entries with the given hash the list's current
+// version and reports whether it is a new one
+func (c *OFACCache) RecordVersion(ctx context.Context, hash string, entries int, at time.Time) (screening.ListVersion, bool, error) {
+	return recordListVersion(ctx, c.client, ofacVersionKey, hash, entries, at)
+}
+
+// RescreenedVersion returns the latest list version whose delta was
+// re-screened, or 0 if none was recorded
+func (c *OFACCache) RescreenedVersion(ctx context.Context) (int64, error) {
+	version, err := c.client.Get(ctx, ofacRescreenedKey).Int64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get %s: %w", ofacRescreenedKey, err)
+	}
+	return version, nil
+}
+
+// MarkRescreened records that the delta leading to a list version was
+// re-screened
+func (c *OFACCache) MarkRescreened(ctx context.Context, version int64) error {
+	if err := c.client.Set(ctx, ofacRescreenedKey, version, 0).Err(); err != nil {
+		return fmt.Errorf("set %s: %w", ofacRescreenedKey, err)
+	}
+	return nil
+}
+
+// replaceHash atomically swaps the contents of a hash
+func replaceHash(ctx context.Context, client *goredis.Client, key string, fields map[string]interface{}, ttl time.Duration) error {
+	tmpKey := key + ":loading"
+
+	pipe := client.TxPipeline()
+	pipe.Del(ctx, tmpKey)
+	if len(fields) > 0 {
+		pipe.HSet(ctx, tmpKey, fields)
+		pipe.Rename(ctx, tmpKey, key)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+	} else {
+		pipe.Del(ctx, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("replace %s: %w", key, err)
+	}
+	return nil
+}
+
+// writeHash adds fields to a hash and clears their tombstones. With
+// replace, every other field of the hash is deleted and tombstoned with
+// now, so the hash holds exactly fields. It runs under WATCH and is retried
+// if another writer changes the hash in between.
+func writeHash(ctx context.Context, client *goredis.Client, key, tombstoneKey string, fields map[string]interface{}, ttl time.Duration, replace bool, now time.Time) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	removedAt := now.UTC().Format(time.RFC3339Nano)
+
+	write := func(tx *goredis.Tx) error {
+		var stale []string
+		if replace {
+			cached, err := tx.HKeys(ctx, key).Result()
+			if err != nil {
+				return err
+			}
+			for _, name := range cached {
+				if _, ok := fields[name]; !ok {
+					stale = append(stale, name)
+				}
+			}
+		}
+
+		_, err := tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			if len(fields) > 0 {
+				pipe.HSet(ctx, key, fields)
+				pipe.HDel(ctx, tombstoneKey, names...)
+			}
+			if len(stale) > 0 {
+				pipe.HDel(ctx, key, stale...)
+				tombstones := make(map[string]interface{}, len(stale))
+				for _, name := range stale {
+					tombstones[name] = removedAt
+				}
+				pipe.HSet(ctx, tombstoneKey, tombstones)
+			}
+			if ttl > 0 {
+				pipe.Expire(ctx, key, ttl)
+				pipe.Expire(ctx, tombstoneKey, ttl)
+			}
+			return nil
+		})
+		return err
+	}
+
+	for range maxWatchRetries {
+		err := client.Watch(ctx, write, key)
+		if errors.Is(err, goredis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("write %s: %w", key, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("write %s: list changed concurrently %d times", key, maxWatchRetries)
+}
+
+// getTime reads an RFC 3339 timestamp key, returning the zero time if unset
+func getTime(ctx context.Context, client *goredis.Client, key string) (time.Time, error) {
+	value, err := client.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("get %s: %w", key, err)
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
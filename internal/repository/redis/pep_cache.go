@@ -0,0 +1,132 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+const (
+	pepEntriesKey    = keyPrefix + "pep:entries"     // hash: normalized name -> entry JSON
+	pepLastUpdateKey = keyPrefix + "pep:last_update" // RFC 3339 timestamp
+	pepVersionKey    = keyPrefix + "pep:version"     // hash: version, hash, entries, loaded_at
+)
+
+// PEPCache stores the PEP list in Redis
+type PEPCache struct {
+	client *goredis.Client
+}
+
+// NewPEPCache creates a new PEP cache
+func NewPEPCache(client *goredis.Client) *PEPCache {
+	return &PEPCache{client: client}
+}
+
+// GetByName returns the entry indexed under a normalized name
+func (c *PEPCache) GetByName(ctx context.Context, name string) (*screening.PEPEntry, error) {
+	data, err := c.client.HGet(ctx, pepEntriesKey, name).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			metrics.RecordCacheLookup("pep", false)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get pep entry: %w", err)
+	}
+
+	metrics.RecordCacheLookup("pep", true)
+
+	var entry screening.PEPEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshal pep entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// GetByFuzzyName returns entries whose normalized name is at least threshold
+// similar to name by matcher, best match first
+func (c *PEPCache) GetByFuzzyName(ctx context.Context, name string, matcher screening.NameMatcher, threshold float64) ([]screening.PEPEntry, error) {
+	entries, err := c.GetAllEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		entry screening.PEPEntry
+		score float64
+	}
+	var matches []scored
+	for _, entry := range entries {
+		if score := matcher.Similarity(name, entry.NormalizedName); score >= threshold {
+			matches = append(matches, scored{entry: entry, score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	result := make([]screening.PEPEntry, len(matches))
+	for i, m := range matches {
+		result[i] = m.entry
+	}
+	return result, nil
+}
+
+// GetAllEntries returns every cached entry
+func (c *PEPCache) GetAllEntries(ctx context.Context) ([]screening.PEPEntry, error) {
+	values, err := c.client.HVals(ctx, pepEntriesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get pep entries: %w", err)
+	}
+
+	entries := make([]screening.PEPEntry, 0, len(values))
+	for _, v := range values {
+		var entry screening.PEPEntry
+		if err := json.Unmarshal([]byte(v), &entry); err != nil {
+			return nil, fmt.Errorf("unmarshal pep entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// SetEntries replaces the cached list
+func (c *PEPCache) SetEntries(ctx context.Context, entries []screening.PEPEntry, ttl time.Duration) error {
+	fields := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("marshal pep entry: %w", err)
+		}
+		fields[entry.NormalizedName] = data
+	}
+
+	return replaceHash(ctx, c.client, pepEntriesKey, fields, ttl)
+}
+
+// GetLastUpdate returns when the list was last refreshed
+func (c *PEPCache) GetLastUpdate(ctx context.Context) (time.Time, error) {
+	return getTime(ctx, c.client, pepLastUpdateKey)
+}
+
+// SetLastUpdate records when the list was last refreshed
+func (c *PEPCache) SetLastUpdate(ctx context.Context, t time.Time) error {
+	return c.client.Set(ctx, pepLastUpdateKey, t.UTC().Format(time.RFC3339Nano), 0).Err()
+}
+
+// GetVersion returns the list's current version, or the zero version if
+// none was recorded
+func (c *PEPCache) GetVersion(ctx context.Context) (screening.ListVersion, error) {
+	return getListVersion(ctx, c.client, pepVersionKey)
+}
+
+// RecordVersion makes the entries with the given hash the list's current
+// version and reports whether it is a new one
+func (c *PEPCache) RecordVersion(ctx context.Context, hash string, entries int, at time.Time) (screening.ListVersion, bool, error) {
+	return recordListVersion(ctx, c.client, pepVersionKey, hash, entries, at)
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+)
+
+const resultKeyPrefix = keyPrefix + "screening:result:" // + content key -> result JSON
+
+// ResultCache stores screening results keyed by transaction content so
+// identical payloads skip the screening pipeline
+type ResultCache struct {
+	client *goredis.Client
+}
+
+// NewResultCache creates a new screening result cache
+func NewResultCache(client *goredis.Client) *ResultCache {
+	return &ResultCache{client: client}
+}
+
+// Get returns the result cached under key, or nil on a miss
+func (c *ResultCache) Get(ctx context.Context, key string) (*domain.ScreeningResult, error) {
+	data, err := c.client.Get(ctx, resultKeyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			metrics.RecordCacheLookup("screening_result", false)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get screening result: %w", err)
+	}
+
+	metrics.RecordCacheLookup("screening_result", true)
+
+	var result domain.ScreeningResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal screening result: %w", err)
+	}
+	return &result, nil
+}
+
+// Set caches a result under key for ttl
+func (c *ResultCache) Set(ctx context.Context, key string, result *domain.ScreeningResult, ttl time.Duration) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal screening result: %w", err)
+	}
+	if err := c.client.Set(ctx, resultKeyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("set screening result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+const (
+	velocityHourTTL = 2 * time.Hour
+	velocityDayTTL  = 31 * 24 * time.Hour
+	velocityDays    = 30
+)
+
+// VelocityCache tracks per-user transaction counts and amounts in
+// hourly and daily buckets
+//
+// Keys:
+//
+//	aml:velocity:{user}:h:{YYYYMMDDHH}  hash {count, amount_minor}
+//	aml:velocity:{user}:d:{YYYYMMDD}    hash {count, amount_minor}
+//	aml:velocity:{user}:baseline        hash {avg_daily_tx_count, avg_daily_amount, std_dev_daily_amount, baseline_days}
+//	aml:velocity:{user}:location        JSON domain.TransactionLocation of the last screened transaction
+//
+// amount_minor is an integer count of money.Amount units, so bucket sums
+// are exact. Buckets written before it was introduced hold a float amount
+// field instead, which is still read until those buckets expire.
+//
+// A stored baseline, written by the velocity baseline job, takes
+// precedence; without one the baseline is derived from the daily buckets
+// before today.
+type VelocityCache struct {
+	client *goredis.Client
+}
+
+// NewVelocityCache creates a new velocity cache
+func NewVelocityCache(client *goredis.Client) *VelocityCache {
+	return &VelocityCache{client: client}
+}
+
+// GetVelocity returns the user's current velocity and baseline
+func (c *VelocityCache) GetVelocity(ctx context.Context, userID uuid.UUID) (*domain.VelocityData, error) {
+	now := time.Now().UTC()
+
+	pipe := c.client.Pipeline()
+	hourCmd := pipe.HGetAll(ctx, velocityHourKey(userID, now))
+	dayCmds := make([]*goredis.MapStringStringCmd, velocityDays)
+	for i := 0; i < velocityDays; i++ {
+		dayCmds[i] = pipe.HGetAll(ctx, velocityDayKey(userID, now.AddDate(0, 0, -i)))
+	}
+	baselineCmd := pipe.HGetAll(ctx, velocityBaselineKey(userID))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("get velocity: %w", err)
+	}
+
+	data := &domain.VelocityData{UserID: userID, UpdatedAt: now}
+	data.TxCountHour, data.AmountHour = parseBucket(hourCmd.Val())
+
+	counts := make([]int, velocityDays)
+	amounts := make([]money.Amount, velocityDays)
+	for i, cmd := range dayCmds {
+		count, amount := parseBucket(cmd.Val())
+		counts[i], amounts[i] = count, amount
+		if i == 0 {
+			data.TxCountDay, data.AmountDay = count, amount
+		}
+		if i < 7 {
+			data.TxCountWeek += count
+			data.AmountWeek += amount
+		}
+		data.TxCountMonth += count
+		data.AmountMonth += amount
+	}
+
+	if baseline := baselineCmd.Val(); len(baseline) > 0 {
+		data.AvgDailyTxCount = parseFloat(baseline["avg_daily_tx_count"])
+		data.AvgDailyAmount = parseFloat(baseline["avg_daily_amount"])
+		data.StdDevDailyAmount = parseFloat(baseline["std_dev_daily_amount"])
+		data.BaselineDays, _ = strconv.Atoi(baseline["baseline_days"])
+	} else {
+		setBaseline(data, counts, amounts)
+	}
+
+	return data, nil
+}
+
+// setBaseline derives the daily baseline from the buckets before today,
+// starting at the oldest day with activity so a new customer's empty days
+// before onboarding do not drag the average down. Quiet days after that
+// count as zero, so a dormant account keeps a low baseline.
+func setBaseline(data *domain.VelocityData, counts []int, amounts []money.Amount) {
+	days := 0
+	for i := len(amounts) - 1; i > 0; i-- {
+		if counts[i] > 0 {
+			days = i
+			break
+		}
+	}
+	if days == 0 {
+		return
+	}
+
+	var totalCount int
+	var totalAmount money.Amount
+	for i := 1; i <= days; i++ {
+		totalCount += counts[i]
+		totalAmount += amounts[i]
+	}
+	mean := totalAmount.Float64() / float64(days)
+
+	var variance float64
+	for i := 1; i <= days; i++ {
+		d := amounts[i].Float64() - mean
+		variance += d * d
+	}
+
+	data.BaselineDays = days
+	data.AvgDailyTxCount = float64(totalCount) / float64(days)
+	data.AvgDailyAmount = mean
+	data.StdDevDailyAmount = math.Sqrt(variance / float64(days))
+}
+
+// SetBaselines stores recomputed baselines, replacing the users' previous
+// ones. All of them are written in one MULTI/EXEC transaction, so a reader
+// sees either the old or the new baseline, never a mix. Each expires after
+// ttl, after which the baseline is again derived from the daily buckets.
+func (c *VelocityCache) SetBaselines(ctx context.Context, baselines map[uuid.UUID]*domain.VelocityBaseline, ttl time.Duration) error {
+	pipe := c.client.TxPipeline()
+	for userID, b := range baselines {
+		key := velocityBaselineKey(userID)
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key,
+			"avg_daily_tx_count", b.AvgDailyTxCount,
+			"avg_daily_amount", b.AvgDailyAmount,
+			"std_dev_daily_amount", b.StdDevDailyAmount,
+			"baseline_days", b.BaselineDays,
+		)
+		pipe.Expire(ctx, key, ttl)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("set velocity baselines: %w", err)
+	}
+	return nil
+}
+
+// IncrementVelocity records a transaction in the current hour and day buckets
+func (c *VelocityCache) IncrementVelocity(ctx context.Context, userID uuid.UUID, amount money.Amount) error {
+	now := time.Now().UTC()
+	hourKey := velocityHourKey(userID, now)
+	dayKey := velocityDayKey(userID, now)
+
+	pipe := c.client.TxPipeline()
+	pipe.HIncrBy(ctx, hourKey, "count", 1)
+	pipe.HIncrBy(ctx, hourKey, "amount_minor", int64(amount))
+	pipe.Expire(ctx, hourKey, velocityHourTTL)
+	pipe.HIncrBy(ctx, dayKey, "count", 1)
+	pipe.HIncrBy(ctx, dayKey, "amount_minor", int64(amount))
+	pipe.Expire(ctx, dayKey, velocityDayTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("increment velocity: %w", err)
+	}
+	return nil
+}
+
+// LastLocation returns where the user's last screened transaction was
+// made, or nil if no location is held
+func (c *VelocityCache) LastLocation(ctx context.Context, userID uuid.UUID) (*domain.TransactionLocation, error) {
+	data, err := c.client.Get(ctx, velocityLocationKey(userID)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get last location: %w", err)
+	}
+
+	var last domain.TransactionLocation
+	if err := json.Unmarshal(data, &last); err != nil {
+		return nil, fmt.Errorf("unmarshal location: %w", err)
+	}
+	return &last, nil
+}
+
+// SwapLastLocation stores where the user's current transaction was made,
+// replacing and returning the previous location, or nil if none is held.
+// The location expires after ttl.
+func (c *VelocityCache) SwapLastLocation(ctx context.Context, userID uuid.UUID, loc *domain.TransactionLocation, ttl time.Duration) (*domain.TransactionLocation, error) {
+	data, err := json.Marshal(loc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal location: %w", err)
+	}
+
+	previous, err := c.client.SetArgs(ctx, velocityLocationKey(userID), data, goredis.SetArgs{Get: true, TTL: ttl}).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("swap last location: %w", err)
+	}
+
+	var last domain.TransactionLocation
+	if err := json.Unmarshal([]byte(previous), &last); err != nil {
+		return nil, fmt.Errorf("unmarshal location: %w", err)
+	}
+	return &last, nil
+}
+
+func velocityHourKey(userID uuid.UUID, t time.Time) string {
+	return keyPrefix + "velocity:" + userID.String() + ":h:" + t.Format("2006010215")
+}
+
+func velocityDayKey(userID uuid.UUID, t time.Time) string {
+	return keyPrefix + "velocity:" + userID.String() + ":d:" + t.Format("20060102")
+}
+
+func velocityBaselineKey(userID uuid.UUID) string {
+	return keyPrefix + "velocity:" + userID.String() + ":baseline"
+}
+
+func velocityLocationKey(userID uuid.UUID) string {
+	return keyPrefix + "velocity:" + userID.String() + ":location"
+}
+
+// parseBucket reads the count and amount of a velocity bucket, adding any
+// legacy float amount to the exact one
+func parseBucket(fields map[string]string) (int, money.Amount) {
+	count, _ := strconv.Atoi(fields["count"])
+	minor, _ := strconv.ParseInt(fields["amount_minor"], 10, 64)
+	return count, money.Amount(minor) + money.FromFloat(parseFloat(fields["amount"]))
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,66 @@
+package screening
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// amountBands scales sanctions and PEP factor weights with the size of the
+// transaction, so a PEP receiving $5M weighs more than one receiving $50.
+// Amounts are converted to USD with the currency converter. Multipliers
+// never go below 1, so small transactions flag exactly as before.
+type amountBands struct {
+	bands     []config.AmountBandConfig // ascending by MinUSD
+	converter CurrencyConverter
+}
+
+func newAmountBands(cfg *config.ScreeningConfig, converter CurrencyConverter) *amountBands {
+	bands := append([]config.AmountBandConfig(nil), cfg.AmountBands...)
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinUSD < bands[j].MinUSD })
+	return &amountBands{bands: bands, converter: converter}
+}
+
+// usdAmount converts a transaction amount to USD. ok is false when the
+// currency cannot be converted.
+func (a *amountBands) usdAmount(tx *domain.Transaction) (money.Amount, bool) {
+	return a.converter.ConvertTo(tx.Amount, tx.Currency, "USD")
+}
+
+// multiplier returns the multiplier of the highest band the transaction's
+// USD amount reaches, or 1 below every band or when the amount cannot be
+// converted
+func (a *amountBands) multiplier(tx *domain.Transaction) float64 {
+	usd, ok := a.usdAmount(tx)
+	if !ok {
+		return 1
+	}
+
+	m := 1.0
+	for _, band := range a.bands {
+		if usd < money.FromFloat(band.MinUSD) {
+			break
+		}
+		m = band.Multiplier
+	}
+	return m
+}
+
+// scale applies the transaction's multiplier to a factor weight, capped at
+// the maximum risk score
+func (a *amountBands) scale(weight int, tx *domain.Transaction) int {
+	return min(int(math.Round(float64(weight)*a.multiplier(tx))), 100)
+}
+
+// details appends the amount to a factor's details when it was scaled
+func (a *amountBands) details(details string, tx *domain.Transaction) string {
+	m := a.multiplier(tx)
+	if m == 1 {
+		return details
+	}
+	return fmt.Sprintf("%s; weight x%.2g for %s %s", details, m, tx.Amount, tx.Currency)
+}
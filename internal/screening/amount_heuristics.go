@@ -0,0 +1,112 @@
+package screening
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// calculateAmountHeuristics adds the low-weight factors for amounts that
+// look arranged rather than owed: round amounts, amounts just below the CTR
+// threshold and amounts repeated to the same counterparty.
+//
+// Roundness is judged in the currency the amount was sent in, since a round
+// EUR 5,000 converts to an uneven base-currency amount and an uneven one can
+// convert to a round one. The floor and the CTR band are base-currency
+// amounts, so both need a converted amount. Repeated amounts compare the
+// amount and currency as sent.
+func (c *RiskCalculator) calculateAmountHeuristics(sctx *ScreeningContext, baseAmount money.Amount, converted bool) int {
+	tx := sctx.Transaction
+	if tx.Amount <= 0 {
+		return 0
+	}
+
+	score := 0
+	base := c.converter.Base()
+	amountDetails := fmt.Sprintf("%s %s (%s %s)", tx.Amount.StringFixed(2), tx.Currency, baseAmount.StringFixed(2), base)
+
+	if converted && c.cfg.RoundAmountWeight > 0 && baseAmount >= money.FromFloat(c.cfg.RoundAmountFloor) &&
+		isRoundAmount(tx.Amount, money.FromFloat(c.cfg.RoundAmountUnit)) {
+		score += c.addAmountFactor(sctx, "ROUND_AMOUNT", c.cfg.RoundAmountWeight,
+			fmt.Sprintf("Amount is a whole multiple of %s %s", money.FromFloat(c.cfg.RoundAmountUnit).StringFixed(0), tx.Currency),
+			amountDetails, baseAmount, true)
+	}
+
+	if ctrThreshold := c.ctrThresholdFor(sctx); converted && c.cfg.ThresholdAdjacentWeight > 0 && ctrThreshold > 0 {
+		ctr := money.FromFloat(ctrThreshold)
+		if baseAmount < ctr && baseAmount >= ctr-money.FromFloat(c.cfg.ThresholdAdjacentBand) {
+			score += c.addAmountFactor(sctx, "THRESHOLD_ADJACENT", c.cfg.ThresholdAdjacentWeight,
+				fmt.Sprintf("Amount is just below the %s %s currency transaction reporting threshold", ctr.StringFixed(2), base),
+				amountDetails, baseAmount, true)
+		}
+	}
+
+	if c.cfg.RepeatedAmountWeight > 0 {
+		if repeats := c.countRepeatedAmounts(sctx); repeats >= c.cfg.RepeatedAmountMinCount {
+			key, _ := tx.Counterparty()
+			score += c.addAmountFactor(sctx, "REPEATED_AMOUNT", c.cfg.RepeatedAmountWeight,
+				"Same amount sent to the same counterparty repeatedly",
+				fmt.Sprintf("%s %s to %s at %s %d times before in %s",
+					tx.Amount.StringFixed(2), tx.Currency, key.Account, bankName(key.Bank), repeats, formatWindow(c.cfg.RepeatedAmountWindow)),
+				baseAmount, converted)
+		}
+	}
+
+	return score
+}
+
+// countRepeatedAmounts counts the user's earlier transactions within the
+// repeated amount window that sent the same amount in the same currency to
+// the same counterparty as the transaction being screened
+func (c *RiskCalculator) countRepeatedAmounts(sctx *ScreeningContext) int {
+	tx := sctx.Transaction
+	key, ok := tx.Counterparty()
+	if !ok {
+		return 0
+	}
+
+	at := tx.InitiatedAt
+	if at.IsZero() {
+		at = sctx.StartTime
+	}
+	since := at.Add(-c.cfg.RepeatedAmountWindow)
+
+	repeats := 0
+	for _, h := range sctx.RecentTransactions {
+		if h.ID == tx.ID || h.Amount != tx.Amount || !strings.EqualFold(h.Currency, tx.Currency) {
+			continue
+		}
+		if h.InitiatedAt.Before(since) || h.InitiatedAt.After(at) {
+			continue
+		}
+		if hKey, ok := h.Counterparty(); ok && hKey == key {
+			repeats++
+		}
+	}
+	return repeats
+}
+
+// ctrThresholdFor returns the CTR threshold of the screening's tenant, or
+// the configured one if it sets none
+func (c *RiskCalculator) ctrThresholdFor(sctx *ScreeningContext) float64 {
+	if settings := sctx.TenantSettings; settings != nil && settings.CTRThreshold != nil {
+		return *settings.CTRThreshold
+	}
+	return c.ctrThreshold
+}
+
+// RepeatedAmountWindow is how far back the calculator looks for repeated
+// amounts; the engine loads the user's transactions over this window
+func (c *RiskCalculator) RepeatedAmountWindow() time.Duration {
+	if c.cfg.RepeatedAmountWeight <= 0 {
+		return 0
+	}
+	return c.cfg.RepeatedAmountWindow
+}
+
+// isRoundAmount reports whether amount is a whole multiple of unit
+func isRoundAmount(amount, unit money.Amount) bool {
+	return unit > 0 && amount%unit == 0
+}
@@ -0,0 +1,96 @@
+package screening
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/currency"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// amountFactors runs the amount heuristics on a transaction with a USD
+// base, EUR at 1.08 USD and a 10,000 USD CTR threshold, and returns the
+// factors raised
+func amountFactors(t *testing.T, amount float64, cur string) []domain.RiskFactor {
+	t.Helper()
+	converter := currency.NewConverter(&config.CurrencyConfig{
+		BaseCurrency: "USD",
+		Rates:        map[string]float64{"eur": 1.08},
+	}, nil, logger.NewNop())
+	calculator := NewRiskCalculator(&config.PatternsConfig{
+		RoundAmountFloor:        1000,
+		RoundAmountUnit:         1000,
+		RoundAmountWeight:       5,
+		ThresholdAdjacentBand:   1000,
+		ThresholdAdjacentWeight: 10,
+	}, nil, converter, 10000, logger.NewNop())
+
+	tx := &domain.Transaction{ID: uuid.New(), UserID: uuid.New(), Amount: money.FromFloat(amount), Currency: cur}
+	sctx := &ScreeningContext{Transaction: tx}
+	baseAmount, converted := converter.Convert(tx.Amount, tx.Currency)
+	if !converted {
+		t.Fatalf("no rate for %s", cur)
+	}
+	calculator.calculateAmountHeuristics(sctx, baseAmount, converted)
+	return sctx.RiskFactors
+}
+
+func hasFactor(factors []domain.RiskFactor, name string) bool {
+	for _, f := range factors {
+		if f.Factor == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRoundAmountJudgedInCurrencySent(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     bool
+	}{
+		{"round EUR, uneven in USD", 5000, "EUR", true},
+		{"uneven EUR, round in USD", 4629.6296, "EUR", false},
+		{"round USD", 5000, "USD", true},
+		{"uneven USD", 5000.01, "USD", false},
+		{"round EUR below the base-currency floor", 900, "EUR", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasFactor(amountFactors(t, tt.amount, tt.currency), "ROUND_AMOUNT"); got != tt.want {
+				t.Errorf("ROUND_AMOUNT raised = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThresholdAdjacentBandInBaseCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     bool
+	}{
+		{"band floor", 9000, "USD", true},
+		{"just below the threshold", 9999.99, "USD", true},
+		{"at the threshold", 10000, "USD", false},
+		{"below the band", 8999.99, "USD", false},
+		{"EUR inside the band once converted", 8800, "EUR", true},
+		{"EUR in the band nominally, over the threshold once converted", 9500, "EUR", false},
+		{"EUR in the band nominally, below it once converted", 8300, "EUR", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factors := amountFactors(t, tt.amount, tt.currency)
+			if got := hasFactor(factors, "THRESHOLD_ADJACENT"); got != tt.want {
+				t.Errorf("THRESHOLD_ADJACENT raised = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package screening
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/breaker"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// The wrappers below route the screening path's Redis and Postgres lookups
+// through a circuit breaker. While a breaker is open calls fail immediately
+// with an error matching breaker.IsOpen instead of waiting out a timeout.
+
+// breakerOFACCache guards an OFACCache
+type breakerOFACCache struct {
+	next OFACCache
+	cb   *breaker.Breaker
+}
+
+// WithOFACBreaker wraps an OFAC cache in a circuit breaker
+func WithOFACBreaker(next OFACCache, cb *breaker.Breaker) OFACCache {
+	return &breakerOFACCache{next: next, cb: cb}
+}
+
+func (c *breakerOFACCache) GetByExactName(ctx context.Context, name string) (*OFACEntry, error) {
+	return breaker.Do(c.cb, func() (*OFACEntry, error) { return c.next.GetByExactName(ctx, name) })
+}
+
+func (c *breakerOFACCache) GetByFuzzyName(ctx context.Context, name string, matcher NameMatcher, threshold float64) ([]OFACEntry, error) {
+	return breaker.Do(c.cb, func() ([]OFACEntry, error) { return c.next.GetByFuzzyName(ctx, name, matcher, threshold) })
+}
+
+func (c *breakerOFACCache) GetAllEntries(ctx context.Context) ([]OFACEntry, error) {
+	return breaker.Do(c.cb, func() ([]OFACEntry, error) { return c.next.GetAllEntries(ctx) })
+}
+
+func (c *breakerOFACCache) SetEntries(ctx context.Context, entries []OFACEntry, ttl time.Duration, mode ListWriteMode) error {
+	return c.cb.Run(func() error { return c.next.SetEntries(ctx, entries, ttl, mode) })
+}
+
+func (c *breakerOFACCache) GetLastUpdate(ctx context.Context) (time.Time, error) {
+	return breaker.Do(c.cb, func() (time.Time, error) { return c.next.GetLastUpdate(ctx) })
+}
+
+func (c *breakerOFACCache) SetLastUpdate(ctx context.Context, t time.Time) error {
+	return c.cb.Run(func() error { return c.next.SetLastUpdate(ctx, t) })
+}
+
+func (c *breakerOFACCache) GetVersion(ctx context.Context) (ListVersion, error) {
+	return breaker.Do(c.cb, func() (ListVersion, error) { return c.next.GetVersion(ctx) })
+}
+
+func (c *breakerOFACCache) RecordVersion(ctx context.Context, hash string, entries int, at time.Time) (version ListVersion, created bool, err error) {
+	err = c.cb.Run(func() error {
+		version, created, err = c.next.RecordVersion(ctx, hash, entries, at)
+		return err
+	})
+	return version, created, err
+}
+
+// breakerPEPCache guards a PEPCache
+type breakerPEPCache struct {
+	next PEPCache
+	cb   *breaker.Breaker
+}
+
+// WithPEPBreaker wraps a PEP cache in a circuit breaker
+func WithPEPBreaker(next PEPCache, cb *breaker.Breaker) PEPCache {
+	return &breakerPEPCache{next: next, cb: cb}
+}
+
+func (c *breakerPEPCache) GetByName(ctx context.Context, name string) (*PEPEntry, error) {
+	return breaker.Do(c.cb, func() (*PEPEntry, error) { return c.next.GetByName(ctx, name) })
+}
+
+func (c *breakerPEPCache) GetByFuzzyName(ctx context.Context, name string, matcher NameMatcher, threshold float64) ([]PEPEntry, error) {
+	return breaker.Do(c.cb, func() ([]PEPEntry, error) { return c.next.GetByFuzzyName(ctx, name, matcher, threshold) })
+}
+
+func (c *breakerPEPCache) GetAllEntries(ctx context.Context) ([]PEPEntry, error) {
+	return breaker.Do(c.cb, func() ([]PEPEntry, error) { return c.next.GetAllEntries(ctx) })
+}
+
+func (c *breakerPEPCache) SetEntries(ctx context.Context, entries []PEPEntry, ttl time.Duration) error {
+	return c.cb.Run(func() error { return c.next.SetEntries(ctx, entries, ttl) })
+}
+
+func (c *breakerPEPCache) GetLastUpdate(ctx context.Context) (time.Time, error) {
+	return breaker.Do(c.cb, func() (time.Time, error) { return c.next.GetLastUpdate(ctx) })
+}
+
+func (c *breakerPEPCache) GetVersion(ctx context.Context) (ListVersion, error) {
+	return breaker.Do(c.cb, func() (ListVersion, error) { return c.next.GetVersion(ctx) })
+}
+
+func (c *breakerPEPCache) RecordVersion(ctx context.Context, hash string, entries int, at time.Time) (version ListVersion, created bool, err error) {
+	err = c.cb.Run(func() error {
+		version, created, err = c.next.RecordVersion(ctx, hash, entries, at)
+		return err
+	})
+	return version, created, err
+}
+
+// breakerVelocityCache guards a VelocityCache
+type breakerVelocityCache struct {
+	next VelocityCache
+	cb   *breaker.Breaker
+}
+
+// WithVelocityBreaker wraps a velocity cache in a circuit breaker
+func WithVelocityBreaker(next VelocityCache, cb *breaker.Breaker) VelocityCache {
+	return &breakerVelocityCache{next: next, cb: cb}
+}
+
+func (c *breakerVelocityCache) GetVelocity(ctx context.Context, userID uuid.UUID) (*domain.VelocityData, error) {
+	return breaker.Do(c.cb, func() (*domain.VelocityData, error) { return c.next.GetVelocity(ctx, userID) })
+}
+
+func (c *breakerVelocityCache) IncrementVelocity(ctx context.Context, userID uuid.UUID, amount money.Amount) error {
+	return c.cb.Run(func() error { return c.next.IncrementVelocity(ctx, userID, amount) })
+}
+
+// breakerRiskProfiles guards a RiskProfileRepository. The breaker should be
+// created with domain.ErrNotFound ignored so new users do not trip it.
+type breakerRiskProfiles struct {
+	next RiskProfileRepository
+	cb   *breaker.Breaker
+}
+
+// WithRiskProfileBreaker wraps a risk profile repository in a circuit breaker
+func WithRiskProfileBreaker(next RiskProfileRepository, cb *breaker.Breaker) RiskProfileRepository {
+	return &breakerRiskProfiles{next: next, cb: cb}
+}
+
+func (r *breakerRiskProfiles) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserRiskProfile, error) {
+	return breaker.Do(r.cb, func() (*domain.UserRiskProfile, error) { return r.next.GetByUserID(ctx, userID) })
+}
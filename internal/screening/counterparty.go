@@ -0,0 +1,130 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// CounterpartyStore keeps what screening has learnt about each external
+// account, per tenant. Get returns nil for a counterparty never recorded; Record adds
+// an event, decaying the stored counts with the given half-life.
+type CounterpartyStore interface {
+	Get(ctx context.Context, key domain.CounterpartyKey) (*domain.CounterpartyReputation, error)
+	Record(ctx context.Context, event domain.CounterpartyEvent, halfLife time.Duration) error
+}
+
+// CounterpartyChecker scores a transaction's external account by the
+// blocked and suspicious screenings and SAR filings it has been party to
+// across all of the tenant's users, and records new ones. Reputations are
+// kept per tenant, so one tenant's history never weighs on, or shows in,
+// another's screenings.
+type CounterpartyChecker struct {
+	store CounterpartyStore
+	cfg   *config.CounterpartyConfig
+	log   *logger.Logger
+}
+
+// NewCounterpartyChecker creates a new counterparty reputation checker
+func NewCounterpartyChecker(store CounterpartyStore, cfg *config.CounterpartyConfig, log *logger.Logger) *CounterpartyChecker {
+	return &CounterpartyChecker{
+		store: store,
+		cfg:   cfg,
+		log:   log.Named("counterparty_checker"),
+	}
+}
+
+// Check returns a COUNTERPARTY_REPUTATION factor weighted by the
+// counterparty's decayed history, or nil if it has none worth scoring
+func (c *CounterpartyChecker) Check(ctx context.Context, tx *domain.Transaction, now time.Time) (*domain.RiskFactor, error) {
+	key, ok := tx.Counterparty()
+	if !ok {
+		return nil, nil
+	}
+	key.TenantID = tenant.OrDefault(ctx)
+
+	rep, err := c.store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("get counterparty reputation: %w", err)
+	}
+	if rep == nil {
+		return nil, nil
+	}
+
+	rep = rep.DecayedTo(now, c.cfg.HalfLife)
+	weight := c.Weight(rep)
+	if weight < c.cfg.MinWeight {
+		return nil, nil
+	}
+
+	return &domain.RiskFactor{
+		Factor:      "COUNTERPARTY_REPUTATION",
+		Weight:      weight,
+		Description: "Counterparty account has a history of blocked or suspicious transfers or SAR filings",
+		Details: fmt.Sprintf("%s at %s: %.1f blocked, %.1f suspicious, %.1f SARs (decayed, half-life %s)",
+			key.Account, bankName(key.Bank), rep.Blocked, rep.Suspicious, rep.SARs, formatWindow(c.cfg.HalfLife)),
+	}, nil
+}
+
+// Weight converts a decayed reputation into a risk factor weight, capped
+// at the configured maximum
+func (c *CounterpartyChecker) Weight(rep *domain.CounterpartyReputation) int {
+	points := rep.Blocked*c.cfg.BlockedPoints + rep.Suspicious*c.cfg.SuspiciousPoints + rep.SARs*c.cfg.SARPoints
+	return min(int(math.Round(points)), c.cfg.MaxWeight)
+}
+
+// RecordDecision counts a blocked or suspicious screening against the
+// transaction's counterparty, for the tenant ctx acts for. Other decisions
+// are not recorded.
+func (c *CounterpartyChecker) RecordDecision(ctx context.Context, tx *domain.Transaction, decision domain.ScreeningDecision, at time.Time) error {
+	event, ok := DecisionEvent(tenant.OrDefault(ctx), tx, decision, at)
+	if !ok {
+		return nil
+	}
+	return c.Record(ctx, event)
+}
+
+// Record adds an event to the counterparty's reputation
+func (c *CounterpartyChecker) Record(ctx context.Context, event domain.CounterpartyEvent) error {
+	return c.store.Record(ctx, event, c.cfg.HalfLife)
+}
+
+// HalfLife is how long the counts take to decay by half
+func (c *CounterpartyChecker) HalfLife() time.Duration {
+	return c.cfg.HalfLife
+}
+
+// DecisionEvent returns the event a screening decision records against the
+// transaction's counterparty for a tenant. ok is false for decisions that
+// are not recorded and transactions without a counterparty account.
+func DecisionEvent(tenantID string, tx *domain.Transaction, decision domain.ScreeningDecision, at time.Time) (domain.CounterpartyEvent, bool) {
+	key, ok := tx.Counterparty()
+	if !ok {
+		return domain.CounterpartyEvent{}, false
+	}
+	key.TenantID = tenantID
+
+	event := domain.CounterpartyEvent{Key: key, At: at}
+	switch decision {
+	case domain.DecisionBlocked:
+		event.Blocked = 1
+	case domain.DecisionSuspicious:
+		event.Suspicious = 1
+	default:
+		return domain.CounterpartyEvent{}, false
+	}
+	return event, true
+}
+
+func bankName(bank string) string {
+	if bank == "" {
+		return "unknown bank"
+	}
+	return bank
+}
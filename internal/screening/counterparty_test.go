@@ -0,0 +1,74 @@
+package screening
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// memoryCounterparties is a CounterpartyStore holding reputations in
+// memory by their full key
+type memoryCounterparties map[domain.CounterpartyKey]*domain.CounterpartyReputation
+
+func (m memoryCounterparties) Get(_ context.Context, key domain.CounterpartyKey) (*domain.CounterpartyReputation, error) {
+	return m[key], nil
+}
+
+func (m memoryCounterparties) Record(_ context.Context, event domain.CounterpartyEvent, halfLife time.Duration) error {
+	rep, ok := m[event.Key]
+	if !ok {
+		rep = &domain.CounterpartyReputation{CounterpartyKey: event.Key}
+		m[event.Key] = rep
+	}
+	rep.Apply(event, halfLife)
+	return nil
+}
+
+func TestCounterpartyCheckerIsolatesTenants(t *testing.T) {
+	cfg := &config.CounterpartyConfig{
+		HalfLife:         90 * 24 * time.Hour,
+		BlockedPoints:    20,
+		SuspiciousPoints: 8,
+		SARPoints:        25,
+		MinWeight:        5,
+		MaxWeight:        40,
+	}
+	store := memoryCounterparties{}
+	checker := NewCounterpartyChecker(store, cfg, logger.NewNop())
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	tx := &domain.Transaction{ReceiverAccount: "GB29 NWBK 6016 1331 9268 19", ReceiverBank: "NatWest"}
+
+	ctxA := tenant.WithID(context.Background(), "a")
+	ctxB := tenant.WithID(context.Background(), "b")
+
+	if err := checker.RecordDecision(ctxA, tx, domain.DecisionBlocked, now); err != nil {
+		t.Fatalf("RecordDecision: %v", err)
+	}
+
+	factor, err := checker.Check(ctxA, tx, now)
+	if err != nil {
+		t.Fatalf("Check for tenant a: %v", err)
+	}
+	if factor == nil {
+		t.Fatal("tenant a's own blocked screening raised no factor")
+	}
+
+	factor, err = checker.Check(ctxB, tx, now)
+	if err != nil {
+		t.Fatalf("Check for tenant b: %v", err)
+	}
+	if factor != nil {
+		t.Fatalf("tenant b scored on tenant a's history: %+v", factor)
+	}
+
+	for key := range store {
+		if key.TenantID != "a" {
+			t.Errorf("reputation recorded for tenant %q, want a", key.TenantID)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package screening
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// CountryRisk holds the country risk table in force. The table is swapped
+// whole on reload, so a screening scores every country against the same
+// version.
+type CountryRisk struct {
+	current atomic.Pointer[countryRiskIndex]
+}
+
+// countryRiskIndex is a table with its tiers keyed by country
+type countryRiskIndex struct {
+	table *domain.CountryRiskTable
+	tiers map[string]*domain.CountryRiskTier
+}
+
+// NewCountryRisk creates a country risk holder with table in force
+func NewCountryRisk(table *domain.CountryRiskTable) *CountryRisk {
+	r := &CountryRisk{}
+	r.Set(table)
+	return r
+}
+
+// ConfiguredCountryRiskTable returns the country risk table in
+// configuration, as version 0
+func ConfiguredCountryRiskTable(cfg *config.PatternsConfig) *domain.CountryRiskTable {
+	table := &domain.CountryRiskTable{
+		Tiers:        make([]domain.CountryRiskTier, len(cfg.CountryRiskTiers)),
+		Ratings:      make(map[string]int, len(cfg.CountryRiskRatings)),
+		RatingPoints: cfg.CountryRiskRatingPoints,
+		Comment:      "configured",
+		CreatedBy:    domain.SystemActorID,
+	}
+	// Configuration keys are read in lower case
+	for country, rating := range cfg.CountryRiskRatings {
+		table.Ratings[strings.ToUpper(country)] = rating
+	}
+	for i, t := range cfg.CountryRiskTiers {
+		table.Tiers[i] = domain.CountryRiskTier{
+			Name:        t.Name,
+			Points:      t.Points,
+			EDDRequired: t.EDDRequired,
+			Countries:   t.Countries,
+		}
+	}
+	return table
+}
+
+// Set puts table in force
+func (r *CountryRisk) Set(table *domain.CountryRiskTable) {
+	idx := &countryRiskIndex{table: table, tiers: make(map[string]*domain.CountryRiskTier)}
+	for i := range table.Tiers {
+		for _, country := range table.Tiers[i].Countries {
+			idx.tiers[country] = &table.Tiers[i]
+		}
+	}
+	r.current.Store(idx)
+}
+
+// Table returns the table in force
+func (r *CountryRisk) Table() *domain.CountryRiskTable {
+	return r.current.Load().table
+}
+
+// index returns the table in force with its lookup
+func (r *CountryRisk) index() *countryRiskIndex {
+	return r.current.Load()
+}
+
+// tier returns the tier a country is in, or nil if it is in none
+func (idx *countryRiskIndex) tier(country string) *domain.CountryRiskTier {
+	return idx.tiers[country]
+}
@@ -0,0 +1,115 @@
+package screening
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/fuzzy"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// descriptionKeyword is a configured description keyword compiled to a
+// case-insensitive expression over diacritic-folded text
+type descriptionKeyword struct {
+	name   string
+	weight int
+	re     *regexp.Regexp
+}
+
+// compileDescriptionKeywords compiles the configured description keywords.
+// Terms become whole-word expressions whose spaces match any run of
+// non-alphanumeric characters, so "tornado cash" also finds "Tornado-Cash"
+// and "tornado.cash". Entries that do not compile are logged and skipped;
+// configuration validation reports them at startup.
+func compileDescriptionKeywords(keywords []config.DescriptionKeywordConfig, log *logger.Logger) []descriptionKeyword {
+	compiled := make([]descriptionKeyword, 0, len(keywords))
+	for _, k := range keywords {
+		expr := termExpression(k.Term)
+		if k.Pattern != "" {
+			expr = fuzzy.FoldDiacritics(k.Pattern)
+		}
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			log.Warn("skipping description keyword that does not compile",
+				logger.StringField("keyword", k.Name),
+				logger.ErrorField(err),
+			)
+			continue
+		}
+		compiled = append(compiled, descriptionKeyword{name: k.Name, weight: k.Weight, re: re})
+	}
+	return compiled
+}
+
+// termExpression returns a regular expression matching term, with its
+// diacritics folded, as a whole word or phrase
+func termExpression(term string) string {
+	words := strings.Fields(fuzzy.FoldDiacritics(term))
+	if len(words) == 0 {
+		return ""
+	}
+	folded := []rune(strings.Join(words, " "))
+
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	expr := strings.Join(quoted, `[^\pL\pN]+`)
+
+	// \b only separates word characters from others, so it is added on
+	// the sides that end in one
+	if isWordRune(folded[0]) {
+		expr = `\b` + expr
+	}
+	if isWordRune(folded[len(folded)-1]) {
+		expr += `\b`
+	}
+	return expr
+}
+
+// isWordRune reports whether r is an ASCII word character as \b sees it
+func isWordRune(r rune) bool {
+	return r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// calculateDescriptionKeywords adds a DESCRIPTION_KEYWORD factor when the
+// transaction's description or reference contains a watchlisted term. The
+// factor carries the heaviest matching keyword's weight and its details
+// name every term found and where.
+func (c *RiskCalculator) calculateDescriptionKeywords(sctx *ScreeningContext) int {
+	if len(c.keywords) == 0 {
+		return 0
+	}
+
+	tx := sctx.Transaction
+	fields := []struct{ name, text string }{
+		{"description", tx.Description},
+		{"reference", tx.Reference},
+	}
+
+	weight := 0
+	var found []string
+	for _, field := range fields {
+		if strings.TrimSpace(field.text) == "" {
+			continue
+		}
+		folded := fuzzy.FoldDiacritics(field.text)
+		for _, k := range c.keywords {
+			match := k.re.FindString(folded)
+			if match == "" {
+				continue
+			}
+			found = append(found, fmt.Sprintf("%q in %s (%s)", match, field.name, k.name))
+			weight = max(weight, k.weight)
+		}
+	}
+	if len(found) == 0 {
+		return 0
+	}
+
+	return c.addFactor(sctx, "DESCRIPTION_KEYWORD", weight,
+		"Transaction description or reference contains a watchlisted term", strings.Join(found, "; "))
+}
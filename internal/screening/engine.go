@@ -2,33 +2,91 @@ package screening
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/banking/aml-service/internal/audit"
 	"github.com/banking/aml-service/internal/config"
 	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/breaker"
+	"github.com/banking/aml-service/internal/pkg/clock"
 	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/pkg/money"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+	"github.com/banking/aml-service/internal/pkg/tracing"
 )
 
+const tracerName = "github.com/banking/aml-service/internal/screening"
+
+// ErrOriginalTransactionMissing is returned when a stored result predates
+// transaction capture and so cannot be re-screened
+var ErrOriginalTransactionMissing = errors.New("original transaction not stored with screening result")
+
 // Engine is the core screening engine that performs parallel AML checks
 type Engine struct {
 	ofacChecker     *OFACChecker
 	pepChecker      *PEPChecker
+	reputation      *ReputationChecker
+	geoIP           *GeoIPChecker
+	sharedDevices   *SharedDeviceChecker
+	counterparties  *CounterpartyChecker
+	accountDenylist AccountDenylist
 	riskCalculator  *RiskCalculator
+	tenants         *TenantSettings // nil screens every tenant under the configuration
+	shadow          *ShadowScorer   // nil unless shadow mode is enabled
 	patternEngine   PatternDetector
+	history         TransactionHistory // nil leaves repeated amounts unscored
 	velocityCache   VelocityCache
 	riskProfileRepo RiskProfileRepository
+	resultRepo      ScreeningResultRepository
+	resultCache     ResultCache
+	matchCache      MatchCache
+	alertRepo       AlertRepository
+	auditor         Auditor
+	notifier        DecisionNotifier
+
+	// Checks Screen runs in parallel, and those disabled in configuration
+	checks        []engineCheck
+	skippedChecks []string
+
+	// Checks whose failure holds the decision as PENDING
+	failClosed map[string]bool
+
+	// Decision thresholds by risk tier
+	thresholds map[string]domain.DecisionThresholds
+
+	// Scales OFAC and PEP factor weights with the transaction amount
+	amountBands *amountBands
 
-	cfg *config.ScreeningConfig
-	log *logger.Logger
+	cfg    *config.ScreeningConfig
+	log    *logger.Logger
+	tracer trace.Tracer
 
-	// Metrics
+	// clock stamps results, ages former PEPs and measures latencies;
+	// frozen for replays
+	clock clock.Clock
+
+	// Moving average behind GetAverageLatency, running totals behind
+	// GetMeanLatency and recent samples behind GetLatencyPercentile;
+	// distributions are also exported through the metrics package
 	screeningCount int64
 	avgLatencyMs   float64
+	latencySumMs   float64
 	latencyMu      sync.RWMutex
+	latencies      *latencySamples
 }
 
 // PatternDetector interface for pattern detection
@@ -36,10 +94,22 @@ type PatternDetector interface {
 	DetectPatterns(ctx context.Context, userID uuid.UUID, tx *domain.Transaction) ([]domain.PatternMatch, error)
 }
 
+// TransactionHistory lists a user's screened transactions initiated since a
+// time
+type TransactionHistory interface {
+	GetByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.Transaction, error)
+}
+
 // VelocityCache interface for velocity data
 type VelocityCache interface {
 	GetVelocity(ctx context.Context, userID uuid.UUID) (*domain.VelocityData, error)
-	IncrementVelocity(ctx context.Context, userID uuid.UUID, amount float64) error
+	IncrementVelocity(ctx context.Context, userID uuid.UUID, amount money.Amount) error
+}
+
+// AccountDenylist looks up sender and receiver accounts on the internal
+// denylist of blocked account numbers and IBANs
+type AccountDenylist interface {
+	Lookup(ctx context.Context, accounts ...string) ([]*domain.AccountDenylistEntry, error)
 }
 
 // RiskProfileRepository interface for risk profiles
@@ -47,27 +117,157 @@ type RiskProfileRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserRiskProfile, error)
 }
 
+// ScreeningResultRepository interface for screening result persistence
+type ScreeningResultRepository interface {
+	Save(ctx context.Context, result *domain.ScreeningResult) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScreeningResult, error)
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.ScreeningResult, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.ScreeningResult, error)
+}
+
+// ResultCache caches screening results by transaction content
+type ResultCache interface {
+	Get(ctx context.Context, key string) (*domain.ScreeningResult, error)
+	Set(ctx context.Context, key string, result *domain.ScreeningResult, ttl time.Duration) error
+}
+
+// MatchCache caches OFAC and PEP check outcomes by counterparty name and
+// list version. Get methods return nil on a miss.
+type MatchCache interface {
+	GetOFAC(ctx context.Context, key string) (*domain.OFACMatch, error)
+	SetOFAC(ctx context.Context, key string, match *domain.OFACMatch) error
+	GetPEP(ctx context.Context, key string) (*domain.PEPMatch, error)
+	SetPEP(ctx context.Context, key string, match *domain.PEPMatch) error
+}
+
+// AlertRepository interface for alert persistence
+type AlertRepository interface {
+	Create(ctx context.Context, alert *domain.AMLAlert) error
+}
+
+// DecisionNotifier tells downstream systems about screening decisions. It
+// must not block the screening path.
+type DecisionNotifier interface {
+	Notify(ctx context.Context, result *domain.ScreeningResult)
+}
+
+// DecisionNotifiers fans a decision out to several notifiers
+type DecisionNotifiers []DecisionNotifier
+
+// Notify passes result to each notifier in turn
+func (ns DecisionNotifiers) Notify(ctx context.Context, result *domain.ScreeningResult) {
+	for _, n := range ns {
+		n.Notify(ctx, result)
+	}
+}
+
+// Auditor records screening decisions in the audit log
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
 // NewEngine creates a new screening engine
 func NewEngine(
 	ofacChecker *OFACChecker,
 	pepChecker *PEPChecker,
+	reputation *ReputationChecker,
+	geoIP *GeoIPChecker,
+	sharedDevices *SharedDeviceChecker,
+	counterparties *CounterpartyChecker,
+	accountDenylist AccountDenylist,
 	riskCalculator *RiskCalculator,
+	tenants *TenantSettings,
+	shadow *ShadowScorer,
+	converter CurrencyConverter,
 	patternEngine PatternDetector,
+	history TransactionHistory,
 	velocityCache VelocityCache,
 	riskProfileRepo RiskProfileRepository,
+	resultRepo ScreeningResultRepository,
+	resultCache ResultCache,
+	matchCache MatchCache,
+	alertRepo AlertRepository,
+	auditor Auditor,
+	notifier DecisionNotifier,
+	clk clock.Clock,
 	cfg *config.ScreeningConfig,
+	complianceCfg *config.ComplianceConfig,
 	log *logger.Logger,
 ) *Engine {
-	return &Engine{
+	failClosed := make(map[string]bool, len(cfg.FailClosedChecks))
+	for _, check := range cfg.FailClosedChecks {
+		failClosed[check] = true
+	}
+
+	e := &Engine{
 		ofacChecker:     ofacChecker,
 		pepChecker:      pepChecker,
+		reputation:      reputation,
+		geoIP:           geoIP,
+		sharedDevices:   sharedDevices,
+		counterparties:  counterparties,
+		accountDenylist: accountDenylist,
 		riskCalculator:  riskCalculator,
+		tenants:         tenants,
+		shadow:          shadow,
 		patternEngine:   patternEngine,
+		history:         history,
 		velocityCache:   velocityCache,
 		riskProfileRepo: riskProfileRepo,
+		resultRepo:      resultRepo,
+		resultCache:     resultCache,
+		matchCache:      matchCache,
+		alertRepo:       alertRepo,
+		auditor:         auditor,
+		notifier:        notifier,
+		failClosed:      failClosed,
+		thresholds:      buildThresholds(complianceCfg.DecisionThresholds),
+		amountBands:     newAmountBands(cfg, converter),
 		cfg:             cfg,
 		log:             log.Named("screening_engine"),
+		tracer:          otel.Tracer(tracerName),
+		clock:           clk,
+		latencies:       newLatencySamples(latencySampleSize),
 	}
+	e.checks, e.skippedChecks = e.pipeline(cfg.EnabledChecks)
+	return e
+}
+
+// engineCheck is a check Screen runs in parallel with the others
+type engineCheck struct {
+	name string
+	run  func(ctx context.Context, sctx *ScreeningContext) error
+}
+
+// pipeline splits the checks into those enabled, in the order Screen starts
+// them, and the names of those disabled. The account denylist is left out
+// entirely when the engine has none, as in replays.
+func (e *Engine) pipeline(enabled []string) ([]engineCheck, []string) {
+	all := []engineCheck{
+		{name: domain.CheckOFAC, run: e.runOFACCheck},
+		{name: domain.CheckPEP, run: e.runPEPCheck},
+		{name: domain.CheckRiskProfile, run: e.getRiskProfile},
+		{name: domain.CheckVelocity, run: e.getVelocityData},
+		{name: domain.CheckPatterns, run: e.detectPatterns},
+		{name: domain.CheckReputation, run: e.runReputationCheck},
+		{name: domain.CheckGeoIP, run: e.runGeoIPCheck},
+		{name: domain.CheckSharedDevice, run: e.runSharedDeviceCheck},
+		{name: domain.CheckCounterparty, run: e.runCounterpartyCheck},
+	}
+	if e.accountDenylist != nil {
+		all = append(all, engineCheck{name: domain.CheckAccountDenylist, run: e.runAccountDenylistCheck})
+	}
+
+	var checks []engineCheck
+	var skipped []string
+	for _, c := range all {
+		if slices.Contains(enabled, c.name) {
+			checks = append(checks, c)
+		} else {
+			skipped = append(skipped, c.name)
+		}
+	}
+	return checks, skipped
 }
 
 // ScreeningContext holds intermediate results during screening
@@ -75,86 +275,281 @@ type ScreeningContext struct {
 	Transaction *domain.Transaction
 	ScreeningID uuid.UUID
 	StartTime   time.Time
+	BypassIndex bool // list checks read Redis directly, skipping the in-memory indexes and the match cache
+
+	// The tenant screening and its overrides of the configuration, nil if
+	// it has none
+	TenantID       string
+	TenantSettings *domain.TenantSettings
 
 	// Results from parallel checks
 	OFACResult     *domain.OFACMatch
 	PEPResult      *domain.PEPMatch
+	DenylistHit    bool   // sender or receiver account is denylisted
+	BankHit        bool   // sender or receiver bank is a designated bank
+	IPCountry      string // country the IP address is located in, if known
 	RiskProfile    *domain.UserRiskProfile
 	VelocityData   *domain.VelocityData
 	PatternMatches []domain.PatternMatch
 	RiskFactors    []domain.RiskFactor
+	ChecksFailed   []domain.CheckFailure
+	Degraded       []string // dependencies bypassed by an open breaker
+	Errors         []domain.ScreeningError
+
+	// The user's transactions within the repeated amount window, for the
+	// calculator's amount heuristics
+	RecentTransactions []*domain.Transaction
+
+	// Version of the country risk table the calculator applied
+	CountryRiskVersion int
+
+	// Match cache keys of list checks that ran, written after the decision
+	ofacCacheKey string
+	pepCacheKey  string
 
 	// Locks for concurrent access
 	mu sync.Mutex
 }
 
-// Screen performs comprehensive AML screening on a transaction
+// Screen performs comprehensive AML screening on a transaction.
+// An identical payload screened within the result cache TTL returns the
+// cached result; a transaction that was already screened returns the
+// stored result.
 // Target: <200ms p99 latency
 func (e *Engine) Screen(ctx context.Context, tx *domain.Transaction) (*domain.ScreeningResult, error) {
-	startTime := time.Now()
-	screeningID := uuid.New()
-
-	e.log.ScreeningStarted(tx.ID.String(), tx.UserID.String())
+	return e.screen(ctx, tx, screenOptions{})
+}
 
-	// Initialize screening context
-	sctx := &ScreeningContext{
-		Transaction: tx,
-		ScreeningID: screeningID,
-		StartTime:   startTime,
-		RiskFactors: make([]domain.RiskFactor, 0),
+// ScreenRequest screens the transaction in a request, honouring BypassCache
+// to skip the result cache, force a re-screen of a transaction that was
+// already screened and check the sanctions and PEP lists in Redis directly
+// rather than through the in-memory indexes. A request naming a tenant other
+// than the one ctx acts for is refused with domain.ErrForbidden.
+func (e *Engine) ScreenRequest(ctx context.Context, req *domain.ScreeningRequest) (*domain.ScreeningResult, error) {
+	if req.TenantID != "" {
+		if scope, ok := tenant.FromContext(ctx); !ok {
+			ctx = tenant.WithID(ctx, req.TenantID)
+		} else if scope != req.TenantID {
+			return nil, fmt.Errorf("%w: request names tenant %s, caller acts for %s", domain.ErrForbidden, req.TenantID, scope)
+		}
 	}
 
-	// Create timeout context (200ms budget)
-	screenCtx, cancel := context.WithTimeout(ctx, e.cfg.MaxScreeningLatency)
-	defer cancel()
+	return e.screen(ctx, req.Transaction, screenOptions{force: req.BypassCache, bypassIndex: req.BypassCache})
+}
 
-	// Run all checks in parallel using errgroup
-	g, gctx := errgroup.WithContext(screenCtx)
+// Rescreen re-runs screening for the transaction behind a stored result
+// against the current lists, bypassing the stored-result shortcut. The new
+// result is persisted separately and linked to the original.
+func (e *Engine) Rescreen(ctx context.Context, original *domain.ScreeningResult) (*domain.ScreeningResult, error) {
+	if original.Transaction == nil {
+		return nil, ErrOriginalTransactionMissing
+	}
+	if original.TenantID != "" {
+		ctx = tenant.WithID(ctx, original.TenantID)
+	}
 
-	// 1. OFAC Screening (<1ms with cache)
-	g.Go(func() error {
-		return e.runOFACCheck(gctx, sctx)
+	return e.screen(ctx, original.Transaction, screenOptions{
+		force:      true,
+		rescreenOf: &original.ID,
+		flagged:    original.Decision == domain.DecisionBlocked || original.Decision == domain.DecisionSuspicious,
 	})
+}
 
-	// 2. PEP Check (<5ms with cache)
-	g.Go(func() error {
-		return e.runPEPCheck(gctx, sctx)
-	})
+// ScreenName checks a name against the OFAC and PEP lists without a
+// transaction. A list that cannot be checked is an error rather than a
+// clean result, so callers gating onboarding fail closed.
+func (e *Engine) ScreenName(ctx context.Context, req *domain.NameScreeningRequest) (*domain.NameScreeningResponse, error) {
+	ctx, span := e.tracer.Start(ctx, "screening.ScreenName")
+	defer span.End()
 
-	// 3. Get Risk Profile (<50ms)
-	g.Go(func() error {
-		return e.getRiskProfile(gctx, sctx)
-	})
+	resp := &domain.NameScreeningResponse{
+		Name:        req.Name,
+		DateOfBirth: req.DateOfBirth,
+		Country:     req.Country,
+	}
 
-	// 4. Get Velocity Data (<5ms with cache)
+	g, gctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
-		return e.getVelocityData(gctx, sctx)
+		match, err := e.ofacChecker.Check(gctx, req.Name)
+		if err != nil {
+			return fmt.Errorf("ofac check: %w", err)
+		}
+		resp.OFACMatch = match
+		return nil
 	})
-
-	// 5. Pattern Detection (<100ms)
 	g.Go(func() error {
-		return e.detectPatterns(gctx, sctx)
+		match, err := e.pepChecker.CheckWithAssociates(gctx, req.Name)
+		if err != nil {
+			return fmt.Errorf("pep check: %w", err)
+		}
+		resp.PEPMatch = match
+		return nil
 	})
+	if err := g.Wait(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if resp.OFACMatch.Matched {
+		metrics.RecordOFACHit(string(resp.OFACMatch.MatchType))
+	}
+	if resp.PEPMatch.Matched {
+		metrics.RecordPEPHit(string(resp.PEPMatch.MatchType))
+		if req.Country != "" && resp.PEPMatch.PEPCountry != "" {
+			same := strings.EqualFold(req.Country, resp.PEPMatch.PEPCountry)
+			resp.PEPCountryMatch = &same
+		}
+	}
+	resp.Matched = resp.OFACMatch.Matched || resp.PEPMatch.Matched
+	resp.ScreenedAt = e.clock.Now()
+
+	span.SetAttributes(
+		attribute.Bool("ofac_matched", resp.OFACMatch.Matched),
+		attribute.Bool("pep_matched", resp.PEPMatch.Matched),
+	)
+	return resp, nil
+}
+
+// screenOptions controls a single screening run
+type screenOptions struct {
+	force       bool       // skip the result cache and stored-result lookup
+	bypassIndex bool       // check the lists in Redis, not the in-memory indexes
+	rescreenOf  *uuid.UUID // original result when re-screening
+	flagged     bool       // the original result already counted against the counterparty
+}
+
+func (e *Engine) screen(ctx context.Context, tx *domain.Transaction, opts screenOptions) (*domain.ScreeningResult, error) {
+	ctx, span := e.tracer.Start(ctx, "screening.Screen", trace.WithAttributes(
+		attribute.String("transaction_id", tx.ID.String()),
+		attribute.String("user_id", tx.UserID.String()),
+		attribute.Bool("force", opts.force),
+		attribute.Bool("bypass_index", opts.bypassIndex),
+	))
+	defer span.End()
+	ctx = tracing.WithLogContext(ctx)
+	log := e.log.WithContext(ctx)
+
+	// Every lookup and write below is scoped to the tenant; callers naming
+	// none screen for the default tenant
+	tenantID := tenant.OrDefault(ctx)
+	ctx = tenant.WithID(ctx, tenantID)
+	span.SetAttributes(attribute.String("tenant_id", tenantID))
+
+	cacheKey := e.resultCacheKey(tenantID, tx)
+
+	if !opts.force {
+		if cached := e.getCachedResult(ctx, cacheKey); cached != nil {
+			log.Debug("returning cached screening result",
+				logger.StringField("transaction_id", tx.ID.String()),
+				logger.StringField("screening_id", cached.ID.String()),
+			)
+			cached.CacheHit = true
+			span.SetAttributes(attribute.Bool("cache_hit", true))
+			return cached, nil
+		}
+
+		if previous := e.findPreviousResult(ctx, tx.ID); previous != nil {
+			if previous.Decision == domain.DecisionPending {
+				// A held decision is never replayed; redelivery retries the checks
+				log.Info("re-screening transaction held as pending",
+					logger.StringField("transaction_id", tx.ID.String()),
+					logger.StringField("screening_id", previous.ID.String()),
+				)
+				return e.screen(ctx, tx, screenOptions{force: true, rescreenOf: &previous.ID})
+			}
+			log.Info("returning stored screening result for redelivered transaction",
+				logger.StringField("transaction_id", tx.ID.String()),
+				logger.StringField("screening_id", previous.ID.String()),
+			)
+			previous.IdempotentReplay = true
+			span.SetAttributes(attribute.Bool("idempotent_replay", true))
+			return previous, nil
+		}
+	}
+
+	startTime := e.clock.Now()
+	screeningID := uuid.New()
+
+	span.SetAttributes(attribute.Bool("cache_hit", false))
+	log.ScreeningStarted(tx.ID.String(), tx.UserID.String())
+
+	// Initialize screening context
+	sctx := &ScreeningContext{
+		Transaction:    tx,
+		ScreeningID:    screeningID,
+		StartTime:      startTime,
+		BypassIndex:    opts.bypassIndex,
+		TenantID:       tenantID,
+		TenantSettings: e.tenants.Get(tenantID),
+		RiskFactors:    make([]domain.RiskFactor, 0),
+	}
+
+	// Create timeout context (200ms budget, or the caller's deadline if that
+	// is sooner); each check also gets its own timeout within it
+	screenCtx, cancel := context.WithTimeout(ctx, e.cfg.MaxScreeningLatency)
+	defer cancel()
+
+	// Run all checks in parallel using errgroup
+	g, gctx := errgroup.WithContext(screenCtx)
+
+	// OFAC (<1ms with cache), PEP (<5ms with cache), risk profile (<50ms),
+	// velocity (<5ms with cache), patterns (<100ms), device and IP
+	// reputation, IP geolocation, device sharing, counterparty reputation
+	// and the internal account denylist, as enabled
+	for _, c := range e.checks {
+		g.Go(e.timed(gctx, c.name, func(ctx context.Context) error {
+			return c.run(ctx, sctx)
+		}))
+	}
 
 	// Wait for all checks to complete
 	if err := g.Wait(); err != nil {
 		// Log but continue with available results
-		e.log.Warn("some screening checks failed", logger.ErrorField(err))
+		log.Warn("some screening checks failed", logger.ErrorField(err))
 	}
 
-	// 6. Calculate risk score and make decision
+	// Calculate risk score and make decision
 	result := e.calculateResult(sctx)
+	result.Transaction = tx
+	result.RescreenOfID = opts.rescreenOf
+	e.stampListVersions(result)
+
+	// Persist result for audit and idempotent replays. The decision has been
+	// made, so this outlives a caller that gives up at its deadline.
+	persistCtx := context.WithoutCancel(ctx)
+	e.saveResult(persistCtx, result)
+	e.cacheResult(persistCtx, cacheKey, result)
+	e.cacheMatches(persistCtx, sctx)
+	e.auditDecision(persistCtx, result)
+	if !opts.flagged {
+		e.recordCounterparty(persistCtx, result)
+	}
+	if e.notifier != nil {
+		e.notifier.Notify(persistCtx, result)
+	}
+
+	if result.HasBlockingFailure() {
+		e.raiseCheckFailureAlert(persistCtx, result)
+	}
 
 	// Record latency metrics
-	durationMs := time.Since(startTime).Milliseconds()
-	e.recordLatency(durationMs)
+	duration := e.clock.Since(startTime)
+	durationMs := duration.Milliseconds()
+	e.recordLatency(tenantID, result.Decision, duration)
 
 	// Log if we exceeded latency budget
 	if durationMs > int64(e.cfg.MaxScreeningLatency.Milliseconds()) {
-		e.log.LatencyWarning("full_screening", durationMs, int64(e.cfg.MaxScreeningLatency.Milliseconds()))
+		log.LatencyWarning("full_screening", durationMs, int64(e.cfg.MaxScreeningLatency.Milliseconds()))
 	}
 
-	e.log.ScreeningCompleted(
+	span.SetAttributes(
+		attribute.String("decision", string(result.Decision)),
+		attribute.Int("score", result.RiskScore),
+		attribute.String("risk_level", string(result.RiskLevel)),
+	)
+
+	log.ScreeningCompleted(
 		tx.ID.String(),
 		string(result.Decision),
 		result.RiskScore,
@@ -164,9 +559,138 @@ func (e *Engine) Screen(ctx context.Context, tx *domain.Transaction) (*domain.Sc
 	return result, nil
 }
 
+// stampListVersions records which sanctions list versions the result was screened against
+func (e *Engine) stampListVersions(result *domain.ScreeningResult) {
+	if t := e.ofacChecker.ListUpdatedAt(); !t.IsZero() {
+		result.OFACListUpdatedAt = &t
+	}
+	if t := e.pepChecker.ListUpdatedAt(); !t.IsZero() {
+		result.PEPListUpdatedAt = &t
+	}
+	ofac, pep := e.ofacChecker.ListVersion(), e.pepChecker.ListVersion()
+	result.OFACListVersion, result.OFACListHash = ofac.Version, ofac.Hash
+	result.PEPListVersion, result.PEPListHash = pep.Version, pep.Hash
+}
+
+// findPreviousResult returns the stored result for a transaction, if any
+func (e *Engine) findPreviousResult(ctx context.Context, transactionID uuid.UUID) *domain.ScreeningResult {
+	if e.resultRepo == nil {
+		return nil
+	}
+
+	result, err := e.resultRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			e.log.Warn("failed to look up previous screening result", logger.ErrorField(err))
+		}
+		return nil
+	}
+
+	return result
+}
+
+// resultCacheKey keys the result cache on the tenant, the transaction's
+// material fields and the sanctions list versions, so a list refresh never
+// serves a result screened against the previous list and no tenant is
+// served another's result
+func (e *Engine) resultCacheKey(tenantID string, tx *domain.Transaction) string {
+	return fmt.Sprintf("%s:%s:%d:%d",
+		tenantID,
+		tx.ContentHash(),
+		e.ofacChecker.ListUpdatedAt().UnixNano(),
+		e.pepChecker.ListUpdatedAt().UnixNano(),
+	)
+}
+
+// getCachedResult returns the cached result for a content key, if any.
+// Cache errors are treated as a miss.
+func (e *Engine) getCachedResult(ctx context.Context, key string) *domain.ScreeningResult {
+	if e.resultCache == nil || e.cfg.ResultCacheTTL <= 0 {
+		return nil
+	}
+
+	result, err := e.resultCache.Get(ctx, key)
+	if err != nil {
+		e.log.Warn("failed to read screening result cache", logger.ErrorField(err))
+		return nil
+	}
+
+	return result
+}
+
+// cacheResult caches a completed result. Results from incomplete or
+// degraded screenings are not cached so that a retry runs the checks again.
+func (e *Engine) cacheResult(ctx context.Context, key string, result *domain.ScreeningResult) {
+	if e.resultCache == nil || e.cfg.ResultCacheTTL <= 0 {
+		return
+	}
+	if len(result.ChecksFailed) > 0 || result.IsDegraded() || len(result.Errors) > 0 {
+		return
+	}
+
+	if err := e.resultCache.Set(ctx, key, result, e.cfg.ResultCacheTTL); err != nil {
+		e.log.Warn("failed to cache screening result",
+			logger.StringField("screening_id", result.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// saveResult persists a screening result; failures are logged and reported
+// in the result's Errors rather than returned, so that a storage outage
+// never blocks a screening decision
+func (e *Engine) saveResult(ctx context.Context, result *domain.ScreeningResult) {
+	if e.resultRepo == nil {
+		return
+	}
+
+	if err := e.resultRepo.Save(ctx, result); err != nil {
+		e.log.Error("failed to persist screening result",
+			logger.StringField("screening_id", result.ID.String()),
+			logger.ErrorField(err),
+		)
+		result.Errors = append(result.Errors, screeningError("", fmt.Errorf("%w: %w", ErrResultNotPersisted, err)))
+	}
+}
+
+// auditDecision records a screening decision in the audit log; failures are
+// logged and reported in the result's Errors so that the audit log never
+// blocks a decision
+func (e *Engine) auditDecision(ctx context.Context, result *domain.ScreeningResult) {
+	if e.auditor == nil {
+		return
+	}
+
+	err := e.auditor.Record(ctx, audit.Entry{
+		ActorID:    domain.SystemActorID,
+		Action:     audit.ActionScreeningDecision,
+		EntityType: audit.EntityScreeningResult,
+		EntityID:   result.ID.String(),
+		After: map[string]interface{}{
+			"transaction_id": result.TransactionID,
+			"user_id":        result.UserID,
+			"decision":       result.Decision,
+			"risk_score":     result.RiskScore,
+			"risk_level":     result.RiskLevel,
+			"reason_codes":   result.ReasonCodes,
+			"rescreen_of_id": result.RescreenOfID,
+		},
+	})
+	if err != nil {
+		e.log.Error("failed to audit screening decision",
+			logger.StringField("screening_id", result.ID.String()),
+			logger.ErrorField(err),
+		)
+		result.Errors = append(result.Errors, screeningError("", fmt.Errorf("%w: %w", ErrDecisionNotAudited, err)))
+	}
+}
+
 // runOFACCheck performs OFAC sanctions check
 func (e *Engine) runOFACCheck(ctx context.Context, sctx *ScreeningContext) error {
-	start := time.Now()
+	start := e.clock.Now()
+
+	// Correspondent banks are sanctioned whatever the parties' names
+	e.checkBankBICs(ctx, sctx)
 
 	// Check counterparty name against OFAC list
 	counterpartyName := sctx.Transaction.GetCounterpartyName()
@@ -174,23 +698,44 @@ func (e *Engine) runOFACCheck(ctx context.Context, sctx *ScreeningContext) error
 		return nil
 	}
 
-	result, err := e.ofacChecker.Check(ctx, counterpartyName)
-	if err != nil {
-		e.log.Warn("ofac check failed", logger.ErrorField(err))
-		return nil // Don't fail screening if OFAC check fails
+	key := e.matchCacheKey(sctx, e.ofacChecker.normalizer, counterpartyName, e.ofacChecker.ListUpdatedAt())
+	result := e.cachedOFACMatch(ctx, key)
+	if result == nil {
+		check := e.ofacChecker.Check
+		if sctx.BypassIndex {
+			check = e.ofacChecker.CheckSource
+		}
+		var err error
+		if result, err = check(ctx, counterpartyName); err != nil {
+			e.recordFailure(ctx, sctx, domain.CheckOFAC, err)
+			return nil
+		}
 	}
 
-	durationMs := time.Since(start).Milliseconds()
+	durationMs := e.clock.Since(start).Milliseconds()
 	result.CheckDurationMs = durationMs
+	if result.Degraded {
+		e.markDegraded(ctx, sctx, domain.DependencyOFACCache)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool("matched", result.Matched),
+		attribute.String("match_type", string(result.MatchType)),
+		attribute.Float64("score", result.MatchScore),
+		attribute.Bool("cache_hit", result.CacheHit),
+	)
 
 	sctx.mu.Lock()
 	sctx.OFACResult = result
+	if !result.CacheHit && !result.Degraded {
+		sctx.ofacCacheKey = key
+	}
 	if result.Matched {
+		metrics.RecordOFACHit(string(result.MatchType))
 		sctx.RiskFactors = append(sctx.RiskFactors, domain.RiskFactor{
 			Factor:      "OFAC_MATCH",
-			Weight:      50, // Major risk factor
+			Weight:      e.amountBands.scale(50, sctx.Transaction), // Major risk factor
 			Description: "Counterparty matches OFAC sanctions list",
-			Details:     result.SDNName,
+			Details:     e.amountBands.details(result.SDNName, sctx.Transaction),
 		})
 	}
 	sctx.mu.Unlock()
@@ -205,32 +750,110 @@ func (e *Engine) runOFACCheck(ctx context.Context, sctx *ScreeningContext) error
 	return nil
 }
 
+// checkBankBICs screens the sender and receiver bank BICs against the
+// banks designated on the OFAC list, so a wire routed through a sanctioned
+// bank is caught even when both parties are clean
+func (e *Engine) checkBankBICs(ctx context.Context, sctx *ScreeningContext) {
+	tx := sctx.Transaction
+	banks := []struct{ party, bic string }{
+		{"sender", tx.SenderBankBIC},
+		{"receiver", tx.ReceiverBankBIC},
+	}
+
+	var factors []domain.RiskFactor
+	for _, bank := range banks {
+		if bank.bic == "" {
+			continue
+		}
+		match, err := e.ofacChecker.CheckBIC(bank.bic)
+		if err != nil {
+			e.recordFailure(ctx, sctx, domain.CheckOFAC, fmt.Errorf("screen %s bank bic: %w", bank.party, err))
+			return
+		}
+		if !match.Matched {
+			continue
+		}
+		metrics.RecordOFACHit(string(match.MatchType))
+		factors = append(factors, domain.RiskFactor{
+			Factor:      "SANCTIONED_BANK",
+			Weight:      e.amountBands.scale(50, tx),
+			Description: "Sender or receiver bank matches OFAC sanctions list",
+			Details:     e.amountBands.details(fmt.Sprintf("%s bank %s: %s", bank.party, domain.NormalizeBIC(bank.bic), match.SDNName), tx),
+		})
+	}
+	if len(factors) == 0 {
+		return
+	}
+
+	sctx.mu.Lock()
+	sctx.BankHit = true
+	sctx.RiskFactors = append(sctx.RiskFactors, factors...)
+	sctx.mu.Unlock()
+
+	e.log.Warn("sanctioned bank in transaction",
+		logger.StringField("transaction_id", tx.ID.String()),
+		logger.IntField("banks", len(factors)),
+	)
+}
+
 // runPEPCheck performs PEP database check
 func (e *Engine) runPEPCheck(ctx context.Context, sctx *ScreeningContext) error {
-	start := time.Now()
+	start := e.clock.Now()
 
 	counterpartyName := sctx.Transaction.GetCounterpartyName()
 	if counterpartyName == "" {
 		return nil
 	}
 
-	result, err := e.pepChecker.Check(ctx, counterpartyName)
-	if err != nil {
-		e.log.Warn("pep check failed", logger.ErrorField(err))
-		return nil
+	key := e.matchCacheKey(sctx, e.pepChecker.normalizer, counterpartyName, e.pepChecker.ListUpdatedAt())
+	result := e.cachedPEPMatch(ctx, key)
+	if result == nil {
+		check := e.pepChecker.CheckWithAssociates
+		if sctx.BypassIndex {
+			check = e.pepChecker.CheckSourceWithAssociates
+		}
+		var err error
+		if result, err = check(ctx, counterpartyName); err != nil {
+			e.recordFailure(ctx, sctx, domain.CheckPEP, err)
+			return nil
+		}
 	}
 
-	durationMs := time.Since(start).Milliseconds()
+	durationMs := e.clock.Since(start).Milliseconds()
 	result.CheckDurationMs = durationMs
+	if result.Degraded {
+		e.markDegraded(ctx, sctx, domain.DependencyPEPCache)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool("matched", result.Matched),
+		attribute.String("match_type", string(result.MatchType)),
+		attribute.Float64("score", result.MatchScore),
+		attribute.Bool("associate", result.Associate),
+		attribute.Bool("cache_hit", result.CacheHit),
+	)
 
 	sctx.mu.Lock()
 	sctx.PEPResult = result
-	if result.Matched {
+	if !result.CacheHit && !result.Degraded {
+		sctx.pepCacheKey = key
+	}
+	switch {
+	case result.Associate:
+		metrics.RecordPEPHit(string(result.MatchType))
+		sctx.RiskFactors = append(sctx.RiskFactors, domain.RiskFactor{
+			Factor:      "PEP_ASSOCIATE",
+			Weight:      e.amountBands.scale(e.pepWeight(result, 20, e.clock.Now()), sctx.Transaction),
+			Description: "Counterparty is a relative or close associate of a Politically Exposed Person",
+			Details: e.amountBands.details(fmt.Sprintf("%s, associate of %s (%s)",
+				result.AssociateName, result.PEPName, result.PEPPosition), sctx.Transaction),
+		})
+	case result.Matched:
+		metrics.RecordPEPHit(string(result.MatchType))
 		sctx.RiskFactors = append(sctx.RiskFactors, domain.RiskFactor{
 			Factor:      "PEP_MATCH",
-			Weight:      30,
+			Weight:      e.amountBands.scale(e.pepWeight(result, 30, e.clock.Now()), sctx.Transaction),
 			Description: "Counterparty is a Politically Exposed Person",
-			Details:     result.PEPPosition,
+			Details:     e.amountBands.details(pepDetails(result), sctx.Transaction),
 		})
 	}
 	sctx.mu.Unlock()
@@ -240,14 +863,53 @@ func (e *Engine) runPEPCheck(ctx context.Context, sctx *ScreeningContext) error
 	return nil
 }
 
+// pepWeight scales a PEP factor's base weight by the match's risk category.
+// Former PEPs, and associates of one, keep the base weight during the
+// cooling-off period after leaving office and then decline in proportion to
+// the time since, so long-retired officials weigh substantially less.
+func (e *Engine) pepWeight(match *domain.PEPMatch, base int, now time.Time) int {
+	former := match.RiskCategory == "FORMER_PEP" ||
+		(match.Associate && match.PEPEndDate != nil && match.PEPEndDate.Before(now))
+	if !former || match.PEPEndDate == nil || e.cfg.PEPCoolingOff <= 0 {
+		return base
+	}
+
+	since := now.Sub(*match.PEPEndDate)
+	if since <= e.cfg.PEPCoolingOff {
+		return base
+	}
+	weight := int(float64(base) * float64(e.cfg.PEPCoolingOff) / float64(since))
+	return min(base, max(weight, e.cfg.FormerPEPMinWeight))
+}
+
+// pepDetails describes a PEP match's position, noting when a former PEP left office
+func pepDetails(match *domain.PEPMatch) string {
+	if match.RiskCategory == "FORMER_PEP" && match.PEPEndDate != nil {
+		return fmt.Sprintf("%s (left office %s)", match.PEPPosition, match.PEPEndDate.Format(time.DateOnly))
+	}
+	return match.PEPPosition
+}
+
 // getRiskProfile fetches user risk profile
 func (e *Engine) getRiskProfile(ctx context.Context, sctx *ScreeningContext) error {
 	profile, err := e.riskProfileRepo.GetByUserID(ctx, sctx.Transaction.UserID)
 	if err != nil {
-		e.log.Warn("failed to get risk profile", logger.ErrorField(err))
+		// New users have no profile yet; only lookup errors count as failures
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+		case breaker.IsOpen(err):
+			e.markDegraded(ctx, sctx, domain.DependencyRiskProfiles)
+		default:
+			e.recordFailure(ctx, sctx, domain.CheckRiskProfile, err)
+		}
 		return nil
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("score", profile.RiskScore),
+		attribute.Bool("watchlist", profile.OnWatchlist),
+	)
+
 	sctx.mu.Lock()
 	sctx.RiskProfile = profile
 
@@ -283,10 +945,19 @@ func (e *Engine) getRiskProfile(ctx context.Context, sctx *ScreeningContext) err
 func (e *Engine) getVelocityData(ctx context.Context, sctx *ScreeningContext) error {
 	velocity, err := e.velocityCache.GetVelocity(ctx, sctx.Transaction.UserID)
 	if err != nil {
-		e.log.Debug("no velocity data available", logger.ErrorField(err))
+		if breaker.IsOpen(err) {
+			e.markDegraded(ctx, sctx, domain.DependencyVelocityCache)
+			return nil
+		}
+		e.recordFailure(ctx, sctx, domain.CheckVelocity, err)
 		return nil
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("tx_count_day", velocity.TxCountDay),
+		attribute.Float64("amount_day", velocity.AmountDay.Float64()),
+	)
+
 	sctx.mu.Lock()
 	sctx.VelocityData = velocity
 	sctx.mu.Unlock()
@@ -294,14 +965,22 @@ func (e *Engine) getVelocityData(ctx context.Context, sctx *ScreeningContext) er
 	return nil
 }
 
-// detectPatterns runs pattern detection
+// detectPatterns runs pattern detection and loads the history the
+// calculator's repeated amount heuristic reads
 func (e *Engine) detectPatterns(ctx context.Context, sctx *ScreeningContext) error {
+	e.loadRecentTransactions(ctx, sctx)
+
+	// Detectors that failed are reported; matches from the others still count
 	patterns, err := e.patternEngine.DetectPatterns(ctx, sctx.Transaction.UserID, sctx.Transaction)
 	if err != nil {
-		e.log.Warn("pattern detection failed", logger.ErrorField(err))
-		return nil
+		e.recordFailure(ctx, sctx, domain.CheckPatterns, err)
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool("matched", len(patterns) > 0),
+		attribute.Int("pattern_count", len(patterns)),
+	)
+
 	sctx.mu.Lock()
 	sctx.PatternMatches = patterns
 	for _, p := range patterns {
@@ -312,64 +991,471 @@ func (e *Engine) detectPatterns(ctx context.Context, sctx *ScreeningContext) err
 			Description: p.Description,
 		})
 		e.log.PatternDetected(sctx.Transaction.UserID.String(), string(p.PatternType), p.Confidence)
+		metrics.RecordPatternDetection(string(p.PatternType))
+	}
+	sctx.mu.Unlock()
+
+	return nil
+}
+
+// loadRecentTransactions reads the user's transactions within the repeated
+// amount window. Transactions without a counterparty account cannot repeat
+// one, so nothing is read for them; a failed read leaves repeated amounts
+// unscored rather than failing the check.
+func (e *Engine) loadRecentTransactions(ctx context.Context, sctx *ScreeningContext) {
+	window := e.riskCalculator.RepeatedAmountWindow()
+	if e.history == nil || window <= 0 {
+		return
+	}
+	tx := sctx.Transaction
+	if _, ok := tx.Counterparty(); !ok {
+		return
+	}
+
+	at := tx.InitiatedAt
+	if at.IsZero() {
+		at = sctx.StartTime
+	}
+	recent, err := e.history.GetByUser(ctx, tx.UserID, at.Add(-window))
+	if err != nil {
+		e.log.Warn("failed to load recent transactions for repeated amounts",
+			logger.StringField("transaction_id", tx.ID.String()),
+			logger.ErrorField(err),
+		)
+		return
+	}
+
+	sctx.mu.Lock()
+	sctx.RecentTransactions = recent
+	sctx.mu.Unlock()
+}
+
+// runReputationCheck scores the transaction's IP address, device and location
+func (e *Engine) runReputationCheck(ctx context.Context, sctx *ScreeningContext) error {
+	factors, err := e.reputation.Check(ctx, sctx.Transaction)
+	if err != nil {
+		e.recordFailure(ctx, sctx, domain.CheckReputation, err)
+		return nil
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool("matched", len(factors) > 0),
+		attribute.Int("factor_count", len(factors)),
+	)
+
+	sctx.mu.Lock()
+	sctx.RiskFactors = append(sctx.RiskFactors, factors...)
+	sctx.mu.Unlock()
+
+	return nil
+}
+
+// runGeoIPCheck locates the transaction's IP address and checks for
+// impossible travel since the user's previous transaction. Factors found
+// before a failure still count.
+func (e *Engine) runGeoIPCheck(ctx context.Context, sctx *ScreeningContext) error {
+	country, factors, err := e.geoIP.Check(ctx, sctx.Transaction)
+	if err != nil {
+		e.recordFailure(ctx, sctx, domain.CheckGeoIP, err)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("ip_country", country),
+		attribute.Int("factor_count", len(factors)),
+	)
+
+	sctx.mu.Lock()
+	sctx.IPCountry = country
+	sctx.RiskFactors = append(sctx.RiskFactors, factors...)
+	sctx.mu.Unlock()
+
+	return nil
+}
+
+// runSharedDeviceCheck flags a device or IP address the transaction shares
+// with many other users
+func (e *Engine) runSharedDeviceCheck(ctx context.Context, sctx *ScreeningContext) error {
+	factors, err := e.sharedDevices.Check(ctx, sctx.Transaction)
+	if err != nil {
+		e.recordFailure(ctx, sctx, domain.CheckSharedDevice, err)
+		return nil
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("matched", len(factors) > 0))
+
+	sctx.mu.Lock()
+	sctx.RiskFactors = append(sctx.RiskFactors, factors...)
+	sctx.mu.Unlock()
+
+	return nil
+}
+
+// runCounterpartyCheck scores the transaction's external account by its
+// history across all users
+func (e *Engine) runCounterpartyCheck(ctx context.Context, sctx *ScreeningContext) error {
+	factor, err := e.counterparties.Check(ctx, sctx.Transaction, e.clock.Now())
+	if err != nil {
+		e.recordFailure(ctx, sctx, domain.CheckCounterparty, err)
+		return nil
 	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("matched", factor != nil))
+	if factor == nil {
+		return nil
+	}
+
+	sctx.mu.Lock()
+	sctx.RiskFactors = append(sctx.RiskFactors, *factor)
 	sctx.mu.Unlock()
 
 	return nil
 }
 
+// recordCounterparty counts a blocked or suspicious decision against the
+// transaction's counterparty. Failures are logged; the rebuild job restores
+// anything missed from the stored results.
+func (e *Engine) recordCounterparty(ctx context.Context, result *domain.ScreeningResult) {
+	if err := e.counterparties.RecordDecision(ctx, result.Transaction, result.Decision, result.CreatedAt); err != nil {
+		e.log.Error("failed to record counterparty reputation",
+			logger.StringField("screening_id", result.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// runAccountDenylistCheck matches the sender and receiver accounts against
+// the internal denylist, catching known mule accounts whatever name they
+// are used under
+func (e *Engine) runAccountDenylistCheck(ctx context.Context, sctx *ScreeningContext) error {
+	tx := sctx.Transaction
+	parties := make(map[string]string, 2) // normalized account -> party
+	if account := domain.NormalizeAccount(tx.ReceiverAccount); account != "" {
+		parties[account] = "receiver"
+	}
+	if account := domain.NormalizeAccount(tx.SenderAccount); account != "" {
+		parties[account] = "sender"
+	}
+	if len(parties) == 0 {
+		return nil
+	}
+
+	accounts := make([]string, 0, len(parties))
+	for account := range parties {
+		accounts = append(accounts, account)
+	}
+	entries, err := e.accountDenylist.Lookup(ctx, accounts...)
+	if err != nil {
+		e.recordFailure(ctx, sctx, domain.CheckAccountDenylist, err)
+		return nil
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("matched", len(entries) > 0))
+	if len(entries) == 0 {
+		return nil
+	}
+
+	sctx.mu.Lock()
+	sctx.DenylistHit = true
+	for _, entry := range entries {
+		sctx.RiskFactors = append(sctx.RiskFactors, domain.RiskFactor{
+			Factor:      "DENYLISTED_ACCOUNT",
+			Weight:      e.cfg.AccountDenylistWeight,
+			Description: "Sender or receiver account is on the internal account denylist",
+			Details:     fmt.Sprintf("%s account %s: %s", parties[entry.Account], entry.Account, entry.Reason),
+		})
+	}
+	sctx.mu.Unlock()
+
+	e.log.Warn("denylisted account in transaction",
+		logger.StringField("transaction_id", tx.ID.String()),
+		logger.IntField("accounts", len(entries)),
+	)
+	return nil
+}
+
 // calculateResult calculates final risk score and decision
 func (e *Engine) calculateResult(sctx *ScreeningContext) *domain.ScreeningResult {
 	sctx.mu.Lock()
 	defer sctx.mu.Unlock()
 
-	// Calculate base risk score from factors
+	// Calculate base risk score from factors. The calculator adds its own
+	// factors, so the shadow scorer gets the checks' factors as they were.
+	var checkFactors []domain.RiskFactor
+	if e.shadow != nil {
+		checkFactors = slices.Clone(sctx.RiskFactors)
+	}
 	riskScore := e.riskCalculator.Calculate(sctx)
+	thresholds := thresholdsFor(tenantThresholds(e.thresholds, sctx), sctx)
 
 	// Build result
 	result := &domain.ScreeningResult{
-		ID:                  sctx.ScreeningID,
-		TransactionID:       sctx.Transaction.ID,
-		UserID:              sctx.Transaction.UserID,
-		RiskScore:           riskScore,
-		RiskLevel:           domain.CalculateRiskLevel(riskScore),
-		Decision:            domain.CalculateDecision(riskScore),
-		OFACMatch:           sctx.OFACResult,
-		PEPMatch:            sctx.PEPResult,
-		RiskFactors:         sctx.RiskFactors,
-		PatternMatches:      sctx.PatternMatches,
-		ScreeningDurationMs: time.Since(sctx.StartTime).Milliseconds(),
-		CreatedAt:           time.Now(),
-		UpdatedAt:           time.Now(),
-	}
-
-	// Override decision if OFAC match (always block)
-	if sctx.OFACResult != nil && sctx.OFACResult.Matched && sctx.OFACResult.MatchType == domain.MatchTypeExact {
+		ID:                   sctx.ScreeningID,
+		TransactionID:        sctx.Transaction.ID,
+		UserID:               sctx.Transaction.UserID,
+		TenantID:             sctx.TenantID,
+		RiskScore:            riskScore,
+		RiskLevel:            domain.CalculateRiskLevel(riskScore),
+		Decision:             thresholds.Decide(riskScore),
+		OFACMatch:            sctx.OFACResult,
+		PEPMatch:             sctx.PEPResult,
+		RiskFactors:          sctx.RiskFactors,
+		PatternMatches:       sctx.PatternMatches,
+		ChecksFailed:         sctx.ChecksFailed,
+		DegradedDependencies: sctx.Degraded,
+		SkippedChecks:        e.skippedChecks,
+		Errors:               sctx.Errors,
+		AppliedThresholds:    &thresholds,
+		ScreeningDurationMs:  e.clock.Since(sctx.StartTime).Milliseconds(),
+		CreatedAt:            e.clock.Now(),
+		UpdatedAt:            e.clock.Now(),
+	}
+
+	// Exact OFAC matches, sanctioned banks and denylisted accounts are
+	// always blocked
+	if blockedOnSight(sctx) {
 		result.Decision = domain.DecisionBlocked
 		result.RiskScore = 100
 		result.RiskLevel = domain.RiskLevelCritical
 	}
 
+	// A clean score means nothing when a fail-closed check did not run;
+	// hold the decision unless it is already a block
+	if result.HasBlockingFailure() && result.Decision != domain.DecisionBlocked {
+		result.Decision = domain.DecisionPending
+	}
+
+	countryRiskVersion := sctx.CountryRiskVersion
+	result.CountryRiskVersion = &countryRiskVersion
+	result.ReasonCodes = domain.BuildReasonCodes(result)
+
+	if e.shadow != nil {
+		e.shadow.score(sctx, checkFactors, result)
+	}
+
 	return result
 }
 
+// blockedOnSight reports whether the screening found an exact OFAC match, a
+// sanctioned bank or a denylisted account, which block whatever the score
+func blockedOnSight(sctx *ScreeningContext) bool {
+	exactOFAC := sctx.OFACResult != nil && sctx.OFACResult.Matched && sctx.OFACResult.MatchType == domain.MatchTypeExact
+	return exactOFAC || sctx.BankHit || sctx.DenylistHit
+}
+
+// recordFailure notes a check that errored. A check whose deadline passed
+// is marked skipped. Fail-closed checks make the failure blocking, timeouts
+// included; the others are recorded for audit only.
+func (e *Engine) recordFailure(ctx context.Context, sctx *ScreeningContext, check string, err error) {
+	blocking := e.failClosed[check]
+	skipped := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if skipped {
+		metrics.RecordCheckTimeout(check)
+	}
+	err = checkError(check, skipped, err)
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.SetAttributes(attribute.Bool("blocking", blocking), attribute.Bool("skipped", skipped))
+
+	sctx.mu.Lock()
+	sctx.ChecksFailed = append(sctx.ChecksFailed, domain.CheckFailure{
+		Check:    check,
+		Error:    err.Error(),
+		Blocking: blocking,
+		Skipped:  skipped,
+	})
+	sctx.Errors = append(sctx.Errors, screeningError(check, err))
+	sctx.mu.Unlock()
+
+	e.log.Warn("screening check failed",
+		logger.StringField("check", check),
+		logger.StringField("transaction_id", sctx.Transaction.ID.String()),
+		logger.BoolField("blocking", blocking),
+		logger.BoolField("skipped", skipped),
+		logger.ErrorField(err),
+	)
+}
+
+// markDegraded notes a dependency skipped because its circuit breaker is open
+func (e *Engine) markDegraded(ctx context.Context, sctx *ScreeningContext, dependency string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("degraded_dependency", dependency))
+
+	sctx.mu.Lock()
+	sctx.Degraded = append(sctx.Degraded, dependency)
+	sctx.Errors = append(sctx.Errors, screeningError(dependency, ErrDependencyDegraded))
+	sctx.mu.Unlock()
+
+	e.log.Debug("screening in degraded mode",
+		logger.StringField("dependency", dependency),
+		logger.StringField("transaction_id", sctx.Transaction.ID.String()),
+	)
+}
+
+// raiseCheckFailureAlert opens an alert for a decision held because
+// fail-closed checks could not be completed
+func (e *Engine) raiseCheckFailureAlert(ctx context.Context, result *domain.ScreeningResult) {
+	if e.alertRepo == nil {
+		return
+	}
+
+	var checks []string
+	for _, f := range result.ChecksFailed {
+		if f.Blocking {
+			checks = append(checks, f.Check)
+		}
+	}
+
+	now := e.clock.Now()
+	txID := result.TransactionID
+	alert := &domain.AMLAlert{
+		ID:            uuid.New(),
+		AlertNumber:   domain.GenerateAlertNumber(now),
+		TenantID:      result.TenantID,
+		UserID:        result.UserID,
+		TransactionID: &txID,
+		AlertType:     domain.AlertTypeSystemGenerated,
+		Status:        domain.AlertStatusNew,
+		Priority:      domain.RiskLevelHigh,
+		RiskScore:     result.RiskScore,
+		Title:         fmt.Sprintf("Screening held: %s unavailable", strings.Join(checks, ", ")),
+		Description: fmt.Sprintf("Transaction %s was held as %s because critical checks could not be completed: %s",
+			result.TransactionID, result.Decision, strings.Join(checks, ", ")),
+		RelatedTxIDs:  []uuid.UUID{txID},
+		Confidence:    1.0,
+		DetectionRule: "SCREENING_CHECK_FAILED",
+		DetectedAt:    now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := e.alertRepo.Create(ctx, alert); err != nil {
+		e.log.Error("failed to create check failure alert",
+			logger.StringField("screening_id", result.ID.String()),
+			logger.ErrorField(err),
+		)
+		return
+	}
+
+	e.log.AlertCreated(alert.ID.String(), string(alert.AlertType), alert.UserID.String(), alert.RiskScore)
+}
+
+// buildThresholds converts configured decision thresholds by risk tier,
+// falling back to the built-in defaults for the default tier
+func buildThresholds(cfg map[string]config.DecisionThresholdsConfig) map[string]domain.DecisionThresholds {
+	thresholds := map[string]domain.DecisionThresholds{
+		domain.ThresholdTierDefault: domain.DefaultDecisionThresholds(),
+	}
+	for tier, t := range cfg {
+		thresholds[tier] = domain.DecisionThresholds{
+			Tier:       tier,
+			Suspicious: t.Suspicious,
+			Blocked:    t.Blocked,
+		}
+	}
+	return thresholds
+}
+
+// tenantThresholds returns the decision thresholds by risk tier for the
+// screening's tenant: those it overrides over those configured
+func tenantThresholds(configured map[string]domain.DecisionThresholds, sctx *ScreeningContext) map[string]domain.DecisionThresholds {
+	if sctx.TenantSettings == nil || len(sctx.TenantSettings.DecisionThresholds) == 0 {
+		return configured
+	}
+	thresholds := maps.Clone(configured)
+	maps.Copy(thresholds, sctx.TenantSettings.DecisionThresholds)
+	return thresholds
+}
+
+// thresholdsFor selects the thresholds for a screening's risk tier. Users
+// requiring enhanced due diligence, and counterparties in a country risk
+// tier that requires it, get the stricter "edd" tier. It must run after
+// the risk calculator has added its factors.
+func thresholdsFor(thresholds map[string]domain.DecisionThresholds, sctx *ScreeningContext) domain.DecisionThresholds {
+	if requiresEnhancedDueDiligence(sctx) {
+		if t, ok := thresholds[domain.ThresholdTierEDD]; ok {
+			return t
+		}
+	}
+	return thresholds[domain.ThresholdTierDefault]
+}
+
+// requiresEnhancedDueDiligence reports whether the user or a risk factor
+// calls for enhanced due diligence
+func requiresEnhancedDueDiligence(sctx *ScreeningContext) bool {
+	if sctx.RiskProfile != nil && sctx.RiskProfile.RequiresEnhancedDueDiligence() {
+		return true
+	}
+	for _, f := range sctx.RiskFactors {
+		if f.EDDRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// timed wraps a check in a child span with the check's own timeout, if
+// configured, and observes its latency whatever the outcome
+func (e *Engine) timed(ctx context.Context, check string, fn func(context.Context) error) func() error {
+	return func() error {
+		if timeout := e.cfg.CheckTimeouts[check]; timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		ctx, span := e.tracer.Start(ctx, "screening.check."+check,
+			trace.WithAttributes(attribute.String("check", check)))
+		start := e.clock.Now()
+		defer func() {
+			metrics.ObserveCheck(check, e.clock.Since(start))
+			span.End()
+		}()
+		return fn(ctx)
+	}
+}
+
 // recordLatency records screening latency for metrics
-func (e *Engine) recordLatency(durationMs int64) {
+func (e *Engine) recordLatency(tenantID string, decision domain.ScreeningDecision, d time.Duration) {
+	metrics.ObserveScreening(tenantID, string(decision), d)
+
 	e.latencyMu.Lock()
 	defer e.latencyMu.Unlock()
 
 	e.screeningCount++
 	// Exponential moving average
-	e.avgLatencyMs = e.avgLatencyMs*0.9 + float64(durationMs)*0.1
+	e.avgLatencyMs = e.avgLatencyMs*0.9 + float64(d.Milliseconds())*0.1
+	e.latencySumMs += float64(d.Milliseconds())
+	e.latencies.add(d)
+}
+
+// GetLatencyPercentile returns the p-th percentile (0-100) screening
+// latency in milliseconds over the most recent screenings, e.g. 99 for p99.
+// It returns 0 before any screening has completed.
+func (e *Engine) GetLatencyPercentile(p float64) float64 {
+	return e.latencies.percentile(p)
 }
 
-// GetAverageLatency returns the average screening latency
+// GetAverageLatency returns the exponential moving average of screening
+// latency in milliseconds, weighted towards recent screenings
 func (e *Engine) GetAverageLatency() float64 {
 	e.latencyMu.RLock()
 	defer e.latencyMu.RUnlock()
 	return e.avgLatencyMs
 }
 
+// GetMeanLatency returns the mean screening latency in milliseconds over
+// every screening since start. Use GetLatencyPercentile or the
+// aml_screening_duration_seconds histogram for percentiles.
+func (e *Engine) GetMeanLatency() float64 {
+	e.latencyMu.RLock()
+	defer e.latencyMu.RUnlock()
+	if e.screeningCount == 0 {
+		return 0
+	}
+	return e.latencySumMs / float64(e.screeningCount)
+}
+
 // GetScreeningCount returns total screenings performed
 func (e *Engine) GetScreeningCount() int64 {
 	e.latencyMu.RLock()
@@ -0,0 +1,76 @@
+package screening
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// Error is a typed screening error. Its code is reported in the result's
+// Errors so consumers can see what degraded without parsing log lines.
+type Error struct {
+	Code string
+	msg  string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Typed errors for checks and steps that did not complete
+var (
+	ErrOFACUnavailable        = &Error{Code: "OFAC_UNAVAILABLE", msg: "ofac check unavailable"}
+	ErrOFACTimeout            = &Error{Code: "OFAC_TIMEOUT", msg: "ofac check timed out"}
+	ErrPEPUnavailable         = &Error{Code: "PEP_UNAVAILABLE", msg: "pep check unavailable"}
+	ErrPEPTimeout             = &Error{Code: "PEP_TIMEOUT", msg: "pep check timed out"}
+	ErrRiskProfileUnavailable = &Error{Code: "RISK_PROFILE_UNAVAILABLE", msg: "risk profile unavailable"}
+	ErrRiskProfileTimeout     = &Error{Code: "RISK_PROFILE_TIMEOUT", msg: "risk profile lookup timed out"}
+	ErrVelocityUnavailable    = &Error{Code: "VELOCITY_UNAVAILABLE", msg: "velocity data unavailable"}
+	ErrVelocityTimeout        = &Error{Code: "VELOCITY_TIMEOUT", msg: "velocity lookup timed out"}
+	ErrPatternUnavailable     = &Error{Code: "PATTERN_UNAVAILABLE", msg: "pattern detection unavailable"}
+	ErrPatternTimeout         = &Error{Code: "PATTERN_TIMEOUT", msg: "pattern detection timed out"}
+	ErrReputationUnavailable  = &Error{Code: "REPUTATION_UNAVAILABLE", msg: "reputation check unavailable"}
+	ErrReputationTimeout      = &Error{Code: "REPUTATION_TIMEOUT", msg: "reputation check timed out"}
+
+	// ErrDependencyDegraded is reported when a dependency was bypassed
+	// because its circuit breaker is open
+	ErrDependencyDegraded = &Error{Code: "DEPENDENCY_DEGRADED", msg: "dependency bypassed by open circuit breaker"}
+
+	ErrResultNotPersisted = &Error{Code: "RESULT_NOT_PERSISTED", msg: "screening result not persisted"}
+	ErrDecisionNotAudited = &Error{Code: "DECISION_NOT_AUDITED", msg: "screening decision not audited"}
+)
+
+// checkErrors maps each check to its unavailable and timeout errors
+var checkErrors = map[string][2]*Error{
+	domain.CheckOFAC:        {ErrOFACUnavailable, ErrOFACTimeout},
+	domain.CheckPEP:         {ErrPEPUnavailable, ErrPEPTimeout},
+	domain.CheckRiskProfile: {ErrRiskProfileUnavailable, ErrRiskProfileTimeout},
+	domain.CheckVelocity:    {ErrVelocityUnavailable, ErrVelocityTimeout},
+	domain.CheckPatterns:    {ErrPatternUnavailable, ErrPatternTimeout},
+	domain.CheckReputation:  {ErrReputationUnavailable, ErrReputationTimeout},
+}
+
+// checkError wraps the error of a check in its typed unavailable or timeout error
+func checkError(check string, timedOut bool, err error) error {
+	kinds, ok := checkErrors[check]
+	if !ok {
+		return err
+	}
+	kind := kinds[0]
+	if timedOut {
+		kind = kinds[1]
+	}
+	return fmt.Errorf("%w: %w", kind, err)
+}
+
+// screeningError records a typed error against the check or dependency it
+// affected. Untyped errors are reported as UNKNOWN.
+func screeningError(check string, err error) domain.ScreeningError {
+	code := "UNKNOWN"
+	var typed *Error
+	if errors.As(err, &typed) {
+		code = typed.Code
+	}
+	return domain.ScreeningError{Code: code, Check: check, Message: err.Error()}
+}
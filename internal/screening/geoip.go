@@ -0,0 +1,277 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oschwald/maxminddb-golang"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// IPLocation is where an IP address is registered
+type IPLocation struct {
+	Country        string // ISO 3166 alpha-2 code, empty if unknown
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool // city-level databases only
+}
+
+// GeoIPProvider resolves IP addresses to locations. Implementations may be
+// backed by a local database or a lookup service; an address the provider
+// knows nothing about yields nil.
+type GeoIPProvider interface {
+	Locate(ctx context.Context, ip string) (*IPLocation, error)
+}
+
+// LocationHistory remembers where each user last transacted.
+// SwapLastLocation stores loc for ttl and returns the location it
+// replaced, or nil if there was none.
+type LocationHistory interface {
+	SwapLastLocation(ctx context.Context, userID uuid.UUID, loc *domain.TransactionLocation, ttl time.Duration) (*domain.TransactionLocation, error)
+}
+
+// GeoIPChecker locates a transaction's IP address and flags high-risk IP
+// countries and impossible travel since the user's previous transaction
+type GeoIPChecker struct {
+	provider    GeoIPProvider
+	history     LocationHistory
+	countryRisk *CountryRisk
+	cfg         *config.GeoIPConfig
+	log         *logger.Logger
+}
+
+// NewGeoIPChecker creates a new IP geolocation checker. IP countries are
+// scored against the table countryRisk holds.
+func NewGeoIPChecker(provider GeoIPProvider, history LocationHistory, countryRisk *CountryRisk, cfg *config.GeoIPConfig, log *logger.Logger) *GeoIPChecker {
+	return &GeoIPChecker{
+		provider:    provider,
+		history:     history,
+		countryRisk: countryRisk,
+		cfg:         cfg,
+		log:         log.Named("geoip_checker"),
+	}
+}
+
+// Check returns the country the transaction's IP address is located in, if
+// known, and the risk factors raised by its location. A failure to read or
+// write the user's last location is returned along with the factors found
+// before it.
+func (c *GeoIPChecker) Check(ctx context.Context, tx *domain.Transaction) (string, []domain.RiskFactor, error) {
+	var ipLoc *IPLocation
+	if tx.IPAddress != "" {
+		loc, err := c.provider.Locate(ctx, tx.IPAddress)
+		if err != nil {
+			return "", nil, fmt.Errorf("locate ip: %w", err)
+		}
+		ipLoc = loc
+	}
+
+	var country string
+	var factors []domain.RiskFactor
+	if ipLoc != nil && ipLoc.Country != "" {
+		country = ipLoc.Country
+		if tier := c.countryRisk.index().tier(country); tier != nil {
+			factors = append(factors, domain.RiskFactor{
+				Factor:      "IP_HIGH_RISK_COUNTRY",
+				Weight:      c.cfg.HighRiskIPWeight,
+				Description: fmt.Sprintf("IP address is located in a %s jurisdiction", tier.Name),
+				Details:     tx.IPAddress + " (" + country + ")",
+				Tier:        tier.Name,
+			})
+		}
+	}
+
+	here := transactionLocation(tx, ipLoc)
+	if here == nil {
+		return country, factors, nil
+	}
+	previous, err := c.history.SwapLastLocation(ctx, tx.UserID, here, c.cfg.LocationTTL)
+	if err != nil {
+		return country, factors, fmt.Errorf("swap last location: %w", err)
+	}
+	if f := c.impossibleTravel(previous, here); f != nil {
+		factors = append(factors, *f)
+	}
+
+	return country, factors, nil
+}
+
+// impossibleTravel returns an IMPOSSIBLE_TRAVEL factor when getting from
+// the previous location to this one in the time between the transactions
+// would take more than the configured travel speed
+func (c *GeoIPChecker) impossibleTravel(previous, here *domain.TransactionLocation) *domain.RiskFactor {
+	if previous == nil {
+		return nil
+	}
+
+	distance := distanceKm(previous, here)
+	if distance < c.cfg.MinTravelDistanceKm {
+		return nil
+	}
+	elapsed := here.At.Sub(previous.At).Abs()
+	if hours := elapsed.Hours(); hours > 0 && distance/hours <= c.cfg.MaxTravelSpeedKmh {
+		return nil
+	}
+
+	return &domain.RiskFactor{
+		Factor:      "IMPOSSIBLE_TRAVEL",
+		Weight:      c.cfg.ImpossibleTravelWeight,
+		Description: "Location is too far from the user's previous transaction to have travelled in the time between",
+		Details: fmt.Sprintf("%.0f km from %s in %s", distance,
+			describeLocation(previous), elapsed.Round(time.Second)),
+	}
+}
+
+// transactionLocation places a transaction at the coordinates of its geo
+// location, or failing that of its IP address. It returns nil when
+// neither gives coordinates or the transaction carries no timestamp.
+func transactionLocation(tx *domain.Transaction, ipLoc *IPLocation) *domain.TransactionLocation {
+	at := tx.InitiatedAt
+	if at.IsZero() {
+		at = tx.CreatedAt
+	}
+	if at.IsZero() {
+		return nil
+	}
+
+	loc := &domain.TransactionLocation{At: at}
+	if ipLoc != nil {
+		loc.Country = ipLoc.Country
+	}
+	switch lat, lon, ok := geoCoordinates(tx.GeoLocation); {
+	case ok:
+		loc.Latitude, loc.Longitude = lat, lon
+	case ipLoc != nil && ipLoc.HasCoordinates:
+		loc.Latitude, loc.Longitude = ipLoc.Latitude, ipLoc.Longitude
+	default:
+		return nil
+	}
+	return loc
+}
+
+// geoCoordinates parses a geo location given as "latitude,longitude", such
+// as "37.7749,-122.4194". Locations naming a country are not coordinates.
+func geoCoordinates(location string) (float64, float64, bool) {
+	latText, lonText, ok := strings.Cut(location, ",")
+	if !ok {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, false
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonText), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// distanceKm is the great-circle distance between two locations
+func distanceKm(a, b *domain.TransactionLocation) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// describeLocation names a location by country when known, otherwise by
+// its coordinates
+func describeLocation(loc *domain.TransactionLocation) string {
+	if loc.Country != "" {
+		return loc.Country
+	}
+	return fmt.Sprintf("%.4f,%.4f", loc.Latitude, loc.Longitude)
+}
+
+// MaxMindProvider is a GeoIPProvider backed by a MaxMind GeoIP2 or
+// GeoLite2 database file. A nil provider locates nothing.
+type MaxMindProvider struct {
+	reader *maxminddb.Reader
+}
+
+// maxMindRecord is the part of a Country or City database record read
+type maxMindRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// OpenMaxMindProvider opens the MaxMind database at path. Without a path,
+// or when the database cannot be opened, it logs why and returns nil, so
+// screening carries on without IP geolocation rather than failing.
+func OpenMaxMindProvider(path string, log *logger.Logger) *MaxMindProvider {
+	if path == "" {
+		log.Info("no GeoIP database configured, IP geolocation disabled")
+		return nil
+	}
+
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		log.Warn("failed to open GeoIP database, IP geolocation disabled",
+			logger.StringField("path", path),
+			logger.ErrorField(err),
+		)
+		return nil
+	}
+	return &MaxMindProvider{reader: reader}
+}
+
+// Locate looks the IP address up in the database. Addresses that do not
+// parse or are not in the database yield nil.
+func (p *MaxMindProvider) Locate(_ context.Context, ip string) (*IPLocation, error) {
+	if p == nil {
+		return nil, nil
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, nil
+	}
+
+	var record maxMindRecord
+	if err := p.reader.Lookup(parsed, &record); err != nil {
+		return nil, fmt.Errorf("lookup %s: %w", ip, err)
+	}
+
+	loc := &IPLocation{Country: record.Country.ISOCode}
+	if loc.Country == "" {
+		loc.Country = record.RegisteredCountry.ISOCode
+	}
+	if record.Location.Latitude != nil && record.Location.Longitude != nil {
+		loc.Latitude, loc.Longitude = *record.Location.Latitude, *record.Location.Longitude
+		loc.HasCoordinates = true
+	}
+	if loc.Country == "" && !loc.HasCoordinates {
+		return nil, nil
+	}
+	return loc, nil
+}
+
+// Close releases the database
+func (p *MaxMindProvider) Close() error {
+	if p == nil {
+		return nil
+	}
+	return p.reader.Close()
+}
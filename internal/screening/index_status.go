@@ -0,0 +1,48 @@
+package screening
+
+import "time"
+
+// IndexStatus describes a checker's in-memory list index
+type IndexStatus struct {
+	// Loaded is false until a load has returned at least one entry; an
+	// empty index cannot screen anything
+	Loaded        bool       `json:"loaded"`
+	Entries       int        `json:"entries"`
+	LoadedAt      *time.Time `json:"loaded_at,omitempty"`
+	ListUpdatedAt *time.Time `json:"list_updated_at,omitempty"`
+
+	// Version and Hash, a SHA-256 over the loaded entries, are stamped on
+	// every screening result so a decision can be traced to the list it
+	// used
+	Version int64  `json:"version,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+}
+
+func newIndexStatus(entries int, loadedAt, listUpdatedAt time.Time, version ListVersion) IndexStatus {
+	s := IndexStatus{
+		Loaded:  !loadedAt.IsZero() && entries > 0,
+		Entries: entries,
+		Version: version.Version,
+		Hash:    version.Hash,
+	}
+	if !loadedAt.IsZero() {
+		s.LoadedAt = &loadedAt
+	}
+	if !listUpdatedAt.IsZero() {
+		s.ListUpdatedAt = &listUpdatedAt
+	}
+	return s
+}
+
+// Age returns how old the loaded list is: the time since the list was last
+// updated, or since it was loaded when the list timestamp is unknown. It is
+// zero when the index has never loaded.
+func (s IndexStatus) Age(now time.Time) time.Duration {
+	switch {
+	case s.ListUpdatedAt != nil:
+		return now.Sub(*s.ListUpdatedAt)
+	case s.LoadedAt != nil:
+		return now.Sub(*s.LoadedAt)
+	}
+	return 0
+}
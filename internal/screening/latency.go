@@ -0,0 +1,57 @@
+package screening
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencySampleSize is how many recent screening latencies are kept for
+// percentile estimates
+const latencySampleSize = 4096
+
+// latencySamples keeps the most recent screening latencies in a ring
+// buffer. Percentiles over the window track current behaviour, unlike a
+// lifetime average, at a fixed memory cost.
+type latencySamples struct {
+	mu      sync.Mutex
+	samples []float64 // milliseconds
+	next    int
+}
+
+func newLatencySamples(size int) *latencySamples {
+	return &latencySamples{samples: make([]float64, 0, size)}
+}
+
+// add records one latency, overwriting the oldest once the window is full
+func (s *latencySamples) add(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.samples) < cap(s.samples) {
+		s.samples = append(s.samples, ms)
+		return
+	}
+	s.samples[s.next] = ms
+	s.next = (s.next + 1) % len(s.samples)
+}
+
+// percentile returns the p-th percentile (0-100) of the window using the
+// nearest-rank method, or 0 when nothing has been recorded
+func (s *latencySamples) percentile(p float64) float64 {
+	s.mu.Lock()
+	sorted := slices.Clone(s.samples)
+	s.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0
+	}
+	slices.Sort(sorted)
+
+	p = min(max(p, 0), 100)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
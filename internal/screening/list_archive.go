@@ -0,0 +1,147 @@
+package screening
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// listArchivePrefix is where list snapshots are kept in the object store,
+// one object per list and version
+const listArchivePrefix = "lists/"
+
+// ErrListVersionNotRetained is returned for a list version whose snapshot
+// is not in the archive
+var ErrListVersionNotRetained = errors.New("list version not retained")
+
+// ObjectStore stores and reads objects by key, returning domain.ErrNotFound
+// for a key it does not hold
+type ObjectStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// ListArchiver keeps a snapshot of every list version a checker loads
+type ListArchiver interface {
+	ArchiveOFAC(ctx context.Context, version ListVersion, updatedAt time.Time, entries []OFACEntry) error
+	ArchivePEP(ctx context.Context, version ListVersion, updatedAt time.Time, entries []PEPEntry) error
+}
+
+// ListArchive keeps every OFAC and PEP list version in the object store as
+// a gzipped snapshot file, so screenings can be replayed against the exact
+// list they used
+type ListArchive struct {
+	store ObjectStore
+}
+
+// NewListArchive creates a new list archive
+func NewListArchive(store ObjectStore) *ListArchive {
+	return &ListArchive{store: store}
+}
+
+// ArchiveOFAC stores an OFAC list version
+func (a *ListArchive) ArchiveOFAC(ctx context.Context, version ListVersion, updatedAt time.Time, entries []OFACEntry) error {
+	return archiveList(ctx, a.store, "ofac", version, updatedAt, entries)
+}
+
+// ArchivePEP stores a PEP list version
+func (a *ListArchive) ArchivePEP(ctx context.Context, version ListVersion, updatedAt time.Time, entries []PEPEntry) error {
+	return archiveList(ctx, a.store, "pep", version, updatedAt, entries)
+}
+
+// Snapshot reads archived OFAC and PEP list versions as a list snapshot.
+// It fails with ErrListVersionNotRetained when either is not archived, and
+// refuses a snapshot whose entries no longer hash to its version's hash.
+func (a *ListArchive) Snapshot(ctx context.Context, ofacVersion, pepVersion int64) (*ListSnapshot, error) {
+	ofac, err := readArchivedList[OFACEntry](ctx, a.store, "ofac", ofacVersion)
+	if err != nil {
+		return nil, err
+	}
+	pep, err := readArchivedList[PEPEntry](ctx, a.store, "pep", pepVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &ListSnapshot{
+		OFAC:          ofac.Entries,
+		PEP:           pep.Entries,
+		OFACUpdatedAt: ofac.UpdatedAt,
+		PEPUpdatedAt:  pep.UpdatedAt,
+		OFACVersion:   ofac.Version,
+		PEPVersion:    pep.Version,
+	}, nil
+}
+
+func listArchiveKey(list string, version int64) string {
+	return fmt.Sprintf("%s%s/%d.json.gz", listArchivePrefix, list, version)
+}
+
+// archiveList stores a list version's snapshot. A version already archived
+// with the same hash is left as is; one archived with another hash, as
+// after the version counter was lost, is never overwritten.
+func archiveList[E any](ctx context.Context, store ObjectStore, list string, version ListVersion, updatedAt time.Time, entries []E) error {
+	existing, err := readArchivedList[E](ctx, store, list, version.Version)
+	switch {
+	case err == nil && existing.Hash == version.Hash:
+		return nil
+	case err == nil:
+		return fmt.Errorf("%s list version %d is already archived with hash %s", list, version.Version, existing.Hash)
+	case !errors.Is(err, ErrListVersionNotRetained):
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	err = json.NewEncoder(zw).Encode(snapshotFile[E]{
+		Version:   version.Version,
+		Hash:      version.Hash,
+		UpdatedAt: updatedAt,
+		Entries:   entries,
+	})
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("encode %s list version %d: %w", list, version.Version, err)
+	}
+
+	if err := store.Put(ctx, listArchiveKey(list, version.Version), "application/gzip", buf.Bytes()); err != nil {
+		return fmt.Errorf("archive %s list version %d: %w", list, version.Version, err)
+	}
+	return nil
+}
+
+func readArchivedList[E any](ctx context.Context, store ObjectStore, list string, version int64) (*snapshotFile[E], error) {
+	data, err := store.Get(ctx, listArchiveKey(list, version))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s list version %d", ErrListVersionNotRetained, list, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s list version %d: %w", list, version, err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("read %s list version %d: %w", list, version, err)
+	}
+	defer zr.Close()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("read %s list version %d: %w", list, version, err)
+	}
+
+	var f snapshotFile[E]
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("decode %s list version %d: %w", list, version, err)
+	}
+	if f.Version != version || listHash(f.Entries) != f.Hash {
+		return nil, fmt.Errorf("%s list version %d: archived snapshot does not match its hash", list, version)
+	}
+	return &f, nil
+}
@@ -0,0 +1,43 @@
+package screening
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// ListVersion identifies one content of a screening list. Versions count up
+// from 1 and are shared by every instance through the list cache: a load
+// whose entries hash the same as the current version keeps it, any other
+// load gets the next one. Version 0 means the version is unknown.
+type ListVersion struct {
+	Version  int64     `json:"version"`
+	Hash     string    `json:"hash"`
+	Entries  int       `json:"entries"`
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+// listHash returns a hex SHA-256 over a list's entries, taken in sorted
+// order so the same entries hash alike however the cache returned them. It
+// is empty for an empty list.
+func listHash[E any](entries []E) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	encoded := make([][]byte, len(entries))
+	for i, entry := range entries {
+		encoded[i], _ = json.Marshal(entry)
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+
+	h := sha256.New()
+	for _, data := range encoded {
+		h.Write(data)
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
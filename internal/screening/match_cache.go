@@ -0,0 +1,76 @@
+package screening
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// matchCacheKey keys a list check outcome on the counterparty's normalized
+// name and the version of the loaded list, so a list refresh moves checks
+// to fresh keys and outcomes for the previous list are never served. The
+// name is hashed to keep counterparty names out of Redis keys. It returns
+// "" when the outcome must not be cached: there is no cache, the request
+// bypasses cached state, or the list version is unknown.
+func (e *Engine) matchCacheKey(sctx *ScreeningContext, normalizer *NameNormalizer, name string, listUpdatedAt time.Time) string {
+	if e.matchCache == nil || sctx.BypassIndex || listUpdatedAt.IsZero() {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(normalizer.Normalize(name)))
+	return fmt.Sprintf("%d:%s", listUpdatedAt.UnixNano(), hex.EncodeToString(sum[:]))
+}
+
+// cachedOFACMatch returns the cached OFAC outcome for a key, if any. Cache
+// errors are treated as a miss.
+func (e *Engine) cachedOFACMatch(ctx context.Context, key string) *domain.OFACMatch {
+	if key == "" {
+		return nil
+	}
+	match, err := e.matchCache.GetOFAC(ctx, key)
+	if err != nil {
+		e.log.Warn("failed to read ofac match cache", logger.ErrorField(err))
+		return nil
+	}
+	if match != nil {
+		match.CacheHit = true
+	}
+	return match
+}
+
+// cachedPEPMatch returns the cached PEP outcome for a key, if any. Cache
+// errors are treated as a miss.
+func (e *Engine) cachedPEPMatch(ctx context.Context, key string) *domain.PEPMatch {
+	if key == "" {
+		return nil
+	}
+	match, err := e.matchCache.GetPEP(ctx, key)
+	if err != nil {
+		e.log.Warn("failed to read pep match cache", logger.ErrorField(err))
+		return nil
+	}
+	if match != nil {
+		match.CacheHit = true
+	}
+	return match
+}
+
+// cacheMatches caches the list check outcomes a screening looked up. It
+// runs after the decision so the write never counts against the checks'
+// latency budgets; degraded outcomes and cache hits have no key.
+func (e *Engine) cacheMatches(ctx context.Context, sctx *ScreeningContext) {
+	if sctx.ofacCacheKey != "" {
+		if err := e.matchCache.SetOFAC(ctx, sctx.ofacCacheKey, sctx.OFACResult); err != nil {
+			e.log.Warn("failed to cache ofac match", logger.ErrorField(err))
+		}
+	}
+	if sctx.pepCacheKey != "" {
+		if err := e.matchCache.SetPEP(ctx, sctx.pepCacheKey, sctx.PEPResult); err != nil {
+			e.log.Warn("failed to cache pep match", logger.ErrorField(err))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package screening
+
+import (
+	"fmt"
+
+	"github.com/banking/aml-service/internal/pkg/fuzzy"
+)
+
+// Name matching algorithms, selectable per list in screening.name_matchers
+const (
+	MatcherJaroWinkler = "jaro_winkler"
+	MatcherLevenshtein = "levenshtein"
+	MatcherPhonetic    = "phonetic"
+)
+
+// NameMatcher scores the similarity of two normalized names, from 0 (no
+// match) to 1 (exact match). Scores are compared against the fuzzy match
+// threshold, so algorithms that score more strictly may need it lowered.
+type NameMatcher interface {
+	Similarity(a, b string) float64
+}
+
+// similarityFunc adapts a fuzzy similarity function to NameMatcher
+type similarityFunc func(a, b string) float64
+
+func (f similarityFunc) Similarity(a, b string) float64 {
+	return f(a, b)
+}
+
+var nameMatchers = map[string]NameMatcher{
+	MatcherJaroWinkler: similarityFunc(fuzzy.JaroWinkler),
+	MatcherLevenshtein: similarityFunc(fuzzy.Levenshtein),
+	MatcherPhonetic:    similarityFunc(fuzzy.Phonetic),
+}
+
+// NewNameMatcher returns the matcher for an algorithm name. An empty name
+// selects Jaro-Winkler.
+func NewNameMatcher(algorithm string) (NameMatcher, error) {
+	if algorithm == "" {
+		algorithm = MatcherJaroWinkler
+	}
+	m, ok := nameMatchers[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unknown name matcher %q", algorithm)
+	}
+	return m, nil
+}
+
+// better reports whether a candidate name scoring score beats the best so
+// far. Equal scores go to the lexically smaller name, so ties resolve the
+// same way on every run whatever the map iteration order.
+func better(score float64, candidate string, bestScore float64, bestCandidate string) bool {
+	return score > bestScore || (score == bestScore && candidate < bestCandidate)
+}
@@ -2,37 +2,115 @@ package screening
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/breaker"
 	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
 )
 
 // OFACChecker performs OFAC sanctions list screening
 // Target: <1ms per lookup using Redis cache
 type OFACChecker struct {
-	cache     OFACCache
-	log       *logger.Logger
-	threshold float64 // Fuzzy match threshold (e.g., 0.85)
-
-	// In-memory index for fast exact match (loaded from Redis)
-	exactIndex map[string]OFACEntry
-	indexMu    sync.RWMutex
+	cache      OFACCache
+	archive    ListArchiver
+	matcher    NameMatcher
+	normalizer *NameNormalizer
+	log        *logger.Logger
+	threshold  float64 // Fuzzy match threshold (e.g., 0.85)
+
+	// Most names CheckBatch checks at once, so a large batch cannot starve
+	// live screening of Redis connections
+	batchWorkers int
+
+	// Most entities a reload may change before the new list is refused as
+	// a likely truncated or corrupt download; 0 accepts any change
+	maxDelta int
+
+	// matcher that folds cached list names as the normalizer does
+	listMatcher NameMatcher
+
+	// In-memory index for fast exact match (loaded from Redis), and the
+	// Entity entries keyed by their names with entity stopwords stripped.
+	// The maps are never modified once published: a reload builds new ones
+	// and swaps them in under a brief write lock, so lookups are not
+	// stalled while a list is indexed. Scans take the maps under the read
+	// lock and release it before matching, so a pending swap never waits
+	// on a scan, nor do the lookups queued behind it.
+	exactIndex  map[string]OFACEntry
+	entityIndex map[string]OFACEntry
+	indexMu     sync.RWMutex
+
+	// Designated banks keyed by the institution part of their BICs
+	bicIndex map[string]OFACEntry
+
+	// Serializes reloads, so each delta is computed against the entries it
+	// replaces
+	reloadMu sync.Mutex
+
+	// Entries of the last load keyed by entryKey, used to compute deltas
+	entries map[string]OFACEntry
+
+	// Last-update timestamp and version of the list the index was loaded
+	// from
+	listUpdatedAt time.Time
+	listVersion   ListVersion
+
+	// When the index was last loaded; zero until the first load
+	loadedAt time.Time
+
+	// When a failed cache lookup last started a background reload, in Unix
+	// nanoseconds
+	revalidatedAt atomic.Int64
 }
 
+const (
+	// ofacRevalidateInterval spaces the background reloads started by
+	// failed cache lookups, so an outage does not start one per screening
+	ofacRevalidateInterval = 5 * time.Second
+	// ofacRevalidateTimeout bounds such a reload
+	ofacRevalidateTimeout = 30 * time.Second
+)
+
 // OFACCache interface for OFAC data caching
 type OFACCache interface {
 	GetByExactName(ctx context.Context, name string) (*OFACEntry, error)
-	GetByFuzzyName(ctx context.Context, name string, threshold float64) ([]OFACEntry, error)
+	GetByFuzzyName(ctx context.Context, name string, matcher NameMatcher, threshold float64) ([]OFACEntry, error)
 	GetAllEntries(ctx context.Context) ([]OFACEntry, error)
-	SetEntries(ctx context.Context, entries []OFACEntry, ttl time.Duration) error
+	SetEntries(ctx context.Context, entries []OFACEntry, ttl time.Duration, mode ListWriteMode) error
 	GetLastUpdate(ctx context.Context) (time.Time, error)
 	SetLastUpdate(ctx context.Context, t time.Time) error
+	GetVersion(ctx context.Context) (ListVersion, error)
+	RecordVersion(ctx context.Context, hash string, entries int, at time.Time) (version ListVersion, created bool, err error)
 }
 
+// ListWriteMode says how SetEntries applies entries to the cached list
+type ListWriteMode int
+
+const (
+	// ListMerge adds or updates the given entries and leaves every other
+	// cached entry in place, for incremental feeds
+	ListMerge ListWriteMode = iota
+	// ListFullReplace treats the entries as the complete list: cached
+	// entries absent from it are tombstoned, removing them at once rather
+	// than when the list's TTL runs out
+	ListFullReplace
+)
+
+// OFACTypeEntity is the SDN type of companies and other organizations
+const OFACTypeEntity = "Entity"
+
 // OFACEntry represents an entry from the OFAC SDN list
 type OFACEntry struct {
 	EntityID       string   `json:"entity_id"`
@@ -42,16 +120,32 @@ type OFACEntry struct {
 	Aliases        []string `json:"aliases"`
 	Addresses      []string `json:"addresses,omitempty"`
 	Remarks        string   `json:"remarks,omitempty"`
+	BICs           []string `json:"bics,omitempty"` // SWIFT BICs of designated banks
 	NormalizedName string   `json:"normalized_name"`
 }
 
-// NewOFACChecker creates a new OFAC checker
-func NewOFACChecker(cache OFACCache, log *logger.Logger, threshold float64) *OFACChecker {
+// remarksBIC finds the BICs the SDN list records in an entry's remarks, as
+// in "SWIFT/BIC BKIDIRTH"
+var remarksBIC = regexp.MustCompile(`(?i)SWIFT/BIC\s+([A-Z0-9]{8}(?:[A-Z0-9]{3})?)\b`)
+
+// NewOFACChecker creates a new OFAC checker. CheckBatch checks at most
+// batchWorkers names concurrently. A reload changing more than maxDelta
+// entities is refused, keeping the loaded index; 0 accepts any change.
+// Each new list version is archived when archive is not nil.
+func NewOFACChecker(cache OFACCache, archive ListArchiver, matcher NameMatcher, normalizer *NameNormalizer, log *logger.Logger, threshold float64, batchWorkers, maxDelta int) *OFACChecker {
 	return &OFACChecker{
-		cache:      cache,
-		log:        log.Named("ofac_checker"),
-		threshold:  threshold,
-		exactIndex: make(map[string]OFACEntry),
+		cache:        cache,
+		archive:      archive,
+		matcher:      matcher,
+		normalizer:   normalizer,
+		log:          log.Named("ofac_checker"),
+		threshold:    threshold,
+		batchWorkers: max(batchWorkers, 1),
+		maxDelta:     maxDelta,
+		listMatcher:  normalizer.listMatcher(matcher),
+		exactIndex:   make(map[string]OFACEntry),
+		entityIndex:  make(map[string]OFACEntry),
+		bicIndex:     make(map[string]OFACEntry),
 	}
 }
 
@@ -61,7 +155,7 @@ func (c *OFACChecker) Check(ctx context.Context, name string) (*domain.OFACMatch
 		return &domain.OFACMatch{Matched: false}, nil
 	}
 
-	normalizedName := normalizeName(name)
+	normalizedName := c.normalizer.Normalize(name)
 
 	// 1. Try exact match first (fastest, <0.1ms)
 	if match, found := c.exactMatch(normalizedName); found {
@@ -78,7 +172,10 @@ func (c *OFACChecker) Check(ctx context.Context, name string) (*domain.OFACMatch
 
 	// 2. Try cache lookup (should be <1ms)
 	entry, err := c.cache.GetByExactName(ctx, normalizedName)
-	if err == nil && entry != nil {
+	if err != nil {
+		return c.fallbackMatch(normalizedName, err)
+	}
+	if entry != nil {
 		return &domain.OFACMatch{
 			Matched:      true,
 			MatchScore:   1.0,
@@ -91,11 +188,14 @@ func (c *OFACChecker) Check(ctx context.Context, name string) (*domain.OFACMatch
 	}
 
 	// 3. Fuzzy match (slightly slower, but still <5ms)
-	fuzzyMatches, err := c.cache.GetByFuzzyName(ctx, normalizedName, c.threshold)
-	if err == nil && len(fuzzyMatches) > 0 {
+	fuzzyMatches, err := c.cache.GetByFuzzyName(ctx, normalizedName, c.listMatcher, c.threshold)
+	if err != nil {
+		return c.fallbackMatch(normalizedName, err)
+	}
+	if len(fuzzyMatches) > 0 {
 		// Return best match
 		bestMatch := fuzzyMatches[0]
-		similarity := jaroWinkler(normalizedName, bestMatch.NormalizedName)
+		similarity := c.listMatcher.Similarity(normalizedName, bestMatch.NormalizedName)
 
 		return &domain.OFACMatch{
 			Matched:      true,
@@ -108,59 +208,503 @@ func (c *OFACChecker) Check(ctx context.Context, name string) (*domain.OFACMatch
 		}, nil
 	}
 
+	// 4. Entity names with corporate suffixes and stopwords stripped
+	if entry, score, found := c.entityMatch(normalizedName); found {
+		return &domain.OFACMatch{
+			Matched:      true,
+			MatchScore:   score,
+			MatchType:    domain.MatchTypeFuzzy,
+			SDNName:      entry.Name,
+			SDNType:      entry.Type,
+			Program:      entry.Program,
+			MatchedField: "name",
+		}, nil
+	}
+
 	// No match found
 	return &domain.OFACMatch{Matched: false}, nil
 }
 
-// CheckBatch performs OFAC screening on multiple names concurrently
-func (c *OFACChecker) CheckBatch(ctx context.Context, names []string) (map[string]*domain.OFACMatch, error) {
-	results := make(map[string]*domain.OFACMatch)
+// entityMatch matches a name, stripped of entity stopwords, against the
+// stripped names of Entity entries in the in-memory index and returns the
+// best match at or above the threshold
+func (c *OFACChecker) entityMatch(normalizedName string) (OFACEntry, float64, bool) {
+	c.indexMu.RLock()
+	entityIndex := c.entityIndex
+	c.indexMu.RUnlock()
+	return c.entityMatchIn(entityIndex, normalizedName)
+}
+
+// entityMatchIn matches a name, stripped of entity stopwords, against an
+// index of stripped Entity names
+func (c *OFACChecker) entityMatchIn(entityIndex map[string]OFACEntry, normalizedName string) (OFACEntry, float64, bool) {
+	stripped := c.normalizer.EntityName(normalizedName)
+	if entry, found := entityIndex[stripped]; found {
+		return entry, 1.0, true
+	}
+
+	var best OFACEntry
+	var bestName string
+	bestScore := 0.0
+	for candidate, entry := range entityIndex {
+		if score := c.matcher.Similarity(stripped, candidate); better(score, candidate, bestScore, bestName) {
+			best, bestName, bestScore = entry, candidate, score
+		}
+	}
+	return best, bestScore, bestScore >= c.threshold
+}
+
+// fallbackMatch screens against the in-memory index, the last list known
+// to be good, when a cache lookup fails. A lookup refused by the open
+// breaker marks the match degraded; one that failed after its retries
+// instead starts a background reload of the index, so it catches up with
+// the list once the cache answers again. With no index loaded either, the
+// check fails: a cache outage never yields a clean result.
+func (c *OFACChecker) fallbackMatch(normalizedName string, cacheErr error) (*domain.OFACMatch, error) {
+	c.indexMu.RLock()
+	exactIndex, entityIndex, version := c.exactIndex, c.entityIndex, c.listVersion
+	c.indexMu.RUnlock()
+
+	if len(exactIndex) == 0 {
+		metrics.RecordOFACCacheFailure("failed")
+		return nil, fmt.Errorf("ofac cache unavailable and index not loaded: %w", cacheErr)
+	}
+
+	match := c.matchIndex(normalizedName, exactIndex, entityIndex)
+	if breaker.IsOpen(cacheErr) {
+		match.Degraded = true
+		return match, nil
+	}
+
+	metrics.RecordOFACCacheFailure("index")
+	c.log.Warn("ofac cache lookup failed, screened against the index",
+		logger.Int64Field("version", version.Version),
+		logger.ErrorField(cacheErr),
+	)
+	c.revalidate()
+	return match, nil
+}
+
+// revalidate reloads the index in the background, at most once per
+// ofacRevalidateInterval
+func (c *OFACChecker) revalidate() {
+	now := time.Now().UnixNano()
+	last := c.revalidatedAt.Load()
+	if now-last < int64(ofacRevalidateInterval) || !c.revalidatedAt.CompareAndSwap(last, now) {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ofacRevalidateTimeout)
+		defer cancel()
+		if _, err := c.ReloadIndex(ctx); err != nil {
+			c.log.Warn("ofac index revalidation failed", logger.ErrorField(err))
+		}
+	}()
+}
+
+// CheckSource screens a name against the list as currently held in the
+// cache, skipping the in-memory index, for requests that bypass cached
+// state. It reads the whole list, so it is much slower than Check, and a
+// cache failure fails the check instead of falling back to the index.
+func (c *OFACChecker) CheckSource(ctx context.Context, name string) (*domain.OFACMatch, error) {
+	if name == "" {
+		return &domain.OFACMatch{Matched: false}, nil
+	}
+
+	entries, err := c.cache.GetAllEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ofac cache lookup: %w", err)
+	}
+	exactIndex, entityIndex := c.buildIndex(entries)
+	return c.matchIndex(c.normalizer.Normalize(name), exactIndex, entityIndex), nil
+}
+
+// matchIndex screens a normalized name against an index: exactly, then by
+// similarity to every indexed name and alias, then by Entity name with
+// stopwords stripped
+func (c *OFACChecker) matchIndex(normalizedName string, exactIndex, entityIndex map[string]OFACEntry) *domain.OFACMatch {
+	if entry, found := exactIndex[normalizedName]; found {
+		return &domain.OFACMatch{
+			Matched:      true,
+			MatchScore:   1.0,
+			MatchType:    domain.MatchTypeExact,
+			SDNName:      entry.Name,
+			SDNType:      entry.Type,
+			Program:      entry.Program,
+			MatchedField: "name",
+		}
+	}
+
+	var best OFACEntry
+	var bestName string
+	bestScore := 0.0
+	for candidate, entry := range exactIndex {
+		if score := c.matcher.Similarity(normalizedName, candidate); better(score, candidate, bestScore, bestName) {
+			best, bestName, bestScore = entry, candidate, score
+		}
+	}
+
+	if bestScore < c.threshold {
+		if entry, score, found := c.entityMatchIn(entityIndex, normalizedName); found {
+			best, bestScore = entry, score
+		} else {
+			return &domain.OFACMatch{Matched: false}
+		}
+	}
+	return &domain.OFACMatch{
+		Matched:      true,
+		MatchScore:   bestScore,
+		MatchType:    domain.MatchTypeFuzzy,
+		SDNName:      best.Name,
+		SDNType:      best.Type,
+		Program:      best.Program,
+		MatchedField: "name",
+	}
+}
+
+// BatchFailure is a name whose check failed within a batch
+type BatchFailure struct {
+	Index int
+	Name  string
+	Err   error
+}
+
+// BatchError lists the names of a batch whose checks failed. The results
+// for the other names are returned alongside it.
+type BatchError struct {
+	Failed []BatchFailure
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d names failed, first: %v", len(e.Failed), e.Failed[0].Err)
+}
+
+// CheckBatch screens names with at most batchWorkers checks in flight and
+// returns the matches in input order: results[i] is the match for
+// names[i], or nil if it was not checked. Names whose check fails are
+// listed in a *BatchError. Cancelling ctx stops the batch promptly; the
+// names checked so far are returned with the context's error.
+func (c *OFACChecker) CheckBatch(ctx context.Context, names []string) ([]*domain.OFACMatch, error) {
+	results := make([]*domain.OFACMatch, len(names))
+	var failures []BatchFailure
 	var mu sync.Mutex
-	var wg sync.WaitGroup
 
-	for _, name := range names {
-		wg.Add(1)
-		go func(n string) {
-			defer wg.Done()
-			result, err := c.Check(ctx, n)
+	var g errgroup.Group
+	g.SetLimit(c.batchWorkers)
+	for i, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			match, err := c.Check(ctx, name)
 			if err != nil {
-				c.log.Warn("batch ofac check failed for name", logger.ErrorField(err))
-				return
+				mu.Lock()
+				failures = append(failures, BatchFailure{Index: i, Name: name, Err: err})
+				mu.Unlock()
+				return nil
 			}
-			mu.Lock()
-			results[n] = result
-			mu.Unlock()
-		}(name)
+			results[i] = match
+			return nil
+		})
 	}
+	g.Wait()
 
-	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	if len(failures) > 0 {
+		sort.Slice(failures, func(i, j int) bool { return failures[i].Index < failures[j].Index })
+		c.log.Warn("batch ofac checks failed",
+			logger.IntField("failed", len(failures)),
+			logger.IntField("names", len(names)),
+			logger.ErrorField(failures[0].Err),
+		)
+		return results, &BatchError{Failed: failures}
+	}
 	return results, nil
 }
 
 // LoadIndex loads OFAC list into in-memory index for fastest lookups
 func (c *OFACChecker) LoadIndex(ctx context.Context) error {
+	_, err := c.ReloadIndex(ctx)
+	return err
+}
+
+// ReloadIndex reloads the in-memory index and returns what changed since
+// the previous load. The delta of the first load is marked Initial. A list
+// changing more entities than the sanity threshold allows is not loaded:
+// screening carries on against the previous index, and the delta is
+// returned with an error wrapping ErrOFACListRejected.
+func (c *OFACChecker) ReloadIndex(ctx context.Context) (*OFACDelta, error) {
 	entries, err := c.cache.GetAllEntries(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	updatedAt, err := c.cache.GetLastUpdate(ctx)
+	if err != nil {
+		c.log.Warn("ofac list last update unavailable", logger.ErrorField(err))
+	}
+
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	// Build the new index off to the side; lookups keep using the old one
+	c.indexMu.RLock()
+	previous := c.entries
+	c.indexMu.RUnlock()
+
+	delta := computeOFACDelta(previous, entries, c.matcher, c.normalizer)
+	if !delta.Initial && c.maxDelta > 0 && delta.Size() > c.maxDelta {
+		metrics.RecordOFACListRejected()
+		c.log.Error("ofac list change exceeds sanity threshold, previous index kept",
+			logger.IntField("delta_size", delta.Size()),
+			logger.IntField("max_entries", c.maxDelta),
+			logger.IntField("added", len(delta.Added)),
+			logger.IntField("modified", len(delta.Modified)),
+			logger.IntField("removed", len(delta.Removed)),
+			logger.IntField("previous_count", delta.PreviousCount),
+			logger.IntField("current_count", delta.CurrentCount),
+		)
+		return delta, fmt.Errorf("%w: %d entities changed, at most %d allowed",
+			ErrOFACListRejected, delta.Size(), c.maxDelta)
 	}
 
+	byKey := make(map[string]OFACEntry, len(entries))
+	for _, entry := range entries {
+		byKey[entryKey(entry)] = entry
+	}
+	exactIndex, entityIndex := c.buildIndex(entries)
+	bicIndex := buildBICIndex(entries)
+	version, created := c.recordVersion(ctx, entries)
+	delta.Version = version
+
+	// The list version only moves with the index it describes
 	c.indexMu.Lock()
-	defer c.indexMu.Unlock()
+	c.entries = byKey
+	c.exactIndex, c.entityIndex = exactIndex, entityIndex
+	c.bicIndex = bicIndex
+	c.listUpdatedAt = updatedAt
+	c.listVersion = version
+	c.loadedAt = time.Now()
+	c.indexMu.Unlock()
+
+	c.log.Info("ofac index loaded",
+		logger.IntField("entries", len(entries)),
+		logger.IntField("bics", len(bicIndex)),
+		logger.Int64Field("version", version.Version),
+		logger.StringField("hash", version.Hash),
+		logger.IntField("added", len(delta.Added)),
+		logger.IntField("modified", len(delta.Modified)),
+		logger.IntField("removed", len(delta.Removed)),
+	)
+	c.reportRemoved(delta.Removed)
+
+	if created && c.archive != nil {
+		if err := c.archive.ArchiveOFAC(ctx, version, updatedAt, entries); err != nil {
+			c.log.Error("failed to archive ofac list version",
+				logger.Int64Field("version", version.Version),
+				logger.ErrorField(err),
+			)
+		}
+	}
+	return delta, nil
+}
 
-	c.exactIndex = make(map[string]OFACEntry, len(entries))
+// recordVersion registers the entries' hash with the list cache and
+// returns the list version they are, and whether this load created it. If
+// the cache cannot be reached the version is left unknown.
+func (c *OFACChecker) recordVersion(ctx context.Context, entries []OFACEntry) (ListVersion, bool) {
+	hash := listHash(entries)
+	if hash == "" {
+		return ListVersion{}, false
+	}
+
+	version, created, err := c.cache.RecordVersion(ctx, hash, len(entries), time.Now())
+	if err != nil {
+		c.log.Warn("ofac list version unavailable", logger.ErrorField(err))
+		return ListVersion{Hash: hash, Entries: len(entries)}, false
+	}
+	return version, created
+}
+
+// reportRemoved logs and counts the designations a reload dropped. They no
+// longer match from the moment the new index is swapped in, and match cache
+// keys move with the list version, so no cached outcome keeps blocking a
+// delisted party.
+func (c *OFACChecker) reportRemoved(removed []OFACEntry) {
+	if len(removed) == 0 {
+		return
+	}
+
+	metrics.RecordOFACDesignationsRemoved(len(removed))
+	for _, entry := range removed {
+		c.log.Info("ofac designation removed",
+			logger.StringField("entity_id", entry.EntityID),
+			logger.StringField("name", entry.Name),
+			logger.StringField("program", entry.Program),
+		)
+	}
+}
+
+// buildIndex indexes entries by normalized name and aliases, and Entity
+// entries also by those names with entity stopwords stripped
+func (c *OFACChecker) buildIndex(entries []OFACEntry) (exactIndex, entityIndex map[string]OFACEntry) {
+	exactIndex = make(map[string]OFACEntry, len(entries))
+	entityIndex = make(map[string]OFACEntry)
 	for _, entry := range entries {
+		names := make([]string, 0, len(entry.Aliases)+1)
 		// Index by normalized name
-		c.exactIndex[entry.NormalizedName] = entry
+		names = append(names, c.normalizer.listName(entry.NormalizedName))
 		// Also index by aliases
 		for _, alias := range entry.Aliases {
-			c.exactIndex[normalizeName(alias)] = entry
+			names = append(names, c.normalizer.Normalize(alias))
 		}
+
+		for _, name := range names {
+			exactIndex[name] = entry
+			if c.normalizer.stripsEntityNames() && strings.EqualFold(entry.Type, OFACTypeEntity) {
+				entityIndex[c.normalizer.EntityName(name)] = entry
+			}
+		}
+	}
+	return exactIndex, entityIndex
+}
+
+// buildBICIndex indexes the entries carrying BICs, in their BICs field or
+// their remarks, by the institution part of each BIC
+func buildBICIndex(entries []OFACEntry) map[string]OFACEntry {
+	bicIndex := make(map[string]OFACEntry)
+	for _, entry := range entries {
+		bics := slices.Clone(entry.BICs)
+		for _, m := range remarksBIC.FindAllStringSubmatch(entry.Remarks, -1) {
+			bics = append(bics, m[1])
+		}
+		for _, bic := range bics {
+			if institution := domain.BICInstitution(bic); institution != "" {
+				bicIndex[institution] = entry
+			}
+		}
+	}
+	return bicIndex
+}
+
+// CheckBIC screens a bank's SWIFT BIC against the designated banks in the
+// in-memory index. Any branch of a designated institution matches. BICs
+// are only held in the index, so it fails until the index is loaded.
+func (c *OFACChecker) CheckBIC(bic string) (*domain.OFACMatch, error) {
+	institution := domain.BICInstitution(bic)
+	if institution == "" {
+		return &domain.OFACMatch{Matched: false}, nil
 	}
 
-	c.log.Info("ofac index loaded", logger.IntField("entries", len(entries)))
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+
+	if c.loadedAt.IsZero() {
+		return nil, fmt.Errorf("ofac index not loaded")
+	}
+	entry, found := c.bicIndex[institution]
+	if !found {
+		return &domain.OFACMatch{Matched: false}, nil
+	}
+	return &domain.OFACMatch{
+		Matched:      true,
+		MatchScore:   1.0,
+		MatchType:    domain.MatchTypeExact,
+		SDNName:      entry.Name,
+		SDNType:      entry.Type,
+		Program:      entry.Program,
+		MatchedField: "bic",
+	}, nil
+}
+
+// EntryDelta returns a delta holding only the loaded entry with the given
+// SDN entity ID, so stored names can be matched against that one
+// designation. ok is false when no loaded entry has the ID.
+func (c *OFACChecker) EntryDelta(entityID string) (*OFACDelta, bool) {
+	c.indexMu.RLock()
+	entry, ok := c.entries[entityID]
+	c.indexMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	delta := &OFACDelta{
+		Added:      []OFACEntry{entry},
+		names:      make(map[string]OFACEntry),
+		matcher:    c.matcher,
+		normalizer: c.normalizer,
+	}
+	for _, name := range entryNames(entry, c.normalizer) {
+		delta.names[name] = entry
+	}
+	return delta, true
+}
+
+// PrimeCache looks up one loaded name in the cached list, so the Redis
+// connections and list hash the screening path reads are warm before the
+// first request. It does nothing while the index is empty.
+func (c *OFACChecker) PrimeCache(ctx context.Context) error {
+	c.indexMu.RLock()
+	var name string
+	for name = range c.exactIndex {
+		break
+	}
+	c.indexMu.RUnlock()
+
+	if name == "" {
+		return nil
+	}
+	if _, err := c.cache.GetByExactName(ctx, name); err != nil {
+		return fmt.Errorf("prime ofac cache: %w", err)
+	}
 	return nil
 }
 
+// ListChanged reports whether the cached list is newer than the loaded index
+func (c *OFACChecker) ListChanged(ctx context.Context) (bool, error) {
+	updatedAt, err := c.cache.GetLastUpdate(ctx)
+	if err != nil {
+		return false, err
+	}
+	return updatedAt.After(c.ListUpdatedAt()), nil
+}
+
+// ListUpdatedAt returns the last-update timestamp of the loaded OFAC list,
+// or the zero time if it is unknown
+func (c *OFACChecker) ListUpdatedAt() time.Time {
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+	return c.listUpdatedAt
+}
+
+// ListVersion returns the version of the loaded OFAC list, which is zero
+// if none is loaded
+func (c *OFACChecker) ListVersion() ListVersion {
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+	return c.listVersion
+}
+
+// LastUpdate returns when the cached OFAC list was last refreshed, which is
+// later than ListUpdatedAt while a refresh awaits the next reload
+func (c *OFACChecker) LastUpdate(ctx context.Context) (time.Time, error) {
+	return c.cache.GetLastUpdate(ctx)
+}
+
+// IndexStatus reports whether the in-memory index is loaded and from which
+// list version
+func (c *OFACChecker) IndexStatus() IndexStatus {
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+	return newIndexStatus(len(c.entries), c.loadedAt, c.listUpdatedAt, c.listVersion)
+}
+
 // exactMatch checks the in-memory index
 func (c *OFACChecker) exactMatch(normalizedName string) (OFACEntry, bool) {
 	c.indexMu.RLock()
@@ -192,90 +736,3 @@ func normalizeName(name string) string {
 	// Normalize whitespace
 	return strings.Join(strings.Fields(result.String()), " ")
 }
-
-// jaroWinkler calculates Jaro-Winkler similarity between two strings
-// Returns value between 0 (no match) and 1 (exact match)
-func jaroWinkler(s1, s2 string) float64 {
-	if s1 == s2 {
-		return 1.0
-	}
-
-	if len(s1) == 0 || len(s2) == 0 {
-		return 0.0
-	}
-
-	// Calculate Jaro distance
-	matchDistance := max(len(s1), len(s2))/2 - 1
-	if matchDistance < 0 {
-		matchDistance = 0
-	}
-
-	s1Matches := make([]bool, len(s1))
-	s2Matches := make([]bool, len(s2))
-
-	matches := 0
-	transpositions := 0
-
-	for i := 0; i < len(s1); i++ {
-		start := max(0, i-matchDistance)
-		end := min(i+matchDistance+1, len(s2))
-
-		for j := start; j < end; j++ {
-			if s2Matches[j] || s1[i] != s2[j] {
-				continue
-			}
-			s1Matches[i] = true
-			s2Matches[j] = true
-			matches++
-			break
-		}
-	}
-
-	if matches == 0 {
-		return 0.0
-	}
-
-	k := 0
-	for i := 0; i < len(s1); i++ {
-		if !s1Matches[i] {
-			continue
-		}
-		for !s2Matches[k] {
-			k++
-		}
-		if s1[i] != s2[k] {
-			transpositions++
-		}
-		k++
-	}
-
-	jaro := (float64(matches)/float64(len(s1)) +
-		float64(matches)/float64(len(s2)) +
-		float64(matches-transpositions/2)/float64(matches)) / 3.0
-
-	// Calculate Winkler adjustment (prefix bonus)
-	prefix := 0
-	for i := 0; i < min(4, min(len(s1), len(s2))); i++ {
-		if s1[i] == s2[i] {
-			prefix++
-		} else {
-			break
-		}
-	}
-
-	return jaro + float64(prefix)*0.1*(1.0-jaro)
-}
-
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
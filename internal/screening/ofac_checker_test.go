@@ -0,0 +1,314 @@
+package screening
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// swappableOFACCache serves an OFAC list from memory that tests replace as
+// a list refresh would
+type swappableOFACCache struct {
+	normalizer *NameNormalizer
+	version    atomic.Int64
+	current    atomic.Pointer[snapshotOFACCache]
+}
+
+func newSwappableOFACCache(normalizer *NameNormalizer, entries []OFACEntry) *swappableOFACCache {
+	c := &swappableOFACCache{normalizer: normalizer}
+	c.set(entries)
+	return c
+}
+
+// set replaces the cached list with entries as a new version
+func (c *swappableOFACCache) set(entries []OFACEntry) {
+	version := c.version.Add(1)
+	c.current.Store(newSnapshotOFACCache(&ListSnapshot{
+		OFAC:          entries,
+		OFACUpdatedAt: time.Unix(version, 0),
+		OFACVersion:   version,
+	}, c.normalizer))
+}
+
+func (c *swappableOFACCache) GetByExactName(ctx context.Context, name string) (*OFACEntry, error) {
+	return c.current.Load().GetByExactName(ctx, name)
+}
+
+func (c *swappableOFACCache) GetByFuzzyName(ctx context.Context, name string, matcher NameMatcher, threshold float64) ([]OFACEntry, error) {
+	return c.current.Load().GetByFuzzyName(ctx, name, matcher, threshold)
+}
+
+func (c *swappableOFACCache) GetAllEntries(ctx context.Context) ([]OFACEntry, error) {
+	return c.current.Load().GetAllEntries(ctx)
+}
+
+func (c *swappableOFACCache) SetEntries(context.Context, []OFACEntry, time.Duration, ListWriteMode) error {
+	return errSnapshotReadOnly
+}
+
+func (c *swappableOFACCache) GetLastUpdate(ctx context.Context) (time.Time, error) {
+	return c.current.Load().GetLastUpdate(ctx)
+}
+
+func (c *swappableOFACCache) SetLastUpdate(context.Context, time.Time) error {
+	return errSnapshotReadOnly
+}
+
+func (c *swappableOFACCache) GetVersion(ctx context.Context) (ListVersion, error) {
+	return c.current.Load().GetVersion(ctx)
+}
+
+func (c *swappableOFACCache) RecordVersion(ctx context.Context, _ string, _ int, _ time.Time) (ListVersion, bool, error) {
+	version, err := c.current.Load().GetVersion(ctx)
+	return version, false, err
+}
+
+// failingLookupCache fails name lookups while failing is set, as an
+// unreachable Redis would, so checks fall back to the in-memory index
+type failingLookupCache struct {
+	*swappableOFACCache
+	failing atomic.Bool
+}
+
+func (c *failingLookupCache) GetByExactName(ctx context.Context, name string) (*OFACEntry, error) {
+	if c.failing.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return c.swappableOFACCache.GetByExactName(ctx, name)
+}
+
+// parkingMatcher parks the first similarity scan of probe, against
+// candidate when one is set, until release is closed, holding a check
+// mid-scan
+type parkingMatcher struct {
+	NameMatcher
+	probe     string
+	candidate string
+	once      sync.Once
+	parked    chan struct{}
+	release   chan struct{}
+}
+
+func (m *parkingMatcher) Similarity(a, b string) float64 {
+	if a == m.probe && (m.candidate == "" || b == m.candidate) {
+		m.once.Do(func() {
+			close(m.parked)
+			<-m.release
+		})
+	}
+	return m.NameMatcher.Similarity(a, b)
+}
+
+// testOFACEntries returns n distinct Individual entries
+func testOFACEntries(n int) []OFACEntry {
+	entries := make([]OFACEntry, n)
+	for i := range entries {
+		entries[i] = OFACEntry{
+			EntityID: fmt.Sprintf("%d", 10000+i),
+			Name:     fmt.Sprintf("Designated Person %c%c%c", 'A'+i%26, 'A'+i/26%26, 'A'+i/676%26),
+			Type:     "Individual",
+			Program:  "SDGT",
+		}
+	}
+	return entries
+}
+
+func newTestOFACChecker(t testing.TB, cache OFACCache, maxDelta int) *OFACChecker {
+	t.Helper()
+	matcher, err := NewNameMatcher("")
+	if err != nil {
+		t.Fatalf("NewNameMatcher: %v", err)
+	}
+	normalizer, err := NewNameNormalizer("", nil)
+	if err != nil {
+		t.Fatalf("NewNameNormalizer: %v", err)
+	}
+	return NewOFACChecker(cache, nil, matcher, normalizer, logger.NewNop(), 0.85, 8, maxDelta)
+}
+
+func TestReloadIndexRefusesOversizedDelta(t *testing.T) {
+	ctx := context.Background()
+	normalizer, _ := NewNameNormalizer("", nil)
+	entries := testOFACEntries(50)
+	cache := newSwappableOFACCache(normalizer, entries)
+	checker := newTestOFACChecker(t, cache, 10)
+
+	if err := checker.LoadIndex(ctx); err != nil {
+		t.Fatalf("initial LoadIndex: %v", err)
+	}
+	loaded := checker.ListVersion()
+
+	// A truncated download drops most of the list
+	cache.set(entries[:5])
+	delta, err := checker.ReloadIndex(ctx)
+	if !errors.Is(err, ErrOFACListRejected) {
+		t.Fatalf("ReloadIndex of a truncated list = %v, want ErrOFACListRejected", err)
+	}
+	if len(delta.Removed) != 45 {
+		t.Errorf("delta removed %d entities, want 45", len(delta.Removed))
+	}
+
+	if got := checker.IndexStatus().Entries; got != 50 {
+		t.Errorf("index holds %d entries after a refused reload, want the previous 50", got)
+	}
+	if got := checker.ListVersion(); got != loaded {
+		t.Errorf("list version moved to %+v after a refused reload, want %+v", got, loaded)
+	}
+	if match, found := checker.exactMatch(normalizer.Normalize(entries[40].Name)); !found || match.EntityID != entries[40].EntityID {
+		t.Errorf("entry dropped by the refused list no longer matches the live index")
+	}
+
+	// A change within the threshold is loaded
+	cache.set(append(entries, testOFACEntries(55)[50:]...))
+	if _, err := checker.ReloadIndex(ctx); err != nil {
+		t.Fatalf("ReloadIndex within the threshold: %v", err)
+	}
+	if got := checker.IndexStatus().Entries; got != 55 {
+		t.Errorf("index holds %d entries, want 55", got)
+	}
+}
+
+func TestCheckNotStalledByReloadDuringScan(t *testing.T) {
+	ctx := context.Background()
+	normalizer, _ := NewNameNormalizer("", nil)
+	base, err := NewNameMatcher("")
+	if err != nil {
+		t.Fatalf("NewNameMatcher: %v", err)
+	}
+	matcher := &parkingMatcher{
+		NameMatcher: base,
+		probe:       normalizer.Normalize("Unrelated Holdings Group"),
+		parked:      make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	released := false
+	defer func() {
+		if !released {
+			close(matcher.release)
+		}
+	}()
+
+	entries := testOFACEntries(50)
+	cache := &failingLookupCache{swappableOFACCache: newSwappableOFACCache(normalizer, entries)}
+	checker := NewOFACChecker(cache, nil, matcher, normalizer, logger.NewNop(), 0.85, 8, 0)
+	if err := checker.LoadIndex(ctx); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+
+	// A check falling back to the index while the cache is down parks
+	// mid-scan
+	cache.failing.Store(true)
+	go checker.Check(ctx, "Unrelated Holdings Group")
+	<-matcher.parked
+	cache.failing.Store(false)
+
+	cache.set(append(entries, testOFACEntries(51)[50]))
+	reloaded := make(chan error, 1)
+	go func() { reloaded <- checker.LoadIndex(ctx) }()
+
+	// Listed names are answered from the index throughout the reload
+	listed := entries[0].Name
+	slowest := make(chan time.Duration, 1)
+	go func() {
+		var worst time.Duration
+		deadline := time.Now().Add(20 * time.Millisecond)
+		for time.Now().Before(deadline) {
+			start := time.Now()
+			match, err := checker.Check(ctx, listed)
+			worst = max(worst, time.Since(start))
+			if err != nil || !match.Matched {
+				t.Errorf("Check(%q) = %+v, %v, want a match", listed, match, err)
+				break
+			}
+		}
+		slowest <- worst
+	}()
+
+	select {
+	case worst := <-slowest:
+		// On a single CPU the reload preempts the loop for as long as it
+		// runs; a Check queued behind it instead never returns
+		if min(runtime.NumCPU(), runtime.GOMAXPROCS(0)) > 1 && worst > 500*time.Microsecond {
+			t.Errorf("slowest Check took %s during the reload, want at most 500µs", worst)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Check blocked behind the reload waiting on a scan")
+	}
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("LoadIndex: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("LoadIndex blocked on a scan in progress")
+	}
+	if got := checker.IndexStatus().Entries; got != 51 {
+		t.Errorf("index holds %d entries after the reload, want 51", got)
+	}
+
+	released = true
+	close(matcher.release)
+}
+
+// BenchmarkCheckDuringBatch reports the p99 latency of live checks on
+// their own and while a large batch screens concurrently, which
+// batchWorkers bounds
+func BenchmarkCheckDuringBatch(b *testing.B) {
+	ctx := context.Background()
+	normalizer, _ := NewNameNormalizer("", nil)
+	entries := testOFACEntries(2000)
+	checker := newTestOFACChecker(b, newSwappableOFACCache(normalizer, entries), 0)
+	if err := checker.LoadIndex(ctx); err != nil {
+		b.Fatalf("LoadIndex: %v", err)
+	}
+
+	batch := make([]string, 5000)
+	for i := range batch {
+		batch[i] = fmt.Sprintf("Batch Customer %d", i)
+	}
+
+	for _, concurrent := range []bool{false, true} {
+		name := "idle"
+		if concurrent {
+			name = "with_batch"
+		}
+		b.Run(name, func(b *testing.B) {
+			batchCtx, stop := context.WithCancel(ctx)
+			var wg sync.WaitGroup
+			if concurrent {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for batchCtx.Err() == nil {
+						checker.CheckBatch(batchCtx, batch)
+					}
+				}()
+			}
+
+			latencies := make([]time.Duration, 0, b.N)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				start := time.Now()
+				if _, err := checker.Check(ctx, "Live Customer Name"); err != nil {
+					b.Fatalf("Check: %v", err)
+				}
+				latencies = append(latencies, time.Since(start))
+			}
+			b.StopTimer()
+			stop()
+			wg.Wait()
+
+			slices.Sort(latencies)
+			p99 := latencies[len(latencies)*99/100]
+			b.ReportMetric(float64(p99.Microseconds()), "p99-µs")
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package screening
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrOFACListRejected is returned by a reload that refused a list changing
+// more entities than the sanity threshold allows. Such a change usually
+// means a truncated or malformed download rather than a genuine update.
+var ErrOFACListRejected = errors.New("ofac list change exceeds sanity threshold")
+
+// OFACDelta describes how the SDN list changed between two index loads
+type OFACDelta struct {
+	// Initial is set for the first load, when there is nothing to compare against
+	Initial bool
+
+	Added    []OFACEntry // entities not present in the previous load
+	Modified []OFACEntry // entities whose name or aliases changed
+	Removed  []OFACEntry // entities dropped from the list, as last loaded
+
+	PreviousCount int
+	CurrentCount  int
+
+	// Version is the list version the delta leads to; zero when it is
+	// unknown or the list was refused
+	Version ListVersion
+
+	// Normalized names introduced by this delta, mapped to their entity
+	names map[string]OFACEntry
+
+	// matcher and normalizer are the OFAC checker's
+	matcher    NameMatcher
+	normalizer *NameNormalizer
+}
+
+// Size returns the total number of changed entities
+func (d *OFACDelta) Size() int {
+	return len(d.Added) + len(d.Modified) + len(d.Removed)
+}
+
+// IsEmpty returns true when no entity gained a new name or alias
+func (d *OFACDelta) IsEmpty() bool {
+	return len(d.names) == 0
+}
+
+// NameCount returns the number of new names and aliases to re-screen against
+func (d *OFACDelta) NameCount() int {
+	return len(d.names)
+}
+
+// Match fuzzy-matches a name against the names introduced by the delta and
+// returns the best matching entry at or above threshold
+func (d *OFACDelta) Match(name string, threshold float64) (OFACEntry, float64, bool) {
+	normalized := d.normalizer.Normalize(name)
+	if normalized == "" {
+		return OFACEntry{}, 0, false
+	}
+
+	if entry, ok := d.names[normalized]; ok {
+		return entry, 1.0, true
+	}
+
+	var best OFACEntry
+	bestScore := 0.0
+	for candidate, entry := range d.names {
+		if score := d.matcher.Similarity(normalized, candidate); score > bestScore {
+			best, bestScore = entry, score
+		}
+	}
+
+	if bestScore < threshold {
+		return OFACEntry{}, 0, false
+	}
+	return best, bestScore, true
+}
+
+// computeOFACDelta compares a fresh load against the previous entries keyed
+// by entryKey. Only names and aliases that did not exist before are
+// recorded for re-screening.
+func computeOFACDelta(previous map[string]OFACEntry, current []OFACEntry, matcher NameMatcher, normalizer *NameNormalizer) *OFACDelta {
+	delta := &OFACDelta{
+		Initial:       previous == nil,
+		PreviousCount: len(previous),
+		CurrentCount:  len(current),
+		names:         make(map[string]OFACEntry),
+		matcher:       matcher,
+		normalizer:    normalizer,
+	}
+	if delta.Initial {
+		return delta
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, entry := range current {
+		key := entryKey(entry)
+		seen[key] = true
+
+		old, existed := previous[key]
+		if !existed {
+			delta.Added = append(delta.Added, entry)
+			for _, name := range entryNames(entry, normalizer) {
+				delta.names[name] = entry
+			}
+			continue
+		}
+
+		oldNames := entryNames(old, normalizer)
+		var newNames []string
+		for _, name := range entryNames(entry, normalizer) {
+			if !slices.Contains(oldNames, name) {
+				newNames = append(newNames, name)
+			}
+		}
+		if len(newNames) > 0 {
+			delta.Modified = append(delta.Modified, entry)
+			for _, name := range newNames {
+				delta.names[name] = entry
+			}
+		}
+	}
+
+	for id, entry := range previous {
+		if !seen[id] {
+			delta.Removed = append(delta.Removed, entry)
+		}
+	}
+
+	return delta
+}
+
+// entryKey identifies an entity across loads, falling back to the
+// normalized name for entries without an entity ID
+func entryKey(entry OFACEntry) string {
+	if entry.EntityID != "" {
+		return entry.EntityID
+	}
+	return entry.NormalizedName
+}
+
+// entryNames returns the normalized primary name and aliases of an entry
+func entryNames(entry OFACEntry, n *NameNormalizer) []string {
+	names := make([]string, 0, len(entry.Aliases)+1)
+	primary := n.listName(entry.NormalizedName)
+	if primary == "" {
+		primary = n.Normalize(entry.Name)
+	}
+	names = append(names, primary)
+	for _, alias := range entry.Aliases {
+		names = append(names, n.Normalize(alias))
+	}
+	return names
+}
@@ -2,32 +2,67 @@ package screening
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/breaker"
+	"github.com/banking/aml-service/internal/pkg/fuzzy"
 	"github.com/banking/aml-service/internal/pkg/logger"
 )
 
 // PEPChecker performs Politically Exposed Persons screening
 // Target: <5ms per lookup using Redis cache
 type PEPChecker struct {
-	cache     PEPCache
-	log       *logger.Logger
-	threshold float64
+	cache      PEPCache
+	archive    ListArchiver
+	matcher    NameMatcher
+	normalizer *NameNormalizer
+	log        *logger.Logger
+	threshold  float64
 
-	// In-memory index for fast lookups
-	pepIndex map[string]PEPEntry
-	indexMu  sync.RWMutex
+	// partialThreshold is the least score a match with initials expanded
+	// or middle names left out needs, kept above threshold so common names
+	// reduced to initials do not over-match
+	partialThreshold float64
+
+	// matcher that folds cached list names as the normalizer does
+	listMatcher NameMatcher
+
+	// In-memory index for fast lookups, and the PEPs keyed by the normalized
+	// names of their relatives and close associates. As with the OFAC
+	// index, the maps are never modified once published: a reload builds
+	// new ones and swaps them in under a brief write lock, and scans take
+	// them under the read lock and release it before matching.
+	pepIndex       map[string]PEPEntry
+	associateIndex map[string]pepAssociate
+	indexMu        sync.RWMutex
+
+	// Last-update timestamp and version of the list the index was loaded
+	// from
+	listUpdatedAt time.Time
+	listVersion   ListVersion
+
+	// Serializes loads, so each new version is archived once
+	loadMu sync.Mutex
+
+	// When the index was last loaded and how many entries it held; loadedAt
+	// is zero until the first load
+	loadedAt time.Time
+	entries  int
 }
 
 // PEPCache interface for PEP data caching
 type PEPCache interface {
 	GetByName(ctx context.Context, name string) (*PEPEntry, error)
-	GetByFuzzyName(ctx context.Context, name string, threshold float64) ([]PEPEntry, error)
+	GetByFuzzyName(ctx context.Context, name string, matcher NameMatcher, threshold float64) ([]PEPEntry, error)
 	GetAllEntries(ctx context.Context) ([]PEPEntry, error)
 	SetEntries(ctx context.Context, entries []PEPEntry, ttl time.Duration) error
 	GetLastUpdate(ctx context.Context) (time.Time, error)
+	GetVersion(ctx context.Context) (ListVersion, error)
+	RecordVersion(ctx context.Context, hash string, entries int, at time.Time) (version ListVersion, created bool, err error)
 }
 
 // PEPEntry represents a Politically Exposed Person entry
@@ -46,13 +81,28 @@ type PEPEntry struct {
 	Associates     []string   `json:"associates,omitempty"` // Family, close associates
 }
 
-// NewPEPChecker creates a new PEP checker
-func NewPEPChecker(cache PEPCache, log *logger.Logger, threshold float64) *PEPChecker {
+// pepAssociate is a listed associate and the PEP they are associated with
+type pepAssociate struct {
+	name      string
+	principal PEPEntry
+}
+
+// NewPEPChecker creates a new PEP checker. Names matching a listed name
+// only with initials expanded or middle names left out must score at least
+// partialThreshold. Each new list version is archived when archive is not
+// nil.
+func NewPEPChecker(cache PEPCache, archive ListArchiver, matcher NameMatcher, normalizer *NameNormalizer, log *logger.Logger, threshold, partialThreshold float64) *PEPChecker {
 	return &PEPChecker{
-		cache:     cache,
-		log:       log.Named("pep_checker"),
-		threshold: threshold,
-		pepIndex:  make(map[string]PEPEntry),
+		cache:            cache,
+		archive:          archive,
+		matcher:          matcher,
+		normalizer:       normalizer,
+		log:              log.Named("pep_checker"),
+		threshold:        threshold,
+		partialThreshold: partialThreshold,
+		listMatcher:      normalizer.listMatcher(matcher),
+		pepIndex:         make(map[string]PEPEntry),
+		associateIndex:   make(map[string]pepAssociate),
 	}
 }
 
@@ -62,7 +112,7 @@ func (c *PEPChecker) Check(ctx context.Context, name string) (*domain.PEPMatch,
 		return &domain.PEPMatch{Matched: false}, nil
 	}
 
-	normalizedName := normalizeName(name)
+	normalizedName := c.normalizer.Normalize(name)
 
 	// 1. Check in-memory index first (fastest)
 	if match, found := c.exactMatch(normalizedName); found {
@@ -74,13 +124,17 @@ func (c *PEPChecker) Check(ctx context.Context, name string) (*domain.PEPMatch,
 			PEPName:      match.Name,
 			PEPPosition:  match.Position,
 			PEPCountry:   match.Country,
+			PEPEndDate:   match.EndDate,
 			RiskCategory: riskCategory,
 		}, nil
 	}
 
 	// 2. Try cache lookup
 	entry, err := c.cache.GetByName(ctx, normalizedName)
-	if err == nil && entry != nil {
+	if err != nil {
+		return c.degradedMatch(normalizedName, err)
+	}
+	if entry != nil {
 		riskCategory := c.determineRiskCategory(*entry)
 		return &domain.PEPMatch{
 			Matched:      true,
@@ -89,15 +143,30 @@ func (c *PEPChecker) Check(ctx context.Context, name string) (*domain.PEPMatch,
 			PEPName:      entry.Name,
 			PEPPosition:  entry.Position,
 			PEPCountry:   entry.Country,
+			PEPEndDate:   entry.EndDate,
 			RiskCategory: riskCategory,
 		}, nil
 	}
 
-	// 3. Fuzzy match
-	fuzzyMatches, err := c.cache.GetByFuzzyName(ctx, normalizedName, c.threshold)
-	if err == nil && len(fuzzyMatches) > 0 {
+	// 3. Partial match against the in-memory index, kept unless a fuzzy
+	// match scores higher
+	pepIndex, _ := c.indexes()
+	partial, partialScore, partialFound := c.partialMatch(normalizedName, pepIndex)
+
+	// 4. Fuzzy match
+	fuzzyMatches, err := c.cache.GetByFuzzyName(ctx, normalizedName, c.listMatcher, c.threshold)
+	if err != nil {
+		return c.degradedMatch(normalizedName, err)
+	}
+	var similarity float64
+	if len(fuzzyMatches) > 0 {
+		similarity = c.listMatcher.Similarity(normalizedName, fuzzyMatches[0].NormalizedName)
+	}
+	if partialFound && partialScore > similarity {
+		return c.pepMatch(partial, partialScore, domain.MatchTypePartial), nil
+	}
+	if len(fuzzyMatches) > 0 {
 		bestMatch := fuzzyMatches[0]
-		similarity := jaroWinkler(normalizedName, bestMatch.NormalizedName)
 		riskCategory := c.determineRiskCategory(bestMatch)
 
 		return &domain.PEPMatch{
@@ -107,6 +176,7 @@ func (c *PEPChecker) Check(ctx context.Context, name string) (*domain.PEPMatch,
 			PEPName:      bestMatch.Name,
 			PEPPosition:  bestMatch.Position,
 			PEPCountry:   bestMatch.Country,
+			PEPEndDate:   bestMatch.EndDate,
 			RiskCategory: riskCategory,
 		}, nil
 	}
@@ -114,22 +184,182 @@ func (c *PEPChecker) Check(ctx context.Context, name string) (*domain.PEPMatch,
 	return &domain.PEPMatch{Matched: false}, nil
 }
 
-// CheckWithAssociates also checks against known associates
-func (c *PEPChecker) CheckWithAssociates(ctx context.Context, name string) (*domain.PEPMatch, []string, error) {
+// degradedMatch screens against the in-memory index alone when the cache
+// breaker is open. Any other cache error, or an empty index, fails the check.
+func (c *PEPChecker) degradedMatch(normalizedName string, cacheErr error) (*domain.PEPMatch, error) {
+	if !breaker.IsOpen(cacheErr) {
+		return nil, fmt.Errorf("pep cache lookup: %w", cacheErr)
+	}
+
+	pepIndex, _ := c.indexes()
+	if len(pepIndex) == 0 {
+		return nil, fmt.Errorf("pep cache unavailable and index not loaded: %w", cacheErr)
+	}
+
+	match := c.matchIndex(normalizedName, pepIndex)
+	match.Degraded = true
+	return match, nil
+}
+
+// matchIndex screens a normalized name against an index, exactly and then
+// by similarity to every indexed name and alias
+func (c *PEPChecker) matchIndex(normalizedName string, pepIndex map[string]PEPEntry) *domain.PEPMatch {
+	matchType, bestScore := domain.MatchTypeExact, 1.0
+	best, found := pepIndex[normalizedName]
+	if !found {
+		matchType, bestScore = domain.MatchTypeFuzzy, 0
+		var bestName string
+		for candidate, entry := range pepIndex {
+			if score := c.matcher.Similarity(normalizedName, candidate); better(score, candidate, bestScore, bestName) {
+				best, bestName, bestScore = entry, candidate, score
+			}
+		}
+	}
+
+	if !found {
+		if partial, score, ok := c.partialMatch(normalizedName, pepIndex); ok && score > bestScore {
+			best, bestScore, matchType = partial, score, domain.MatchTypePartial
+		}
+	}
+
+	if bestScore < c.threshold {
+		return &domain.PEPMatch{Matched: false}
+	}
+	return c.pepMatch(best, bestScore, matchType)
+}
+
+// pepMatch describes a match against a listed PEP
+func (c *PEPChecker) pepMatch(entry PEPEntry, score float64, matchType domain.MatchType) *domain.PEPMatch {
+	return &domain.PEPMatch{
+		Matched:      true,
+		MatchScore:   score,
+		MatchType:    matchType,
+		PEPName:      entry.Name,
+		PEPPosition:  entry.Position,
+		PEPCountry:   entry.Country,
+		PEPEndDate:   entry.EndDate,
+		RiskCategory: c.determineRiskCategory(entry),
+	}
+}
+
+// partialMatch finds the indexed name a normalized name matches best with
+// initials expanded and middle names left out, scoring at least the
+// partial threshold. A best score shared by two different PEPs, as "j
+// smith" is by every John and James Smith, is ambiguous and matches
+// neither.
+func (c *PEPChecker) partialMatch(normalizedName string, pepIndex map[string]PEPEntry) (PEPEntry, float64, bool) {
+	space := strings.LastIndexByte(normalizedName, ' ')
+	if space < 0 {
+		return PEPEntry{}, 0, false
+	}
+	surname := normalizedName[space:]
+
+	var best PEPEntry
+	var bestScore float64
+	ambiguous := false
+	for candidate, entry := range pepIndex {
+		// Surnames must be equal, so most names are passed over cheaply
+		if !strings.HasSuffix(candidate, surname) {
+			continue
+		}
+		score := fuzzy.Initials(normalizedName, candidate)
+		switch {
+		case score < c.partialThreshold || score < bestScore:
+		case score > bestScore:
+			best, bestScore, ambiguous = entry, score, false
+		case !samePEP(entry, best):
+			ambiguous = true
+		}
+	}
+
+	if bestScore == 0 || ambiguous {
+		return PEPEntry{}, 0, false
+	}
+	return best, bestScore, true
+}
+
+// samePEP reports whether two index entries are the same listed person,
+// as a name and its aliases are
+func samePEP(a, b PEPEntry) bool {
+	if a.ID != "" || b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Name == b.Name
+}
+
+// CheckWithAssociates screens a name against the PEP list and, when it is
+// not a PEP, against the PEPs' relatives and close associates. Associates
+// are only held in the in-memory index.
+func (c *PEPChecker) CheckWithAssociates(ctx context.Context, name string) (*domain.PEPMatch, error) {
 	result, err := c.Check(ctx, name)
+	if err != nil || result.Matched || name == "" {
+		return result, err
+	}
+
+	_, associateIndex := c.indexes()
+	if match := c.matchAssociates(c.normalizer.Normalize(name), associateIndex); match != nil {
+		match.Degraded = result.Degraded
+		return match, nil
+	}
+	return result, nil
+}
+
+// CheckSourceWithAssociates is CheckWithAssociates against the list as
+// currently held in the cache, skipping the in-memory index, for requests
+// that bypass cached state. It reads the whole list, so it is much slower,
+// and a cache failure fails the check instead of falling back to the index.
+func (c *PEPChecker) CheckSourceWithAssociates(ctx context.Context, name string) (*domain.PEPMatch, error) {
+	if name == "" {
+		return &domain.PEPMatch{Matched: false}, nil
+	}
+
+	entries, err := c.cache.GetAllEntries(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, fmt.Errorf("pep cache lookup: %w", err)
+	}
+	pepIndex, associateIndex := c.buildIndex(entries)
+
+	normalizedName := c.normalizer.Normalize(name)
+	if match := c.matchIndex(normalizedName, pepIndex); match.Matched {
+		return match, nil
 	}
+	if match := c.matchAssociates(normalizedName, associateIndex); match != nil {
+		return match, nil
+	}
+	return &domain.PEPMatch{Matched: false}, nil
+}
 
-	if result.Matched {
-		// Get associates
-		entry, _ := c.cache.GetByName(ctx, normalizeName(name))
-		if entry != nil {
-			return result, entry.Associates, nil
+// matchAssociates matches a normalized name against an index of relatives
+// and close associates, returning nil when none is at or above threshold
+func (c *PEPChecker) matchAssociates(normalizedName string, associateIndex map[string]pepAssociate) *domain.PEPMatch {
+	matchType, score := domain.MatchTypeExact, 1.0
+	assoc, found := associateIndex[normalizedName]
+	if !found {
+		score = 0
+		var bestName string
+		for candidate, a := range associateIndex {
+			if s := c.matcher.Similarity(normalizedName, candidate); better(s, candidate, score, bestName) {
+				assoc, bestName, score = a, candidate, s
+			}
 		}
+		if score < c.threshold {
+			return nil
+		}
+		matchType = domain.MatchTypeFuzzy
 	}
 
-	return result, nil, nil
+	return &domain.PEPMatch{
+		Matched:       true,
+		MatchScore:    score,
+		MatchType:     matchType,
+		PEPName:       assoc.principal.Name,
+		PEPPosition:   assoc.principal.Position,
+		PEPCountry:    assoc.principal.Country,
+		PEPEndDate:    assoc.principal.EndDate,
+		RiskCategory:  "PEP_ASSOCIATE",
+		Associate:     true,
+		AssociateName: assoc.name,
+	}
 }
 
 // LoadIndex loads PEP list into in-memory index
@@ -139,21 +369,134 @@ func (c *PEPChecker) LoadIndex(ctx context.Context) error {
 		return err
 	}
 
+	updatedAt, err := c.cache.GetLastUpdate(ctx)
+	if err != nil {
+		c.log.Warn("pep list last update unavailable", logger.ErrorField(err))
+	}
+
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	pepIndex, associateIndex := c.buildIndex(entries)
+	version, created := c.recordVersion(ctx, entries)
+
 	c.indexMu.Lock()
-	defer c.indexMu.Unlock()
+	c.pepIndex, c.associateIndex = pepIndex, associateIndex
+	c.listUpdatedAt = updatedAt
+	c.listVersion = version
+	c.loadedAt = time.Now()
+	c.entries = len(entries)
+	c.indexMu.Unlock()
+
+	c.log.Info("pep index loaded",
+		logger.IntField("entries", len(entries)),
+		logger.Int64Field("version", version.Version),
+		logger.StringField("hash", version.Hash),
+	)
+
+	if created && c.archive != nil {
+		if err := c.archive.ArchivePEP(ctx, version, updatedAt, entries); err != nil {
+			c.log.Error("failed to archive pep list version",
+				logger.Int64Field("version", version.Version),
+				logger.ErrorField(err),
+			)
+		}
+	}
+	return nil
+}
+
+// recordVersion registers the entries' hash with the list cache and
+// returns the list version they are, and whether this load created it. If
+// the cache cannot be reached the version is left unknown.
+func (c *PEPChecker) recordVersion(ctx context.Context, entries []PEPEntry) (ListVersion, bool) {
+	hash := listHash(entries)
+	if hash == "" {
+		return ListVersion{}, false
+	}
+
+	version, created, err := c.cache.RecordVersion(ctx, hash, len(entries), time.Now())
+	if err != nil {
+		c.log.Warn("pep list version unavailable", logger.ErrorField(err))
+		return ListVersion{Hash: hash, Entries: len(entries)}, false
+	}
+	return version, created
+}
 
-	c.pepIndex = make(map[string]PEPEntry, len(entries))
+// buildIndex indexes entries by normalized name and aliases, and the PEPs
+// by the normalized names of their relatives and close associates
+func (c *PEPChecker) buildIndex(entries []PEPEntry) (pepIndex map[string]PEPEntry, associateIndex map[string]pepAssociate) {
+	pepIndex = make(map[string]PEPEntry, len(entries))
+	associateIndex = make(map[string]pepAssociate)
 	for _, entry := range entries {
-		c.pepIndex[entry.NormalizedName] = entry
+		pepIndex[c.normalizer.listName(entry.NormalizedName)] = entry
 		for _, alias := range entry.Aliases {
-			c.pepIndex[normalizeName(alias)] = entry
+			pepIndex[c.normalizer.Normalize(alias)] = entry
+		}
+		for _, associate := range entry.Associates {
+			associateIndex[c.normalizer.Normalize(associate)] = pepAssociate{name: associate, principal: entry}
 		}
 	}
+	return pepIndex, associateIndex
+}
 
-	c.log.Info("pep index loaded", logger.IntField("entries", len(entries)))
+// PrimeCache looks up one loaded name in the cached list, so the Redis
+// connections and list hash the screening path reads are warm before the
+// first request. It does nothing while the index is empty.
+func (c *PEPChecker) PrimeCache(ctx context.Context) error {
+	c.indexMu.RLock()
+	var name string
+	for name = range c.pepIndex {
+		break
+	}
+	c.indexMu.RUnlock()
+
+	if name == "" {
+		return nil
+	}
+	if _, err := c.cache.GetByName(ctx, name); err != nil {
+		return fmt.Errorf("prime pep cache: %w", err)
+	}
 	return nil
 }
 
+// ListUpdatedAt returns the last-update timestamp of the loaded PEP list,
+// or the zero time if it is unknown
+func (c *PEPChecker) ListUpdatedAt() time.Time {
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+	return c.listUpdatedAt
+}
+
+// ListVersion returns the version of the loaded PEP list, which is zero if
+// none is loaded
+func (c *PEPChecker) ListVersion() ListVersion {
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+	return c.listVersion
+}
+
+// LastUpdate returns when the cached PEP list was last refreshed, which is
+// later than ListUpdatedAt while a refresh awaits the next reload
+func (c *PEPChecker) LastUpdate(ctx context.Context) (time.Time, error) {
+	return c.cache.GetLastUpdate(ctx)
+}
+
+// IndexStatus reports whether the in-memory index is loaded and from which
+// list version
+func (c *PEPChecker) IndexStatus() IndexStatus {
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+	return newIndexStatus(c.entries, c.loadedAt, c.listUpdatedAt, c.listVersion)
+}
+
+// indexes returns the loaded name and associate indexes, to be scanned
+// without holding the lock
+func (c *PEPChecker) indexes() (map[string]PEPEntry, map[string]pepAssociate) {
+	c.indexMu.RLock()
+	defer c.indexMu.RUnlock()
+	return c.pepIndex, c.associateIndex
+}
+
 // exactMatch checks the in-memory index
 func (c *PEPChecker) exactMatch(normalizedName string) (PEPEntry, bool) {
 	c.indexMu.RLock()
@@ -0,0 +1,163 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// swappablePEPCache serves a PEP list from memory that tests replace as a
+// list refresh would
+type swappablePEPCache struct {
+	normalizer *NameNormalizer
+	version    atomic.Int64
+	current    atomic.Pointer[snapshotPEPCache]
+}
+
+func newSwappablePEPCache(normalizer *NameNormalizer, entries []PEPEntry) *swappablePEPCache {
+	c := &swappablePEPCache{normalizer: normalizer}
+	c.set(entries)
+	return c
+}
+
+// set replaces the cached list with entries as a new version
+func (c *swappablePEPCache) set(entries []PEPEntry) {
+	version := c.version.Add(1)
+	c.current.Store(newSnapshotPEPCache(&ListSnapshot{
+		PEP:          entries,
+		PEPUpdatedAt: time.Unix(version, 0),
+		PEPVersion:   version,
+	}, c.normalizer))
+}
+
+func (c *swappablePEPCache) GetByName(ctx context.Context, name string) (*PEPEntry, error) {
+	return c.current.Load().GetByName(ctx, name)
+}
+
+func (c *swappablePEPCache) GetByFuzzyName(ctx context.Context, name string, matcher NameMatcher, threshold float64) ([]PEPEntry, error) {
+	return c.current.Load().GetByFuzzyName(ctx, name, matcher, threshold)
+}
+
+func (c *swappablePEPCache) GetAllEntries(ctx context.Context) ([]PEPEntry, error) {
+	return c.current.Load().GetAllEntries(ctx)
+}
+
+func (c *swappablePEPCache) SetEntries(context.Context, []PEPEntry, time.Duration) error {
+	return errSnapshotReadOnly
+}
+
+func (c *swappablePEPCache) GetLastUpdate(ctx context.Context) (time.Time, error) {
+	return c.current.Load().GetLastUpdate(ctx)
+}
+
+func (c *swappablePEPCache) GetVersion(ctx context.Context) (ListVersion, error) {
+	return c.current.Load().GetVersion(ctx)
+}
+
+func (c *swappablePEPCache) RecordVersion(ctx context.Context, _ string, _ int, _ time.Time) (ListVersion, bool, error) {
+	version, err := c.current.Load().GetVersion(ctx)
+	return version, false, err
+}
+
+// testPEPEntries returns n distinct active PEPs, each with one associate
+func testPEPEntries(n int) []PEPEntry {
+	entries := make([]PEPEntry, n)
+	for i := range entries {
+		suffix := fmt.Sprintf("%c%c%c", 'A'+i%26, 'A'+i/26%26, 'A'+i/676%26)
+		entries[i] = PEPEntry{
+			ID:         fmt.Sprintf("PEP-%d", 10000+i),
+			Name:       "Exposed Person " + suffix,
+			Position:   "minister",
+			Country:    "GB",
+			Category:   "domestic",
+			IsActive:   true,
+			Associates: []string{"Close Associate " + suffix},
+		}
+	}
+	return entries
+}
+
+func TestPEPCheckNotStalledByReloadDuringScan(t *testing.T) {
+	ctx := context.Background()
+	normalizer, _ := NewNameNormalizer("", nil)
+	base, err := NewNameMatcher("")
+	if err != nil {
+		t.Fatalf("NewNameMatcher: %v", err)
+	}
+	entries := testPEPEntries(50)
+	matcher := &parkingMatcher{
+		NameMatcher: base,
+		probe:       normalizer.Normalize("Unrelated Holdings Group"),
+		candidate:   normalizer.Normalize(entries[0].Associates[0]),
+		parked:      make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	released := false
+	defer func() {
+		if !released {
+			close(matcher.release)
+		}
+	}()
+
+	cache := newSwappablePEPCache(normalizer, entries)
+	checker := NewPEPChecker(cache, nil, matcher, normalizer, logger.NewNop(), 0.85, 0.9)
+	if err := checker.LoadIndex(ctx); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+
+	// An unlisted name parks in the associate scan
+	go checker.CheckWithAssociates(ctx, "Unrelated Holdings Group")
+	<-matcher.parked
+
+	cache.set(append(entries, testPEPEntries(51)[50]))
+	reloaded := make(chan error, 1)
+	go func() { reloaded <- checker.LoadIndex(ctx) }()
+
+	// Listed names are answered from the index throughout the reload
+	listed := entries[0].Name
+	slowest := make(chan time.Duration, 1)
+	go func() {
+		var worst time.Duration
+		deadline := time.Now().Add(20 * time.Millisecond)
+		for time.Now().Before(deadline) {
+			start := time.Now()
+			match, err := checker.Check(ctx, listed)
+			worst = max(worst, time.Since(start))
+			if err != nil || !match.Matched {
+				t.Errorf("Check(%q) = %+v, %v, want a match", listed, match, err)
+				break
+			}
+		}
+		slowest <- worst
+	}()
+
+	select {
+	case worst := <-slowest:
+		// On a single CPU the reload preempts the loop for as long as it
+		// runs; a Check queued behind it instead never returns
+		if min(runtime.NumCPU(), runtime.GOMAXPROCS(0)) > 1 && worst > 500*time.Microsecond {
+			t.Errorf("slowest Check took %s during the reload, want at most 500µs", worst)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Check blocked behind the reload waiting on a scan")
+	}
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("LoadIndex: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("LoadIndex blocked on a scan in progress")
+	}
+	if got := checker.IndexStatus().Entries; got != 51 {
+		t.Errorf("index holds %d entries after the reload, want 51", got)
+	}
+
+	released = true
+	close(matcher.release)
+}
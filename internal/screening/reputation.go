@@ -0,0 +1,210 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// Reputation is what a provider knows about a transaction's IP and device
+type Reputation struct {
+	DeniedIP     bool   // IP is on a known-bad list
+	Anonymizer   bool   // IP is a Tor exit node or anonymizing proxy
+	DeniedDevice bool   // device is on a known-bad list
+	Source       string // provider that supplied the verdict
+}
+
+// ReputationProvider looks up IP and device reputation. Implementations may
+// be backed by a threat-intel feed or a local denylist; either argument may
+// be empty.
+type ReputationProvider interface {
+	Lookup(ctx context.Context, ip, deviceID string) (*Reputation, error)
+}
+
+// BlockedDeviceHistory counts previously blocked transactions made from a device
+type BlockedDeviceHistory interface {
+	CountBlockedByDevice(ctx context.Context, deviceID string, since time.Time) (int, error)
+}
+
+// ReputationChecker scores a transaction's IP address, device and location
+type ReputationChecker struct {
+	provider ReputationProvider
+	history  BlockedDeviceHistory
+	cfg      *config.ReputationConfig
+	log      *logger.Logger
+}
+
+// NewReputationChecker creates a new device and IP reputation checker
+func NewReputationChecker(provider ReputationProvider, history BlockedDeviceHistory, cfg *config.ReputationConfig, log *logger.Logger) *ReputationChecker {
+	return &ReputationChecker{
+		provider: provider,
+		history:  history,
+		cfg:      cfg,
+		log:      log.Named("reputation_checker"),
+	}
+}
+
+// Check returns the risk factors raised by the transaction's session details
+func (c *ReputationChecker) Check(ctx context.Context, tx *domain.Transaction) ([]domain.RiskFactor, error) {
+	var factors []domain.RiskFactor
+
+	if tx.IPAddress != "" || tx.DeviceID != "" {
+		rep, err := c.provider.Lookup(ctx, tx.IPAddress, tx.DeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("lookup reputation: %w", err)
+		}
+		if rep.DeniedIP {
+			factors = append(factors, domain.RiskFactor{
+				Factor:      "DENIED_IP",
+				Weight:      c.cfg.DeniedIPWeight,
+				Description: "Transaction originated from a known-bad IP address",
+				Details:     tx.IPAddress + " (" + rep.Source + ")",
+			})
+		}
+		if rep.Anonymizer {
+			factors = append(factors, domain.RiskFactor{
+				Factor:      "ANONYMIZER",
+				Weight:      c.cfg.AnonymizerWeight,
+				Description: "Transaction originated through Tor or an anonymizing proxy",
+				Details:     tx.IPAddress + " (" + rep.Source + ")",
+			})
+		}
+		if rep.DeniedDevice {
+			factors = append(factors, domain.RiskFactor{
+				Factor:      "DENIED_DEVICE",
+				Weight:      c.cfg.DeniedDeviceWeight,
+				Description: "Transaction originated from a known-bad device",
+				Details:     tx.DeviceID + " (" + rep.Source + ")",
+			})
+		}
+	}
+
+	if tx.DeviceID != "" {
+		since := time.Now().AddDate(0, 0, -c.cfg.BlockedLookbackDays)
+		blocked, err := c.history.CountBlockedByDevice(ctx, tx.DeviceID, since)
+		if err != nil {
+			return nil, fmt.Errorf("count blocked transactions for device: %w", err)
+		}
+		if blocked > 0 {
+			factors = append(factors, domain.RiskFactor{
+				Factor:      "BLOCKED_DEVICE",
+				Weight:      c.cfg.BlockedDeviceWeight,
+				Description: "Device was used for previously blocked transactions",
+				Details:     fmt.Sprintf("%d blocked in the last %d days", blocked, c.cfg.BlockedLookbackDays),
+			})
+		}
+	}
+
+	if geo := geoCountry(tx.GeoLocation); geo != "" && tx.SenderCountry != "" && !strings.EqualFold(geo, tx.SenderCountry) {
+		factors = append(factors, domain.RiskFactor{
+			Factor:      "GEO_MISMATCH",
+			Weight:      c.cfg.GeoMismatchWeight,
+			Description: "Device location does not match the sender country",
+			Details:     geo + " vs " + tx.SenderCountry,
+		})
+	}
+
+	return factors, nil
+}
+
+// geoCountry extracts the ISO country code leading a geo location such as
+// "US", "US-CA" or "US/San Francisco". Locations that do not start with a
+// country code, such as raw coordinates, are ignored.
+func geoCountry(location string) string {
+	code, _, _ := strings.Cut(strings.TrimSpace(location), "-")
+	code, _, _ = strings.Cut(code, "/")
+	code, _, _ = strings.Cut(code, ",")
+	code = strings.TrimSpace(code)
+	if len(code) != 2 {
+		return ""
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return ""
+		}
+	}
+	return strings.ToUpper(code)
+}
+
+// DenylistProvider is a ReputationProvider backed by the locally configured
+// IP ranges and device IDs
+type DenylistProvider struct {
+	deniedIPs   []*net.IPNet
+	anonymizers []*net.IPNet
+	devices     map[string]bool
+}
+
+// NewDenylistProvider builds a provider from the configured denylists
+func NewDenylistProvider(cfg *config.ReputationConfig) (*DenylistProvider, error) {
+	deniedIPs, err := parseNetworks(cfg.DeniedIPs)
+	if err != nil {
+		return nil, fmt.Errorf("denied ips: %w", err)
+	}
+	anonymizers, err := parseNetworks(cfg.AnonymizerRanges)
+	if err != nil {
+		return nil, fmt.Errorf("anonymizer ranges: %w", err)
+	}
+
+	devices := make(map[string]bool, len(cfg.DeniedDevices))
+	for _, d := range cfg.DeniedDevices {
+		devices[d] = true
+	}
+
+	return &DenylistProvider{
+		deniedIPs:   deniedIPs,
+		anonymizers: anonymizers,
+		devices:     devices,
+	}, nil
+}
+
+// Lookup checks the IP and device against the local denylists
+func (p *DenylistProvider) Lookup(_ context.Context, ip, deviceID string) (*Reputation, error) {
+	rep := &Reputation{Source: "local_denylist"}
+	if parsed := net.ParseIP(ip); parsed != nil {
+		rep.DeniedIP = containsIP(p.deniedIPs, parsed)
+		rep.Anonymizer = containsIP(p.anonymizers, parsed)
+	}
+	rep.DeniedDevice = deviceID != "" && p.devices[deviceID]
+	return rep, nil
+}
+
+// parseNetworks parses IPs and CIDR ranges; a bare IP matches only itself
+func parseNetworks(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %q: %w", entry, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
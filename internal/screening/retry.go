@@ -0,0 +1,53 @@
+package screening
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+)
+
+// retryOFACCache retries an OFACCache's name lookups
+type retryOFACCache struct {
+	OFACCache
+	cfg config.RetryConfig
+}
+
+// WithOFACRetry retries failed exact and fuzzy name lookups on an OFAC
+// cache, so a Redis blip of a few tens of milliseconds does not fail the
+// check. Other calls are passed through. Wrap the result in the breaker,
+// so that a lookup counts once towards it however often it was tried.
+func WithOFACRetry(next OFACCache, cfg config.RetryConfig) OFACCache {
+	return &retryOFACCache{OFACCache: next, cfg: cfg}
+}
+
+func (c *retryOFACCache) GetByExactName(ctx context.Context, name string) (*OFACEntry, error) {
+	return retryOFACLookup(ctx, c.cfg, func() (*OFACEntry, error) { return c.OFACCache.GetByExactName(ctx, name) })
+}
+
+func (c *retryOFACCache) GetByFuzzyName(ctx context.Context, name string, matcher NameMatcher, threshold float64) ([]OFACEntry, error) {
+	return retryOFACLookup(ctx, c.cfg, func() ([]OFACEntry, error) { return c.OFACCache.GetByFuzzyName(ctx, name, matcher, threshold) })
+}
+
+// retryOFACLookup calls fn until it succeeds, the attempts run out or ctx
+// is done, returning the last result
+func retryOFACLookup[T any](ctx context.Context, cfg config.RetryConfig, fn func() (T, error)) (T, error) {
+	v, err := fn()
+	for i := 0; err != nil && i < cfg.Attempts; i++ {
+		metrics.RecordOFACCacheFailure("retried")
+
+		wait := cfg.Backoff << i
+		wait = wait/2 + rand.N(wait+1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return v, err
+		case <-timer.C:
+		}
+		v, err = fn()
+	}
+	return v, err
+}
@@ -1,16 +1,59 @@
 package screening
 
 import (
+	"fmt"
+	"strings"
+	"sync"
+
 	"github.com/banking/aml-service/internal/config"
 	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/money"
 )
 
+// CurrencyConverter converts transaction amounts to the base currency that
+// amount thresholds are set in
+type CurrencyConverter interface {
+	Base() string
+	Convert(amount money.Amount, currency string) (money.Amount, bool)
+	ConvertTo(amount money.Amount, from, to string) (money.Amount, bool)
+}
+
 // RiskCalculator calculates risk scores based on multiple factors
 type RiskCalculator struct {
-	cfg               *config.PatternsConfig
-	highRiskCountries map[string]bool
+	cfg         *config.PatternsConfig
+	countryRisk *CountryRisk
+	converter   CurrencyConverter
+	log         *logger.Logger
+	rules       []config.TransactionRuleConfig
+
+	// Currency transaction reporting threshold in the base currency that
+	// threshold-adjacent amounts are measured against
+	ctrThreshold float64
+
+	// Compiled patterns.description_keywords
+	keywords []descriptionKeyword
+
+	// Unknown type/channel values already warned about
+	warned sync.Map
 }
 
+// Known transaction attributes; anything else falls back to default scoring
+var (
+	knownTransactionTypes = map[string]bool{
+		domain.TransactionTypeTransfer:   true,
+		domain.TransactionTypeDeposit:    true,
+		domain.TransactionTypeWithdrawal: true,
+		domain.TransactionTypePayment:    true,
+	}
+	knownChannels = map[string]bool{
+		domain.ChannelMobile: true,
+		domain.ChannelWeb:    true,
+		domain.ChannelBranch: true,
+		domain.ChannelAPI:    true,
+	}
+)
+
 // RiskWeight defines weights for different risk factors
 type RiskWeight struct {
 	Factor   string
@@ -21,11 +64,13 @@ type RiskWeight struct {
 // Default risk weights
 var defaultRiskWeights = map[string]RiskWeight{
 	"OFAC_MATCH":        {Factor: "OFAC_MATCH", MaxScore: 100, Weight: 1.0},
+	"SANCTIONED_BANK":   {Factor: "SANCTIONED_BANK", MaxScore: 100, Weight: 1.0},
 	"PEP_MATCH":         {Factor: "PEP_MATCH", MaxScore: 40, Weight: 0.8},
+	"PEP_ASSOCIATE":     {Factor: "PEP_ASSOCIATE", MaxScore: 20, Weight: 0.6},
 	"USER_WATCHLIST":    {Factor: "USER_WATCHLIST", MaxScore: 30, Weight: 0.7},
 	"USER_PEP":          {Factor: "USER_PEP", MaxScore: 25, Weight: 0.6},
 	"PRIOR_SARS":        {Factor: "PRIOR_SARS", MaxScore: 20, Weight: 0.5},
-	"HIGH_RISK_COUNTRY": {Factor: "HIGH_RISK_COUNTRY", MaxScore: 20, Weight: 0.5},
+	"HIGH_RISK_COUNTRY": {Factor: "HIGH_RISK_COUNTRY", MaxScore: 40, Weight: 0.5},
 	"HIGH_AMOUNT":       {Factor: "HIGH_AMOUNT", MaxScore: 15, Weight: 0.4},
 	"VELOCITY_SPIKE":    {Factor: "VELOCITY_SPIKE", MaxScore: 20, Weight: 0.5},
 	"STRUCTURING":       {Factor: "STRUCTURING", MaxScore: 35, Weight: 0.8},
@@ -35,18 +80,48 @@ var defaultRiskWeights = map[string]RiskWeight{
 	"SMURFING":          {Factor: "SMURFING", MaxScore: 30, Weight: 0.7},
 	"UNUSUAL_TIME":      {Factor: "UNUSUAL_TIME", MaxScore: 10, Weight: 0.3},
 	"CROSS_BORDER":      {Factor: "CROSS_BORDER", MaxScore: 10, Weight: 0.3},
+	"DENIED_IP":         {Factor: "DENIED_IP", MaxScore: 30, Weight: 0.7},
+	"ANONYMIZER":        {Factor: "ANONYMIZER", MaxScore: 20, Weight: 0.5},
+	"DENIED_DEVICE":     {Factor: "DENIED_DEVICE", MaxScore: 30, Weight: 0.7},
+	"BLOCKED_DEVICE":    {Factor: "BLOCKED_DEVICE", MaxScore: 25, Weight: 0.6},
+	"GEO_MISMATCH":      {Factor: "GEO_MISMATCH", MaxScore: 15, Weight: 0.4},
+	"UNKNOWN_CURRENCY":  {Factor: "UNKNOWN_CURRENCY", MaxScore: 10, Weight: 0.4},
+	"PROFILE_AMOUNT":    {Factor: "PROFILE_AMOUNT", MaxScore: 20, Weight: 0.5},
+	"NEW_COUNTRY":       {Factor: "NEW_COUNTRY", MaxScore: 10, Weight: 0.3},
+	"TRANSACTION_RULE":  {Factor: "TRANSACTION_RULE", MaxScore: 20, Weight: 1.0},
+
+	"COUNTRY_RISK_RATING":  {Factor: "COUNTRY_RISK_RATING", MaxScore: 40, Weight: 0.5},
+	"IP_HIGH_RISK_COUNTRY": {Factor: "IP_HIGH_RISK_COUNTRY", MaxScore: 20, Weight: 0.5},
+	"IP_COUNTRY_MISMATCH":  {Factor: "IP_COUNTRY_MISMATCH", MaxScore: 10, Weight: 0.3},
+	"IMPOSSIBLE_TRAVEL":    {Factor: "IMPOSSIBLE_TRAVEL", MaxScore: 25, Weight: 0.6},
+	"SHARED_DEVICE":        {Factor: "SHARED_DEVICE", MaxScore: 25, Weight: 0.6},
+
+	"COUNTERPARTY_REPUTATION": {Factor: "COUNTERPARTY_REPUTATION", MaxScore: 40, Weight: 0.7},
+	"DORMANT_REACTIVATION":    {Factor: "DORMANT_REACTIVATION", MaxScore: 25, Weight: 0.6},
+	"ROUND_AMOUNT":            {Factor: "ROUND_AMOUNT", MaxScore: 10, Weight: 0.3},
+	"THRESHOLD_ADJACENT":      {Factor: "THRESHOLD_ADJACENT", MaxScore: 15, Weight: 0.4},
+	"REPEATED_AMOUNT":         {Factor: "REPEATED_AMOUNT", MaxScore: 10, Weight: 0.3},
+	"DESCRIPTION_KEYWORD":     {Factor: "DESCRIPTION_KEYWORD", MaxScore: 25, Weight: 0.6},
 }
 
-// NewRiskCalculator creates a new risk calculator
-func NewRiskCalculator(cfg *config.PatternsConfig) *RiskCalculator {
-	highRiskCountries := make(map[string]bool)
-	for _, country := range cfg.HighRiskCountries {
-		highRiskCountries[country] = true
+// NewRiskCalculator creates a new risk calculator. Counterparty countries
+// are scored against the table countryRisk holds; a nil countryRisk uses
+// the table in cfg. ctrThreshold is the currency transaction reporting
+// threshold in the base currency.
+func NewRiskCalculator(cfg *config.PatternsConfig, countryRisk *CountryRisk, converter CurrencyConverter, ctrThreshold float64, log *logger.Logger) *RiskCalculator {
+	if countryRisk == nil {
+		countryRisk = NewCountryRisk(ConfiguredCountryRiskTable(cfg))
 	}
 
+	log = log.Named("risk_calculator")
 	return &RiskCalculator{
-		cfg:               cfg,
-		highRiskCountries: highRiskCountries,
+		cfg:          cfg,
+		countryRisk:  countryRisk,
+		converter:    converter,
+		log:          log,
+		rules:        cfg.TransactionRules,
+		ctrThreshold: ctrThreshold,
+		keywords:     compileDescriptionKeywords(cfg.DescriptionKeywords, log),
 	}
 }
 
@@ -59,38 +134,107 @@ func (c *RiskCalculator) Calculate(sctx *ScreeningContext) int {
 		totalScore += factor.Weight
 	}
 
-	// 2. Add transaction-specific risk factors
+	// 2. Add transaction-specific risk factors. These are recorded on the
+	// context after summing so each contributes exactly once.
 	tx := sctx.Transaction
 
-	// High-risk country check
-	if c.isHighRiskCountry(tx.GetCounterpartyCountry()) {
-		totalScore += 20
+	// Country risk tier of the counterparty, or failing that its rating
+	countryRisk := c.countryRisk.index()
+	sctx.CountryRiskVersion = countryRisk.table.Version
+	country := tx.GetCounterpartyCountry()
+	tier := countryRisk.tier(country)
+	if settings := sctx.TenantSettings; settings != nil && len(settings.CountryRiskTiers) > 0 {
+		// The tenant's tiers replace the table's; its ratings still apply
+		tier = settings.CountryTier(country)
+	}
+	if tier != nil {
+		sctx.RiskFactors = append(sctx.RiskFactors, domain.RiskFactor{
+			Factor:      "HIGH_RISK_COUNTRY",
+			Weight:      tier.Points,
+			Description: fmt.Sprintf("Counterparty is in a %s jurisdiction", tier.Name),
+			Details:     country,
+			Tier:        tier.Name,
+			EDDRequired: tier.EDDRequired,
+		})
+		totalScore += tier.Points
+	} else if rating, points := countryRisk.table.RatingPointsFor(country); points > 0 {
+		totalScore += c.addFactor(sctx, "COUNTRY_RISK_RATING", points,
+			fmt.Sprintf("Counterparty country is rated %d/100 for AML risk", rating), country)
 	}
 
 	// Cross-border transaction
 	if tx.IsCrossBorder() {
-		totalScore += 5
+		totalScore += c.addFactor(sctx, "CROSS_BORDER", 5, "Transaction crosses borders",
+			tx.SenderCountry+"->"+tx.ReceiverCountry)
 	}
 
-	// High value transaction (>$10K)
-	if tx.IsHighValue(10000) {
-		if tx.Amount >= 50000 {
-			totalScore += 15
-		} else {
-			totalScore += 10
+	// Transaction type/channel rule
+	rule := c.matchRule(tx)
+	highValueThreshold := c.cfg.HighValueThreshold
+	if highValueThreshold <= 0 {
+		highValueThreshold = 10000
+	}
+	if rule != nil {
+		if rule.HighValueThreshold > 0 {
+			highValueThreshold = rule.HighValueThreshold
 		}
+		totalScore += c.addFactor(sctx, "TRANSACTION_RULE", rule.ScoreAdjustment,
+			fmt.Sprintf("Rule %s applied for %s/%s/%s", rule.Name, tx.Type, tx.Channel, tx.Direction),
+			rule.Name)
 	}
 
+	// High value transaction (>$10K by default). Thresholds are set in the
+	// base currency; an amount that cannot be converted is flagged rather
+	// than let through unchecked.
+	threshold := money.FromFloat(highValueThreshold)
+	baseAmount, converted := c.converter.Convert(tx.Amount, tx.Currency)
+	if !converted {
+		totalScore += c.addAmountFactor(sctx, "UNKNOWN_CURRENCY", 10,
+			"Currency has no exchange rate; amount thresholds could not be applied",
+			fmt.Sprintf("%s %q", tx.Amount, tx.Currency), 0, false)
+	} else if baseAmount >= threshold {
+		weight := 10
+		if baseAmount >= threshold*5 {
+			weight = 15
+		}
+		totalScore += c.addAmountFactor(sctx, "HIGH_AMOUNT", weight, "Transaction amount exceeds high-value threshold",
+			fmt.Sprintf("%s %s (%s %s)", tx.Amount, tx.Currency, baseAmount.StringFixed(2), c.converter.Base()), baseAmount, true)
+	}
+
+	// Round, threshold-adjacent and repeated amounts
+	totalScore += c.calculateAmountHeuristics(sctx, baseAmount, converted)
+
+	// Watchlisted terms in the description or reference
+	totalScore += c.calculateDescriptionKeywords(sctx)
+
 	// 3. Velocity-based risk factors
 	if sctx.VelocityData != nil {
-		velocityScore := c.calculateVelocityRisk(sctx.VelocityData, tx)
-		totalScore += velocityScore
+		if velocityScore, details := c.calculateVelocityRisk(sctx.VelocityData, tx); velocityScore > 0 {
+			totalScore += c.addFactor(sctx, "VELOCITY_SPIKE", velocityScore, "Transaction velocity exceeds baseline", details)
+		}
 	}
 
-	// 4. Profile-based adjustments
-	if sctx.RiskProfile != nil {
-		profileScore := c.calculateProfileRisk(sctx.RiskProfile)
-		totalScore += profileScore
+	// 4. Profile-based adjustments, and anomalies against the user's
+	// history that do not depend on velocity data
+	if profile := sctx.RiskProfile; profile != nil {
+		totalScore += c.calculateProfileRisk(profile)
+
+		if converted {
+			if amountScore, details := c.calculateProfileAmountRisk(profile, baseAmount); amountScore > 0 {
+				totalScore += c.addAmountFactor(sctx, "PROFILE_AMOUNT", amountScore,
+					"Transaction amount is far above the user's historical average", details, baseAmount, true)
+			}
+		}
+
+		if country := tx.GetCounterpartyCountry(); tx.IsCrossBorder() && isNewCountry(profile, country) {
+			totalScore += c.addFactor(sctx, "NEW_COUNTRY", 10,
+				"Cross-border transaction with a country outside the user's primary countries", country)
+		}
+
+		if isNewCountry(profile, sctx.IPCountry) {
+			totalScore += c.addFactor(sctx, "IP_COUNTRY_MISMATCH", 10,
+				"IP address is located outside the user's primary countries", sctx.IPCountry)
+		}
 	}
 
 	// 5. Pattern-based scores (already included via RiskFactors)
@@ -106,6 +250,36 @@ func (c *RiskCalculator) Calculate(sctx *ScreeningContext) int {
 	return totalScore
 }
 
+// addFactor records a risk factor on the context and returns its weight
+func (c *RiskCalculator) addFactor(sctx *ScreeningContext, factor string, weight int, description, details string) int {
+	sctx.RiskFactors = append(sctx.RiskFactors, domain.RiskFactor{
+		Factor:      factor,
+		Weight:      weight,
+		Description: description,
+		Details:     details,
+	})
+	return weight
+}
+
+// addAmountFactor records an amount-based risk factor carrying the
+// transaction amount as screened and, when converted, in the base currency
+func (c *RiskCalculator) addAmountFactor(sctx *ScreeningContext, factor string, weight int, description, details string, baseAmount money.Amount, converted bool) int {
+	f := domain.RiskFactor{
+		Factor:           factor,
+		Weight:           weight,
+		Description:      description,
+		Details:          details,
+		OriginalAmount:   sctx.Transaction.Amount,
+		OriginalCurrency: sctx.Transaction.Currency,
+	}
+	if converted {
+		f.ConvertedAmount = baseAmount
+		f.ConvertedCurrency = c.converter.Base()
+	}
+	sctx.RiskFactors = append(sctx.RiskFactors, f)
+	return weight
+}
+
 // CalculateFromFactors calculates score from a list of risk factors
 func (c *RiskCalculator) CalculateFromFactors(factors []domain.RiskFactor) int {
 	totalScore := 0
@@ -127,26 +301,92 @@ func (c *RiskCalculator) CalculateFromFactors(factors []domain.RiskFactor) int {
 	return totalScore
 }
 
-// isHighRiskCountry checks if a country is considered high-risk
-func (c *RiskCalculator) isHighRiskCountry(country string) bool {
-	if country == "" {
-		return false
+// matchRule returns the most specific transaction rule for a transaction.
+// Rules with more populated fields win; ties go to the first configured rule.
+func (c *RiskCalculator) matchRule(tx *domain.Transaction) *config.TransactionRuleConfig {
+	if !knownTransactionTypes[tx.Type] {
+		c.warnUnknown("type", tx.Type)
+		return nil
+	}
+	if !knownChannels[tx.Channel] {
+		c.warnUnknown("channel", tx.Channel)
+		return nil
 	}
-	return c.highRiskCountries[country]
+
+	var best *config.TransactionRuleConfig
+	bestSpecificity := -1
+	for i := range c.rules {
+		rule := &c.rules[i]
+		if !ruleFieldMatches(rule.Type, tx.Type) ||
+			!ruleFieldMatches(rule.Channel, tx.Channel) ||
+			!ruleFieldMatches(rule.Direction, tx.Direction) {
+			continue
+		}
+
+		specificity := 0
+		for _, field := range []string{rule.Type, rule.Channel, rule.Direction} {
+			if field != "" {
+				specificity++
+			}
+		}
+		if specificity > bestSpecificity {
+			best = rule
+			bestSpecificity = specificity
+		}
+	}
+
+	return best
 }
 
-// calculateVelocityRisk calculates risk based on velocity anomalies
-func (c *RiskCalculator) calculateVelocityRisk(velocity *domain.VelocityData, tx *domain.Transaction) int {
-	score := 0
+// ruleFieldMatches treats an empty rule field as a wildcard
+func ruleFieldMatches(ruleValue, txValue string) bool {
+	return ruleValue == "" || ruleValue == txValue
+}
+
+// warnUnknown logs an unknown transaction attribute once per value
+func (c *RiskCalculator) warnUnknown(attribute, value string) {
+	if _, loaded := c.warned.LoadOrStore(attribute+":"+value, true); loaded {
+		return
+	}
+	c.log.Warn("unknown transaction attribute, using default scoring",
+		logger.StringField("attribute", attribute),
+		logger.StringField("value", value),
+	)
+}
 
-	// Check for velocity spike (10x normal)
-	if velocity.AvgDailyAmount > 0 {
-		ratio := (velocity.AmountDay + tx.Amount) / velocity.AvgDailyAmount
+// calculateVelocityRisk calculates risk based on velocity anomalies and
+// describes what tripped
+func (c *RiskCalculator) calculateVelocityRisk(velocity *domain.VelocityData, tx *domain.Transaction) (int, string) {
+	score := 0
+	var details []string
+	// Baselines are statistics, so the exact daily sum is compared as a float
+	dayAmount := (velocity.AmountDay + tx.Amount).Float64()
+
+	if velocity.BaselineDays >= c.cfg.VelocityMinHistoryDays && c.cfg.VelocityStdDevMultiplier > 0 {
+		// Enough history: measure the day against the user's own spread,
+		// so customers who routinely move large sums are not flagged for it
+		n := c.cfg.VelocityStdDevMultiplier
+		threshold := velocity.AvgDailyAmount + n*velocity.StdDevDailyAmount
+		if dayAmount > threshold {
+			score += 20
+			details = append(details, fmt.Sprintf("daily amount %.2f exceeds mean %.2f + %.1f stddev (%.2f) over %d days",
+				dayAmount, velocity.AvgDailyAmount, n, velocity.StdDevDailyAmount, velocity.BaselineDays))
+		} else if dayAmount > velocity.AvgDailyAmount+n/2*velocity.StdDevDailyAmount && velocity.StdDevDailyAmount > 0 {
+			score += 10
+			details = append(details, fmt.Sprintf("daily amount %.2f exceeds mean %.2f + %.1f stddev (%.2f)",
+				dayAmount, velocity.AvgDailyAmount, n/2, velocity.StdDevDailyAmount))
+		}
+	} else if velocity.AvgDailyAmount > 0 {
+		// Too little history for a stable spread; fall back to a multiple of the mean
+		ratio := dayAmount / velocity.AvgDailyAmount
 		if ratio >= c.cfg.VelocitySpikeMultiplier {
 			score += 20 // Significant velocity spike
-		} else if ratio >= 5.0 {
+		} else if ratio >= c.cfg.VelocitySpikeMultiplier/2 {
 			score += 10 // Moderate velocity spike
 		}
+		if score > 0 {
+			details = append(details, fmt.Sprintf("daily amount %.1fx the mean of %.2f", ratio, velocity.AvgDailyAmount))
+		}
 	}
 
 	// Check transaction count spike
@@ -154,10 +394,11 @@ func (c *RiskCalculator) calculateVelocityRisk(velocity *domain.VelocityData, tx
 		txRatio := float64(velocity.TxCountDay+1) / velocity.AvgDailyTxCount
 		if txRatio >= c.cfg.VelocitySpikeMultiplier {
 			score += 10
+			details = append(details, fmt.Sprintf("daily count %.1fx the mean of %.1f", txRatio, velocity.AvgDailyTxCount))
 		}
 	}
 
-	return score
+	return score, strings.Join(details, "; ")
 }
 
 // calculateProfileRisk adds risk based on user profile
@@ -184,6 +425,53 @@ func (c *RiskCalculator) calculateProfileRisk(profile *domain.UserRiskProfile) i
 	return score
 }
 
+// calculateProfileAmountRisk compares a base-currency amount with the
+// user's average transaction amount and monthly volume from the risk
+// profile and describes what tripped. Users without history score nothing.
+func (c *RiskCalculator) calculateProfileAmountRisk(profile *domain.UserRiskProfile, baseAmount money.Amount) (int, string) {
+	if profile.AvgTransactionAmt <= 0 {
+		return 0, ""
+	}
+
+	score := 0
+	var details []string
+	amount := baseAmount.Float64()
+	n := c.cfg.ProfileAmountMultiplier
+
+	ratio := amount / profile.AvgTransactionAmt
+	if ratio >= n {
+		score += 15
+	} else if ratio >= n/2 {
+		score += 8
+	}
+	if score > 0 {
+		details = append(details, fmt.Sprintf("amount %.2f is %.1fx the average of %.2f",
+			amount, ratio, profile.AvgTransactionAmt))
+	}
+
+	// A single transaction larger than a typical month's volume
+	if score > 0 && profile.AvgMonthlyVolume > 0 && amount > profile.AvgMonthlyVolume {
+		score += 5
+		details = append(details, fmt.Sprintf("exceeds the average monthly volume of %.2f", profile.AvgMonthlyVolume))
+	}
+
+	return score, strings.Join(details, "; ")
+}
+
+// isNewCountry returns true if the user has primary countries on record
+// and the country is not one of them
+func isNewCountry(profile *domain.UserRiskProfile, country string) bool {
+	if country == "" || len(profile.PrimaryCountries) == 0 {
+		return false
+	}
+	for _, c := range profile.PrimaryCountries {
+		if strings.EqualFold(c, country) {
+			return false
+		}
+	}
+	return true
+}
+
 // GetRiskThresholds returns the thresholds for risk levels
 func GetRiskThresholds() map[string]int {
 	return map[string]int{
@@ -0,0 +1,49 @@
+package screening
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/currency"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+func TestShadowScoreAppliesTenantThresholds(t *testing.T) {
+	converter := currency.NewConverter(&config.CurrencyConfig{BaseCurrency: "USD"}, nil, logger.NewNop())
+	calculator := NewRiskCalculator(&config.PatternsConfig{}, nil, converter, 10000, logger.NewNop())
+	scorer := NewShadowScorer(calculator, nil)
+	tx := &domain.Transaction{ID: uuid.New(), UserID: uuid.New(), Amount: money.FromFloat(120), Currency: "USD"}
+
+	strict := &domain.TenantSettings{
+		TenantID: "strict",
+		DecisionThresholds: map[string]domain.DecisionThresholds{
+			domain.ThresholdTierDefault: {Tier: domain.ThresholdTierDefault, Suspicious: 0, Blocked: 100},
+		},
+	}
+	tests := []struct {
+		name     string
+		settings *domain.TenantSettings
+		want     domain.ScreeningDecision
+	}{
+		{name: "configured thresholds", want: domain.DecisionApproved},
+		{name: "tenant thresholds", settings: strict, want: domain.DecisionSuspicious},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sctx := &ScreeningContext{Transaction: tx, TenantSettings: tt.settings}
+			if tt.settings != nil {
+				sctx.TenantID = tt.settings.TenantID
+			}
+			result := &domain.ScreeningResult{Decision: tt.want}
+
+			scorer.score(sctx, nil, result)
+			if result.ShadowDecision != tt.want {
+				t.Errorf("shadow decision = %s at score %d, want %s", result.ShadowDecision, *result.ShadowScore, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// DeviceUserIndex links devices and IP addresses to the users transacting
+// from them. Record links userID to key and returns how many users key has
+// had within the tracking window, with the most recently seen of them up
+// to limit. Keys name the tenant, so each tenant's users are counted and
+// listed apart.
+type DeviceUserIndex interface {
+	Record(ctx context.Context, key string, userID uuid.UUID, at time.Time, limit int) (int, []uuid.UUID, error)
+}
+
+// SharedDeviceChecker flags devices and IP addresses used by more of a
+// tenant's users than one customer plausibly has accounts for, as mule
+// networks do. Users of other tenants are neither counted nor named.
+type SharedDeviceChecker struct {
+	index DeviceUserIndex
+	cfg   *config.SharedDeviceConfig
+	log   *logger.Logger
+}
+
+// NewSharedDeviceChecker creates a new shared device and IP checker
+func NewSharedDeviceChecker(index DeviceUserIndex, cfg *config.SharedDeviceConfig, log *logger.Logger) *SharedDeviceChecker {
+	return &SharedDeviceChecker{
+		index: index,
+		cfg:   cfg,
+		log:   log.Named("shared_device_checker"),
+	}
+}
+
+// Check records the transaction's user against its device and IP address
+// and returns a SHARED_DEVICE factor for each used by more than the
+// configured number of users. Private and loopback addresses are skipped,
+// as many customers share them behind a NAT.
+func (c *SharedDeviceChecker) Check(ctx context.Context, tx *domain.Transaction) ([]domain.RiskFactor, error) {
+	at := tx.InitiatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	tenantID := tenant.OrDefault(ctx)
+	var factors []domain.RiskFactor
+	if tx.DeviceID != "" {
+		f, err := c.check(ctx, tenantID, tx.UserID, "device", tx.DeviceID, at)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			factors = append(factors, *f)
+		}
+	}
+	if ip := net.ParseIP(tx.IPAddress); ip != nil && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsUnspecified() {
+		f, err := c.check(ctx, tenantID, tx.UserID, "ip", ip.String(), at)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			factors = append(factors, *f)
+		}
+	}
+	return factors, nil
+}
+
+// check records the user against one of the tenant's devices or IP
+// addresses
+func (c *SharedDeviceChecker) check(ctx context.Context, tenantID string, userID uuid.UUID, kind, value string, at time.Time) (*domain.RiskFactor, error) {
+	// One extra in case the sample includes the user screened
+	count, users, err := c.index.Record(ctx, deviceUsersKey(tenantID, kind, value), userID, at, c.cfg.SampleSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("record %s user: %w", kind, err)
+	}
+	if count <= c.cfg.MaxUsers {
+		return nil, nil
+	}
+
+	others := make([]string, 0, c.cfg.SampleSize)
+	for _, u := range users {
+		if u != userID && len(others) < c.cfg.SampleSize {
+			others = append(others, u.String())
+		}
+	}
+
+	return &domain.RiskFactor{
+		Factor:      "SHARED_DEVICE",
+		Weight:      c.cfg.Weight,
+		Description: fmt.Sprintf("Transaction %s is shared by many users", describeDeviceKind(kind)),
+		Details: fmt.Sprintf("%s %s used by %d users in the last %s, including %s",
+			kind, value, count, formatWindow(c.cfg.Window), strings.Join(others, ", ")),
+	}, nil
+}
+
+// deviceUsersKey keys a device or IP address within a tenant
+func deviceUsersKey(tenantID, kind, value string) string {
+	return tenantID + ":" + kind + ":" + value
+}
+
+func describeDeviceKind(kind string) string {
+	if kind == "ip" {
+		return "IP address"
+	}
+	return "device"
+}
+
+// formatWindow describes a window of whole days in days, and any other in
+// hours and minutes
+func formatWindow(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", int(d/(24*time.Hour)))
+	}
+	return d.String()
+}
@@ -0,0 +1,83 @@
+package screening
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// memoryDeviceUsers is a DeviceUserIndex holding every key's users in
+// memory, without a window
+type memoryDeviceUsers map[string]map[uuid.UUID]time.Time
+
+func (m memoryDeviceUsers) Record(_ context.Context, key string, userID uuid.UUID, at time.Time, limit int) (int, []uuid.UUID, error) {
+	if m[key] == nil {
+		m[key] = make(map[uuid.UUID]time.Time)
+	}
+	m[key][userID] = at
+
+	users := make([]uuid.UUID, 0, len(m[key]))
+	for u := range m[key] {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return m[key][users[i]].After(m[key][users[j]]) })
+	return len(users), users[:min(limit, len(users))], nil
+}
+
+func TestSharedDeviceCheckerIsolatesTenants(t *testing.T) {
+	cfg := &config.SharedDeviceConfig{Window: 24 * time.Hour, MaxUsers: 2, MaxTracked: 100, SampleSize: 10, Weight: 20}
+	checker := NewSharedDeviceChecker(memoryDeviceUsers{}, cfg, logger.NewNop())
+	at := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	check := func(tenantID string, userID uuid.UUID) []domain.RiskFactor {
+		t.Helper()
+		ctx := tenant.WithID(context.Background(), tenantID)
+		factors, err := checker.Check(ctx, &domain.Transaction{
+			UserID:      userID,
+			DeviceID:    "device-1",
+			IPAddress:   "203.0.113.7",
+			InitiatedAt: at,
+		})
+		if err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+		return factors
+	}
+
+	// Three of tenant a's users share the device and address
+	var usersA []uuid.UUID
+	for range 3 {
+		u := uuid.New()
+		usersA = append(usersA, u)
+		check("a", u)
+	}
+
+	// Tenant b's first user on them sees none of tenant a's
+	userB := uuid.New()
+	if factors := check("b", userB); len(factors) != 0 {
+		t.Fatalf("tenant b got %d factors from tenant a's users: %+v", len(factors), factors)
+	}
+
+	// Tenant a is flagged, and never told about tenant b's user
+	factors := check("a", usersA[0])
+	if len(factors) != 2 {
+		t.Fatalf("tenant a got %d factors, want 2 (device and IP)", len(factors))
+	}
+	for _, f := range factors {
+		if strings.Contains(f.Details, userB.String()) {
+			t.Errorf("tenant a's %s factor names tenant b's user: %s", f.Factor, f.Details)
+		}
+		if !strings.Contains(f.Details, "used by 3 users") {
+			t.Errorf("tenant a's factor counts other tenants' users: %s", f.Details)
+		}
+	}
+}
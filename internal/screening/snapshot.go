@@ -0,0 +1,371 @@
+package screening
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/money"
+)
+
+// replayCheckTimeout replaces the screening latency budget in snapshot
+// engines, so a slow run never turns a check into a timeout and changes
+// the outcome
+const replayCheckTimeout = time.Minute
+
+// errSnapshotReadOnly is returned by writes to a list snapshot
+var errSnapshotReadOnly = errors.New("list snapshot is read-only")
+
+// ListSnapshot is a pinned copy of the OFAC and PEP lists, used to screen
+// without Redis. The versions are 0 for snapshots exported without one.
+type ListSnapshot struct {
+	OFAC          []OFACEntry
+	PEP           []PEPEntry
+	OFACUpdatedAt time.Time
+	PEPUpdatedAt  time.Time
+	OFACVersion   int64
+	PEPVersion    int64
+}
+
+// snapshotFile is the on-disk form of one list, as written by exporting
+// the entries cached in Redis or by the list archive
+type snapshotFile[T any] struct {
+	Version   int64     `json:"version,omitempty"`
+	Hash      string    `json:"hash,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Entries   []T       `json:"entries"`
+}
+
+// LoadListSnapshot reads OFAC and PEP snapshot files of the form
+// {"version": 42, "updated_at": "...", "entries": [...]}, version being
+// optional
+func LoadListSnapshot(ofacPath, pepPath string) (*ListSnapshot, error) {
+	ofac, err := readSnapshotFile[OFACEntry](ofacPath)
+	if err != nil {
+		return nil, fmt.Errorf("load ofac snapshot: %w", err)
+	}
+	pep, err := readSnapshotFile[PEPEntry](pepPath)
+	if err != nil {
+		return nil, fmt.Errorf("load pep snapshot: %w", err)
+	}
+	return &ListSnapshot{
+		OFAC:          ofac.Entries,
+		PEP:           pep.Entries,
+		OFACUpdatedAt: ofac.UpdatedAt,
+		PEPUpdatedAt:  pep.UpdatedAt,
+		OFACVersion:   ofac.Version,
+		PEPVersion:    pep.Version,
+	}, nil
+}
+
+func readSnapshotFile[T any](path string) (*snapshotFile[T], error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f snapshotFile[T]
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// NewSnapshotEngine creates an engine for deterministic replay. The OFAC
+// and PEP checks run against the pinned snapshot instead of Redis, results
+// are stamped with the frozen clock now, which also reports every latency
+// as zero, and nothing is cached, persisted, alerted or audited. Per-user
+// state (risk profiles, velocity, pattern, device, location and device
+// sharing history) is
+// not available offline, so those checks see a user with no history, and
+// the internal account denylist is not checked: a replay exercises list
+// matching, rules, weights and thresholds. The latency budget is lifted so
+// no check times out.
+func NewSnapshotEngine(
+	snapshot *ListSnapshot,
+	reputation ReputationProvider,
+	geoIP GeoIPProvider,
+	converter CurrencyConverter,
+	cfg *config.ScreeningConfig,
+	patternsCfg *config.PatternsConfig,
+	complianceCfg *config.ComplianceConfig,
+	now time.Time,
+	log *logger.Logger,
+) (*Engine, error) {
+	ofacMatcher, err := NewNameMatcher(cfg.NameMatchers["ofac"])
+	if err != nil {
+		return nil, fmt.Errorf("create ofac name matcher: %w", err)
+	}
+	pepMatcher, err := NewNameMatcher(cfg.NameMatchers["pep"])
+	if err != nil {
+		return nil, fmt.Errorf("create pep name matcher: %w", err)
+	}
+	normalizer, err := NewNameNormalizer(cfg.NameFolding, cfg.EntityStopwords)
+	if err != nil {
+		return nil, fmt.Errorf("create name normalizer: %w", err)
+	}
+
+	ofacChecker := NewOFACChecker(newSnapshotOFACCache(snapshot, normalizer), nil, ofacMatcher, normalizer, log, cfg.FuzzyMatchThreshold, cfg.BatchConcurrency, 0)
+	pepChecker := NewPEPChecker(newSnapshotPEPCache(snapshot, normalizer), nil, pepMatcher, normalizer, log, cfg.FuzzyMatchThreshold, cfg.PEPPartialMatchThreshold)
+	ctx := context.Background()
+	if err := ofacChecker.LoadIndex(ctx); err != nil {
+		return nil, fmt.Errorf("load ofac snapshot index: %w", err)
+	}
+	if err := pepChecker.LoadIndex(ctx); err != nil {
+		return nil, fmt.Errorf("load pep snapshot index: %w", err)
+	}
+
+	replayCfg := *cfg
+	replayCfg.MaxScreeningLatency = replayCheckTimeout
+	replayCfg.CheckTimeouts = nil
+	replayCfg.ResultCacheTTL = 0
+
+	countryRisk := NewCountryRisk(ConfiguredCountryRiskTable(patternsCfg))
+
+	return NewEngine(
+		ofacChecker,
+		pepChecker,
+		NewReputationChecker(reputation, noDeviceHistory{}, &cfg.Reputation, log),
+		NewGeoIPChecker(geoIP, noLocationHistory{}, countryRisk, &cfg.GeoIP, log),
+		NewSharedDeviceChecker(noDeviceUsers{}, &cfg.SharedDevice, log),
+		NewCounterpartyChecker(noCounterparties{}, &cfg.Counterparty, log),
+		nil, // the account denylist lives in Redis
+		NewRiskCalculator(patternsCfg, countryRisk, converter, complianceCfg.CTRThreshold, log), // configured country risk table
+		nil, // and configured thresholds for every tenant
+		nil, // no shadow scoring
+		converter,
+		noPatterns{},
+		nil, // no transaction history
+		noVelocity{},
+		noRiskProfiles{},
+		nil, // results are not persisted
+		nil, // or cached
+		nil, // list checks always run
+		nil, // or alerted on
+		nil, // or audited
+		nil, // or notified
+		clock.NewFake(now),
+		&replayCfg,
+		complianceCfg,
+		log,
+	), nil
+}
+
+// snapshotOFACCache serves the OFAC list from a snapshot
+type snapshotOFACCache struct {
+	entries   []OFACEntry
+	byName    map[string]OFACEntry
+	updatedAt time.Time
+	version   ListVersion
+}
+
+func newSnapshotOFACCache(snapshot *ListSnapshot, normalizer *NameNormalizer) *snapshotOFACCache {
+	c := &snapshotOFACCache{
+		entries:   make([]OFACEntry, len(snapshot.OFAC)),
+		byName:    make(map[string]OFACEntry, len(snapshot.OFAC)),
+		updatedAt: snapshot.OFACUpdatedAt,
+	}
+	for i, entry := range snapshot.OFAC {
+		if entry.NormalizedName == "" {
+			entry.NormalizedName = normalizer.Normalize(entry.Name)
+		}
+		c.entries[i] = entry
+		c.byName[entry.NormalizedName] = entry
+	}
+	c.version = snapshotVersion(snapshot.OFACVersion, c.entries, snapshot.OFACUpdatedAt)
+	return c
+}
+
+func (c *snapshotOFACCache) GetByExactName(_ context.Context, name string) (*OFACEntry, error) {
+	if entry, ok := c.byName[name]; ok {
+		return &entry, nil
+	}
+	return nil, nil
+}
+
+func (c *snapshotOFACCache) GetByFuzzyName(_ context.Context, name string, matcher NameMatcher, threshold float64) ([]OFACEntry, error) {
+	return fuzzyEntries(c.entries, name, matcher, threshold, func(e OFACEntry) string { return e.NormalizedName }), nil
+}
+
+func (c *snapshotOFACCache) GetAllEntries(context.Context) ([]OFACEntry, error) {
+	return c.entries, nil
+}
+
+func (c *snapshotOFACCache) SetEntries(context.Context, []OFACEntry, time.Duration, ListWriteMode) error {
+	return errSnapshotReadOnly
+}
+
+func (c *snapshotOFACCache) GetLastUpdate(context.Context) (time.Time, error) {
+	return c.updatedAt, nil
+}
+
+func (c *snapshotOFACCache) SetLastUpdate(context.Context, time.Time) error {
+	return errSnapshotReadOnly
+}
+
+func (c *snapshotOFACCache) GetVersion(context.Context) (ListVersion, error) {
+	return c.version, nil
+}
+
+func (c *snapshotOFACCache) RecordVersion(context.Context, string, int, time.Time) (ListVersion, bool, error) {
+	return c.version, false, nil
+}
+
+// snapshotPEPCache serves the PEP list from a snapshot
+type snapshotPEPCache struct {
+	entries   []PEPEntry
+	byName    map[string]PEPEntry
+	updatedAt time.Time
+	version   ListVersion
+}
+
+func newSnapshotPEPCache(snapshot *ListSnapshot, normalizer *NameNormalizer) *snapshotPEPCache {
+	c := &snapshotPEPCache{
+		entries:   make([]PEPEntry, len(snapshot.PEP)),
+		byName:    make(map[string]PEPEntry, len(snapshot.PEP)),
+		updatedAt: snapshot.PEPUpdatedAt,
+	}
+	for i, entry := range snapshot.PEP {
+		if entry.NormalizedName == "" {
+			entry.NormalizedName = normalizer.Normalize(entry.Name)
+		}
+		c.entries[i] = entry
+		c.byName[entry.NormalizedName] = entry
+	}
+	c.version = snapshotVersion(snapshot.PEPVersion, c.entries, snapshot.PEPUpdatedAt)
+	return c
+}
+
+func (c *snapshotPEPCache) GetByName(_ context.Context, name string) (*PEPEntry, error) {
+	if entry, ok := c.byName[name]; ok {
+		return &entry, nil
+	}
+	return nil, nil
+}
+
+func (c *snapshotPEPCache) GetByFuzzyName(_ context.Context, name string, matcher NameMatcher, threshold float64) ([]PEPEntry, error) {
+	return fuzzyEntries(c.entries, name, matcher, threshold, func(e PEPEntry) string { return e.NormalizedName }), nil
+}
+
+func (c *snapshotPEPCache) GetAllEntries(context.Context) ([]PEPEntry, error) {
+	return c.entries, nil
+}
+
+func (c *snapshotPEPCache) SetEntries(context.Context, []PEPEntry, time.Duration) error {
+	return errSnapshotReadOnly
+}
+
+func (c *snapshotPEPCache) GetLastUpdate(context.Context) (time.Time, error) {
+	return c.updatedAt, nil
+}
+
+func (c *snapshotPEPCache) GetVersion(context.Context) (ListVersion, error) {
+	return c.version, nil
+}
+
+func (c *snapshotPEPCache) RecordVersion(context.Context, string, int, time.Time) (ListVersion, bool, error) {
+	return c.version, false, nil
+}
+
+// snapshotVersion is the pinned version of a snapshot list, hashed over
+// the entries the checker reads
+func snapshotVersion[E any](version int64, entries []E, updatedAt time.Time) ListVersion {
+	return ListVersion{
+		Version:  version,
+		Hash:     listHash(entries),
+		Entries:  len(entries),
+		LoadedAt: updatedAt,
+	}
+}
+
+// fuzzyEntries returns the entries whose normalized name is at least
+// threshold similar to name, best match first. Ties keep snapshot order so
+// replays pick the same entry every run.
+func fuzzyEntries[T any](entries []T, name string, matcher NameMatcher, threshold float64, normalized func(T) string) []T {
+	type scored struct {
+		entry T
+		score float64
+	}
+	var matches []scored
+	for _, entry := range entries {
+		if score := matcher.Similarity(name, normalized(entry)); score >= threshold {
+			matches = append(matches, scored{entry: entry, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	result := make([]T, len(matches))
+	for i, m := range matches {
+		result[i] = m.entry
+	}
+	return result
+}
+
+// noPatterns detects nothing; pattern detectors need transaction history
+type noPatterns struct{}
+
+func (noPatterns) DetectPatterns(context.Context, uuid.UUID, *domain.Transaction) ([]domain.PatternMatch, error) {
+	return nil, nil
+}
+
+// noVelocity reports a user with no recent activity
+type noVelocity struct{}
+
+func (noVelocity) GetVelocity(_ context.Context, userID uuid.UUID) (*domain.VelocityData, error) {
+	return &domain.VelocityData{UserID: userID}, nil
+}
+
+func (noVelocity) IncrementVelocity(context.Context, uuid.UUID, money.Amount) error {
+	return nil
+}
+
+// noLocationHistory reports every user as having no previous location and
+// remembers nothing
+type noLocationHistory struct{}
+
+func (noLocationHistory) SwapLastLocation(context.Context, uuid.UUID, *domain.TransactionLocation, time.Duration) (*domain.TransactionLocation, error) {
+	return nil, nil
+}
+
+// noDeviceUsers reports every device and IP address as used by the
+// screened user alone and records nothing
+type noDeviceUsers struct{}
+
+func (noDeviceUsers) Record(_ context.Context, _ string, userID uuid.UUID, _ time.Time, _ int) (int, []uuid.UUID, error) {
+	return 1, []uuid.UUID{userID}, nil
+}
+
+// noCounterparties knows nothing about any counterparty and records
+// nothing
+type noCounterparties struct{}
+
+func (noCounterparties) Get(context.Context, domain.CounterpartyKey) (*domain.CounterpartyReputation, error) {
+	return nil, nil
+}
+
+func (noCounterparties) Record(context.Context, domain.CounterpartyEvent, time.Duration) error {
+	return nil
+}
+
+// noRiskProfiles reports every user as having no profile yet
+type noRiskProfiles struct{}
+
+func (noRiskProfiles) GetByUserID(context.Context, uuid.UUID) (*domain.UserRiskProfile, error) {
+	return nil, domain.ErrNotFound
+}
+
+// noDeviceHistory reports no previously blocked transactions
+type noDeviceHistory struct{}
+
+func (noDeviceHistory) CountBlockedByDevice(context.Context, string, time.Time) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,55 @@
+package screening
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+
+	"github.com/banking/aml-service/internal/domain"
+)
+
+// TenantSettings holds every tenant's stored settings in force. The set is
+// swapped whole on reload, so a screening reads one tenant's settings as
+// they were when it started.
+type TenantSettings struct {
+	current atomic.Pointer[map[string]*domain.TenantSettings]
+	mu      sync.Mutex // serialises writers
+}
+
+// NewTenantSettings creates a holder with no overrides, so every tenant
+// screens under the global configuration
+func NewTenantSettings() *TenantSettings {
+	s := &TenantSettings{}
+	s.Set(nil)
+	return s
+}
+
+// Set puts the given settings in force, dropping any tenant not among them
+func (s *TenantSettings) Set(all []*domain.TenantSettings) {
+	byTenant := make(map[string]*domain.TenantSettings, len(all))
+	for _, settings := range all {
+		byTenant[settings.TenantID] = settings
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Store(&byTenant)
+}
+
+// Put puts one tenant's settings in force
+func (s *TenantSettings) Put(settings *domain.TenantSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byTenant := maps.Clone(*s.current.Load())
+	byTenant[settings.TenantID] = settings
+	s.current.Store(&byTenant)
+}
+
+// Get returns a tenant's settings, or nil if it has none or s is nil
+func (s *TenantSettings) Get(tenantID string) *domain.TenantSettings {
+	if s == nil {
+		return nil
+	}
+	return (*s.current.Load())[tenantID]
+}
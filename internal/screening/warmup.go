@@ -0,0 +1,114 @@
+package screening
+
+import (
+	"context"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// listWarmer is a list checker that can be warmed at startup
+type listWarmer interface {
+	LoadIndex(ctx context.Context) error
+	PrimeCache(ctx context.Context) error
+}
+
+// Warmer loads the OFAC and PEP indexes and primes their Redis caches at
+// startup, so the first screenings after boot do not pay for cold lookups.
+// The readiness probe waits for it.
+type Warmer struct {
+	lists   map[string]listWarmer
+	enabled bool
+	retry   time.Duration
+	log     *logger.Logger
+	done    chan struct{} // closed once warm-up completes
+}
+
+// NewWarmer creates a warmer for the OFAC and PEP checkers
+func NewWarmer(ofac *OFACChecker, pep *PEPChecker, cfg *config.ScreeningConfig, log *logger.Logger) *Warmer {
+	return &Warmer{
+		lists:   map[string]listWarmer{"ofac": ofac, "pep": pep},
+		enabled: cfg.WarmupEnabled,
+		retry:   cfg.WarmupRetryInterval,
+		log:     log.Named("warmup"),
+		done:    make(chan struct{}),
+	}
+}
+
+// WarmedUp reports whether warm-up has completed
+func (w *Warmer) WarmedUp() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until warm-up completes or ctx is cancelled, returning
+// ctx's error in the latter case
+func (w *Warmer) Wait(ctx context.Context) error {
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run warms every list, retrying the ones that fail until all succeed or
+// ctx is cancelled. When warm-up is disabled each index gets one
+// best-effort load and Run returns at once.
+func (w *Warmer) Run(ctx context.Context) {
+	if !w.enabled {
+		for name, list := range w.lists {
+			if err := list.LoadIndex(ctx); err != nil {
+				w.log.Error("failed to load index", logger.StringField("list", name), logger.ErrorField(err))
+			}
+		}
+		close(w.done)
+		return
+	}
+
+	start := time.Now()
+	pending := make(map[string]listWarmer, len(w.lists))
+	for name, list := range w.lists {
+		pending[name] = list
+	}
+
+	for {
+		for name, list := range pending {
+			if err := w.warm(ctx, list); err != nil {
+				w.log.Warn("list warm-up failed, will retry",
+					logger.StringField("list", name),
+					logger.DurationField("retry_in", w.retry),
+					logger.ErrorField(err),
+				)
+				continue
+			}
+			delete(pending, name)
+		}
+
+		if len(pending) == 0 {
+			close(w.done)
+			w.log.Info("warm-up completed", logger.DurationField("took", time.Since(start)))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			w.log.Warn("warm-up stopped before completing", logger.IntField("pending", len(pending)))
+			return
+		case <-time.After(w.retry):
+		}
+	}
+}
+
+// warm loads a list's index and primes its cache
+func (w *Warmer) warm(ctx context.Context, list listWarmer) error {
+	if err := list.LoadIndex(ctx); err != nil {
+		return err
+	}
+	return list.PrimeCache(ctx)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// alertAutoCloseLockKey is the advisory lock key shared by all auto-close job instances
+const alertAutoCloseLockKey int64 = 0x414d4c0a // "AML" + 10
+
+// AlertAutoCloseStore dismisses alerts selected by an auto-close policy
+type AlertAutoCloseStore interface {
+	AutoClose(ctx context.Context, policy domain.AlertAutoClosePolicy, closedAt time.Time, limit int) ([]*domain.AMLAlert, error)
+}
+
+// AlertAutoCloseJob dismisses NEW alerts that nobody has picked up, whose
+// confidence is below the configured ceiling and which have seen no new
+// occurrence for the configured age, so the analyst queue holds real risk.
+// Watchlist hits, alerts on transactions with an OFAC match and alerts
+// linked to an open investigation are never closed. Every dismissal is
+// audited.
+type AlertAutoCloseJob struct {
+	alerts  AlertAutoCloseStore
+	locker  Locker
+	auditor Auditor
+	cfg     *config.AlertAutoCloseConfig
+	log     *logger.Logger
+}
+
+// NewAlertAutoCloseJob creates a new alert auto-close job
+func NewAlertAutoCloseJob(alerts AlertAutoCloseStore, locker Locker, auditor Auditor, cfg *config.AlertAutoCloseConfig, log *logger.Logger) *AlertAutoCloseJob {
+	return &AlertAutoCloseJob{
+		alerts:  alerts,
+		locker:  locker,
+		auditor: auditor,
+		cfg:     cfg,
+		log:     log.Named("alert_auto_close_job"),
+	}
+}
+
+// Run closes stale alerts on the configured interval until ctx is cancelled
+func (j *AlertAutoCloseJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	j.log.Info("alert auto-close job started",
+		logger.DurationField("interval", j.cfg.Interval),
+		logger.DurationField("min_age", j.cfg.MinAge),
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.log.Info("alert auto-close job stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.RunOnce(ctx); err != nil {
+				j.log.Error("alert auto-close run failed", logger.ErrorField(err))
+			}
+		}
+	}
+}
+
+// RunOnce closes every alert currently eligible and returns how many were
+// closed. It is a no-op when another instance holds the job lock.
+func (j *AlertAutoCloseJob) RunOnce(ctx context.Context) (int, error) {
+	release, acquired, err := j.locker.TryLock(ctx, alertAutoCloseLockKey)
+	if err != nil {
+		return 0, fmt.Errorf("acquire alert auto-close lock: %w", err)
+	}
+	if !acquired {
+		j.log.Debug("alert auto-close skipped, another instance holds the lock")
+		return 0, nil
+	}
+	defer release()
+
+	now := time.Now()
+	policy := j.policy(now)
+
+	closed := 0
+	for ctx.Err() == nil {
+		alerts, err := j.alerts.AutoClose(ctx, policy, now, j.cfg.BatchSize)
+		if err != nil {
+			return closed, fmt.Errorf("auto-close alerts: %w", err)
+		}
+		for _, alert := range alerts {
+			j.audit(ctx, alert)
+		}
+		closed += len(alerts)
+		if len(alerts) < j.cfg.BatchSize {
+			break
+		}
+	}
+
+	if closed > 0 {
+		j.log.Info("stale alerts auto-closed",
+			logger.IntField("closed", closed),
+			logger.StringField("stale_before", policy.StaleBefore.Format(time.RFC3339)),
+		)
+	}
+	return closed, nil
+}
+
+// policy returns the configured policy with the built-in exclusions added
+func (j *AlertAutoCloseJob) policy(now time.Time) domain.AlertAutoClosePolicy {
+	excludeTypes := append([]domain.AlertType(nil), domain.NeverAutoClosedAlertTypes...)
+	for _, t := range j.cfg.ExcludeTypes {
+		excludeTypes = append(excludeTypes, domain.AlertType(t))
+	}
+
+	return domain.AlertAutoClosePolicy{
+		MaxConfidence: j.cfg.MaxConfidence,
+		StaleBefore:   now.Add(-j.cfg.MinAge),
+		ExcludeTypes:  excludeTypes,
+		ExcludeRules:  j.cfg.ExcludeRules,
+	}
+}
+
+// audit records an automatic dismissal in the audit log
+func (j *AlertAutoCloseJob) audit(ctx context.Context, alert *domain.AMLAlert) {
+	err := j.auditor.Record(ctx, audit.Entry{
+		ActorID:    domain.SystemActorID,
+		Action:     audit.ActionAlertAutoClosed,
+		EntityType: audit.EntityAlert,
+		EntityID:   alert.ID.String(),
+		Before:     map[string]interface{}{"status": domain.AlertStatusNew},
+		After: map[string]interface{}{
+			"status":           alert.Status,
+			"resolution":       alert.Resolution,
+			"confidence":       alert.Confidence,
+			"max_confidence":   j.cfg.MaxConfidence,
+			"last_detected_at": alert.LastDetectedAt,
+		},
+	})
+	if err != nil {
+		j.log.Error("failed to audit alert auto-close",
+			logger.StringField("alert_id", alert.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// AlertGroupStore interface for alert persistence with correlation
+type AlertGroupStore interface {
+	Create(ctx context.Context, alert *domain.AMLAlert) error
+	CreateCorrelated(ctx context.Context, alert *domain.AMLAlert, since time.Time) (*domain.AMLAlert, error)
+	Update(ctx context.Context, alert *domain.AMLAlert) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AMLAlert, error)
+	ApplyTransition(ctx context.Context, alert *domain.AMLAlert, from domain.AlertStatus, inv *domain.Investigation) error
+}
+
+// AlertService creates and reviews AML alerts. Every alert is prioritized
+// by its risk score as it is raised. Repeat occurrences of the same
+// behaviour are folded into one alert to prevent alert storms; only new
+// alerts are published to webhook endpoints.
+type AlertService struct {
+	alerts           AlertGroupStore
+	auditor          Auditor
+	webhooks         WebhookPublisher
+	window           time.Duration
+	investigationSLA time.Duration
+	priority         domain.AlertPriorityPolicy
+	autoEscalate     bool
+	log              *logger.Logger
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(alerts AlertGroupStore, auditor Auditor, webhooks WebhookPublisher, cfg *config.ComplianceConfig, log *logger.Logger) *AlertService {
+	return &AlertService{
+		alerts:           alerts,
+		auditor:          auditor,
+		webhooks:         webhooks,
+		window:           cfg.AlertCorrelationWindow,
+		investigationSLA: cfg.InvestigationSLA,
+		priority:         alertPriorityPolicy(&cfg.AlertPriority),
+		autoEscalate:     cfg.AlertPriority.AutoEscalate,
+		log:              log.Named("alert_service"),
+	}
+}
+
+// alertPriorityPolicy converts the configured bands and escalation cutoffs
+func alertPriorityPolicy(cfg *config.AlertPriorityConfig) domain.AlertPriorityPolicy {
+	policy := domain.AlertPriorityPolicy{
+		EscalationScore:    cfg.EscalationScore,
+		EscalationPriority: domain.RiskLevel(cfg.EscalationPriority),
+	}
+	for _, band := range cfg.Bands {
+		policy.Bands = append(policy.Bands, domain.AlertPriorityBand{
+			MinScore: band.MinScore,
+			Priority: domain.RiskLevel(band.Priority),
+		})
+	}
+	sort.Slice(policy.Bands, func(i, j int) bool { return policy.Bands[i].MinScore < policy.Bands[j].MinScore })
+	return policy
+}
+
+// Prioritize raises an alert's priority to its risk score's band, for
+// callers that store the alert themselves
+func (s *AlertService) Prioritize(alert *domain.AMLAlert) {
+	s.priority.Prioritize(alert)
+}
+
+// Create prioritizes an alert by its risk score and stores it, or folds it
+// into an open alert with the same correlation key detected within the
+// correlation window. On a merge the alert is overwritten with the group it
+// joined.
+func (s *AlertService) Create(ctx context.Context, alert *domain.AMLAlert) error {
+	s.priority.Prioritize(alert)
+
+	if s.window <= 0 {
+		if err := s.alerts.Create(ctx, alert); err != nil {
+			return err
+		}
+		s.webhooks.Publish(ctx, alert.TenantID, domain.WebhookEventAlertCreated, alert.ToSummary())
+		s.escalateIfRequired(ctx, alert)
+		return nil
+	}
+
+	group, err := s.alerts.CreateCorrelated(ctx, alert, alert.DetectedAt.Add(-s.window))
+	if err != nil {
+		return err
+	}
+
+	if group.ID != alert.ID {
+		s.log.Info("alert correlated",
+			logger.StringField("alert_id", group.ID.String()),
+			logger.StringField("alert_number", group.AlertNumber),
+			logger.StringField("correlation_key", group.CorrelationKey()),
+			logger.IntField("occurrences", group.OccurrenceCount),
+		)
+		*alert = *group
+		s.escalateIfRequired(ctx, alert)
+		return nil
+	}
+
+	s.webhooks.Publish(ctx, alert.TenantID, domain.WebhookEventAlertCreated, alert.ToSummary())
+	s.escalateIfRequired(ctx, alert)
+	return nil
+}
+
+// escalateIfRequired escalates a NEW alert that needs escalation to a new
+// investigation when auto-escalation is on. Alerts already under review are
+// left to their analyst. A failure is logged; the alert stays raised.
+func (s *AlertService) escalateIfRequired(ctx context.Context, alert *domain.AMLAlert) {
+	if !s.autoEscalate || alert.Status != domain.AlertStatusNew || !alert.RequiresEscalation(s.priority) {
+		return
+	}
+
+	reason := fmt.Sprintf("auto-escalated: risk score %d, priority %s", alert.RiskScore, alert.Priority)
+	escalated, _, err := s.transition(ctx, alert.ID, domain.AlertStatusEscalated, domain.SystemActorID, reason, true)
+	if err != nil {
+		s.log.Error("failed to auto-escalate alert",
+			logger.StringField("alert_id", alert.ID.String()),
+			logger.ErrorField(err),
+		)
+		return
+	}
+	*alert = *escalated
+}
+
+// GetAlert returns an alert by ID
+func (s *AlertService) GetAlert(ctx context.Context, id uuid.UUID) (*domain.AMLAlert, error) {
+	return s.alerts.GetByID(ctx, id)
+}
+
+// ReviewAlert marks an alert as under review by the given analyst
+func (s *AlertService) ReviewAlert(ctx context.Context, id uuid.UUID, req *domain.ReviewAlertRequest) (*domain.AMLAlert, error) {
+	alert, _, err := s.transition(ctx, id, domain.AlertStatusReviewing, req.ReviewedBy, "", false)
+	return alert, err
+}
+
+// DismissAlert dismisses an alert together with every occurrence folded into it
+func (s *AlertService) DismissAlert(ctx context.Context, id uuid.UUID, req *domain.DismissAlertRequest) (*domain.AMLAlert, error) {
+	if req.Resolution == "" {
+		return nil, fmt.Errorf("%w: resolution is required", domain.ErrValidation)
+	}
+
+	alert, _, err := s.transition(ctx, id, domain.AlertStatusDismissed, req.ReviewedBy, req.Resolution, false)
+	return alert, err
+}
+
+// EscalateAlert escalates an alert, optionally opening an investigation
+// linked to it
+func (s *AlertService) EscalateAlert(ctx context.Context, id uuid.UUID, req *domain.EscalateAlertRequest) (*domain.EscalateAlertResponse, error) {
+	alert, inv, err := s.transition(ctx, id, domain.AlertStatusEscalated, req.ReviewedBy, req.Reason, req.CreateInvestigation)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.EscalateAlertResponse{Alert: alert, Investigation: inv}, nil
+}
+
+// transition moves an alert to a new status and records who reviewed it.
+// An empty resolution leaves the existing one in place.
+func (s *AlertService) transition(
+	ctx context.Context,
+	id uuid.UUID,
+	to domain.AlertStatus,
+	reviewer uuid.UUID,
+	resolution string,
+	openInvestigation bool,
+) (*domain.AMLAlert, *domain.Investigation, error) {
+	alert, err := s.alerts.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := alert.ValidateTransition(to); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	from := alert.Status
+	alert.Status = to
+	alert.ReviewedBy = &reviewer
+	alert.ReviewedAt = &now
+	alert.UpdatedAt = now
+	if resolution != "" {
+		alert.Resolution = resolution
+	}
+
+	var inv *domain.Investigation
+	if openInvestigation {
+		inv = alert.NewInvestigation(s.investigationSLA, now)
+		alert.InvestigationID = &inv.ID
+	}
+
+	if err := s.alerts.ApplyTransition(ctx, alert, from, inv); err != nil {
+		return nil, nil, fmt.Errorf("apply alert transition: %w", err)
+	}
+
+	s.log.Info("alert transitioned",
+		logger.StringField("alert_id", alert.ID.String()),
+		logger.StringField("alert_number", alert.AlertNumber),
+		logger.StringField("from", string(from)),
+		logger.StringField("to", string(to)),
+		logger.StringField("reviewed_by", reviewer.String()),
+		logger.IntField("occurrences", alert.OccurrenceCount),
+	)
+	if inv != nil {
+		err := s.auditor.Record(ctx, audit.Entry{
+			ActorID:    reviewer,
+			Action:     audit.ActionInvestigationOpened,
+			EntityType: audit.EntityInvestigation,
+			EntityID:   inv.ID.String(),
+			After: map[string]interface{}{
+				"status":      inv.Status,
+				"priority":    inv.Priority,
+				"case_number": inv.CaseNumber,
+				"alert_id":    alert.ID,
+			},
+		})
+		if err != nil {
+			s.log.Error("failed to audit investigation opening",
+				logger.StringField("investigation_id", inv.ID.String()),
+				logger.ErrorField(err),
+			)
+		}
+
+		s.log.Info("investigation opened from alert",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.StringField("case_number", inv.CaseNumber),
+			logger.StringField("alert_id", alert.ID.String()),
+		)
+	}
+
+	return alert, inv, nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/email"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+)
+
+// analystDigestLockKey is the advisory lock key shared by all digest job instances
+const analystDigestLockKey int64 = 0x414d4c0b // "AML" + 11
+
+// DigestRoster reads the analysts due a digest and records sent digests
+type DigestRoster interface {
+	ListDigestDue(ctx context.Context, scheduledAt time.Time) ([]*domain.Analyst, error)
+	MarkDigestSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+}
+
+// DigestInvestigationStore reads an analyst's open investigations
+type DigestInvestigationStore interface {
+	ListOpenByAssignee(ctx context.Context, analystID uuid.UUID, limit int) ([]*domain.Investigation, error)
+}
+
+// DigestAlertStore reads new alerts
+type DigestAlertStore interface {
+	ListNewSince(ctx context.Context, since time.Time, types []domain.AlertType, limit int) ([]*domain.AMLAlert, error)
+}
+
+// DigestFilingStore reads the filings an analyst may review
+type DigestFilingStore interface {
+	ListAwaitingReview(ctx context.Context, reviewerID uuid.UUID, limit int) ([]*domain.RegulatoryFiling, error)
+}
+
+// AnalystDigestJob emails each subscribed analyst a morning digest of their
+// open investigations, new alerts matching their alert filter and filings
+// awaiting their review. An analyst gets at most one digest a day, sent on
+// the first check at or after the configured time; a failed send is
+// retried on the next check.
+type AnalystDigestJob struct {
+	analysts       DigestRoster
+	investigations DigestInvestigationStore
+	alerts         DigestAlertStore
+	filings        DigestFilingStore
+	sender         email.Sender
+	locker         Locker
+	cfg            *config.DigestConfig
+	log            *logger.Logger
+}
+
+// NewAnalystDigestJob creates a new analyst digest job
+func NewAnalystDigestJob(
+	analysts DigestRoster,
+	investigations DigestInvestigationStore,
+	alerts DigestAlertStore,
+	filings DigestFilingStore,
+	sender email.Sender,
+	locker Locker,
+	cfg *config.DigestConfig,
+	log *logger.Logger,
+) *AnalystDigestJob {
+	return &AnalystDigestJob{
+		analysts:       analysts,
+		investigations: investigations,
+		alerts:         alerts,
+		filings:        filings,
+		sender:         sender,
+		locker:         locker,
+		cfg:            cfg,
+		log:            log.Named("analyst_digest"),
+	}
+}
+
+// Run checks for due digests on the configured interval until ctx is
+// cancelled
+func (j *AnalystDigestJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	j.log.Info("analyst digest job started",
+		logger.StringField("send_at", j.cfg.SendAt),
+		logger.StringField("timezone", j.cfg.Timezone),
+	)
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			j.log.Error("analyst digest run failed", logger.ErrorField(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			j.log.Info("analyst digest job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the digests due since the latest scheduled send time and
+// returns how many were sent. It is a no-op when another instance holds the
+// lock.
+func (j *AnalystDigestJob) RunOnce(ctx context.Context) (int, error) {
+	release, acquired, err := j.locker.TryLock(ctx, analystDigestLockKey)
+	if err != nil {
+		return 0, fmt.Errorf("acquire analyst digest lock: %w", err)
+	}
+	if !acquired {
+		j.log.Debug("analyst digest run skipped, another instance holds the lock")
+		return 0, nil
+	}
+	defer release()
+
+	now := time.Now()
+	scheduledAt, err := j.scheduledAt(now)
+	if err != nil {
+		return 0, err
+	}
+
+	analysts, err := j.analysts.ListDigestDue(ctx, scheduledAt)
+	if err != nil {
+		return 0, fmt.Errorf("list analysts due a digest: %w", err)
+	}
+
+	sent := 0
+	for _, a := range analysts {
+		if err := j.sendDigest(ctx, a, scheduledAt, now); err != nil {
+			metrics.RecordDigest("failed")
+			j.log.Error("failed to send analyst digest",
+				logger.StringField("analyst_id", a.ID.String()),
+				logger.ErrorField(err),
+			)
+			continue
+		}
+		sent++
+	}
+
+	if len(analysts) > 0 {
+		j.log.Info("analyst digests sent",
+			logger.IntField("due", len(analysts)),
+			logger.IntField("sent", sent),
+		)
+	}
+	return sent, nil
+}
+
+// scheduledAt returns the latest configured send time at or before now
+func (j *AnalystDigestJob) scheduledAt(now time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(j.cfg.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("load digest timezone: %w", err)
+	}
+	sendAt, err := time.Parse("15:04", j.cfg.SendAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse digest send time: %w", err)
+	}
+
+	local := now.In(loc)
+	t := time.Date(local.Year(), local.Month(), local.Day(), sendAt.Hour(), sendAt.Minute(), 0, 0, loc)
+	if t.After(local) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t, nil
+}
+
+// sendDigest compiles, renders and sends one analyst's digest. An analyst
+// with nothing to report gets no email but is still marked as done for the
+// day.
+func (j *AnalystDigestJob) sendDigest(ctx context.Context, a *domain.Analyst, scheduledAt, now time.Time) error {
+	digest, err := j.compile(ctx, a, scheduledAt, now)
+	if err != nil {
+		return err
+	}
+
+	if digest.Empty() {
+		metrics.RecordDigest("empty")
+	} else {
+		msg, err := email.RenderDigest(a.Email, digest)
+		if err != nil {
+			return err
+		}
+		if err := j.sender.Send(ctx, msg); err != nil {
+			return fmt.Errorf("send digest: %w", err)
+		}
+		metrics.RecordDigest("sent")
+	}
+
+	return j.analysts.MarkDigestSent(ctx, a.ID, now)
+}
+
+// compile gathers the analyst's work items. Each section is read one item
+// past the limit to tell whether there are more.
+func (j *AnalystDigestJob) compile(ctx context.Context, a *domain.Analyst, scheduledAt, now time.Time) (*email.Digest, error) {
+	limit := j.cfg.MaxItems
+	loc := scheduledAt.Location()
+	today := scheduledAt.Format(time.DateOnly)
+	digest := &email.Digest{AnalystName: a.Name, Date: scheduledAt}
+
+	investigations, err := j.investigations.ListOpenByAssignee(ctx, a.ID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list investigations: %w", err)
+	}
+	investigations, digest.MoreInvestigations = truncate(investigations, limit)
+	for _, inv := range investigations {
+		due := inv.DueDate.In(loc)
+		digest.Investigations = append(digest.Investigations, email.DigestInvestigation{
+			CaseNumber: inv.CaseNumber,
+			Title:      inv.Title,
+			Priority:   string(inv.Priority),
+			Status:     string(inv.Status),
+			DueDate:    due,
+			DueToday:   due.Format(time.DateOnly) == today,
+			Breached:   inv.SLABreached || inv.DueDate.Before(now),
+			Link:       j.link("investigation", inv.ID),
+		})
+	}
+
+	// New alerts are those raised since the last digest, or in the last
+	// day for an analyst's first
+	since := now.Add(-24 * time.Hour)
+	if a.LastDigestAt != nil {
+		since = *a.LastDigestAt
+	}
+	alerts, err := j.alerts.ListNewSince(ctx, since, a.AlertTypes, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list alerts: %w", err)
+	}
+	alerts, digest.MoreAlerts = truncate(alerts, limit)
+	for _, alert := range alerts {
+		digest.Alerts = append(digest.Alerts, email.DigestAlert{
+			AlertNumber: alert.AlertNumber,
+			Title:       alert.Title,
+			AlertType:   string(alert.AlertType),
+			Priority:    string(alert.Priority),
+			RiskScore:   alert.RiskScore,
+			Link:        j.link("alert", alert.ID),
+		})
+	}
+
+	filings, err := j.filings.ListAwaitingReview(ctx, a.ID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list filings: %w", err)
+	}
+	filings, digest.MoreFilings = truncate(filings, limit)
+	for _, f := range filings {
+		digest.Filings = append(digest.Filings, email.DigestFiling{
+			FilingNumber: f.FilingNumber,
+			FilingType:   string(f.FilingType),
+			DueDate:      f.FilingDueDate.In(loc),
+			Link:         j.link("filing", f.ID),
+		})
+	}
+
+	return digest, nil
+}
+
+// link fills the configured link template
+func (j *AnalystDigestJob) link(kind string, id uuid.UUID) string {
+	if j.cfg.LinkTemplate == "" {
+		return ""
+	}
+	return strings.NewReplacer("{kind}", kind, "{id}", id.String()).Replace(j.cfg.LinkTemplate)
+}
+
+// truncate cuts items to limit and reports whether any were cut
+func truncate[T any](items []T, limit int) ([]T, bool) {
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// batchScreeningLockKey is the advisory lock key shared by all batch runner instances
+const batchScreeningLockKey int64 = 0x414d4c03 // "AML" + 3
+
+// maxBatchWorkers caps concurrent name checks regardless of batch size
+const maxBatchWorkers = 32
+
+// BatchJobStore interface for batch job persistence
+type BatchJobStore interface {
+	Create(ctx context.Context, job *domain.BatchScreeningJob) error
+	Update(ctx context.Context, job *domain.BatchScreeningJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.BatchScreeningJob, error)
+	NextRunnable(ctx context.Context) (*domain.BatchScreeningJob, error)
+	SaveProgress(ctx context.Context, job *domain.BatchScreeningJob, results []domain.BatchResult) error
+	ListHits(ctx context.Context, jobID uuid.UUID) ([]domain.BatchResult, error)
+}
+
+// UserNameResolver resolves a customer ID to the name to screen
+type UserNameResolver interface {
+	ResolveUserName(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// OFACBatchChecker screens many names against the sanctions list with
+// bounded concurrency, returning the matches in input order. Names whose
+// check failed have no match.
+type OFACBatchChecker interface {
+	CheckBatch(ctx context.Context, names []string) ([]*domain.OFACMatch, error)
+}
+
+// PEPNameChecker screens a single name against the PEP list
+type PEPNameChecker interface {
+	Check(ctx context.Context, name string) (*domain.PEPMatch, error)
+}
+
+// BatchScreeningService screens large name lists outside the transaction
+// flow, e.g. re-screening the customer base after a sanctions list update
+type BatchScreeningService struct {
+	jobs     BatchJobStore
+	users    UserNameResolver
+	ofac     OFACBatchChecker
+	pep      PEPNameChecker
+	locker   Locker
+	log      *logger.Logger
+	wake     chan struct{}
+	chunk    int
+	workers  int
+	interval time.Duration
+}
+
+// NewBatchScreeningService creates a new batch screening service
+func NewBatchScreeningService(
+	jobs BatchJobStore,
+	users UserNameResolver,
+	ofac OFACBatchChecker,
+	pep PEPNameChecker,
+	locker Locker,
+	cfg *config.PatternsConfig,
+	log *logger.Logger,
+) *BatchScreeningService {
+	chunk := max(cfg.BatchSize, 1)
+
+	return &BatchScreeningService{
+		jobs:     jobs,
+		users:    users,
+		ofac:     ofac,
+		pep:      pep,
+		locker:   locker,
+		log:      log.Named("batch_screening"),
+		wake:     make(chan struct{}, 1),
+		chunk:    chunk,
+		workers:  min(chunk, maxBatchWorkers),
+		interval: cfg.BatchInterval,
+	}
+}
+
+// Enqueue validates a request, resolves user IDs to names and stores a
+// pending job for the background runner
+func (s *BatchScreeningService) Enqueue(ctx context.Context, req *domain.BatchScreeningRequest) (*domain.BatchScreeningJob, error) {
+	total := len(req.Names) + len(req.UserIDs)
+	if total == 0 {
+		return nil, fmt.Errorf("%w: names or user_ids are required", domain.ErrValidation)
+	}
+	if total > domain.MaxBatchScreeningItems {
+		return nil, fmt.Errorf("%w: at most %d items per batch", domain.ErrValidation, domain.MaxBatchScreeningItems)
+	}
+
+	items := make([]domain.BatchItem, 0, total)
+	for _, name := range req.Names {
+		items = append(items, domain.BatchItem{Name: name})
+	}
+	for _, id := range req.UserIDs {
+		userID := id
+		name, err := s.users.ResolveUserName(ctx, userID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("resolve user %s: %w", userID, err)
+		}
+		// Unresolved users are kept so the result shows they were not screened
+		items = append(items, domain.BatchItem{Name: name, UserID: &userID})
+	}
+
+	now := time.Now()
+	job := &domain.BatchScreeningJob{
+		ID:         uuid.New(),
+		Status:     domain.BatchJobStatusPending,
+		Items:      items,
+		TotalItems: len(items),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("create batch job: %w", err)
+	}
+
+	s.log.Info("batch screening job enqueued",
+		logger.StringField("job_id", job.ID.String()),
+		logger.IntField("items", job.TotalItems),
+	)
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// GetJob returns a batch job by ID
+func (s *BatchScreeningService) GetJob(ctx context.Context, id uuid.UUID) (*domain.BatchScreeningJob, error) {
+	return s.jobs.GetByID(ctx, id)
+}
+
+// ListHits returns a job's results that matched a list
+func (s *BatchScreeningService) ListHits(ctx context.Context, id uuid.UUID) ([]domain.BatchResult, error) {
+	return s.jobs.ListHits(ctx, id)
+}
+
+// Run processes queued jobs until ctx is cancelled. Jobs interrupted by a
+// restart are resumed from their persisted cursor.
+func (s *BatchScreeningService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.log.Info("batch screening runner started",
+		logger.IntField("chunk_size", s.chunk),
+		logger.IntField("workers", s.workers),
+	)
+
+	for {
+		if err := s.RunOnce(ctx); err != nil {
+			s.log.Error("batch screening run failed", logger.ErrorField(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			s.log.Info("batch screening runner stopped")
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce processes runnable jobs until none remain. It is a no-op when
+// another instance holds the runner lock.
+func (s *BatchScreeningService) RunOnce(ctx context.Context) error {
+	release, acquired, err := s.locker.TryLock(ctx, batchScreeningLockKey)
+	if err != nil {
+		return fmt.Errorf("acquire batch screening lock: %w", err)
+	}
+	if !acquired {
+		return nil
+	}
+	defer release()
+
+	for ctx.Err() == nil {
+		job, err := s.jobs.NextRunnable(ctx)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("next batch job: %w", err)
+		}
+
+		if err := s.process(ctx, job); err != nil {
+			if ctx.Err() != nil {
+				// Shutting down; the job resumes from its cursor on restart
+				return nil
+			}
+			s.fail(ctx, job, err)
+		}
+	}
+
+	return nil
+}
+
+// process screens a job's remaining items chunk by chunk, persisting the
+// cursor after each chunk
+func (s *BatchScreeningService) process(ctx context.Context, job *domain.BatchScreeningJob) error {
+	if job.Status == domain.BatchJobStatusPending {
+		now := time.Now()
+		job.Status = domain.BatchJobStatusRunning
+		job.StartedAt = &now
+		job.UpdatedAt = now
+		if err := s.jobs.Update(ctx, job); err != nil {
+			return fmt.Errorf("start batch job: %w", err)
+		}
+	} else {
+		s.log.Info("resuming batch screening job",
+			logger.StringField("job_id", job.ID.String()),
+			logger.IntField("cursor", job.Cursor),
+		)
+	}
+
+	for job.Cursor < len(job.Items) {
+		end := min(job.Cursor+s.chunk, len(job.Items))
+
+		results, err := s.screenChunk(ctx, job, job.Cursor, end)
+		if err != nil {
+			return err
+		}
+
+		for _, res := range results {
+			if res.Hit {
+				job.HitCount++
+			}
+		}
+		job.Cursor = end
+		job.UpdatedAt = time.Now()
+
+		if err := s.jobs.SaveProgress(ctx, job, results); err != nil {
+			return fmt.Errorf("save batch progress: %w", err)
+		}
+	}
+
+	now := time.Now()
+	job.Status = domain.BatchJobStatusCompleted
+	job.CompletedAt = &now
+	job.UpdatedAt = now
+	if err := s.jobs.Update(ctx, job); err != nil {
+		return fmt.Errorf("complete batch job: %w", err)
+	}
+
+	s.log.Info("batch screening job completed",
+		logger.StringField("job_id", job.ID.String()),
+		logger.IntField("items", job.TotalItems),
+		logger.IntField("hits", job.HitCount),
+	)
+	return nil
+}
+
+// screenChunk runs OFAC and PEP checks for items [start, end) using at most
+// s.workers concurrent checks
+func (s *BatchScreeningService) screenChunk(ctx context.Context, job *domain.BatchScreeningJob, start, end int) ([]domain.BatchResult, error) {
+	items := job.Items[start:end]
+
+	// Unresolved names are checked too, cheaply, to keep matches aligned
+	// with items
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+
+	// Failed names are reported per item as unavailable
+	ofacMatches, err := s.ofac.CheckBatch(ctx, names)
+	var batchErr *screening.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return nil, fmt.Errorf("ofac batch check: %w", err)
+	}
+
+	pepMatches := make([]*domain.PEPMatch, len(items))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.workers)
+	for i, item := range items {
+		if item.Name == "" {
+			continue
+		}
+		g.Go(func() error {
+			match, err := s.pep.Check(gctx, item.Name)
+			if err != nil {
+				s.log.Warn("batch pep check failed", logger.ErrorField(err))
+				return nil
+			}
+			pepMatches[i] = match
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	results := make([]domain.BatchResult, len(items))
+	for i, item := range items {
+		res := domain.BatchResult{
+			JobID:      job.ID,
+			Seq:        start + i,
+			Name:       item.Name,
+			UserID:     item.UserID,
+			ScreenedAt: now,
+		}
+
+		if item.Name == "" {
+			res.Error = "name could not be resolved"
+		} else {
+			res.OFACMatch = ofacMatches[i]
+			res.PEPMatch = pepMatches[i]
+			if res.OFACMatch == nil || res.PEPMatch == nil {
+				res.Error = "check unavailable"
+			}
+			res.Hit = (res.OFACMatch != nil && res.OFACMatch.Matched) ||
+				(res.PEPMatch != nil && res.PEPMatch.Matched)
+		}
+
+		results[i] = res
+	}
+
+	return results, nil
+}
+
+// fail marks a job as failed after an unrecoverable error
+func (s *BatchScreeningService) fail(ctx context.Context, job *domain.BatchScreeningJob, cause error) {
+	s.log.Error("batch screening job failed",
+		logger.StringField("job_id", job.ID.String()),
+		logger.ErrorField(cause),
+	)
+
+	now := time.Now()
+	job.Status = domain.BatchJobStatusFailed
+	job.Error = cause.Error()
+	job.CompletedAt = &now
+	job.UpdatedAt = now
+	if err := s.jobs.Update(ctx, job); err != nil {
+		s.log.Error("failed to mark batch job failed", logger.ErrorField(err))
+	}
+}
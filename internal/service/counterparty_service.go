@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// counterpartyRebuildBatchSize is how many screenings or SARs one rebuild
+// page reads
+const counterpartyRebuildBatchSize = 1000
+
+// CounterpartyStore reads and rebuilds counterparty reputations
+type CounterpartyStore interface {
+	ListByAccount(ctx context.Context, account string) ([]*domain.CounterpartyReputation, error)
+	Replace(ctx context.Context, reps []*domain.CounterpartyReputation) error
+}
+
+// CounterpartyScreenings reads the screenings counterparty reputations are
+// built from
+type CounterpartyScreenings interface {
+	ListFlaggedScreenings(ctx context.Context, after uuid.UUID, limit int) ([]domain.FlaggedScreening, error)
+	ListLatestByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*domain.ScreeningResult, error)
+}
+
+// SubmittedSARLister reads the SAR filings counterparty reputations are
+// built from
+type SubmittedSARLister interface {
+	ListSubmittedSARs(ctx context.Context, after uuid.UUID, limit int) ([]domain.SubmittedSAR, error)
+}
+
+// CounterpartyService serves counterparty reputations to investigators and
+// rebuilds them from the stored screening results and SAR filings
+type CounterpartyService struct {
+	store      CounterpartyStore
+	screenings CounterpartyScreenings
+	sars       SubmittedSARLister
+	checker    *screening.CounterpartyChecker
+	clock      clock.Clock
+	log        *logger.Logger
+}
+
+// NewCounterpartyService creates a new counterparty service. The checker
+// weighs reputations as screening does.
+func NewCounterpartyService(store CounterpartyStore, screenings CounterpartyScreenings, sars SubmittedSARLister, checker *screening.CounterpartyChecker, clk clock.Clock, log *logger.Logger) *CounterpartyService {
+	return &CounterpartyService{
+		store:      store,
+		screenings: screenings,
+		sars:       sars,
+		checker:    checker,
+		clock:      clk,
+		log:        log.Named("counterparty_service"),
+	}
+}
+
+// Get returns what the tenant ctx acts for knows about an account at each
+// bank it has been seen with, decayed to now. An account never flagged has no reputations.
+func (s *CounterpartyService) Get(ctx context.Context, account string) (*domain.CounterpartyReputationResponse, error) {
+	normalized := domain.NormalizeAccount(account)
+	if normalized == "" {
+		return nil, fmt.Errorf("%w: account is required", domain.ErrValidation)
+	}
+
+	reps, err := s.store.ListByAccount(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	halfLife := s.checker.HalfLife()
+	for i, rep := range reps {
+		reps[i] = rep.DecayedTo(now, halfLife)
+		reps[i].Weight = s.checker.Weight(reps[i])
+	}
+
+	return &domain.CounterpartyReputationResponse{
+		Account:      normalized,
+		Reputations:  reps,
+		HalfLifeDays: halfLife.Hours() / 24,
+	}, nil
+}
+
+// Rebuild recomputes every counterparty reputation of the tenant ctx acts
+// for, or of every tenant when it names none, from the latest screening of
+// each stored transaction and the submitted SARs, and replaces the store
+// with the result. Events recorded by live screening
+// while it runs are lost, so run it when traffic is quiet. It returns the
+// number of counterparties rebuilt.
+func (s *CounterpartyService) Rebuild(ctx context.Context) (int, error) {
+	halfLife := s.checker.HalfLife()
+	reps := make(map[domain.CounterpartyKey]*domain.CounterpartyReputation)
+	apply := func(event domain.CounterpartyEvent) {
+		rep, ok := reps[event.Key]
+		if !ok {
+			rep = &domain.CounterpartyReputation{CounterpartyKey: event.Key}
+			reps[event.Key] = rep
+		}
+		rep.Apply(event, halfLife)
+	}
+
+	screenings, err := s.replayScreenings(ctx, apply)
+	if err != nil {
+		return 0, err
+	}
+	sars, err := s.replaySARs(ctx, apply)
+	if err != nil {
+		return 0, err
+	}
+
+	rebuilt := make([]*domain.CounterpartyReputation, 0, len(reps))
+	for _, rep := range reps {
+		rebuilt = append(rebuilt, rep)
+	}
+	if err := s.store.Replace(ctx, rebuilt); err != nil {
+		return 0, err
+	}
+
+	s.log.Info("counterparty reputations rebuilt",
+		logger.IntField("counterparties", len(rebuilt)),
+		logger.IntField("screenings", screenings),
+		logger.IntField("sars", sars),
+	)
+	return len(rebuilt), nil
+}
+
+// replayScreenings applies the latest blocked or suspicious screening of
+// each transaction and returns how many were applied
+func (s *CounterpartyService) replayScreenings(ctx context.Context, apply func(domain.CounterpartyEvent)) (int, error) {
+	var n int
+	after := uuid.Nil
+	for {
+		page, err := s.screenings.ListFlaggedScreenings(ctx, after, counterpartyRebuildBatchSize)
+		if err != nil {
+			return n, fmt.Errorf("list flagged screenings: %w", err)
+		}
+		for _, f := range page {
+			if event, ok := screening.DecisionEvent(f.TenantID, f.Transaction, f.Decision, f.ScreenedAt); ok {
+				apply(event)
+				n++
+			}
+		}
+		if len(page) < counterpartyRebuildBatchSize {
+			return n, nil
+		}
+		after = page[len(page)-1].TransactionID
+	}
+}
+
+// replaySARs applies each submitted SAR to the counterparties of the
+// transactions it covers and returns how many SARs were read
+func (s *CounterpartyService) replaySARs(ctx context.Context, apply func(domain.CounterpartyEvent)) (int, error) {
+	var n int
+	after := uuid.Nil
+	for {
+		page, err := s.sars.ListSubmittedSARs(ctx, after, counterpartyRebuildBatchSize)
+		if err != nil {
+			return n, fmt.Errorf("list submitted sars: %w", err)
+		}
+		for _, sar := range page {
+			events, err := s.sarEvents(ctx, sar.TransactionIDs, sar.SubmittedAt)
+			if err != nil {
+				return n, err
+			}
+			for _, event := range events {
+				apply(event)
+			}
+			n++
+		}
+		if len(page) < counterpartyRebuildBatchSize {
+			return n, nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
+
+// RecordSAR counts a submitted SAR against the counterparties of the
+// transactions it covers
+func (s *CounterpartyService) RecordSAR(ctx context.Context, transactionIDs []uuid.UUID, at time.Time) error {
+	events, err := s.sarEvents(ctx, transactionIDs, at)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := s.checker.Record(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sarEvents returns one SAR event for each counterparty of the given
+// transactions
+func (s *CounterpartyService) sarEvents(ctx context.Context, transactionIDs []uuid.UUID, at time.Time) ([]domain.CounterpartyEvent, error) {
+	if len(transactionIDs) == 0 {
+		return nil, nil
+	}
+	results, err := s.screenings.ListLatestByTransactionIDs(ctx, transactionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("load sar transactions: %w", err)
+	}
+
+	seen := make(map[domain.CounterpartyKey]bool, len(results))
+	var events []domain.CounterpartyEvent
+	for _, result := range results {
+		if result.Transaction == nil {
+			continue
+		}
+		key, ok := result.Transaction.Counterparty()
+		if !ok {
+			continue
+		}
+		key.TenantID = result.TenantID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		events = append(events, domain.CounterpartyEvent{Key: key, SARs: 1, At: at})
+	}
+	return events, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// CountryRiskStore persists versions of the country risk table
+type CountryRiskStore interface {
+	Create(ctx context.Context, table *domain.CountryRiskTable) error
+	Latest(ctx context.Context) (*domain.CountryRiskTable, error)
+	Get(ctx context.Context, version int) (*domain.CountryRiskTable, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.CountryRiskTable, error)
+}
+
+// CountryRiskService manages the country risk table the risk calculator
+// scores counterparty countries against. The configured table, version 0,
+// is in force until a table is saved; the newest saved version is in force
+// after that. A save is applied to this instance at once and is picked up
+// by other instances on their next refresh.
+type CountryRiskService struct {
+	tables     CountryRiskStore
+	risk       *screening.CountryRisk
+	configured *domain.CountryRiskTable
+	auditor    Auditor
+	interval   time.Duration
+	log        *logger.Logger
+}
+
+// NewCountryRiskService creates a new country risk service. risk is the
+// holder the enforced risk calculator reads; interval is how often saved
+// tables are reloaded.
+func NewCountryRiskService(
+	tables CountryRiskStore,
+	risk *screening.CountryRisk,
+	configured *domain.CountryRiskTable,
+	auditor Auditor,
+	interval time.Duration,
+	log *logger.Logger,
+) *CountryRiskService {
+	return &CountryRiskService{
+		tables:     tables,
+		risk:       risk,
+		configured: configured,
+		auditor:    auditor,
+		interval:   interval,
+		log:        log.Named("country_risk_service"),
+	}
+}
+
+// Current returns the table in force
+func (s *CountryRiskService) Current() *domain.CountryRiskTable {
+	return s.risk.Table()
+}
+
+// Get returns a version of the table; version 0 is the configured table
+func (s *CountryRiskService) Get(ctx context.Context, version int) (*domain.CountryRiskTable, error) {
+	if version == 0 {
+		return s.configured, nil
+	}
+	return s.tables.Get(ctx, version)
+}
+
+// List returns a page of saved versions, newest first
+func (s *CountryRiskService) List(ctx context.Context, limit, offset int) ([]*domain.CountryRiskTable, error) {
+	return s.tables.List(ctx, limit, offset)
+}
+
+// Update saves the requested tiers as the next version and puts it in
+// force. ErrConflict is returned if the newest version is not the one the
+// change was made against.
+func (s *CountryRiskService) Update(ctx context.Context, req *domain.UpdateCountryRiskTableRequest) (*domain.CountryRiskTable, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	before, err := s.latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if before.Version != req.BaseVersion {
+		return nil, fmt.Errorf("%w: the country risk table is at version %d, not %d", domain.ErrConflict, before.Version, req.BaseVersion)
+	}
+
+	table := &domain.CountryRiskTable{
+		Version:      before.Version + 1,
+		Tiers:        req.Tiers,
+		Ratings:      req.Ratings,
+		RatingPoints: req.RatingPoints,
+		Comment:      req.Comment,
+		CreatedBy:    req.ActorID,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := s.tables.Create(ctx, table); err != nil {
+		return nil, err
+	}
+	s.risk.Set(table)
+
+	err = s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     audit.ActionCountryRiskTableChanged,
+		EntityType: audit.EntityCountryRiskTable,
+		EntityID:   strconv.Itoa(table.Version),
+		Before: map[string]interface{}{
+			"version":       before.Version,
+			"tiers":         before.Tiers,
+			"ratings":       before.Ratings,
+			"rating_points": before.RatingPoints,
+		},
+		After: map[string]interface{}{
+			"version":       table.Version,
+			"tiers":         table.Tiers,
+			"ratings":       table.Ratings,
+			"rating_points": table.RatingPoints,
+			"comment":       table.Comment,
+		},
+	})
+	if err != nil {
+		s.log.Error("failed to audit country risk table change",
+			logger.IntField("version", table.Version),
+			logger.ErrorField(err),
+		)
+	}
+
+	s.log.Info("country risk table saved",
+		logger.IntField("version", table.Version),
+		logger.IntField("tiers", len(table.Tiers)),
+		logger.IntField("ratings", len(table.Ratings)),
+		logger.StringField("actor_id", req.ActorID.String()),
+	)
+	return table, nil
+}
+
+// Refresh puts the newest saved version in force, or the configured table
+// if none was saved
+func (s *CountryRiskService) Refresh(ctx context.Context) error {
+	table, err := s.latest(ctx)
+	if err != nil {
+		return err
+	}
+	if current := s.risk.Table(); current.Version == table.Version {
+		return nil
+	}
+
+	s.risk.Set(table)
+	s.log.Info("country risk table loaded", logger.IntField("version", table.Version))
+	return nil
+}
+
+// Run refreshes the table on the configured interval until ctx is
+// cancelled
+func (s *CountryRiskService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				s.log.Error("failed to refresh country risk table", logger.ErrorField(err))
+			}
+		}
+	}
+}
+
+// latest returns the newest saved version, or the configured table
+func (s *CountryRiskService) latest(ctx context.Context) (*domain.CountryRiskTable, error) {
+	table, err := s.tables.Latest(ctx)
+	if errors.Is(err, domain.ErrNotFound) {
+		return s.configured, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest country risk table: %w", err)
+	}
+	return table, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+const (
+	// graphLookback is how far back transactions link entities
+	graphLookback = 90 * 24 * time.Hour
+
+	// graphMaxNodes caps the size of an entity graph
+	graphMaxNodes = 200
+)
+
+// InvestigationReader loads investigations
+type InvestigationReader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Investigation, error)
+}
+
+// EntityLinkStore reads counterparty and case links from persisted data
+type EntityLinkStore interface {
+	ListCounterparties(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]domain.CounterpartyLink, error)
+	ListCounterpartyUsers(ctx context.Context, keys []string, exclude uuid.UUID, since time.Time, limit int) ([]domain.CounterpartyLink, error)
+	ListCases(ctx context.Context, userIDs []uuid.UUID, limit int) ([]domain.CaseLink, error)
+}
+
+// EntityGraphService builds linked-entity graphs for investigations
+type EntityGraphService struct {
+	investigations InvestigationReader
+	links          EntityLinkStore
+	log            *logger.Logger
+}
+
+// NewEntityGraphService creates a new entity graph service
+func NewEntityGraphService(investigations InvestigationReader, links EntityLinkStore, log *logger.Logger) *EntityGraphService {
+	return &EntityGraphService{
+		investigations: investigations,
+		links:          links,
+		log:            log.Named("entity_graph"),
+	}
+}
+
+// Build returns the graph around an investigation's subject: their
+// counterparties in the last 90 days (1 hop), other users of those
+// counterparties (2 hops), and the investigations, alerts and filings on
+// every user in the graph. The graph stops growing at 200 nodes.
+func (s *EntityGraphService) Build(ctx context.Context, investigationID uuid.UUID) (*domain.EntityGraph, error) {
+	inv, err := s.investigations.GetByID(ctx, investigationID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-graphLookback)
+	graph := domain.NewEntityGraph(inv.ID, since, graphMaxNodes)
+	graph.SubjectID = domain.UserNodeID(inv.UserID)
+	graph.AddNode(&domain.GraphNode{
+		ID:        graph.SubjectID,
+		Type:      domain.GraphNodeUser,
+		Label:     inv.UserID.String(),
+		RiskScore: inv.RiskScore,
+	})
+
+	// Hop 1: the subject's counterparties
+	counterparties, err := s.links.ListCounterparties(ctx, inv.UserID, since, graphMaxNodes)
+	if err != nil {
+		return nil, fmt.Errorf("list counterparties: %w", err)
+	}
+	keys := make([]string, 0, len(counterparties))
+	for _, cp := range counterparties {
+		if !addLink(graph, cp, 1) {
+			break
+		}
+		keys = append(keys, cp.Key)
+	}
+
+	// Hop 2: other users of the same counterparties
+	users := []uuid.UUID{inv.UserID}
+	if len(keys) > 0 && !graph.Truncated {
+		shared, err := s.links.ListCounterpartyUsers(ctx, keys, inv.UserID, since, graphMaxNodes)
+		if err != nil {
+			return nil, fmt.Errorf("list counterparty users: %w", err)
+		}
+		seen := make(map[uuid.UUID]bool)
+		for _, link := range shared {
+			if !graph.AddNode(&domain.GraphNode{
+				ID:        domain.UserNodeID(link.UserID),
+				Type:      domain.GraphNodeUser,
+				Label:     link.UserID.String(),
+				RiskScore: link.RiskScore,
+				Hops:      2,
+			}) {
+				break
+			}
+			addLink(graph, link, 1)
+			if !seen[link.UserID] {
+				seen[link.UserID] = true
+				users = append(users, link.UserID)
+			}
+		}
+	}
+
+	// Cases on every user in the graph
+	if !graph.Truncated {
+		cases, err := s.links.ListCases(ctx, users, graphMaxNodes)
+		if err != nil {
+			return nil, fmt.Errorf("list cases: %w", err)
+		}
+		for _, c := range cases {
+			id := string(c.Type) + ":" + c.ID.String()
+			if !graph.AddNode(&domain.GraphNode{
+				ID:        id,
+				Type:      c.Type,
+				Label:     c.Label,
+				RiskScore: c.RiskScore,
+				Status:    c.Status,
+				Hops:      graph.HopsTo(domain.UserNodeID(c.UserID)) + 1,
+			}) {
+				break
+			}
+			graph.AddEdge(&domain.GraphEdge{
+				Source: domain.UserNodeID(c.UserID),
+				Target: id,
+				Type:   domain.GraphEdgeCase,
+			})
+		}
+	}
+
+	s.log.Debug("entity graph built",
+		logger.StringField("investigation_id", inv.ID.String()),
+		logger.IntField("nodes", len(graph.Nodes)),
+		logger.IntField("edges", len(graph.Edges)),
+		logger.BoolField("truncated", graph.Truncated),
+	)
+
+	return graph, nil
+}
+
+// addLink adds a counterparty node and its edge from the link's user. It
+// returns false once the graph is full.
+func addLink(graph *domain.EntityGraph, link domain.CounterpartyLink, hops int) bool {
+	id := "counterparty:" + link.Key
+	label := link.Name
+	if label == "" {
+		label = link.Account
+	}
+	if !graph.AddNode(&domain.GraphNode{
+		ID:        id,
+		Type:      domain.GraphNodeCounterparty,
+		Label:     label,
+		RiskScore: link.RiskScore,
+		Hops:      hops,
+	}) {
+		return false
+	}
+	graph.AddEdge(&domain.GraphEdge{
+		Source:  domain.UserNodeID(link.UserID),
+		Target:  id,
+		Type:    domain.GraphEdgeTransactedWith,
+		TxCount: link.TxCount,
+	})
+	return true
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// ObjectStore holds evidence files. Implementations exist for local disk
+// and S3.
+type ObjectStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// EvidenceStore persists evidence records on investigations
+type EvidenceStore interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Investigation, error)
+	AddEvidence(ctx context.Context, id uuid.UUID, ev *domain.Evidence, event *domain.InvestigationTimeline) error
+	UpdateEvidence(
+		ctx context.Context,
+		id, evidenceID uuid.UUID,
+		fn func(*domain.Evidence) (*domain.InvestigationTimeline, error),
+	) (*domain.Evidence, error)
+}
+
+// EvidenceService attaches files to investigations. Each file's SHA-256 is
+// recorded on upload and checked whenever the file is read back.
+type EvidenceService struct {
+	investigations EvidenceStore
+	objects        ObjectStore
+	auditor        Auditor
+	log            *logger.Logger
+}
+
+// NewEvidenceService creates a new evidence service
+func NewEvidenceService(investigations EvidenceStore, objects ObjectStore, auditor Auditor, log *logger.Logger) *EvidenceService {
+	return &EvidenceService{
+		investigations: investigations,
+		objects:        objects,
+		auditor:        auditor,
+		log:            log.Named("evidence_service"),
+	}
+}
+
+// Add stores a file and attaches it to an open investigation
+func (s *EvidenceService) Add(ctx context.Context, investigationID uuid.UUID, req *domain.AddEvidenceRequest, data []byte) (*domain.Evidence, error) {
+	inv, err := s.investigations.GetByID(ctx, investigationID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.IsClosed() {
+		return nil, fmt.Errorf("%w: investigation %s is closed", domain.ErrConflict, inv.CaseNumber)
+	}
+
+	now := time.Now()
+	sum := sha256.Sum256(data)
+	ev := &domain.Evidence{
+		ID:          uuid.New(),
+		Type:        req.Type,
+		Description: req.Description,
+		AddedBy:     req.AddedBy,
+		AddedAt:     now,
+		FileName:    req.FileName,
+		ContentType: req.ContentType,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+	ev.ObjectKey = domain.EvidenceObjectKey(inv.ID, ev.ID)
+	ev.Reference = ev.ObjectKey
+
+	// Store the file before the record so a record never points at a
+	// missing object; an orphaned object after a failed insert is harmless
+	if err := s.objects.Put(ctx, ev.ObjectKey, ev.ContentType, data); err != nil {
+		return nil, fmt.Errorf("store evidence file: %w", err)
+	}
+
+	event := &domain.InvestigationTimeline{
+		ID:              uuid.New(),
+		InvestigationID: inv.ID,
+		EventType:       domain.TimelineEventEvidenceAdded,
+		Description:     fmt.Sprintf("%s evidence %q attached (%d bytes)", ev.Type, ev.FileName, ev.Size),
+		NewValue:        ev.SHA256,
+		ActorID:         req.AddedBy,
+		CreatedAt:       now,
+	}
+	if err := s.investigations.AddEvidence(ctx, inv.ID, ev, event); err != nil {
+		return nil, fmt.Errorf("add evidence: %w", err)
+	}
+
+	s.audit(ctx, req.AddedBy, audit.ActionEvidenceAdded, inv.ID, nil, map[string]interface{}{
+		"evidence_id": ev.ID,
+		"file_name":   ev.FileName,
+		"sha256":      ev.SHA256,
+		"size":        ev.Size,
+	})
+
+	return ev, nil
+}
+
+// Open returns an evidence file after checking it against its recorded
+// hash. Withdrawn evidence can still be read for audit. A mismatch returns
+// domain.ErrIntegrity.
+func (s *EvidenceService) Open(ctx context.Context, investigationID, evidenceID uuid.UUID) (*domain.Evidence, []byte, error) {
+	inv, err := s.investigations.GetByID(ctx, investigationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ev, err := inv.FindEvidence(evidenceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ev.HasFile() {
+		return nil, nil, fmt.Errorf("%w: evidence has no attached file", domain.ErrNotFound)
+	}
+
+	data, err := s.objects.Get(ctx, ev.ObjectKey)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			s.log.Error("evidence file missing from object store",
+				logger.StringField("investigation_id", inv.ID.String()),
+				logger.StringField("evidence_id", ev.ID.String()),
+			)
+			return nil, nil, fmt.Errorf("%w: evidence file is missing", domain.ErrIntegrity)
+		}
+		return nil, nil, fmt.Errorf("read evidence file: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != ev.SHA256 {
+		s.log.Error("evidence file failed integrity check",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.StringField("evidence_id", ev.ID.String()),
+			logger.StringField("expected_sha256", ev.SHA256),
+			logger.StringField("actual_sha256", got),
+		)
+		return nil, nil, fmt.Errorf("%w: evidence file hash does not match", domain.ErrIntegrity)
+	}
+
+	return ev, data, nil
+}
+
+// Delete withdraws evidence. The record, hash and file are kept; only the
+// deletion is recorded.
+func (s *EvidenceService) Delete(ctx context.Context, investigationID, evidenceID uuid.UUID, req *domain.DeleteEvidenceRequest) (*domain.Evidence, error) {
+	now := time.Now()
+	ev, err := s.investigations.UpdateEvidence(ctx, investigationID, evidenceID,
+		func(ev *domain.Evidence) (*domain.InvestigationTimeline, error) {
+			if ev.IsDeleted() {
+				return nil, fmt.Errorf("%w: evidence is already deleted", domain.ErrConflict)
+			}
+			ev.DeletedAt = &now
+			ev.DeletedBy = &req.ActorID
+			ev.DeleteReason = req.Reason
+
+			return &domain.InvestigationTimeline{
+				ID:              uuid.New(),
+				InvestigationID: investigationID,
+				EventType:       domain.TimelineEventEvidenceDeleted,
+				Description:     fmt.Sprintf("%s evidence %q withdrawn: %s", ev.Type, ev.FileName, req.Reason),
+				OldValue:        ev.SHA256,
+				ActorID:         req.ActorID,
+				CreatedAt:       now,
+			}, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, req.ActorID, audit.ActionEvidenceDeleted, investigationID,
+		map[string]interface{}{"evidence_id": ev.ID, "sha256": ev.SHA256},
+		map[string]interface{}{"deleted": true, "reason": req.Reason},
+	)
+
+	return ev, nil
+}
+
+func (s *EvidenceService) audit(ctx context.Context, actor uuid.UUID, action string, investigationID uuid.UUID, before, after map[string]interface{}) {
+	err := s.auditor.Record(ctx, audit.Entry{
+		ActorID:    actor,
+		Action:     action,
+		EntityType: audit.EntityInvestigation,
+		EntityID:   investigationID.String(),
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		s.log.Error("failed to audit evidence change",
+			logger.StringField("investigation_id", investigationID.String()),
+			logger.StringField("action", action),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// List returns an investigation's evidence, including withdrawn items
+func (s *EvidenceService) List(ctx context.Context, investigationID uuid.UUID) ([]domain.Evidence, error) {
+	inv, err := s.investigations.GetByID(ctx, investigationID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Evidence == nil {
+		return []domain.Evidence{}, nil
+	}
+	return inv.Evidence, nil
+}
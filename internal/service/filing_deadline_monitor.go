@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// filingMonitorLockKey is the advisory lock key shared by all filing deadline monitor instances
+const filingMonitorLockKey int64 = 0x414d4c02 // "AML" + 2
+
+// filingScanBatchSize limits how many filings one scan processes
+const filingScanBatchSize = 500
+
+// FilingDeadlineMonitor warns as SAR deadlines approach and raises
+// critical alerts once they are missed. Missed deadlines are also
+// published to webhook endpoints.
+type FilingDeadlineMonitor struct {
+	filings  FilingStore
+	alerts   AlertStore
+	webhooks WebhookPublisher
+	locker   Locker
+	clock    clock.Clock
+	cfg      *config.ComplianceConfig
+	log      *logger.Logger
+
+	// Lead times in days, largest first
+	leadDays []int
+}
+
+// NewFilingDeadlineMonitor creates a new filing deadline monitor
+func NewFilingDeadlineMonitor(
+	filings FilingStore,
+	alerts AlertStore,
+	webhooks WebhookPublisher,
+	locker Locker,
+	clk clock.Clock,
+	cfg *config.ComplianceConfig,
+	log *logger.Logger,
+) *FilingDeadlineMonitor {
+	leadDays := slices.Clone(cfg.SARDeadlineWarningDays)
+	slices.Sort(leadDays)
+	slices.Reverse(leadDays)
+
+	return &FilingDeadlineMonitor{
+		filings:  filings,
+		alerts:   alerts,
+		webhooks: webhooks,
+		locker:   locker,
+		clock:    clk,
+		cfg:      cfg,
+		log:      log.Named("filing_deadline_monitor"),
+		leadDays: leadDays,
+	}
+}
+
+// Run scans on the configured interval until ctx is cancelled
+func (m *FilingDeadlineMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.FilingScanInterval)
+	defer ticker.Stop()
+
+	m.log.Info("filing deadline monitor started", logger.DurationField("interval", m.cfg.FilingScanInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.log.Info("filing deadline monitor stopped")
+			return
+		case <-ticker.C:
+			if _, err := m.RunOnce(ctx); err != nil {
+				m.log.Error("filing deadline scan failed", logger.ErrorField(err))
+			}
+		}
+	}
+}
+
+// RunOnce performs a single scan and returns the number of alerts raised.
+// It is a no-op when another instance holds the scan lock.
+func (m *FilingDeadlineMonitor) RunOnce(ctx context.Context) (int, error) {
+	release, acquired, err := m.locker.TryLock(ctx, filingMonitorLockKey)
+	if err != nil {
+		return 0, fmt.Errorf("acquire filing monitor lock: %w", err)
+	}
+	if !acquired {
+		m.log.Debug("filing deadline scan skipped, another instance holds the lock")
+		return 0, nil
+	}
+	defer release()
+
+	now := m.clock.Now()
+	horizon := now
+	if len(m.leadDays) > 0 {
+		horizon = now.AddDate(0, 0, m.leadDays[0])
+	}
+
+	filings, err := m.filings.ListOpenSARsDueBefore(ctx, horizon, filingScanBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list open sars: %w", err)
+	}
+
+	raised := 0
+	for _, f := range filings {
+		notified, err := m.check(ctx, f, now)
+		if err != nil {
+			m.log.Error("failed to process sar deadline",
+				logger.StringField("filing_id", f.ID.String()),
+				logger.ErrorField(err),
+			)
+			continue
+		}
+		if notified {
+			raised++
+		}
+	}
+
+	if raised > 0 {
+		m.log.Info("filing deadline scan completed", logger.IntField("alerts", raised))
+	}
+	return raised, nil
+}
+
+// check raises at most one alert for a filing: critical once overdue,
+// otherwise a warning the first time each lead time is crossed
+func (m *FilingDeadlineMonitor) check(ctx context.Context, f *domain.RegulatoryFiling, now time.Time) (bool, error) {
+	daysLeft := f.DaysUntilDue(now)
+
+	if !now.Before(f.FilingDueDate) {
+		alert := m.newAlert(f, now, domain.RiskLevelCritical, "SAR_FILING_OVERDUE",
+			fmt.Sprintf("SAR %s is overdue", f.FilingNumber),
+			fmt.Sprintf("SAR %s was due %s and has not been submitted", f.FilingNumber, f.FilingDueDate.Format(time.RFC3339)),
+		)
+		if err := m.alerts.Create(ctx, alert); err != nil {
+			return false, fmt.Errorf("create alert: %w", err)
+		}
+
+		f.OverdueAlertedAt = &now
+		f.UpdatedAt = now
+		if err := m.filings.Update(ctx, f); err != nil {
+			return false, fmt.Errorf("update filing: %w", err)
+		}
+
+		m.webhooks.Publish(ctx, f.TenantID, domain.WebhookEventFilingOverdue, f.ToSummary(now))
+
+		m.log.Error("sar filing deadline missed",
+			logger.StringField("filing_id", f.ID.String()),
+			logger.StringField("filing_number", f.FilingNumber),
+			logger.StringField("due_date", f.FilingDueDate.Format(time.RFC3339)),
+		)
+		return true, nil
+	}
+
+	lead, ok := m.leadTimeFor(daysLeft)
+	if !ok || (f.DeadlineWarningDays != nil && *f.DeadlineWarningDays <= lead) {
+		return false, nil
+	}
+
+	priority := domain.RiskLevelMedium
+	if lead <= 1 {
+		priority = domain.RiskLevelHigh
+	}
+	alert := m.newAlert(f, now, priority, "SAR_FILING_DEADLINE",
+		fmt.Sprintf("SAR %s due in %d day(s)", f.FilingNumber, daysLeft),
+		fmt.Sprintf("SAR %s must be submitted by %s", f.FilingNumber, f.FilingDueDate.Format(time.RFC3339)),
+	)
+	if err := m.alerts.Create(ctx, alert); err != nil {
+		return false, fmt.Errorf("create alert: %w", err)
+	}
+
+	f.DeadlineWarningDays = &lead
+	f.UpdatedAt = now
+	if err := m.filings.Update(ctx, f); err != nil {
+		return false, fmt.Errorf("update filing: %w", err)
+	}
+
+	m.log.Warn("sar filing deadline approaching",
+		logger.StringField("filing_id", f.ID.String()),
+		logger.StringField("filing_number", f.FilingNumber),
+		logger.IntField("days_left", daysLeft),
+	)
+	return true, nil
+}
+
+// leadTimeFor returns the smallest configured lead time that daysLeft falls within
+func (m *FilingDeadlineMonitor) leadTimeFor(daysLeft int) (int, bool) {
+	lead, ok := 0, false
+	for _, d := range m.leadDays {
+		if daysLeft <= d {
+			lead, ok = d, true
+		}
+	}
+	return lead, ok
+}
+
+func (m *FilingDeadlineMonitor) newAlert(f *domain.RegulatoryFiling, now time.Time, priority domain.RiskLevel, rule, title, description string) *domain.AMLAlert {
+	return &domain.AMLAlert{
+		ID:              uuid.New(),
+		AlertNumber:     domain.GenerateAlertNumber(now),
+		TenantID:        f.TenantID,
+		UserID:          f.UserID,
+		AlertType:       domain.AlertTypeSystemGenerated,
+		Status:          domain.AlertStatusNew,
+		Priority:        priority,
+		Title:           title,
+		Description:     description,
+		RelatedTxIDs:    f.TransactionIDs,
+		Confidence:      1.0,
+		DetectionRule:   rule,
+		InvestigationID: f.InvestigationID,
+		DetectedAt:      now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
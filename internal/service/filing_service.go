@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/compliance"
+	"github.com/banking/aml-service/internal/compliance/fincen"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/crypto"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// FilingStore interface for regulatory filing persistence
+type FilingStore interface {
+	Create(ctx context.Context, f *domain.RegulatoryFiling) error
+	Update(ctx context.Context, f *domain.RegulatoryFiling) error
+	UpdateDraft(ctx context.Context, f *domain.RegulatoryFiling) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.RegulatoryFiling, error)
+	ListOpenSARsDueBefore(ctx context.Context, before time.Time, limit int) ([]*domain.RegulatoryFiling, error)
+	ApplyTransition(ctx context.Context, f *domain.RegulatoryFiling, from domain.FilingStatus, t *domain.FilingTransition, superseded *domain.FilingChange) error
+	CreateAmendment(ctx context.Context, amendment *domain.RegulatoryFiling, from domain.FilingStatus) error
+	ListAmendmentChain(ctx context.Context, id uuid.UUID) ([]*domain.RegulatoryFiling, error)
+	ListTransitions(ctx context.Context, filingID uuid.UUID) ([]domain.FilingTransition, error)
+}
+
+// ScreeningResultLister loads the screening results of given transactions
+type ScreeningResultLister interface {
+	ListLatestByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*domain.ScreeningResult, error)
+}
+
+// InvestigationAlertLister loads the alerts linked to an investigation
+type InvestigationAlertLister interface {
+	ListByInvestigation(ctx context.Context, investigationID uuid.UUID) ([]*domain.AMLAlert, error)
+}
+
+// SARRecorder counts submitted SARs against the counterparties of the
+// transactions they cover
+type SARRecorder interface {
+	RecordSAR(ctx context.Context, transactionIDs []uuid.UUID, at time.Time) error
+}
+
+// FilingService manages the lifecycle of regulatory filings
+type FilingService struct {
+	filings        FilingStore
+	results        ScreeningResultLister
+	investigations InvestigationReader
+	alerts         InvestigationAlertLister
+	auditor        Auditor
+	counterparties SARRecorder
+	exporter       *fincen.Exporter
+	clock          clock.Clock
+	cfg            *config.ComplianceConfig
+	log            *logger.Logger
+}
+
+// NewFilingService creates a new filing service. Screening results,
+// investigations and alerts are read to draft SAR narratives; deadlines
+// and timestamps are taken from clk. Submitted SARs are counted against
+// their counterparties through counterparties.
+func NewFilingService(filings FilingStore, results ScreeningResultLister, investigations InvestigationReader, alerts InvestigationAlertLister, auditor Auditor, counterparties SARRecorder, clk clock.Clock, cfg *config.ComplianceConfig, log *logger.Logger) *FilingService {
+	return &FilingService{
+		filings:        filings,
+		results:        results,
+		investigations: investigations,
+		alerts:         alerts,
+		auditor:        auditor,
+		counterparties: counterparties,
+		exporter:       fincen.NewExporter(&cfg.FilingInstitution),
+		clock:          clk,
+		cfg:            cfg,
+		log:            log.Named("filing_service"),
+	}
+}
+
+// CreateSAR drafts a SAR and sets its statutory filing deadline
+func (s *FilingService) CreateSAR(ctx context.Context, req *domain.CreateSARRequest) (*domain.RegulatoryFiling, error) {
+	if err := s.validateSAR(req); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	subject := req.SubjectInfo
+	activity := req.SuspiciousActivity
+
+	filing := &domain.RegulatoryFiling{
+		ID:                 uuid.New(),
+		FilingNumber:       domain.GenerateFilingNumber(domain.FilingTypeSAR, now),
+		FilingType:         domain.FilingTypeSAR,
+		Status:             domain.FilingStatusDraft,
+		UserID:             req.UserID,
+		InvestigationID:    req.InvestigationID,
+		TransactionIDs:     req.TransactionIDs,
+		SubjectInfo:        &subject,
+		SuspiciousActivity: &activity,
+		TotalAmount:        req.TotalAmount,
+		Currency:           "USD",
+		Narrative:          req.Narrative,
+		PreparedBy:         req.PreparedBy,
+		ActivityStartDate:  req.ActivityStartDate,
+		ActivityEndDate:    req.ActivityEndDate,
+		FilingDueDate:      domain.SARDueDate(req.ActivityEndDate, s.cfg.SARDeadlineDays),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	if err := s.filings.Create(ctx, filing); err != nil {
+		return nil, fmt.Errorf("create sar: %w", err)
+	}
+
+	s.log.Info("sar drafted",
+		logger.StringField("filing_id", filing.ID.String()),
+		logger.StringField("filing_number", filing.FilingNumber),
+		logger.StringField("due_date", filing.FilingDueDate.Format(time.RFC3339)),
+	)
+
+	return filing, nil
+}
+
+// validateSAR checks a SAR request, returning domain.FieldErrors listing
+// every invalid field. Activity categories must come from the FinCEN
+// suspicious activity types the export accepts.
+func (s *FilingService) validateSAR(req *domain.CreateSARRequest) error {
+	var errs domain.FieldErrors
+	invalid := func(field, message string) {
+		errs = append(errs, domain.FieldError{Field: field, Message: message})
+	}
+
+	if req.UserID == uuid.Nil {
+		invalid("user_id", "is required")
+	}
+	if req.PreparedBy == uuid.Nil {
+		invalid("prepared_by", "is required")
+	}
+	if len(req.TransactionIDs) == 0 {
+		invalid("transaction_ids", "at least one transaction is required")
+	}
+	if req.TotalAmount <= 0 {
+		invalid("total_amount", "must be greater than zero")
+	}
+	if req.ActivityStartDate.IsZero() {
+		invalid("activity_start_date", "is required")
+	}
+	if req.ActivityEndDate.IsZero() {
+		invalid("activity_end_date", "is required")
+	} else if req.ActivityEndDate.Before(req.ActivityStartDate) {
+		invalid("activity_end_date", "is before activity_start_date")
+	}
+	if n := utf8.RuneCountInString(strings.TrimSpace(req.Narrative)); n < s.cfg.SARMinNarrativeLength {
+		invalid("narrative", fmt.Sprintf("must be at least %d characters, got %d", s.cfg.SARMinNarrativeLength, n))
+	}
+
+	activity := req.SuspiciousActivity
+	if len(activity.Categories) == 0 {
+		invalid("suspicious_activity.categories", "at least one category is required")
+	}
+	for i, c := range activity.Categories {
+		if !fincen.IsSARCategory(c) {
+			invalid(fmt.Sprintf("suspicious_activity.categories[%d]", i), fmt.Sprintf("unknown category %q", c))
+		}
+	}
+	if !hasNonBlank(activity.Instruments) {
+		invalid("suspicious_activity.instruments", "at least one instrument is required")
+	}
+	if !hasNonBlank(activity.Products) {
+		invalid("suspicious_activity.products", "at least one product is required")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// hasNonBlank returns true if any value is not blank
+func hasNonBlank(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFiling returns a filing by ID
+func (s *FilingService) GetFiling(ctx context.Context, id uuid.UUID) (*domain.RegulatoryFiling, error) {
+	return s.filings.GetByID(ctx, id)
+}
+
+// DraftNarrative generates a SAR narrative from the filing's transactions,
+// their screening results and the linked investigation, and stores it on
+// the filing, which stays in DRAFT. A narrative that was written or edited
+// by hand is only replaced when req.Force is set; otherwise ErrConflict is
+// returned.
+func (s *FilingService) DraftNarrative(ctx context.Context, id uuid.UUID, req *domain.DraftNarrativeRequest) (*domain.RegulatoryFiling, error) {
+	// The existing narrative is compared in plaintext and the filing is
+	// written back, so it is read decrypted
+	filing, err := s.filings.GetByID(crypto.WithPIIAccess(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if filing.FilingType != domain.FilingTypeSAR {
+		return nil, fmt.Errorf("%w: only SAR filings carry a narrative", domain.ErrValidation)
+	}
+	if !filing.IsEditable() {
+		return nil, fmt.Errorf("%w: filing is %s, narratives can only be drafted in %s", domain.ErrConflict, filing.Status, domain.FilingStatusDraft)
+	}
+	edited := filing.NarrativeEdited()
+	if edited && !req.Force {
+		return nil, fmt.Errorf("%w: narrative has been edited manually; set force to replace it", domain.ErrConflict)
+	}
+
+	input, err := s.narrativeInput(ctx, filing)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	filing.SetNarrativeDraft(compliance.GenerateNarrative(input), now)
+	if err := s.filings.UpdateDraft(ctx, filing); err != nil {
+		return nil, fmt.Errorf("store narrative draft: %w", err)
+	}
+
+	err = s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     audit.ActionFilingNarrativeDrafted,
+		EntityType: audit.EntityFiling,
+		EntityID:   filing.ID.String(),
+		After: map[string]interface{}{
+			"filing_number":      filing.FilingNumber,
+			"narrative_sha256":   filing.NarrativeDraftSHA256,
+			"replaced_edited":    edited,
+			"transactions_count": len(filing.TransactionIDs),
+		},
+	})
+	if err != nil {
+		s.log.Error("failed to audit narrative draft",
+			logger.StringField("filing_id", filing.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	s.log.Info("sar narrative drafted",
+		logger.StringField("filing_id", filing.ID.String()),
+		logger.StringField("filing_number", filing.FilingNumber),
+		logger.BoolField("replaced_edited", edited),
+	)
+
+	return filing, nil
+}
+
+// narrativeInput gathers the evidence a narrative is drafted from
+func (s *FilingService) narrativeInput(ctx context.Context, filing *domain.RegulatoryFiling) (*compliance.NarrativeInput, error) {
+	input := &compliance.NarrativeInput{Filing: filing}
+
+	if len(filing.TransactionIDs) > 0 {
+		results, err := s.results.ListLatestByTransactionIDs(ctx, filing.TransactionIDs)
+		if err != nil {
+			return nil, fmt.Errorf("load screening results: %w", err)
+		}
+		input.Results = results
+	}
+
+	if filing.InvestigationID != nil {
+		inv, err := s.investigations.GetByID(ctx, *filing.InvestigationID)
+		if err != nil {
+			return nil, fmt.Errorf("load investigation: %w", err)
+		}
+		alerts, err := s.alerts.ListByInvestigation(ctx, inv.ID)
+		if err != nil {
+			return nil, fmt.Errorf("load investigation alerts: %w", err)
+		}
+		input.Investigation = inv
+		input.Alerts = alerts
+	}
+
+	return input, nil
+}
+
+// Transition moves a filing to a new status after checking the transition
+// is legal and that reviews are made by someone other than the preparer.
+// Submitting an amendment moves the filing it amends to AMENDED.
+func (s *FilingService) Transition(ctx context.Context, id uuid.UUID, req *domain.FilingTransitionRequest) (*domain.FilingTransitionResponse, error) {
+	filing, err := s.filings.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := filing.ValidateTransition(req); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	from := filing.Status
+	actor := req.ActorID
+
+	switch req.ToStatus {
+	case domain.FilingStatusReview:
+		filing.RejectionReason = ""
+	case domain.FilingStatusApproved:
+		filing.ReviewedBy = &actor
+		filing.ApprovedBy = &actor
+	case domain.FilingStatusDraft:
+		filing.ReviewedBy = nil
+		filing.ApprovedBy = nil
+		if from == domain.FilingStatusReview {
+			reviewer := actor
+			filing.ReviewedBy = &reviewer
+		}
+	case domain.FilingStatusSubmitted:
+		filing.SubmittedAt = &now
+	case domain.FilingStatusAccepted:
+		filing.BSAFilingID = req.BSAFilingID
+		filing.ConfirmationNumber = req.ConfirmationNumber
+	case domain.FilingStatusRejected:
+		filing.RejectionReason = req.Reason
+	}
+
+	filing.Status = req.ToStatus
+	filing.UpdatedAt = now
+
+	transition := &domain.FilingTransition{
+		ID:         uuid.New(),
+		FilingID:   filing.ID,
+		FromStatus: from,
+		ToStatus:   req.ToStatus,
+		ActorID:    actor,
+		Reason:     req.Reason,
+		CreatedAt:  now,
+	}
+
+	var superseded *domain.FilingChange
+	if req.ToStatus == domain.FilingStatusSubmitted && filing.IsAmendment() {
+		if superseded, err = s.supersede(ctx, filing, actor, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.filings.ApplyTransition(ctx, filing, from, transition, superseded); err != nil {
+		return nil, fmt.Errorf("apply filing transition: %w", err)
+	}
+
+	s.auditTransition(ctx, filing, transition)
+	if superseded != nil {
+		s.auditTransition(ctx, superseded.Filing, superseded.Transition)
+	}
+	if req.ToStatus == domain.FilingStatusSubmitted && filing.FilingType == domain.FilingTypeSAR && !filing.IsAmendment() {
+		s.recordSAR(ctx, filing, now)
+	}
+
+	s.log.Info("filing transitioned",
+		logger.StringField("filing_id", filing.ID.String()),
+		logger.StringField("filing_number", filing.FilingNumber),
+		logger.StringField("from", string(from)),
+		logger.StringField("to", string(req.ToStatus)),
+		logger.StringField("actor_id", actor.String()),
+	)
+
+	resp := &domain.FilingTransitionResponse{
+		Filing:     filing,
+		Transition: transition,
+	}
+	if superseded != nil {
+		resp.Superseded = superseded.Filing
+		s.log.Info("filing superseded by amendment",
+			logger.StringField("filing_id", superseded.Filing.ID.String()),
+			logger.StringField("amendment_id", filing.ID.String()),
+		)
+	}
+	return resp, nil
+}
+
+// recordSAR counts a submitted SAR against its counterparties. An amendment
+// is not counted again. Failures are logged; the counterparty rebuild job
+// restores anything missed.
+func (s *FilingService) recordSAR(ctx context.Context, filing *domain.RegulatoryFiling, at time.Time) {
+	if err := s.counterparties.RecordSAR(ctx, filing.TransactionIDs, at); err != nil {
+		s.log.Error("failed to record sar against counterparties",
+			logger.StringField("filing_id", filing.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// supersede prepares the move of the filing an amendment corrects to
+// AMENDED, applied together with the amendment's submission
+func (s *FilingService) supersede(ctx context.Context, amendment *domain.RegulatoryFiling, actor uuid.UUID, now time.Time) (*domain.FilingChange, error) {
+	original, err := s.filings.GetByID(ctx, *amendment.AmendedFromID)
+	if err != nil {
+		return nil, fmt.Errorf("load amended filing: %w", err)
+	}
+	if !original.CanTransitionTo(domain.FilingStatusAmended) {
+		return nil, fmt.Errorf("%w: amended filing %s is %s and cannot be superseded",
+			domain.ErrConflict, original.FilingNumber, original.Status)
+	}
+
+	from := original.Status
+	original.Status = domain.FilingStatusAmended
+	original.UpdatedAt = now
+
+	return &domain.FilingChange{
+		Filing: original,
+		From:   from,
+		Transition: &domain.FilingTransition{
+			ID:         uuid.New(),
+			FilingID:   original.ID,
+			FromStatus: from,
+			ToStatus:   domain.FilingStatusAmended,
+			ActorID:    actor,
+			Reason:     "superseded by amendment " + amendment.FilingNumber,
+			CreatedAt:  now,
+		},
+	}, nil
+}
+
+// Amend opens a draft amendment of a submitted, accepted or rejected filing.
+// The original keeps its status until the amendment is submitted. A filing
+// with an open amendment cannot be amended again.
+func (s *FilingService) Amend(ctx context.Context, id uuid.UUID, req *domain.AmendFilingRequest) (*domain.RegulatoryFiling, error) {
+	filing, err := s.filings.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !filing.CanAmend() {
+		return nil, fmt.Errorf("%w: a %s filing cannot be amended", domain.ErrConflict, filing.Status)
+	}
+
+	amendment := filing.NewAmendment(req.ActorID, req.Reason, s.clock.Now())
+	if err := s.filings.CreateAmendment(ctx, amendment, filing.Status); err != nil {
+		return nil, fmt.Errorf("create amendment: %w", err)
+	}
+
+	err = s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     audit.ActionFilingAmended,
+		EntityType: audit.EntityFiling,
+		EntityID:   filing.ID.String(),
+		After: map[string]interface{}{
+			"amendment_id":     amendment.ID,
+			"amendment_number": amendment.FilingNumber,
+			"reason":           req.Reason,
+		},
+	})
+	if err != nil {
+		s.log.Error("failed to audit filing amendment",
+			logger.StringField("filing_id", filing.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	s.log.Info("filing amendment opened",
+		logger.StringField("filing_id", amendment.ID.String()),
+		logger.StringField("amended_from_id", filing.ID.String()),
+		logger.StringField("actor_id", req.ActorID.String()),
+	)
+
+	return amendment, nil
+}
+
+// History returns the amendment chain containing a filing, oldest first
+func (s *FilingService) History(ctx context.Context, id uuid.UUID) ([]*domain.RegulatoryFiling, error) {
+	return s.filings.ListAmendmentChain(ctx, id)
+}
+
+// auditTransition records a filing transition in the audit log. Approvals
+// and submissions get their own actions.
+func (s *FilingService) auditTransition(ctx context.Context, filing *domain.RegulatoryFiling, t *domain.FilingTransition) {
+	action := audit.ActionFilingTransitioned
+	switch t.ToStatus {
+	case domain.FilingStatusApproved:
+		action = audit.ActionFilingApproved
+	case domain.FilingStatusSubmitted:
+		action = audit.ActionFilingSubmitted
+	}
+
+	after := map[string]interface{}{
+		"status":        t.ToStatus,
+		"filing_number": filing.FilingNumber,
+		"transition_id": t.ID,
+	}
+	if t.Reason != "" {
+		after["reason"] = t.Reason
+	}
+
+	err := s.auditor.Record(ctx, audit.Entry{
+		ActorID:    t.ActorID,
+		Action:     action,
+		EntityType: audit.EntityFiling,
+		EntityID:   filing.ID.String(),
+		Before:     map[string]interface{}{"status": t.FromStatus},
+		After:      after,
+	})
+	if err != nil {
+		s.log.Error("failed to audit filing transition",
+			logger.StringField("filing_id", filing.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// ListTransitions returns a filing's status history
+func (s *FilingService) ListTransitions(ctx context.Context, id uuid.UUID) ([]domain.FilingTransition, error) {
+	if _, err := s.filings.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.filings.ListTransitions(ctx, id)
+}
+
+// ExportFinCEN returns the FinCEN BSA E-Filing XML for an approved SAR.
+// The export carries the subject's identifiers, so the filing is read with
+// PII access. Missing or invalid data is reported as a *fincen.ValidationError.
+func (s *FilingService) ExportFinCEN(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	filing, err := s.filings.GetByID(crypto.WithPIIAccess(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.exporter.MarshalSAR(filing)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("sar exported for fincen",
+		logger.StringField("filing_id", filing.ID.String()),
+		logger.StringField("filing_number", filing.FilingNumber),
+	)
+	return out, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// InvestigationCaseStore persists investigations, their notes and their timeline
+type InvestigationCaseStore interface {
+	Create(ctx context.Context, inv *domain.Investigation, openedBy uuid.UUID) error
+	CreateAutoAssigned(ctx context.Context, inv *domain.Investigation, openedBy uuid.UUID, maxOpen int) (*domain.Analyst, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Investigation, error)
+	ListTimeline(ctx context.Context, investigationID uuid.UUID) ([]*domain.InvestigationTimeline, error)
+	AddNote(ctx context.Context, note *domain.InvestigationNote, event *domain.InvestigationTimeline) error
+	ListNotes(ctx context.Context, investigationID uuid.UUID, includeInternal bool) ([]*domain.InvestigationNote, error)
+}
+
+// InvestigationService opens investigations directly, outside of alert
+// escalation, and serves their notes and history
+type InvestigationService struct {
+	investigations InvestigationCaseStore
+	alerts         AlertStore
+	auditor        Auditor
+	sla            time.Duration
+	maxOpen        int
+	clock          clock.Clock
+	log            *logger.Logger
+}
+
+// NewInvestigationService creates a new investigation service. Due dates
+// and timestamps are taken from clk.
+func NewInvestigationService(investigations InvestigationCaseStore, alerts AlertStore, auditor Auditor, clk clock.Clock, cfg *config.ComplianceConfig, log *logger.Logger) *InvestigationService {
+	return &InvestigationService{
+		investigations: investigations,
+		alerts:         alerts,
+		auditor:        auditor,
+		sla:            cfg.InvestigationSLA,
+		maxOpen:        cfg.MaxOpenInvestigations,
+		clock:          clk,
+		log:            log.Named("investigation_service"),
+	}
+}
+
+// Create opens an investigation, due after the investigation SLA. With
+// AutoAssign set the case goes to the least loaded analyst with capacity,
+// preferring those skilled in its type; if nobody has capacity it stays
+// OPEN and an alert is raised so a supervisor can place it.
+func (s *InvestigationService) Create(ctx context.Context, req *domain.CreateInvestigationRequest) (*domain.Investigation, error) {
+	inv := req.NewInvestigation(s.sla, s.clock.Now())
+
+	var analyst *domain.Analyst
+	var err error
+	if req.AutoAssign {
+		analyst, err = s.investigations.CreateAutoAssigned(ctx, inv, req.CreatedBy, s.maxOpen)
+	} else {
+		err = s.investigations.Create(ctx, inv, req.CreatedBy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create investigation: %w", err)
+	}
+
+	err = s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.CreatedBy,
+		Action:     audit.ActionInvestigationOpened,
+		EntityType: audit.EntityInvestigation,
+		EntityID:   inv.ID.String(),
+		After: map[string]interface{}{
+			"status":      inv.Status,
+			"priority":    inv.Priority,
+			"case_number": inv.CaseNumber,
+		},
+	})
+	if err != nil {
+		s.log.Error("failed to audit investigation opening",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	if req.AutoAssign {
+		if analyst != nil {
+			s.auditAssignment(ctx, inv, analyst)
+		} else {
+			s.alertUnassigned(ctx, inv)
+		}
+	}
+
+	s.log.Info("investigation opened",
+		logger.StringField("investigation_id", inv.ID.String()),
+		logger.StringField("case_number", inv.CaseNumber),
+		logger.StringField("created_by", req.CreatedBy.String()),
+	)
+
+	return inv, nil
+}
+
+// Timeline returns the investigation's history, oldest first: its opening,
+// assignments, evidence, notes, SLA escalations and status changes as they
+// were recorded
+func (s *InvestigationService) Timeline(ctx context.Context, id uuid.UUID) ([]*domain.InvestigationTimeline, error) {
+	if _, err := s.investigations.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.investigations.ListTimeline(ctx, id)
+}
+
+// Notes returns the investigation's notes, oldest first. Internal notes are
+// left out unless includeInternal is set.
+func (s *InvestigationService) Notes(ctx context.Context, id uuid.UUID, includeInternal bool) ([]*domain.InvestigationNote, error) {
+	if _, err := s.investigations.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.investigations.ListNotes(ctx, id, includeInternal)
+}
+
+// AddNote adds a note to an open investigation and records it on the
+// timeline
+func (s *InvestigationService) AddNote(ctx context.Context, id uuid.UUID, req *domain.AddNoteRequest) (*domain.InvestigationNote, error) {
+	inv, err := s.investigations.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv.IsClosed() {
+		return nil, fmt.Errorf("%w: investigation %s is closed", domain.ErrConflict, inv.CaseNumber)
+	}
+
+	note := req.NewNote(inv.ID, s.clock.Now())
+	if err := s.investigations.AddNote(ctx, note, note.AddedEvent()); err != nil {
+		return nil, fmt.Errorf("add note: %w", err)
+	}
+
+	err = s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.AuthorID,
+		Action:     audit.ActionInvestigationNoteAdded,
+		EntityType: audit.EntityInvestigation,
+		EntityID:   inv.ID.String(),
+		After: map[string]interface{}{
+			"note_id":     note.ID,
+			"is_internal": note.IsInternal,
+		},
+	})
+	if err != nil {
+		s.log.Error("failed to audit investigation note",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	return note, nil
+}
+
+// auditAssignment records an automatic assignment in the audit log
+func (s *InvestigationService) auditAssignment(ctx context.Context, inv *domain.Investigation, analyst *domain.Analyst) {
+	err := s.auditor.Record(ctx, audit.Entry{
+		ActorID:    domain.SystemActorID,
+		Action:     audit.ActionInvestigationAssigned,
+		EntityType: audit.EntityInvestigation,
+		EntityID:   inv.ID.String(),
+		After: map[string]interface{}{
+			"assigned_to": analyst.ID,
+			"status":      inv.Status,
+			"open_cases":  analyst.OpenCases,
+		},
+	})
+	if err != nil {
+		s.log.Error("failed to audit investigation assignment",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// alertUnassigned raises an alert for a case auto-assignment could not place
+func (s *InvestigationService) alertUnassigned(ctx context.Context, inv *domain.Investigation) {
+	now := s.clock.Now()
+	alert := &domain.AMLAlert{
+		ID:              uuid.New(),
+		AlertNumber:     domain.GenerateAlertNumber(now),
+		TenantID:        inv.TenantID,
+		UserID:          inv.UserID,
+		TransactionID:   inv.TransactionID,
+		AlertType:       domain.AlertTypeSystemGenerated,
+		Status:          domain.AlertStatusNew,
+		Priority:        domain.RiskLevelMedium,
+		RiskScore:       inv.RiskScore,
+		Title:           fmt.Sprintf("Investigation %s could not be auto-assigned", inv.CaseNumber),
+		Description:     fmt.Sprintf("No analyst on the roster has capacity for investigation %s (%s); it remains OPEN", inv.CaseNumber, inv.InvestigationType),
+		Confidence:      1.0,
+		DetectionRule:   "INVESTIGATION_UNASSIGNED",
+		InvestigationID: &inv.ID,
+		DetectedAt:      now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.alerts.Create(ctx, alert); err != nil {
+		s.log.Error("failed to alert on unassigned investigation",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.ErrorField(err),
+		)
+		return
+	}
+
+	s.log.Warn("no analyst capacity for investigation",
+		logger.StringField("investigation_id", inv.ID.String()),
+		logger.StringField("case_number", inv.CaseNumber),
+	)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// keyRotationLockKey is the advisory lock key shared by all key rotation instances
+const keyRotationLockKey int64 = 0x414d4c06 // "AML" + 6
+
+// keyRotationBatchSize limits how many rows one rotation transaction rewrites
+const keyRotationBatchSize = 100
+
+// Reencrypter moves encrypted rows onto the current key in batches
+type Reencrypter interface {
+	ReencryptBatch(ctx context.Context, limit int) (int, error)
+}
+
+// KeyRotator re-encrypts stored PII under the current encryption key.
+// Old keys stay in the key set while it runs, so rows are readable
+// throughout and rotation needs no downtime.
+type KeyRotator struct {
+	stores []Reencrypter
+	locker Locker
+	cfg    *config.SecurityConfig
+	log    *logger.Logger
+}
+
+// NewKeyRotator creates a new key rotator for the given stores
+func NewKeyRotator(locker Locker, cfg *config.SecurityConfig, log *logger.Logger, stores ...Reencrypter) *KeyRotator {
+	return &KeyRotator{
+		stores: stores,
+		locker: locker,
+		cfg:    cfg,
+		log:    log.Named("key_rotator"),
+	}
+}
+
+// Run rotates at startup and then on the configured interval until ctx is
+// cancelled
+func (k *KeyRotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(k.cfg.KeyRotationInterval)
+	defer ticker.Stop()
+
+	k.log.Info("key rotator started",
+		logger.IntField("current_key_version", k.cfg.CurrentKeyVersion),
+		logger.DurationField("interval", k.cfg.KeyRotationInterval),
+	)
+
+	for {
+		if _, err := k.RunOnce(ctx); err != nil {
+			k.log.Error("key rotation failed", logger.ErrorField(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			k.log.Info("key rotator stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce re-encrypts every row not yet on the current key and returns how
+// many were rewritten. It is a no-op when another instance holds the lock.
+func (k *KeyRotator) RunOnce(ctx context.Context) (int, error) {
+	release, acquired, err := k.locker.TryLock(ctx, keyRotationLockKey)
+	if err != nil {
+		return 0, fmt.Errorf("acquire key rotation lock: %w", err)
+	}
+	if !acquired {
+		k.log.Debug("key rotation skipped, another instance holds the lock")
+		return 0, nil
+	}
+	defer release()
+
+	total := 0
+	for _, store := range k.stores {
+		for {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+
+			n, err := store.ReencryptBatch(ctx, keyRotationBatchSize)
+			if err != nil {
+				return total, fmt.Errorf("reencrypt batch: %w", err)
+			}
+			total += n
+			if n < keyRotationBatchSize {
+				break
+			}
+		}
+	}
+
+	if total > 0 {
+		k.log.Info("key rotation completed",
+			logger.IntField("rows", total),
+			logger.IntField("key_version", k.cfg.CurrentKeyVersion),
+		)
+	}
+	return total, nil
+}
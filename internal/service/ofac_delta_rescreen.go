@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// ofacDeltaLockKey is the advisory lock key shared by all delta re-screener instances
+const ofacDeltaLockKey int64 = 0x414d4c04 // "AML" + 4
+
+// partyPageSize limits how many stored names one query returns
+const partyPageSize = 1000
+
+// OFACListReloader reloads the sanctions index and reports what changed
+type OFACListReloader interface {
+	ListChanged(ctx context.Context) (bool, error)
+	ReloadIndex(ctx context.Context) (*screening.OFACDelta, error)
+	IndexStatus() screening.IndexStatus
+	ListVersion() screening.ListVersion
+}
+
+// OFACRescreenLog records the list versions whose deltas were re-screened,
+// shared by every instance so each delta raises its alerts once
+type OFACRescreenLog interface {
+	RescreenedVersion(ctx context.Context) (int64, error)
+	MarkRescreened(ctx context.Context, version int64) error
+}
+
+// PartyNameLister pages through customer and counterparty names
+type PartyNameLister interface {
+	ListPartyNames(ctx context.Context, after domain.PartyName, limit int) ([]domain.PartyName, error)
+}
+
+// OFACDeltaRunStats summarises one delta re-screening run
+type OFACDeltaRunStats struct {
+	StartedAt     time.Time     `json:"started_at"`
+	Duration      time.Duration `json:"duration"`
+	Added         int           `json:"added"`
+	Modified      int           `json:"modified"`
+	Removed       int           `json:"removed"`
+	DeltaNames    int           `json:"delta_names"`
+	NamesScanned  int           `json:"names_scanned"`
+	Hits          int           `json:"hits"`
+	AlertsRaised  int           `json:"alerts_raised"`
+	Skipped       bool          `json:"skipped"`
+	SkippedReason string        `json:"skipped_reason,omitempty"`
+}
+
+// OFACDeltaRescreener reloads the OFAC index when the cached list changes
+// and re-screens known customers and counterparties against only the new
+// names and aliases, raising watchlist alerts for hits
+type OFACDeltaRescreener struct {
+	ofac      OFACListReloader
+	rescreens OFACRescreenLog
+	parties   PartyNameLister
+	alerts    AlertStore
+	locker    Locker
+	log       *logger.Logger
+	interval  time.Duration
+	threshold float64
+
+	mu      sync.RWMutex
+	lastRun *OFACDeltaRunStats
+}
+
+// NewOFACDeltaRescreener creates a new OFAC delta re-screener
+func NewOFACDeltaRescreener(
+	ofac OFACListReloader,
+	rescreens OFACRescreenLog,
+	parties PartyNameLister,
+	alerts AlertStore,
+	locker Locker,
+	cfg *config.ScreeningConfig,
+	log *logger.Logger,
+) *OFACDeltaRescreener {
+	return &OFACDeltaRescreener{
+		ofac:      ofac,
+		rescreens: rescreens,
+		parties:   parties,
+		alerts:    alerts,
+		locker:    locker,
+		log:       log.Named("ofac_delta_rescreen"),
+		interval:  cfg.ListRefreshInterval,
+		threshold: cfg.FuzzyMatchThreshold,
+	}
+}
+
+// LastRun returns the stats of the most recent run, or nil if none has completed
+func (r *OFACDeltaRescreener) LastRun() *OFACDeltaRunStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastRun
+}
+
+// Run checks for list updates on the configured interval until ctx is cancelled
+func (r *OFACDeltaRescreener) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.log.Info("ofac delta re-screener started", logger.DurationField("interval", r.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Info("ofac delta re-screener stopped")
+			return
+		case <-ticker.C:
+			if _, err := r.RunOnce(ctx); err != nil {
+				r.log.Error("ofac delta re-screen failed", logger.ErrorField(err))
+			}
+		}
+	}
+}
+
+// RunOnce reloads the index if the list changed and re-screens stored names
+// against the delta. It returns nil stats when the list is unchanged.
+//
+// Every instance reloads its own index; only the lock holder re-screens,
+// and only a list version no instance has re-screened yet, so alerts are
+// raised once per delta.
+func (r *OFACDeltaRescreener) RunOnce(ctx context.Context) (*OFACDeltaRunStats, error) {
+	changed, err := r.ofac.ListChanged(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("check ofac list: %w", err)
+	}
+	if !changed {
+		return nil, nil
+	}
+	return r.reload(ctx)
+}
+
+// LoadIndex reloads the index on demand, even if the list looks unchanged,
+// and re-screens stored names against whatever changed. Only this
+// instance's index is reloaded; others pick up the list on their next run.
+func (r *OFACDeltaRescreener) LoadIndex(ctx context.Context) error {
+	_, err := r.reload(ctx)
+	return err
+}
+
+// IndexStatus reports the state of the reloaded OFAC index
+func (r *OFACDeltaRescreener) IndexStatus() screening.IndexStatus {
+	return r.ofac.IndexStatus()
+}
+
+// ListVersion returns the version of the reloaded OFAC list
+func (r *OFACDeltaRescreener) ListVersion() screening.ListVersion {
+	return r.ofac.ListVersion()
+}
+
+// reload reloads the index and, unless the delta is skipped, re-screens
+// stored names against it while holding the delta lock. A list the checker
+// refuses as too large a change is reported as a skipped run, not an
+// error: the previous index stays live and the checker has raised the
+// alarm.
+func (r *OFACDeltaRescreener) reload(ctx context.Context) (*OFACDeltaRunStats, error) {
+	delta, err := r.ofac.ReloadIndex(ctx)
+	if err != nil && !errors.Is(err, screening.ErrOFACListRejected) {
+		return nil, fmt.Errorf("reload ofac index: %w", err)
+	}
+
+	stats := &OFACDeltaRunStats{
+		StartedAt:  time.Now(),
+		Added:      len(delta.Added),
+		Modified:   len(delta.Modified),
+		Removed:    len(delta.Removed),
+		DeltaNames: delta.NameCount(),
+	}
+
+	switch {
+	case err != nil:
+		stats.Skipped, stats.SkippedReason = true, "delta exceeds sanity threshold, list not loaded"
+	case delta.Initial:
+		stats.Skipped, stats.SkippedReason = true, "initial load"
+	case delta.IsEmpty():
+		stats.Skipped, stats.SkippedReason = true, "no new names"
+	}
+
+	if !stats.Skipped {
+		release, acquired, err := r.locker.TryLock(ctx, ofacDeltaLockKey)
+		if err != nil {
+			return nil, fmt.Errorf("acquire ofac delta lock: %w", err)
+		}
+		if !acquired {
+			r.log.Debug("ofac delta re-screen skipped, another instance holds the lock")
+			return nil, nil
+		}
+		defer release()
+
+		// Every instance computes the same delta on its own reload; the
+		// first to take the lock re-screens it and the rest skip it
+		version := delta.Version.Version
+		done, err := r.rescreens.RescreenedVersion(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get re-screened ofac list version: %w", err)
+		}
+		if version > 0 && version <= done {
+			stats.Skipped, stats.SkippedReason = true, "list version already re-screened"
+		} else {
+			if err := r.rescreen(ctx, delta, stats); err != nil {
+				return nil, err
+			}
+			if version > 0 {
+				if err := r.rescreens.MarkRescreened(ctx, version); err != nil {
+					return nil, fmt.Errorf("mark ofac list version %d re-screened: %w", version, err)
+				}
+			}
+		}
+	}
+
+	stats.Duration = time.Since(stats.StartedAt)
+	r.record(stats)
+	return stats, nil
+}
+
+// rescreen matches every stored party name against the delta
+func (r *OFACDeltaRescreener) rescreen(ctx context.Context, delta *screening.OFACDelta, stats *OFACDeltaRunStats) error {
+	// A user is alerted at most once per sanctioned entity per delta, even if
+	// they match under several names or roles
+	alerted := make(map[string]bool)
+
+	var after domain.PartyName
+	for {
+		page, err := r.parties.ListPartyNames(ctx, after, partyPageSize)
+		if err != nil {
+			return fmt.Errorf("list party names: %w", err)
+		}
+
+		for _, party := range page {
+			stats.NamesScanned++
+
+			entry, score, ok := delta.Match(party.Name, r.threshold)
+			if !ok {
+				continue
+			}
+			stats.Hits++
+
+			key := party.UserID.String() + "/" + entry.EntityID + "/" + entry.NormalizedName
+			if alerted[key] {
+				continue
+			}
+
+			if err := r.alerts.Create(ctx, r.newAlert(party, entry, score)); err != nil {
+				r.log.Error("failed to create ofac delta alert",
+					logger.StringField("user_id", party.UserID.String()),
+					logger.ErrorField(err),
+				)
+				continue
+			}
+			alerted[key] = true
+			stats.AlertsRaised++
+		}
+
+		if len(page) < partyPageSize {
+			return nil
+		}
+		after = page[len(page)-1]
+	}
+}
+
+func (r *OFACDeltaRescreener) record(stats *OFACDeltaRunStats) {
+	r.mu.Lock()
+	r.lastRun = stats
+	r.mu.Unlock()
+
+	r.log.Info("ofac delta re-screen completed",
+		logger.IntField("added", stats.Added),
+		logger.IntField("modified", stats.Modified),
+		logger.IntField("removed", stats.Removed),
+		logger.IntField("delta_names", stats.DeltaNames),
+		logger.IntField("names_scanned", stats.NamesScanned),
+		logger.IntField("hits", stats.Hits),
+		logger.IntField("alerts", stats.AlertsRaised),
+		logger.StringField("skipped_reason", stats.SkippedReason),
+		logger.DurationField("duration", stats.Duration),
+	)
+}
+
+func (r *OFACDeltaRescreener) newAlert(party domain.PartyName, entry screening.OFACEntry, score float64) *domain.AMLAlert {
+	now := time.Now()
+
+	priority := domain.RiskLevelHigh
+	if score >= 1.0 {
+		priority = domain.RiskLevelCritical
+	}
+
+	return &domain.AMLAlert{
+		ID:          uuid.New(),
+		AlertNumber: domain.GenerateAlertNumber(now),
+		TenantID:    party.TenantID,
+		UserID:      party.UserID,
+		AlertType:   domain.AlertTypeWatchlist,
+		Status:      domain.AlertStatusNew,
+		Priority:    priority,
+		RiskScore:   int(score * 100),
+		Title:       fmt.Sprintf("New OFAC listing matches %s", party.Name),
+		Description: fmt.Sprintf("%s name %q matches newly listed SDN %q (program %s, entity %s) with score %.2f",
+			party.Role, party.Name, entry.Name, entry.Program, entry.EntityID, score),
+		Confidence:    score,
+		DetectionRule: "OFAC_LIST_DELTA",
+		DetectedAt:    now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// scoreDeltaBuckets are the upper bounds of the score delta buckets a
+// replay report counts, in order; deltas above the last fall in ">=20"
+var scoreDeltaBuckets = []struct {
+	max   int
+	label string
+}{
+	{-20, "<=-20"},
+	{-10, "-19..-10"},
+	{-5, "-9..-5"},
+	{-1, "-4..-1"},
+	{0, "0"},
+	{4, "1..4"},
+	{9, "5..9"},
+	{19, "10..19"},
+}
+
+// ReplayService re-screens historical transactions and compares the new
+// results with the ones they were given at the time, so the effect of a
+// code or configuration change on past decisions can be measured before
+// it ships
+type ReplayService struct {
+	screener TransactionScreener
+	log      *logger.Logger
+}
+
+// NewReplayService creates a new replay service. The screener should be
+// deterministic, such as an engine built by screening.NewSnapshotEngine.
+func NewReplayService(screener TransactionScreener, log *logger.Logger) *ReplayService {
+	return &ReplayService{
+		screener: screener,
+		log:      log.Named("replay"),
+	}
+}
+
+// ReplaySource calls fn for each case to replay, in order, and stops at
+// the first error fn returns
+type ReplaySource func(fn func(*domain.ReplayCase) error) error
+
+// Replay screens each case from source, passes every new result to out
+// when it is not nil, and reports how the results differ from their
+// baselines. A failed screening is counted and skipped; an error from the
+// source or from out stops the replay.
+func (s *ReplayService) Replay(
+	ctx context.Context,
+	source ReplaySource,
+	out func(*domain.ScreeningResult) error,
+) (*domain.ReplayReport, error) {
+	report := &domain.ReplayReport{
+		DecisionTransitions: make(map[string]int),
+		ScoreDeltas:         make(map[string]int),
+		FactorsAdded:        make(map[string]int),
+		FactorsRemoved:      make(map[string]int),
+		Changes:             []domain.ReplayChange{},
+	}
+
+	err := source(func(c *domain.ReplayCase) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result, err := s.screener.Screen(ctx, c.Transaction)
+		if err != nil {
+			s.log.Error("replay screening failed",
+				logger.StringField("transaction_id", c.Transaction.ID.String()),
+				logger.ErrorField(err),
+			)
+			report.Failed++
+			return nil
+		}
+		report.Replayed++
+
+		if out != nil {
+			if err := out(result); err != nil {
+				return fmt.Errorf("write replay result: %w", err)
+			}
+		}
+		if c.Baseline != nil {
+			compareReplay(report, c.Baseline, result)
+		}
+		return nil
+	})
+
+	if report.Compared > 0 {
+		report.ChangedDecisionRate = float64(report.DecisionsChanged) / float64(report.Compared)
+	}
+	return report, err
+}
+
+// compareReplay adds the differences between a baseline and its replay to
+// the report
+func compareReplay(report *domain.ReplayReport, baseline, replay *domain.ScreeningResult) {
+	report.Compared++
+	report.ScoreDeltas[scoreDeltaBucket(replay.RiskScore-baseline.RiskScore)]++
+
+	added, removed := diffFactors(baseline.RiskFactors, replay.RiskFactors)
+	for _, f := range added {
+		report.FactorsAdded[f]++
+	}
+	for _, f := range removed {
+		report.FactorsRemoved[f]++
+	}
+
+	if replay.Decision == baseline.Decision {
+		return
+	}
+	report.DecisionsChanged++
+	report.DecisionTransitions[string(baseline.Decision)+"->"+string(replay.Decision)]++
+	report.Changes = append(report.Changes, domain.ReplayChange{
+		TransactionID:    baseline.TransactionID,
+		BaselineDecision: baseline.Decision,
+		ReplayDecision:   replay.Decision,
+		BaselineScore:    baseline.RiskScore,
+		ReplayScore:      replay.RiskScore,
+		FactorsAdded:     added,
+		FactorsRemoved:   removed,
+	})
+}
+
+// diffFactors returns the risk factor names present in replay but not in
+// baseline and the reverse, counting repeats of the same factor, sorted
+func diffFactors(baseline, replay []domain.RiskFactor) (added, removed []string) {
+	counts := make(map[string]int)
+	for _, f := range baseline {
+		counts[f.Factor]--
+	}
+	for _, f := range replay {
+		counts[f.Factor]++
+	}
+	for name, n := range counts {
+		for ; n > 0; n-- {
+			added = append(added, name)
+		}
+		for ; n < 0; n++ {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func scoreDeltaBucket(delta int) string {
+	for _, b := range scoreDeltaBuckets {
+		if delta <= b.max {
+			return b.label
+		}
+	}
+	return ">=20"
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/banking/aml-service/internal/compliance"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// MIReportSnapshots stores the reports of closed periods
+type MIReportSnapshots interface {
+	GetSnapshot(ctx context.Context, period domain.ReportPeriod, start time.Time) (*domain.MIReport, error)
+	SaveSnapshot(ctx context.Context, report *domain.MIReport) error
+}
+
+// ReportService serves daily and monthly MI reports. A closed period is
+// generated once and then served from its snapshot, so figures already
+// reported stay fixed; only a backfill regenerates them.
+type ReportService struct {
+	generator *compliance.MIReportGenerator
+	snapshots MIReportSnapshots
+	loc       *time.Location
+	log       *logger.Logger
+}
+
+// NewReportService creates a new report service
+func NewReportService(source compliance.MIDataSource, snapshots MIReportSnapshots, cfg *config.ComplianceConfig, log *logger.Logger) (*ReportService, error) {
+	loc, err := time.LoadLocation(cfg.ReportTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("load report timezone: %w", err)
+	}
+
+	return &ReportService{
+		generator: compliance.NewMIReportGenerator(source),
+		snapshots: snapshots,
+		loc:       loc,
+		log:       log.Named("report_service"),
+	}, nil
+}
+
+// Report returns the report for the period containing the calendar date of
+// date, read in the report time zone. A zero date selects the most recent
+// closed period. The current period is generated live and marked
+// provisional.
+func (s *ReportService) Report(ctx context.Context, period domain.ReportPeriod, date time.Time) (*domain.MIReport, error) {
+	now := time.Now().In(s.loc)
+	if date.IsZero() {
+		current, _ := period.Bounds(now)
+		date = current.AddDate(0, 0, -1)
+	}
+
+	start, end := period.Bounds(s.calendarDate(date))
+	if start.After(now) {
+		return nil, fmt.Errorf("%w: report period starting %s has not begun", domain.ErrValidation, start.Format(time.DateOnly))
+	}
+
+	if end.After(now) {
+		report, err := s.generator.Generate(ctx, period, start)
+		if err != nil {
+			return nil, fmt.Errorf("generate provisional report: %w", err)
+		}
+		report.Provisional = true
+		return report, nil
+	}
+
+	report, err := s.snapshots.GetSnapshot(ctx, period, start)
+	if err == nil {
+		return report, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	return s.generate(ctx, period, start)
+}
+
+// Regenerate rebuilds and stores the snapshots of every closed period from
+// the one containing from through the one containing to, replacing earlier
+// snapshots. Periods that have not ended are skipped. It returns the number
+// of reports written.
+func (s *ReportService) Regenerate(ctx context.Context, period domain.ReportPeriod, from, to time.Time) (int, error) {
+	now := time.Now()
+	last, _ := period.Bounds(s.calendarDate(to))
+
+	n := 0
+	for start, end := period.Bounds(s.calendarDate(from)); !start.After(last) && !end.After(now); start, end = period.Bounds(end) {
+		if _, err := s.generate(ctx, period, start); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// generate compiles the report for a closed period and stores it
+func (s *ReportService) generate(ctx context.Context, period domain.ReportPeriod, start time.Time) (*domain.MIReport, error) {
+	report, err := s.generator.Generate(ctx, period, start)
+	if err != nil {
+		return nil, fmt.Errorf("generate %s report for %s: %w", period, start.Format(time.DateOnly), err)
+	}
+	if err := s.snapshots.SaveSnapshot(ctx, report); err != nil {
+		return nil, err
+	}
+
+	s.log.WithContext(ctx).Info("mi report snapshot stored",
+		logger.StringField("period", string(period)),
+		logger.StringField("period_start", start.Format(time.DateOnly)),
+	)
+	return report, nil
+}
+
+// calendarDate returns midnight of t's calendar date in the report time zone
+func (s *ReportService) calendarDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, s.loc)
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// retroactiveRescreenLockKey is the advisory lock key shared by all retroactive re-screen instances
+const retroactiveRescreenLockKey int64 = 0x414d4c09 // "AML" + 9
+
+// retroactiveDetectionRule tags the alerts a retroactive re-screen raises
+const retroactiveDetectionRule = "RETROACTIVE_RESCREEN"
+
+// TransactionRescreener replays a stored screening through the engine
+type TransactionRescreener interface {
+	Rescreen(ctx context.Context, original *domain.ScreeningResult) (*domain.ScreeningResult, error)
+}
+
+// PinnedRescreenerFunc builds a screener that replays against archived
+// OFAC and PEP list versions. It fails with
+// screening.ErrListVersionNotRetained when either version is not archived.
+type PinnedRescreenerFunc func(ctx context.Context, ofacVersion, pepVersion int64) (TransactionRescreener, error)
+
+// OFACEntryLookup builds a matcher for a single loaded SDN entry
+type OFACEntryLookup interface {
+	EntryDelta(entityID string) (*screening.OFACDelta, bool)
+}
+
+// RetroactiveRescreenService replays historical transactions against the
+// current sanctions and PEP lists after a list change and raises alerts,
+// tagged retroactive, for parties that were not a hit when the transaction
+// was first screened. Runs are requested on demand, processed one at a
+// time in the background and rate-limited so they do not compete with live
+// screening. A run pinned to archived list versions screens against those
+// instead; its re-screens are not stored.
+type RetroactiveRescreenService struct {
+	results   ScreeningExportStore
+	screener  TransactionRescreener
+	pinned    PinnedRescreenerFunc
+	ofac      OFACEntryLookup
+	alerts    AlertStore
+	auditor   Auditor
+	locker    Locker
+	log       *logger.Logger
+	rate      float64
+	threshold float64
+	queue     chan retroactiveJob
+
+	mu  sync.RWMutex
+	run *domain.RetroactiveRescreenRun // current or most recent run
+}
+
+// retroactiveJob is a queued run and the screener it replays through
+type retroactiveJob struct {
+	run      *domain.RetroactiveRescreenRun
+	screener TransactionRescreener
+}
+
+// NewRetroactiveRescreenService creates a new retroactive re-screen service
+func NewRetroactiveRescreenService(
+	results ScreeningExportStore,
+	screener TransactionRescreener,
+	pinned PinnedRescreenerFunc,
+	ofac OFACEntryLookup,
+	alerts AlertStore,
+	auditor Auditor,
+	locker Locker,
+	cfg *config.ScreeningConfig,
+	log *logger.Logger,
+) *RetroactiveRescreenService {
+	return &RetroactiveRescreenService{
+		results:   results,
+		screener:  screener,
+		pinned:    pinned,
+		ofac:      ofac,
+		alerts:    alerts,
+		auditor:   auditor,
+		locker:    locker,
+		log:       log.Named("retroactive_rescreen"),
+		rate:      cfg.RetroactiveRescreenRate,
+		threshold: cfg.FuzzyMatchThreshold,
+		queue:     make(chan retroactiveJob, 1),
+	}
+}
+
+// Start validates and audits a request and queues it for the background
+// runner. Only one run is accepted at a time; ErrConflict is returned
+// while another is queued or running on this instance. A run pinned to
+// list versions that are not retained is refused.
+func (s *RetroactiveRescreenService) Start(ctx context.Context, req *domain.RetroactiveRescreenRequest) (*domain.RetroactiveRescreenRun, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.EntityID != "" {
+		if _, ok := s.ofac.EntryDelta(req.EntityID); !ok {
+			return nil, fmt.Errorf("%w: no loaded SDN entry has entity_id %q", domain.ErrValidation, req.EntityID)
+		}
+	}
+
+	screener := s.screener
+	if req.Pinned() {
+		var err error
+		screener, err = s.pinned(ctx, req.OFACListVersion, req.PEPListVersion)
+		if errors.Is(err, screening.ErrListVersionNotRetained) {
+			return nil, fmt.Errorf("%w: %v", domain.ErrValidation, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("load pinned list versions: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.run != nil && (s.run.Status == domain.RetroactiveRescreenQueued || s.run.Status == domain.RetroactiveRescreenRunning) {
+		return nil, fmt.Errorf("%w: retroactive re-screen %s is still %s", domain.ErrConflict, s.run.ID, s.run.Status)
+	}
+
+	run := &domain.RetroactiveRescreenRun{
+		ID:        uuid.New(),
+		Request:   *req,
+		Status:    domain.RetroactiveRescreenQueued,
+		CreatedAt: time.Now(),
+	}
+
+	err := s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     audit.ActionRetroactiveRescreen,
+		EntityType: audit.EntityRetroactiveRescreen,
+		EntityID:   run.ID.String(),
+		After: map[string]interface{}{
+			"from":              req.From.Format(time.RFC3339),
+			"to":                req.To.Format(time.RFC3339),
+			"entity_id":         req.EntityID,
+			"ofac_list_version": req.OFACListVersion,
+			"pep_list_version":  req.PEPListVersion,
+			"reason":            req.Reason,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("audit retroactive re-screen: %w", err)
+	}
+
+	s.run = run
+	s.queue <- retroactiveJob{run: run, screener: screener}
+
+	s.log.Info("retroactive re-screen queued",
+		logger.StringField("run_id", run.ID.String()),
+		logger.StringField("actor_id", req.ActorID.String()),
+		logger.StringField("entity_id", req.EntityID),
+	)
+
+	snapshot := *run
+	return &snapshot, nil
+}
+
+// Status returns the current or most recent run, or nil if none was started
+func (s *RetroactiveRescreenService) Status() *domain.RetroactiveRescreenRun {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.run == nil {
+		return nil
+	}
+	snapshot := *s.run
+	return &snapshot
+}
+
+// Run processes queued runs until ctx is cancelled
+func (s *RetroactiveRescreenService) Run(ctx context.Context) {
+	s.log.Info("retroactive re-screen runner started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("retroactive re-screen runner stopped")
+			return
+		case job := <-s.queue:
+			err := s.process(ctx, job.run, job.screener)
+			s.finish(job.run, err)
+		}
+	}
+}
+
+// process replays every stored screening in the run's range through
+// screener while holding the cross-instance lock
+func (s *RetroactiveRescreenService) process(ctx context.Context, run *domain.RetroactiveRescreenRun, screener TransactionRescreener) error {
+	release, acquired, err := s.locker.TryLock(ctx, retroactiveRescreenLockKey)
+	if err != nil {
+		return fmt.Errorf("acquire retroactive re-screen lock: %w", err)
+	}
+	if !acquired {
+		return errors.New("another instance is running a retroactive re-screen")
+	}
+	defer release()
+
+	// The entry is looked up again in case the index was reloaded since
+	var parties *screening.OFACDelta
+	if run.Request.EntityID != "" {
+		var ok bool
+		if parties, ok = s.ofac.EntryDelta(run.Request.EntityID); !ok {
+			return fmt.Errorf("SDN entity %q is no longer loaded", run.Request.EntityID)
+		}
+	}
+
+	s.update(run, func(r *domain.RetroactiveRescreenRun) {
+		now := time.Now()
+		r.Status = domain.RetroactiveRescreenRunning
+		r.StartedAt = &now
+	})
+
+	limiter := rate.NewLimiter(rate.Limit(s.rate), 1)
+	selection := &domain.ScreeningExportRequest{
+		From:    run.Request.From,
+		To:      run.Request.To,
+		ActorID: run.Request.ActorID,
+	}
+
+	return s.results.StreamForExport(ctx, selection, func(original *domain.ScreeningResult) error {
+		// Re-screens are never replayed themselves, including those this
+		// run stores when the range reaches the present
+		if original.RescreenOfID != nil || original.Transaction == nil ||
+			(parties != nil && !s.partyMatches(parties, original.Transaction)) {
+			s.update(run, func(r *domain.RetroactiveRescreenRun) { r.Scanned++; r.Skipped++ })
+			return nil
+		}
+
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		rescreen, err := screener.Rescreen(ctx, original)
+		if err != nil {
+			s.log.Error("retroactive re-screen of transaction failed",
+				logger.StringField("run_id", run.ID.String()),
+				logger.StringField("screening_id", original.ID.String()),
+				logger.ErrorField(err),
+			)
+			s.update(run, func(r *domain.RetroactiveRescreenRun) { r.Scanned++; r.Failed++ })
+			return nil
+		}
+
+		hits := newListHits(original, rescreen)
+		raised := 0
+		for _, hit := range hits {
+			if err := s.alerts.Create(ctx, s.newAlert(run, original, rescreen, hit)); err != nil {
+				s.log.Error("failed to create retroactive re-screen alert",
+					logger.StringField("run_id", run.ID.String()),
+					logger.StringField("transaction_id", original.TransactionID.String()),
+					logger.ErrorField(err),
+				)
+				continue
+			}
+			raised++
+		}
+
+		s.update(run, func(r *domain.RetroactiveRescreenRun) {
+			r.Scanned++
+			r.Rescreened++
+			r.NewHits += len(hits)
+			r.AlertsRaised += raised
+		})
+		return nil
+	})
+}
+
+// partyMatches reports whether the transaction's sender or receiver
+// matches the SDN entry
+func (s *RetroactiveRescreenService) partyMatches(parties *screening.OFACDelta, tx *domain.Transaction) bool {
+	for _, name := range []string{tx.SenderName, tx.ReceiverName} {
+		if _, _, ok := parties.Match(name, s.threshold); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *RetroactiveRescreenService) update(run *domain.RetroactiveRescreenRun, fn func(*domain.RetroactiveRescreenRun)) {
+	s.mu.Lock()
+	fn(run)
+	s.mu.Unlock()
+}
+
+func (s *RetroactiveRescreenService) finish(run *domain.RetroactiveRescreenRun, err error) {
+	s.update(run, func(r *domain.RetroactiveRescreenRun) {
+		now := time.Now()
+		r.CompletedAt = &now
+		r.Status = domain.RetroactiveRescreenCompleted
+		if err != nil {
+			r.Status = domain.RetroactiveRescreenFailed
+			r.Error = err.Error()
+		}
+	})
+
+	snapshot := s.Status()
+	if err != nil {
+		s.log.Error("retroactive re-screen failed",
+			logger.StringField("run_id", run.ID.String()),
+			logger.IntField("rescreened", snapshot.Rescreened),
+			logger.ErrorField(err),
+		)
+		return
+	}
+	s.log.Info("retroactive re-screen completed",
+		logger.StringField("run_id", run.ID.String()),
+		logger.IntField("scanned", snapshot.Scanned),
+		logger.IntField("skipped", snapshot.Skipped),
+		logger.IntField("rescreened", snapshot.Rescreened),
+		logger.IntField("failed", snapshot.Failed),
+		logger.IntField("new_hits", snapshot.NewHits),
+		logger.IntField("alerts", snapshot.AlertsRaised),
+	)
+}
+
+// listHit is a sanctions or PEP match found on re-screening
+type listHit struct {
+	list  string
+	name  string
+	score float64
+}
+
+// newListHits returns the OFAC and PEP matches of the re-screen that the
+// original screening did not have, or matched to a different listed party
+func newListHits(original, rescreen *domain.ScreeningResult) []listHit {
+	var hits []listHit
+	if m := rescreen.OFACMatch; m != nil && m.Matched {
+		if o := original.OFACMatch; o == nil || !o.Matched || o.SDNName != m.SDNName {
+			hits = append(hits, listHit{list: "OFAC", name: m.SDNName, score: m.MatchScore})
+		}
+	}
+	if m := rescreen.PEPMatch; m != nil && m.Matched {
+		if o := original.PEPMatch; o == nil || !o.Matched || o.PEPName != m.PEPName {
+			hits = append(hits, listHit{list: "PEP", name: m.PEPName, score: m.MatchScore})
+		}
+	}
+	return hits
+}
+
+func (s *RetroactiveRescreenService) newAlert(run *domain.RetroactiveRescreenRun, original, rescreen *domain.ScreeningResult, hit listHit) *domain.AMLAlert {
+	now := time.Now()
+	txID := original.TransactionID
+
+	priority := domain.RiskLevelHigh
+	if hit.list == "OFAC" {
+		priority = domain.RiskLevelCritical
+	}
+
+	// Pinned re-screens are not stored, so their list versions are cited
+	against := "re-screen " + rescreen.ID.String()
+	if run.Request.Pinned() {
+		against = fmt.Sprintf("OFAC list version %d, PEP list version %d", run.Request.OFACListVersion, run.Request.PEPListVersion)
+	}
+
+	return &domain.AMLAlert{
+		ID:            uuid.New(),
+		AlertNumber:   domain.GenerateAlertNumber(now),
+		TenantID:      original.TenantID,
+		UserID:        original.UserID,
+		TransactionID: &txID,
+		AlertType:     domain.AlertTypeWatchlist,
+		Status:        domain.AlertStatusNew,
+		Priority:      priority,
+		RiskScore:     rescreen.RiskScore,
+		Title:         fmt.Sprintf("Retroactive %s hit on past transaction", hit.list),
+		Description: fmt.Sprintf("Transaction %s, screened %s with decision %s, now matches %s entry %q with score %.2f (%s, run %s: %s)",
+			txID, original.CreatedAt.Format(time.RFC3339), original.Decision, hit.list, hit.name, hit.score,
+			against, run.ID, run.Request.Reason),
+		Confidence:    hit.score,
+		DetectionRule: retroactiveDetectionRule,
+		DetectedAt:    now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// ScreeningExportStore reads screening results for bulk export
+type ScreeningExportStore interface {
+	CountForExport(ctx context.Context, req *domain.ScreeningExportRequest) (int, error)
+	StreamForExport(ctx context.Context, req *domain.ScreeningExportRequest, fn func(*domain.ScreeningResult) error) error
+}
+
+// ScreeningExportService exports screening results in bulk for auditors.
+// Exports are capped in size and every export is recorded in the audit log.
+type ScreeningExportService struct {
+	results ScreeningExportStore
+	auditor Auditor
+	maxRows int
+	log     *logger.Logger
+}
+
+// NewScreeningExportService creates a new screening export service
+func NewScreeningExportService(results ScreeningExportStore, auditor Auditor, cfg *config.ComplianceConfig, log *logger.Logger) *ScreeningExportService {
+	return &ScreeningExportService{
+		results: results,
+		auditor: auditor,
+		maxRows: cfg.ExportMaxRows,
+		log:     log.Named("screening_export"),
+	}
+}
+
+// Export streams the screening results selected by req to fn, oldest
+// first. The request is validated, checked against the row cap and
+// recorded in the audit log before anything is streamed; if any of these
+// fail, fn is never called. An export that cannot be audited is refused.
+func (s *ScreeningExportService) Export(ctx context.Context, req *domain.ScreeningExportRequest, fn func(*domain.ScreeningResult) error) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	rows, err := s.results.CountForExport(ctx, req)
+	if err != nil {
+		return err
+	}
+	if rows > s.maxRows {
+		return fmt.Errorf("%w: %d screening results match but exports are limited to %d; narrow the date range or filter by decision",
+			domain.ErrValidation, rows, s.maxRows)
+	}
+
+	exportID := uuid.New()
+	err = s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     audit.ActionScreeningsExported,
+		EntityType: audit.EntityScreeningExport,
+		EntityID:   exportID.String(),
+		After: map[string]interface{}{
+			"from":     req.From.Format(time.RFC3339),
+			"to":       req.To.Format(time.RFC3339),
+			"decision": req.Decision,
+			"rows":     rows,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("audit screening export: %w", err)
+	}
+
+	s.log.Info("screening export started",
+		logger.StringField("export_id", exportID.String()),
+		logger.StringField("actor_id", req.ActorID.String()),
+		logger.IntField("rows", rows),
+	)
+	return s.results.StreamForExport(ctx, req, fn)
+}
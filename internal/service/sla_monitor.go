@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+)
+
+// slaMonitorLockKey is the advisory lock key shared by all SLA monitor instances
+const slaMonitorLockKey int64 = 0x414d4c01 // "AML" + 1
+
+// slaScanBatchSize limits how many investigations each stage of a scan processes
+const slaScanBatchSize = 500
+
+// SLA escalation policies
+const (
+	EscalationPolicyStatus   = "status"
+	EscalationPolicyPriority = "priority"
+	EscalationPolicyBoth     = "both"
+)
+
+// InvestigationStore interface for investigation persistence used by jobs
+type InvestigationStore interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Investigation, error)
+	Update(ctx context.Context, inv *domain.Investigation) error
+	ListOverdue(ctx context.Context, now time.Time, limit int) ([]*domain.Investigation, error)
+	ListAtRisk(ctx context.Context, now time.Time, share float64, limit int) ([]*domain.Investigation, error)
+	AddTimelineEvent(ctx context.Context, event *domain.InvestigationTimeline) error
+	Escalate(ctx context.Context, inv *domain.Investigation, event *domain.InvestigationTimeline, alert *domain.AMLAlert) error
+}
+
+// AlertStore interface for alert persistence
+type AlertStore interface {
+	Create(ctx context.Context, alert *domain.AMLAlert) error
+}
+
+// AlertPrioritizer sets an alert's priority from its risk score
+type AlertPrioritizer interface {
+	Prioritize(alert *domain.AMLAlert)
+}
+
+// Auditor records compliance-relevant actions in the audit log
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// Locker interface for cross-instance mutual exclusion
+type Locker interface {
+	TryLock(ctx context.Context, key int64) (release func(), acquired bool, err error)
+}
+
+// SLANotifier pages on-call staff about investigations at risk of
+// breaching, or breaching, their SLA. It must not block the scan.
+type SLANotifier interface {
+	NotifySLAAtRisk(ctx context.Context, inv *domain.Investigation)
+	NotifySLABreach(ctx context.Context, inv *domain.Investigation, alert *domain.AMLAlert)
+}
+
+// SLAMonitor periodically flags and escalates overdue investigations. Open
+// cases still unassigned once SLAAtRiskShare of their SLA has elapsed are
+// bumped one priority level so they get picked up before they breach.
+// Each case's flag, timeline event and alert are written in one
+// transaction, so a failed write leaves the case for the next scan.
+type SLAMonitor struct {
+	investigations InvestigationStore
+	alerts         AlertPrioritizer
+	publisher      EventPublisher
+	webhooks       WebhookPublisher
+	notifier       SLANotifier
+	locker         Locker
+	auditor        Auditor
+	clock          clock.Clock
+	cfg            *config.ComplianceConfig
+	log            *logger.Logger
+}
+
+// NewSLAMonitor creates a new SLA monitor
+func NewSLAMonitor(
+	investigations InvestigationStore,
+	alerts AlertPrioritizer,
+	publisher EventPublisher,
+	webhooks WebhookPublisher,
+	notifier SLANotifier,
+	locker Locker,
+	auditor Auditor,
+	clk clock.Clock,
+	cfg *config.ComplianceConfig,
+	log *logger.Logger,
+) *SLAMonitor {
+	return &SLAMonitor{
+		investigations: investigations,
+		alerts:         alerts,
+		publisher:      publisher,
+		webhooks:       webhooks,
+		notifier:       notifier,
+		locker:         locker,
+		auditor:        auditor,
+		clock:          clk,
+		cfg:            cfg,
+		log:            log.Named("sla_monitor"),
+	}
+}
+
+// Run scans on the configured interval until ctx is cancelled
+func (m *SLAMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.SLAScanInterval)
+	defer ticker.Stop()
+
+	m.log.Info("sla monitor started", logger.DurationField("interval", m.cfg.SLAScanInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.log.Info("sla monitor stopped")
+			return
+		case <-ticker.C:
+			if _, err := m.RunOnce(ctx); err != nil {
+				m.log.Error("sla scan failed", logger.ErrorField(err))
+			}
+		}
+	}
+}
+
+// RunOnce performs a single scan and returns the number of investigations
+// escalated, whether at risk or breached. It is a no-op when another instance holds the scan lock.
+func (m *SLAMonitor) RunOnce(ctx context.Context) (int, error) {
+	release, acquired, err := m.locker.TryLock(ctx, slaMonitorLockKey)
+	if err != nil {
+		return 0, fmt.Errorf("acquire sla monitor lock: %w", err)
+	}
+	if !acquired {
+		m.log.Debug("sla scan skipped, another instance holds the lock")
+		return 0, nil
+	}
+	defer release()
+
+	now := m.clock.Now()
+	escalated := 0
+
+	atRisk, err := m.investigations.ListAtRisk(ctx, now, m.cfg.SLAAtRiskShare, slaScanBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list at-risk investigations: %w", err)
+	}
+	for _, inv := range atRisk {
+		if err := m.flagAtRisk(ctx, inv, now); err != nil {
+			m.log.Error("failed to escalate at-risk investigation",
+				logger.StringField("investigation_id", inv.ID.String()),
+				logger.ErrorField(err),
+			)
+			continue
+		}
+		escalated++
+	}
+
+	overdue, err := m.investigations.ListOverdue(ctx, now, slaScanBatchSize)
+	if err != nil {
+		return escalated, fmt.Errorf("list overdue investigations: %w", err)
+	}
+	for _, inv := range overdue {
+		if err := m.escalate(ctx, inv, now); err != nil {
+			m.log.Error("failed to escalate overdue investigation",
+				logger.StringField("investigation_id", inv.ID.String()),
+				logger.ErrorField(err),
+			)
+			continue
+		}
+		escalated++
+	}
+
+	if escalated > 0 {
+		m.log.Info("sla scan completed",
+			logger.IntField("at_risk", len(atRisk)),
+			logger.IntField("overdue", len(overdue)),
+			logger.IntField("escalated", escalated),
+		)
+	}
+	return escalated, nil
+}
+
+// flagAtRisk raises an unassigned investigation's priority one level and
+// records why on its timeline
+func (m *SLAMonitor) flagAtRisk(ctx context.Context, inv *domain.Investigation, now time.Time) error {
+	oldPriority := inv.Priority
+
+	inv.SLAAtRisk = true
+	inv.Priority = inv.Priority.Escalate()
+	inv.UpdatedAt = now
+
+	event := &domain.InvestigationTimeline{
+		ID:              uuid.New(),
+		InvestigationID: inv.ID,
+		EventType:       domain.TimelineEventSLAAtRisk,
+		Description: fmt.Sprintf("%.0f%% of SLA elapsed without assignment; priority raised automatically",
+			inv.SLAElapsed(now)*100),
+		OldValue:  string(oldPriority),
+		NewValue:  string(inv.Priority),
+		ActorID:   domain.SystemActorID,
+		CreatedAt: now,
+	}
+	if err := m.investigations.Escalate(ctx, inv, event, nil); err != nil {
+		return fmt.Errorf("flag investigation at risk: %w", err)
+	}
+
+	err := m.auditor.Record(ctx, audit.Entry{
+		ActorID:    domain.SystemActorID,
+		Action:     audit.ActionInvestigationStatusChanged,
+		EntityType: audit.EntityInvestigation,
+		EntityID:   inv.ID.String(),
+		Before:     map[string]interface{}{"priority": oldPriority},
+		After:      map[string]interface{}{"priority": inv.Priority, "sla_at_risk": true},
+	})
+	if err != nil {
+		m.log.Error("failed to audit investigation priority change",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	m.notifier.NotifySLAAtRisk(ctx, inv)
+
+	metrics.RecordSLAEscalation("at_risk")
+	m.log.InvestigationEscalated(inv.ID.String(), inv.CaseNumber, "sla_at_risk")
+
+	return nil
+}
+
+// escalate flags an investigation as breached, applies the escalation
+// policy, records a timeline event and raises an alert
+func (m *SLAMonitor) escalate(ctx context.Context, inv *domain.Investigation, now time.Time) error {
+	oldStatus, oldPriority := inv.Status, inv.Priority
+
+	inv.SLABreached = true
+	switch m.cfg.SLAEscalationPolicy {
+	case EscalationPolicyPriority:
+		inv.Priority = inv.Priority.Escalate()
+	case EscalationPolicyBoth:
+		inv.Priority = inv.Priority.Escalate()
+		inv.Status = domain.InvestigationStatusEscalated
+	default:
+		inv.Status = domain.InvestigationStatusEscalated
+	}
+	inv.UpdatedAt = now
+
+	overdueBy := now.Sub(inv.DueDate).Round(time.Minute)
+	event := &domain.InvestigationTimeline{
+		ID:              uuid.New(),
+		InvestigationID: inv.ID,
+		EventType:       domain.TimelineEventSLABreached,
+		Description:     fmt.Sprintf("SLA breached by %s; escalated automatically", overdueBy),
+		OldValue:        fmt.Sprintf("%s/%s", oldStatus, oldPriority),
+		NewValue:        fmt.Sprintf("%s/%s", inv.Status, inv.Priority),
+		ActorID:         domain.SystemActorID,
+		CreatedAt:       now,
+	}
+
+	alert := &domain.AMLAlert{
+		ID:              uuid.New(),
+		AlertNumber:     domain.GenerateAlertNumber(now),
+		TenantID:        inv.TenantID,
+		UserID:          inv.UserID,
+		TransactionID:   inv.TransactionID,
+		AlertType:       domain.AlertTypeSystemGenerated,
+		Status:          domain.AlertStatusNew,
+		Priority:        domain.RiskLevelHigh,
+		RiskScore:       inv.RiskScore,
+		Title:           fmt.Sprintf("Investigation %s breached SLA", inv.CaseNumber),
+		Description:     fmt.Sprintf("Investigation %s was due %s and is overdue by %s", inv.CaseNumber, inv.DueDate.Format(time.RFC3339), overdueBy),
+		Confidence:      1.0,
+		DetectionRule:   "INVESTIGATION_SLA_BREACH",
+		InvestigationID: &inv.ID,
+		DetectedAt:      now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	m.alerts.Prioritize(alert)
+
+	if err := m.investigations.Escalate(ctx, inv, event, alert); err != nil {
+		return fmt.Errorf("escalate investigation: %w", err)
+	}
+	m.webhooks.Publish(ctx, alert.TenantID, domain.WebhookEventAlertCreated, alert.ToSummary())
+
+	err := m.auditor.Record(ctx, audit.Entry{
+		ActorID:    domain.SystemActorID,
+		Action:     audit.ActionInvestigationStatusChanged,
+		EntityType: audit.EntityInvestigation,
+		EntityID:   inv.ID.String(),
+		Before:     map[string]interface{}{"status": oldStatus, "priority": oldPriority},
+		After:      map[string]interface{}{"status": inv.Status, "priority": inv.Priority, "sla_breached": true},
+	})
+	if err != nil {
+		m.log.Error("failed to audit investigation escalation",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	m.publishBreach(ctx, inv, now)
+	m.notifier.NotifySLABreach(ctx, inv, alert)
+
+	metrics.RecordSLAEscalation("breached")
+	m.log.InvestigationEscalated(inv.ID.String(), inv.CaseNumber, "sla_breached")
+	m.log.AlertCreated(alert.ID.String(), string(alert.AlertType), alert.UserID.String(), alert.RiskScore)
+
+	return nil
+}
+
+// publishBreach announces a breach on Kafka and to webhook endpoints. The
+// breach is already recorded and alerted, so a publish failure is logged
+// rather than retried.
+func (m *SLAMonitor) publishBreach(ctx context.Context, inv *domain.Investigation, now time.Time) {
+	event := &domain.InvestigationSLABreachedEvent{
+		EventID:         uuid.New(),
+		EventType:       "aml.investigation.sla_breached",
+		Timestamp:       now,
+		InvestigationID: inv.ID,
+		CaseNumber:      inv.CaseNumber,
+		UserID:          inv.UserID,
+		Status:          inv.Status,
+		Priority:        inv.Priority,
+		AssignedTo:      inv.AssignedTo,
+		DueDate:         inv.DueDate,
+	}
+	m.webhooks.Publish(ctx, inv.TenantID, domain.WebhookEventInvestigationSLABreached, event)
+
+	payload, err := json.Marshal(event)
+	if err == nil {
+		err = m.publisher.Publish(ctx, inv.UserID.String(), payload)
+	}
+	if err != nil {
+		m.log.Error("failed to publish sla breach event",
+			logger.StringField("investigation_id", inv.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/clock"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// escalation is one write made through slaInvestigations.Escalate
+type escalation struct {
+	inv   *domain.Investigation
+	event *domain.InvestigationTimeline
+	alert *domain.AMLAlert
+}
+
+// slaInvestigations serves fixed at-risk and overdue cases and records the
+// time each scan asked about and each escalation written
+type slaInvestigations struct {
+	InvestigationStore
+	atRisk, overdue []*domain.Investigation
+	scannedAt       []time.Time
+	escalations     []escalation
+}
+
+func (s *slaInvestigations) ListAtRisk(_ context.Context, now time.Time, _ float64, _ int) ([]*domain.Investigation, error) {
+	s.scannedAt = append(s.scannedAt, now)
+	return s.atRisk, nil
+}
+
+func (s *slaInvestigations) ListOverdue(_ context.Context, now time.Time, _ int) ([]*domain.Investigation, error) {
+	s.scannedAt = append(s.scannedAt, now)
+	return s.overdue, nil
+}
+
+func (s *slaInvestigations) Escalate(_ context.Context, inv *domain.Investigation, event *domain.InvestigationTimeline, alert *domain.AMLAlert) error {
+	s.escalations = append(s.escalations, escalation{inv: inv, event: event, alert: alert})
+	return nil
+}
+
+type freeLocker struct{}
+
+func (freeLocker) TryLock(context.Context, int64) (func(), bool, error) { return func() {}, true, nil }
+
+type nopSLAOutputs struct{}
+
+func (nopSLAOutputs) Prioritize(*domain.AMLAlert)                                              {}
+func (nopSLAOutputs) Publish(context.Context, string, []byte) error                            { return nil }
+func (nopSLAOutputs) Record(context.Context, audit.Entry) error                                { return nil }
+func (nopSLAOutputs) NotifySLAAtRisk(context.Context, *domain.Investigation)                   {}
+func (nopSLAOutputs) NotifySLABreach(context.Context, *domain.Investigation, *domain.AMLAlert) {}
+
+type nopWebhooks struct{}
+
+func (nopWebhooks) Publish(context.Context, string, domain.WebhookEventType, interface{}) {}
+
+func TestSLAMonitorStampsEscalationsWithClock(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	atRisk := &domain.Investigation{
+		ID:        uuid.New(),
+		Priority:  domain.PriorityMedium,
+		CreatedAt: now.Add(-80 * time.Hour),
+		DueDate:   now.Add(20 * time.Hour),
+		UpdatedAt: now.Add(-80 * time.Hour),
+	}
+	overdue := &domain.Investigation{
+		ID:        uuid.New(),
+		TenantID:  "bank-a",
+		Status:    domain.InvestigationStatusOpen,
+		Priority:  domain.PriorityHigh,
+		CreatedAt: now.Add(-100 * time.Hour),
+		DueDate:   now.Add(-90 * time.Minute),
+		UpdatedAt: now.Add(-100 * time.Hour),
+	}
+	store := &slaInvestigations{
+		atRisk:  []*domain.Investigation{atRisk},
+		overdue: []*domain.Investigation{overdue},
+	}
+
+	cfg := &config.ComplianceConfig{SLAEscalationPolicy: EscalationPolicyBoth, SLAAtRiskShare: 0.75}
+	outputs := nopSLAOutputs{}
+	m := NewSLAMonitor(store, outputs, outputs, nopWebhooks{}, outputs, freeLocker{}, outputs,
+		clock.NewFake(now), cfg, logger.NewNop())
+
+	escalated, err := m.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if escalated != 2 {
+		t.Fatalf("escalated %d investigations, want 2", escalated)
+	}
+
+	for i, at := range store.scannedAt {
+		if !at.Equal(now) {
+			t.Errorf("scan %d asked for cases due at %s, want %s", i, at, now)
+		}
+	}
+	if len(store.escalations) != 2 {
+		t.Fatalf("%d escalations written, want 2", len(store.escalations))
+	}
+	for _, e := range store.escalations {
+		if !e.inv.UpdatedAt.Equal(now) {
+			t.Errorf("investigation %s UpdatedAt = %s, want %s", e.inv.ID, e.inv.UpdatedAt, now)
+		}
+		if !e.event.CreatedAt.Equal(now) {
+			t.Errorf("%s timeline event CreatedAt = %s, want %s", e.event.EventType, e.event.CreatedAt, now)
+		}
+	}
+
+	breach := store.escalations[1]
+	if breach.alert == nil {
+		t.Fatal("breach raised no alert")
+	}
+	for name, got := range map[string]time.Time{
+		"DetectedAt": breach.alert.DetectedAt,
+		"CreatedAt":  breach.alert.CreatedAt,
+		"UpdatedAt":  breach.alert.UpdatedAt,
+	} {
+		if !got.Equal(now) {
+			t.Errorf("alert %s = %s, want %s", name, got, now)
+		}
+	}
+	if want := "SLA breached by 1h30m0s; escalated automatically"; breach.event.Description != want {
+		t.Errorf("breach description = %q, want %q", breach.event.Description, want)
+	}
+}
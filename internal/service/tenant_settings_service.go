@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+// TenantSettingsStore persists each tenant's overrides
+type TenantSettingsStore interface {
+	Upsert(ctx context.Context, settings *domain.TenantSettings) error
+	Get(ctx context.Context, tenantID string) (*domain.TenantSettings, error)
+	List(ctx context.Context) ([]*domain.TenantSettings, error)
+}
+
+// TenantSettingsService manages the per-tenant overrides of the configured
+// decision thresholds, country risk tiers and SAR and CTR thresholds. A
+// save is applied to this instance at once and is picked up by other
+// instances on their next refresh.
+type TenantSettingsService struct {
+	store       TenantSettingsStore
+	settings    *screening.TenantSettings
+	countryRisk *screening.CountryRisk
+	tenancy     *config.TenancyConfig
+	compliance  *config.ComplianceConfig
+	auditor     Auditor
+	log         *logger.Logger
+}
+
+// NewTenantSettingsService creates a new tenant settings service. settings
+// is the holder the screening engine reads; countryRisk holds the table
+// whose tiers apply to tenants that override none.
+func NewTenantSettingsService(
+	store TenantSettingsStore,
+	settings *screening.TenantSettings,
+	countryRisk *screening.CountryRisk,
+	tenancy *config.TenancyConfig,
+	compliance *config.ComplianceConfig,
+	auditor Auditor,
+	log *logger.Logger,
+) *TenantSettingsService {
+	return &TenantSettingsService{
+		store:       store,
+		settings:    settings,
+		countryRisk: countryRisk,
+		tenancy:     tenancy,
+		compliance:  compliance,
+		auditor:     auditor,
+		log:         log.Named("tenant_settings_service"),
+	}
+}
+
+// Get returns a tenant's overrides and the rules in force for it
+func (s *TenantSettingsService) Get(ctx context.Context, tenantID string) (*domain.TenantSettingsResponse, error) {
+	if err := s.authorize(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.store.Get(ctx, tenantID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("get tenant settings: %w", err)
+	}
+
+	return s.response(tenantID, settings), nil
+}
+
+// Update replaces a tenant's overrides and puts them in force
+func (s *TenantSettingsService) Update(ctx context.Context, tenantID string, req *domain.UpdateTenantSettingsRequest) (*domain.TenantSettingsResponse, error) {
+	if err := s.authorize(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	before, err := s.store.Get(ctx, tenantID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("get tenant settings: %w", err)
+	}
+
+	settings := req.NewSettings(tenantID, time.Now().UTC())
+	if err := s.store.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+	s.settings.Put(settings)
+
+	entry := audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     audit.ActionTenantSettingsChanged,
+		EntityType: audit.EntityTenantSettings,
+		EntityID:   tenantID,
+		After:      settings,
+	}
+	if before != nil {
+		entry.Before = before
+	}
+	if err := s.auditor.Record(ctx, entry); err != nil {
+		s.log.Error("failed to audit tenant settings change",
+			logger.StringField("tenant_id", tenantID),
+			logger.ErrorField(err),
+		)
+	}
+
+	s.log.Info("tenant settings saved",
+		logger.StringField("tenant_id", tenantID),
+		logger.StringField("actor_id", req.ActorID.String()),
+	)
+	return s.response(tenantID, settings), nil
+}
+
+// Refresh puts every tenant's saved overrides in force
+func (s *TenantSettingsService) Refresh(ctx context.Context) error {
+	all, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	s.settings.Set(all)
+	return nil
+}
+
+// Run refreshes the overrides on the configured interval until ctx is
+// cancelled
+func (s *TenantSettingsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.tenancy.SettingsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				s.log.Error("failed to refresh tenant settings", logger.ErrorField(err))
+			}
+		}
+	}
+}
+
+// authorize refuses tenants that are not configured, and callers acting
+// for a tenant other than the one they ask about
+func (s *TenantSettingsService) authorize(ctx context.Context, tenantID string) error {
+	if !s.tenancy.Allows(tenantID) {
+		return fmt.Errorf("%w: unknown tenant %s", domain.ErrNotFound, tenantID)
+	}
+	if scope, ok := tenant.FromContext(ctx); ok && scope != tenantID {
+		return fmt.Errorf("%w: caller acts for tenant %s, not %s", domain.ErrForbidden, scope, tenantID)
+	}
+	return nil
+}
+
+// response fills in the rules settings leaves to the global configuration
+func (s *TenantSettingsService) response(tenantID string, settings *domain.TenantSettings) *domain.TenantSettingsResponse {
+	resp := &domain.TenantSettingsResponse{TenantID: tenantID, Overrides: settings}
+
+	resp.Effective.DecisionThresholds = map[string]domain.DecisionThresholds{
+		domain.ThresholdTierDefault: domain.DefaultDecisionThresholds(),
+	}
+	for tier, t := range s.compliance.DecisionThresholds {
+		resp.Effective.DecisionThresholds[tier] = domain.DecisionThresholds{Tier: tier, Suspicious: t.Suspicious, Blocked: t.Blocked}
+	}
+	resp.Effective.CountryRiskTiers = s.countryRisk.Table().Tiers
+	resp.Effective.SARThreshold = s.compliance.SARThreshold
+	resp.Effective.CTRThreshold = s.compliance.CTRThreshold
+
+	if settings == nil {
+		return resp
+	}
+	maps.Copy(resp.Effective.DecisionThresholds, settings.DecisionThresholds)
+	if len(settings.CountryRiskTiers) > 0 {
+		resp.Effective.CountryRiskTiers = settings.CountryRiskTiers
+	}
+	if settings.SARThreshold != nil {
+		resp.Effective.SARThreshold = *settings.SARThreshold
+	}
+	if settings.CTRThreshold != nil {
+		resp.Effective.CTRThreshold = *settings.CTRThreshold
+	}
+	return resp
+}
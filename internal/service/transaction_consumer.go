@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+	"github.com/banking/aml-service/internal/pkg/tracing"
+)
+
+// eventInFlightTTL bounds how long a crashed consumer can hold an event's
+// idempotency key before another instance may process it
+const eventInFlightTTL = time.Minute
+
+// PriorityHeader is the Kafka header that carries a transaction event's
+// screening priority, so the consumer can queue it without parsing the
+// payload
+const PriorityHeader = "x-priority"
+
+// TransactionScreener screens a transaction
+type TransactionScreener interface {
+	Screen(ctx context.Context, tx *domain.Transaction) (*domain.ScreeningResult, error)
+}
+
+// IdempotencyStore claims idempotency keys and stores their outcome
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
+
+// EventPublisher publishes events to Kafka
+type EventPublisher interface {
+	Publish(ctx context.Context, key string, value []byte) error
+}
+
+// TransactionEventHandler screens transactions consumed from Kafka and
+// publishes the outcome. Redelivered events are recognised by EventID, or
+// by transaction ID for events without one, and skipped. Each event is
+// screened for the tenant its x-tenant-id header names, or tenant.Default.
+type TransactionEventHandler struct {
+	screener  TransactionScreener
+	store     IdempotencyStore
+	publisher EventPublisher
+	tenants   func(id string) bool
+	ttl       time.Duration
+	log       *logger.Logger
+}
+
+// NewTransactionEventHandler creates a new transaction event handler.
+// tenants reports whether a tenant is configured; processed events are
+// remembered for ttl.
+func NewTransactionEventHandler(screener TransactionScreener, store IdempotencyStore, publisher EventPublisher, tenants func(id string) bool, ttl time.Duration, log *logger.Logger) *TransactionEventHandler {
+	return &TransactionEventHandler{
+		screener:  screener,
+		store:     store,
+		publisher: publisher,
+		tenants:   tenants,
+		ttl:       ttl,
+		log:       log.Named("transaction_consumer"),
+	}
+}
+
+// Handle screens one TransactionCreatedEvent
+func (h *TransactionEventHandler) Handle(ctx context.Context, msg tracing.KafkaMessage) error {
+	log := h.log.WithContext(ctx)
+
+	// A malformed event will never parse, so it fails as a validation error
+	// and is dead-lettered rather than retried
+	var event domain.TransactionCreatedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("%w: malformed transaction event: %v", domain.ErrValidation, err)
+	}
+	if event.Transaction == nil {
+		return fmt.Errorf("%w: transaction event has no transaction", domain.ErrValidation)
+	}
+
+	tenantID := msg.Headers[tenant.KafkaHeader]
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	if !h.tenants(tenantID) {
+		return fmt.Errorf("%w: transaction event names unknown tenant %s", domain.ErrValidation, tenantID)
+	}
+	ctx = tenant.WithID(ctx, tenantID)
+
+	key := eventIdempotencyKey(msg.Topic, tenantID, &event)
+	sum := sha256.Sum256(msg.Value)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	existing, err := h.store.Reserve(ctx, key, fingerprint, eventInFlightTTL)
+	if err != nil {
+		// The engine's stored-result lookup still guards against duplicates
+		log.Warn("idempotency store unavailable, screening without it", logger.ErrorField(err))
+	}
+	if existing != nil {
+		switch {
+		case existing.Fingerprint != fingerprint:
+			return fmt.Errorf("%w: event %s reuses an id with a different payload", domain.ErrConflict, key)
+		case existing.Completed:
+			log.Debug("skipping redelivered transaction event", logger.StringField("idempotency_key", key))
+		default:
+			log.Debug("transaction event is being processed elsewhere", logger.StringField("idempotency_key", key))
+		}
+		return nil
+	}
+	reserved := err == nil
+
+	result, err := h.screener.Screen(ctx, event.Transaction)
+	if err != nil {
+		if reserved {
+			if rerr := h.store.Release(context.WithoutCancel(ctx), key); rerr != nil {
+				log.Error("failed to release idempotency key", logger.ErrorField(rerr))
+			}
+		}
+		return fmt.Errorf("screen transaction %s: %w", event.Transaction.ID, err)
+	}
+
+	resp := result.ToResponse()
+	completed, err := json.Marshal(&domain.ScreeningCompletedEvent{
+		EventID:   uuid.New(),
+		EventType: "aml.screening.completed",
+		Timestamp: time.Now(),
+		TenantID:  result.TenantID,
+		UserID:    result.UserID,
+		Result:    resp,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal screening completed event: %w", err)
+	}
+	if err := h.publisher.Publish(ctx, result.UserID.String(), completed); err != nil {
+		log.Error("failed to publish screening completed event",
+			logger.StringField("screening_id", result.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	if reserved {
+		err := h.store.Complete(context.WithoutCancel(ctx), key, &domain.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Body:        completed,
+			CreatedAt:   time.Now(),
+		}, h.ttl)
+		if err != nil {
+			log.Error("failed to record processed event", logger.ErrorField(err))
+		}
+	}
+
+	return nil
+}
+
+// TransactionEventPriority returns the screening priority a transaction
+// event asks for in its x-priority header or, failing that, its payload.
+// ok is false for an event that names no priority, or one that is unknown.
+func TransactionEventPriority(msg tracing.KafkaMessage) (domain.ScreeningPriority, bool) {
+	name := msg.Headers[PriorityHeader]
+	if name == "" {
+		var event struct {
+			Priority string `json:"priority"`
+		}
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			return domain.ScreeningPriorityNormal, false
+		}
+		name = event.Priority
+	}
+	if name == "" {
+		return domain.ScreeningPriorityNormal, false
+	}
+	return domain.ParseScreeningPriority(name)
+}
+
+// eventIdempotencyKey keys an event by tenant and EventID, falling back to
+// the transaction ID for producers that do not set one
+func eventIdempotencyKey(topic, tenantID string, event *domain.TransactionCreatedEvent) string {
+	if event.EventID != uuid.Nil {
+		return "kafka:" + topic + ":" + tenantID + ":event:" + event.EventID.String()
+	}
+	return "kafka:" + topic + ":" + tenantID + ":transaction:" + event.Transaction.ID.String()
+}
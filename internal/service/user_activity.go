@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// activitySectionLimit caps the alerts, investigations and filings in a
+// user activity response
+const activitySectionLimit = 100
+
+// ActivityScreeningStore reads a user's screening history
+type ActivityScreeningStore interface {
+	PageByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit, offset int) ([]*domain.ScreeningResult, int, error)
+	CountPatternsByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]domain.UserPatternSummary, error)
+}
+
+// ActivityAlertStore reads a user's alerts
+type ActivityAlertStore interface {
+	ListByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.AMLAlert, error)
+}
+
+// ActivityInvestigationStore reads investigations by filter
+type ActivityInvestigationStore interface {
+	List(ctx context.Context, filter domain.InvestigationFilter) ([]*domain.Investigation, int, error)
+}
+
+// ActivityFilingStore reads the filings on a user
+type ActivityFilingStore interface {
+	ListByUser(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.RegulatoryFiling, error)
+}
+
+// ActivityProfileStore reads a user's risk profile
+type ActivityProfileStore interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserRiskProfile, error)
+}
+
+// ActivityVelocityStore reads a user's current velocity
+type ActivityVelocityStore interface {
+	GetVelocity(ctx context.Context, userID uuid.UUID) (*domain.VelocityData, error)
+}
+
+// UserActivityService assembles everything known about a user for case
+// review
+type UserActivityService struct {
+	screenings     ActivityScreeningStore
+	alerts         ActivityAlertStore
+	investigations ActivityInvestigationStore
+	filings        ActivityFilingStore
+	profiles       ActivityProfileStore
+	velocity       ActivityVelocityStore
+	budget         time.Duration
+	log            *logger.Logger
+}
+
+// NewUserActivityService creates a new user activity service. budget
+// bounds the time taken by all of a request's queries together.
+func NewUserActivityService(
+	screenings ActivityScreeningStore,
+	alerts ActivityAlertStore,
+	investigations ActivityInvestigationStore,
+	filings ActivityFilingStore,
+	profiles ActivityProfileStore,
+	velocity ActivityVelocityStore,
+	budget time.Duration,
+	log *logger.Logger,
+) *UserActivityService {
+	return &UserActivityService{
+		screenings:     screenings,
+		alerts:         alerts,
+		investigations: investigations,
+		filings:        filings,
+		profiles:       profiles,
+		velocity:       velocity,
+		budget:         budget,
+		log:            log.Named("user_activity"),
+	}
+}
+
+// Get returns a user's activity with the given page of screenings. The
+// sections are read in parallel; if any read fails or the budget runs out
+// the request fails, as a partial picture of a user could mislead a
+// reviewer. A zero since includes all history.
+func (s *UserActivityService) Get(ctx context.Context, userID uuid.UUID, since time.Time, limit, offset int) (*domain.UserActivity, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.budget)
+	defer cancel()
+
+	activity := &domain.UserActivity{
+		UserID:      userID,
+		GeneratedAt: time.Now(),
+		Screenings:  domain.UserScreeningPage{Limit: limit, Offset: offset},
+	}
+	if !since.IsZero() {
+		activity.Since = &since
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		profile, err := s.profiles.GetByUserID(gctx, userID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get risk profile: %w", err)
+		}
+		activity.RiskProfile = profile.ToRiskSummary()
+		activity.Watchlist = domain.UserWatchlistStatus{
+			OnWatchlist: profile.OnWatchlist,
+			Reason:      profile.WatchlistReason,
+			AddedAt:     profile.WatchlistAddedAt,
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		velocity, err := s.velocity.GetVelocity(gctx, userID)
+		if err != nil {
+			return fmt.Errorf("get velocity: %w", err)
+		}
+		activity.Velocity = velocity
+		return nil
+	})
+
+	g.Go(func() error {
+		results, total, err := s.screenings.PageByUser(gctx, userID, since, limit, offset)
+		if err != nil {
+			return fmt.Errorf("list screenings: %w", err)
+		}
+		items := make([]*domain.UserScreening, 0, len(results))
+		for _, r := range results {
+			items = append(items, r.ToUserScreening())
+		}
+		activity.Screenings.Items = items
+		activity.Screenings.Total = total
+		return nil
+	})
+
+	g.Go(func() error {
+		patterns, err := s.screenings.CountPatternsByUser(gctx, userID, since)
+		if err != nil {
+			return fmt.Errorf("count patterns: %w", err)
+		}
+		activity.Patterns = patterns
+		return nil
+	})
+
+	g.Go(func() error {
+		alerts, err := s.alerts.ListByUser(gctx, userID, since, activitySectionLimit+1)
+		if err != nil {
+			return fmt.Errorf("list alerts: %w", err)
+		}
+		alerts, activity.Alerts.Truncated = truncate(alerts, activitySectionLimit)
+		activity.Alerts.Open = []*domain.AlertSummary{}
+		activity.Alerts.Historical = []*domain.AlertSummary{}
+		for _, a := range alerts {
+			if a.IsResolved() {
+				activity.Alerts.Historical = append(activity.Alerts.Historical, a.ToSummary())
+			} else {
+				activity.Alerts.Open = append(activity.Alerts.Open, a.ToSummary())
+			}
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		filter := domain.InvestigationFilter{UserID: &userID, Limit: activitySectionLimit}
+		if !since.IsZero() {
+			filter.CreatedFrom = &since
+		}
+		investigations, total, err := s.investigations.List(gctx, filter)
+		if err != nil {
+			return fmt.Errorf("list investigations: %w", err)
+		}
+		items := make([]*domain.InvestigationSummary, 0, len(investigations))
+		for _, inv := range investigations {
+			items = append(items, inv.ToSummary(activity.GeneratedAt))
+		}
+		activity.Investigations = domain.UserInvestigations{Items: items, Total: total}
+		return nil
+	})
+
+	g.Go(func() error {
+		filings, err := s.filings.ListByUser(gctx, userID, since, activitySectionLimit+1)
+		if err != nil {
+			return fmt.Errorf("list filings: %w", err)
+		}
+		filings, activity.Filings.Truncated = truncate(filings, activitySectionLimit)
+		activity.Filings.Items = make([]*domain.FilingSummary, 0, len(filings))
+		for _, f := range filings {
+			activity.Filings.Items = append(activity.Filings.Items, f.ToSummary(activity.GeneratedAt))
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	s.log.Debug("user activity assembled",
+		logger.StringField("user_id", userID.String()),
+		logger.IntField("screenings", activity.Screenings.Total),
+		logger.DurationField("elapsed", time.Since(activity.GeneratedAt)),
+	)
+
+	return activity, nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/patterns"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/stats"
+)
+
+// velocityBaselineLockKey is the advisory lock key shared by all baseline job instances
+const velocityBaselineLockKey int64 = 0x414d4c08 // "AML" + 8
+
+// velocityBaselineBatchSize is how many users one baseline batch covers
+const velocityBaselineBatchSize = 500
+
+// ActiveUserStore lists the users with screened transactions in a window
+type ActiveUserStore interface {
+	ListActiveUsers(ctx context.Context, since, until time.Time, after uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+// VelocityBaselineStore stores recomputed velocity baselines
+type VelocityBaselineStore interface {
+	SetBaselines(ctx context.Context, baselines map[uuid.UUID]*domain.VelocityBaseline, ttl time.Duration) error
+}
+
+// VelocityBaselineJob recomputes every active user's velocity baseline from
+// the last VelocityBaselineDays of screened transactions and stores it in
+// the velocity cache, where it takes precedence over the baseline derived
+// from the cache's own buckets. Blocked transactions are left out. With the
+// robust method a single large transfer, such as a house purchase, neither
+// inflates the mean nor the deviation enough to hide later spikes.
+type VelocityBaselineJob struct {
+	users     ActiveUserStore
+	history   patterns.TransactionRepository
+	baselines VelocityBaselineStore
+	locker    Locker
+	cfg       *config.PatternsConfig
+	log       *logger.Logger
+}
+
+// NewVelocityBaselineJob creates a new velocity baseline job
+func NewVelocityBaselineJob(
+	users ActiveUserStore,
+	history patterns.TransactionRepository,
+	baselines VelocityBaselineStore,
+	locker Locker,
+	cfg *config.PatternsConfig,
+	log *logger.Logger,
+) *VelocityBaselineJob {
+	return &VelocityBaselineJob{
+		users:     users,
+		history:   history,
+		baselines: baselines,
+		locker:    locker,
+		cfg:       cfg,
+		log:       log.Named("velocity_baseline_job"),
+	}
+}
+
+// Run recomputes at startup and then on the configured interval until ctx
+// is cancelled
+func (j *VelocityBaselineJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.VelocityBaselineInterval)
+	defer ticker.Stop()
+
+	j.log.Info("velocity baseline job started",
+		logger.StringField("method", j.cfg.VelocityBaselineMethod),
+		logger.DurationField("interval", j.cfg.VelocityBaselineInterval),
+	)
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			j.log.Error("velocity baseline run failed", logger.ErrorField(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			j.log.Info("velocity baseline job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce recomputes the baselines of every user with activity in the
+// baseline window and returns how many were stored. It is a no-op when
+// another instance holds the lock.
+func (j *VelocityBaselineJob) RunOnce(ctx context.Context) (int, error) {
+	release, acquired, err := j.locker.TryLock(ctx, velocityBaselineLockKey)
+	if err != nil {
+		return 0, fmt.Errorf("acquire velocity baseline lock: %w", err)
+	}
+	if !acquired {
+		j.log.Debug("velocity baseline run skipped, another instance holds the lock")
+		return 0, nil
+	}
+	defer release()
+
+	// Baselines cover whole UTC days before today, as the cache's do
+	until := time.Now().UTC().Truncate(24 * time.Hour)
+	since := until.AddDate(0, 0, -j.cfg.VelocityBaselineDays)
+
+	// Kept past the next run so a late run never leaves users without one
+	ttl := 2 * j.cfg.VelocityBaselineInterval
+
+	total := 0
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		users, err := j.users.ListActiveUsers(ctx, since, until, after, velocityBaselineBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(users) == 0 {
+			break
+		}
+
+		txs, err := j.history.GetInWindow(ctx, domain.TransactionWindow{
+			UserIDs:        users,
+			From:           since,
+			To:             until,
+			ExcludeBlocked: true,
+		})
+		if err != nil {
+			return total, err
+		}
+		baselines := j.computeBaselines(dailyActivity(txs), since)
+		if len(baselines) > 0 {
+			if err := j.baselines.SetBaselines(ctx, baselines, ttl); err != nil {
+				return total, err
+			}
+		}
+		total += len(baselines)
+
+		if len(users) < velocityBaselineBatchSize {
+			break
+		}
+		after = users[len(users)-1]
+	}
+
+	j.log.Info("velocity baselines recomputed",
+		logger.IntField("users", total),
+		logger.StringField("method", j.cfg.VelocityBaselineMethod),
+	)
+	return total, nil
+}
+
+// dailyActivity sums transactions per user and UTC day of initiation
+func dailyActivity(txs []*domain.Transaction) []domain.DailyActivity {
+	type key struct {
+		userID uuid.UUID
+		day    time.Time
+	}
+	index := make(map[key]int)
+	var activity []domain.DailyActivity
+	for _, tx := range txs {
+		k := key{userID: tx.UserID, day: tx.InitiatedAt.UTC().Truncate(24 * time.Hour)}
+		i, ok := index[k]
+		if !ok {
+			i = len(activity)
+			index[k] = i
+			activity = append(activity, domain.DailyActivity{UserID: k.userID, Day: k.day})
+		}
+		activity[i].Count++
+		activity[i].Amount += tx.Amount.Float64()
+	}
+	return activity
+}
+
+// computeBaselines lays each user's activity out as daily series starting
+// at since and computes their baselines
+func (j *VelocityBaselineJob) computeBaselines(activity []domain.DailyActivity, since time.Time) map[uuid.UUID]*domain.VelocityBaseline {
+	days := j.cfg.VelocityBaselineDays
+	type series struct{ counts, amounts []float64 }
+	byUser := make(map[uuid.UUID]*series)
+
+	for _, a := range activity {
+		i := int(a.Day.Sub(since) / (24 * time.Hour))
+		if i < 0 || i >= days {
+			continue
+		}
+		s, ok := byUser[a.UserID]
+		if !ok {
+			s = &series{counts: make([]float64, days), amounts: make([]float64, days)}
+			byUser[a.UserID] = s
+		}
+		s.counts[i] += float64(a.Count)
+		s.amounts[i] += a.Amount
+	}
+
+	baselines := make(map[uuid.UUID]*domain.VelocityBaseline, len(byUser))
+	for userID, s := range byUser {
+		if b := velocityBaseline(s.counts, s.amounts, j.cfg.VelocityBaselineMethod, j.cfg.VelocityBaselineTrim); b != nil {
+			baselines[userID] = b
+		}
+	}
+	return baselines
+}
+
+// velocityBaseline computes a baseline from daily counts and amounts,
+// oldest day first. The series starts at the first day with activity so a
+// new customer's empty days before onboarding do not drag the average
+// down; quiet days after that count as zero. It returns nil when there is
+// no activity.
+func velocityBaseline(counts, amounts []float64, method string, trim float64) *domain.VelocityBaseline {
+	first := -1
+	for i, c := range counts {
+		if c > 0 {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	counts, amounts = counts[first:], amounts[first:]
+
+	b := &domain.VelocityBaseline{BaselineDays: len(amounts)}
+	if method == "robust" {
+		b.AvgDailyTxCount = stats.TrimmedMean(counts, trim)
+		b.AvgDailyAmount = stats.TrimmedMean(amounts, trim)
+		b.StdDevDailyAmount = stats.RobustStdDev(amounts, trim)
+	} else {
+		b.AvgDailyTxCount = stats.Mean(counts)
+		b.AvgDailyAmount = stats.Mean(amounts)
+		b.StdDevDailyAmount = stats.StdDev(amounts)
+	}
+	return b
+}
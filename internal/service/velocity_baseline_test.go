@@ -0,0 +1,70 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+// dailySeries returns 30 days of steady activity, 2-4 transactions and
+// 800-1200 a day
+func dailySeries() (counts, amounts []float64) {
+	for i := range 30 {
+		counts = append(counts, float64(2+i%3))
+		amounts = append(amounts, 800+float64(i%5)*100)
+	}
+	return counts, amounts
+}
+
+func TestVelocityBaselineOutlier(t *testing.T) {
+	tests := []struct {
+		method string
+		// whether one outlier may move the baseline by more than 10%
+		shifts bool
+	}{
+		{method: "robust", shifts: false},
+		{method: "classic", shifts: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			counts, amounts := dailySeries()
+			before := velocityBaseline(counts, amounts, tt.method, 0.1)
+
+			// One house purchase
+			counts, amounts = dailySeries()
+			counts[20], amounts[20] = 1, 250000
+			after := velocityBaseline(counts, amounts, tt.method, 0.1)
+
+			for _, m := range []struct {
+				name          string
+				before, after float64
+			}{
+				{"average daily amount", before.AvgDailyAmount, after.AvgDailyAmount},
+				{"daily amount deviation", before.StdDevDailyAmount, after.StdDevDailyAmount},
+			} {
+				shift := math.Abs(m.after-m.before) / m.before
+				if tt.shifts && shift <= 0.1 {
+					t.Errorf("%s moved %.1f%% (%.2f to %.2f), want the outlier to move it more than 10%%",
+						m.name, shift*100, m.before, m.after)
+				}
+				if !tt.shifts && shift > 0.1 {
+					t.Errorf("%s moved %.1f%% (%.2f to %.2f), want at most 10%%",
+						m.name, shift*100, m.before, m.after)
+				}
+			}
+		})
+	}
+}
+
+func TestVelocityBaselineStartsAtFirstActivity(t *testing.T) {
+	counts := []float64{0, 0, 2, 2}
+	amounts := []float64{0, 0, 500, 700}
+	for _, method := range []string{"classic", "robust"} {
+		b := velocityBaseline(counts, amounts, method, 0.1)
+		if b.BaselineDays != 2 || b.AvgDailyAmount != 600 {
+			t.Errorf("%s baseline = %d days averaging %.2f, want 2 days averaging 600", method, b.BaselineDays, b.AvgDailyAmount)
+		}
+	}
+	if b := velocityBaseline(make([]float64, 4), make([]float64, 4), "robust", 0.1); b != nil {
+		t.Errorf("baseline without activity = %+v, want nil", b)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// RiskProfileStore reads and writes user risk profiles
+type RiskProfileStore interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserRiskProfile, error)
+	Save(ctx context.Context, p *domain.UserRiskProfile) error
+	ListWatchlisted(ctx context.Context, limit, offset int) ([]*domain.UserRiskProfile, int, error)
+}
+
+// WatchlistService manages the internal watchlist. Every change reassesses
+// the user's risk score and is recorded in the audit log.
+type WatchlistService struct {
+	profiles    RiskProfileStore
+	auditor     Auditor
+	supervisors map[uuid.UUID]bool
+	log         *logger.Logger
+}
+
+// NewWatchlistService creates a new watchlist service
+func NewWatchlistService(profiles RiskProfileStore, auditor Auditor, cfg *config.ComplianceConfig, log *logger.Logger) (*WatchlistService, error) {
+	supervisors := make(map[uuid.UUID]bool, len(cfg.Supervisors))
+	for _, s := range cfg.Supervisors {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid supervisor id %q: %w", s, err)
+		}
+		supervisors[id] = true
+	}
+
+	return &WatchlistService{
+		profiles:    profiles,
+		auditor:     auditor,
+		supervisors: supervisors,
+		log:         log.Named("watchlist_service"),
+	}, nil
+}
+
+// List returns the watchlisted users' profiles and the total count
+func (s *WatchlistService) List(ctx context.Context, limit, offset int) ([]*domain.UserRiskProfile, int, error) {
+	return s.profiles.ListWatchlisted(ctx, limit, offset)
+}
+
+// Add puts a user on the watchlist, creating their risk profile if needed
+func (s *WatchlistService) Add(ctx context.Context, userID uuid.UUID, req *domain.WatchlistChangeRequest) (*domain.UserRiskProfile, error) {
+	now := time.Now()
+	profile, err := s.profiles.GetByUserID(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		profile = domain.NewRiskProfile(userID, now)
+	case err != nil:
+		return nil, err
+	}
+
+	before := watchlistState(profile)
+	profile.OnWatchlist = true
+	profile.WatchlistReason = req.Reason
+	profile.WatchlistAddedAt = &now
+	profile.Reassess(now)
+
+	if err := s.save(ctx, profile, before, req.ActorID, req.Reason); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Remove takes a user off the watchlist. Only supervisors may remove users;
+// req.ActorID must be the caller's verified identity, not one it claims.
+func (s *WatchlistService) Remove(ctx context.Context, userID uuid.UUID, req *domain.WatchlistChangeRequest) (*domain.UserRiskProfile, error) {
+	if !s.supervisors[req.ActorID] {
+		return nil, fmt.Errorf("%w: only a supervisor may remove a user from the watchlist", domain.ErrForbidden)
+	}
+
+	profile, err := s.profiles.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.OnWatchlist {
+		return nil, fmt.Errorf("%w: user is not on the watchlist", domain.ErrConflict)
+	}
+
+	now := time.Now()
+	before := watchlistState(profile)
+	profile.OnWatchlist = false
+	profile.WatchlistReason = ""
+	profile.WatchlistAddedAt = nil
+	profile.Reassess(now)
+
+	if err := s.save(ctx, profile, before, req.ActorID, req.Reason); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// save persists the profile and records the change in the audit log
+func (s *WatchlistService) save(ctx context.Context, profile *domain.UserRiskProfile, before map[string]interface{}, actor uuid.UUID, reason string) error {
+	if err := s.profiles.Save(ctx, profile); err != nil {
+		return fmt.Errorf("save risk profile: %w", err)
+	}
+
+	after := watchlistState(profile)
+	after["reason"] = reason
+	err := s.auditor.Record(ctx, audit.Entry{
+		ActorID:    actor,
+		Action:     audit.ActionWatchlistChanged,
+		EntityType: audit.EntityWatchlist,
+		EntityID:   profile.UserID.String(),
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		s.log.Error("failed to audit watchlist change",
+			logger.StringField("user_id", profile.UserID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	s.log.Info("watchlist changed",
+		logger.StringField("user_id", profile.UserID.String()),
+		logger.BoolField("on_watchlist", profile.OnWatchlist),
+		logger.StringField("actor_id", actor.String()),
+		logger.IntField("risk_score", profile.RiskScore),
+	)
+	return nil
+}
+
+func watchlistState(p *domain.UserRiskProfile) map[string]interface{} {
+	return map[string]interface{}{
+		"on_watchlist":     p.OnWatchlist,
+		"watchlist_reason": p.WatchlistReason,
+		"risk_score":       p.RiskScore,
+		"risk_level":       p.RiskLevel,
+	}
+}
@@ -0,0 +1,503 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/apperr"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/metrics"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// Webhook request headers. The signature is
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" under the secret>".
+const (
+	webhookEventIDHeader   = "X-AML-Event-ID"
+	webhookEventTypeHeader = "X-AML-Event-Type"
+	webhookAttemptHeader   = "X-AML-Delivery-Attempt"
+	webhookSignatureHeader = "X-AML-Signature"
+)
+
+// maxWebhookResponseBody caps how much of a subscriber's response is read
+const maxWebhookResponseBody = 4 << 10
+
+// deadLetterTimeout bounds the dead-letter writes made while shutting down
+const deadLetterTimeout = 10 * time.Second
+
+// webhookDisabledRule is the detection rule of the alert raised when an
+// endpoint is disabled
+const webhookDisabledRule = "WEBHOOK_ENDPOINT_DISABLED"
+
+// WebhookPublisher queues events for the webhook endpoints of a tenant
+// subscribed to them
+type WebhookPublisher interface {
+	Publish(ctx context.Context, tenantID string, eventType domain.WebhookEventType, payload interface{})
+}
+
+// WebhookEndpointStore reads webhook endpoints and tracks their delivery
+// failures
+type WebhookEndpointStore interface {
+	ListEnabled(ctx context.Context) ([]*domain.WebhookEndpoint, error)
+	RecordSuccess(ctx context.Context, id uuid.UUID) error
+	RecordFailure(ctx context.Context, id uuid.UUID, disableAfter int, reason string, now time.Time) (bool, error)
+}
+
+// WebhookEventStore persists webhook events and delivery attempts
+type WebhookEventStore interface {
+	CreateEvent(ctx context.Context, e *domain.WebhookEvent) error
+	CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error
+}
+
+// WebhookDeadLetterStore persists events that could not be delivered
+type WebhookDeadLetterStore interface {
+	Create(ctx context.Context, d *domain.WebhookDeadLetter) error
+}
+
+// webhookEvent is an event queued for delivery. It is stored once, by
+// whichever delivery of it runs first; replayed events are already stored.
+type webhookEvent struct {
+	domain.WebhookEvent
+	result *domain.ScreeningResult // screening events only
+	store  sync.Once
+}
+
+// webhookDelivery is one event queued for one endpoint
+type webhookDelivery struct {
+	endpoint *domain.WebhookEndpoint
+	event    *webhookEvent
+	replay   bool
+}
+
+// WebhookDispatcher POSTs events such as screening decisions and new
+// alerts to the webhook endpoints subscribed to them, so partner systems
+// without Kafka access can act on them without polling. Publishing only
+// queues; deliveries run on a fixed pool of workers and are retried with
+// exponential backoff. Every attempt is recorded with its status and
+// latency, events that still fail are dead-lettered, and an endpoint whose
+// deliveries keep failing is disabled and an alert raised.
+type WebhookDispatcher struct {
+	endpoints   WebhookEndpointStore
+	events      WebhookEventStore
+	deadLetters WebhookDeadLetterStore
+	alerts      AlertStore
+	client      *http.Client
+	queue       chan *webhookDelivery
+	cfg         *config.WebhooksConfig
+	log         *logger.Logger
+
+	// Enabled endpoints of every tenant, reloaded by Refresh
+	mu     sync.RWMutex
+	active []*domain.WebhookEndpoint
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher. Endpoints are
+// loaded by Refresh.
+func NewWebhookDispatcher(
+	endpoints WebhookEndpointStore,
+	events WebhookEventStore,
+	deadLetters WebhookDeadLetterStore,
+	alerts AlertStore,
+	cfg *config.WebhooksConfig,
+	log *logger.Logger,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		endpoints:   endpoints,
+		events:      events,
+		deadLetters: deadLetters,
+		alerts:      alerts,
+		client:      &http.Client{Timeout: cfg.Timeout},
+		queue:       make(chan *webhookDelivery, cfg.QueueSize),
+		cfg:         cfg,
+		log:         log.Named("webhook_dispatcher"),
+	}
+}
+
+// Refresh reloads the enabled endpoints of every tenant. Changes made on
+// other instances are picked up on the next periodic refresh.
+func (d *WebhookDispatcher) Refresh(ctx context.Context) error {
+	active, err := d.endpoints.ListEnabled(ctx)
+	if err != nil {
+		return fmt.Errorf("list webhook endpoints: %w", err)
+	}
+
+	d.mu.Lock()
+	d.active = active
+	d.mu.Unlock()
+	return nil
+}
+
+// Notify queues a screening decision for the endpoints of its tenant
+// subscribed to it. The payload is the screening response.
+func (d *WebhookDispatcher) Notify(ctx context.Context, result *domain.ScreeningResult) {
+	d.publish(ctx, result.TenantID, domain.ScreeningWebhookEvent(result.Decision), result.ToResponse(), result)
+}
+
+// Publish queues an event for the endpoints of tenantID subscribed to its
+// type. It never blocks: when the queue is full the event is dead-lettered
+// instead.
+func (d *WebhookDispatcher) Publish(ctx context.Context, tenantID string, eventType domain.WebhookEventType, payload interface{}) {
+	d.publish(ctx, tenantID, eventType, payload, nil)
+}
+
+func (d *WebhookDispatcher) publish(ctx context.Context, tenantID string, eventType domain.WebhookEventType, payload interface{}, result *domain.ScreeningResult) {
+	if tenantID == "" {
+		// Records not yet stored name no tenant; they are stored for the
+		// default one
+		tenantID = tenant.Default
+	}
+	endpoints := d.subscribers(tenantID, eventType)
+	if len(endpoints) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		d.log.Error("failed to marshal webhook payload",
+			logger.StringField("event_type", string(eventType)),
+			logger.ErrorField(err),
+		)
+		return
+	}
+
+	event := &webhookEvent{
+		WebhookEvent: domain.WebhookEvent{ID: uuid.New(), TenantID: tenantID, Type: eventType, Payload: data, CreatedAt: time.Now()},
+		result:       result,
+	}
+	for _, e := range endpoints {
+		d.enqueue(ctx, &webhookDelivery{endpoint: e, event: event})
+	}
+}
+
+// Replay queues a stored event for the given endpoints again, under its
+// original event ID. The endpoints must belong to the event's tenant.
+func (d *WebhookDispatcher) Replay(ctx context.Context, event *domain.WebhookEvent, endpoints []*domain.WebhookEndpoint) error {
+	if len(d.queue)+len(endpoints) > cap(d.queue) {
+		return apperr.Unavailable("webhook queue", errors.New("queue is full"))
+	}
+
+	queued := &webhookEvent{WebhookEvent: *event}
+	queued.store.Do(func() {})
+	for _, e := range endpoints {
+		d.enqueue(ctx, &webhookDelivery{endpoint: e, event: queued, replay: true})
+	}
+	return nil
+}
+
+// subscribers returns the enabled endpoints of a tenant subscribed to an
+// event type
+func (d *WebhookDispatcher) subscribers(tenantID string, eventType domain.WebhookEventType) []*domain.WebhookEndpoint {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var endpoints []*domain.WebhookEndpoint
+	for _, e := range d.active {
+		if e.TenantID == tenantID && e.Subscribes(eventType) {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+func (d *WebhookDispatcher) enqueue(ctx context.Context, delivery *webhookDelivery) {
+	select {
+	case d.queue <- delivery:
+	default:
+		metrics.RecordWebhookDelivery(delivery.endpoint.Name, "dropped")
+		d.storeEvent(ctx, delivery.event)
+		d.deadLetter(ctx, delivery, 0, "webhook queue full")
+	}
+}
+
+// Run delivers queued events and refreshes the endpoints until ctx is
+// cancelled. Events still queued at shutdown are dead-lettered so none is
+// lost silently.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	d.log.Info("webhook dispatcher started",
+		logger.IntField("workers", d.cfg.Workers),
+		logger.DurationField("refresh_interval", d.cfg.RefreshInterval),
+	)
+
+	var wg sync.WaitGroup
+	for range d.cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delivery := <-d.queue:
+					d.deliver(ctx, delivery)
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(d.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			done = true
+		case <-ticker.C:
+			if err := d.Refresh(ctx); err != nil {
+				d.log.Error("failed to refresh webhook endpoints", logger.ErrorField(err))
+			}
+		}
+	}
+	wg.Wait()
+
+	drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deadLetterTimeout)
+	defer cancel()
+	for {
+		select {
+		case delivery := <-d.queue:
+			d.storeEvent(drainCtx, delivery.event)
+			d.deadLetter(drainCtx, delivery, 0, "service shut down before delivery")
+		default:
+			d.log.Info("webhook dispatcher stopped")
+			return
+		}
+	}
+}
+
+// deliver sends an event, retrying transient failures. An event every
+// attempt fails to deliver, or that the endpoint rejects, is dead-lettered
+// and counted towards disabling the endpoint.
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *webhookDelivery) {
+	d.storeEvent(ctx, delivery.event)
+
+	backoff := d.cfg.RetryBackoff
+	var lastErr error
+	attempt := 1
+	for ; attempt <= d.cfg.MaxAttempts; attempt++ {
+		retry, err := d.post(ctx, delivery, attempt)
+		if err == nil {
+			metrics.RecordWebhookDelivery(delivery.endpoint.Name, "delivered")
+			if delivery.endpoint.ConsecutiveFailures > 0 || attempt > 1 {
+				if err := d.endpoints.RecordSuccess(ctx, delivery.endpoint.ID); err != nil {
+					d.log.Error("failed to reset webhook endpoint failures",
+						logger.StringField("endpoint", delivery.endpoint.Name),
+						logger.ErrorField(err),
+					)
+				}
+			}
+			return
+		}
+		lastErr = err
+		if !retry || attempt == d.cfg.MaxAttempts {
+			break
+		}
+
+		metrics.RecordWebhookDelivery(delivery.endpoint.Name, "retried")
+		select {
+		case <-ctx.Done():
+			d.deadLetter(context.WithoutCancel(ctx), delivery, attempt, fmt.Sprintf("%v; service shut down before retry", lastErr))
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	d.deadLetter(ctx, delivery, attempt, lastErr.Error())
+	d.recordFailure(ctx, delivery.endpoint, lastErr.Error())
+}
+
+// post makes one delivery attempt and records it. retry reports whether a
+// failure is worth retrying: network errors, timeouts, 408, 429 and 5xx
+// responses.
+func (d *WebhookDispatcher) post(ctx context.Context, delivery *webhookDelivery, attempt int) (retry bool, err error) {
+	start := time.Now()
+	statusCode := 0
+	defer func() {
+		d.recordAttempt(ctx, delivery, attempt, statusCode, time.Since(start), err)
+	}()
+
+	event := delivery.event
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.endpoint.URL, bytes.NewReader(event.Payload))
+	if err != nil {
+		return false, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventIDHeader, event.ID.String())
+	req.Header.Set(webhookEventTypeHeader, string(event.Type))
+	req.Header.Set(webhookAttemptHeader, strconv.Itoa(attempt))
+	req.Header.Set(webhookSignatureHeader, signWebhook([]byte(delivery.endpoint.Secret), time.Now(), event.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseBody))
+	statusCode = resp.StatusCode
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, fmt.Errorf("subscriber returned %s", resp.Status)
+	default:
+		return false, fmt.Errorf("subscriber rejected the event: %s", resp.Status)
+	}
+}
+
+// storeEvent persists an event the first time one of its deliveries runs.
+// A failure is logged: the event is still delivered, it just cannot be
+// replayed.
+func (d *WebhookDispatcher) storeEvent(ctx context.Context, event *webhookEvent) {
+	event.store.Do(func() {
+		if err := d.events.CreateEvent(context.WithoutCancel(ctx), &event.WebhookEvent); err != nil {
+			d.log.Error("failed to store webhook event",
+				logger.StringField("event_id", event.ID.String()),
+				logger.StringField("event_type", string(event.Type)),
+				logger.ErrorField(err),
+			)
+		}
+	})
+}
+
+// recordAttempt stores a delivery attempt for operators debugging missed
+// callbacks
+func (d *WebhookDispatcher) recordAttempt(ctx context.Context, delivery *webhookDelivery, attempt, statusCode int, latency time.Duration, deliveryErr error) {
+	record := &domain.WebhookDelivery{
+		ID:         uuid.New(),
+		EventID:    delivery.event.ID,
+		EventType:  delivery.event.Type,
+		EndpointID: delivery.endpoint.ID,
+		Attempt:    attempt,
+		Replay:     delivery.replay,
+		Status:     domain.WebhookDeliveryDelivered,
+		StatusCode: statusCode,
+		LatencyMs:  latency.Milliseconds(),
+		CreatedAt:  time.Now(),
+	}
+	if deliveryErr != nil {
+		record.Status = domain.WebhookDeliveryFailed
+		record.Error = deliveryErr.Error()
+	}
+
+	if err := d.events.CreateDelivery(context.WithoutCancel(ctx), record); err != nil {
+		d.log.Error("failed to record webhook delivery",
+			logger.StringField("event_id", record.EventID.String()),
+			logger.StringField("endpoint", delivery.endpoint.Name),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// recordFailure counts a failed delivery against an endpoint. The instance
+// whose failure disables the endpoint raises the alert.
+func (d *WebhookDispatcher) recordFailure(ctx context.Context, endpoint *domain.WebhookEndpoint, reason string) {
+	now := time.Now()
+	disabled, err := d.endpoints.RecordFailure(ctx, endpoint.ID, d.cfg.DisableAfterFailures, reason, now)
+	if err != nil {
+		d.log.Error("failed to record webhook endpoint failure",
+			logger.StringField("endpoint", endpoint.Name),
+			logger.ErrorField(err),
+		)
+		return
+	}
+	if !disabled {
+		return
+	}
+
+	metrics.RecordWebhookDelivery(endpoint.Name, "endpoint_disabled")
+	d.log.Error("webhook endpoint disabled after repeated failures",
+		logger.StringField("endpoint", endpoint.Name),
+		logger.StringField("endpoint_id", endpoint.ID.String()),
+		logger.IntField("failures", d.cfg.DisableAfterFailures),
+		logger.StringField("reason", reason),
+	)
+
+	alert := &domain.AMLAlert{
+		ID:          uuid.New(),
+		AlertNumber: domain.GenerateAlertNumber(now),
+		// The alert concerns no customer; it is filed under the system actor
+		UserID:        domain.SystemActorID,
+		AlertType:     domain.AlertTypeSystemGenerated,
+		Status:        domain.AlertStatusNew,
+		Priority:      domain.RiskLevelHigh,
+		Title:         fmt.Sprintf("Webhook endpoint %s disabled", endpoint.Name),
+		Description:   fmt.Sprintf("Webhook endpoint %s (%s) was disabled after %d failed deliveries in a row; last error: %s. Re-enable it once the receiver is fixed and replay the dead-lettered events.", endpoint.Name, endpoint.URL, d.cfg.DisableAfterFailures, reason),
+		Confidence:    1.0,
+		DetectionRule: webhookDisabledRule,
+		DetectedAt:    now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := d.alerts.Create(ctx, alert); err != nil {
+		d.log.Error("failed to raise webhook endpoint disabled alert",
+			logger.StringField("endpoint", endpoint.Name),
+			logger.ErrorField(err),
+		)
+	}
+
+	if err := d.Refresh(ctx); err != nil {
+		d.log.Error("failed to refresh webhook endpoints", logger.ErrorField(err))
+	}
+}
+
+// deadLetter stores an undeliverable event. If even that fails the event is
+// logged in full so it can still be recovered.
+func (d *WebhookDispatcher) deadLetter(ctx context.Context, delivery *webhookDelivery, attempts int, reason string) {
+	metrics.RecordWebhookDelivery(delivery.endpoint.Name, "dead_lettered")
+
+	event := delivery.event
+	letter := &domain.WebhookDeadLetter{
+		ID:         uuid.New(),
+		EventID:    event.ID,
+		EventType:  event.Type,
+		EndpointID: delivery.endpoint.ID,
+		Subscriber: delivery.endpoint.Name,
+		URL:        delivery.endpoint.URL,
+		Payload:    event.Payload,
+		Attempts:   attempts,
+		LastError:  reason,
+		CreatedAt:  time.Now(),
+	}
+	if r := event.result; r != nil {
+		letter.ScreeningID, letter.TransactionID, letter.Decision = &r.ID, &r.TransactionID, r.Decision
+	}
+	d.log.Error("webhook event undeliverable",
+		logger.StringField("subscriber", letter.Subscriber),
+		logger.StringField("event_id", letter.EventID.String()),
+		logger.StringField("event_type", string(letter.EventType)),
+		logger.IntField("attempts", attempts),
+		logger.StringField("reason", reason),
+	)
+
+	if err := d.deadLetters.Create(ctx, letter); err != nil {
+		d.log.Error("failed to store webhook dead letter",
+			logger.StringField("event_id", letter.EventID.String()),
+			logger.StringField("payload", string(letter.Payload)),
+			logger.ErrorField(err),
+		)
+	}
+}
+
+// signWebhook returns the signature header value for a payload sent at t
+func signWebhook(secret []byte, t time.Time, payload []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
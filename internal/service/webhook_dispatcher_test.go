@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+)
+
+// staticEndpoints serves a fixed set of enabled endpoints
+type staticEndpoints struct {
+	WebhookEndpointStore
+	endpoints []*domain.WebhookEndpoint
+}
+
+func (s *staticEndpoints) ListEnabled(context.Context) ([]*domain.WebhookEndpoint, error) {
+	return s.endpoints, nil
+}
+
+func TestWebhookDispatchStaysWithinTenant(t *testing.T) {
+	endpoint := func(tenantID, name string) *domain.WebhookEndpoint {
+		return &domain.WebhookEndpoint{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			Name:       name,
+			EventTypes: []domain.WebhookEventType{domain.WebhookEventScreeningBlocked},
+			Enabled:    true,
+		}
+	}
+	bankA, bankA2, bankB := endpoint("bank-a", "a"), endpoint("bank-a", "a2"), endpoint("bank-b", "b")
+	fallback := endpoint("default", "default")
+
+	cfg := &config.WebhooksConfig{QueueSize: 10, Timeout: time.Second}
+	d := NewWebhookDispatcher(&staticEndpoints{endpoints: []*domain.WebhookEndpoint{bankA, bankB, bankA2, fallback}},
+		nil, nil, nil, cfg, logger.NewNop())
+	if err := d.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tenantID string
+		want     []*domain.WebhookEndpoint
+	}{
+		{"tenant a", "bank-a", []*domain.WebhookEndpoint{bankA, bankA2}},
+		{"tenant b", "bank-b", []*domain.WebhookEndpoint{bankB}},
+		{"no tenant", "", []*domain.WebhookEndpoint{fallback}},
+		{"tenant without endpoints", "bank-c", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d.Notify(context.Background(), &domain.ScreeningResult{
+				ID:       uuid.New(),
+				TenantID: tt.tenantID,
+				Decision: domain.DecisionBlocked,
+			})
+
+			var got []*domain.WebhookEndpoint
+			for len(d.queue) > 0 {
+				delivery := <-d.queue
+				if delivery.event.TenantID != tt.want[0].TenantID {
+					t.Errorf("event stored for tenant %q, want %q", delivery.event.TenantID, tt.want[0].TenantID)
+				}
+				got = append(got, delivery.endpoint)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("queued for %d endpoints, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("delivery %d went to %s/%s, want %s/%s", i, got[i].TenantID, got[i].Name, tt.want[i].TenantID, tt.want[i].Name)
+				}
+			}
+		})
+	}
+}
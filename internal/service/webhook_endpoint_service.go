@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+)
+
+// WebhookEndpointAdminStore manages registered webhook endpoints
+type WebhookEndpointAdminStore interface {
+	Create(ctx context.Context, e *domain.WebhookEndpoint) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error)
+	List(ctx context.Context) ([]*domain.WebhookEndpoint, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Enable(ctx context.Context, id uuid.UUID) error
+	SyncConfigured(ctx context.Context, endpoints []*domain.WebhookEndpoint) error
+}
+
+// WebhookHistoryStore reads stored webhook events and delivery attempts
+type WebhookHistoryStore interface {
+	GetEvent(ctx context.Context, id uuid.UUID) (*domain.WebhookEvent, error)
+	ListDeliveries(ctx context.Context, endpointID uuid.UUID, eventID *uuid.UUID, limit, offset int) ([]*domain.WebhookDelivery, error)
+}
+
+// WebhookEndpointService registers webhook endpoints, shows their delivery
+// history and replays events to them. Every change is recorded in the audit
+// log and applied to this instance's dispatcher at once; other instances
+// pick it up on their next refresh.
+type WebhookEndpointService struct {
+	endpoints  WebhookEndpointAdminStore
+	history    WebhookHistoryStore
+	dispatcher *WebhookDispatcher
+	auditor    Auditor
+	log        *logger.Logger
+}
+
+// NewWebhookEndpointService creates a new webhook endpoint service
+func NewWebhookEndpointService(
+	endpoints WebhookEndpointAdminStore,
+	history WebhookHistoryStore,
+	dispatcher *WebhookDispatcher,
+	auditor Auditor,
+	log *logger.Logger,
+) *WebhookEndpointService {
+	return &WebhookEndpointService{
+		endpoints:  endpoints,
+		history:    history,
+		dispatcher: dispatcher,
+		auditor:    auditor,
+		log:        log.Named("webhook_endpoint_service"),
+	}
+}
+
+// SyncConfigured registers the configured webhook subscribers as endpoints
+// of their tenants, subscribed to the screening events of their decisions,
+// and loads the dispatcher's endpoints. Subscribers removed from config are
+// deleted.
+func (s *WebhookEndpointService) SyncConfigured(ctx context.Context, subscribers []config.WebhookSubscriberConfig) error {
+	now := time.Now().UTC()
+	endpoints := make([]*domain.WebhookEndpoint, len(subscribers))
+	for i, sub := range subscribers {
+		decisions := sub.Decisions
+		if len(decisions) == 0 {
+			decisions = []string{string(domain.DecisionBlocked), string(domain.DecisionSuspicious)}
+		}
+		eventTypes := make([]domain.WebhookEventType, len(decisions))
+		for j, d := range decisions {
+			eventTypes[j] = domain.ScreeningWebhookEvent(domain.ScreeningDecision(d))
+		}
+
+		tenantID := sub.TenantID
+		if tenantID == "" {
+			tenantID = tenant.Default
+		}
+
+		endpoints[i] = &domain.WebhookEndpoint{
+			ID:              uuid.New(),
+			TenantID:        tenantID,
+			Name:            sub.Name,
+			URL:             sub.URL,
+			Secret:          sub.Secret,
+			EventTypes:      eventTypes,
+			Enabled:         true,
+			ManagedByConfig: true,
+			CreatedBy:       domain.SystemActorID,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+	}
+
+	if err := s.endpoints.SyncConfigured(ctx, endpoints); err != nil {
+		return fmt.Errorf("sync configured webhook endpoints: %w", err)
+	}
+	return s.dispatcher.Refresh(ctx)
+}
+
+// Create registers an endpoint. ErrConflict is returned if the name is
+// taken.
+func (s *WebhookEndpointService) Create(ctx context.Context, req *domain.CreateWebhookEndpointRequest) (*domain.WebhookEndpoint, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an http or https URL", domain.ErrValidation)
+	}
+
+	now := time.Now().UTC()
+	endpoint := &domain.WebhookEndpoint{
+		ID:         uuid.New(),
+		Name:       req.Name,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Enabled:    true,
+		CreatedBy:  req.ActorID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.endpoints.Create(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, req.ActorID, endpoint, "registered", nil, map[string]interface{}{
+		"name":        endpoint.Name,
+		"url":         endpoint.URL,
+		"event_types": endpoint.EventTypes,
+	})
+	s.refresh(ctx)
+	return endpoint, nil
+}
+
+// List returns the endpoints of the tenant ctx acts for by name
+func (s *WebhookEndpointService) List(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	return s.endpoints.List(ctx)
+}
+
+// Get returns an endpoint by ID
+func (s *WebhookEndpointService) Get(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	return s.endpoints.GetByID(ctx, id)
+}
+
+// Delete removes an endpoint registered through the API. ErrConflict is
+// returned for endpoints managed by config.
+func (s *WebhookEndpointService) Delete(ctx context.Context, id uuid.UUID, req *domain.WebhookEndpointChangeRequest) error {
+	endpoint, err := s.endpoints.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.endpoints.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.audit(ctx, req.ActorID, endpoint, "deleted", map[string]interface{}{
+		"name":        endpoint.Name,
+		"url":         endpoint.URL,
+		"event_types": endpoint.EventTypes,
+		"enabled":     endpoint.Enabled,
+	}, map[string]interface{}{
+		"reason": req.Reason,
+	})
+	s.refresh(ctx)
+	return nil
+}
+
+// Enable re-enables an endpoint, typically one disabled after repeated
+// delivery failures, and clears its failure count
+func (s *WebhookEndpointService) Enable(ctx context.Context, id uuid.UUID, req *domain.WebhookEndpointChangeRequest) (*domain.WebhookEndpoint, error) {
+	before, err := s.endpoints.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.endpoints.Enable(ctx, id); err != nil {
+		return nil, err
+	}
+	endpoint, err := s.endpoints.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, req.ActorID, endpoint, "enabled", map[string]interface{}{
+		"enabled":              before.Enabled,
+		"consecutive_failures": before.ConsecutiveFailures,
+		"disabled_reason":      before.DisabledReason,
+	}, map[string]interface{}{
+		"enabled": true,
+		"reason":  req.Reason,
+	})
+	s.refresh(ctx)
+	return endpoint, nil
+}
+
+// Deliveries returns an endpoint's delivery attempts, newest first,
+// optionally only those of one event
+func (s *WebhookEndpointService) Deliveries(ctx context.Context, id uuid.UUID, eventID *uuid.UUID, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	if _, err := s.endpoints.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.history.ListDeliveries(ctx, id, eventID, limit, offset)
+}
+
+// Replay re-sends a stored event under its original event ID, to one
+// endpoint or to every enabled endpoint of its tenant subscribed to its
+// type. A disabled endpoint must be enabled before events can be replayed
+// to it.
+func (s *WebhookEndpointService) Replay(ctx context.Context, eventID uuid.UUID, req *domain.ReplayWebhookEventRequest) (*domain.ReplayWebhookEventResponse, error) {
+	event, err := s.history.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*domain.WebhookEndpoint
+	if req.EndpointID != nil {
+		endpoint, err := s.endpoints.GetByID(ctx, *req.EndpointID)
+		if err != nil {
+			return nil, err
+		}
+		if endpoint.TenantID != event.TenantID {
+			return nil, fmt.Errorf("%w: webhook endpoint %s does not belong to the event's tenant", domain.ErrValidation, endpoint.Name)
+		}
+		if !endpoint.Enabled {
+			return nil, fmt.Errorf("%w: webhook endpoint %s is disabled", domain.ErrConflict, endpoint.Name)
+		}
+		if !endpoint.Subscribes(event.Type) {
+			return nil, fmt.Errorf("%w: webhook endpoint %s is not subscribed to %s", domain.ErrValidation, endpoint.Name, event.Type)
+		}
+		targets = []*domain.WebhookEndpoint{endpoint}
+	} else {
+		endpoints, err := s.endpoints.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range endpoints {
+			if e.TenantID == event.TenantID && e.Enabled && e.Subscribes(event.Type) {
+				targets = append(targets, e)
+			}
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("%w: no enabled webhook endpoint is subscribed to %s", domain.ErrConflict, event.Type)
+		}
+	}
+
+	if err := s.dispatcher.Replay(ctx, event, targets); err != nil {
+		return nil, err
+	}
+
+	resp := &domain.ReplayWebhookEventResponse{EventID: event.ID, EndpointIDs: make([]uuid.UUID, len(targets))}
+	for i, e := range targets {
+		resp.EndpointIDs[i] = e.ID
+	}
+
+	err = s.auditor.Record(ctx, audit.Entry{
+		ActorID:    req.ActorID,
+		Action:     audit.ActionWebhookEventReplayed,
+		EntityType: audit.EntityWebhookEvent,
+		EntityID:   event.ID.String(),
+		After: map[string]interface{}{
+			"event_type":   event.Type,
+			"endpoint_ids": resp.EndpointIDs,
+			"reason":       req.Reason,
+		},
+	})
+	if err != nil {
+		s.log.Error("failed to audit webhook event replay",
+			logger.StringField("event_id", event.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	s.log.Info("webhook event replayed",
+		logger.StringField("event_id", event.ID.String()),
+		logger.StringField("event_type", string(event.Type)),
+		logger.IntField("endpoints", len(targets)),
+		logger.StringField("actor_id", req.ActorID.String()),
+	)
+	return resp, nil
+}
+
+// audit records an endpoint change; failures are logged, as the change has
+// already been applied
+func (s *WebhookEndpointService) audit(ctx context.Context, actor uuid.UUID, endpoint *domain.WebhookEndpoint, change string, before, after map[string]interface{}) {
+	err := s.auditor.Record(ctx, audit.Entry{
+		ActorID:    actor,
+		Action:     audit.ActionWebhookEndpointChanged,
+		EntityType: audit.EntityWebhookEndpoint,
+		EntityID:   endpoint.ID.String(),
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		s.log.Error("failed to audit webhook endpoint change",
+			logger.StringField("endpoint_id", endpoint.ID.String()),
+			logger.ErrorField(err),
+		)
+	}
+
+	s.log.Info("webhook endpoint changed",
+		logger.StringField("endpoint", endpoint.Name),
+		logger.StringField("change", change),
+		logger.StringField("actor_id", actor.String()),
+	)
+}
+
+// refresh applies an endpoint change to this instance's dispatcher
+func (s *WebhookEndpointService) refresh(ctx context.Context) {
+	if err := s.dispatcher.Refresh(ctx); err != nil {
+		s.log.Error("failed to refresh webhook endpoints", logger.ErrorField(err))
+	}
+}
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS screening_results (
+    id                    UUID PRIMARY KEY,
+    transaction_id        UUID        NOT NULL,
+    user_id               UUID        NOT NULL,
+    risk_score            INTEGER     NOT NULL CHECK (risk_score BETWEEN 0 AND 100),
+    decision              VARCHAR(20) NOT NULL,
+    risk_level            VARCHAR(20) NOT NULL,
+    ofac_match            JSONB,
+    pep_match             JSONB,
+    risk_factors          JSONB       NOT NULL DEFAULT '[]',
+    pattern_matches       JSONB       NOT NULL DEFAULT '[]',
+    screening_duration_ms BIGINT      NOT NULL DEFAULT 0,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_screening_results_transaction_id
+    ON screening_results (transaction_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_screening_results_user_id
+    ON screening_results (user_id, created_at DESC);
@@ -0,0 +1 @@
+ALTER TABLE screening_results ADD COLUMN IF NOT EXISTS applied_thresholds JSONB;
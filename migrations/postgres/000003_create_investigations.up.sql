@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS investigations (
+    id                  UUID PRIMARY KEY,
+    case_number         VARCHAR(50)  NOT NULL UNIQUE,
+    user_id             UUID         NOT NULL,
+    transaction_id      UUID,
+    screening_result_id UUID,
+    alert_id            UUID,
+    status              VARCHAR(20)  NOT NULL,
+    priority            VARCHAR(20)  NOT NULL,
+    risk_score          INTEGER      NOT NULL DEFAULT 0,
+    investigation_type  VARCHAR(50)  NOT NULL,
+    assigned_to         UUID,
+    assigned_at         TIMESTAMPTZ,
+    assigned_by         UUID,
+    title               VARCHAR(200) NOT NULL,
+    description         TEXT         NOT NULL,
+    findings            TEXT         NOT NULL DEFAULT '',
+    evidence            JSONB        NOT NULL DEFAULT '[]',
+    decision            VARCHAR(30),
+    decision_reason     TEXT         NOT NULL DEFAULT '',
+    decision_by         UUID,
+    decision_at         TIMESTAMPTZ,
+    sar_filing_id       UUID,
+    ctr_filing_id       UUID,
+    due_date            TIMESTAMPTZ  NOT NULL,
+    sla_breached        BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    closed_at           TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_investigations_status ON investigations (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_investigations_assigned_to ON investigations (assigned_to) WHERE assigned_to IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_investigations_due_date ON investigations (due_date) WHERE status <> 'CLOSED';
+CREATE INDEX IF NOT EXISTS idx_investigations_user_id ON investigations (user_id);
@@ -0,0 +1 @@
+ALTER TABLE screening_results DROP COLUMN IF EXISTS reason_codes;
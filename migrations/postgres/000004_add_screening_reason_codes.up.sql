@@ -0,0 +1 @@
+ALTER TABLE screening_results ADD COLUMN IF NOT EXISTS reason_codes JSONB NOT NULL DEFAULT '[]';
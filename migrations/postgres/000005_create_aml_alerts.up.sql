@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS aml_alerts (
+    id               UUID PRIMARY KEY,
+    alert_number     VARCHAR(50)  NOT NULL UNIQUE,
+    user_id          UUID         NOT NULL,
+    transaction_id   UUID,
+    alert_type       VARCHAR(30)  NOT NULL,
+    status           VARCHAR(20)  NOT NULL,
+    priority         VARCHAR(20)  NOT NULL,
+    risk_score       INTEGER      NOT NULL DEFAULT 0,
+    title            VARCHAR(200) NOT NULL,
+    description      TEXT         NOT NULL,
+    pattern_type     VARCHAR(30),
+    related_tx_ids   UUID[]       NOT NULL DEFAULT '{}',
+    confidence       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    detection_rule   VARCHAR(100) NOT NULL,
+    investigation_id UUID,
+    reviewed_by      UUID,
+    reviewed_at      TIMESTAMPTZ,
+    resolution       TEXT         NOT NULL DEFAULT '',
+    detected_at      TIMESTAMPTZ  NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_user_id ON aml_alerts (user_id, detected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_status ON aml_alerts (status, detected_at DESC);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS investigation_timeline (
+    id               UUID PRIMARY KEY,
+    investigation_id UUID        NOT NULL REFERENCES investigations (id),
+    event_type       VARCHAR(50) NOT NULL,
+    description      TEXT        NOT NULL,
+    old_value        TEXT        NOT NULL DEFAULT '',
+    new_value        TEXT        NOT NULL DEFAULT '',
+    actor_id         UUID        NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_investigation_timeline_investigation_id
+    ON investigation_timeline (investigation_id, created_at);
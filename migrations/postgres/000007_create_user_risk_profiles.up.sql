@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS user_risk_profiles (
+    id                    UUID PRIMARY KEY,
+    user_id               UUID        NOT NULL UNIQUE,
+    risk_score            INTEGER     NOT NULL DEFAULT 0,
+    risk_level            VARCHAR(20) NOT NULL DEFAULT 'LOW',
+    last_assessment       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    next_review_date      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    country_risk          INTEGER     NOT NULL DEFAULT 0,
+    occupation_risk       INTEGER     NOT NULL DEFAULT 0,
+    transaction_risk      INTEGER     NOT NULL DEFAULT 0,
+    behavioral_risk       INTEGER     NOT NULL DEFAULT 0,
+    relationship_risk     INTEGER     NOT NULL DEFAULT 0,
+    is_pep                BOOLEAN     NOT NULL DEFAULT FALSE,
+    pep_details           JSONB,
+    is_high_net_worth     BOOLEAN     NOT NULL DEFAULT FALSE,
+    has_ofac_match        BOOLEAN     NOT NULL DEFAULT FALSE,
+    ofac_match_details    TEXT        NOT NULL DEFAULT '',
+    avg_monthly_volume    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    avg_transaction_amt   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    tx_count_last_30_days INTEGER     NOT NULL DEFAULT 0,
+    primary_countries     TEXT[]      NOT NULL DEFAULT '{}',
+    high_risk_countries   TEXT[]      NOT NULL DEFAULT '{}',
+    sar_count             INTEGER     NOT NULL DEFAULT 0,
+    investigation_count   INTEGER     NOT NULL DEFAULT 0,
+    blocked_tx_count      INTEGER     NOT NULL DEFAULT 0,
+    on_watchlist          BOOLEAN     NOT NULL DEFAULT FALSE,
+    watchlist_reason      TEXT        NOT NULL DEFAULT '',
+    watchlist_added_at    TIMESTAMPTZ,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_risk_profiles_watchlist
+    ON user_risk_profiles (user_id) WHERE on_watchlist;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_screening_results_rescreen_of_id;
+
+ALTER TABLE screening_results
+    DROP COLUMN IF EXISTS rescreen_of_id,
+    DROP COLUMN IF EXISTS pep_list_updated_at,
+    DROP COLUMN IF EXISTS ofac_list_updated_at,
+    DROP COLUMN IF EXISTS transaction;
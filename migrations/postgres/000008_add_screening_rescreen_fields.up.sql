@@ -0,0 +1,8 @@
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS transaction          JSONB,
+    ADD COLUMN IF NOT EXISTS ofac_list_updated_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS pep_list_updated_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS rescreen_of_id       UUID REFERENCES screening_results (id);
+
+CREATE INDEX IF NOT EXISTS idx_screening_results_rescreen_of_id
+    ON screening_results (rescreen_of_id) WHERE rescreen_of_id IS NOT NULL;
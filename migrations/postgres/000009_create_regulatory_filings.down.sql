@@ -0,0 +1 @@
+DROP TABLE IF EXISTS regulatory_filings;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS regulatory_filings (
+    id                    UUID PRIMARY KEY,
+    filing_number         VARCHAR(50)  NOT NULL UNIQUE,
+    bsa_filing_id         VARCHAR(50)  NOT NULL DEFAULT '',
+    filing_type           VARCHAR(10)  NOT NULL,
+    status                VARCHAR(20)  NOT NULL,
+    user_id               UUID         NOT NULL,
+    investigation_id      UUID REFERENCES investigations (id),
+    transaction_ids       UUID[]       NOT NULL DEFAULT '{}',
+    subject_info          JSONB,
+    suspicious_activity   JSONB,
+    ctr_details           JSONB,
+    total_amount          NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    currency              VARCHAR(3)   NOT NULL DEFAULT 'USD',
+    narrative             TEXT         NOT NULL DEFAULT '',
+    prepared_by           UUID         NOT NULL,
+    reviewed_by           UUID,
+    approved_by           UUID,
+    activity_start_date   TIMESTAMPTZ  NOT NULL,
+    activity_end_date     TIMESTAMPTZ  NOT NULL,
+    filing_due_date       TIMESTAMPTZ  NOT NULL,
+    submitted_at          TIMESTAMPTZ,
+    confirmation_number   VARCHAR(50)  NOT NULL DEFAULT '',
+    rejection_reason      TEXT         NOT NULL DEFAULT '',
+    deadline_warning_days INTEGER,
+    overdue_alerted_at    TIMESTAMPTZ,
+    amended_from_id       UUID REFERENCES regulatory_filings (id),
+    amendment_reason      TEXT         NOT NULL DEFAULT '',
+    created_at            TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_regulatory_filings_user_id ON regulatory_filings (user_id, created_at DESC);
+
+-- Open SARs ordered by deadline, used by the deadline monitor
+CREATE INDEX IF NOT EXISTS idx_regulatory_filings_open_due
+    ON regulatory_filings (filing_due_date)
+    WHERE filing_type = 'SAR' AND status NOT IN ('SUBMITTED', 'ACCEPTED', 'AMENDED');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS batch_results;
+DROP TABLE IF EXISTS batch_jobs;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS batch_jobs (
+    id           UUID PRIMARY KEY,
+    status       VARCHAR(20) NOT NULL,
+    items        JSONB       NOT NULL,
+    total_items  INTEGER     NOT NULL,
+    cursor       INTEGER     NOT NULL DEFAULT 0,
+    hit_count    INTEGER     NOT NULL DEFAULT 0,
+    error        TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at   TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_jobs_runnable
+    ON batch_jobs (created_at) WHERE status IN ('PENDING', 'RUNNING');
+
+CREATE TABLE IF NOT EXISTS batch_results (
+    job_id      UUID        NOT NULL REFERENCES batch_jobs (id) ON DELETE CASCADE,
+    seq         INTEGER     NOT NULL,
+    name        TEXT        NOT NULL,
+    user_id     UUID,
+    ofac_match  JSONB,
+    pep_match   JSONB,
+    hit         BOOLEAN     NOT NULL DEFAULT FALSE,
+    error       TEXT        NOT NULL DEFAULT '',
+    screened_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (job_id, seq)
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_results_hits ON batch_results (job_id, seq) WHERE hit;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS filing_transitions (
+    id          UUID PRIMARY KEY,
+    filing_id   UUID        NOT NULL REFERENCES regulatory_filings (id),
+    from_status VARCHAR(20) NOT NULL,
+    to_status   VARCHAR(20) NOT NULL,
+    actor_id    UUID        NOT NULL,
+    reason      TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_filing_transitions_filing_id ON filing_transitions (filing_id, created_at);
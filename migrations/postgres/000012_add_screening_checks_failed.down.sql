@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_screening_results_pending;
+
+ALTER TABLE screening_results
+    DROP COLUMN IF EXISTS checks_failed;
@@ -0,0 +1,6 @@
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS checks_failed JSONB NOT NULL DEFAULT '[]';
+
+-- Held decisions awaiting re-screening
+CREATE INDEX IF NOT EXISTS idx_screening_results_pending
+    ON screening_results (created_at) WHERE decision = 'PENDING';
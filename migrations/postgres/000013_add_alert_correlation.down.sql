@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_aml_alerts_correlation;
+
+ALTER TABLE aml_alerts
+    DROP COLUMN IF EXISTS last_detected_at,
+    DROP COLUMN IF EXISTS occurrence_count;
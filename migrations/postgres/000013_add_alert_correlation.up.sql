@@ -0,0 +1,12 @@
+ALTER TABLE aml_alerts
+    ADD COLUMN IF NOT EXISTS occurrence_count INTEGER     NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS last_detected_at TIMESTAMPTZ;
+
+UPDATE aml_alerts SET last_detected_at = detected_at WHERE last_detected_at IS NULL;
+
+ALTER TABLE aml_alerts ALTER COLUMN last_detected_at SET NOT NULL;
+
+-- Open alerts by correlation key, used to fold repeat occurrences
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_correlation
+    ON aml_alerts (user_id, alert_type, detection_rule, last_detected_at DESC)
+    WHERE status NOT IN ('DISMISSED', 'RESOLVED');
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_screening_results_account_id
+    ON screening_results ((transaction->>'account_id'), created_at DESC)
+    WHERE transaction IS NOT NULL;
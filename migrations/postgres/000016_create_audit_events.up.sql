@@ -0,0 +1,19 @@
+-- before/after use JSON rather than JSONB: JSONB normalises the text, which
+-- would change the bytes covered by the event HMAC
+CREATE TABLE IF NOT EXISTS audit_events (
+    sequence    BIGINT PRIMARY KEY,
+    id          UUID         NOT NULL UNIQUE,
+    actor_id    UUID         NOT NULL,
+    action      VARCHAR(64)  NOT NULL,
+    entity_type VARCHAR(64)  NOT NULL,
+    entity_id   VARCHAR(128) NOT NULL,
+    before      JSON,
+    after       JSON,
+    request_id  VARCHAR(128) NOT NULL DEFAULT '',
+    timestamp   TIMESTAMPTZ  NOT NULL,
+    prev_hmac   CHAR(64)     NOT NULL DEFAULT '',
+    hmac        CHAR(64)     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity
+    ON audit_events (entity_type, entity_id, sequence);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_regulatory_filings_key_version;
+
+ALTER TABLE regulatory_filings
+    DROP COLUMN IF EXISTS encryption_key_version,
+    DROP COLUMN IF EXISTS narrative_encrypted;
@@ -0,0 +1,8 @@
+-- Version 0 marks rows written before field encryption; the key rotation
+-- job encrypts them under the current key
+ALTER TABLE regulatory_filings
+    ADD COLUMN IF NOT EXISTS narrative_encrypted    TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS encryption_key_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_regulatory_filings_key_version
+    ON regulatory_filings (encryption_key_version);
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_screening_results_blocked_device
+    ON screening_results ((transaction->>'device_id'), created_at DESC)
+    WHERE transaction IS NOT NULL AND decision = 'BLOCKED';
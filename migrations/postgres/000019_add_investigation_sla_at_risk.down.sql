@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_investigations_sla_open;
+ALTER TABLE investigations DROP COLUMN IF EXISTS sla_at_risk;
@@ -0,0 +1,5 @@
+ALTER TABLE investigations ADD COLUMN IF NOT EXISTS sla_at_risk BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_investigations_sla_open
+    ON investigations (due_date)
+    WHERE status <> 'CLOSED' AND NOT sla_breached;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_investigations_open_assignee;
+DROP TABLE IF EXISTS analysts;
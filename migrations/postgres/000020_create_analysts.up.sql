@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS analysts (
+    id             UUID PRIMARY KEY,
+    name           VARCHAR(200) NOT NULL,
+    skills         TEXT[]       NOT NULL DEFAULT '{}',
+    max_open_cases INTEGER      NOT NULL,
+    active         BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_investigations_open_assignee
+    ON investigations (assigned_to)
+    WHERE status <> 'CLOSED' AND assigned_to IS NOT NULL;
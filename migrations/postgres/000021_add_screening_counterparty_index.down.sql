@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_screening_results_counterparty_key;
@@ -0,0 +1,8 @@
+-- Expression must match counterpartyKeyExpr in entity_graph_repository.go
+CREATE INDEX IF NOT EXISTS idx_screening_results_counterparty_key
+    ON screening_results ((COALESCE(
+        NULLIF(CASE WHEN transaction->>'direction' = 'OUTBOUND'
+            THEN transaction->>'receiver_account' ELSE transaction->>'sender_account' END, ''),
+        'name:' || LOWER(NULLIF(CASE WHEN transaction->>'direction' = 'OUTBOUND'
+            THEN transaction->>'receiver_name' ELSE transaction->>'sender_name' END, '')))), created_at DESC)
+    WHERE transaction IS NOT NULL;
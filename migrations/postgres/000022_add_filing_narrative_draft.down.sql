@@ -0,0 +1,3 @@
+ALTER TABLE regulatory_filings
+    DROP COLUMN IF EXISTS narrative_draft_sha256,
+    DROP COLUMN IF EXISTS narrative_drafted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE regulatory_filings
+    ADD COLUMN IF NOT EXISTS narrative_drafted_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS narrative_draft_sha256 VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_regulatory_filings_amended_from;
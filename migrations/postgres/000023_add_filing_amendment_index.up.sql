@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_regulatory_filings_amended_from
+    ON regulatory_filings (amended_from_id)
+    WHERE amended_from_id IS NOT NULL;
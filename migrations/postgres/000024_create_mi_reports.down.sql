@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_regulatory_filings_submitted_at;
+DROP INDEX IF EXISTS idx_investigations_closed_at;
+DROP INDEX IF EXISTS idx_screening_results_created_at;
+DROP TABLE IF EXISTS mi_reports;
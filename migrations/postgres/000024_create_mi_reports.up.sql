@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS mi_reports (
+    period       VARCHAR(10) NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end   TIMESTAMPTZ NOT NULL,
+    report       JSONB       NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (period, period_start)
+);
+
+-- Range scans used to aggregate activity per reporting period
+CREATE INDEX IF NOT EXISTS idx_screening_results_created_at
+    ON screening_results (created_at) WHERE rescreen_of_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_investigations_closed_at
+    ON investigations (closed_at) WHERE closed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_regulatory_filings_submitted_at
+    ON regulatory_filings (filing_type, submitted_at) WHERE submitted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Keyset pagination for bulk exports, which include re-screens
+CREATE INDEX IF NOT EXISTS idx_screening_results_created_at_id
+    ON screening_results (created_at, id);
@@ -0,0 +1,7 @@
+ALTER TABLE screening_results
+    ADD CONSTRAINT screening_results_rescreen_of_id_fkey
+    FOREIGN KEY (rescreen_of_id) REFERENCES screening_results (id) NOT VALID;
+
+DROP INDEX IF EXISTS idx_aml_alerts_detected_at_id;
+DROP TABLE IF EXISTS retention_holds;
+DROP TABLE IF EXISTS retention_archives;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS retention_archives (
+    id           UUID PRIMARY KEY,
+    entity_type  VARCHAR(30) NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end   TIMESTAMPTZ NOT NULL,
+    object_key   TEXT        NOT NULL UNIQUE,
+    row_count    INTEGER     NOT NULL,
+    sha256       VARCHAR(64) NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_archives_period
+    ON retention_archives (entity_type, period_start);
+
+CREATE TABLE IF NOT EXISTS retention_holds (
+    id           UUID PRIMARY KEY,
+    entity_type  VARCHAR(30) NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end   TIMESTAMPTZ NOT NULL,
+    hold_until   TIMESTAMPTZ NOT NULL,
+    reason       TEXT        NOT NULL,
+    actor_id     UUID        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_holds_entity
+    ON retention_holds (entity_type, hold_until);
+
+-- Keyset pagination when archiving alerts
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_detected_at_id
+    ON aml_alerts (detected_at, id);
+
+-- An original screening may be archived while a later re-screen of it is
+-- still in Postgres; the reference is resolved by restoring the archive
+ALTER TABLE screening_results
+    DROP CONSTRAINT IF EXISTS screening_results_rescreen_of_id_fkey;
@@ -0,0 +1,2 @@
+ALTER TABLE screening_results
+    DROP COLUMN IF EXISTS errors;
@@ -0,0 +1,2 @@
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS errors JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+ALTER TABLE screening_results
+    DROP COLUMN IF EXISTS shadow_decision,
+    DROP COLUMN IF EXISTS shadow_score;
@@ -0,0 +1,3 @@
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS shadow_score INTEGER,
+    ADD COLUMN IF NOT EXISTS shadow_decision VARCHAR(20);
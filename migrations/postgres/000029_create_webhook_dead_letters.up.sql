@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id             UUID PRIMARY KEY,
+    event_id       UUID        NOT NULL,
+    subscriber     VARCHAR(100) NOT NULL,
+    url            TEXT        NOT NULL,
+    screening_id   UUID        NOT NULL,
+    transaction_id UUID        NOT NULL,
+    decision       VARCHAR(20) NOT NULL,
+    payload        JSONB       NOT NULL,
+    attempts       INTEGER     NOT NULL,
+    last_error     TEXT        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_subscriber
+    ON webhook_dead_letters (subscriber, created_at);
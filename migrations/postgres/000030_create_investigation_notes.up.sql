@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS investigation_notes (
+    id               UUID PRIMARY KEY,
+    investigation_id UUID        NOT NULL REFERENCES investigations (id),
+    author_id        UUID        NOT NULL,
+    content          TEXT        NOT NULL,
+    is_internal      BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_investigation_notes_investigation_id
+    ON investigation_notes (investigation_id, created_at);
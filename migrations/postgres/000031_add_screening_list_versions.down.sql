@@ -0,0 +1,3 @@
+ALTER TABLE screening_results
+    DROP COLUMN IF EXISTS pep_list_version,
+    DROP COLUMN IF EXISTS ofac_list_version;
@@ -0,0 +1,3 @@
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS ofac_list_version TEXT,
+    ADD COLUMN IF NOT EXISTS pep_list_version  TEXT;
@@ -0,0 +1,12 @@
+DELETE FROM webhook_dead_letters WHERE screening_id IS NULL;
+
+ALTER TABLE webhook_dead_letters
+    ALTER COLUMN decision SET NOT NULL,
+    ALTER COLUMN transaction_id SET NOT NULL,
+    ALTER COLUMN screening_id SET NOT NULL,
+    DROP COLUMN IF EXISTS endpoint_id,
+    DROP COLUMN IF EXISTS event_type;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id                     UUID PRIMARY KEY,
+    name                   VARCHAR(100) NOT NULL UNIQUE,
+    url                    TEXT         NOT NULL,
+    secret                 TEXT         NOT NULL,
+    encryption_key_version INTEGER      NOT NULL DEFAULT 0,
+    event_types            TEXT[]       NOT NULL,
+    enabled                BOOLEAN      NOT NULL DEFAULT TRUE,
+    managed_by_config      BOOLEAN      NOT NULL DEFAULT FALSE,
+    consecutive_failures   INTEGER      NOT NULL DEFAULT 0,
+    disabled_at            TIMESTAMPTZ,
+    disabled_reason        TEXT,
+    created_by             UUID         NOT NULL,
+    created_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id         UUID PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    payload    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_created_at ON webhook_events (created_at);
+
+-- Delivery attempts outlive deleted endpoints, so they are not foreign keys
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id          UUID PRIMARY KEY,
+    event_id    UUID        NOT NULL,
+    event_type  VARCHAR(50) NOT NULL,
+    endpoint_id UUID        NOT NULL,
+    attempt     INTEGER     NOT NULL,
+    replay      BOOLEAN     NOT NULL DEFAULT FALSE,
+    status      VARCHAR(20) NOT NULL,
+    status_code INTEGER,
+    latency_ms  BIGINT      NOT NULL,
+    error       TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_id
+    ON webhook_deliveries (endpoint_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id
+    ON webhook_deliveries (event_id);
+
+-- Dead letters now cover every event type, not only screening decisions
+ALTER TABLE webhook_dead_letters
+    ADD COLUMN IF NOT EXISTS event_type  VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS endpoint_id UUID,
+    ALTER COLUMN screening_id DROP NOT NULL,
+    ALTER COLUMN transaction_id DROP NOT NULL,
+    ALTER COLUMN decision DROP NOT NULL;
+
+UPDATE webhook_dead_letters
+SET event_type = 'screening.' || LOWER(decision)
+WHERE event_type IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS skipped_checks JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_regulatory_filings_pending_review;
+DROP INDEX IF EXISTS idx_aml_alerts_new_created;
+
+ALTER TABLE analysts
+    DROP COLUMN IF EXISTS last_digest_at,
+    DROP COLUMN IF EXISTS digest_enabled,
+    DROP COLUMN IF EXISTS alert_types,
+    DROP COLUMN IF EXISTS email;
@@ -0,0 +1,13 @@
+ALTER TABLE analysts
+    ADD COLUMN IF NOT EXISTS email          VARCHAR(320) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS alert_types    TEXT[]       NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS digest_enabled BOOLEAN      NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS last_digest_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_new_created
+    ON aml_alerts (created_at)
+    WHERE status = 'NEW';
+
+CREATE INDEX IF NOT EXISTS idx_regulatory_filings_pending_review
+    ON regulatory_filings (filing_due_date)
+    WHERE status = 'PENDING_REVIEW';
@@ -0,0 +1,4 @@
+ALTER TABLE screening_results
+    DROP COLUMN IF EXISTS country_risk_version;
+
+DROP TABLE IF EXISTS country_risk_tables;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS country_risk_tables (
+    version    INTEGER PRIMARY KEY CHECK (version > 0),
+    tiers      JSONB       NOT NULL,
+    comment    TEXT        NOT NULL,
+    created_by UUID        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- NULL on results screened before country risk tables were versioned
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS country_risk_version INTEGER;
@@ -0,0 +1,3 @@
+ALTER TABLE country_risk_tables
+    DROP COLUMN IF EXISTS rating_points,
+    DROP COLUMN IF EXISTS ratings;
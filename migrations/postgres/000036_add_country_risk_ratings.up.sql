@@ -0,0 +1,3 @@
+ALTER TABLE country_risk_tables
+    ADD COLUMN IF NOT EXISTS ratings       JSONB   NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS rating_points INTEGER NOT NULL DEFAULT 0 CHECK (rating_points BETWEEN 0 AND 100);
@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS counterparty_decay(TIMESTAMPTZ, TIMESTAMPTZ, DOUBLE PRECISION);
+DROP TABLE IF EXISTS counterparty_reputation;
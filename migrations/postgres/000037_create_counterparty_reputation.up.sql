@@ -0,0 +1,21 @@
+-- Counts are decayed as of decayed_at; readers decay them further to now
+CREATE TABLE IF NOT EXISTS counterparty_reputation (
+    account       TEXT             NOT NULL,
+    bank          TEXT             NOT NULL DEFAULT '',
+    blocked       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    suspicious    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    sars          DOUBLE PRECISION NOT NULL DEFAULT 0,
+    first_seen_at TIMESTAMPTZ      NOT NULL,
+    last_event_at TIMESTAMPTZ      NOT NULL,
+    decayed_at    TIMESTAMPTZ      NOT NULL,
+    PRIMARY KEY (account, bank)
+);
+
+-- Factor decaying a count held as of held_at to to_at. The exponent is
+-- capped so that ancient counts decay to almost nothing rather than
+-- underflowing.
+CREATE OR REPLACE FUNCTION counterparty_decay(held_at TIMESTAMPTZ, to_at TIMESTAMPTZ, half_life_seconds DOUBLE PRECISION)
+    RETURNS DOUBLE PRECISION
+    LANGUAGE SQL IMMUTABLE AS $$
+    SELECT power(0.5, LEAST(GREATEST(EXTRACT(EPOCH FROM (to_at - held_at)), 0) / half_life_seconds, 1000))
+$$;
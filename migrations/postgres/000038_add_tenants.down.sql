@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS tenant_settings;
+
+DELETE FROM mi_reports WHERE tenant_id <> '';
+ALTER TABLE mi_reports
+    DROP CONSTRAINT IF EXISTS mi_reports_pkey,
+    ADD PRIMARY KEY (period, period_start);
+ALTER TABLE mi_reports
+    DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_regulatory_filings_tenant_user;
+DROP INDEX IF EXISTS idx_investigations_tenant_status;
+DROP INDEX IF EXISTS idx_aml_alerts_tenant_user;
+DROP INDEX IF EXISTS idx_screening_results_tenant_user;
+
+ALTER TABLE batch_jobs DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE regulatory_filings DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE investigations DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE aml_alerts DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE screening_results DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,39 @@
+-- Rows written before tenants were introduced belong to the default tenant
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE aml_alerts
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE investigations
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE regulatory_filings
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE batch_jobs
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_screening_results_tenant_user
+    ON screening_results (tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_aml_alerts_tenant_user
+    ON aml_alerts (tenant_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_investigations_tenant_status
+    ON investigations (tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_regulatory_filings_tenant_user
+    ON regulatory_filings (tenant_id, user_id);
+
+-- Snapshots are stored per tenant; '' holds the snapshots across all tenants
+ALTER TABLE mi_reports
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE mi_reports
+    DROP CONSTRAINT IF EXISTS mi_reports_pkey,
+    ADD PRIMARY KEY (tenant_id, period, period_start);
+
+-- Per-tenant overrides of the configured screening and reporting rules;
+-- NULL falls back to the global configuration
+CREATE TABLE IF NOT EXISTS tenant_settings (
+    tenant_id           TEXT PRIMARY KEY,
+    decision_thresholds JSONB            NOT NULL DEFAULT '{}',
+    country_risk_tiers  JSONB            NOT NULL DEFAULT '[]',
+    sar_threshold       DOUBLE PRECISION CHECK (sar_threshold > 0),
+    ctr_threshold       DOUBLE PRECISION CHECK (ctr_threshold > 0),
+    updated_by          UUID             NOT NULL,
+    updated_at          TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,8 @@
+CREATE INDEX IF NOT EXISTS idx_screening_results_account_id
+    ON screening_results ((transaction->>'account_id'), created_at DESC)
+    WHERE transaction IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_screening_results_account_initiated;
+DROP INDEX IF EXISTS idx_screening_results_user_initiated;
+
+ALTER TABLE screening_results DROP COLUMN IF EXISTS initiated_at;
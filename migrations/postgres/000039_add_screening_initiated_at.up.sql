@@ -0,0 +1,21 @@
+-- Transaction history is windowed on when a transaction was initiated, not
+-- when it was screened, so replays and late events land on the right day.
+-- Transactions without an initiation time fall back to their screening.
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS initiated_at TIMESTAMPTZ;
+
+UPDATE screening_results
+SET initiated_at = CASE
+        WHEN COALESCE(transaction->>'initiated_at', '') IN ('', '0001-01-01T00:00:00Z') THEN created_at
+        ELSE (transaction->>'initiated_at')::timestamptz
+    END
+WHERE transaction IS NOT NULL AND initiated_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_screening_results_user_initiated
+    ON screening_results (user_id, initiated_at)
+    WHERE transaction IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_screening_results_account_initiated
+    ON screening_results ((transaction->>'account_id'), initiated_at)
+    WHERE transaction IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_screening_results_account_id;
@@ -0,0 +1,6 @@
+ALTER TABLE screening_results
+    DROP COLUMN IF EXISTS pep_list_version,
+    DROP COLUMN IF EXISTS ofac_list_version;
+
+ALTER TABLE screening_results RENAME COLUMN pep_list_hash TO pep_list_version;
+ALTER TABLE screening_results RENAME COLUMN ofac_list_hash TO ofac_list_version;
@@ -0,0 +1,10 @@
+-- The list content hashes stamped so far become ofac_list_hash and
+-- pep_list_hash; ofac_list_version and pep_list_version now hold the
+-- version numbers under which list snapshots are archived. Results
+-- screened before versions were numbered have none.
+ALTER TABLE screening_results RENAME COLUMN ofac_list_version TO ofac_list_hash;
+ALTER TABLE screening_results RENAME COLUMN pep_list_version TO pep_list_hash;
+
+ALTER TABLE screening_results
+    ADD COLUMN IF NOT EXISTS ofac_list_version BIGINT,
+    ADD COLUMN IF NOT EXISTS pep_list_version  BIGINT;
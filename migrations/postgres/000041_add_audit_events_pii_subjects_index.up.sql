@@ -0,0 +1,5 @@
+-- Data subject access reports look up PII_ACCESSED events by the users
+-- whose data was read. after is JSON, so the index is over its JSONB form.
+CREATE INDEX IF NOT EXISTS idx_audit_events_pii_subjects
+    ON audit_events USING GIN (((after::jsonb) -> 'subject_user_ids'))
+    WHERE action = 'PII_ACCESSED';
@@ -0,0 +1,6 @@
+DELETE FROM counterparty_reputation WHERE tenant_id <> 'default';
+ALTER TABLE counterparty_reputation
+    DROP CONSTRAINT IF EXISTS counterparty_reputation_pkey,
+    ADD PRIMARY KEY (account, bank);
+ALTER TABLE counterparty_reputation
+    DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,7 @@
+-- Each tenant learns about counterparties separately; reputations recorded
+-- before tenants were keyed on belong to the default tenant
+ALTER TABLE counterparty_reputation
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE counterparty_reputation
+    DROP CONSTRAINT IF EXISTS counterparty_reputation_pkey,
+    ADD PRIMARY KEY (tenant_id, account, bank);
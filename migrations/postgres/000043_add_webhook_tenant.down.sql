@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_webhook_endpoints_tenant;
+ALTER TABLE webhook_events
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE webhook_endpoints
+    DROP COLUMN IF EXISTS tenant_id;
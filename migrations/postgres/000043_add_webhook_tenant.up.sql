@@ -0,0 +1,10 @@
+-- Each tenant's webhook endpoints receive only its own events; endpoints
+-- and events recorded before tenants were keyed on belong to the default
+-- tenant
+ALTER TABLE webhook_endpoints
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE webhook_events
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_tenant
+    ON webhook_endpoints (tenant_id);
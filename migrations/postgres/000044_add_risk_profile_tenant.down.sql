@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_user_risk_profiles_tenant_watchlist;
+CREATE INDEX IF NOT EXISTS idx_user_risk_profiles_watchlist
+    ON user_risk_profiles (user_id) WHERE on_watchlist;
+
+DELETE FROM user_risk_profiles WHERE tenant_id <> 'default';
+ALTER TABLE user_risk_profiles
+    DROP CONSTRAINT IF EXISTS user_risk_profiles_tenant_user_key,
+    ADD CONSTRAINT user_risk_profiles_user_id_key UNIQUE (user_id);
+ALTER TABLE user_risk_profiles
+    DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,12 @@
+-- Each tenant keeps its own risk profile and watchlist entry for a user;
+-- profiles written before tenants were keyed on belong to the default
+-- tenant
+ALTER TABLE user_risk_profiles
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_risk_profiles
+    DROP CONSTRAINT IF EXISTS user_risk_profiles_user_id_key,
+    ADD CONSTRAINT user_risk_profiles_tenant_user_key UNIQUE (tenant_id, user_id);
+
+DROP INDEX IF EXISTS idx_user_risk_profiles_watchlist;
+CREATE INDEX IF NOT EXISTS idx_user_risk_profiles_tenant_watchlist
+    ON user_risk_profiles (tenant_id, watchlist_added_at DESC) WHERE on_watchlist;
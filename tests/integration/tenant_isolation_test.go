@@ -0,0 +1,205 @@
+//go:build integration
+
+// Package integration runs the repositories against the PostgreSQL and
+// Redis instances the service is configured for, through the same
+// AML_SERVICE_* environment as the server. The database must be migrated.
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/crypto"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/repository/redis"
+	"github.com/banking/aml-service/internal/screening"
+)
+
+func setup(t *testing.T) (*config.Config, *sql.DB, *goredis.Client) {
+	t.Helper()
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	db, err := postgres.NewDB(ctx, &cfg.Database)
+	if err != nil {
+		t.Fatalf("connect to postgres: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	client, err := redis.NewClient(ctx, &cfg.Redis)
+	if err != nil {
+		t.Fatalf("connect to redis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return cfg, db, client
+}
+
+// testTenants returns two tenant IDs no other run uses
+func testTenants() (string, string) {
+	run := uuid.NewString()[:8]
+	return "it-a-" + run, "it-b-" + run
+}
+
+func TestCounterpartyReputationIsolatesTenants(t *testing.T) {
+	_, db, _ := setup(t)
+	repo := postgres.NewCounterpartyRepository(db)
+	tenantA, tenantB := testTenants()
+	ctxA := tenant.WithID(context.Background(), tenantA)
+	ctxB := tenant.WithID(context.Background(), tenantB)
+
+	account := "IT" + strings.ToUpper(uuid.NewString()[:12])
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM counterparty_reputation WHERE account = $1`, account)
+	})
+
+	key := domain.CounterpartyKey{TenantID: tenantA, Account: account, Bank: "NWBK"}
+	event := domain.CounterpartyEvent{Key: key, Blocked: 1, At: time.Now()}
+	if err := repo.Record(ctxA, event, 90*24*time.Hour); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	reps, err := repo.ListByAccount(ctxB, account)
+	if err != nil {
+		t.Fatalf("ListByAccount for tenant b: %v", err)
+	}
+	if len(reps) != 0 {
+		t.Fatalf("tenant b read %d of tenant a's reputations", len(reps))
+	}
+
+	keyB := key
+	keyB.TenantID = tenantB
+	rep, err := repo.Get(ctxB, keyB)
+	if err != nil {
+		t.Fatalf("Get for tenant b: %v", err)
+	}
+	if rep != nil {
+		t.Fatalf("tenant b read tenant a's reputation: %+v", rep)
+	}
+
+	if _, err := repo.Get(ctxB, key); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Get of tenant a's key for tenant b = %v, want ErrForbidden", err)
+	}
+	if err := repo.Record(ctxB, event, 90*24*time.Hour); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Record of tenant a's event for tenant b = %v, want ErrForbidden", err)
+	}
+
+	reps, err = repo.ListByAccount(ctxA, account)
+	if err != nil {
+		t.Fatalf("ListByAccount for tenant a: %v", err)
+	}
+	if len(reps) != 1 || reps[0].TenantID != tenantA || reps[0].Blocked != 1 {
+		t.Fatalf("tenant a's reputations = %+v, want its one blocked screening", reps)
+	}
+}
+
+func TestSharedDeviceIsolatesTenants(t *testing.T) {
+	cfg, _, client := setup(t)
+	tenantA, tenantB := testTenants()
+	shared := cfg.Screening.SharedDevice
+	shared.MaxUsers = 1
+	checker := screening.NewSharedDeviceChecker(redis.NewDeviceUsers(client, &shared), &shared, logger.NewNop())
+
+	device := "it-device-" + uuid.NewString()
+	check := func(tenantID string, userID uuid.UUID) []domain.RiskFactor {
+		t.Helper()
+		factors, err := checker.Check(tenant.WithID(context.Background(), tenantID), &domain.Transaction{
+			UserID:      userID,
+			DeviceID:    device,
+			InitiatedAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+		return factors
+	}
+
+	userA1, userA2, userB := uuid.New(), uuid.New(), uuid.New()
+	check(tenantA, userA1)
+	if factors := check(tenantB, userB); len(factors) != 0 {
+		t.Fatalf("tenant b's only user on the device was flagged: %+v", factors)
+	}
+
+	factors := check(tenantA, userA2)
+	if len(factors) != 1 {
+		t.Fatalf("tenant a got %d factors, want 1", len(factors))
+	}
+	if strings.Contains(factors[0].Details, userB.String()) {
+		t.Fatalf("tenant a's factor names tenant b's user: %s", factors[0].Details)
+	}
+	if !strings.Contains(factors[0].Details, userA1.String()) {
+		t.Fatalf("tenant a's factor does not name its other user: %s", factors[0].Details)
+	}
+}
+
+func TestWebhookEndpointsIsolateTenants(t *testing.T) {
+	cfg, db, _ := setup(t)
+	keyring, err := crypto.NewKeyring(cfg.Security.EncryptionKeys, cfg.Security.CurrentKeyVersion)
+	if err != nil {
+		t.Fatalf("load keyring: %v", err)
+	}
+	repo := postgres.NewWebhookEndpointRepository(db, keyring)
+	tenantA, tenantB := testTenants()
+	ctxA := tenant.WithID(context.Background(), tenantA)
+	ctxB := tenant.WithID(context.Background(), tenantB)
+
+	now := time.Now().UTC()
+	endpoint := &domain.WebhookEndpoint{
+		ID:         uuid.New(),
+		Name:       "it-" + tenantA,
+		URL:        "https://bank-a.example/hooks",
+		Secret:     "it-secret",
+		EventTypes: []domain.WebhookEventType{domain.WebhookEventScreeningBlocked},
+		Enabled:    true,
+		CreatedBy:  uuid.New(),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := repo.Create(ctxA, endpoint); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM webhook_endpoints WHERE id = $1`, endpoint.ID)
+	})
+	if endpoint.TenantID != tenantA {
+		t.Fatalf("endpoint stored for tenant %q, want %q", endpoint.TenantID, tenantA)
+	}
+
+	if _, err := repo.GetByID(ctxB, endpoint.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID of tenant a's endpoint for tenant b = %v, want ErrNotFound", err)
+	}
+	endpoints, err := repo.List(ctxB)
+	if err != nil {
+		t.Fatalf("List for tenant b: %v", err)
+	}
+	for _, e := range endpoints {
+		if e.ID == endpoint.ID {
+			t.Fatalf("tenant b listed tenant a's endpoint")
+		}
+	}
+	if err := repo.Delete(ctxB, endpoint.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Delete of tenant a's endpoint for tenant b = %v, want ErrNotFound", err)
+	}
+
+	got, err := repo.GetByID(ctxA, endpoint.ID)
+	if err != nil {
+		t.Fatalf("GetByID for tenant a: %v", err)
+	}
+	if got.TenantID != tenantA {
+		t.Fatalf("tenant a's endpoint reads back for tenant %q", got.TenantID)
+	}
+}
@@ -0,0 +1,108 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/banking/aml-service/internal/api/http/handlers"
+	amlmiddleware "github.com/banking/aml-service/internal/api/http/middleware"
+	"github.com/banking/aml-service/internal/api/http/validation"
+	"github.com/banking/aml-service/internal/audit"
+	"github.com/banking/aml-service/internal/config"
+	"github.com/banking/aml-service/internal/domain"
+	"github.com/banking/aml-service/internal/pkg/logger"
+	"github.com/banking/aml-service/internal/pkg/tenant"
+	"github.com/banking/aml-service/internal/repository/postgres"
+	"github.com/banking/aml-service/internal/service"
+)
+
+// nopAuditor drops audit entries
+type nopAuditor struct{}
+
+func (nopAuditor) Record(context.Context, audit.Entry) error { return nil }
+
+func TestWatchlistAPIIsolatesTenants(t *testing.T) {
+	_, db, _ := setup(t)
+	tenantA, tenantB := testTenants()
+	const secret = "it-watchlist-secret"
+	supervisor := uuid.New()
+
+	watchlist, err := service.NewWatchlistService(postgres.NewRiskProfileRepository(db), nopAuditor{},
+		&config.ComplianceConfig{Supervisors: []string{supervisor.String()}}, logger.NewNop())
+	if err != nil {
+		t.Fatalf("NewWatchlistService: %v", err)
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler(logger.NewNop())
+	e.Validator = validation.New()
+	api := e.Group("/api/v1")
+	api.Use(amlmiddleware.Tenant("", func(string) bool { return true }))
+	handlers.NewWatchlistHandler(watchlist, amlmiddleware.RequireToken(secret), logger.NewNop()).Register(api)
+
+	bearer, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{Subject: supervisor.String()}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	call := func(tenantID, method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(tenant.Header, tenantID)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+bearer)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	listed := func(tenantID string, userID uuid.UUID) bool {
+		t.Helper()
+		rec := call(tenantID, http.MethodGet, "/api/v1/watchlist?limit=200", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("list for %s: status %d: %s", tenantID, rec.Code, rec.Body)
+		}
+		var page domain.WatchlistListResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decode watchlist: %v", err)
+		}
+		for _, entry := range page.Entries {
+			if entry.UserID == userID {
+				return true
+			}
+		}
+		return false
+	}
+
+	userID := uuid.New()
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM user_risk_profiles WHERE user_id = $1`, userID)
+	})
+	path := "/api/v1/watchlist/" + userID.String()
+	add := `{"actor_id":"` + uuid.NewString() + `","reason":"adverse media reported by tenant a"}`
+	if rec := call(tenantA, http.MethodPost, path, add); rec.Code != http.StatusOK {
+		t.Fatalf("add for tenant a: status %d: %s", rec.Code, rec.Body)
+	}
+
+	if listed(tenantB, userID) {
+		t.Fatal("tenant b's watchlist lists tenant a's user")
+	}
+	if !listed(tenantA, userID) {
+		t.Fatal("tenant a's watchlist does not list its user")
+	}
+
+	if rec := call(tenantB, http.MethodDelete, path, `{"reason":"cleared"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("removal of tenant a's user by tenant b: status %d, want 404: %s", rec.Code, rec.Body)
+	}
+	if !listed(tenantA, userID) {
+		t.Fatal("tenant b's removal took tenant a's user off the watchlist")
+	}
+}